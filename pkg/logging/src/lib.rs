@@ -9,7 +9,7 @@ use std::io;
 use std::io::Write;
 use std::process;
 use std::result;
-use std::sync::Mutex;
+use std::sync::{Arc, Mutex};
 
 const LOG_LEVELS: &[(&str, slog::Level)] = &[
     ("trace", slog::Level::Trace),
@@ -27,6 +27,38 @@ pub fn create_logger<W>(
     level: slog::Level,
     writer: W,
 ) -> (slog::Logger, slog_async::AsyncGuard)
+where
+    W: Write + Send + Sync + 'static,
+{
+    let (logger, guard, _) = create_logger_with_level_handle(name, source, level, writer);
+
+    (logger, guard)
+}
+
+// LevelHandle changes the log level of a logger at runtime.
+#[derive(Clone, Debug)]
+pub struct LevelHandle {
+    level: Arc<Mutex<slog::Level>>,
+}
+
+impl LevelHandle {
+    pub fn get(&self) -> slog::Level {
+        *self.level.lock().unwrap()
+    }
+
+    pub fn set(&self, level: slog::Level) {
+        *self.level.lock().unwrap() = level;
+    }
+}
+
+// Same as create_logger(), also returning the handle to change the log level
+// of the logger.
+pub fn create_logger_with_level_handle<W>(
+    name: &str,
+    source: &str,
+    level: slog::Level,
+    writer: W,
+) -> (slog::Logger, slog_async::AsyncGuard, LevelHandle)
 where
     W: Write + Send + Sync + 'static,
 {
@@ -39,7 +71,10 @@ where
     let unique_drain = UniqueDrain::new(json_drain).fuse();
 
     // Allow runtime filtering of records by log level
-    let filter_drain = RuntimeLevelFilter::new(unique_drain, level).fuse();
+    let level_handle = LevelHandle {
+        level: Arc::new(Mutex::new(level)),
+    };
+    let filter_drain = RuntimeLevelFilter::new(unique_drain, level_handle.clone()).fuse();
 
     // Ensure the logger is thread-safe
     let (async_drain, guard) = slog_async::Async::new(filter_drain)
@@ -56,7 +91,7 @@ where
             "source" => source.to_string()),
     );
 
-    (logger, guard)
+    (logger, guard, level_handle)
 }
 
 pub fn get_log_levels() -> Vec<&'static str> {
@@ -179,15 +214,12 @@ where
 // specified in the struct.
 struct RuntimeLevelFilter<D> {
     drain: D,
-    level: Mutex<slog::Level>,
+    level: LevelHandle,
 }
 
 impl<D> RuntimeLevelFilter<D> {
-    fn new(drain: D, level: slog::Level) -> Self {
-        RuntimeLevelFilter {
-            drain,
-            level: Mutex::new(level),
-        }
+    fn new(drain: D, level: LevelHandle) -> Self {
+        RuntimeLevelFilter { drain, level }
     }
 }
 
@@ -203,9 +235,9 @@ where
         record: &slog::Record,
         values: &slog::OwnedKVList,
     ) -> result::Result<Self::Ok, Self::Err> {
-        let log_level = self.level.lock().unwrap();
+        let log_level = self.level.get();
 
-        if record.level().is_at_least(*log_level) {
+        if record.level().is_at_least(log_level) {
             self.drain.log(record, values)?;
         }
 
@@ -217,7 +249,7 @@ where
 mod tests {
     use super::*;
     use serde_json::Value;
-    use slog::info;
+    use slog::{debug, info};
     use std::io::prelude::*;
     use tempfile::NamedTempFile;
 
@@ -360,6 +392,35 @@ mod tests {
         }
     }
 
+    #[test]
+    fn test_level_handle() {
+        let writer = NamedTempFile::new().expect("failed to create tempfile");
+        let mut writer_ref = writer.reopen().expect("failed to clone tempfile");
+
+        let (logger, guard, handle) =
+            create_logger_with_level_handle("name", "source", slog::Level::Info, writer);
+        assert_eq!(handle.get(), slog::Level::Info);
+
+        debug!(logger, "filtered");
+
+        handle.set(slog::Level::Debug);
+        assert_eq!(handle.get(), slog::Level::Debug);
+
+        debug!(logger, "logged");
+
+        // Force the records to be written
+        drop(logger);
+        drop(guard);
+
+        let mut contents = String::new();
+        writer_ref
+            .read_to_string(&mut contents)
+            .expect("failed to read tempfile contents");
+
+        assert!(!contents.contains("filtered"));
+        assert!(contents.contains("logged"));
+    }
+
     #[test]
     fn test_create_logger_write_to_tmpfile() {
         // Create a writer for the logger drain to use
@@ -194,6 +194,18 @@ pub struct Hooks {
     pub poststart: Vec<Hook>,
     #[serde(default, skip_serializing_if = "Vec::is_empty")]
     pub poststop: Vec<Hook>,
+    #[serde(
+        default,
+        rename = "createContainer",
+        skip_serializing_if = "Vec::is_empty"
+    )]
+    pub create_container: Vec<Hook>,
+    #[serde(
+        default,
+        rename = "startContainer",
+        skip_serializing_if = "Vec::is_empty"
+    )]
+    pub start_container: Vec<Hook>,
 }
 
 #[derive(Serialize, Deserialize, Debug, Default, Clone, PartialEq)]
@@ -1399,6 +1411,8 @@ mod tests {
                     env: vec![],
                     timeout: None,
                 }],
+                create_container: vec![],
+                start_container: vec![],
             }),
             annotations: [
                 ("com.example.key1".to_string(), "value1".to_string()),
@@ -30,6 +30,29 @@ pub async fn notify_oom(cid: &str, cg_dir: String) -> Result<Receiver<String>> {
     notify_on_oom(cid, cg_dir).await
 }
 
+// MemoryEvent is a memory event of a container cgroup: the counter of
+// memory.events that increased and its value.
+#[derive(Debug, Clone, PartialEq)]
+pub struct MemoryEvent {
+    pub container_id: String,
+    pub event: String,
+    pub count: u64,
+}
+
+// the counters of memory.events reported as memory events
+const MEMORY_EVENTS: [&str; 2] = ["high", "max"];
+
+// the memory.pressure_level notifications of cgroup v1 reported as memory events
+const MEMORY_PRESSURE_EVENT: &str = "pressure";
+const MEMORY_PRESSURE_LEVEL: &str = "medium";
+
+pub async fn notify_memory_events(cid: &str, cg_dir: String) -> Result<Receiver<MemoryEvent>> {
+    if cgroups::hierarchies::is_cgroup2_unified_mode() {
+        return notify_on_memory_events_v2(cid, cg_dir).await;
+    }
+    notify_on_memory_pressure(cid, cg_dir).await
+}
+
 // get_value_from_cgroup parse cgroup file with `Flat keyed`
 // and get the value of `key`.
 // Flat keyed file format:
@@ -129,6 +152,111 @@ async fn register_memory_event_v2(
     Ok(receiver)
 }
 
+// notify_on_memory_events_v2 returns channel on which you can expect the
+// memory events of memory.events, it is closed when the processes exited.
+async fn notify_on_memory_events_v2(cid: &str, cg_dir: String) -> Result<Receiver<MemoryEvent>> {
+    let event_control_path = Path::new(&cg_dir).join("memory.events");
+    let cgroup_event_control_path = Path::new(&cg_dir).join("cgroup.events");
+    info!(
+        sl!(),
+        "notify_on_memory_events_v2 event_control_path: {:?}", &event_control_path
+    );
+
+    let mut inotify = Inotify::init().context("Failed to initialize inotify")?;
+
+    let ev_wd = inotify.add_watch(&event_control_path, WatchMask::MODIFY)?;
+    let cg_wd = inotify.add_watch(&cgroup_event_control_path, WatchMask::MODIFY)?;
+
+    // the counters are reported when they increase
+    let mut counts: Vec<i64> = MEMORY_EVENTS
+        .iter()
+        .map(|key| get_value_from_cgroup(&event_control_path, key).unwrap_or(0))
+        .collect();
+
+    let (sender, receiver) = channel(100);
+    let container_id = cid.to_string();
+
+    tokio::spawn(async move {
+        let mut buffer = [0; 32];
+        let mut stream = inotify
+            .event_stream(&mut buffer)
+            .expect("create inotify event stream failed");
+
+        while let Some(event_or_error) = stream.next().await {
+            let event = match event_or_error {
+                Ok(event) => event,
+                Err(e) => {
+                    warn!(sl!(), "failed to read inotify event: {:?}", e);
+                    return;
+                }
+            };
+
+            if event.wd == ev_wd {
+                for (i, key) in MEMORY_EVENTS.iter().enumerate() {
+                    let count = get_value_from_cgroup(&event_control_path, key).unwrap_or(0);
+                    if count <= counts[i] {
+                        continue;
+                    }
+                    counts[i] = count;
+
+                    let memory_event = MemoryEvent {
+                        container_id: container_id.clone(),
+                        event: key.to_string(),
+                        count: count as u64,
+                    };
+                    if let Err(e) = sender.send(memory_event).await {
+                        error!(sl!(), "send memory event failed, error: {:?}", e);
+                        return;
+                    }
+                }
+            } else if event.wd == cg_wd {
+                let pids = get_value_from_cgroup(&cgroup_event_control_path, "populated");
+                if pids.unwrap_or(-1) == 0 {
+                    return;
+                }
+            }
+
+            if !Path::new(&event_control_path).exists() {
+                return;
+            }
+        }
+    });
+
+    Ok(receiver)
+}
+
+// notify_on_memory_pressure returns channel on which you can expect the
+// memory pressure notifications of cgroup v1, counted as memory events.
+async fn notify_on_memory_pressure(cid: &str, dir: String) -> Result<Receiver<MemoryEvent>> {
+    if dir.is_empty() {
+        return Err(anyhow!("memory controller missing"));
+    }
+
+    let mut rx =
+        register_memory_event(cid, dir, "memory.pressure_level", MEMORY_PRESSURE_LEVEL).await?;
+
+    let (sender, receiver) = channel(100);
+
+    tokio::spawn(async move {
+        let mut count = 0;
+        while let Some(container_id) = rx.recv().await {
+            count += 1;
+
+            let memory_event = MemoryEvent {
+                container_id,
+                event: MEMORY_PRESSURE_EVENT.to_string(),
+                count,
+            };
+            if let Err(e) = sender.send(memory_event).await {
+                error!(sl!(), "send memory event failed, error: {:?}", e);
+                return;
+            }
+        }
+    });
+
+    Ok(receiver)
+}
+
 // notify_on_oom returns channel on which you can expect event about OOM,
 // if process died without OOM this channel will be closed.
 async fn notify_on_oom(cid: &str, dir: String) -> Result<Receiver<String>> {
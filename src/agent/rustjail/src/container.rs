@@ -377,6 +377,12 @@ fn do_init_child(cwfd: RawFd) -> Result<()> {
     let cm_str = std::str::from_utf8(&buf)?;
 
     let cm: FsManager = serde_json::from_str(cm_str)?;
+    log_child!(cfd_log, "notify parent to send oci state");
+    write_sync(cwfd, SYNC_SUCCESS, "")?;
+
+    // the state passed to the hooks run in the container namespaces
+    let buf = read_sync(crfd)?;
+    let state = std::str::from_utf8(&buf)?.to_string();
 
     let p = if spec.process.is_some() {
         spec.process.as_ref().unwrap()
@@ -546,6 +552,17 @@ fn do_init_child(cwfd: RawFd) -> Result<()> {
         unistd::close(mount_fd)?;
     }
 
+    // run the createContainer hooks, before pivoting the root, so that
+    // they are resolved in the guest root filesystem
+    if init {
+        if let Some(hooks) = spec.hooks.as_ref() {
+            for h in hooks.create_container.iter() {
+                log_child!(cfd_log, "run createContainer hook {}", &h.path);
+                execute_hook_in_child(h, &state)?;
+            }
+        }
+    }
+
     if to_new.contains(CloneFlags::CLONE_NEWNS) {
         // unistd::chroot(rootfs)?;
         if no_pivot {
@@ -667,6 +684,14 @@ fn do_init_child(cwfd: RawFd) -> Result<()> {
         unistd::close(fifofd)?;
         let mut buf: &mut [u8] = &mut [0];
         unistd::read(fd, &mut buf)?;
+
+        // run the startContainer hooks, resolved in the container root
+        // filesystem, once the container is started
+        if let Some(hooks) = spec.hooks.as_ref() {
+            for h in hooks.start_container.iter() {
+                execute_hook_in_child(h, &state)?;
+            }
+        }
     }
 
     do_exec(&args);
@@ -1211,6 +1236,12 @@ async fn join_namespaces(
     let cm_str = serde_json::to_string(cm)?;
     write_async(pipe_w, SYNC_DATA, cm_str.as_str()).await?;
 
+    info!(logger, "wait child received cgroup manager");
+    read_async(pipe_r).await?;
+
+    let st_str = serde_json::to_string(st)?;
+    write_async(pipe_w, SYNC_DATA, st_str.as_str()).await?;
+
     // wait child setup user namespace
     info!(logger, "wait child setup user namespace");
     read_async(pipe_r).await?;
@@ -1528,6 +1559,68 @@ async fn execute_hook(logger: &Logger, h: &Hook, st: &OCIState) -> Result<()> {
     }
 }
 
+// execute_hook_in_child runs a hook from the container process, in the
+// container namespaces. Unlike execute_hook, it's run in the single threaded
+// child without the async runtime, and its output isn't logged, the log pipe
+// being closed when the startContainer hooks are run.
+fn execute_hook_in_child(h: &Hook, state: &str) -> Result<()> {
+    use std::io::Write;
+    use std::os::unix::process::CommandExt;
+
+    let mut cmd = std::process::Command::new(h.path.as_str());
+    if let Some((arg0, args)) = h.args.split_first() {
+        cmd.arg0(arg0).args(args);
+    }
+
+    let env = h.env.iter().filter_map(|e| {
+        let v: Vec<&str> = e.splitn(2, '=').collect();
+        if v.len() == 2 {
+            Some((v[0], v[1]))
+        } else {
+            None
+        }
+    });
+
+    let mut child = cmd
+        .env_clear()
+        .envs(env)
+        .stdin(Stdio::piped())
+        .stdout(Stdio::null())
+        .stderr(Stdio::null())
+        .spawn()
+        .with_context(|| format!("failed to run hook {}", &h.path))?;
+
+    // a hook not reading its state mustn't fail the container
+    if let Some(mut stdin) = child.stdin.take() {
+        let _ = stdin.write_all(state.as_bytes());
+    }
+
+    // default timeout 10s
+    let timeout = match h.timeout {
+        Some(t) if t > 0 => Duration::from_secs(t as u64),
+        _ => Duration::from_secs(10),
+    };
+
+    let deadline = std::time::Instant::now() + timeout;
+    loop {
+        if let Some(exit) = child.try_wait()? {
+            return match exit.code() {
+                Some(0) => Ok(()),
+                Some(code) => Err(anyhow!("hook {} exit status is {}", &h.path, code)),
+                None => Err(anyhow!("hook {} killed by a signal", &h.path)),
+            };
+        }
+
+        if std::time::Instant::now() >= deadline {
+            let _ = child.kill();
+            let _ = child.wait();
+            return Err(anyhow!(nix::Error::from_errno(Errno::ETIMEDOUT)));
+        }
+
+        std::thread::sleep(Duration::from_millis(10));
+    }
+}
+
 #[cfg(test)]
 mod tests {
     use super::*;
@@ -1613,6 +1706,34 @@ mod tests {
         );
     }
 
+    #[tokio::test]
+    async fn test_execute_hook_in_child() {
+        let state = "{}";
+
+        let hook = |path: String, args: Vec<&str>, timeout| Hook {
+            path,
+            args: args.iter().map(|a| a.to_string()).collect(),
+            env: vec!["PATH=/bin".to_string()],
+            timeout,
+        };
+
+        let res = execute_hook_in_child(&hook(which("true").await, vec!["true"], None), state);
+        assert!(res.is_ok());
+
+        let res = execute_hook_in_child(&hook(which("false").await, vec!["false"], None), state);
+        assert!(res.is_err());
+
+        let res = execute_hook_in_child(
+            &hook(which("sleep").await, vec!["sleep", "2"], Some(1)),
+            state,
+        );
+        let expected_err = nix::Error::from_errno(Errno::ETIMEDOUT);
+        assert_eq!(
+            res.unwrap_err().downcast::<nix::Error>().unwrap(),
+            expected_err
+        );
+    }
+
     #[test]
     fn test_status_transtition() {
         let mut status = ContainerStatus::new();
@@ -154,10 +154,16 @@ fn hooks_grpc_to_oci(h: &grpc::Hooks) -> oci::Hooks {
 
     let poststop = hook_grpc_to_oci(h.Poststop.as_ref());
 
+    let create_container = hook_grpc_to_oci(h.CreateContainer.as_ref());
+
+    let start_container = hook_grpc_to_oci(h.StartContainer.as_ref());
+
     oci::Hooks {
         prestart,
         poststart,
         poststop,
+        create_container,
+        start_container,
     }
 }
 
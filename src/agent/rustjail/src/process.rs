@@ -20,6 +20,7 @@ use slog::Logger;
 use crate::pipestream::PipeStream;
 use std::collections::HashMap;
 use std::sync::Arc;
+use std::time::Instant;
 use tokio::io::{split, ReadHalf, WriteHalf};
 use tokio::sync::Mutex;
 use tokio::sync::Notify;
@@ -59,6 +60,10 @@ pub struct Process {
 
     pub exit_code: i32,
     pub exit_watchers: Vec<Sender<i32>>,
+    // when the process was created and reaped, for the
+    // exec session records
+    pub start_time: Instant,
+    pub exit_time: Option<Instant>,
     pub oci: OCIProcess,
     pub logger: Logger,
     pub term_exit_notifier: Arc<Notify>,
@@ -115,6 +120,8 @@ impl Process {
             pid: -1,
             exit_code: 0,
             exit_watchers: Vec::new(),
+            start_time: Instant::now(),
+            exit_time: None,
             oci: ocip.clone(),
             logger: logger.clone(),
             term_exit_notifier: Arc::new(Notify::new()),
@@ -10,6 +10,7 @@ use std::time;
 use tracing::instrument;
 
 const DEBUG_CONSOLE_FLAG: &str = "agent.debug_console";
+const DEBUG_CONSOLE_CONTROL_FLAG: &str = "agent.debug_console_control";
 const DEV_MODE_FLAG: &str = "agent.devmode";
 const TRACE_MODE_OPTION: &str = "agent.trace";
 const LOG_LEVEL_OPTION: &str = "agent.log";
@@ -19,6 +20,7 @@ const DEBUG_CONSOLE_VPORT_OPTION: &str = "agent.debug_console_vport";
 const LOG_VPORT_OPTION: &str = "agent.log_vport";
 const CONTAINER_PIPE_SIZE_OPTION: &str = "agent.container_pipe_size";
 const UNIFIED_CGROUP_HIERARCHY_OPTION: &str = "agent.unified_cgroup_hierarchy";
+const INITDATA_OPTION: &str = "agent.initdata";
 
 const DEFAULT_LOG_LEVEL: slog::Level = slog::Level::Info;
 const DEFAULT_HOTPLUG_TIMEOUT: time::Duration = time::Duration::from_secs(3);
@@ -50,6 +52,10 @@ const ERR_INVALID_CONTAINER_PIPE_NEGATIVE: &str = "container pipe size should no
 #[derive(Debug)]
 pub struct AgentConfig {
     pub debug_console: bool,
+    // the debug console can be started at runtime by SetDebugConsole, which
+    // is only allowed by the kernel command line, measured with the guest
+    // image when the guest is protected
+    pub debug_console_control: bool,
     pub dev_mode: bool,
     pub log_level: slog::Level,
     pub hotplug_timeout: time::Duration,
@@ -59,6 +65,8 @@ pub struct AgentConfig {
     pub server_addr: String,
     pub unified_cgroup_hierarchy: bool,
     pub tracing: tracer::TraceType,
+    // how the runtime passes the initdata, it is only read when set
+    pub initdata: String,
 }
 
 // parse_cmdline_param parse commandline parameters.
@@ -95,6 +103,7 @@ impl AgentConfig {
     pub fn new() -> AgentConfig {
         AgentConfig {
             debug_console: false,
+            debug_console_control: false,
             dev_mode: false,
             log_level: DEFAULT_LOG_LEVEL,
             hotplug_timeout: DEFAULT_HOTPLUG_TIMEOUT,
@@ -104,6 +113,7 @@ impl AgentConfig {
             server_addr: format!("{}:{}", VSOCK_ADDR, VSOCK_PORT),
             unified_cgroup_hierarchy: false,
             tracing: tracer::TraceType::Disabled,
+            initdata: String::new(),
         }
     }
 
@@ -114,6 +124,11 @@ impl AgentConfig {
         for param in params.iter() {
             // parse cmdline flags
             parse_cmdline_param!(param, DEBUG_CONSOLE_FLAG, self.debug_console);
+            parse_cmdline_param!(
+                param,
+                DEBUG_CONSOLE_CONTROL_FLAG,
+                self.debug_console_control
+            );
             parse_cmdline_param!(param, DEV_MODE_FLAG, self.dev_mode);
 
             // Support "bare" tracing option for backwards compatibility with
@@ -171,6 +186,7 @@ impl AgentConfig {
                 self.unified_cgroup_hierarchy,
                 get_bool_value
             );
+            parse_cmdline_param!(param, INITDATA_OPTION, self.initdata, get_string_value);
         }
 
         if let Ok(addr) = env::var(SERVER_ADDR_ENV_VAR) {
@@ -387,6 +403,7 @@ mod tests {
             contents: &'a str,
             env_vars: Vec<&'a str>,
             debug_console: bool,
+            debug_console_control: bool,
             dev_mode: bool,
             log_level: slog::Level,
             hotplug_timeout: time::Duration,
@@ -394,6 +411,7 @@ mod tests {
             server_addr: &'a str,
             unified_cgroup_hierarchy: bool,
             tracing: tracer::TraceType,
+            initdata: &'a str,
         }
 
         impl Default for TestData<'_> {
@@ -402,6 +420,7 @@ mod tests {
                     contents: "",
                     env_vars: Vec::new(),
                     debug_console: false,
+                    debug_console_control: false,
                     dev_mode: false,
                     log_level: DEFAULT_LOG_LEVEL,
                     hotplug_timeout: DEFAULT_HOTPLUG_TIMEOUT,
@@ -409,6 +428,7 @@ mod tests {
                     server_addr: TEST_SERVER_ADDR,
                     unified_cgroup_hierarchy: false,
                     tracing: tracer::TraceType::Disabled,
+                    initdata: "",
                 }
             }
         }
@@ -463,6 +483,16 @@ mod tests {
                 contents: "foo debug_console agent bar devmode",
                 ..Default::default()
             },
+            TestData {
+                contents: "agent.debug_console_control",
+                debug_console_control: true,
+                ..Default::default()
+            },
+            TestData {
+                contents: "agent.initdata=disk",
+                initdata: "disk",
+                ..Default::default()
+            },
             TestData {
                 contents: "agent.debug_console",
                 debug_console: true,
@@ -771,6 +801,11 @@ mod tests {
             assert!(result.is_ok(), "{}", msg);
 
             assert_eq!(d.debug_console, config.debug_console, "{}", msg);
+            assert_eq!(
+                d.debug_console_control, config.debug_console_control,
+                "{}",
+                msg
+            );
             assert_eq!(d.dev_mode, config.dev_mode, "{}", msg);
             assert_eq!(
                 d.unified_cgroup_hierarchy, config.unified_cgroup_hierarchy,
@@ -782,6 +817,7 @@ mod tests {
             assert_eq!(d.container_pipe_size, config.container_pipe_size, "{}", msg);
             assert_eq!(d.server_addr, config.server_addr, "{}", msg);
             assert_eq!(d.tracing, config.tracing, "{}", msg);
+            assert_eq!(d.initdata, config.initdata, "{}", msg);
 
             for v in vars_to_unset {
                 env::remove_var(v);
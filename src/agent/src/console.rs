@@ -25,7 +25,7 @@ use std::sync::Mutex as SyncMutex;
 use futures::StreamExt;
 use tokio::io::{AsyncRead, AsyncWrite};
 use tokio::select;
-use tokio::sync::watch::Receiver;
+use tokio::sync::watch::{self, Receiver};
 
 const CONSOLE_PATH: &str = "/dev/console";
 
@@ -46,6 +46,21 @@ pub fn initialize() {
     lazy_static::initialize(&SHELLS);
 }
 
+// start_debug_console runs the debug console in the background until the
+// returned sender sends a stop request or is dropped.
+pub fn start_debug_console(logger: &Logger, port: u32) -> watch::Sender<bool> {
+    let (stop_tx, stop_rx) = watch::channel(false);
+    let logger = logger.clone();
+
+    tokio::spawn(async move {
+        if let Err(e) = debug_console_handler(logger.clone(), port, stop_rx).await {
+            error!(logger, "debug console failed"; "error" => format!("{:?}", e));
+        }
+    });
+
+    stop_tx
+}
+
 pub async fn debug_console_handler(
     logger: Logger,
     port: u32,
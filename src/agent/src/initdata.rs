@@ -0,0 +1,278 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+// The initdata is the sandbox metadata passed by the runtime at boot, as a
+// QEMU fw_cfg blob or a small read-only disk, so that it is applied before
+// the vsock is up. The blob is a header sector, with the magic, the version
+// and the length of the data, little endian, followed by the JSON data.
+// It is only read when the agent.initdata kernel parameter names the method,
+// the disk being the one with the serial assigned by the runtime.
+
+use anyhow::{anyhow, Context, Result};
+use serde_json::Value;
+use slog::Logger;
+use std::collections::HashMap;
+use std::env;
+use std::fs::{self, File, OpenOptions};
+use std::io::{Read, Write};
+use std::path::Path;
+
+const INITDATA_FW_CFG: &str =
+    "/sys/firmware/qemu_fw_cfg/by_name/opt/io.katacontainers/initdata/raw";
+const SYSFS_BLOCK: &str = "/sys/block";
+const INITDATA_DISK_SERIAL: &str = "initdata";
+const INITDATA_METHOD_FW_CFG: &str = "fw_cfg";
+const INITDATA_METHOD_DISK: &str = "disk";
+const GUEST_CA_CERTIFICATES: &str = "/etc/ssl/certs/ca-certificates.crt";
+
+const INITDATA_MAGIC: &[u8] = b"KATAINIT";
+const INITDATA_VERSION: u32 = 1;
+const INITDATA_SECTOR_SIZE: usize = 512;
+
+#[derive(Debug, Default, Clone, PartialEq)]
+pub struct InitData {
+    pub sandbox_id: String,
+    pub hostname: String,
+    pub dns: Vec<String>,
+    pub proxies: HashMap<String, String>,
+    pub certificates: Vec<String>,
+    pub allowed_apis: Vec<String>,
+    pub kernel_modules: Vec<String>,
+    pub guest_hook_path: String,
+}
+
+fn get_string(v: &Value, key: &str) -> String {
+    v[key].as_str().unwrap_or_default().to_string()
+}
+
+fn get_strings(v: &Value, key: &str) -> Vec<String> {
+    v[key]
+        .as_array()
+        .map(|a| {
+            a.iter()
+                .filter_map(|s| s.as_str())
+                .map(|s| s.to_string())
+                .collect()
+        })
+        .unwrap_or_default()
+}
+
+fn read_u32(buf: &[u8]) -> u32 {
+    u32::from_le_bytes([buf[0], buf[1], buf[2], buf[3]])
+}
+
+// is_initdata returns whether the header sector is the one of an initdata.
+fn is_initdata(header: &[u8]) -> bool {
+    header.len() >= 16 && &header[..8] == INITDATA_MAGIC
+}
+
+// parse parses an initdata blob.
+pub fn parse(buf: &[u8]) -> Result<InitData> {
+    if buf.len() < INITDATA_SECTOR_SIZE || !is_initdata(buf) {
+        return Err(anyhow!("invalid initdata header"));
+    }
+
+    let version = read_u32(&buf[8..12]);
+    if version != INITDATA_VERSION {
+        return Err(anyhow!("unsupported initdata version {}", version));
+    }
+
+    let length = read_u32(&buf[12..16]) as usize;
+    let payload = buf
+        .get(INITDATA_SECTOR_SIZE..INITDATA_SECTOR_SIZE + length)
+        .ok_or_else(|| anyhow!("truncated initdata of {} bytes", length))?;
+
+    let v: Value = serde_json::from_slice(payload).context("invalid initdata")?;
+
+    let mut proxies = HashMap::new();
+    if let Some(p) = v["proxies"].as_object() {
+        for (name, value) in p.iter() {
+            if let Some(value) = value.as_str() {
+                proxies.insert(name.clone(), value.to_string());
+            }
+        }
+    }
+
+    Ok(InitData {
+        sandbox_id: get_string(&v, "sandbox_id"),
+        hostname: get_string(&v, "hostname"),
+        dns: get_strings(&v, "dns"),
+        proxies,
+        certificates: get_strings(&v, "certificates"),
+        allowed_apis: get_strings(&v, "allowed_apis"),
+        kernel_modules: get_strings(&v, "kernel_modules"),
+        guest_hook_path: get_string(&v, "guest_hook_path"),
+    })
+}
+
+// find_disk returns the virtio block device with the initdata serial, if
+// any. The other disks are not looked at, their contents are not trusted.
+fn find_disk(sysfs_block: &str) -> Result<Option<String>> {
+    if !Path::new(sysfs_block).exists() {
+        return Ok(None);
+    }
+
+    for entry in fs::read_dir(sysfs_block)? {
+        let entry = entry?;
+        let name = entry.file_name().to_string_lossy().to_string();
+        if !name.starts_with("vd") {
+            continue;
+        }
+
+        let serial = fs::read_to_string(entry.path().join("serial")).unwrap_or_default();
+        if serial.trim_end() == INITDATA_DISK_SERIAL {
+            return Ok(Some(format!("/dev/{}", name)));
+        }
+    }
+
+    Ok(None)
+}
+
+// load reads the initdata passed by the runtime with the method, if any.
+pub fn load(logger: &Logger, method: &str) -> Result<Option<InitData>> {
+    let path = match method {
+        "" => return Ok(None),
+        INITDATA_METHOD_FW_CFG => INITDATA_FW_CFG.to_string(),
+        INITDATA_METHOD_DISK => find_disk(SYSFS_BLOCK)?
+            .ok_or_else(|| anyhow!("no initdata disk with serial {}", INITDATA_DISK_SERIAL))?,
+        _ => return Err(anyhow!("unknown initdata method {}", method)),
+    };
+
+    info!(logger, "loading initdata"; "path" => &path);
+
+    let mut buf = Vec::new();
+    File::open(&path)?.read_to_end(&mut buf)?;
+
+    parse(&buf).map(Some)
+}
+
+// install_certificates adds the certificates to the guest trust store.
+fn install_certificates(certificates: &[String]) -> Result<()> {
+    if certificates.is_empty() {
+        return Ok(());
+    }
+
+    if let Some(dir) = Path::new(GUEST_CA_CERTIFICATES).parent() {
+        fs::create_dir_all(dir)?;
+    }
+
+    let mut f = OpenOptions::new()
+        .create(true)
+        .append(true)
+        .open(GUEST_CA_CERTIFICATES)?;
+    for cert in certificates {
+        f.write_all(cert.as_bytes())?;
+        if !cert.ends_with('\n') {
+            f.write_all(b"\n")?;
+        }
+    }
+
+    Ok(())
+}
+
+// apply applies the part of the initdata not related to the sandbox state:
+// the proxies, the certificates and the kernel modules.
+pub fn apply(logger: &Logger, data: &InitData) -> Result<()> {
+    for (name, value) in data.proxies.iter() {
+        env::set_var(name, value);
+        env::set_var(name.to_lowercase(), value);
+    }
+
+    install_certificates(&data.certificates).context("install initdata certificates")?;
+
+    for m in data.kernel_modules.iter() {
+        let fields: Vec<&str> = m.split_whitespace().collect();
+        if fields.is_empty() {
+            continue;
+        }
+
+        let mut module = protocols::agent::KernelModule::new();
+        module.name = fields[0].to_string();
+        module.parameters = fields[1..].iter().map(|p| p.to_string()).collect();
+        crate::rpc::load_kernel_module(&module)?;
+    }
+
+    info!(logger, "initdata applied"; "sandbox" => &data.sandbox_id);
+
+    Ok(())
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+    use tempfile::tempdir;
+
+    fn blob(payload: &str) -> Vec<u8> {
+        let mut buf = vec![0u8; INITDATA_SECTOR_SIZE];
+        buf[..8].copy_from_slice(INITDATA_MAGIC);
+        buf[8..12].copy_from_slice(&INITDATA_VERSION.to_le_bytes());
+        buf[12..16].copy_from_slice(&(payload.len() as u32).to_le_bytes());
+        buf.extend_from_slice(payload.as_bytes());
+        buf.resize(INITDATA_SECTOR_SIZE * 2, 0);
+        buf
+    }
+
+    #[test]
+    fn test_parse() {
+        let data = parse(&blob(
+            r#"{"sandbox_id":"sid","hostname":"pod","dns":["nameserver 10.0.0.10"],"proxies":{"HTTPS_PROXY":"http://proxy:3128"},"allowed_apis":["grpc.GetMetricsRequest"]}"#,
+        ))
+        .unwrap();
+
+        assert_eq!(data.sandbox_id, "sid");
+        assert_eq!(data.hostname, "pod");
+        assert_eq!(data.dns, vec!["nameserver 10.0.0.10"]);
+        assert_eq!(
+            data.proxies.get("HTTPS_PROXY").map(|s| s.as_str()),
+            Some("http://proxy:3128")
+        );
+        assert_eq!(data.allowed_apis, vec!["grpc.GetMetricsRequest"]);
+        assert!(data.certificates.is_empty());
+    }
+
+    #[test]
+    fn test_parse_invalid() {
+        assert!(parse(&[0u8; 16]).is_err());
+        assert!(parse(&vec![0u8; INITDATA_SECTOR_SIZE]).is_err());
+
+        let mut buf = blob("{}");
+        buf[8..12].copy_from_slice(&2u32.to_le_bytes());
+        assert!(parse(&buf).is_err());
+
+        let mut buf = blob("{}");
+        buf[12..16].copy_from_slice(&4096u32.to_le_bytes());
+        assert!(parse(&buf).is_err());
+    }
+
+    #[test]
+    fn test_find_disk() {
+        let dir = tempdir().expect("failed to create tmpdir");
+        let sysfs = dir.path().to_str().unwrap();
+
+        assert_eq!(find_disk("/does/not/exist").unwrap(), None);
+
+        for (name, serial) in &[("vda", "drive-1234"), ("sda", "initdata"), ("vdb", "")] {
+            let dev = dir.path().join(name);
+            fs::create_dir(&dev).unwrap();
+            if !serial.is_empty() {
+                fs::write(dev.join("serial"), serial).unwrap();
+            }
+        }
+        assert_eq!(find_disk(sysfs).unwrap(), None);
+
+        let dev = dir.path().join("vdc");
+        fs::create_dir(&dev).unwrap();
+        fs::write(dev.join("serial"), "initdata\n").unwrap();
+        assert_eq!(find_disk(sysfs).unwrap(), Some("/dev/vdc".to_string()));
+    }
+
+    #[test]
+    fn test_load_disabled() {
+        let logger = slog::Logger::root(slog::Discard, o!());
+
+        assert_eq!(load(&logger, "").unwrap(), None);
+        assert!(load(&logger, "floppy").is_err());
+    }
+}
@@ -85,6 +85,7 @@ pub const SYSFS_MEMORY_ONLINE_PATH: &str = "/sys/devices/system/memory";
 pub const SYSFS_SCSI_HOST_PATH: &str = "/sys/class/scsi_host";
 
 pub const SYSFS_CGROUPPATH: &str = "/sys/fs/cgroup";
+pub const SYSFS_HUGEPAGES_PATH: &str = "/sys/kernel/mm/hugepages";
 pub const SYSFS_ONLINE_FILE: &str = "online";
 
 pub const PROC_MOUNTSTATS: &str = "/proc/self/mountstats";
@@ -39,11 +39,13 @@ mod ccw;
 mod config;
 mod console;
 mod device;
+mod initdata;
 mod linux_abi;
 mod metrics;
 mod mount;
 mod namespace;
 mod netlink;
+mod netpolicy;
 mod network;
 mod pci;
 pub mod random;
@@ -186,8 +188,8 @@ async fn real_main() -> std::result::Result<(), Box<dyn std::error::Error>> {
     let writer = unsafe { File::from_raw_fd(wfd) };
 
     // Recreate a logger with the log level get from "/proc/cmdline".
-    let (logger, logger_async_guard) =
-        logging::create_logger(NAME, "agent", config.log_level, writer);
+    let (logger, logger_async_guard, log_level) =
+        logging::create_logger_with_level_handle(NAME, "agent", config.log_level, writer);
 
     announce(&logger, &config);
 
@@ -217,7 +219,15 @@ async fn real_main() -> std::result::Result<(), Box<dyn std::error::Error>> {
     let _enter = root.enter();
 
     // Start the sandbox and wait for its ttRPC server to end
-    start_sandbox(&logger, &config, init_mode, &mut tasks, shutdown_rx.clone()).await?;
+    start_sandbox(
+        &logger,
+        &config,
+        init_mode,
+        log_level,
+        &mut tasks,
+        shutdown_rx.clone(),
+    )
+    .await?;
 
     // Install a NOP logger for the remainder of the shutdown sequence
     // to ensure any log calls made by local crates using the scope logger
@@ -274,6 +284,14 @@ fn main() -> std::result::Result<(), Box<dyn std::error::Error>> {
         exit(0);
     }
 
+    if args.len() == 2 && args[1] == namespace::PIDNS_INIT_ARG {
+        if let Err(e) = namespace::pidns_init() {
+            eprintln!("failed to create the sandbox pid namespace: {:?}", e);
+            exit(1);
+        }
+        exit(0);
+    }
+
     let rt = tokio::runtime::Builder::new_multi_thread()
         .enable_all()
         .build()?;
@@ -286,27 +304,36 @@ async fn start_sandbox(
     logger: &Logger,
     config: &AgentConfig,
     init_mode: bool,
+    log_level: logging::LevelHandle,
     tasks: &mut Vec<JoinHandle<Result<()>>>,
     shutdown: Receiver<bool>,
 ) -> Result<()> {
-    let debug_console_vport = config.debug_console_vport as u32;
+    // Initialize unique sandbox structure.
+    let mut s = Sandbox::new(&logger).context("Failed to create sandbox")?;
 
-    if config.debug_console {
-        let debug_console_task = tokio::task::spawn(console::debug_console_handler(
-            logger.clone(),
-            debug_console_vport,
-            shutdown.clone(),
-        ));
+    // the log level and the debug console can be changed at runtime
+    s.log_level = Some(log_level);
 
-        tasks.push(debug_console_task);
+    if config.debug_console {
+        let debug_console_vport = config.debug_console_vport as u32;
+        s.debug_console = Some(console::start_debug_console(&logger, debug_console_vport));
     }
 
-    // Initialize unique sandbox structure.
-    let s = Sandbox::new(&logger).context("Failed to create sandbox")?;
     if init_mode {
         s.rtnl.handle_localhost().await?;
     }
 
+    // the sandbox metadata passed at boot, the rest of it is applied when
+    // the sandbox is created
+    if let Some(data) =
+        initdata::load(&logger, &config.initdata).context("Failed to load initdata")?
+    {
+        initdata::apply(&logger, &data).context("Failed to apply initdata")?;
+        s.id = data.sandbox_id.clone();
+        s.allowed_apis = data.allowed_apis.clone();
+        s.initdata = Some(data);
+    }
+
     let sandbox = Arc::new(Mutex::new(s));
 
     let signal_handler_task = tokio::spawn(setup_signal_handler(
@@ -331,6 +358,9 @@ async fn start_sandbox(
     rx.await?;
     server.shutdown().await?;
 
+    // Stop the debug console, if running
+    sandbox.lock().await.debug_console.take();
+
     Ok(())
 }
 
@@ -13,6 +13,21 @@ use tracing::instrument;
 const NAMESPACE_KATA_AGENT: &str = "kata_agent";
 const NAMESPACE_KATA_GUEST: &str = "kata_guest";
 
+// containers rootfs and volumes are all mounted under this path
+const SANDBOX_STORAGE_PATH: &str = "/run/kata-containers";
+
+// groups of metrics which can be selected by GetMetricsRequest
+const METRICS_GROUP_PROC: &str = "proc";
+const METRICS_GROUP_MEMINFO: &str = "meminfo";
+const METRICS_GROUP_NETDEV: &str = "netdev";
+const METRICS_GROUP_FILESYSTEM: &str = "filesystem";
+const METRICS_GROUPS: [&str; 4] = [
+    METRICS_GROUP_PROC,
+    METRICS_GROUP_MEMINFO,
+    METRICS_GROUP_NETDEV,
+    METRICS_GROUP_FILESYSTEM,
+];
+
 // Convenience macro to obtain the scope logger
 macro_rules! sl {
     () => {
@@ -67,17 +82,32 @@ lazy_static! {
 
     static ref     GUEST_MEMINFO: GaugeVec =
     prometheus::register_gauge_vec!(format!("{}_{}",NAMESPACE_KATA_GUEST,"meminfo").as_ref() , "Statistics about memory usage in the system.", &["item"]).unwrap();
+
+    static ref     GUEST_SWAP: GaugeVec =
+    prometheus::register_gauge_vec!(format!("{}_{}",NAMESPACE_KATA_GUEST,"swap").as_ref() , "Usage of the swap devices(bytes).", &["device","item"]).unwrap();
+
+    static ref     GUEST_FILESYSTEM: GaugeVec =
+    prometheus::register_gauge_vec!(format!("{}_{}",NAMESPACE_KATA_GUEST,"filesystem").as_ref() , "Usage of the sandbox storage filesystems(bytes).", &["mount","item"]).unwrap();
 }
 
 #[instrument]
-pub fn get_metrics(_: &protocols::agent::GetMetricsRequest) -> Result<String> {
+pub fn get_metrics(req: &protocols::agent::GetMetricsRequest) -> Result<String> {
     AGENT_SCRAPE_COUNT.inc();
 
+    let groups = req.get_groups();
+    for group in groups {
+        if !METRICS_GROUPS.contains(&group.as_str()) {
+            info!(sl!(), "unknown metrics group: {}", group);
+        }
+    }
+
     // update agent process metrics
-    update_agent_metrics();
+    if group_enabled(groups, METRICS_GROUP_PROC) {
+        update_agent_metrics();
+    }
 
     // update guest os metrics
-    update_guest_metrics();
+    update_guest_metrics(groups);
 
     // gather all metrics and return as a String
     let metric_families = prometheus::gather();
@@ -89,6 +119,11 @@ pub fn get_metrics(_: &protocols::agent::GetMetricsRequest) -> Result<String> {
     Ok(String::from_utf8(buffer).unwrap())
 }
 
+// all groups are enabled if none is selected.
+fn group_enabled(groups: &[String], group: &str) -> bool {
+    groups.is_empty() || groups.iter().any(|g| g == group)
+}
+
 #[instrument]
 fn update_agent_metrics() {
     let me = procfs::process::Process::myself();
@@ -140,7 +175,7 @@ fn update_agent_metrics() {
 }
 
 #[instrument]
-fn update_guest_metrics() {
+fn update_guest_metrics(groups: &[String]) {
     // try get load and task info
     match procfs::LoadAverage::new() {
         Err(err) => {
@@ -199,6 +234,23 @@ fn update_guest_metrics() {
     }
 
     // try to get net device stats
+    if group_enabled(groups, METRICS_GROUP_NETDEV) {
+        update_guest_netdev_metrics();
+    }
+
+    // get statistics about memory from /proc/meminfo
+    if group_enabled(groups, METRICS_GROUP_MEMINFO) {
+        update_guest_meminfo_metrics();
+    }
+
+    // get usage of the filesystems backing the sandbox storage
+    if group_enabled(groups, METRICS_GROUP_FILESYSTEM) {
+        update_guest_filesystem_metrics();
+    }
+}
+
+#[instrument]
+fn update_guest_netdev_metrics() {
     match procfs::net::dev_status() {
         Err(err) => {
             info!(sl!(), "failed to get guest net::dev_status: {:?}", err);
@@ -210,8 +262,10 @@ fn update_guest_metrics() {
             }
         }
     }
+}
 
-    // get statistics about memory from /proc/meminfo
+#[instrument]
+fn update_guest_meminfo_metrics() {
     match procfs::Meminfo::new() {
         Err(err) => {
             info!(sl!(), "failed to get guest Meminfo: {:?}", err);
@@ -220,6 +274,72 @@ fn update_guest_metrics() {
             set_gauge_vec_meminfo(&GUEST_MEMINFO, &meminfo);
         }
     }
+
+    match std::fs::read_to_string("/proc/swaps") {
+        Err(err) => {
+            info!(sl!(), "failed to get guest swaps: {:?}", err);
+        }
+        Ok(swaps) => {
+            set_gauge_vec_swap(&GUEST_SWAP, &swaps);
+        }
+    }
+}
+
+// set_gauge_vec_swap sets the usage of the swap devices from /proc/swaps,
+// whose sizes are in KiB.
+#[instrument]
+fn set_gauge_vec_swap(gv: &prometheus::GaugeVec, swaps: &str) {
+    // skip the header line
+    for line in swaps.lines().skip(1) {
+        let fields: Vec<&str> = line.split_whitespace().collect();
+        if fields.len() < 4 {
+            continue;
+        }
+
+        let size = fields[2].parse::<f64>().unwrap_or(0.0) * 1024.0;
+        let used = fields[3].parse::<f64>().unwrap_or(0.0) * 1024.0;
+
+        gv.with_label_values(&[fields[0], "size"]).set(size);
+        gv.with_label_values(&[fields[0], "used"]).set(used);
+    }
+}
+
+#[instrument]
+fn update_guest_filesystem_metrics() {
+    match std::fs::read_to_string("/proc/self/mounts") {
+        Err(err) => {
+            info!(sl!(), "failed to get guest mounts: {:?}", err);
+        }
+        Ok(mounts) => {
+            for line in mounts.lines() {
+                let fields: Vec<&str> = line.split_whitespace().collect();
+                if fields.len() < 2 || !fields[1].starts_with(SANDBOX_STORAGE_PATH) {
+                    continue;
+                }
+                set_gauge_vec_filesystem(&GUEST_FILESYSTEM, fields[1]);
+            }
+        }
+    }
+}
+
+#[instrument]
+fn set_gauge_vec_filesystem(gv: &prometheus::GaugeVec, mount_point: &str) {
+    match nix::sys::statvfs::statvfs(mount_point) {
+        Err(err) => {
+            info!(sl!(), "failed to statvfs {}: {:?}", mount_point, err);
+        }
+        Ok(stat) => {
+            let fragment_size = stat.fragment_size() as f64;
+            let size = stat.blocks() as f64 * fragment_size;
+            let free = stat.blocks_free() as f64 * fragment_size;
+
+            gv.with_label_values(&[mount_point, "size"]).set(size);
+            gv.with_label_values(&[mount_point, "used"])
+                .set(size - free);
+            gv.with_label_values(&[mount_point, "available"])
+                .set(stat.blocks_available() as f64 * fragment_size);
+        }
+    }
 }
 
 #[instrument]
@@ -500,3 +620,41 @@ fn set_gauge_vec_proc_stat(gv: &prometheus::GaugeVec, stat: &procfs::process::St
     gv.with_label_values(&["cutime"]).set(stat.cutime as f64);
     gv.with_label_values(&["cstime"]).set(stat.cstime as f64);
 }
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[test]
+    fn test_group_enabled() {
+        let groups: Vec<String> = vec![];
+        assert!(group_enabled(&groups, METRICS_GROUP_PROC));
+
+        let groups = vec![METRICS_GROUP_MEMINFO.to_string()];
+        assert!(group_enabled(&groups, METRICS_GROUP_MEMINFO));
+        assert!(!group_enabled(&groups, METRICS_GROUP_PROC));
+    }
+
+    #[test]
+    fn test_set_gauge_vec_swap() {
+        let gv = prometheus::GaugeVec::new(
+            prometheus::Opts::new("test_swap", "test swap"),
+            &["device", "item"],
+        )
+        .unwrap();
+
+        set_gauge_vec_swap(
+            &gv,
+            "Filename\t\t\t\tType\t\tSize\tUsed\tPriority\n/dev/vdb                                partition\t1048572\t2048\t-2\n",
+        );
+
+        assert_eq!(
+            gv.with_label_values(&["/dev/vdb", "size"]).get(),
+            1048572.0 * 1024.0
+        );
+        assert_eq!(
+            gv.with_label_values(&["/dev/vdb", "used"]).get(),
+            2048.0 * 1024.0
+        );
+    }
+}
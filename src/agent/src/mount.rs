@@ -47,6 +47,7 @@ pub const DRIVER_NVDIMM_TYPE: &str = "nvdimm";
 pub const DRIVER_EPHEMERAL_TYPE: &str = "ephemeral";
 pub const DRIVER_LOCAL_TYPE: &str = "local";
 pub const DRIVER_WATCHABLE_BIND_TYPE: &str = "watchable-bind";
+pub const DRIVER_REMOTE_SNAPSHOT_TYPE: &str = "remote-snapshot";
 
 pub const TYPE_ROOTFS: &str = "rootfs";
 
@@ -55,6 +56,23 @@ pub const MOUNT_GUEST_TAG: &str = "kataShared";
 // Allocating an FSGroup that owns the pod's volumes
 const FS_GID: &str = "fsgid";
 
+// Driver option of the block storages encrypted in the guest with a key
+// generated by the agent and never stored, so that the host cannot read
+// the data written by the pod.
+pub const DRIVER_OPTION_EPHEMERAL_ENCRYPTION: &str = "encryption=ephemeral";
+
+const CRYPTSETUP_PATH: &str = "cryptsetup";
+const MOUNT_PATH: &str = "mount";
+const CRYPT_CIPHER: &str = "aes-xts-plain64";
+// in bits, xts splits the key in two aes-256 keys
+const CRYPT_KEY_SIZE: usize = 512;
+const FS_TYPE_HUGETLB: &str = "hugetlbfs";
+
+// File system type of the block storages enabled as swap rather than mounted
+pub const FS_TYPE_SWAP: &str = "swap";
+const SWAPPINESS_OPTION: &str = "swappiness=";
+const PROC_SWAPPINESS: &str = "/proc/sys/vm/swappiness";
+
 #[rustfmt::skip]
 lazy_static! {
     pub static ref FLAGS: HashMap<&'static str, (bool, MsFlags)> = {
@@ -147,6 +165,7 @@ pub const STORAGE_HANDLER_LIST: &[&str] = &[
     DRIVER_SCSI_TYPE,
     DRIVER_NVDIMM_TYPE,
     DRIVER_WATCHABLE_BIND_TYPE,
+    DRIVER_REMOTE_SNAPSHOT_TYPE,
 ];
 
 #[derive(Debug, Clone)]
@@ -256,6 +275,22 @@ async fn ephemeral_storage_handler(
 
     fs::create_dir_all(Path::new(&storage.mount_point))?;
 
+    // hugetlbfs volumes are backed by the hugepages allocated for each
+    // container, see allocate_container_hugepages. A volume is shared by
+    // the containers of the pod, it is not bounded by the size of the
+    // container creating it.
+    if storage.fstype == FS_TYPE_HUGETLB {
+        let mut new_storage = storage.clone();
+        new_storage.options = storage
+            .options
+            .iter()
+            .filter(|o| !o.starts_with("size="))
+            .cloned()
+            .collect();
+        common_storage_handler(logger, &new_storage)?;
+        return Ok("".to_string());
+    }
+
     // By now we only support one option field: "fsGroup" which
     // isn't an valid mount option, thus we should remove it when
     // do mount.
@@ -288,6 +323,123 @@ async fn ephemeral_storage_handler(
     Ok("".to_string())
 }
 
+// container_hugepages returns the hugepages needed by a container for its
+// hugetlbfs storages, by page size. The size option of a storage is the
+// hugepage limit of the container for its page size, the hugepages are
+// allocated once per page size whatever the number of storages.
+fn container_hugepages(storages: &[Storage]) -> Result<HashMap<u64, u64>> {
+    let mut hugepages = HashMap::new();
+
+    for storage in storages.iter().filter(|s| s.fstype == FS_TYPE_HUGETLB) {
+        let opts = parse_options(storage.options.to_vec());
+
+        let get_option = |name: &str| -> Result<u64> {
+            opts.get(name)
+                .ok_or_else(|| anyhow!("missing {} option", name))?
+                .parse::<u64>()
+                .with_context(|| format!("invalid {} option", name))
+        };
+
+        let pagesize = get_option("pagesize")?;
+        let size = get_option("size")?;
+        if pagesize == 0 {
+            return Err(anyhow!("invalid pagesize option: 0"));
+        }
+
+        let pages = (size + pagesize - 1) / pagesize;
+        let entry = hugepages.entry(pagesize).or_insert(0);
+        *entry = std::cmp::max(*entry, pages);
+    }
+
+    Ok(hugepages)
+}
+
+// allocate_container_hugepages allocates the hugepages backing the hugetlbfs
+// storages of a container, they are released with release_container_hugepages
+// when the container is removed. It returns the number of hugepages allocated
+// by page size.
+#[instrument]
+pub fn allocate_container_hugepages(
+    logger: &Logger,
+    storages: &[Storage],
+) -> Result<Vec<(u64, u64)>> {
+    do_allocate_container_hugepages(logger, SYSFS_HUGEPAGES_PATH, storages)
+}
+
+fn do_allocate_container_hugepages(
+    logger: &Logger,
+    sysfs_dir: &str,
+    storages: &[Storage],
+) -> Result<Vec<(u64, u64)>> {
+    let mut allocated = Vec::new();
+
+    for (pagesize, pages) in container_hugepages(storages)? {
+        if let Err(e) = resize_hugepages(logger, sysfs_dir, pagesize, pages as i64) {
+            do_release_container_hugepages(logger, sysfs_dir, &allocated);
+            return Err(e).context("Failed to allocate hugepages");
+        }
+        allocated.push((pagesize, pages));
+    }
+
+    Ok(allocated)
+}
+
+// release_container_hugepages releases the hugepages allocated for a
+// container, the ones still in use are freed once unmapped.
+#[instrument]
+pub fn release_container_hugepages(logger: &Logger, allocated: &[(u64, u64)]) {
+    do_release_container_hugepages(logger, SYSFS_HUGEPAGES_PATH, allocated)
+}
+
+fn do_release_container_hugepages(logger: &Logger, sysfs_dir: &str, allocated: &[(u64, u64)]) {
+    for (pagesize, pages) in allocated {
+        if let Err(e) = resize_hugepages(logger, sysfs_dir, *pagesize, -(*pages as i64)) {
+            warn!(logger, "failed to release hugepages";
+                "pagesize" => pagesize,
+                "pages" => pages,
+                "error" => format!("{:?}", e));
+        }
+    }
+}
+
+// resize_hugepages adds delta hugepages of pagesize bytes to the pool.
+fn resize_hugepages(logger: &Logger, sysfs_dir: &str, pagesize: u64, delta: i64) -> Result<()> {
+    let nr_hugepages = Path::new(sysfs_dir)
+        .join(format!("hugepages-{}kB", pagesize / 1024))
+        .join("nr_hugepages");
+
+    let read_nr_hugepages = || -> Result<u64> {
+        Ok(fs::read_to_string(&nr_hugepages)
+            .with_context(|| format!("unsupported hugepage size {}", pagesize))?
+            .trim()
+            .parse::<u64>()?)
+    };
+
+    let current = read_nr_hugepages()?;
+    let target = std::cmp::max(current as i64 + delta, 0) as u64;
+
+    info!(logger, "resizing hugepages";
+        "pagesize" => pagesize,
+        "current" => current,
+        "target" => target);
+
+    fs::write(&nr_hugepages, target.to_string())?;
+
+    let allocated = read_nr_hugepages()?;
+    if allocated < target {
+        // do not keep a partial allocation
+        fs::write(&nr_hugepages, current.to_string())?;
+        return Err(anyhow!(
+            "only {} of the {} hugepages of {} bytes could be allocated",
+            allocated.saturating_sub(current),
+            target - current,
+            pagesize
+        ));
+    }
+
+    Ok(())
+}
+
 #[instrument]
 async fn local_storage_handler(
     _logger: &Logger,
@@ -435,12 +587,161 @@ async fn virtio_scsi_storage_handler(
 
 #[instrument]
 fn common_storage_handler(logger: &Logger, storage: &Storage) -> Result<String> {
+    if storage.fstype == FS_TYPE_SWAP {
+        return enable_swap(logger, storage).and(Ok(String::new()));
+    }
+
     // Mount the storage device.
     let mount_point = storage.mount_point.to_string();
 
+    if has_ephemeral_encryption(storage) {
+        let storage = setup_ephemeral_encryption(logger, storage)?;
+        return mount_storage(logger, &storage).and(Ok(mount_point));
+    }
+
     mount_storage(logger, storage).and(Ok(mount_point))
 }
 
+// swappiness returns the vm.swappiness of the swap storage, if any.
+fn swappiness(storage: &Storage) -> Result<Option<u32>> {
+    for o in storage.driver_options.iter() {
+        if let Some(value) = o.strip_prefix(SWAPPINESS_OPTION) {
+            let value = value
+                .parse::<u32>()
+                .context(format!("Invalid swap option {:?}", o))?;
+            return Ok(Some(value));
+        }
+    }
+
+    Ok(None)
+}
+
+// enable_swap enables the swap device formatted by the runtime, and sets
+// the swappiness of the guest.
+fn enable_swap(logger: &Logger, storage: &Storage) -> Result<()> {
+    let swappiness = swappiness(storage)?;
+
+    info!(logger, "enabling swap";
+    "device" => storage.source.as_str(),
+    "swappiness" => format!("{:?}", swappiness),
+    );
+
+    let source = CString::new(storage.source.as_str())?;
+    if unsafe { libc::swapon(source.as_ptr(), 0) } != 0 {
+        return Err(anyhow!(io::Error::last_os_error()))
+            .context(format!("Failed to enable swap on {}", &storage.source));
+    }
+
+    if let Some(swappiness) = swappiness {
+        fs::write(PROC_SWAPPINESS, swappiness.to_string())
+            .context("Failed to set the swappiness")?;
+    }
+
+    Ok(())
+}
+
+fn has_ephemeral_encryption(storage: &Storage) -> bool {
+    storage
+        .driver_options
+        .iter()
+        .any(|o| o == DRIVER_OPTION_EPHEMERAL_ENCRYPTION)
+}
+
+// crypt_device_name returns the device mapper name of the encrypted storage.
+fn crypt_device_name(storage: &Storage) -> Result<String> {
+    let name = Path::new(&storage.mount_point)
+        .file_name()
+        .and_then(|n| n.to_str())
+        .ok_or_else(|| anyhow!("Invalid mount point {:?}", &storage.mount_point))?;
+
+    Ok(format!("{}-crypt", name))
+}
+
+// setup_ephemeral_encryption opens a dm-crypt device over the storage device
+// with a random key, formats it, and returns the storage of the encrypted
+// device. The key only lives in the guest kernel, the data is lost with the
+// sandbox.
+fn setup_ephemeral_encryption(logger: &Logger, storage: &Storage) -> Result<Storage> {
+    let name = crypt_device_name(storage)?;
+
+    let mut key = vec![0u8; CRYPT_KEY_SIZE / 8];
+    File::open("/dev/urandom")
+        .and_then(|mut f| io::Read::read_exact(&mut f, &mut key))
+        .context("Failed to generate the encryption key")?;
+
+    info!(logger, "encrypting storage";
+    "device" => storage.source.as_str(),
+    "name" => name.as_str(),
+    );
+
+    let key_size = CRYPT_KEY_SIZE.to_string();
+    let result = run_with_input(
+        CRYPTSETUP_PATH,
+        &[
+            "open",
+            "--type",
+            "plain",
+            "--cipher",
+            CRYPT_CIPHER,
+            "--key-size",
+            key_size.as_str(),
+            "--key-file",
+            "-",
+            storage.source.as_str(),
+            name.as_str(),
+        ],
+        &key,
+    );
+
+    // do not keep the key around
+    key.iter_mut().for_each(|b| *b = 0);
+    result.context("Failed to open the encrypted device")?;
+
+    let mut storage = storage.clone();
+    storage.source = format!("/dev/mapper/{}", name);
+
+    // the host cannot format the encrypted device
+    let fstype = storage.fstype.clone();
+    run_with_input(
+        &format!("mkfs.{}", fstype),
+        &["-q", "-F", storage.source.as_str()],
+        &[],
+    )
+    .context("Failed to format the encrypted device")?;
+
+    Ok(storage)
+}
+
+pub fn run_with_input(cmd: &str, args: &[&str], input: &[u8]) -> Result<()> {
+    use std::io::Write;
+    use std::process::{Command, Stdio};
+
+    let mut child = Command::new(cmd)
+        .args(args)
+        .stdin(Stdio::piped())
+        .stdout(Stdio::null())
+        .stderr(Stdio::piped())
+        .spawn()
+        .context(format!("Failed to run {}", cmd))?;
+
+    if let Some(mut stdin) = child.stdin.take() {
+        stdin.write_all(input)?;
+    }
+
+    let output = child.wait_with_output()?;
+    if !output.status.success() {
+        return Err(anyhow!(
+            "{} {} failed: {}: {}",
+            cmd,
+            args.join(" "),
+            output.status,
+            String::from_utf8_lossy(&output.stderr).trim()
+        ));
+    }
+
+    Ok(())
+}
+
 // nvdimm_storage_handler handles the storage for NVDIMM driver.
 #[instrument]
 async fn nvdimm_storage_handler(
@@ -456,6 +757,45 @@ async fn nvdimm_storage_handler(
     common_storage_handler(logger, &storage)
 }
 
+// remote_snapshot_storage_handler handles the container rootfs provided by
+// a remote snapshotter (e.g. nydus or stargz). It is mounted through the
+// mount helper of the file system type, which pulls the image lazily.
+#[instrument]
+async fn remote_snapshot_storage_handler(
+    logger: &Logger,
+    storage: &Storage,
+    _sandbox: Arc<Mutex<Sandbox>>,
+) -> Result<String> {
+    fs::create_dir_all(&storage.mount_point).context("Create mount destination failed")?;
+
+    let args = remote_snapshot_mount_args(storage);
+
+    info!(logger, "mounting remote snapshot";
+    "mount-source:" => storage.source.as_str(),
+    "mount-destination" => storage.mount_point.as_str(),
+    "mount-fstype"  => storage.fstype.as_str(),
+    );
+
+    let args: Vec<&str> = args.iter().map(String::as_str).collect();
+    run_with_input(MOUNT_PATH, &args, &[]).context("Failed to mount the remote snapshot")?;
+
+    Ok(storage.mount_point.to_string())
+}
+
+fn remote_snapshot_mount_args(storage: &Storage) -> Vec<String> {
+    let mut args = vec!["-t".to_string(), storage.fstype.clone()];
+
+    if !storage.options.is_empty() {
+        args.push("-o".to_string());
+        args.push(storage.options.join(","));
+    }
+
+    args.push(storage.source.clone());
+    args.push(storage.mount_point.clone());
+
+    args
+}
+
 async fn bind_watcher_storage_handler(
     logger: &Logger,
     storage: &Storage,
@@ -603,6 +943,9 @@ pub async fn add_storages(
                 virtio_scsi_storage_handler(&logger, &storage, sandbox.clone()).await
             }
             DRIVER_NVDIMM_TYPE => nvdimm_storage_handler(&logger, &storage, sandbox.clone()).await,
+            DRIVER_REMOTE_SNAPSHOT_TYPE => {
+                remote_snapshot_storage_handler(&logger, &storage, sandbox.clone()).await
+            }
             DRIVER_WATCHABLE_BIND_TYPE => {
                 bind_watcher_storage_handler(&logger, &storage, sandbox.clone()).await?;
                 // Don't register watch mounts, they're hanlded separately by the watcher.
@@ -866,6 +1209,77 @@ mod tests {
     use std::path::PathBuf;
     use tempfile::tempdir;
 
+    #[test]
+    fn test_allocate_container_hugepages() {
+        let logger = slog::Logger::root(slog::Discard, o!());
+        let tempdir = tempdir().unwrap();
+        let sysfs_dir = tempdir.path().to_str().unwrap();
+
+        let hugepages_dir = tempdir.path().join("hugepages-2048kB");
+        fs::create_dir_all(&hugepages_dir).unwrap();
+        let nr_hugepages = hugepages_dir.join("nr_hugepages");
+        fs::write(&nr_hugepages, "2\n").unwrap();
+
+        let storage = |opts: &[&str]| -> Storage {
+            let mut storage = Storage::new();
+            storage.fstype = FS_TYPE_HUGETLB.to_string();
+            storage.options = opts.iter().map(|o| o.to_string()).collect();
+            storage
+        };
+
+        // 5MB of 2MB pages, in addition to the already allocated ones,
+        // once for the two volumes of the container
+        let storages = vec![
+            storage(&["pagesize=2097152", "size=5242880"]),
+            storage(&["pagesize=2097152", "size=5242880"]),
+        ];
+        let allocated = do_allocate_container_hugepages(&logger, sysfs_dir, &storages).unwrap();
+        assert_eq!(allocated, vec![(2097152, 3)]);
+        assert_eq!(fs::read_to_string(&nr_hugepages).unwrap(), "5");
+
+        // another container of the pod
+        let other = do_allocate_container_hugepages(&logger, sysfs_dir, &storages[..1]).unwrap();
+        assert_eq!(fs::read_to_string(&nr_hugepages).unwrap(), "8");
+
+        // the hugepages are released when the containers are removed
+        do_release_container_hugepages(&logger, sysfs_dir, &allocated);
+        assert_eq!(fs::read_to_string(&nr_hugepages).unwrap(), "5");
+        do_release_container_hugepages(&logger, sysfs_dir, &other);
+        assert_eq!(fs::read_to_string(&nr_hugepages).unwrap(), "2");
+
+        // no hugetlbfs storage
+        assert!(do_allocate_container_hugepages(&logger, sysfs_dir, &[])
+            .unwrap()
+            .is_empty());
+
+        // unsupported page size, nothing is kept allocated
+        assert!(do_allocate_container_hugepages(
+            &logger,
+            sysfs_dir,
+            &[
+                storage(&["pagesize=2097152", "size=5242880"]),
+                storage(&["pagesize=1073741824", "size=1073741824"]),
+            ],
+        )
+        .is_err());
+        assert_eq!(fs::read_to_string(&nr_hugepages).unwrap(), "2");
+
+        for opts in [
+            vec!["size=5242880"],
+            vec!["pagesize=2097152"],
+            vec!["pagesize=0", "size=5242880"],
+            vec!["pagesize=2M", "size=5242880"],
+        ]
+        .iter()
+        {
+            assert!(
+                do_allocate_container_hugepages(&logger, sysfs_dir, &[storage(opts)]).is_err(),
+                "{:?}",
+                opts
+            );
+        }
+    }
+
     #[derive(Debug, PartialEq)]
     enum TestUserType {
         RootOnly,
@@ -1037,6 +1451,76 @@ mod tests {
         }
     }
 
+    #[test]
+    fn test_remote_snapshot_mount_args() {
+        let mut storage = Storage {
+            driver: DRIVER_REMOTE_SNAPSHOT_TYPE.to_string(),
+            fstype: "fuse.nydus".to_string(),
+            source: "docker.io/library/busybox:latest".to_string(),
+            mount_point: "/run/kata-containers/shared/containers/foo".to_string(),
+            ..Default::default()
+        };
+        assert_eq!(
+            remote_snapshot_mount_args(&storage),
+            vec![
+                "-t",
+                "fuse.nydus",
+                "docker.io/library/busybox:latest",
+                "/run/kata-containers/shared/containers/foo"
+            ]
+        );
+
+        storage.options =
+            protobuf::RepeatedField::from_vec(vec!["ro".to_string(), "allow_other".to_string()]);
+        assert_eq!(
+            remote_snapshot_mount_args(&storage),
+            vec![
+                "-t",
+                "fuse.nydus",
+                "-o",
+                "ro,allow_other",
+                "docker.io/library/busybox:latest",
+                "/run/kata-containers/shared/containers/foo"
+            ]
+        );
+    }
+
+    #[test]
+    fn test_ephemeral_encryption() {
+        let mut storage = Storage {
+            driver: DRIVER_BLK_TYPE.to_string(),
+            mount_point: "/run/kata-containers/sandbox/ephemeral-disk".to_string(),
+            ..Default::default()
+        };
+        assert!(!has_ephemeral_encryption(&storage));
+
+        storage.driver_options =
+            protobuf::RepeatedField::from_vec(vec![DRIVER_OPTION_EPHEMERAL_ENCRYPTION.to_string()]);
+        assert!(has_ephemeral_encryption(&storage));
+        assert_eq!(crypt_device_name(&storage).unwrap(), "ephemeral-disk-crypt");
+
+        storage.mount_point = "/".to_string();
+        assert!(crypt_device_name(&storage).is_err());
+    }
+
+    #[test]
+    fn test_swappiness() {
+        let mut storage = Storage {
+            driver: DRIVER_BLK_TYPE.to_string(),
+            fstype: FS_TYPE_SWAP.to_string(),
+            ..Default::default()
+        };
+        assert_eq!(swappiness(&storage).unwrap(), None);
+
+        storage.driver_options =
+            protobuf::RepeatedField::from_vec(vec!["swappiness=60".to_string()]);
+        assert_eq!(swappiness(&storage).unwrap(), Some(60));
+
+        storage.driver_options =
+            protobuf::RepeatedField::from_vec(vec!["swappiness=high".to_string()]);
+        assert!(swappiness(&storage).is_err());
+    }
+
     #[test]
     fn test_is_mounted() {
         assert!(is_mounted("/proc").unwrap());
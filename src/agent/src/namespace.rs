@@ -6,10 +6,13 @@
 use anyhow::{anyhow, Result};
 use nix::mount::MsFlags;
 use nix::sched::{unshare, CloneFlags};
-use nix::unistd::{getpid, gettid};
+use nix::sys::signal::{SigSet, Signal};
+use nix::sys::wait::{waitpid, WaitPidFlag, WaitStatus};
+use nix::unistd::{dup2, fork, getpid, gettid, ForkResult, Pid};
 use std::fmt;
 use std::fs;
-use std::fs::File;
+use std::fs::{File, OpenOptions};
+use std::os::unix::io::AsRawFd;
 use std::path::{Path, PathBuf};
 use tracing::instrument;
 
@@ -21,6 +24,9 @@ pub const NSTYPEIPC: &str = "ipc";
 pub const NSTYPEUTS: &str = "uts";
 pub const NSTYPEPID: &str = "pid";
 
+// Argument of the agent running as the init process of the sandbox pid namespace.
+pub const PIDNS_INIT_ARG: &str = "pidns-init";
+
 #[instrument]
 pub fn get_current_thread_ns_path(ns_type: &str) -> String {
     format!(
@@ -151,6 +157,53 @@ impl Namespace {
     }
 }
 
+// pidns_init creates a new pid namespace and forks its init process, which
+// reaps the orphaned processes of the namespace until it is terminated. The
+// host pid of the init process is written to stdout.
+pub fn pidns_init() -> Result<()> {
+    unshare(CloneFlags::CLONE_NEWPID)?;
+
+    match unsafe { fork() }? {
+        ForkResult::Parent { child } => {
+            println!("{}", child);
+            Ok(())
+        }
+        ForkResult::Child => {
+            // Release the stdio of the agent, so that it reads the pid
+            // until the end of file.
+            let null = OpenOptions::new()
+                .read(true)
+                .write(true)
+                .open("/dev/null")?;
+            for fd in 0..3 {
+                dup2(null.as_raw_fd(), fd)?;
+            }
+
+            pidns_reaper()
+        }
+    }
+}
+
+fn pidns_reaper() -> Result<()> {
+    let mut signals = SigSet::empty();
+    signals.add(Signal::SIGCHLD);
+    signals.add(Signal::SIGTERM);
+    signals.thread_block()?;
+
+    loop {
+        if signals.wait()? == Signal::SIGTERM {
+            return Ok(());
+        }
+
+        loop {
+            match waitpid(Pid::from_raw(-1), Some(WaitPidFlag::WNOHANG)) {
+                Ok(WaitStatus::StillAlive) | Err(_) => break,
+                Ok(_) => continue,
+            }
+        }
+    }
+}
+
 /// Represents the Namespace type.
 #[derive(Clone, Copy, PartialEq)]
 enum NamespaceType {
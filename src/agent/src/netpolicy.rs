@@ -0,0 +1,136 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+// The network policy of the pod is enforced in the guest, on top of the
+// host enforcement, with the nftables rules provided by the runtime, e.g.
+// derived from the Kubernetes NetworkPolicy objects by an external
+// controller. The rules are confined to a table owned by the agent, which
+// is replaced atomically.
+
+use anyhow::{anyhow, Result};
+use slog::Logger;
+
+use crate::mount::run_with_input;
+
+const NFT_PATH: &str = "nft";
+const POLICY_TABLE: &str = "inet kata_policy";
+
+// check_ruleset checks that the ruleset stays in the policy table: its
+// braces are balanced, outside of the strings and comments, and it
+// includes no file, the include keyword being valid after any separator.
+fn check_ruleset(ruleset: &str) -> Result<()> {
+    let mut depth = 0;
+    let mut in_string = false;
+    let mut in_comment = false;
+    let mut word = String::new();
+
+    // the final newline ends the last word
+    for c in ruleset.chars().chain(std::iter::once('\n')) {
+        if !in_comment && !in_string && is_word_char(c) {
+            word.push(c);
+            continue;
+        }
+
+        if word == "include" {
+            return Err(anyhow!("the network policy cannot include files"));
+        }
+        word.clear();
+
+        match c {
+            '\n' => in_comment = false,
+            _ if in_comment => {}
+            '"' => in_string = !in_string,
+            _ if in_string => {}
+            '#' => in_comment = true,
+            '{' => depth += 1,
+            '}' => {
+                depth -= 1;
+                if depth < 0 {
+                    return Err(anyhow!("unbalanced braces in the network policy"));
+                }
+            }
+            _ => {}
+        }
+    }
+
+    if depth != 0 || in_string {
+        return Err(anyhow!("unbalanced braces or quotes in the network policy"));
+    }
+
+    Ok(())
+}
+
+// is_word_char returns true if c is part of an nft keyword or identifier,
+// including the variable and set references.
+fn is_word_char(c: char) -> bool {
+    c.is_ascii_alphanumeric() || matches!(c, '_' | '-' | '.' | '/' | '$' | '@')
+}
+
+// policy_script returns the nft script replacing the policy table with the
+// ruleset, the table being only removed when the ruleset is empty.
+pub fn policy_script(ruleset: &str) -> Result<String> {
+    // the table is declared first for its deletion not to fail
+    let mut script = format!("table {}\ndelete table {}\n", POLICY_TABLE, POLICY_TABLE);
+
+    if !ruleset.trim().is_empty() {
+        check_ruleset(ruleset)?;
+        script.push_str(&format!("table {} {{\n{}\n}}\n", POLICY_TABLE, ruleset));
+    }
+
+    Ok(script)
+}
+
+// set_policy replaces the network policy of the guest, in a single nft
+// transaction.
+pub fn set_policy(logger: &Logger, script: &str) -> Result<()> {
+    run_with_input(NFT_PATH, &["-f", "-"], script.as_bytes())?;
+
+    info!(logger, "network policy set"; "table" => POLICY_TABLE);
+
+    Ok(())
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[test]
+    fn test_policy_script() {
+        assert_eq!(
+            policy_script("").unwrap(),
+            "table inet kata_policy\ndelete table inet kata_policy\n"
+        );
+
+        let ruleset = "chain input {\n type filter hook input priority 0; policy drop;\n ct state established accept # \"}\n}";
+        assert_eq!(
+            policy_script(ruleset).unwrap(),
+            format!(
+                "table inet kata_policy\ndelete table inet kata_policy\ntable inet kata_policy {{\n{}\n}}\n",
+                ruleset
+            )
+        );
+    }
+
+    #[test]
+    fn test_check_ruleset() {
+        assert!(check_ruleset("chain input { }").is_ok());
+        assert!(check_ruleset("chain input { meta comment \"}\" }").is_ok());
+
+        // escaping the policy table
+        assert!(check_ruleset("}\ntable ip filter {").is_err());
+        assert!(check_ruleset("chain input { # {\n}\n}").is_err());
+        assert!(check_ruleset("chain input {").is_err());
+        assert!(check_ruleset("chain input { \"}").is_err());
+        assert!(check_ruleset("include \"/etc/nftables.conf\"").is_err());
+        assert!(check_ruleset("chain input { }; include \"/etc/nftables.conf\"").is_err());
+        assert!(check_ruleset("chain input { }\n\tinclude \"/etc/nftables.conf\"").is_err());
+        assert!(check_ruleset("chain input { };include\"/etc/nftables.conf\"").is_err());
+
+        // not the include keyword
+        assert!(check_ruleset("chain include_input { }").is_ok());
+        assert!(check_ruleset("chain input { meta comment \"include\" }").is_ok());
+        assert!(check_ruleset("chain input { } # include").is_ok());
+    }
+}
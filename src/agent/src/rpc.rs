@@ -20,8 +20,9 @@ use anyhow::{anyhow, Context, Result};
 use oci::{LinuxNamespace, Root, Spec};
 use protobuf::{RepeatedField, SingularPtrField};
 use protocols::agent::{
-    AgentDetails, CopyFileRequest, GuestDetailsResponse, Interfaces, Metrics, OOMEvent,
-    ReadStreamResponse, Routes, StatsContainerResponse, WaitProcessResponse, WriteStreamResponse,
+    AgentDetails, CopyFileRequest, ExecSession, GuestDetailsResponse, GuestStatus, Interfaces,
+    MemoryEvent, Metrics, OOMEvent, ReadStreamResponse, Routes, StatsContainerResponse,
+    WaitProcessResponse, WriteStreamResponse,
 };
 use protocols::empty::Empty;
 use protocols::health::{
@@ -40,11 +41,16 @@ use nix::sys::stat;
 use nix::unistd::{self, Pid};
 use rustjail::process::ProcessOperations;
 
+use crate::console;
 use crate::device::{add_devices, rescan_pci_bus, update_device_cgroup};
 use crate::linux_abi::*;
 use crate::metrics::get_metrics;
-use crate::mount::{add_storages, remove_mounts, BareMount, STORAGE_HANDLER_LIST};
+use crate::mount::{
+    add_storages, allocate_container_hugepages, release_container_hugepages, remove_mounts,
+    BareMount, STORAGE_HANDLER_LIST,
+};
 use crate::namespace::{NSTYPEIPC, NSTYPEPID, NSTYPEUTS};
+use crate::netpolicy;
 use crate::network::setup_guest_dns;
 use crate::random;
 use crate::sandbox::Sandbox;
@@ -64,7 +70,7 @@ use std::convert::TryFrom;
 use std::fs;
 use std::os::unix::prelude::PermissionsExt;
 use std::process::{Command, Stdio};
-use std::time::Duration;
+use std::time::{Duration, Instant, SystemTime, UNIX_EPOCH};
 
 use nix::unistd::{Gid, Uid};
 use std::fs::{File, OpenOptions};
@@ -74,6 +80,8 @@ use std::path::PathBuf;
 
 const CONTAINER_BASE: &str = "/run/kata-containers";
 const MODPROBE_PATH: &str = "/sbin/modprobe";
+const PROC_STAT_PATH: &str = "/proc/stat";
+const PROC_UPTIME_PATH: &str = "/proc/uptime";
 
 // Convenience macro to obtain the scope logger
 macro_rules! sl {
@@ -87,6 +95,19 @@ pub struct AgentService {
     sandbox: Arc<Mutex<Sandbox>>,
 }
 
+// Deny the request if it is not part of the API allow-list
+// received at sandbox creation.
+macro_rules! is_allowed {
+    ($self: ident, $name: literal) => {
+        if !$self.sandbox.lock().await.is_api_allowed($name) {
+            return Err(ttrpc_error(
+                ttrpc::Code::PERMISSION_DENIED,
+                format!("{} is blocked by the agent API allow-list", $name),
+            ));
+        }
+    };
+}
+
 // A container ID must match this regex:
 //
 //     ^[a-zA-Z0-9][a-zA-Z0-9_.-]+$
@@ -196,9 +217,19 @@ impl AgentService {
             return Err(anyhow!(nix::Error::from_errno(nix::errno::Errno::EINVAL)));
         };
 
-        ctr.start(p).await?;
+        // the hugepages of the container are allocated before it starts,
+        // and released when it is removed
+        let hugepages = allocate_container_hugepages(&sl!(), &req.storages)?;
+        let started = match ctr.start(p).await {
+            Ok(()) => s.update_shared_pidns(&ctr),
+            Err(e) => Err(e),
+        };
+        if let Err(e) = started {
+            release_container_hugepages(&sl!(), &hugepages);
+            return Err(e);
+        }
 
-        s.update_shared_pidns(&ctr)?;
+        s.hugepages.insert(cid.clone(), hugepages);
         s.add_container(ctr);
         info!(sl!(), "created container!");
 
@@ -222,9 +253,19 @@ impl AgentService {
         // start oom event loop
         if sid != cid && ctr.cgroup_manager.is_some() {
             let cg_path = ctr.cgroup_manager.as_ref().unwrap().get_cg_path("memory");
-            if cg_path.is_some() {
-                let rx = notifier::notify_oom(cid.as_str(), cg_path.unwrap()).await?;
+            if let Some(cg_path) = cg_path {
+                let rx = notifier::notify_oom(cid.as_str(), cg_path.clone()).await?;
                 s.run_oom_event_monitor(rx, cid.clone()).await;
+
+                // the memory events are informational, the container
+                // runs without them
+                match notifier::notify_memory_events(cid.as_str(), cg_path).await {
+                    Ok(rx) => s.run_memory_event_monitor(rx).await,
+                    Err(e) => warn!(
+                        sl!(),
+                        "failed to watch memory events of container {}: {:?}", cid, e
+                    ),
+                }
             }
         }
 
@@ -257,7 +298,12 @@ impl AgentService {
             }
 
             sandbox.container_mounts.remove(cid.as_str());
-            sandbox.containers.remove(cid.as_str());
+            if let Some(hugepages) = sandbox.hugepages.remove(cid.as_str()) {
+                release_container_hugepages(&sl!(), &hugepages);
+            }
+            if let Some(ctr) = sandbox.containers.remove(cid.as_str()) {
+                sandbox.reset_shared_pidns(&ctr);
+            }
             Ok(())
         };
 
@@ -432,6 +478,9 @@ impl AgentService {
         let _ = cleanup_process(&mut p);
 
         resp.status = p.exit_code;
+        if !p.init {
+            resp.set_session(exec_session(p));
+        }
         // broadcast exit code to all parallel watchers
         for s in p.exit_watchers.iter_mut() {
             // Just ignore errors in case any watcher quits unexpectedly
@@ -531,18 +580,47 @@ impl protocols::agent_ttrpc::AgentService for AgentService {
         req: protocols::agent::CreateContainerRequest,
     ) -> ttrpc::Result<Empty> {
         trace_rpc_call!(ctx, "create_container", req);
+        is_allowed!(self, "grpc.CreateContainerRequest");
         match self.do_create_container(req).await {
             Err(e) => Err(ttrpc_error(ttrpc::Code::INTERNAL, e.to_string())),
             Ok(_) => Ok(Empty::new()),
         }
     }
 
+    async fn create_container_batch(
+        &self,
+        ctx: &TtrpcContext,
+        req: protocols::agent::CreateContainerBatchRequest,
+    ) -> ttrpc::Result<Empty> {
+        trace_rpc_call!(ctx, "create_container_batch", req);
+        is_allowed!(self, "grpc.CreateContainerBatchRequest");
+        is_allowed!(self, "grpc.CopyFileRequest");
+        is_allowed!(self, "grpc.CreateContainerRequest");
+
+        for f in req.files.iter() {
+            do_copy_file(f).map_err(|e| ttrpc_error(ttrpc::Code::INTERNAL, e.to_string()))?;
+        }
+
+        let container = req.container.into_option().ok_or_else(|| {
+            ttrpc_error(
+                ttrpc::Code::INVALID_ARGUMENT,
+                "missing container in create container batch request".to_string(),
+            )
+        })?;
+
+        match self.do_create_container(container).await {
+            Err(e) => Err(ttrpc_error(ttrpc::Code::INTERNAL, e.to_string())),
+            Ok(_) => Ok(Empty::new()),
+        }
+    }
+
     async fn start_container(
         &self,
         ctx: &TtrpcContext,
         req: protocols::agent::StartContainerRequest,
     ) -> ttrpc::Result<Empty> {
         trace_rpc_call!(ctx, "start_container", req);
+        is_allowed!(self, "grpc.StartContainerRequest");
         match self.do_start_container(req).await {
             Err(e) => Err(ttrpc_error(ttrpc::Code::INTERNAL, e.to_string())),
             Ok(_) => Ok(Empty::new()),
@@ -555,6 +633,7 @@ impl protocols::agent_ttrpc::AgentService for AgentService {
         req: protocols::agent::RemoveContainerRequest,
     ) -> ttrpc::Result<Empty> {
         trace_rpc_call!(ctx, "remove_container", req);
+        is_allowed!(self, "grpc.RemoveContainerRequest");
         match self.do_remove_container(req).await {
             Err(e) => Err(ttrpc_error(ttrpc::Code::INTERNAL, e.to_string())),
             Ok(_) => Ok(Empty::new()),
@@ -567,6 +646,7 @@ impl protocols::agent_ttrpc::AgentService for AgentService {
         req: protocols::agent::ExecProcessRequest,
     ) -> ttrpc::Result<Empty> {
         trace_rpc_call!(ctx, "exec_process", req);
+        is_allowed!(self, "grpc.ExecProcessRequest");
         match self.do_exec_process(req).await {
             Err(e) => Err(ttrpc_error(ttrpc::Code::INTERNAL, e.to_string())),
             Ok(_) => Ok(Empty::new()),
@@ -579,6 +659,7 @@ impl protocols::agent_ttrpc::AgentService for AgentService {
         req: protocols::agent::SignalProcessRequest,
     ) -> ttrpc::Result<Empty> {
         trace_rpc_call!(ctx, "signal_process", req);
+        is_allowed!(self, "grpc.SignalProcessRequest");
         match self.do_signal_process(req).await {
             Err(e) => Err(ttrpc_error(ttrpc::Code::INTERNAL, e.to_string())),
             Ok(_) => Ok(Empty::new()),
@@ -591,6 +672,7 @@ impl protocols::agent_ttrpc::AgentService for AgentService {
         req: protocols::agent::WaitProcessRequest,
     ) -> ttrpc::Result<WaitProcessResponse> {
         trace_rpc_call!(ctx, "wait_process", req);
+        is_allowed!(self, "grpc.WaitProcessRequest");
         self.do_wait_process(req)
             .await
             .map_err(|e| ttrpc_error(ttrpc::Code::INTERNAL, e.to_string()))
@@ -602,6 +684,7 @@ impl protocols::agent_ttrpc::AgentService for AgentService {
         req: protocols::agent::UpdateContainerRequest,
     ) -> ttrpc::Result<Empty> {
         trace_rpc_call!(ctx, "update_container", req);
+        is_allowed!(self, "grpc.UpdateContainerRequest");
         let cid = req.container_id.clone();
         let res = req.resources;
 
@@ -637,6 +720,7 @@ impl protocols::agent_ttrpc::AgentService for AgentService {
         req: protocols::agent::StatsContainerRequest,
     ) -> ttrpc::Result<StatsContainerResponse> {
         trace_rpc_call!(ctx, "stats_container", req);
+        is_allowed!(self, "grpc.StatsContainerRequest");
         let cid = req.container_id;
         let s = Arc::clone(&self.sandbox);
         let mut sandbox = s.lock().await;
@@ -658,6 +742,7 @@ impl protocols::agent_ttrpc::AgentService for AgentService {
         req: protocols::agent::PauseContainerRequest,
     ) -> ttrpc::Result<protocols::empty::Empty> {
         trace_rpc_call!(ctx, "pause_container", req);
+        is_allowed!(self, "grpc.PauseContainerRequest");
         let cid = req.get_container_id();
         let s = Arc::clone(&self.sandbox);
         let mut sandbox = s.lock().await;
@@ -681,6 +766,7 @@ impl protocols::agent_ttrpc::AgentService for AgentService {
         req: protocols::agent::ResumeContainerRequest,
     ) -> ttrpc::Result<protocols::empty::Empty> {
         trace_rpc_call!(ctx, "resume_container", req);
+        is_allowed!(self, "grpc.ResumeContainerRequest");
         let cid = req.get_container_id();
         let s = Arc::clone(&self.sandbox);
         let mut sandbox = s.lock().await;
@@ -703,6 +789,7 @@ impl protocols::agent_ttrpc::AgentService for AgentService {
         _ctx: &TtrpcContext,
         req: protocols::agent::WriteStreamRequest,
     ) -> ttrpc::Result<WriteStreamResponse> {
+        is_allowed!(self, "grpc.WriteStreamRequest");
         self.do_write_stream(req)
             .await
             .map_err(|e| ttrpc_error(ttrpc::Code::INTERNAL, e.to_string()))
@@ -713,6 +800,7 @@ impl protocols::agent_ttrpc::AgentService for AgentService {
         _ctx: &TtrpcContext,
         req: protocols::agent::ReadStreamRequest,
     ) -> ttrpc::Result<ReadStreamResponse> {
+        is_allowed!(self, "grpc.ReadStreamRequest");
         self.do_read_stream(req, true)
             .await
             .map_err(|e| ttrpc_error(ttrpc::Code::INTERNAL, e.to_string()))
@@ -723,6 +811,7 @@ impl protocols::agent_ttrpc::AgentService for AgentService {
         _ctx: &TtrpcContext,
         req: protocols::agent::ReadStreamRequest,
     ) -> ttrpc::Result<ReadStreamResponse> {
+        is_allowed!(self, "grpc.ReadStreamRequest");
         self.do_read_stream(req, false)
             .await
             .map_err(|e| ttrpc_error(ttrpc::Code::INTERNAL, e.to_string()))
@@ -734,6 +823,7 @@ impl protocols::agent_ttrpc::AgentService for AgentService {
         req: protocols::agent::CloseStdinRequest,
     ) -> ttrpc::Result<Empty> {
         trace_rpc_call!(ctx, "close_stdin", req);
+        is_allowed!(self, "grpc.CloseStdinRequest");
 
         let cid = req.container_id.clone();
         let eid = req.exec_id;
@@ -770,6 +860,7 @@ impl protocols::agent_ttrpc::AgentService for AgentService {
         req: protocols::agent::TtyWinResizeRequest,
     ) -> ttrpc::Result<Empty> {
         trace_rpc_call!(ctx, "tty_win_resize", req);
+        is_allowed!(self, "grpc.TtyWinResizeRequest");
 
         let cid = req.container_id.clone();
         let eid = req.exec_id.clone();
@@ -810,6 +901,7 @@ impl protocols::agent_ttrpc::AgentService for AgentService {
         req: protocols::agent::UpdateInterfaceRequest,
     ) -> ttrpc::Result<Interface> {
         trace_rpc_call!(ctx, "update_interface", req);
+        is_allowed!(self, "grpc.UpdateInterfaceRequest");
 
         let interface = req.interface.into_option().ok_or_else(|| {
             ttrpc_error(
@@ -837,6 +929,7 @@ impl protocols::agent_ttrpc::AgentService for AgentService {
         req: protocols::agent::UpdateRoutesRequest,
     ) -> ttrpc::Result<Routes> {
         trace_rpc_call!(ctx, "update_routes", req);
+        is_allowed!(self, "grpc.UpdateRoutesRequest");
 
         let new_routes = req
             .routes
@@ -877,6 +970,7 @@ impl protocols::agent_ttrpc::AgentService for AgentService {
         req: protocols::agent::ListInterfacesRequest,
     ) -> ttrpc::Result<Interfaces> {
         trace_rpc_call!(ctx, "list_interfaces", req);
+        is_allowed!(self, "grpc.ListInterfacesRequest");
 
         let list = self
             .sandbox
@@ -904,6 +998,7 @@ impl protocols::agent_ttrpc::AgentService for AgentService {
         req: protocols::agent::ListRoutesRequest,
     ) -> ttrpc::Result<Routes> {
         trace_rpc_call!(ctx, "list_routes", req);
+        is_allowed!(self, "grpc.ListRoutesRequest");
 
         let list = self
             .sandbox
@@ -925,6 +1020,7 @@ impl protocols::agent_ttrpc::AgentService for AgentService {
         _ctx: &TtrpcContext,
         req: protocols::agent::StartTracingRequest,
     ) -> ttrpc::Result<Empty> {
+        is_allowed!(self, "grpc.StartTracingRequest");
         info!(sl!(), "start_tracing {:?}", req);
         Ok(Empty::new())
     }
@@ -934,13 +1030,14 @@ impl protocols::agent_ttrpc::AgentService for AgentService {
         _ctx: &TtrpcContext,
         _req: protocols::agent::StopTracingRequest,
     ) -> ttrpc::Result<Empty> {
+        is_allowed!(self, "grpc.StopTracingRequest");
         Ok(Empty::new())
     }
 
     async fn create_sandbox(
         &self,
         ctx: &TtrpcContext,
-        req: protocols::agent::CreateSandboxRequest,
+        mut req: protocols::agent::CreateSandboxRequest,
     ) -> ttrpc::Result<Empty> {
         trace_rpc_call!(ctx, "create_sandbox", req);
 
@@ -948,9 +1045,31 @@ impl protocols::agent_ttrpc::AgentService for AgentService {
             let sandbox = self.sandbox.clone();
             let mut s = sandbox.lock().await;
 
+            // the sandbox is only created once, a second request would
+            // reset its state and replace the API allow-list
+            if s.running {
+                return Err(ttrpc_error(
+                    ttrpc::Code::ALREADY_EXISTS,
+                    "sandbox already created".to_string(),
+                ));
+            }
+
             let _ = fs::remove_dir_all(CONTAINER_BASE);
             let _ = fs::create_dir_all(CONTAINER_BASE);
 
+            // the metadata not sent by the runtime was passed by the initdata
+            if let Some(data) = s.initdata.clone() {
+                if req.hostname.is_empty() {
+                    req.hostname = data.hostname;
+                }
+                if req.guest_hook_path.is_empty() {
+                    req.guest_hook_path = data.guest_hook_path;
+                }
+                if req.dns.is_empty() {
+                    req.dns = data.dns.into();
+                }
+            }
+
             s.hostname = req.hostname.clone();
             s.running = true;
 
@@ -967,6 +1086,12 @@ impl protocols::agent_ttrpc::AgentService for AgentService {
                 s.id = req.sandbox_id.clone();
             }
 
+            // the allow-list passed by the initdata is never widened
+            if !req.allowed_apis.is_empty() && s.allowed_apis.is_empty() {
+                info!(sl!(), "agent API allow-list"; "allowed_apis" => format!("{:?}", req.allowed_apis));
+                s.allowed_apis = req.allowed_apis.to_vec();
+            }
+
             for m in req.kernel_modules.iter() {
                 load_kernel_module(m)
                     .map_err(|e| ttrpc_error(ttrpc::Code::INTERNAL, e.to_string()))?;
@@ -975,6 +1100,12 @@ impl protocols::agent_ttrpc::AgentService for AgentService {
             s.setup_shared_namespaces()
                 .await
                 .map_err(|e| ttrpc_error(ttrpc::Code::INTERNAL, e.to_string()))?;
+
+            if req.sandbox_pidns {
+                s.setup_shared_pidns()
+                    .await
+                    .map_err(|e| ttrpc_error(ttrpc::Code::INTERNAL, e.to_string()))?;
+            }
         }
 
         match add_storages(sl!(), req.storages.to_vec(), self.sandbox.clone()).await {
@@ -1008,6 +1139,7 @@ impl protocols::agent_ttrpc::AgentService for AgentService {
         req: protocols::agent::DestroySandboxRequest,
     ) -> ttrpc::Result<Empty> {
         trace_rpc_call!(ctx, "destroy_sandbox", req);
+        is_allowed!(self, "grpc.DestroySandboxRequest");
 
         let s = Arc::clone(&self.sandbox);
         let mut sandbox = s.lock().await;
@@ -1017,6 +1149,7 @@ impl protocols::agent_ttrpc::AgentService for AgentService {
         // Close get_oom_event connection,
         // otherwise it will block the shutdown of ttrpc.
         sandbox.event_tx.take();
+        sandbox.memory_event_tx.take();
 
         sandbox.sender.take().unwrap().send(1).unwrap();
 
@@ -1029,6 +1162,7 @@ impl protocols::agent_ttrpc::AgentService for AgentService {
         req: protocols::agent::AddARPNeighborsRequest,
     ) -> ttrpc::Result<Empty> {
         trace_rpc_call!(ctx, "add_arp_neighbors", req);
+        is_allowed!(self, "grpc.AddARPNeighborsRequest");
 
         let neighs = req
             .neighbors
@@ -1062,6 +1196,7 @@ impl protocols::agent_ttrpc::AgentService for AgentService {
         ctx: &TtrpcContext,
         req: protocols::agent::OnlineCPUMemRequest,
     ) -> ttrpc::Result<Empty> {
+        is_allowed!(self, "grpc.OnlineCPUMemRequest");
         let s = Arc::clone(&self.sandbox);
         let sandbox = s.lock().await;
         trace_rpc_call!(ctx, "online_cpu_mem", req);
@@ -1079,6 +1214,7 @@ impl protocols::agent_ttrpc::AgentService for AgentService {
         req: protocols::agent::ReseedRandomDevRequest,
     ) -> ttrpc::Result<Empty> {
         trace_rpc_call!(ctx, "reseed_random_dev", req);
+        is_allowed!(self, "grpc.ReseedRandomDevRequest");
 
         random::reseed_rng(req.data.as_slice())
             .map_err(|e| ttrpc_error(ttrpc::Code::INTERNAL, e.to_string()))?;
@@ -1092,6 +1228,7 @@ impl protocols::agent_ttrpc::AgentService for AgentService {
         req: protocols::agent::GuestDetailsRequest,
     ) -> ttrpc::Result<GuestDetailsResponse> {
         trace_rpc_call!(ctx, "get_guest_details", req);
+        is_allowed!(self, "grpc.GuestDetailsRequest");
 
         info!(sl!(), "get guest details!");
         let mut resp = GuestDetailsResponse::new();
@@ -1120,6 +1257,7 @@ impl protocols::agent_ttrpc::AgentService for AgentService {
         req: protocols::agent::MemHotplugByProbeRequest,
     ) -> ttrpc::Result<Empty> {
         trace_rpc_call!(ctx, "mem_hotplug_by_probe", req);
+        is_allowed!(self, "grpc.MemHotplugByProbeRequest");
 
         do_mem_hotplug_by_probe(&req.memHotplugProbeAddr)
             .map_err(|e| ttrpc_error(ttrpc::Code::INTERNAL, e.to_string()))?;
@@ -1133,6 +1271,7 @@ impl protocols::agent_ttrpc::AgentService for AgentService {
         req: protocols::agent::SetGuestDateTimeRequest,
     ) -> ttrpc::Result<Empty> {
         trace_rpc_call!(ctx, "set_guest_date_time", req);
+        is_allowed!(self, "grpc.SetGuestDateTimeRequest");
 
         do_set_guest_date_time(req.Sec, req.Usec)
             .map_err(|e| ttrpc_error(ttrpc::Code::INTERNAL, e.to_string()))?;
@@ -1146,6 +1285,7 @@ impl protocols::agent_ttrpc::AgentService for AgentService {
         req: protocols::agent::CopyFileRequest,
     ) -> ttrpc::Result<Empty> {
         trace_rpc_call!(ctx, "copy_file", req);
+        is_allowed!(self, "grpc.CopyFileRequest");
 
         do_copy_file(&req).map_err(|e| ttrpc_error(ttrpc::Code::INTERNAL, e.to_string()))?;
 
@@ -1158,6 +1298,7 @@ impl protocols::agent_ttrpc::AgentService for AgentService {
         req: protocols::agent::GetMetricsRequest,
     ) -> ttrpc::Result<Metrics> {
         trace_rpc_call!(ctx, "get_metrics", req);
+        is_allowed!(self, "grpc.GetMetricsRequest");
 
         match get_metrics(&req) {
             Err(e) => Err(ttrpc_error(ttrpc::Code::INTERNAL, e.to_string())),
@@ -1174,6 +1315,7 @@ impl protocols::agent_ttrpc::AgentService for AgentService {
         _ctx: &TtrpcContext,
         _req: protocols::agent::GetOOMEventRequest,
     ) -> ttrpc::Result<OOMEvent> {
+        is_allowed!(self, "grpc.GetOOMEventRequest");
         let sandbox = self.sandbox.clone();
         let s = sandbox.lock().await;
         let event_rx = &s.event_rx.clone();
@@ -1192,6 +1334,131 @@ impl protocols::agent_ttrpc::AgentService for AgentService {
 
         Err(ttrpc_error(ttrpc::Code::INTERNAL, ""))
     }
+
+    async fn get_memory_event(
+        &self,
+        _ctx: &TtrpcContext,
+        _req: protocols::agent::GetMemoryEventRequest,
+    ) -> ttrpc::Result<MemoryEvent> {
+        is_allowed!(self, "grpc.GetMemoryEventRequest");
+        let sandbox = self.sandbox.clone();
+        let s = sandbox.lock().await;
+        let event_rx = &s.memory_event_rx.clone();
+        let mut event_rx = event_rx.lock().await;
+        drop(s);
+        drop(sandbox);
+
+        if let Some(event) = event_rx.recv().await {
+            info!(sl!(), "get_memory_event return {:?}", &event);
+
+            let mut resp = MemoryEvent::new();
+            resp.container_id = event.container_id;
+            resp.event = event.event;
+            resp.count = event.count;
+
+            return Ok(resp);
+        }
+
+        Err(ttrpc_error(ttrpc::Code::INTERNAL, ""))
+    }
+
+    async fn get_guest_status(
+        &self,
+        ctx: &TtrpcContext,
+        req: protocols::agent::GetGuestStatusRequest,
+    ) -> ttrpc::Result<GuestStatus> {
+        trace_rpc_call!(ctx, "get_guest_status", req);
+        is_allowed!(self, "grpc.GetGuestStatusRequest");
+
+        get_guest_status().map_err(|e| ttrpc_error(ttrpc::Code::INTERNAL, e.to_string()))
+    }
+
+    async fn set_network_policy(
+        &self,
+        ctx: &TtrpcContext,
+        req: protocols::agent::SetNetworkPolicyRequest,
+    ) -> ttrpc::Result<Empty> {
+        trace_rpc_call!(ctx, "set_network_policy", req);
+        is_allowed!(self, "grpc.SetNetworkPolicyRequest");
+
+        let script = netpolicy::policy_script(&req.ruleset)
+            .map_err(|e| ttrpc_error(ttrpc::Code::INVALID_ARGUMENT, e.to_string()))?;
+
+        netpolicy::set_policy(&sl!(), &script)
+            .map_err(|e| ttrpc_error(ttrpc::Code::INTERNAL, e.to_string()))?;
+
+        Ok(Empty::new())
+    }
+
+    async fn set_log_level(
+        &self,
+        ctx: &TtrpcContext,
+        req: protocols::agent::SetLogLevelRequest,
+    ) -> ttrpc::Result<Empty> {
+        trace_rpc_call!(ctx, "set_log_level", req);
+        is_allowed!(self, "grpc.SetLogLevelRequest");
+
+        let level = logging::level_name_to_slog_level(&req.level)
+            .map_err(|e| ttrpc_error(ttrpc::Code::INVALID_ARGUMENT, e))?;
+
+        let s = self.sandbox.lock().await;
+        let handle = s.log_level.as_ref().ok_or_else(|| {
+            ttrpc_error(
+                ttrpc::Code::UNAVAILABLE,
+                "the log level cannot be changed".to_string(),
+            )
+        })?;
+
+        // logged before the change, for the record to be kept when the
+        // level is raised
+        info!(sl!(), "setting the log level"; "level" => &req.level);
+        handle.set(level);
+
+        Ok(Empty::new())
+    }
+
+    async fn set_debug_console(
+        &self,
+        ctx: &TtrpcContext,
+        req: protocols::agent::SetDebugConsoleRequest,
+    ) -> ttrpc::Result<Empty> {
+        trace_rpc_call!(ctx, "set_debug_console", req);
+        is_allowed!(self, "grpc.SetDebugConsoleRequest");
+
+        let mut s = self.sandbox.lock().await;
+
+        if !req.enable {
+            // the sessions already opened are not closed
+            if let Some(stop) = s.debug_console.take() {
+                let _ = stop.send(true);
+                info!(sl!(), "debug console stopped");
+            }
+            return Ok(Empty::new());
+        }
+
+        if s.debug_console.is_some() {
+            return Ok(Empty::new());
+        }
+
+        let config = AGENT_CONFIG.read().await;
+        if !config.debug_console && !config.debug_console_control {
+            return Err(ttrpc_error(
+                ttrpc::Code::PERMISSION_DENIED,
+                "the debug console is not allowed by the kernel command line, see agent.debug_console_control".to_string(),
+            ));
+        }
+
+        let port = if req.vport > 0 {
+            req.vport
+        } else {
+            config.debug_console_vport as u32
+        };
+
+        s.debug_console = Some(console::start_debug_console(&sl!(), port));
+        info!(sl!(), "debug console started"; "vport" => port);
+
+        Ok(Empty::new())
+    }
 }
 
 #[derive(Clone)]
@@ -1272,6 +1539,44 @@ fn get_memory_info(block_size: bool, hotplug: bool) -> Result<(u64, bool)> {
     Ok((size, plug))
 }
 
+// parse_boot_time returns the btime of /proc/stat, the boot time in seconds
+// since the epoch.
+fn parse_boot_time(stat: &str) -> Result<i64> {
+    stat.lines()
+        .find_map(|l| l.strip_prefix("btime "))
+        .ok_or_else(|| anyhow!("no btime in {}", PROC_STAT_PATH))?
+        .trim()
+        .parse::<i64>()
+        .map_err(|e| anyhow!("invalid btime in {}: {}", PROC_STAT_PATH, e))
+}
+
+// parse_uptime_ms returns the first field of /proc/uptime, the seconds
+// elapsed since the boot, in milliseconds.
+fn parse_uptime_ms(uptime: &str) -> Result<u64> {
+    let secs = uptime
+        .split_whitespace()
+        .next()
+        .ok_or_else(|| anyhow!("empty {}", PROC_UPTIME_PATH))?
+        .parse::<f64>()
+        .map_err(|e| anyhow!("invalid {}: {}", PROC_UPTIME_PATH, e))?;
+
+    Ok((secs * 1000.0) as u64)
+}
+
+fn get_guest_status() -> Result<GuestStatus> {
+    let mut status = GuestStatus::new();
+
+    status.boot_time = parse_boot_time(&fs::read_to_string(PROC_STAT_PATH)?)?;
+    status.uptime_ms = parse_uptime_ms(&fs::read_to_string(PROC_UPTIME_PATH)?)?;
+    status.time_ns = SystemTime::now()
+        .duration_since(UNIX_EPOCH)
+        .map(|d| d.as_nanos() as i64)
+        .unwrap_or_default();
+    status.agent_version = AGENT_VERSION.to_string();
+
+    Ok(status)
+}
+
 fn get_agent_details() -> AgentDetails {
     let mut detail = AgentDetails::new();
 
@@ -1325,6 +1630,24 @@ fn find_process<'a>(
     ctr.get_process(eid).map_err(|_| anyhow!("Invalid exec id"))
 }
 
+// exec_session returns the record of an exited exec process, reported to
+// the runtime for the audit of the exec sessions.
+fn exec_session(p: &Process) -> ExecSession {
+    let duration = p
+        .exit_time
+        .unwrap_or_else(Instant::now)
+        .duration_since(p.start_time);
+
+    ExecSession {
+        uid: p.oci.user.uid,
+        gid: p.oci.user.gid,
+        args: RepeatedField::from_vec(p.oci.args.clone()),
+        terminal: p.oci.terminal,
+        duration_ms: duration.as_millis() as u64,
+        ..Default::default()
+    }
+}
+
 pub fn start(s: Arc<Mutex<Sandbox>>, server_address: &str) -> TtrpcServer {
     let agent_service = Box::new(AgentService { sandbox: s })
         as Box<dyn protocols::agent_ttrpc::AgentService + Send + Sync>;
@@ -1616,7 +1939,7 @@ fn cleanup_process(p: &mut Process) -> Result<()> {
     Ok(())
 }
 
-fn load_kernel_module(module: &protocols::agent::KernelModule) -> Result<()> {
+pub fn load_kernel_module(module: &protocols::agent::KernelModule) -> Result<()> {
     if module.name.is_empty() {
         return Err(anyhow!("Kernel module name is empty"));
     }
@@ -1694,6 +2017,43 @@ mod tests {
         assert!(result.is_ok(), "load module should success");
     }
 
+    #[test]
+    fn test_parse_guest_status() {
+        let stat = "cpu  10 0 20 300 0 0 0 0 0 0\nintr 42\nbtime 1626163200\nprocesses 100\n";
+        assert_eq!(parse_boot_time(stat).unwrap(), 1626163200);
+        assert!(parse_boot_time("cpu  10 0 20 300\n").is_err());
+        assert!(parse_boot_time("btime x\n").is_err());
+
+        assert_eq!(parse_uptime_ms("3600.25 7000.50\n").unwrap(), 3600250);
+        assert!(parse_uptime_ms("").is_err());
+        assert!(parse_uptime_ms("x 1").is_err());
+
+        let status = get_guest_status().unwrap();
+        assert!(status.boot_time > 0);
+        assert!(status.time_ns / 1_000_000_000 >= status.boot_time);
+        assert_eq!(status.agent_version, AGENT_VERSION);
+    }
+
+    #[test]
+    fn test_exec_session() {
+        let mut ocip = oci::Process::default();
+        ocip.user.uid = 1000;
+        ocip.user.gid = 100;
+        ocip.args = vec!["sh".to_string(), "-c".to_string(), "id".to_string()];
+        ocip.terminal = true;
+
+        let logger = slog::Logger::root(slog::Discard, o!());
+        let mut p = Process::new(&logger, &ocip, "exec1", false, 0).unwrap();
+        p.exit_time = Some(p.start_time + Duration::from_millis(1500));
+
+        let session = exec_session(&p);
+        assert_eq!(session.uid, 1000);
+        assert_eq!(session.gid, 100);
+        assert_eq!(session.args.to_vec(), ocip.args);
+        assert!(session.terminal);
+        assert_eq!(session.duration_ms, 1500);
+    }
+
     #[tokio::test]
     async fn test_append_guest_hooks() {
         let logger = slog::Logger::root(slog::Discard, o!());
@@ -1712,6 +2072,28 @@ mod tests {
         assert_eq!(s.hooks, oci.hooks);
     }
 
+    #[tokio::test]
+    async fn test_create_sandbox_twice() {
+        let logger = slog::Logger::root(slog::Discard, o!());
+        let mut sandbox = Sandbox::new(&logger).unwrap();
+        sandbox.running = true;
+        sandbox.allowed_apis = vec!["grpc.GetMetricsRequest".to_string()];
+
+        let agent_service = Box::new(AgentService {
+            sandbox: Arc::new(Mutex::new(sandbox)),
+        });
+
+        let req = protocols::agent::CreateSandboxRequest::default();
+        let ctx = mk_ttrpc_context();
+
+        let result = agent_service.create_sandbox(&ctx, req).await;
+        assert!(result.is_err(), "expected a second create sandbox to fail");
+
+        let s = agent_service.sandbox.lock().await;
+        assert!(s.running);
+        assert_eq!(s.allowed_apis, vec!["grpc.GetMetricsRequest"]);
+    }
+
     #[tokio::test]
     async fn test_update_interface() {
         let logger = slog::Logger::root(slog::Discard, o!());
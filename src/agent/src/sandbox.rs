@@ -3,19 +3,24 @@
 // SPDX-License-Identifier: Apache-2.0
 //
 
+use crate::initdata::InitData;
 use crate::linux_abi::*;
 use crate::mount::{get_mount_fs_type, remove_mounts, TYPE_ROOTFS};
-use crate::namespace::Namespace;
+use crate::namespace::{Namespace, PIDNS_INIT_ARG};
 use crate::netlink::Handle;
 use crate::network::Network;
 use crate::uevent::{Uevent, UeventMatcher};
 use crate::watcher::BindWatcher;
 use anyhow::{anyhow, Context, Result};
 use libc::pid_t;
+use logging::LevelHandle;
+use nix::sys::signal::{self, Signal};
+use nix::unistd::Pid;
 use oci::{Hook, Hooks};
 use protocols::agent::OnlineCPUMemRequest;
 use regex::Regex;
 use rustjail::cgroups as rustjail_cgroups;
+use rustjail::cgroups::notifier::MemoryEvent;
 use rustjail::container::BaseContainer;
 use rustjail::container::LinuxContainer;
 use rustjail::process::Process;
@@ -28,6 +33,7 @@ use std::sync::Arc;
 use std::{thread, time};
 use tokio::sync::mpsc::{channel, Receiver, Sender};
 use tokio::sync::oneshot;
+use tokio::sync::watch;
 use tokio::sync::Mutex;
 use tracing::instrument;
 
@@ -47,7 +53,11 @@ pub struct Sandbox {
     pub shared_utsns: Namespace,
     pub shared_ipcns: Namespace,
     pub sandbox_pidns: Option<Namespace>,
+    pub pidns_init_pid: Option<pid_t>,
     pub storages: HashMap<String, u32>,
+    // the hugepages allocated for the containers, by page size,
+    // see allocate_container_hugepages
+    pub hugepages: HashMap<String, Vec<(u64, u64)>>,
     pub running: bool,
     pub no_pivot_root: bool,
     pub sender: Option<tokio::sync::oneshot::Sender<i32>>,
@@ -55,7 +65,15 @@ pub struct Sandbox {
     pub hooks: Option<Hooks>,
     pub event_rx: Arc<Mutex<Receiver<String>>>,
     pub event_tx: Option<Sender<String>>,
+    pub memory_event_rx: Arc<Mutex<Receiver<MemoryEvent>>>,
+    pub memory_event_tx: Option<Sender<MemoryEvent>>,
     pub bind_watcher: BindWatcher,
+    pub allowed_apis: Vec<String>,
+    pub initdata: Option<InitData>,
+    // changes the level of the agent logger, see SetLogLevel
+    pub log_level: Option<LevelHandle>,
+    // stops the debug console when it is running, see SetDebugConsole
+    pub debug_console: Option<watch::Sender<bool>>,
 }
 
 impl Sandbox {
@@ -65,6 +83,8 @@ impl Sandbox {
         let logger = logger.new(o!("subsystem" => "sandbox"));
         let (tx, rx) = channel::<String>(100);
         let event_rx = Arc::new(Mutex::new(rx));
+        let (memory_event_tx, rx) = channel::<MemoryEvent>(100);
+        let memory_event_rx = Arc::new(Mutex::new(rx));
 
         Ok(Sandbox {
             logger: logger.clone(),
@@ -79,7 +99,9 @@ impl Sandbox {
             shared_utsns: Namespace::new(&logger),
             shared_ipcns: Namespace::new(&logger),
             sandbox_pidns: None,
+            pidns_init_pid: None,
             storages: HashMap::new(),
+            hugepages: HashMap::new(),
             running: false,
             no_pivot_root: fs_type.eq(TYPE_ROOTFS),
             sender: None,
@@ -87,7 +109,13 @@ impl Sandbox {
             hooks: None,
             event_rx,
             event_tx: Some(tx),
+            memory_event_rx,
+            memory_event_tx: Some(memory_event_tx),
             bind_watcher: BindWatcher::new(),
+            allowed_apis: Vec::new(),
+            initdata: None,
+            log_level: None,
+            debug_console: None,
         })
     }
 
@@ -212,6 +240,56 @@ impl Sandbox {
         Ok(())
     }
 
+    // setup_shared_pidns creates the sandbox pid namespace, held by an agent
+    // managed init process so that it outlives the containers of the sandbox.
+    #[instrument]
+    pub async fn setup_shared_pidns(&mut self) -> Result<()> {
+        // Avoid the exit signal to be reaped by the global reaper.
+        let _wait_locker = rustjail::container::WAIT_PID_LOCKER.lock().await;
+        let output = tokio::process::Command::new("/proc/self/exe")
+            .arg(PIDNS_INIT_ARG)
+            .output()
+            .await
+            .context("Failed to start the sandbox pid namespace init")?;
+
+        if !output.status.success() {
+            return Err(anyhow!(
+                "Failed to setup the sandbox pid namespace: {}",
+                String::from_utf8_lossy(&output.stderr)
+            ));
+        }
+
+        let pid = String::from_utf8_lossy(&output.stdout)
+            .trim()
+            .parse::<pid_t>()
+            .context("Invalid pid of the sandbox pid namespace init")?;
+
+        let mut pid_ns = Namespace::new(&self.logger).get_pid();
+        pid_ns.path = format!("/proc/{}/ns/pid", pid);
+
+        info!(self.logger, "sandbox pid namespace created"; "init-pid" => pid);
+
+        self.sandbox_pidns = Some(pid_ns);
+        self.pidns_init_pid = Some(pid);
+
+        Ok(())
+    }
+
+    // reset_shared_pidns forgets the sandbox pid namespace when it is the one
+    // of the removed container, the next container created becoming the infra
+    // container. The namespace held by the agent is never reset.
+    #[instrument]
+    pub fn reset_shared_pidns(&mut self, c: &LinuxContainer) {
+        if self.pidns_init_pid.is_some() {
+            return;
+        }
+
+        let ns_path = format!("/proc/{}/ns/pid", c.init_process_pid);
+        if self.sandbox_pidns.as_ref().map(|ns| ns.path == ns_path) == Some(true) {
+            self.sandbox_pidns = None;
+        }
+    }
+
     pub fn get_container(&mut self, id: &str) -> Option<&mut LinuxContainer> {
         self.containers.get_mut(id)
     }
@@ -231,6 +309,11 @@ impl Sandbox {
         for ctr in self.containers.values_mut() {
             ctr.destroy().await?;
         }
+
+        if let Some(pid) = self.pidns_init_pid.take() {
+            let _ = signal::kill(Pid::from_raw(pid), Some(Signal::SIGKILL));
+        }
+
         Ok(())
     }
 
@@ -278,6 +361,12 @@ impl Sandbox {
         Ok(())
     }
 
+    // is_api_allowed returns true if the request is part of the API
+    // allow-list received at sandbox creation, or if there is none.
+    pub fn is_api_allowed(&self, name: &str) -> bool {
+        self.allowed_apis.is_empty() || self.allowed_apis.iter().any(|api| api == name)
+    }
+
     #[instrument]
     pub fn add_hooks(&mut self, dir: &str) -> Result<()> {
         let mut hooks = Hooks::default();
@@ -362,6 +451,34 @@ impl Sandbox {
             }
         });
     }
+
+    #[instrument]
+    pub async fn run_memory_event_monitor(&self, mut rx: Receiver<MemoryEvent>) {
+        let logger = self.logger.clone();
+
+        if self.memory_event_tx.is_none() {
+            error!(
+                logger,
+                "sandbox.memory_event_tx not found in run_memory_event_monitor"
+            );
+            return;
+        }
+
+        let tx = self.memory_event_tx.as_ref().unwrap().clone();
+
+        tokio::spawn(async move {
+            // None means the container has exited,
+            // and sender in memory events notifier is dropped.
+            while let Some(event) = rx.recv().await {
+                info!(logger, "got a memory event {:?}", event);
+
+                let _ = tx
+                    .send(event)
+                    .await
+                    .map_err(|e| error!(logger, "failed to send message: {:?}", e));
+            }
+        });
+    }
 }
 
 #[instrument]
@@ -743,6 +860,33 @@ mod tests {
         assert_eq!(s.sandbox_pidns.unwrap().path, ns_path);
     }
 
+    #[tokio::test]
+    async fn reset_shared_pidns() {
+        skip_if_not_root!();
+        let logger = slog::Logger::root(slog::Discard, o!());
+        let mut s = Sandbox::new(&logger).unwrap();
+
+        let mut infra = create_linuxcontainer();
+        infra.init_process_pid = 9999;
+        s.update_shared_pidns(&infra).unwrap();
+
+        let mut other = create_linuxcontainer();
+        other.init_process_pid = 10000;
+        s.reset_shared_pidns(&other);
+        assert!(s.sandbox_pidns.is_some());
+
+        s.reset_shared_pidns(&infra);
+        assert!(s.sandbox_pidns.is_none());
+
+        // the namespace held by the agent is kept
+        let mut pid_ns = Namespace::new(&logger).get_pid();
+        pid_ns.path = format!("/proc/{}/ns/pid", infra.init_process_pid);
+        s.sandbox_pidns = Some(pid_ns);
+        s.pidns_init_pid = Some(infra.init_process_pid);
+        s.reset_shared_pidns(&infra);
+        assert!(s.sandbox_pidns.is_some());
+    }
+
     #[tokio::test]
     async fn add_guest_hooks() {
         let logger = slog::Logger::root(slog::Discard, o!());
@@ -773,4 +917,17 @@ mod tests {
         let ret = s.destroy().await;
         assert!(ret.is_ok());
     }
+
+    #[tokio::test]
+    async fn test_is_api_allowed() {
+        let logger = slog::Logger::root(slog::Discard, o!());
+        let mut s = Sandbox::new(&logger).unwrap();
+
+        // all the APIs are allowed without allow-list
+        assert!(s.is_api_allowed("grpc.ExecProcessRequest"));
+
+        s.allowed_apis = vec!["grpc.CreateContainerRequest".to_string()];
+        assert!(s.is_api_allowed("grpc.CreateContainerRequest"));
+        assert!(!s.is_api_allowed("grpc.ExecProcessRequest"));
+    }
 }
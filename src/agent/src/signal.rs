@@ -12,6 +12,7 @@ use nix::sys::wait::{self, WaitStatus};
 use nix::unistd;
 use slog::{error, info, o, Logger};
 use std::sync::Arc;
+use std::time::Instant;
 use tokio::select;
 use tokio::signal::unix::{signal, SignalKind};
 use tokio::sync::watch::Receiver;
@@ -71,6 +72,7 @@ async fn handle_sigchild(logger: Logger, sandbox: Arc<Mutex<Sandbox>>) -> Result
             }
 
             p.exit_code = ret;
+            p.exit_time = Some(Instant::now());
             let _ = p.exit_tx.take();
 
             info!(logger, "notify term to close");
@@ -12,6 +12,7 @@ import (
 	"github.com/containerd/containerd/runtime/v2/shim"
 	containerdshim "github.com/kata-containers/kata-containers/src/runtime/containerd-shim-v2"
 	"github.com/kata-containers/kata-containers/src/runtime/pkg/types"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/vmmsandbox"
 )
 
 func shimConfig(config *shim.Config) {
@@ -20,11 +21,14 @@ func shimConfig(config *shim.Config) {
 }
 
 func main() {
+	// never returns when executed as the hypervisor launcher
+	vmmsandbox.Init()
 
 	if len(os.Args) == 2 && os.Args[1] == "--version" {
 		fmt.Printf("%s containerd shim: id: %q, version: %s, commit: %v\n", project, types.DefaultKataRuntimeName, version, commit)
 		os.Exit(0)
 	}
 
+	containerdshim.SetVersion(version, commit)
 	shim.Run(types.DefaultKataRuntimeName, containerdshim.New, shimConfig)
 }
@@ -0,0 +1,80 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/katautils"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/compatoci"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/oci"
+	"github.com/urfave/cli"
+)
+
+var createCLICommand = cli.Command{
+	Name:      "create",
+	Usage:     "resolve the sandbox of a bundle without launching it",
+	ArgsUsage: `<container-id>`,
+	Description: `resolves the configuration, the devices, the network and the hypervisor
+   command line of the sandbox of the bundle, and prints them as JSON without
+   launching anything. Only the dry run mode is supported, the sandboxes being
+   created by the containerd shim.`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "bundle, b",
+			Value: "",
+			Usage: "path to the root of the bundle directory, defaults to the current directory",
+		},
+		cli.BoolFlag{
+			Name:  "dry-run",
+			Usage: "print the plan of the sandbox without launching it",
+		},
+	},
+	Action: func(context *cli.Context) error {
+		ctx, err := cliContextToContext(context)
+		if err != nil {
+			return err
+		}
+
+		if !context.Bool("dry-run") {
+			return errors.New("only the dry run mode is supported, use --dry-run")
+		}
+
+		containerID := context.Args().First()
+		if containerID == "" {
+			return errors.New("missing container ID")
+		}
+
+		runtimeConfig, ok := context.App.Metadata["runtimeConfig"].(oci.RuntimeConfig)
+		if !ok {
+			return errors.New("invalid runtime config")
+		}
+
+		bundlePath := context.String("bundle")
+		if bundlePath == "" {
+			if bundlePath, err = os.Getwd(); err != nil {
+				return err
+			}
+		}
+
+		ociSpec, err := compatoci.ParseConfigJSON(bundlePath)
+		if err != nil {
+			return fmt.Errorf("invalid bundle %s: %v", bundlePath, err)
+		}
+
+		plan, err := katautils.PlanSandbox(ctx, vci, ociSpec, runtimeConfig, containerID, bundlePath)
+		if err != nil {
+			return err
+		}
+
+		encoder := json.NewEncoder(defaultOutputFile)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(plan)
+	},
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/vcmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/urfave/cli"
+)
+
+func TestCreateCLIFunctionDryRun(t *testing.T) {
+	assert := assert.New(t)
+
+	tmpdir, err := ioutil.TempDir(testDir, "")
+	assert.NoError(err)
+	defer os.RemoveAll(tmpdir)
+
+	runtimeConfig, err := newTestRuntimeConfig(tmpdir, testConsole, true)
+	assert.NoError(err)
+
+	const containerID = "dry-run"
+	bundlePath := filepath.Join(tmpdir, "bundle")
+	assert.NoError(makeOCIBundle(bundlePath))
+
+	testingImpl.CreateSandboxFunc = func(ctx context.Context, sandboxConfig vc.SandboxConfig) (vc.VCSandbox, error) {
+		if !sandboxConfig.DryRun {
+			return nil, errors.New("not a dry run")
+		}
+		return &vcmock.Sandbox{
+			MockID: sandboxConfig.ID,
+			GetDryRunPlanFunc: func() *vc.SandboxPlan {
+				return &vc.SandboxPlan{ID: sandboxConfig.ID, Hypervisor: vc.HypervisorPlan{Type: vc.MockHypervisor}}
+			},
+		}, nil
+	}
+	defer func() {
+		testingImpl.CreateSandboxFunc = nil
+	}()
+
+	output, err := ioutil.TempFile(tmpdir, "output")
+	assert.NoError(err)
+	defer output.Close()
+
+	savedOutputFile := defaultOutputFile
+	defaultOutputFile = output
+	defer func() {
+		defaultOutputFile = savedOutputFile
+	}()
+
+	fn, ok := createCLICommand.Action.(func(context *cli.Context) error)
+	assert.True(ok)
+
+	run := func(dryRun bool, args ...string) error {
+		set := flag.NewFlagSet("", 0)
+		set.String("bundle", bundlePath, "")
+		set.Bool("dry-run", dryRun, "")
+		assert.NoError(set.Parse(args))
+
+		ctx := createCLIContext(set)
+		ctx.App.Metadata["runtimeConfig"] = runtimeConfig
+		return fn(ctx)
+	}
+
+	// sandboxes are only created by the shim
+	assert.Error(run(false, containerID))
+	assert.Error(run(true))
+
+	assert.NoError(run(true, containerID))
+
+	data, err := ioutil.ReadFile(output.Name())
+	assert.NoError(err)
+
+	var plan vc.SandboxPlan
+	assert.NoError(json.Unmarshal(data, &plan))
+	assert.Equal(containerID, plan.ID)
+	assert.Equal(vc.MockHypervisor, plan.Hypervisor.Type)
+}
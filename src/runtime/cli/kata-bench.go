@@ -0,0 +1,243 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/katautils"
+	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/oci"
+	"github.com/urfave/cli"
+)
+
+const defaultBenchIterations = 10
+
+// phases of the sandbox lifecycle measured by the bench command
+const (
+	benchPhaseCreate = "create"
+	benchPhaseStart  = "start"
+	benchPhaseStop   = "stop"
+	benchPhaseDelete = "delete"
+	benchPhaseTotal  = "total"
+)
+
+var benchPhases = []string{benchPhaseCreate, benchPhaseStart, benchPhaseStop, benchPhaseDelete, benchPhaseTotal}
+
+// BenchPhaseResult holds the latency statistics of one phase, in milliseconds.
+type BenchPhaseResult struct {
+	Phase string
+	Min   float64
+	Mean  float64
+	P50   float64
+	P90   float64
+	P99   float64
+	Max   float64
+}
+
+var kataBenchCLICommand = cli.Command{
+	Name:  "bench",
+	Usage: "benchmark the sandbox lifecycle with the current configuration",
+	Description: `creates, starts, stops and deletes a minimal sandbox (without any
+   container nor network) repeatedly, and reports the latency percentiles of each phase.`,
+	Flags: []cli.Flag{
+		cli.UintFlag{
+			Name:  "iterations, n",
+			Value: defaultBenchIterations,
+			Usage: "number of sandboxes to run",
+		},
+		cli.BoolFlag{
+			Name:  "json",
+			Usage: "output the results in JSON format",
+		},
+	},
+	Action: func(context *cli.Context) error {
+		ctx, err := cliContextToContext(context)
+		if err != nil {
+			return err
+		}
+
+		runtimeConfig, ok := context.App.Metadata["runtimeConfig"].(oci.RuntimeConfig)
+		if !ok {
+			return errors.New("invalid runtime config")
+		}
+
+		iterations := context.Uint("iterations")
+		if iterations == 0 {
+			return errors.New("iterations must be greater than 0")
+		}
+
+		katautils.HandleFactory(ctx, vci, &runtimeConfig)
+
+		durations, err := runBench(ctx, runtimeConfig, int(iterations))
+		if err != nil {
+			return err
+		}
+
+		results := benchResults(durations)
+
+		if context.Bool("json") {
+			return writeBenchJSON(defaultOutputFile, results)
+		}
+
+		return writeBenchTable(defaultOutputFile, results)
+	},
+}
+
+// benchSandboxConfig returns the config of a minimal sandbox, only made
+// of the VM and the agent, built from the runtime configuration.
+func benchSandboxConfig(id string, runtimeConfig oci.RuntimeConfig) vc.SandboxConfig {
+	return vc.SandboxConfig{
+		ID: id,
+
+		HypervisorType:   runtimeConfig.HypervisorType,
+		HypervisorConfig: runtimeConfig.HypervisorConfig,
+
+		AgentConfig: runtimeConfig.AgentConfig,
+
+		SandboxCgroupOnly:   runtimeConfig.SandboxCgroupOnly,
+		DisableGuestSeccomp: runtimeConfig.DisableGuestSeccomp,
+
+		Experimental: runtimeConfig.Experimental,
+	}
+}
+
+// runBench runs the sandboxes one after the other, and returns the
+// durations of each phase.
+func runBench(ctx context.Context, runtimeConfig oci.RuntimeConfig, iterations int) (map[string][]time.Duration, error) {
+	durations := make(map[string][]time.Duration)
+
+	for i := 0; i < iterations; i++ {
+		id := fmt.Sprintf("kata-bench-%d-%d", os.Getpid(), i)
+
+		phases, err := benchSandbox(ctx, benchSandboxConfig(id, runtimeConfig))
+		if err != nil {
+			return nil, fmt.Errorf("iteration %d failed: %v", i, err)
+		}
+
+		var total time.Duration
+		for phase, d := range phases {
+			durations[phase] = append(durations[phase], d)
+			total += d
+		}
+		durations[benchPhaseTotal] = append(durations[benchPhaseTotal], total)
+
+		kataLog.WithField("sandbox", id).WithField("phases", phases).Debug("bench iteration done")
+	}
+
+	return durations, nil
+}
+
+func benchSandbox(ctx context.Context, sandboxConfig vc.SandboxConfig) (phases map[string]time.Duration, err error) {
+	phases = make(map[string]time.Duration)
+
+	start := time.Now()
+	sandbox, err := vci.CreateSandbox(ctx, sandboxConfig)
+	if err != nil {
+		return nil, err
+	}
+	phases[benchPhaseCreate] = time.Since(start)
+
+	defer func() {
+		if err != nil {
+			sandbox.Stop(ctx, true)
+			sandbox.Delete(ctx)
+		}
+	}()
+
+	start = time.Now()
+	if err = sandbox.Start(ctx); err != nil {
+		return nil, err
+	}
+	phases[benchPhaseStart] = time.Since(start)
+
+	start = time.Now()
+	if err = sandbox.Stop(ctx, false); err != nil {
+		return nil, err
+	}
+	phases[benchPhaseStop] = time.Since(start)
+
+	start = time.Now()
+	if err = sandbox.Delete(ctx); err != nil {
+		return nil, err
+	}
+	phases[benchPhaseDelete] = time.Since(start)
+
+	return phases, nil
+}
+
+func benchResults(durations map[string][]time.Duration) []BenchPhaseResult {
+	var results []BenchPhaseResult
+
+	for _, phase := range benchPhases {
+		d := durations[phase]
+		if len(d) == 0 {
+			continue
+		}
+
+		ms := make([]float64, len(d))
+		sum := 0.0
+		for i, v := range d {
+			ms[i] = float64(v) / float64(time.Millisecond)
+			sum += ms[i]
+		}
+		sort.Float64s(ms)
+
+		results = append(results, BenchPhaseResult{
+			Phase: phase,
+			Min:   ms[0],
+			Mean:  sum / float64(len(ms)),
+			P50:   percentile(ms, 50),
+			P90:   percentile(ms, 90),
+			P99:   percentile(ms, 99),
+			Max:   ms[len(ms)-1],
+		})
+	}
+
+	return results
+}
+
+// percentile returns the p-th percentile of the sorted values,
+// using the nearest-rank method.
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+
+	return sorted[rank-1]
+}
+
+func writeBenchTable(w io.Writer, results []BenchPhaseResult) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "PHASE\tMIN(ms)\tMEAN(ms)\tP50(ms)\tP90(ms)\tP99(ms)\tMAX(ms)")
+	for _, r := range results {
+		fmt.Fprintf(tw, "%s\t%.1f\t%.1f\t%.1f\t%.1f\t%.1f\t%.1f\n", r.Phase, r.Min, r.Mean, r.P50, r.P90, r.P99, r.Max)
+	}
+
+	return tw.Flush()
+}
+
+func writeBenchJSON(w io.Writer, results []BenchPhaseResult) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(results)
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/vcmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPercentile(t *testing.T) {
+	assert := assert.New(t)
+
+	values := []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+
+	assert.Equal(float64(5), percentile(values, 50))
+	assert.Equal(float64(9), percentile(values, 90))
+	assert.Equal(float64(10), percentile(values, 99))
+	assert.Equal(float64(1), percentile(values, 0))
+	assert.Equal(float64(0), percentile(nil, 50))
+}
+
+func TestBenchResults(t *testing.T) {
+	assert := assert.New(t)
+
+	durations := map[string][]time.Duration{
+		benchPhaseCreate: {3 * time.Millisecond, 1 * time.Millisecond, 2 * time.Millisecond},
+	}
+
+	results := benchResults(durations)
+	assert.Len(results, 1)
+
+	r := results[0]
+	assert.Equal(benchPhaseCreate, r.Phase)
+	assert.Equal(float64(1), r.Min)
+	assert.Equal(float64(2), r.Mean)
+	assert.Equal(float64(2), r.P50)
+	assert.Equal(float64(3), r.Max)
+}
+
+func TestRunBench(t *testing.T) {
+	assert := assert.New(t)
+
+	tmpdir, err := ioutil.TempDir("", "")
+	assert.NoError(err)
+	defer os.RemoveAll(tmpdir)
+
+	runtimeConfig, err := newTestRuntimeConfig(tmpdir, testConsole, true)
+	assert.NoError(err)
+
+	var ids []string
+	testingImpl.CreateSandboxFunc = func(ctx context.Context, sandboxConfig vc.SandboxConfig) (vc.VCSandbox, error) {
+		ids = append(ids, sandboxConfig.ID)
+		return &vcmock.Sandbox{MockID: sandboxConfig.ID}, nil
+	}
+	defer func() {
+		testingImpl.CreateSandboxFunc = nil
+	}()
+
+	durations, err := runBench(context.Background(), runtimeConfig, 3)
+	assert.NoError(err)
+	assert.Len(ids, 3)
+
+	for _, phase := range benchPhases {
+		assert.Len(durations[phase], 3, phase)
+	}
+
+	testingImpl.CreateSandboxFunc = func(ctx context.Context, sandboxConfig vc.SandboxConfig) (vc.VCSandbox, error) {
+		return nil, errors.New("create failed")
+	}
+
+	_, err = runBench(context.Background(), runtimeConfig, 3)
+	assert.Error(err)
+}
+
+func TestWriteBenchResults(t *testing.T) {
+	assert := assert.New(t)
+
+	results := []BenchPhaseResult{
+		{Phase: benchPhaseStart, Min: 1, Mean: 2, P50: 2, P90: 3, P99: 3, Max: 3},
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(writeBenchTable(&buf, results))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(lines, 2)
+	assert.True(strings.HasPrefix(lines[1], benchPhaseStart))
+
+	buf.Reset()
+	assert.NoError(writeBenchJSON(&buf, results))
+	var decoded []BenchPhaseResult
+	assert.NoError(json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(results, decoded)
+}
@@ -0,0 +1,268 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package main
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/blang/semver"
+	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
+)
+
+// guestManifestPath is the path of the summary file osbuilder
+// creates in the rootfs of the guest images and initrds.
+const guestManifestPath = "var/lib/osbuilder/osbuilder.yaml"
+
+const (
+	cpioNewcMagic    = "070701"
+	cpioCRCMagic     = "070702"
+	cpioHeaderSize   = 110
+	cpioTrailer      = "TRAILER!!!"
+	mbrSize          = 512
+	mbrSignature     = 0xaa55
+	mbrPartitionBase = 446
+)
+
+var errNoGuestManifest = errors.New("no osbuilder manifest found")
+
+// guestAgentVersion returns the agent version of an osbuilder manifest,
+// the version key of its top level agent section.
+func guestAgentVersion(manifest []byte) string {
+	inAgent := false
+
+	for _, line := range strings.Split(string(manifest), "\n") {
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		// a top level key
+		if !strings.HasPrefix(line, " ") {
+			inAgent = strings.TrimSpace(line) == "agent:"
+			continue
+		}
+
+		fields := strings.SplitN(strings.TrimSpace(line), ":", 2)
+		if inAgent && len(fields) == 2 && fields[0] == "version" {
+			return strings.Trim(strings.TrimSpace(fields[1]), `"`)
+		}
+	}
+
+	return ""
+}
+
+// readInitrdManifest returns the osbuilder manifest of
+// an initrd, a plain or gzip compressed newc cpio archive.
+func readInitrdManifest(initrd string) ([]byte, error) {
+	f, err := os.Open(initrd)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+
+	magic, err := r.Peek(2)
+	if err != nil {
+		return nil, err
+	}
+
+	var archive io.Reader = r
+	if magic[0] == 0x1f && magic[1] == 0x8b {
+		z, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		defer z.Close()
+		archive = z
+	}
+
+	return readCpioFile(archive, guestManifestPath)
+}
+
+// readCpioFile returns the contents of a file of a newc cpio archive.
+func readCpioFile(r io.Reader, name string) ([]byte, error) {
+	header := make([]byte, cpioHeaderSize)
+
+	for {
+		if _, err := io.ReadFull(r, header); err != nil {
+			return nil, fmt.Errorf("invalid cpio archive: %v", err)
+		}
+
+		magic := string(header[:6])
+		if magic != cpioNewcMagic && magic != cpioCRCMagic {
+			return nil, fmt.Errorf("unsupported cpio archive format %q", magic)
+		}
+
+		// the fields are 8 digits hexadecimal numbers, after the magic
+		field := func(i int) (int64, error) {
+			return strconv.ParseInt(string(header[6+i*8:6+(i+1)*8]), 16, 64)
+		}
+
+		fileSize, err := field(6)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cpio file size: %v", err)
+		}
+		nameSize, err := field(11)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cpio name size: %v", err)
+		}
+
+		// the name and the data are padded to 4 bytes
+		entryName := make([]byte, (cpioHeaderSize+nameSize+3)&^3-cpioHeaderSize)
+		if _, err := io.ReadFull(r, entryName); err != nil {
+			return nil, fmt.Errorf("invalid cpio archive: %v", err)
+		}
+		path := strings.TrimRight(string(entryName[:nameSize]), "\x00")
+
+		if path == cpioTrailer {
+			return nil, errNoGuestManifest
+		}
+
+		data := make([]byte, (fileSize+3)&^3)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, fmt.Errorf("invalid cpio archive: %v", err)
+		}
+
+		if strings.TrimPrefix(filepath.Clean("/"+path), "/") == name {
+			return data[:fileSize], nil
+		}
+	}
+}
+
+// imageRootfsOffset returns the offset of the rootfs partition of an image,
+// the first partition of its MBR.
+func imageRootfsOffset(image string) (int64, error) {
+	f, err := os.Open(image)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	mbr := make([]byte, mbrSize)
+	if _, err := io.ReadFull(f, mbr); err != nil {
+		return 0, err
+	}
+
+	if binary.LittleEndian.Uint16(mbr[mbrSize-2:]) != mbrSignature {
+		return 0, fmt.Errorf("image %v has no partition table", image)
+	}
+
+	// the start LBA of the partition entry
+	lba := binary.LittleEndian.Uint32(mbr[mbrPartitionBase+8:])
+	if lba == 0 {
+		return 0, fmt.Errorf("image %v has no partition", image)
+	}
+
+	return int64(lba) * mbrSize, nil
+}
+
+// readImageManifest returns the osbuilder manifest of
+// an image, by mounting its rootfs read only.
+func readImageManifest(image string) ([]byte, error) {
+	offset, err := imageRootfsOffset(image)
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := ioutil.TempDir("", "kata-check-image")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	options := fmt.Sprintf("ro,loop,offset=%d", offset)
+	if out, err := exec.Command("mount", "-o", options, image, dir).CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("failed to mount image %v: %v: %s", image, err, strings.TrimSpace(string(out)))
+	}
+	defer func() {
+		if out, err := exec.Command("umount", dir).CombinedOutput(); err != nil {
+			kataLog.WithError(err).WithField("output", string(out)).Warn("failed to unmount guest image")
+		}
+	}()
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, guestManifestPath))
+	if os.IsNotExist(err) {
+		return nil, errNoGuestManifest
+	}
+
+	return data, err
+}
+
+// checkGuestManifest checks the guest agent, described by the osbuilder
+// manifest, matches the runtime: their major and minor versions must be
+// the same, the agent API is only compatible within a release.
+func checkGuestManifest(data []byte, runtimeVersion string) error {
+	agentVersion := guestAgentVersion(data)
+	if agentVersion == "" {
+		return errors.New("osbuilder manifest does not provide the agent version")
+	}
+
+	agent, err := semver.ParseTolerant(agentVersion)
+	if err != nil {
+		return fmt.Errorf("invalid guest agent version %q: %v", agentVersion, err)
+	}
+
+	runtime, err := semver.ParseTolerant(runtimeVersion)
+	if err != nil {
+		return fmt.Errorf("invalid runtime version %q: %v", runtimeVersion, err)
+	}
+
+	if agent.Major != runtime.Major || agent.Minor != runtime.Minor {
+		return fmt.Errorf("guest agent version %v does not match runtime version %v: rebuild the guest image or initrd with a matching agent",
+			agent, runtime)
+	}
+
+	return nil
+}
+
+// checkGuestAgentVersion checks the agent of the configured
+// guest image or initrd matches the runtime version.
+func checkGuestAgentVersion(config vc.HypervisorConfig, runtimeVersion string) error {
+	var (
+		data  []byte
+		err   error
+		asset string
+	)
+
+	switch {
+	case config.RootfsDiskPath != "":
+		kataLog.WithField("rootfs-disk", config.RootfsDiskPath).Info("not checking the agent version of the guest rootfs disk")
+		return nil
+	case config.InitrdPath != "":
+		asset = config.InitrdPath
+		data, err = readInitrdManifest(asset)
+	case config.ImagePath != "":
+		asset = config.ImagePath
+		data, err = readImageManifest(asset)
+	default:
+		return nil
+	}
+
+	if err == errNoGuestManifest {
+		kataLog.WithField("guest", asset).Warn("guest was not built by osbuilder, not checking its agent version")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read the osbuilder manifest of %v: %v", asset, err)
+	}
+
+	if err := checkGuestManifest(data, runtimeVersion); err != nil {
+		return fmt.Errorf("%v: %v", asset, err)
+	}
+
+	return nil
+}
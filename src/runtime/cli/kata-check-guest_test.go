@@ -0,0 +1,167 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
+	"github.com/stretchr/testify/assert"
+)
+
+func testGuestManifest(agentVersion string) []byte {
+	return []byte(fmt.Sprintf(`---
+osbuilder:
+  url: "https://github.com/kata-containers/kata-containers/tools/osbuilder"
+  version: "unknown"
+description: "osbuilder rootfs"
+file-format-version: "0.0.2"
+architecture: "x86_64"
+base-distro:
+  name: "Clear"
+  version: "34100"
+agent:
+  url: "https://github.com/kata-containers/kata-containers"
+  name: "kata-agent"
+  version: "%s"
+  agent-is-init-daemon: "no"
+`, agentVersion))
+}
+
+// writeCpioEntry appends a newc cpio entry to b.
+func writeCpioEntry(b *bytes.Buffer, name string, data []byte) {
+	fmt.Fprintf(b, "%s%08x%08x%08x%08x%08x%08x%08x%08x%08x%08x%08x%08x%08x",
+		cpioNewcMagic, 0, 0100644, 0, 0, 1, 0, len(data), 0, 0, 0, 0, len(name)+1, 0)
+	b.WriteString(name + "\x00")
+	for b.Len()%4 != 0 {
+		b.WriteByte(0)
+	}
+	b.Write(data)
+	for b.Len()%4 != 0 {
+		b.WriteByte(0)
+	}
+}
+
+func writeTestInitrd(t *testing.T, path string, compress bool, files map[string][]byte) {
+	var archive bytes.Buffer
+	for name, data := range files {
+		writeCpioEntry(&archive, name, data)
+	}
+	writeCpioEntry(&archive, cpioTrailer, nil)
+
+	data := archive.Bytes()
+	if compress {
+		var z bytes.Buffer
+		w := gzip.NewWriter(&z)
+		_, err := w.Write(data)
+		assert.NoError(t, err)
+		assert.NoError(t, w.Close())
+		data = z.Bytes()
+	}
+
+	assert.NoError(t, ioutil.WriteFile(path, data, 0600))
+}
+
+func TestReadInitrdManifest(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "kata-check-guest")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	manifest := testGuestManifest("2.2.0")
+	initrd := filepath.Join(dir, "initrd")
+
+	for _, compress := range []bool{true, false} {
+		writeTestInitrd(t, initrd, compress, map[string][]byte{
+			"./sbin/init":            []byte("#!/bin/sh\n"),
+			"./" + guestManifestPath: manifest,
+		})
+
+		data, err := readInitrdManifest(initrd)
+		assert.NoError(err)
+		assert.Equal(manifest, data)
+	}
+
+	// not built by osbuilder
+	writeTestInitrd(t, initrd, true, map[string][]byte{
+		"./sbin/init": []byte("#!/bin/sh\n"),
+	})
+	_, err = readInitrdManifest(initrd)
+	assert.Equal(errNoGuestManifest, err)
+
+	// not an initrd
+	assert.NoError(ioutil.WriteFile(initrd, []byte("not a cpio archive"), 0600))
+	_, err = readInitrdManifest(initrd)
+	assert.Error(err)
+}
+
+func TestImageRootfsOffset(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "kata-check-guest")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	image := filepath.Join(dir, "image")
+
+	mbr := make([]byte, mbrSize)
+	assert.NoError(ioutil.WriteFile(image, mbr, 0600))
+	_, err = imageRootfsOffset(image)
+	assert.Error(err)
+
+	binary.LittleEndian.PutUint16(mbr[mbrSize-2:], mbrSignature)
+	binary.LittleEndian.PutUint32(mbr[mbrPartitionBase+8:], 6144)
+	assert.NoError(ioutil.WriteFile(image, mbr, 0600))
+
+	offset, err := imageRootfsOffset(image)
+	assert.NoError(err)
+	assert.Equal(int64(6144*512), offset)
+}
+
+func TestCheckGuestManifest(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("2.2.0-alpha0", guestAgentVersion(testGuestManifest("2.2.0-alpha0")))
+
+	assert.NoError(checkGuestManifest(testGuestManifest("2.2.0-alpha0"), "2.2.0-alpha0"))
+	assert.NoError(checkGuestManifest(testGuestManifest("2.2.1"), "2.2.0"))
+	assert.Error(checkGuestManifest(testGuestManifest("2.1.0"), "2.2.0"))
+	assert.Error(checkGuestManifest(testGuestManifest("1.13.0"), "2.2.0"))
+	assert.Error(checkGuestManifest(testGuestManifest("foo"), "2.2.0"))
+	assert.Error(checkGuestManifest([]byte("description: \"osbuilder rootfs\"\n"), "2.2.0"))
+}
+
+func TestCheckGuestAgentVersion(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "kata-check-guest")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	initrd := filepath.Join(dir, "initrd")
+	config := vc.HypervisorConfig{InitrdPath: initrd}
+
+	writeTestInitrd(t, initrd, true, map[string][]byte{
+		"./" + guestManifestPath: testGuestManifest("2.1.0"),
+	})
+	assert.NoError(checkGuestAgentVersion(config, "2.1.1"))
+	assert.Error(checkGuestAgentVersion(config, "2.2.0"))
+
+	// guests not built by osbuilder are not checked
+	writeTestInitrd(t, initrd, true, nil)
+	assert.NoError(checkGuestAgentVersion(config, "2.2.0"))
+
+	// neither are the rootfs disks
+	assert.NoError(checkGuestAgentVersion(vc.HypervisorConfig{RootfsDiskPath: initrd}, "2.2.0"))
+}
@@ -64,6 +64,8 @@ const (
 	moduleParamDir        = "parameters"
 	successMessageCapable = "System is capable of running " + project
 	successMessageCreate  = "System can currently create " + project
+	successMessageSandbox = "System can launch the hypervisor in the VMM sandboxing profile"
+	successMessageGuest   = "Guest agent version matches the runtime version"
 	failMessage           = "System is not capable of running " + project
 	kernelPropertyCorrect = "Kernel property value correct"
 
@@ -447,6 +449,20 @@ EXAMPLES:
 			}
 
 			fmt.Println(successMessageCreate)
+
+			if runtimeConfig.HypervisorConfig.VMMSandboxing {
+				if err := vc.CheckVMMSandboxing(runtimeConfig.HypervisorConfig); err != nil {
+					return fmt.Errorf("cannot launch the hypervisor in the VMM sandboxing profile: %v", err)
+				}
+
+				fmt.Println(successMessageSandbox)
+			}
+
+			if err := checkGuestAgentVersion(runtimeConfig.HypervisorConfig, version); err != nil {
+				return err
+			}
+
+			fmt.Println(successMessageGuest)
 		}
 
 		return nil
@@ -0,0 +1,71 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package main
+
+import (
+	"encoding/json"
+
+	shim "github.com/kata-containers/kata-containers/src/runtime/containerd-shim-v2"
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/katautils"
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/shimclient"
+	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
+	"github.com/urfave/cli"
+)
+
+// CollectInfo is the information collected from the shim of a sandbox to
+// report an issue, the information which could not be collected being
+// replaced by its error.
+type CollectInfo struct {
+	SandboxID  string                   `json:"sandbox_id"`
+	Shim       *shim.VersionInfo        `json:"shim,omitempty"`
+	Status     *shim.SandboxStatus      `json:"status,omitempty"`
+	Hypervisor *vc.HypervisorInvocation `json:"hypervisor_invocation,omitempty"`
+	Errors     map[string]string        `json:"errors,omitempty"`
+}
+
+var kataCollectCLICommand = cli.Command{
+	Name:      "collect",
+	Usage:     "collect the information of a running sandbox to report an issue",
+	UsageText: "collect <sandbox id>",
+	Description: `collects from the shim of the sandbox its version, the status of the
+   sandbox and how its hypervisor was launched (command line and API calls), so
+   that the VM can be reproduced outside of the container manager.`,
+	Action: func(context *cli.Context) error {
+		sandboxID := context.Args().Get(0)
+
+		if err := katautils.VerifyContainerID(sandboxID); err != nil {
+			return err
+		}
+
+		client := shimclient.New(shimclient.DefaultAddress(sandboxID), defaultTimeout)
+		info := collectSandbox(client, sandboxID)
+
+		encoder := json.NewEncoder(defaultOutputFile)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(info)
+	},
+}
+
+// collectSandbox collects the information of a sandbox from its shim.
+func collectSandbox(client *shimclient.Client, sandboxID string) CollectInfo {
+	info := CollectInfo{
+		SandboxID: sandboxID,
+		Errors:    make(map[string]string),
+	}
+
+	var err error
+	if info.Shim, err = client.Version(); err != nil {
+		info.Errors["shim"] = err.Error()
+	}
+	if info.Status, err = client.Status(); err != nil {
+		info.Errors["status"] = err.Error()
+	}
+	if info.Hypervisor, err = client.HypervisorInvocation(); err != nil {
+		info.Errors["hypervisor_invocation"] = err.Error()
+	}
+
+	return info
+}
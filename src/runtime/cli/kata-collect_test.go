@@ -0,0 +1,57 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	shim "github.com/kata-containers/kata-containers/src/runtime/containerd-shim-v2"
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/shimclient"
+	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollectSandbox(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "kata-collect")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	m := http.NewServeMux()
+	m.HandleFunc("/version", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(shim.VersionInfo{Version: "2.2.0"})
+	})
+	m.HandleFunc("/status", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "sandbox not ready", http.StatusServiceUnavailable)
+	})
+	m.HandleFunc("/hypervisor-invocation", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(vc.HypervisorInvocation{Path: "/usr/bin/qemu-system-x86_64", Args: []string{"-name", "sandbox-foo"}})
+	})
+
+	listener, err := net.Listen("unix", filepath.Join(dir, "shim-monitor"))
+	assert.NoError(err)
+	srv := &http.Server{Handler: m}
+	go srv.Serve(listener)
+	defer srv.Close()
+
+	client := shimclient.New(shimclient.UnixSocketScheme+listener.Addr().String(), time.Second)
+	info := collectSandbox(client, "foo")
+
+	assert.Equal("foo", info.SandboxID)
+	assert.Equal("2.2.0", info.Shim.Version)
+	assert.Nil(info.Status)
+	assert.Equal("503 Service Unavailable: sandbox not ready", info.Errors["status"])
+	assert.Equal("/usr/bin/qemu-system-x86_64", info.Hypervisor.Path)
+	assert.Len(info.Errors, 1)
+}
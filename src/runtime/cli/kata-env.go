@@ -6,15 +6,21 @@
 package main
 
 import (
+	"crypto/sha256"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"os"
 	"runtime"
+	"sort"
 	"strings"
 
 	"github.com/BurntSushi/toml"
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/katautils"
 	"github.com/kata-containers/kata-containers/src/runtime/pkg/utils"
 	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
+	vcConfig "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/device/config"
 	exp "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/experimental"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/oci"
 	vcUtils "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/utils"
@@ -26,8 +32,13 @@ import (
 // Semantic version for the output of the command.
 //
 // XXX: Increment for every change to the output format
-// (meaning any change to the EnvInfo type).
-const formatVersion = "1.0.25"
+// (meaning any change to the EnvInfo type): the minor version
+// for the additions, the major version for the other changes,
+// so the consumers of the JSON output can check they can read it.
+const formatVersion = "1.2.0"
+
+// the algorithm of the digests of the guest assets
+const digestAlgorithm = "sha256"
 
 // MetaInfo stores information on the format of the output itself
 type MetaInfo struct {
@@ -39,16 +50,19 @@ type MetaInfo struct {
 type KernelInfo struct {
 	Path       string
 	Parameters string
+	Digest     string
 }
 
 // InitrdInfo stores initrd image details
 type InitrdInfo struct {
-	Path string
+	Path   string
+	Digest string
 }
 
 // ImageInfo stores root filesystem image details
 type ImageInfo struct {
-	Path string
+	Path   string
+	Digest string
 }
 
 // CPUInfo stores host CPU details
@@ -67,7 +81,12 @@ type MemoryInfo struct {
 
 // RuntimeConfigInfo stores runtime config details.
 type RuntimeConfigInfo struct {
-	Path string
+	Path   string
+	Digest string
+
+	// Overridden are the default config files found but not loaded,
+	// their settings being replaced by the ones of Path.
+	Overridden []string
 }
 
 // RuntimeInfo stores runtime details.
@@ -80,6 +99,7 @@ type RuntimeInfo struct {
 	DisableNewNetNs     bool
 	SandboxCgroupOnly   bool
 	Experimental        []exp.Feature
+	FeatureGates        map[string]bool
 	Path                string
 }
 
@@ -119,6 +139,10 @@ type AgentInfo struct {
 	Trace     bool
 	TraceMode string
 	TraceType string
+
+	// Version is the agent version of the osbuilder manifest of the
+	// guest, empty when it cannot be read.
+	Version string
 }
 
 // DistroInfo stores host operating system distribution details.
@@ -160,6 +184,10 @@ type EnvInfo struct {
 	Agent      AgentInfo
 	Host       HostInfo
 	Netmon     NetmonInfo
+
+	// Features are the features available to the sandboxes
+	// with the host and the configuration, sorted.
+	Features []string
 }
 
 func getMetaInfo() MetaInfo {
@@ -178,7 +206,9 @@ func getRuntimeInfo(configFile string, config oci.RuntimeConfig) RuntimeInfo {
 	}
 
 	runtimeConfig := RuntimeConfigInfo{
-		Path: configFile,
+		Path:       configFile,
+		Digest:     fileDigest(configFile),
+		Overridden: getOverriddenConfigFiles(configFile),
 	}
 
 	runtimePath, _ := os.Executable()
@@ -192,10 +222,48 @@ func getRuntimeInfo(configFile string, config oci.RuntimeConfig) RuntimeInfo {
 		DisableNewNetNs:     config.DisableNewNetNs,
 		SandboxCgroupOnly:   config.SandboxCgroupOnly,
 		Experimental:        config.Experimental,
+		FeatureGates:        config.FeatureGates,
 		DisableGuestSeccomp: config.DisableGuestSeccomp,
 	}
 }
 
+// getOverriddenConfigFiles returns the default config files found
+// which are not the loaded config file.
+func getOverriddenConfigFiles(configFile string) []string {
+	var files []string
+
+	for _, file := range katautils.GetDefaultConfigFilePaths() {
+		resolved, err := katautils.ResolvePath(file)
+		if err != nil || resolved == configFile {
+			continue
+		}
+		files = append(files, resolved)
+	}
+
+	return files
+}
+
+// fileDigest returns the digest of a file in the "<algorithm>:<hex>"
+// format of the OCI digests, empty when the file cannot be read.
+func fileDigest(path string) string {
+	if path == "" {
+		return ""
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%s:%x", digestAlgorithm, h.Sum(nil))
+}
+
 func getHostInfo() (HostInfo, error) {
 	hostKernelVersion, err := getKernelVersion()
 	if err != nil {
@@ -303,10 +371,71 @@ func getAgentInfo(config oci.RuntimeConfig) (AgentInfo, error) {
 	agent.Trace = agentConfig.Trace
 	agent.TraceMode = agentConfig.TraceMode
 	agent.TraceType = agentConfig.TraceType
+	agent.Version = getGuestAgentVersion(config.HypervisorConfig)
 
 	return agent, nil
 }
 
+// getGuestAgentVersion returns the agent version of the osbuilder
+// manifest of the guest initrd or image, empty when it cannot be read.
+func getGuestAgentVersion(config vc.HypervisorConfig) string {
+	var (
+		data []byte
+		err  error
+	)
+
+	switch {
+	case config.RootfsDiskPath != "":
+		return ""
+	case config.InitrdPath != "":
+		data, err = readInitrdManifest(config.InitrdPath)
+	case config.ImagePath != "":
+		data, err = readImageManifest(config.ImagePath)
+	default:
+		return ""
+	}
+
+	if err != nil {
+		kataLog.WithError(err).Debug("cannot read the osbuilder manifest of the guest")
+		return ""
+	}
+
+	return guestAgentVersion(data)
+}
+
+// getFeatures returns the features available to the sandboxes.
+func getFeatures(config oci.RuntimeConfig, host HostInfo) []string {
+	hypervisorConfig := config.HypervisorConfig
+
+	features := []string{}
+	for feature, enabled := range map[string]bool{
+		"admission-check":     config.AdmissionConfig.Mode != "",
+		"audit-log":           config.AuditConfig.Enable,
+		"block-devices":       !hypervisorConfig.DisableBlockDeviceUse,
+		"confidential-guest":  hypervisorConfig.ConfidentialGuest,
+		"device-reuse":        config.DeviceReuseTimeout > 0,
+		"ephemeral-disk":      config.EphemeralDiskConfig.Backend != "",
+		"guest-numa":          len(hypervisorConfig.NUMANodes) > 0,
+		"guest-swap":          config.GuestSwapConfig.SizeMB > 0,
+		"initdata":            config.InitDataConfig.Method != "",
+		"rootfs-disk":         hypervisorConfig.RootfsDiskPath != "",
+		"rootfs-dedup":        config.RootfsDedup,
+		"sandbox-cgroup-only": config.SandboxCgroupOnly,
+		"shared-pidns":        config.SharePidNs,
+		"tracing":             config.Trace,
+		"virtio-fs":           hypervisorConfig.SharedFS == vcConfig.VirtioFS,
+		"vsock":               host.SupportVSocks,
+	} {
+		if enabled {
+			features = append(features, feature)
+		}
+	}
+
+	sort.Strings(features)
+
+	return features
+}
+
 func getHypervisorInfo(config oci.RuntimeConfig) HypervisorInfo {
 	hypervisorPath := config.HypervisorConfig.HypervisorPath
 
@@ -357,16 +486,19 @@ func getEnvInfo(configFile string, config oci.RuntimeConfig) (env EnvInfo, err e
 	hypervisor := getHypervisorInfo(config)
 
 	image := ImageInfo{
-		Path: config.HypervisorConfig.ImagePath,
+		Path:   config.HypervisorConfig.ImagePath,
+		Digest: fileDigest(config.HypervisorConfig.ImagePath),
 	}
 
 	kernel := KernelInfo{
 		Path:       config.HypervisorConfig.KernelPath,
 		Parameters: strings.Join(vc.SerializeParams(config.HypervisorConfig.KernelParams, "="), " "),
+		Digest:     fileDigest(config.HypervisorConfig.KernelPath),
 	}
 
 	initrd := InitrdInfo{
-		Path: config.HypervisorConfig.InitrdPath,
+		Path:   config.HypervisorConfig.InitrdPath,
+		Digest: fileDigest(config.HypervisorConfig.InitrdPath),
 	}
 
 	env = EnvInfo{
@@ -379,6 +511,7 @@ func getEnvInfo(configFile string, config oci.RuntimeConfig) (env EnvInfo, err e
 		Agent:      agent,
 		Host:       host,
 		Netmon:     netmon,
+		Features:   getFeatures(config, host),
 	}
 
 	return env, nil
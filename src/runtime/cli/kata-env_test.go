@@ -300,7 +300,8 @@ func getExpectedHypervisor(config oci.RuntimeConfig) HypervisorInfo {
 
 func getExpectedImage(config oci.RuntimeConfig) ImageInfo {
 	return ImageInfo{
-		Path: config.HypervisorConfig.ImagePath,
+		Path:   config.HypervisorConfig.ImagePath,
+		Digest: fileDigest(config.HypervisorConfig.ImagePath),
 	}
 }
 
@@ -308,6 +309,7 @@ func getExpectedKernel(config oci.RuntimeConfig) KernelInfo {
 	return KernelInfo{
 		Path:       config.HypervisorConfig.KernelPath,
 		Parameters: strings.Join(vc.SerializeParams(config.HypervisorConfig.KernelParams, "="), " "),
+		Digest:     fileDigest(config.HypervisorConfig.KernelPath),
 	}
 }
 
@@ -322,12 +324,15 @@ func getExpectedRuntimeDetails(config oci.RuntimeConfig, configFile string) Runt
 			OCI:     specs.Version,
 		},
 		Config: RuntimeConfigInfo{
-			Path: configFile,
+			Path:       configFile,
+			Digest:     fileDigest(configFile),
+			Overridden: getOverriddenConfigFiles(configFile),
 		},
 		Path:            runtimePath,
 		Debug:           config.Debug,
 		Trace:           config.Trace,
 		DisableNewNetNs: config.DisableNewNetNs,
+		FeatureGates:    config.FeatureGates,
 	}
 }
 
@@ -364,6 +369,7 @@ func getExpectedSettings(config oci.RuntimeConfig, tmpdir, configFile string) (E
 		Agent:      agent,
 		Host:       host,
 		Netmon:     netmon,
+		Features:   getFeatures(config, host),
 	}
 
 	return env, nil
@@ -1024,3 +1030,81 @@ func TestGetHypervisorInfo(t *testing.T) {
 	info = getHypervisorInfo(config)
 	assert.Equal(info.Version, unknown)
 }
+
+func TestFileDigest(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "image")
+	assert.NoError(ioutil.WriteFile(file, []byte("foo"), testFileMode))
+
+	assert.Equal("sha256:2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae", fileDigest(file))
+	assert.Empty(fileDigest(""))
+	assert.Empty(fileDigest(filepath.Join(dir, "missing")))
+}
+
+func TestGetOverriddenConfigFiles(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	sysConfig := filepath.Join(dir, "etc.toml")
+	defaultConfig := filepath.Join(dir, "share.toml")
+
+	savedDefault := katautils.GetDefaultConfigFilePaths()
+	defer katautils.SetConfigOptions("", savedDefault[1], savedDefault[0])
+	katautils.SetConfigOptions("", defaultConfig, sysConfig)
+
+	for _, file := range []string{sysConfig, defaultConfig} {
+		assert.NoError(ioutil.WriteFile(file, []byte(""), testFileMode))
+	}
+
+	// the system config replaces the default one
+	assert.Equal([]string{defaultConfig}, getOverriddenConfigFiles(sysConfig))
+	assert.Equal([]string{sysConfig, defaultConfig}, getOverriddenConfigFiles(filepath.Join(dir, "custom.toml")))
+
+	assert.NoError(os.Remove(sysConfig))
+	assert.Empty(getOverriddenConfigFiles(defaultConfig))
+}
+
+func TestGetGuestAgentVersion(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	initrd := filepath.Join(dir, "initrd")
+	writeTestInitrd(t, initrd, true, map[string][]byte{
+		guestManifestPath: testGuestManifest("2.2.0"),
+	})
+
+	assert.Equal("2.2.0", getGuestAgentVersion(vc.HypervisorConfig{InitrdPath: initrd}))
+	assert.Empty(getGuestAgentVersion(vc.HypervisorConfig{RootfsDiskPath: initrd}))
+	assert.Empty(getGuestAgentVersion(vc.HypervisorConfig{InitrdPath: filepath.Join(dir, "missing")}))
+	assert.Empty(getGuestAgentVersion(vc.HypervisorConfig{}))
+}
+
+func TestGetFeatures(t *testing.T) {
+	assert := assert.New(t)
+
+	config := oci.RuntimeConfig{
+		HypervisorConfig: vc.HypervisorConfig{
+			DisableBlockDeviceUse: true,
+			SharedFS:              "virtio-9p",
+		},
+	}
+	assert.Equal([]string{}, getFeatures(config, HostInfo{}))
+
+	config.HypervisorConfig.DisableBlockDeviceUse = false
+	config.HypervisorConfig.SharedFS = "virtio-fs"
+	config.SandboxCgroupOnly = true
+	config.AuditConfig.Enable = true
+	assert.Equal([]string{"audit-log", "block-devices", "sandbox-cgroup-only", "virtio-fs", "vsock"},
+		getFeatures(config, HostInfo{SupportVSocks: true}))
+}
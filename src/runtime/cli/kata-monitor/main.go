@@ -7,20 +7,64 @@ package main
 
 import (
 	"flag"
+	"fmt"
 	"net/http"
 	"os"
 	"runtime"
+	"strings"
 	"text/template"
 	"time"
 
 	kataMonitor "github.com/kata-containers/kata-containers/src/runtime/pkg/kata-monitor"
+	mutils "github.com/kata-containers/kata-containers/src/runtime/pkg/utils"
 	"github.com/sirupsen/logrus"
 )
 
-var monitorListenAddr = flag.String("listen-address", ":8090", "The address to listen on for HTTP requests.")
+var monitorListenAddr = flag.String("listen-address", ":8090", "The address to listen on for HTTP requests, none if empty.")
+var tokenFile = flag.String("token-file", "", "File of the bearer token required by the requests on -listen-address.")
+var listenSocket = flag.String("listen-socket", "", "Unix socket to listen on for HTTP requests as well, e.g. for the agents of the node.")
+var listenSocketMode = flag.Uint("listen-socket-mode", 0660, "Permissions of the -listen-socket unix socket.")
+var shimTokenFile = flag.String("shim-token-file", "", "File of the management token of the shims, the management_token_file of the runtime configuration, required by the attach.")
+var listenSocketTokenFile = flag.String("listen-socket-token-file", "", "File of the bearer token required by the requests on -listen-socket.")
 var containerdAddr = flag.String("containerd-address", "/run/containerd/containerd.sock", "Containerd address to accept client requests.")
 var containerdConfig = flag.String("containerd-conf", "/etc/containerd/config.toml", "Containerd config file.")
+var containerdNamespaces = flag.String("containerd-namespaces", "", "Comma separated list of the containerd namespaces of the monitored sandboxes, e.g. k8s.io, all of them if empty.")
+var containerdNamespacesExclude = flag.String("containerd-namespaces-exclude", "", "Comma separated list of the containerd namespaces whose sandboxes are not monitored.")
+var rateLimit = flag.Float64("rate-limit", 20, "Requests per second allowed to each client, except on /metrics, none if 0.")
+var rateLimitBurst = flag.Int("rate-limit-burst", 40, "Requests allowed at once to each client, with -rate-limit.")
 var logLevel = flag.String("log-level", "info", "Log level of logrus(trace/debug/info/warn/error/fatal/panic).")
+var podLabels = flag.String("metrics-pod-labels", "", "Comma separated list of pod labels added to sandbox metrics as label_<name>.")
+var podAnnotations = flag.String("metrics-pod-annotations", "", "Comma separated list of pod annotations added to sandbox metrics as annotation_<name>.")
+var kubernetes = flag.Bool("kubernetes", false, "Resolve the pods of the sandboxes with the Kubernetes API, using the in-cluster configuration unless -kube-apiserver is set.")
+var kubeAPIServer = flag.String("kube-apiserver", "", "Kubernetes API server URL.")
+var kubeTokenFile = flag.String("kube-token-file", "", "File of the bearer token used to authenticate to the Kubernetes API server.")
+var kubeCAFile = flag.String("kube-ca-file", "", "File of the CA certificates of the Kubernetes API server.")
+var sandboxTokens = flag.Bool("sandbox-tokens", false, "Grant the pods access to the metrics, stats and top of their own sandbox with their service account token, reviewed with the Kubernetes API.")
+var sandboxTokenAudience = flag.String("sandbox-token-audience", kataMonitor.DefaultSandboxTokenAudience, "Audience of the service account tokens of -sandbox-tokens.")
+var customMetrics = flag.Bool("custom-metrics", false, "Serve the VM overhead and boot duration of the pods with the Kubernetes custom metrics API.")
+var tlsCertFile = flag.String("tls-cert-file", "", "File of the TLS certificate, kata-monitor serves HTTPS when set.")
+var tlsKeyFile = flag.String("tls-key-file", "", "File of the TLS private key.")
+var requestheaderClientCAFile = flag.String("requestheader-client-ca-file", "", "File of the CA certificates of the client certificates granted the custom metrics API on -listen-address, e.g. the requestheader client CA of the API aggregation layer.")
+var requestheaderAllowedNames = flag.String("requestheader-allowed-names", "", "Comma separated list of the common names allowed for the client certificates of -requestheader-client-ca-file, any of them if empty.")
+var problemDetector = flag.Bool("problem-detector", false, "Check the sandboxes for problems, served at /problems for the Node Problem Detector.")
+var problemCheckInterval = flag.Duration("problem-check-interval", 30*time.Second, "Interval of the problem checks.")
+var usageHistory = flag.Bool("usage-history", false, "Keep a history of the CPU and memory usage of the sandboxes, served at /sandboxes/<id>/top.")
+var usageHistoryInterval = flag.Duration("usage-history-interval", 10*time.Second, "Interval of the usage history samples.")
+var usageHistoryDuration = flag.Duration("usage-history-duration", 5*time.Minute, "Duration of the usage history kept for each sandbox.")
+var selfTest = flag.Bool("self-test", false, "Launch a Kata sandbox periodically to check the runtime and its configuration on the node, exporting the results as metrics.")
+var selfTestImage = flag.String("self-test-image", "k8s.gcr.io/pause:3.5", "Image of the self test sandbox, pulled if not present.")
+var selfTestRuntime = flag.String("self-test-runtime", "io.containerd.kata.v2", "Containerd runtime of the self test sandbox.")
+var selfTestConfig = flag.String("self-test-config", "", "Runtime configuration file of the self test sandbox, the default one is used if empty.")
+var selfTestInterval = flag.Duration("self-test-interval", 10*time.Minute, "Interval of the self tests.")
+var selfTestTimeout = flag.Duration("self-test-timeout", time.Minute, "Timeout of the self tests.")
+var preflight = flag.Bool("preflight", false, "Run the kata-runtime checks periodically, labeling the node and setting its "+kataMonitor.PreflightCondition+" condition with the results through the Kubernetes API.")
+var preflightRuntime = flag.String("preflight-runtime", "kata-runtime", "kata-runtime binary running the preflight checks.")
+var preflightConfig = flag.String("preflight-config", "", "Runtime configuration file of the preflight checks, the default one is used if empty.")
+var preflightInterval = flag.Duration("preflight-interval", 5*time.Minute, "Interval of the preflight checks.")
+var preflightTimeout = flag.Duration("preflight-timeout", time.Minute, "Timeout of the preflight checks.")
+var nodeName = flag.String("node-name", os.Getenv("NODE_NAME"), "Name of the node labeled by the preflight checks, $NODE_NAME by default.")
+var npdCheck = flag.String("npd-check", "", "Run as a Node Problem Detector custom plugin checking the problem type, e.g. KataAgentUnresponsive.")
+var npdURL = flag.String("npd-url", "http://127.0.0.1:8090", "URL of the kata-monitor serving the problems, with -npd-check.")
 
 // These values are overridden via ldflags
 var (
@@ -70,8 +114,19 @@ func main() {
 		return
 	}
 
+	if len(os.Args) > 1 && os.Args[1] == "drain-shims" {
+		os.Exit(drainShims(os.Args[2:]))
+	}
+
 	flag.Parse()
 
+	// the Node Problem Detector plugin output is its exit code and message
+	if *npdCheck != "" {
+		code, message := kataMonitor.CheckProblem(*npdURL, *npdCheck)
+		fmt.Println(message)
+		os.Exit(code)
+	}
+
 	// init logrus
 	initLog()
 
@@ -85,40 +140,230 @@ func main() {
 		"git-commit": ver.GitCommit,
 
 		// properties from command-line options
-		"listen-address":     *monitorListenAddr,
-		"containerd-address": *containerdAddr,
-		"containerd-conf":    *containerdConfig,
-		"log-level":          *logLevel,
+		"listen-address":          *monitorListenAddr,
+		"listen-socket":           *listenSocket,
+		"containerd-address":      *containerdAddr,
+		"containerd-conf":         *containerdConfig,
+		"containerd-namespaces":   *containerdNamespaces,
+		"log-level":               *logLevel,
+		"metrics-pod-labels":      *podLabels,
+		"metrics-pod-annotations": *podAnnotations,
+		"kubernetes":              *kubernetes,
+		"kube-apiserver":          *kubeAPIServer,
+		"sandbox-tokens":          *sandboxTokens,
+		"custom-metrics":          *customMetrics,
+		"tls-cert-file":           *tlsCertFile,
+		"requestheader-client-ca": *requestheaderClientCAFile,
+		"problem-detector":        *problemDetector,
+		"usage-history":           *usageHistory,
+		"self-test":               *selfTest,
+		"preflight":               *preflight,
+		"node-name":               *nodeName,
 	}
 
 	logrus.WithFields(announceFields).Info("announce")
 
 	// create new kataMonitor
-	km, err := kataMonitor.NewKataMonitor(*containerdAddr, *containerdConfig)
+	podMetadata := kataMonitor.PodMetadataFilter{
+		Labels:      splitList(*podLabels),
+		Annotations: splitList(*podAnnotations),
+	}
+
+	namespaces := kataMonitor.NamespaceFilter{
+		Allow: splitList(*containerdNamespaces),
+		Deny:  splitList(*containerdNamespacesExclude),
+	}
+
+	var kube *kataMonitor.KubeClient
+	if *kubernetes {
+		var err error
+		if kube, err = kataMonitor.NewKubeClient(*kubeAPIServer, *kubeTokenFile, *kubeCAFile); err != nil {
+			panic(err)
+		}
+	}
+
+	km, err := kataMonitor.NewKataMonitor(*containerdAddr, *containerdConfig, podMetadata, namespaces, kube)
 	if err != nil {
 		panic(err)
 	}
+	km.SetShimTokenFile(*shimTokenFile)
 
 	// setup handlers, now only metrics is supported
 	m := http.NewServeMux()
-	m.Handle("/metrics", http.HandlerFunc(km.ProcessMetricsRequest))
-	m.Handle("/sandboxes", http.HandlerFunc(km.ListSandboxes))
-	m.Handle("/agent-url", http.HandlerFunc(km.GetAgentURL))
+	var limiter *kataMonitor.RateLimiter
+	if *rateLimit > 0 {
+		limiter = kataMonitor.NewRateLimiter(*rateLimit, *rateLimitBurst)
+	}
+	// the requests served by each handler are recorded in metrics, the
+	// scrapes are not rate limited for Prometheus not to miss them
+	handle := func(pattern string, handler http.Handler) {
+		if limiter != nil && pattern != "/metrics" {
+			handler = limiter.Handler(handler)
+		}
+		m.Handle(pattern, kataMonitor.InstrumentHandler(pattern, handler))
+	}
+	handle("/metrics", http.HandlerFunc(km.ProcessMetricsRequest))
+	handle("/sandboxes", http.HandlerFunc(km.ListSandboxes))
+	handle("/sandboxes/", http.HandlerFunc(km.ServeSandbox))
+	handle("/agent-url", http.HandlerFunc(km.GetAgentURL))
+	handle("/loglevel", mutils.NewLogLevelHandler(logrus.StandardLogger()))
+	handle("/problems", http.HandlerFunc(km.ListProblems))
+	handle("/shims", http.HandlerFunc(km.ListShims))
+	handle("/scrape-status", http.HandlerFunc(km.ScrapeStatus))
+
+	if *problemDetector {
+		km.StartProblemDetector(*problemCheckInterval)
+	}
+
+	if *usageHistory {
+		km.StartUsageHistory(*usageHistoryInterval, *usageHistoryDuration)
+	}
+
+	if *selfTest {
+		km.StartSelfTest(kataMonitor.SelfTestConfig{
+			Image:      *selfTestImage,
+			Runtime:    *selfTestRuntime,
+			ConfigPath: *selfTestConfig,
+			Interval:   *selfTestInterval,
+			Timeout:    *selfTestTimeout,
+		})
+	}
+
+	if *preflight {
+		// the node is labeled even if the pods are not resolved
+		preflightKube := kube
+		if preflightKube == nil {
+			if preflightKube, err = kataMonitor.NewKubeClient(*kubeAPIServer, *kubeTokenFile, *kubeCAFile); err != nil {
+				panic(err)
+			}
+		}
+
+		if err := kataMonitor.StartPreflight(preflightKube, kataMonitor.PreflightConfig{
+			Runtime:    *preflightRuntime,
+			ConfigPath: *preflightConfig,
+			Node:       *nodeName,
+			Interval:   *preflightInterval,
+			Timeout:    *preflightTimeout,
+		}); err != nil {
+			panic(err)
+		}
+	}
+
+	if *customMetrics {
+		handle(kataMonitor.CustomMetricsAPIPrefix, http.HandlerFunc(km.CustomMetrics))
+		handle(kataMonitor.CustomMetricsAPIPrefix+"/", http.HandlerFunc(km.CustomMetrics))
+	}
 
 	// for debug shim process
-	m.Handle("/debug/vars", http.HandlerFunc(km.ExpvarHandler))
-	m.Handle("/debug/pprof/", http.HandlerFunc(km.PprofIndex))
-	m.Handle("/debug/pprof/cmdline", http.HandlerFunc(km.PprofCmdline))
-	m.Handle("/debug/pprof/profile", http.HandlerFunc(km.PprofProfile))
-	m.Handle("/debug/pprof/symbol", http.HandlerFunc(km.PprofSymbol))
-	m.Handle("/debug/pprof/trace", http.HandlerFunc(km.PprofTrace))
-
-	// listening on the server
-	svr := &http.Server{
-		Handler: m,
-		Addr:    *monitorListenAddr,
-	}
-	logrus.Fatal(svr.ListenAndServe())
+	handle("/debug/vars", http.HandlerFunc(km.ExpvarHandler))
+	handle("/debug/pprof/", http.HandlerFunc(km.PprofIndex))
+	handle("/debug/pprof/cmdline", http.HandlerFunc(km.PprofCmdline))
+	handle("/debug/pprof/profile", http.HandlerFunc(km.PprofProfile))
+	handle("/debug/pprof/symbol", http.HandlerFunc(km.PprofSymbol))
+	handle("/debug/pprof/trace", http.HandlerFunc(km.PprofTrace))
+
+	// the pods can read their own sandbox with their service account
+	// token, reviewed with the Kubernetes API
+	var sandboxes *kataMonitor.SandboxAuthorizer
+	if *sandboxTokens {
+		tokenKube := kube
+		if tokenKube == nil {
+			if tokenKube, err = kataMonitor.NewKubeClient(*kubeAPIServer, *kubeTokenFile, *kubeCAFile); err != nil {
+				panic(err)
+			}
+		}
+		if sandboxes, err = km.NewSandboxAuthorizer(tokenKube, *sandboxTokenAudience); err != nil {
+			panic(err)
+		}
+	}
+
+	// listening on the TCP address and the unix socket, each with its
+	// own authentication
+	var listeners []kataMonitor.ListenerConfig
+	if *monitorListenAddr != "" {
+		listeners = append(listeners, kataMonitor.ListenerConfig{
+			Address:      *monitorListenAddr,
+			TLSCertFile:  *tlsCertFile,
+			TLSKeyFile:   *tlsKeyFile,
+			TokenFile:    *tokenFile,
+			ClientCAFile: *requestheaderClientCAFile,
+			ClientNames:  splitList(*requestheaderAllowedNames),
+			Sandboxes:    sandboxes,
+		})
+	}
+	if *monitorListenAddr != "" && *tokenFile == "" {
+		logrus.WithField("listen-address", *monitorListenAddr).
+			Warn("no -token-file, the debug attach is not served on the listen address")
+	}
+	if *customMetrics && *requestheaderClientCAFile == "" {
+		logrus.WithField("listen-address", *monitorListenAddr).
+			Warn("no -requestheader-client-ca-file, the custom metrics API is only served to the authenticated clients")
+	}
+	if *listenSocket != "" {
+		listeners = append(listeners, kataMonitor.ListenerConfig{
+			Address:    "unix://" + *listenSocket,
+			TokenFile:  *listenSocketTokenFile,
+			SocketMode: os.FileMode(*listenSocketMode),
+			Sandboxes:  sandboxes,
+		})
+	}
+	logrus.Fatal(kataMonitor.Serve(m, listeners...))
+}
+
+// drainShims runs the drain-shims command, which finds the sandboxes of the
+// shims older than a version on the node for the runtime upgrades. It exits
+// with 1 while outdated shims are left, 2 on errors.
+func drainShims(args []string) int {
+	flags := flag.NewFlagSet("drain-shims", flag.ExitOnError)
+	versionOlderThan := flags.String("version-older-than", "", "Version of the runtime, the shims older than it are outdated.")
+	url := flags.String("url", "http://127.0.0.1:8090", "URL of the kata-monitor of the node.")
+	node := flags.String("node", "", "Name of the node, tainted with "+kataMonitor.ShimUpgradeTaint+" while outdated shims are left.")
+	evict := flags.Bool("evict", false, "Evict the pods of the outdated shims, respecting their disruption budgets.")
+	dryRun := flags.Bool("dry-run", false, "Print the changes without tainting the node nor evicting the pods.")
+	apiServer := flags.String("kube-apiserver", "", "Kubernetes API server URL, the in-cluster configuration is used when not set.")
+	tokenFile := flags.String("kube-token-file", "", "File of the bearer token used to authenticate to the Kubernetes API server.")
+	caFile := flags.String("kube-ca-file", "", "File of the CA certificates of the Kubernetes API server.")
+	flags.Parse(args)
+
+	var kube *kataMonitor.KubeClient
+	if *node != "" || *evict {
+		var err error
+		if kube, err = kataMonitor.NewKubeClient(*apiServer, *tokenFile, *caFile); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+	}
+
+	outdated, err := kataMonitor.DrainShims(kube, kataMonitor.DrainOptions{
+		MonitorURL:       *url,
+		VersionOlderThan: *versionOlderThan,
+		Node:             *node,
+		Evict:            *evict,
+		DryRun:           *dryRun,
+	}, os.Stdout)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	if outdated > 0 {
+		fmt.Printf("%d outdated shims left\n", outdated)
+		return 1
+	}
+
+	fmt.Printf("no shim older than %s\n", *versionOlderThan)
+	return 0
+}
+
+// splitList splits a comma separated list, ignoring empty items.
+func splitList(list string) []string {
+	var items []string
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
 }
 
 // initLog setup logger
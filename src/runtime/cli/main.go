@@ -25,6 +25,7 @@ import (
 	tl "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/factory/template"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/oci"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/rootless"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/vmmsandbox"
 	specs "github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli"
@@ -117,6 +118,7 @@ var runtimeFlags = []cli.Flag{
 // runtimeCommands is the list of supported command-line (sub-)
 // commands.
 var runtimeCommands = []cli.Command{
+	createCLICommand,
 	versionCLICommand,
 
 	// Kata Containers specific extensions
@@ -124,6 +126,8 @@ var runtimeCommands = []cli.Command{
 	kataEnvCLICommand,
 	kataExecCLICommand,
 	kataMetricsCLICommand,
+	kataCollectCLICommand,
+	kataBenchCLICommand,
 	factoryCLICommand,
 }
 
@@ -492,6 +496,9 @@ func cliContextToContext(c *cli.Context) (context.Context, error) {
 }
 
 func main() {
+	// never returns when executed as the hypervisor launcher
+	vmmsandbox.Init()
+
 	// create a new empty context
 	ctx := context.Background()
 	createRuntime(ctx)
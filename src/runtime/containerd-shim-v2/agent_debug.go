@@ -0,0 +1,100 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package containerdshim
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
+)
+
+// debugConsole is the state of the debug console of the guest.
+type debugConsole struct {
+	Enabled bool `json:"enabled"`
+}
+
+// agentLogLevel serves the log level of the agent, and changes it on PUT
+// from the level query parameter, the same way as /loglevel does for the
+// shim.
+func (s *service) agentLogLevel(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		if status, err := s.authorizeManagement(r); err != nil {
+			http.Error(w, err.Error(), status)
+			return
+		}
+	default:
+		w.Header().Set("Allow", http.MethodGet+", "+http.MethodPut)
+		http.Error(w, "only GET and PUT are supported", http.StatusMethodNotAllowed)
+		return
+	}
+	if s.sandbox == nil {
+		http.Error(w, "sandbox is not running", http.StatusServiceUnavailable)
+		return
+	}
+
+	if r.Method == http.MethodPut {
+		level := r.URL.Query().Get("level")
+		if !vc.ValidAgentLogLevel(level) {
+			http.Error(w, fmt.Sprintf("invalid agent log level %q", level), http.StatusBadRequest)
+			return
+		}
+
+		s.mu.Lock()
+		err := s.sandbox.SetAgentLogLevel(s.ctx, level)
+		s.mu.Unlock()
+		if err != nil {
+			shimMgtLog.WithError(err).Error("failed to set the agent log level")
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		shimMgtLog.WithField("level", level).Info("agent log level set")
+	}
+
+	fmt.Fprintln(w, s.sandbox.GetAgentLogLevel())
+}
+
+// debugConsole serves the state of the debug console of the guest, starts
+// it on PUT and stops it on DELETE, for kata-runtime exec to connect to a
+// running sandbox booted without it.
+func (s *service) debugConsole(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut, http.MethodDelete:
+		if status, err := s.authorizeManagement(r); err != nil {
+			http.Error(w, err.Error(), status)
+			return
+		}
+	default:
+		w.Header().Set("Allow", http.MethodGet+", "+http.MethodPut+", "+http.MethodDelete)
+		http.Error(w, "only GET, PUT and DELETE are supported", http.StatusMethodNotAllowed)
+		return
+	}
+	if s.sandbox == nil {
+		http.Error(w, "sandbox is not running", http.StatusServiceUnavailable)
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		enable := r.Method == http.MethodPut
+
+		s.mu.Lock()
+		err := s.sandbox.SetDebugConsole(s.ctx, enable)
+		s.mu.Unlock()
+		if err != nil {
+			shimMgtLog.WithError(err).Error("failed to set the debug console")
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		shimMgtLog.WithField("enabled", enable).Info("debug console set")
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(debugConsole{Enabled: s.sandbox.DebugConsoleEnabled()})
+}
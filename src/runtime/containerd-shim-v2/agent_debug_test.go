@@ -0,0 +1,137 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package containerdshim
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/oci"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/vcmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func newAgentDebugService(t *testing.T, sandbox *vcmock.Sandbox) (*service, func()) {
+	dir, err := ioutil.TempDir("", "management-token")
+	assert.NoError(t, err)
+	tokenFile := filepath.Join(dir, "token")
+	assert.NoError(t, ioutil.WriteFile(tokenFile, []byte("secret\n"), 0600))
+
+	s := &service{
+		id:         testSandboxID,
+		ctx:        context.Background(),
+		sandbox:    sandbox,
+		config:     &oci.RuntimeConfig{ManagementTokenFile: tokenFile},
+		containers: make(map[string]*container),
+	}
+
+	return s, func() { os.RemoveAll(dir) }
+}
+
+func TestAgentLogLevel(t *testing.T) {
+	assert := assert.New(t)
+
+	level := "info"
+	s, cleanup := newAgentDebugService(t, &vcmock.Sandbox{
+		MockID: testSandboxID,
+		SetAgentLogLevelFunc: func(l string) error {
+			level = l
+			return nil
+		},
+		GetAgentLogLevelFunc: func() string {
+			return level
+		},
+	})
+	defer cleanup()
+
+	request := func(method, token, query string) (string, int) {
+		r := httptest.NewRequest(method, "/agent-loglevel"+query, nil)
+		if token != "" {
+			r.Header.Set("Authorization", "Bearer "+token)
+		}
+		rr := httptest.NewRecorder()
+		s.agentLogLevel(rr, r)
+		return strings.TrimSpace(rr.Body.String()), rr.Code
+	}
+
+	result, code := request(http.MethodGet, "", "")
+	assert.Equal(http.StatusOK, code)
+	assert.Equal("info", result)
+
+	// changing the level is a management action
+	_, code = request(http.MethodPut, "", "?level=debug")
+	assert.Equal(http.StatusUnauthorized, code)
+	_, code = request(http.MethodPost, "secret", "?level=debug")
+	assert.Equal(http.StatusMethodNotAllowed, code)
+	_, code = request(http.MethodPut, "secret", "?level=verbose")
+	assert.Equal(http.StatusBadRequest, code)
+
+	result, code = request(http.MethodPut, "secret", "?level=debug")
+	assert.Equal(http.StatusOK, code)
+	assert.Equal("debug", result)
+
+	s.sandbox = nil
+	_, code = request(http.MethodGet, "", "")
+	assert.Equal(http.StatusServiceUnavailable, code)
+}
+
+func TestDebugConsole(t *testing.T) {
+	assert := assert.New(t)
+
+	enabled := false
+	s, cleanup := newAgentDebugService(t, &vcmock.Sandbox{
+		MockID: testSandboxID,
+		SetDebugConsoleFunc: func(enable bool) error {
+			enabled = enable
+			return nil
+		},
+		DebugConsoleEnabledFunc: func() bool {
+			return enabled
+		},
+	})
+	defer cleanup()
+
+	request := func(method, token string) (debugConsole, int) {
+		r := httptest.NewRequest(method, "/debug-console", nil)
+		if token != "" {
+			r.Header.Set("Authorization", "Bearer "+token)
+		}
+		rr := httptest.NewRecorder()
+		s.debugConsole(rr, r)
+
+		var result debugConsole
+		if rr.Code == http.StatusOK {
+			assert.NoError(json.NewDecoder(rr.Body).Decode(&result))
+		}
+		return result, rr.Code
+	}
+
+	result, code := request(http.MethodGet, "")
+	assert.Equal(http.StatusOK, code)
+	assert.False(result.Enabled)
+
+	// starting the console is a management action
+	_, code = request(http.MethodPut, "")
+	assert.Equal(http.StatusUnauthorized, code)
+	_, code = request(http.MethodPut, "wrong")
+	assert.Equal(http.StatusUnauthorized, code)
+	assert.False(enabled)
+
+	result, code = request(http.MethodPut, "secret")
+	assert.Equal(http.StatusOK, code)
+	assert.True(result.Enabled)
+
+	result, code = request(http.MethodDelete, "secret")
+	assert.Equal(http.StatusOK, code)
+	assert.False(result.Enabled)
+}
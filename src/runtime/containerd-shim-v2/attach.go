@@ -0,0 +1,316 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package containerdshim
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/katautils/featuregate"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/types"
+)
+
+const (
+	// AttachFeatureGate enables the debug commands run in the containers
+	// from the management socket, e.g. proxied by kata-monitor.
+	AttachFeatureGate = "MonitorAttach"
+
+	// the websocket subprotocol of the attach streams, framed as the
+	// remote command streams of Kubernetes
+	attachProtocol = "v4.channel.k8s.io"
+
+	attachBufferSize = 32 * 1024
+
+	// the largest message read from the client, the stdin and resize
+	// messages being small
+	attachMaxMessageSize = 1 << 20
+
+	attachCloseTimeout = time.Second
+)
+
+// the channels of the attach streams, the first byte of each message
+const (
+	attachStdin byte = iota
+	attachStdout
+	attachStderr
+	attachError
+	attachResize
+)
+
+var attachGateErr error
+
+func init() {
+	attachGateErr = featuregate.Register(featuregate.Gate{
+		Name:        AttachFeatureGate,
+		Description: "Run debug commands in the containers from the shim management socket, at /containers/<id>/attach.",
+	})
+}
+
+// attachUpgrader upgrades the attach requests. The management socket is
+// an abstract socket, reachable from the host network namespace, so the
+// requests are authorized by the management token, and the origin of the
+// browser requests is checked by kata-monitor, which proxies them with the
+// shim as host.
+var attachUpgrader = websocket.Upgrader{
+	Subprotocols: []string{attachProtocol},
+	CheckOrigin:  func(r *http.Request) bool { return true },
+}
+
+// attachConn is the websocket of an attach stream, whose messages are
+// written by the stdout and stderr copies and the final status.
+type attachConn struct {
+	*websocket.Conn
+	mu sync.Mutex
+}
+
+func (c *attachConn) writeMessage(channel byte, data []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.WriteMessage(websocket.BinaryMessage, append([]byte{channel}, data...))
+}
+
+// close sends a normal closure before closing the connection.
+func (c *attachConn) close() error {
+	c.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+		time.Now().Add(attachCloseTimeout))
+	return c.Close()
+}
+
+// attachTerminalSize is a resize message of the attach streams.
+type attachTerminalSize struct {
+	Width  uint16
+	Height uint16
+}
+
+// attachStatus is the result of the command, sent on the error channel
+// as a Kubernetes Status.
+type attachStatus struct {
+	Metadata struct{}             `json:"metadata"`
+	Status   string               `json:"status"`
+	Message  string               `json:"message,omitempty"`
+	Reason   string               `json:"reason,omitempty"`
+	Details  *attachStatusDetails `json:"details,omitempty"`
+}
+
+type attachStatusDetails struct {
+	Causes []attachStatusCause `json:"causes"`
+}
+
+type attachStatusCause struct {
+	Reason  string `json:"reason"`
+	Message string `json:"message"`
+}
+
+func newAttachStatus(exitCode int32, err error) attachStatus {
+	switch {
+	case err != nil:
+		return attachStatus{Status: "Failure", Message: err.Error()}
+	case exitCode != 0:
+		return attachStatus{
+			Status:  "Failure",
+			Message: fmt.Sprintf("command terminated with non-zero exit code %d", exitCode),
+			Reason:  "NonZeroExitCode",
+			Details: &attachStatusDetails{
+				Causes: []attachStatusCause{{Reason: "ExitCode", Message: strconv.Itoa(int(exitCode))}},
+			},
+		}
+	}
+	return attachStatus{Status: "Success"}
+}
+
+// attachCmd returns the debug command run in the container, with the
+// user, environment and working directory of the container process.
+func attachCmd(c *container, args []string, tty bool) types.Cmd {
+	cmd := types.Cmd{
+		Args:        args,
+		User:        "0",
+		Interactive: tty,
+		Detach:      !tty,
+	}
+
+	if c.spec != nil && c.spec.Process != nil {
+		p := c.spec.Process
+		cmd.Envs = getEnvs(p.Env)
+		cmd.User = fmt.Sprintf("%d", p.User.UID)
+		cmd.PrimaryGroup = fmt.Sprintf("%d", p.User.GID)
+		cmd.WorkDir = p.Cwd
+		cmd.NoNewPrivileges = p.NoNewPrivileges
+	}
+
+	return cmd
+}
+
+// containerAttach runs a debug command in a container, its stdio and the
+// terminal resizes being streamed over a websocket, served at
+// /containers/<id>/attach?command=<arg>&command=<arg>&tty=<true|false>
+func (s *service) containerAttach(w http.ResponseWriter, r *http.Request) {
+	if attachGateErr != nil {
+		http.Error(w, attachGateErr.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !featuregate.Enabled(AttachFeatureGate) {
+		http.Error(w, fmt.Sprintf("attach is disabled, see the %s feature gate", AttachFeatureGate), http.StatusForbidden)
+		return
+	}
+	if status, err := s.authorizeManagement(r); err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	path := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/containers/"), "/"), "/")
+	if len(path) != 2 || path[0] == "" || path[1] != "attach" {
+		http.Error(w, fmt.Sprintf("invalid path %s", r.URL.Path), http.StatusNotFound)
+		return
+	}
+	containerID := path[0]
+
+	query := r.URL.Query()
+	args := query["command"]
+	if len(args) == 0 {
+		args = []string{"/bin/sh"}
+	}
+	tty := false
+	if value := query.Get("tty"); value != "" {
+		var err error
+		if tty, err = strconv.ParseBool(value); err != nil {
+			http.Error(w, fmt.Sprintf("invalid tty %q", value), http.StatusBadRequest)
+			return
+		}
+	}
+
+	c, err := s.getContainer(containerID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	if !websocket.IsWebSocketUpgrade(r) {
+		http.Error(w, "not a websocket handshake", http.StatusBadRequest)
+		return
+	}
+	protocol := false
+	for _, p := range websocket.Subprotocols(r) {
+		protocol = protocol || p == attachProtocol
+	}
+	if !protocol {
+		http.Error(w, fmt.Sprintf("unsupported websocket protocols, expected %s", attachProtocol), http.StatusBadRequest)
+		return
+	}
+
+	conn, err := attachUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		shimMgtLog.WithError(err).Warn("failed to upgrade attach request")
+		return
+	}
+	conn.SetReadLimit(attachMaxMessageSize)
+	ws := &attachConn{Conn: conn}
+	defer ws.close()
+
+	exitCode, err := s.runAttachCmd(ws, containerID, attachCmd(c, args, tty))
+
+	status, _ := json.Marshal(newAttachStatus(exitCode, err))
+	ws.writeMessage(attachError, status)
+}
+
+// runAttachCmd runs the command in the container until it exits, copying
+// the stdin and resize messages from ws, and its output to ws.
+func (s *service) runAttachCmd(ws *attachConn, containerID string, cmd types.Cmd) (int32, error) {
+	ctx := s.ctx
+
+	_, proc, err := s.sandbox.EnterContainer(ctx, containerID, cmd)
+	if err != nil {
+		return 0, fmt.Errorf("cannot enter container %s: %v", containerID, err)
+	}
+	execID := proc.Token
+
+	logger := shimMgtLog.WithField("container", containerID).WithField("exec", execID)
+	logger.WithField("args", cmd.Args).Info("attach command started")
+
+	stdin, stdout, stderr, err := s.sandbox.IOStream(containerID, execID)
+	if err != nil {
+		s.sandbox.SignalProcess(ctx, containerID, execID, syscall.SIGKILL, false)
+		return 0, err
+	}
+
+	var wg sync.WaitGroup
+	copyOutput := func(channel byte, r io.Reader) {
+		defer wg.Done()
+
+		buf := make([]byte, attachBufferSize)
+		for {
+			n, err := r.Read(buf)
+			if n > 0 {
+				if err := ws.writeMessage(channel, buf[:n]); err != nil {
+					return
+				}
+			}
+			if err != nil {
+				return
+			}
+		}
+	}
+
+	wg.Add(1)
+	go copyOutput(attachStdout, stdout)
+	if !cmd.Interactive && stderr != nil {
+		wg.Add(1)
+		go copyOutput(attachStderr, stderr)
+	}
+
+	exited := make(chan struct{})
+	go func() {
+		defer stdin.Close()
+
+		for {
+			_, data, err := ws.ReadMessage()
+			if err != nil {
+				select {
+				case <-exited:
+				default:
+					// the client is gone, the command is not left behind
+					logger.WithError(err).Info("attach stream closed, killing the command")
+					s.sandbox.SignalProcess(ctx, containerID, execID, syscall.SIGKILL, false)
+				}
+				return
+			}
+			if len(data) == 0 {
+				continue
+			}
+
+			switch data[0] {
+			case attachStdin:
+				if _, err := stdin.Write(data[1:]); err != nil {
+					logger.WithError(err).Warn("failed to write attach stdin")
+				}
+			case attachResize:
+				var size attachTerminalSize
+				if err := json.Unmarshal(data[1:], &size); err != nil {
+					logger.WithError(err).Warn("invalid attach resize message")
+					continue
+				}
+				if err := s.sandbox.WinsizeProcess(ctx, containerID, execID, uint32(size.Height), uint32(size.Width)); err != nil {
+					logger.WithError(err).Warn("failed to resize attach terminal")
+				}
+			}
+		}
+	}()
+
+	exitCode, err := s.sandbox.WaitProcess(ctx, containerID, execID)
+	close(exited)
+	wg.Wait()
+
+	logger.WithField("exit-code", exitCode).Info("attach command exited")
+
+	return exitCode, err
+}
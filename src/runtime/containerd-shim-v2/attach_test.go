@@ -0,0 +1,137 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package containerdshim
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/katautils/featuregate"
+	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/vcmock"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/types"
+	"github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func dialAttach(t *testing.T, srv *httptest.Server, path, token string) (*websocket.Conn, error) {
+	dialer := websocket.Dialer{Subprotocols: []string{attachProtocol}}
+	ws, _, err := dialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+path,
+		http.Header{"Authorization": []string{"Bearer " + token}})
+	return ws, err
+}
+
+func TestAttachCmd(t *testing.T) {
+	assert := assert.New(t)
+
+	c := &container{spec: &specs.Spec{Process: &specs.Process{
+		User: specs.User{UID: 1000, GID: 100},
+		Env:  []string{"PATH=/bin"},
+		Cwd:  "/app",
+	}}}
+
+	cmd := attachCmd(c, []string{"sh"}, true)
+	assert.Equal("1000", cmd.User)
+	assert.Equal("100", cmd.PrimaryGroup)
+	assert.Equal("/app", cmd.WorkDir)
+	assert.Equal([]types.EnvVar{{Var: "PATH", Value: "/bin"}}, cmd.Envs)
+	assert.True(cmd.Interactive)
+	assert.False(cmd.Detach)
+
+	cmd = attachCmd(&container{}, []string{"sh"}, false)
+	assert.Equal("0", cmd.User)
+	assert.True(cmd.Detach)
+}
+
+func TestContainerAttach(t *testing.T) {
+	assert := assert.New(t)
+
+	// the command echoes its stdin, and exits with 3 when told to
+	stdout, stdin := io.Pipe()
+	exit := make(chan struct{})
+	resized := make(chan [2]uint32, 1)
+	var entered types.Cmd
+
+	sandbox := &vcmock.Sandbox{
+		MockID: testSandboxID,
+		EnterContainerFunc: func(containerID string, cmd types.Cmd) (vc.VCContainer, *vc.Process, error) {
+			entered = cmd
+			return &vcmock.Container{}, &vc.Process{Token: "debug"}, nil
+		},
+		IOStreamFunc: func(containerID, processID string) (io.WriteCloser, io.Reader, io.Reader, error) {
+			return stdin, stdout, nil, nil
+		},
+		WinsizeProcessFunc: func(containerID, processID string, height, width uint32) error {
+			resized <- [2]uint32{height, width}
+			return nil
+		},
+		WaitProcessFunc: func(containerID, processID string) (int32, error) {
+			<-exit
+			stdin.Close()
+			return 3, nil
+		},
+		SignalProcessFunc: func(containerID, processID string, signal syscall.Signal, all bool) error {
+			return nil
+		},
+	}
+
+	s, cleanup := newAgentDebugService(t, sandbox)
+	defer cleanup()
+	s.containers["foo"] = &container{spec: &specs.Spec{}}
+
+	srv := httptest.NewServer(http.HandlerFunc(s.containerAttach))
+	defer srv.Close()
+
+	// disabled by default
+	_, err := dialAttach(t, srv, "/containers/foo/attach", "secret")
+	assert.Error(err)
+
+	featuregate.Set(map[string]bool{AttachFeatureGate: true})
+	defer featuregate.Set(nil)
+
+	// the management token is required
+	_, err = dialAttach(t, srv, "/containers/foo/attach", "wrong")
+	assert.Error(err)
+
+	_, err = dialAttach(t, srv, "/containers/bar/attach", "secret")
+	assert.Error(err)
+
+	// the streams are only framed for the attach subprotocol
+	_, _, err = websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+"/containers/foo/attach",
+		http.Header{"Authorization": []string{"Bearer secret"}})
+	assert.Error(err)
+
+	ws, err := dialAttach(t, srv, "/containers/foo/attach?command=sh&command=-i&tty=true", "secret")
+	assert.NoError(err)
+	defer ws.Close()
+
+	assert.NoError(ws.WriteMessage(websocket.BinaryMessage, append([]byte{attachStdin}, "hello"...)))
+	_, data, err := ws.ReadMessage()
+	assert.NoError(err)
+	assert.Equal(append([]byte{attachStdout}, "hello"...), data)
+	assert.Equal([]string{"sh", "-i"}, entered.Args)
+	assert.True(entered.Interactive)
+
+	assert.NoError(ws.WriteMessage(websocket.BinaryMessage, append([]byte{attachResize}, `{"Width":80,"Height":24}`...)))
+	assert.Equal([2]uint32{24, 80}, <-resized)
+
+	close(exit)
+	_, data, err = ws.ReadMessage()
+	assert.NoError(err)
+	assert.Equal(attachError, data[0])
+
+	var status attachStatus
+	assert.NoError(json.Unmarshal(data[1:], &status))
+	assert.Equal("Failure", status.Status)
+	assert.Equal("NonZeroExitCode", status.Reason)
+	assert.Equal("3", status.Details.Causes[0].Message)
+}
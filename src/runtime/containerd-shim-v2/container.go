@@ -7,6 +7,7 @@ package containerdshim
 
 import (
 	"io"
+	"sync"
 	"time"
 
 	"github.com/containerd/containerd/api/types/task"
@@ -18,6 +19,11 @@ import (
 )
 
 type container struct {
+	// mu guards the status and exit fields of the container, and
+	// its execs. Their status is only changed with the service lock
+	// held too.
+	mu sync.Mutex
+
 	s           *service
 	ttyio       *ttyIO
 	spec        *specs.Spec
@@ -37,6 +43,10 @@ type container struct {
 	status      task.Status
 	terminal    bool
 	mounted     bool
+
+	// memoryEvents are the counters of the memory events of the
+	// container in the guest, by event.
+	memoryEvents map[string]uint64
 }
 
 func newContainer(s *service, r *taskAPI.CreateTaskRequest, containerType vc.ContainerType, spec *specs.Spec, mounted bool) (*container, error) {
@@ -68,3 +78,9 @@ func newContainer(s *service, r *taskAPI.CreateTaskRequest, containerType vc.Con
 	}
 	return c, nil
 }
+
+func (c *container) setStatus(status task.Status) {
+	c.mu.Lock()
+	c.status = status
+	c.mu.Unlock()
+}
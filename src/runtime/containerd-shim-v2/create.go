@@ -12,8 +12,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	containerd_types "github.com/containerd/containerd/api/types"
+	"github.com/containerd/containerd/api/types/task"
 	"github.com/containerd/containerd/mount"
 	taskAPI "github.com/containerd/containerd/runtime/v2/task"
 	"github.com/containerd/typeurl"
@@ -52,6 +54,16 @@ func create(ctx context.Context, s *service, r *taskAPI.CreateTaskRequest) (*con
 		return nil, err
 	}
 
+	// The rootfs of the remote snapshotters is mounted in the guest,
+	// from the mount info rather than from the mount source.
+	remoteSnapshot, err := vc.ParseRemoteSnapshot(rootFs.Options)
+	if err != nil {
+		return nil, err
+	}
+	if remoteSnapshot != nil {
+		rootFs.Source = ""
+	}
+
 	disableOutput := noNeedForOutput(detach, ociSpec.Process.Terminal)
 	rootfs := filepath.Join(r.Bundle, "rootfs")
 
@@ -65,6 +77,12 @@ func create(ctx context.Context, s *service, r *taskAPI.CreateTaskRequest) (*con
 		if err != nil {
 			return nil, err
 		}
+		if os.Getenv(shimGroupEnv) != "" {
+			if err = checkShimGroupConfig(s.config, ociSpec.Annotations); err != nil {
+				return nil, err
+			}
+		}
+		setShimLogFormat(s.config.ShimLogFormat)
 
 		// create tracer
 		// This is the earliest location we can create the tracer because we must wait
@@ -89,7 +107,7 @@ func create(ctx context.Context, s *service, r *taskAPI.CreateTaskRequest) (*con
 		s.ctx = newCtx
 		defer span.End()
 
-		if rootFs.Mounted, err = checkAndMount(s, r); err != nil {
+		if rootFs.Mounted, err = checkAndMount(s, r, remoteSnapshot != nil); err != nil {
 			return nil, err
 		}
 
@@ -107,10 +125,12 @@ func create(ctx context.Context, s *service, r *taskAPI.CreateTaskRequest) (*con
 		// ctx will be canceled after this rpc service call, but the sandbox will live
 		// across multiple rpc service calls.
 		//
+		bootStart := time.Now()
 		sandbox, _, err := katautils.CreateSandbox(s.ctx, vci, *ociSpec, *s.config, rootFs, r.ID, bundlePath, "", disableOutput, false)
 		if err != nil {
 			return nil, err
 		}
+		s.metrics().vmBootDuration.Set(time.Since(bootStart).Seconds())
 		s.sandbox = sandbox
 		pid, err := s.sandbox.GetHypervisorPid()
 		if err != nil {
@@ -118,7 +138,11 @@ func create(ctx context.Context, s *service, r *taskAPI.CreateTaskRequest) (*con
 		}
 		s.hpid = uint32(pid)
 
-		go s.startManagementServer(ctx, ociSpec)
+		if err = createContainerHooks(s.ctx, s, ociSpec, r.ID, bundlePath); err != nil {
+			return nil, err
+		}
+
+		go s.startManagementServer(ctx, ociSpec, bundlePath)
 
 	case vc.PodContainer:
 		span, ctx := katatrace.Trace(s.ctx, shimLog, "create", shimTracingTags)
@@ -128,7 +152,7 @@ func create(ctx context.Context, s *service, r *taskAPI.CreateTaskRequest) (*con
 			return nil, fmt.Errorf("BUG: Cannot start the container, since the sandbox hasn't been created")
 		}
 
-		if rootFs.Mounted, err = checkAndMount(s, r); err != nil {
+		if rootFs.Mounted, err = checkAndMount(s, r, remoteSnapshot != nil); err != nil {
 			return nil, err
 		}
 
@@ -140,10 +164,23 @@ func create(ctx context.Context, s *service, r *taskAPI.CreateTaskRequest) (*con
 			}
 		}()
 
-		_, err = katautils.CreateContainer(ctx, s.sandbox, *ociSpec, rootFs, r.ID, bundlePath, "", disableOutput)
+		// the other requests are served while the agent creates the container
+		_, err = katautils.CreateContainer(vc.WithAgentCallLock(ctx, &s.mu), s.sandbox, *ociSpec, rootFs, r.ID, bundlePath, "", disableOutput)
 		if err != nil {
 			return nil, err
 		}
+
+		// the sandbox may have been stopped or deleted in the meantime
+		if err = s.checkSandboxAlive(); err != nil {
+			if _, err2 := s.sandbox.DeleteContainer(ctx, r.ID); err2 != nil {
+				shimLog.WithField("container", r.ID).WithError(err2).Warn("failed to delete container")
+			}
+			return nil, err
+		}
+
+		if err = createContainerHooks(ctx, s, ociSpec, r.ID, bundlePath); err != nil {
+			return nil, err
+		}
 	}
 
 	container, err := newContainer(s, r, containerType, ociSpec, rootFs.Mounted)
@@ -154,6 +191,55 @@ func create(ctx context.Context, s *service, r *taskAPI.CreateTaskRequest) (*con
 	return container, nil
 }
 
+// checkSandboxAlive returns an error when the sandbox container was stopped
+// or deleted, or the service shut down. It is checked after the service lock
+// was released for the agent requests.
+func (s *service) checkSandboxAlive() error {
+	if s.ctx.Err() != nil {
+		return fmt.Errorf("sandbox %s is shutting down", s.id)
+	}
+
+	c, err := s.getContainer(s.id)
+	if err != nil {
+		return fmt.Errorf("sandbox %s was deleted", s.id)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.status == task.StatusStopped {
+		return fmt.Errorf("sandbox %s is stopped", s.id)
+	}
+
+	return nil
+}
+
+// createContainerHooks runs the create-container OCI hooks on the host, when
+// enabled by host_container_oci_hooks.
+func createContainerHooks(ctx context.Context, s *service, ociSpec *specs.Spec, containerID, bundlePath string) error {
+	if !s.hostContainerOCIHooks() {
+		return nil
+	}
+
+	ctx = katautils.HookContext(ctx, s.sandbox)
+	return katautils.EnterNetNS(s.sandbox.GetNetNs(), func() error {
+		return katautils.CreateContainerHooks(ctx, *ociSpec, containerID, bundlePath)
+	})
+}
+
+// guestOCIHooks tells if the agent runs the in-namespace OCI hooks and the
+// post-stop hooks rather than the shim.
+func (s *service) guestOCIHooks() bool {
+	return s.config != nil && s.config.GuestOCIHooks
+}
+
+// hostContainerOCIHooks tells if the shim runs the createContainer and
+// startContainer OCI hooks on the host. They are ignored by default, as they
+// don't run in the namespaces of the container they expect.
+func (s *service) hostContainerOCIHooks() bool {
+	return s.config != nil && !s.config.GuestOCIHooks && s.config.HostContainerOCIHooks
+}
+
 func loadSpec(r *taskAPI.CreateTaskRequest) (*specs.Spec, string, error) {
 	// Checks the MUST and MUST NOT from OCI runtime specification
 	bundlePath, err := validBundle(r.ID, r.Bundle)
@@ -209,7 +295,12 @@ func loadRuntimeConfig(s *service, r *taskAPI.CreateTaskRequest, anno map[string
 	return &runtimeConfig, nil
 }
 
-func checkAndMount(s *service, r *taskAPI.CreateTaskRequest) (bool, error) {
+func checkAndMount(s *service, r *taskAPI.CreateTaskRequest, remoteSnapshot bool) (bool, error) {
+	// The rootfs of the remote snapshotters is mounted in the guest.
+	if remoteSnapshot {
+		return false, nil
+	}
+
 	if len(r.Rootfs) == 1 {
 		m := r.Rootfs[0]
 
@@ -15,6 +15,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/containerd/containerd/api/types/task"
 	"github.com/containerd/containerd/namespaces"
 	taskAPI "github.com/containerd/containerd/runtime/v2/task"
 	crioption "github.com/containerd/cri-containerd/pkg/api/runtimeoptions/v1"
@@ -27,6 +28,7 @@ import (
 	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
 	vcAnnotations "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/annotations"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/compatoci"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/oci"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/vcmock"
 )
 
@@ -244,11 +246,13 @@ func TestCreateContainerSuccess(t *testing.T) {
 	assert.NoError(err)
 
 	s := &service{
-		id:         testContainerID,
-		sandbox:    sandbox,
-		containers: make(map[string]*container),
-		config:     &runtimeConfig,
-		ctx:        context.Background(),
+		id:      testSandboxID,
+		sandbox: sandbox,
+		containers: map[string]*container{
+			testSandboxID: {id: testSandboxID, cType: vc.PodSandbox, status: task.StatusRunning},
+		},
+		config: &runtimeConfig,
+		ctx:    context.Background(),
 	}
 
 	req := &taskAPI.CreateTaskRequest{
@@ -260,6 +264,24 @@ func TestCreateContainerSuccess(t *testing.T) {
 	ctx := namespaces.WithNamespace(context.Background(), "UnitTest")
 	_, err = s.Create(ctx, req)
 	assert.NoError(err)
+
+	// the sandbox is stopped while the container is created
+	var deleted []string
+	sandbox.CreateContainerFunc = func(containerConfig vc.ContainerConfig) (vc.VCContainer, error) {
+		s.containers[testSandboxID].status = task.StatusStopped
+		return &vcmock.Container{}, nil
+	}
+	sandbox.DeleteContainerFunc = func(containerID string) (vc.VCContainer, error) {
+		deleted = append(deleted, containerID)
+		return &vcmock.Container{}, nil
+	}
+
+	req.ID = testContainerID + "-2"
+	_, err = s.Create(ctx, req)
+	assert.Error(err)
+	assert.Equal([]string{req.ID}, deleted)
+	_, err = s.getContainer(req.ID)
+	assert.Error(err)
 }
 
 func TestCreateContainerFail(t *testing.T) {
@@ -491,3 +513,21 @@ func TestCreateLoadRuntimeConfig(t *testing.T) {
 	_, err = loadRuntimeConfig(s, r, anno)
 	assert.NoError(err)
 }
+
+func TestHostContainerOCIHooks(t *testing.T) {
+	assert := assert.New(t)
+
+	s := &service{}
+	assert.False(s.hostContainerOCIHooks())
+
+	// ignored by default
+	s.config = &oci.RuntimeConfig{}
+	assert.False(s.hostContainerOCIHooks())
+
+	s.config.HostContainerOCIHooks = true
+	assert.True(s.hostContainerOCIHooks())
+
+	// run by the agent
+	s.config.GuestOCIHooks = true
+	assert.False(s.hostContainerOCIHooks())
+}
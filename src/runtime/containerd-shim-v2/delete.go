@@ -27,11 +27,13 @@ func deleteContainer(ctx context.Context, s *service, c *container) error {
 		}
 	}
 
-	// Run post-stop OCI hooks.
-	if err := katautils.PostStopHooks(ctx, *c.spec, s.sandbox.ID(), c.bundle); err != nil {
-		// log warning and continue, as defined in oci runtime spec
-		// https://github.com/opencontainers/runtime-spec/blob/master/runtime.md#lifecycle
-		shimLog.WithError(err).Warn("Failed to run post-stop hooks")
+	// Run post-stop OCI hooks, unless they are run by the agent.
+	if !s.guestOCIHooks() {
+		if err := katautils.PostStopHooks(katautils.HookContext(ctx, s.sandbox), *c.spec, s.sandbox.ID(), c.bundle); err != nil {
+			// log warning and continue, as defined in oci runtime spec
+			// https://github.com/opencontainers/runtime-spec/blob/master/runtime.md#lifecycle
+			shimLog.WithError(err).Warn("Failed to run post-stop hooks")
+		}
 	}
 
 	if c.mounted {
@@ -41,7 +43,7 @@ func deleteContainer(ctx context.Context, s *service, c *container) error {
 		}
 	}
 
-	delete(s.containers, c.id)
+	s.removeContainer(c.id)
 
 	return nil
 }
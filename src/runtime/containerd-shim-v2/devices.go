@@ -0,0 +1,370 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package containerdshim
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/katautils/featuregate"
+	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/device/config"
+	pbTypes "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/agent/protocols"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// DeviceHotplugFeatureGate enables the devices hot-added to the
+	// sandbox from the management socket.
+	DeviceHotplugFeatureGate = "ManagementDeviceHotplug"
+
+	// the types of the hot-added devices
+	hotplugBlock   = "block"
+	hotplugNetwork = "network"
+	hotplugVFIO    = "vfio"
+
+	// maximum size of a device descriptor
+	hotplugRequestMaxSize = 64 * 1024
+)
+
+var (
+	deviceHotplugGateErr error
+
+	// pciDevicesPath is where the PCI devices of the host are found by
+	// their BDF, a variable to be changed in the tests.
+	pciDevicesPath = "/sys/bus/pci/devices"
+
+	// deviceNumber returns the type and numbers of a device file, a
+	// variable to be mocked in the tests.
+	deviceNumber = statDeviceNumber
+
+	// deviceLeaksCheckInterval is the interval of the checks of the
+	// devices attached to the VM against their users.
+	deviceLeaksCheckInterval = 5 * time.Minute
+
+	bdfRegexp = regexp.MustCompile(`^([0-9a-fA-F]{4}:)?[0-9a-fA-F]{2}:[0-9a-fA-F]{2}\.[0-7]$`)
+)
+
+func init() {
+	deviceHotplugGateErr = featuregate.Register(featuregate.Gate{
+		Name:        DeviceHotplugFeatureGate,
+		Description: "Hot-add the block, network and VFIO devices to the sandbox from the shim management socket, at /devices.",
+	})
+}
+
+// deviceHotplugRequest describes a device hot-added to the sandbox.
+type deviceHotplugRequest struct {
+	// Type is one of block, network or vfio
+	Type string `json:"type"`
+
+	// Path is the block device on the host
+	Path string `json:"path,omitempty"`
+	// ContainerPath is the path of the block device in the guest,
+	// the host one when empty
+	ContainerPath string `json:"container_path,omitempty"`
+	ReadOnly      bool   `json:"readonly,omitempty"`
+
+	// Interface is the tap or virtio-net interface of the sandbox
+	// network namespace added to the guest
+	Interface *pbTypes.Interface `json:"interface,omitempty"`
+
+	// BDF is the PCI address of the host device bound to vfio-pci,
+	// its whole IOMMU group being passed to the guest
+	BDF string `json:"bdf,omitempty"`
+}
+
+// deviceHotplugResponse is the result of a device hot-add.
+type deviceHotplugResponse struct {
+	Type string `json:"type"`
+	// ID is the device ID of the block and VFIO devices
+	ID string `json:"id,omitempty"`
+	// Interface is the network interface added to the guest
+	Interface *pbTypes.Interface `json:"interface,omitempty"`
+}
+
+func statDeviceNumber(path string) (uint32, int64, int64, error) {
+	var stat unix.Stat_t
+	if err := unix.Stat(path, &stat); err != nil {
+		return 0, 0, 0, err
+	}
+	return stat.Mode & unix.S_IFMT, int64(unix.Major(stat.Rdev)), int64(unix.Minor(stat.Rdev)), nil
+}
+
+// blockDeviceInfo returns the device info of a block device of the host.
+func blockDeviceInfo(req deviceHotplugRequest) (config.DeviceInfo, error) {
+	if !filepath.IsAbs(req.Path) {
+		return config.DeviceInfo{}, fmt.Errorf("block device path %q is not absolute", req.Path)
+	}
+
+	mode, major, minor, err := deviceNumber(req.Path)
+	if err != nil {
+		return config.DeviceInfo{}, err
+	}
+	if mode != unix.S_IFBLK {
+		return config.DeviceInfo{}, fmt.Errorf("%s is not a block device", req.Path)
+	}
+
+	containerPath := req.ContainerPath
+	if containerPath == "" {
+		containerPath = req.Path
+	}
+
+	return config.DeviceInfo{
+		HostPath:      req.Path,
+		ContainerPath: containerPath,
+		DevType:       "b",
+		Major:         major,
+		Minor:         minor,
+		ReadOnly:      req.ReadOnly,
+	}, nil
+}
+
+// vfioDeviceInfo returns the device info of the VFIO group of a PCI device
+// of the host.
+func vfioDeviceInfo(req deviceHotplugRequest) (config.DeviceInfo, error) {
+	if !bdfRegexp.MatchString(req.BDF) {
+		return config.DeviceInfo{}, fmt.Errorf("invalid PCI address %q", req.BDF)
+	}
+	bdf := req.BDF
+	if strings.Count(bdf, ":") == 1 {
+		bdf = "0000:" + bdf
+	}
+
+	group, err := os.Readlink(filepath.Join(pciDevicesPath, bdf, "iommu_group"))
+	if err != nil {
+		return config.DeviceInfo{}, fmt.Errorf("cannot find the IOMMU group of %s: %v", bdf, err)
+	}
+
+	path := filepath.Join("/dev/vfio", filepath.Base(group))
+	mode, major, minor, err := deviceNumber(path)
+	if err != nil {
+		return config.DeviceInfo{}, fmt.Errorf("%s is not bound to vfio-pci: %v", bdf, err)
+	}
+	if mode != unix.S_IFCHR {
+		return config.DeviceInfo{}, fmt.Errorf("%s is not a character device", path)
+	}
+
+	return config.DeviceInfo{
+		HostPath:      path,
+		ContainerPath: path,
+		DevType:       "c",
+		Major:         major,
+		Minor:         minor,
+	}, nil
+}
+
+// authorizeManagement checks the bearer token of a request against the
+// one of the management_token_file option. The requests are rejected
+// when no token is configured.
+func (s *service) authorizeManagement(r *http.Request) (int, error) {
+	if s.config == nil || s.config.ManagementTokenFile == "" {
+		return http.StatusForbidden, fmt.Errorf("no management token is configured")
+	}
+
+	// read on each request, for the token to be rotated
+	content, err := ioutil.ReadFile(s.config.ManagementTokenFile)
+	if err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("cannot read the management token: %v", err)
+	}
+	token := strings.TrimSpace(string(content))
+	if token == "" {
+		return http.StatusForbidden, fmt.Errorf("the management token is empty")
+	}
+
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "Bearer ") ||
+		subtle.ConstantTimeCompare([]byte(strings.TrimPrefix(auth, "Bearer ")), []byte(token)) != 1 {
+		return http.StatusUnauthorized, fmt.Errorf("invalid management token")
+	}
+
+	return http.StatusOK, nil
+}
+
+// hotplugDevice hot-adds a block, network or VFIO device to the running
+// sandbox, for the integrations managing the devices of the sandbox out
+// of band, e.g. storage orchestrators.
+func (s *service) hotplugDevice(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "only POST is supported", http.StatusMethodNotAllowed)
+		return
+	}
+	if deviceHotplugGateErr != nil {
+		http.Error(w, deviceHotplugGateErr.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !featuregate.Enabled(DeviceHotplugFeatureGate) {
+		http.Error(w, fmt.Sprintf("device hotplug is disabled, see the %s feature gate", DeviceHotplugFeatureGate), http.StatusForbidden)
+		return
+	}
+	if status, err := s.authorizeManagement(r); err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+	if s.sandbox == nil {
+		http.Error(w, "sandbox is not running", http.StatusServiceUnavailable)
+		return
+	}
+
+	var req deviceHotplugRequest
+	if err := json.NewDecoder(io.LimitReader(r.Body, hotplugRequestMaxSize)).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("invalid device: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	logger := shimMgtLog.WithField("device-type", req.Type)
+	resp := deviceHotplugResponse{Type: req.Type}
+
+	switch req.Type {
+	case hotplugBlock, hotplugVFIO:
+		var info config.DeviceInfo
+		var err error
+		if req.Type == hotplugBlock {
+			info, err = blockDeviceInfo(req)
+		} else {
+			info, err = vfioDeviceInfo(req)
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		logger = logger.WithField("device", info.HostPath)
+		// serialized with the containers using the devices of the sandbox
+		s.mu.Lock()
+		dev, err := s.sandbox.AddDevice(s.ctx, info)
+		s.mu.Unlock()
+		if err != nil {
+			logger.WithError(err).Error("failed to hot-add device")
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		resp.ID = dev.DeviceID()
+	case hotplugNetwork:
+		if req.Interface == nil {
+			http.Error(w, "missing network interface", http.StatusBadRequest)
+			return
+		}
+
+		logger = logger.WithField("device", req.Interface.Device)
+		s.mu.Lock()
+		inf, err := s.sandbox.AddInterface(s.ctx, req.Interface)
+		s.mu.Unlock()
+		if err != nil {
+			logger.WithError(err).Error("failed to hot-add network interface")
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		resp.Interface = inf
+	default:
+		http.Error(w, fmt.Sprintf("unsupported device type %q", req.Type), http.StatusBadRequest)
+		return
+	}
+
+	logger.Info("device hot-added")
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// watchDeviceLeaks checks periodically that the devices attached to the VM
+// match the containers using them.
+func watchDeviceLeaks(ctx context.Context, s *service) {
+	if s.sandbox == nil {
+		return
+	}
+
+	tick := time.NewTicker(deviceLeaksCheckInterval)
+	defer tick.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-tick.C:
+			s.checkDeviceLeaks()
+		}
+	}
+}
+
+// checkDeviceLeaks returns the device leaks of the sandbox, and records
+// them in the logs and metrics.
+func (s *service) checkDeviceLeaks() []vc.DeviceLeak {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.sandbox == nil {
+		return nil
+	}
+
+	leaks := s.sandbox.DeviceLeaks()
+	s.metrics().deviceLeaks.Set(float64(len(leaks)))
+	for _, leak := range leaks {
+		shimLog.WithFields(logrus.Fields{
+			"device":       leak.HostPath,
+			"device-id":    leak.ID,
+			"attach-count": leak.AttachCount,
+			"users":        leak.Users,
+		}).Warn("device attachments don't match the device users")
+	}
+
+	return leaks
+}
+
+// deviceLeaks serves the device leaks of the sandbox, and cleans them up on
+// POST: the devices attached more than they are used are detached.
+func (s *service) deviceLeaks(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		if status, err := s.authorizeManagement(r); err != nil {
+			http.Error(w, err.Error(), status)
+			return
+		}
+	default:
+		w.Header().Set("Allow", http.MethodGet+", "+http.MethodPost)
+		http.Error(w, "only GET and POST are supported", http.StatusMethodNotAllowed)
+		return
+	}
+	if s.sandbox == nil {
+		http.Error(w, "sandbox is not running", http.StatusServiceUnavailable)
+		return
+	}
+
+	var leaks []vc.DeviceLeak
+	if r.Method == http.MethodPost {
+		var err error
+		s.mu.Lock()
+		leaks, err = s.sandbox.CleanupDeviceLeaks(s.ctx)
+		s.mu.Unlock()
+		if err != nil {
+			shimMgtLog.WithError(err).Error("failed to clean up the device leaks")
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		shimMgtLog.WithField("devices", len(leaks)).Info("device leaks cleaned up")
+		s.checkDeviceLeaks()
+	} else {
+		leaks = s.checkDeviceLeaks()
+	}
+
+	if leaks == nil {
+		leaks = []vc.DeviceLeak{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(leaks)
+}
@@ -0,0 +1,260 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package containerdshim
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/katautils/featuregate"
+	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/device/api"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/device/config"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/device/drivers"
+	pbTypes "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/agent/protocols"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/oci"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/vcmock"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/sys/unix"
+)
+
+func mockDeviceNumber(devices map[string]uint32) func(string) (uint32, int64, int64, error) {
+	return func(path string) (uint32, int64, int64, error) {
+		mode, ok := devices[path]
+		if !ok {
+			return 0, 0, 0, os.ErrNotExist
+		}
+		return mode, 8, 16, nil
+	}
+}
+
+func TestBlockDeviceInfo(t *testing.T) {
+	assert := assert.New(t)
+
+	saved := deviceNumber
+	defer func() {
+		deviceNumber = saved
+	}()
+	deviceNumber = mockDeviceNumber(map[string]uint32{
+		"/dev/sdb":  unix.S_IFBLK,
+		"/dev/null": unix.S_IFCHR,
+	})
+
+	info, err := blockDeviceInfo(deviceHotplugRequest{Path: "/dev/sdb", ReadOnly: true})
+	assert.NoError(err)
+	assert.Equal(config.DeviceInfo{
+		HostPath:      "/dev/sdb",
+		ContainerPath: "/dev/sdb",
+		DevType:       "b",
+		Major:         8,
+		Minor:         16,
+		ReadOnly:      true,
+	}, info)
+
+	info, err = blockDeviceInfo(deviceHotplugRequest{Path: "/dev/sdb", ContainerPath: "/dev/data"})
+	assert.NoError(err)
+	assert.Equal("/dev/data", info.ContainerPath)
+
+	for _, path := range []string{"", "sdb", "/dev/null", "/dev/sdc"} {
+		_, err = blockDeviceInfo(deviceHotplugRequest{Path: path})
+		assert.Error(err, path)
+	}
+}
+
+func TestVFIODeviceInfo(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "pci-devices")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	assert.NoError(os.MkdirAll(filepath.Join(dir, "0000:3b:00.0"), 0755))
+	assert.NoError(os.Symlink("../../../kernel/iommu_groups/42", filepath.Join(dir, "0000:3b:00.0", "iommu_group")))
+
+	savedPath, savedNumber := pciDevicesPath, deviceNumber
+	defer func() {
+		pciDevicesPath, deviceNumber = savedPath, savedNumber
+	}()
+	pciDevicesPath = dir
+	deviceNumber = mockDeviceNumber(map[string]uint32{"/dev/vfio/42": unix.S_IFCHR})
+
+	for _, bdf := range []string{"0000:3b:00.0", "3b:00.0"} {
+		info, err := vfioDeviceInfo(deviceHotplugRequest{BDF: bdf})
+		assert.NoError(err, bdf)
+		assert.Equal("/dev/vfio/42", info.HostPath)
+		assert.Equal("c", info.DevType)
+	}
+
+	for _, bdf := range []string{"", "3b:00", "../3b:00.0", "0000:3b:01.0"} {
+		_, err = vfioDeviceInfo(deviceHotplugRequest{BDF: bdf})
+		assert.Error(err, bdf)
+	}
+}
+
+func TestHotplugDevice(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "management-token")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+	tokenFile := filepath.Join(dir, "token")
+	assert.NoError(ioutil.WriteFile(tokenFile, []byte("secret\n"), 0600))
+
+	saved := deviceNumber
+	defer func() {
+		deviceNumber = saved
+	}()
+	deviceNumber = mockDeviceNumber(map[string]uint32{"/dev/sdb": unix.S_IFBLK})
+
+	var added []config.DeviceInfo
+	sandbox := &vcmock.Sandbox{
+		MockID: testSandboxID,
+		AddDeviceFunc: func(info config.DeviceInfo) (api.Device, error) {
+			added = append(added, info)
+			return drivers.NewBlockDevice(&config.DeviceInfo{ID: "blk0"}), nil
+		},
+		AddInterfaceFunc: func(inf *pbTypes.Interface) (*pbTypes.Interface, error) {
+			if inf.Device != "tap1" {
+				return nil, fmt.Errorf("no interface %s", inf.Device)
+			}
+			inf.PciPath = "02/01"
+			return inf, nil
+		},
+	}
+
+	s := &service{
+		id:         testSandboxID,
+		ctx:        context.Background(),
+		sandbox:    sandbox,
+		config:     &oci.RuntimeConfig{},
+		containers: make(map[string]*container),
+	}
+
+	post := func(token, body string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, "/devices", strings.NewReader(body))
+		if token != "" {
+			r.Header.Set("Authorization", "Bearer "+token)
+		}
+		rr := httptest.NewRecorder()
+		s.hotplugDevice(rr, r)
+		return rr
+	}
+
+	block := `{"type":"block","path":"/dev/sdb","readonly":true}`
+
+	// disabled by default
+	assert.Equal(http.StatusForbidden, post("secret", block).Code)
+
+	featuregate.Set(map[string]bool{DeviceHotplugFeatureGate: true})
+	defer featuregate.Set(nil)
+
+	// no token configured
+	assert.Equal(http.StatusForbidden, post("secret", block).Code)
+
+	s.config.ManagementTokenFile = tokenFile
+	assert.Equal(http.StatusUnauthorized, post("", block).Code)
+	assert.Equal(http.StatusUnauthorized, post("wrong", block).Code)
+
+	rr := httptest.NewRecorder()
+	s.hotplugDevice(rr, httptest.NewRequest(http.MethodGet, "/devices", nil))
+	assert.Equal(http.StatusMethodNotAllowed, rr.Code)
+
+	rr = post("secret", block)
+	assert.Equal(http.StatusOK, rr.Code)
+	var resp deviceHotplugResponse
+	assert.NoError(json.NewDecoder(rr.Body).Decode(&resp))
+	assert.Equal(deviceHotplugResponse{Type: hotplugBlock, ID: "blk0"}, resp)
+	assert.Len(added, 1)
+	assert.Equal("/dev/sdb", added[0].HostPath)
+	assert.True(added[0].ReadOnly)
+
+	rr = post("secret", `{"type":"network","interface":{"device":"tap1","name":"eth1"}}`)
+	assert.Equal(http.StatusOK, rr.Code)
+	resp = deviceHotplugResponse{}
+	assert.NoError(json.NewDecoder(rr.Body).Decode(&resp))
+	assert.Equal("02/01", resp.Interface.PciPath)
+
+	assert.Equal(http.StatusInternalServerError, post("secret", `{"type":"network","interface":{"device":"tap2"}}`).Code)
+	assert.Equal(http.StatusBadRequest, post("secret", `{"type":"network"}`).Code)
+	assert.Equal(http.StatusBadRequest, post("secret", `{"type":"block","path":"/dev/sdc"}`).Code)
+	assert.Equal(http.StatusBadRequest, post("secret", `{"type":"floppy"}`).Code)
+	assert.Equal(http.StatusBadRequest, post("secret", `{`).Code)
+}
+
+func TestDeviceLeaks(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "management-token")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+	tokenFile := filepath.Join(dir, "token")
+	assert.NoError(ioutil.WriteFile(tokenFile, []byte("secret\n"), 0600))
+
+	leaks := []vc.DeviceLeak{
+		{ID: "blk0", Type: "block", HostPath: "/dev/sdb", AttachCount: 2, Users: 1},
+		{ID: "vfio0", Type: "vfio", HostPath: "/dev/vfio/1", AttachCount: 0, Users: 1},
+	}
+	sandbox := &vcmock.Sandbox{
+		MockID: testSandboxID,
+		DeviceLeaksFunc: func() []vc.DeviceLeak {
+			return leaks
+		},
+		CleanupDeviceLeaksFunc: func() ([]vc.DeviceLeak, error) {
+			cleaned := leaks[:1]
+			leaks = leaks[1:]
+			return cleaned, nil
+		},
+	}
+
+	s := &service{
+		id:         testSandboxID,
+		ctx:        context.Background(),
+		sandbox:    sandbox,
+		config:     &oci.RuntimeConfig{ManagementTokenFile: tokenFile},
+		containers: make(map[string]*container),
+	}
+
+	request := func(method, token string) ([]vc.DeviceLeak, int) {
+		r := httptest.NewRequest(method, "/devices/leaks", nil)
+		if token != "" {
+			r.Header.Set("Authorization", "Bearer "+token)
+		}
+		rr := httptest.NewRecorder()
+		s.deviceLeaks(rr, r)
+
+		var result []vc.DeviceLeak
+		if rr.Code == http.StatusOK {
+			assert.NoError(json.NewDecoder(rr.Body).Decode(&result))
+		}
+		return result, rr.Code
+	}
+
+	result, code := request(http.MethodGet, "")
+	assert.Equal(http.StatusOK, code)
+	assert.Len(result, 2)
+
+	// the cleanup is a management action
+	_, code = request(http.MethodPost, "")
+	assert.Equal(http.StatusUnauthorized, code)
+	_, code = request(http.MethodDelete, "secret")
+	assert.Equal(http.StatusMethodNotAllowed, code)
+
+	result, code = request(http.MethodPost, "secret")
+	assert.Equal(http.StatusOK, code)
+	assert.Equal([]vc.DeviceLeak{{ID: "blk0", Type: "block", HostPath: "/dev/sdb", AttachCount: 2, Users: 1}}, result)
+
+	result, _ = request(http.MethodGet, "")
+	assert.Len(result, 1)
+	assert.False(result[0].Leaked())
+}
@@ -34,6 +34,8 @@ func toGRPC(err error) error {
 		return status.Errorf(codes.InvalidArgument, err.Error())
 	case isNotFound(err):
 		return status.Errorf(codes.NotFound, err.Error())
+	case isResourceExhausted(err):
+		return status.Errorf(codes.ResourceExhausted, err.Error())
 	}
 
 	return err
@@ -61,6 +63,11 @@ func isNotFound(err error) bool {
 		strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "not exist")
 }
 
+func isResourceExhausted(err error) bool {
+	_, ok := err.(*vc.InsufficientMemoryError)
+	return ok
+}
+
 func isGRPCErrorCode(code codes.Code, err error) bool {
 	s, ok := status.FromError(err)
 	if !ok {
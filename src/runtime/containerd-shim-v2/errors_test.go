@@ -31,6 +31,14 @@ func TestToGRPC(t *testing.T) {
 	}
 }
 
+func TestToGRPCInsufficientMemory(t *testing.T) {
+	assert := assert.New(t)
+
+	err := toGRPC(&vc.InsufficientMemoryError{RequiredMiB: 2048, AvailableMiB: 1024})
+	assert.True(isGRPCErrorCode(codes.ResourceExhausted, err))
+	assert.Contains(err.Error(), "2048 MiB required")
+}
+
 func TestIsGRPCErrorCode(t *testing.T) {
 	assert := assert.New(t)
 
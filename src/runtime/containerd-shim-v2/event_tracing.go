@@ -0,0 +1,79 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package containerdshim
+
+import (
+	eventstypes "github.com/containerd/containerd/api/events"
+	"go.opentelemetry.io/otel/codes"
+	otelLabel "go.opentelemetry.io/otel/label"
+	otelTrace "go.opentelemetry.io/otel/trace"
+
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/katautils/katatrace"
+)
+
+// tracedEvent is a task event sent with its span, ended once the event is
+// published to containerd.
+type tracedEvent struct {
+	event interface{}
+	span  otelTrace.Span
+}
+
+// traceEvent starts the span of a task event, a child of the root span of
+// the sandbox, so that a trace shows the whole lifecycle of the sandbox, from
+// its creation to the exit of its tasks. The events sent before the sandbox
+// is created are not traced.
+func (s *service) traceEvent(evt interface{}) interface{} {
+	if s.rootCtx == nil {
+		return evt
+	}
+
+	span, _ := katatrace.Trace(s.rootCtx, shimLog, getTopic(evt), shimTracingTags)
+	span.SetAttributes(eventAttributes(evt)...)
+
+	return tracedEvent{event: evt, span: span}
+}
+
+// eventAttributes returns the attributes of the span of a task event.
+func eventAttributes(evt interface{}) []otelLabel.KeyValue {
+	switch e := evt.(type) {
+	case *eventstypes.TaskCreate:
+		return []otelLabel.KeyValue{otelLabel.String("container", e.ContainerID), otelLabel.Uint32("pid", e.Pid)}
+	case *eventstypes.TaskStart:
+		return []otelLabel.KeyValue{otelLabel.String("container", e.ContainerID), otelLabel.Uint32("pid", e.Pid)}
+	case *eventstypes.TaskOOM:
+		return []otelLabel.KeyValue{otelLabel.String("container", e.ContainerID)}
+	case *eventstypes.TaskExit:
+		return []otelLabel.KeyValue{otelLabel.String("container", e.ContainerID), otelLabel.String("exec", e.ID),
+			otelLabel.Uint32("pid", e.Pid), otelLabel.Uint32("exit_status", e.ExitStatus)}
+	case *eventstypes.TaskDelete:
+		return []otelLabel.KeyValue{otelLabel.String("container", e.ContainerID), otelLabel.String("exec", e.ID),
+			otelLabel.Uint32("pid", e.Pid), otelLabel.Uint32("exit_status", e.ExitStatus)}
+	case *eventstypes.TaskExecAdded:
+		return []otelLabel.KeyValue{otelLabel.String("container", e.ContainerID), otelLabel.String("exec", e.ExecID)}
+	case *eventstypes.TaskExecStarted:
+		return []otelLabel.KeyValue{otelLabel.String("container", e.ContainerID), otelLabel.String("exec", e.ExecID),
+			otelLabel.Uint32("pid", e.Pid)}
+	case *eventstypes.TaskPaused:
+		return []otelLabel.KeyValue{otelLabel.String("container", e.ContainerID)}
+	case *eventstypes.TaskResumed:
+		return []otelLabel.KeyValue{otelLabel.String("container", e.ContainerID)}
+	}
+
+	return nil
+}
+
+// endEventSpan ends the span of a published event, recording the error of
+// the publication, if any.
+func endEventSpan(span otelTrace.Span, err error) {
+	if span == nil {
+		return
+	}
+
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
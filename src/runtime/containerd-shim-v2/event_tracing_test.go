@@ -0,0 +1,101 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package containerdshim
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	eventstypes "github.com/containerd/containerd/api/events"
+	"github.com/containerd/containerd/events"
+	cdruntime "github.com/containerd/containerd/runtime"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	export "go.opentelemetry.io/otel/sdk/export/trace"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// spanRecorder records the ended spans.
+type spanRecorder struct {
+	sync.Mutex
+	spans []*export.SpanData
+}
+
+func (r *spanRecorder) ExportSpans(ctx context.Context, spans []*export.SpanData) error {
+	r.Lock()
+	defer r.Unlock()
+	r.spans = append(r.spans, spans...)
+	return nil
+}
+
+func (r *spanRecorder) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+// failingPublisher fails to publish the exit events.
+type failingPublisher struct{}
+
+func (p *failingPublisher) Publish(ctx context.Context, topic string, event events.Event) error {
+	if topic == cdruntime.TaskExitEventTopic {
+		return errors.New("containerd is gone")
+	}
+	return nil
+}
+
+func TestTraceEvent(t *testing.T) {
+	assert := assert.New(t)
+
+	recorder := &spanRecorder{}
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(recorder)))
+	defer otel.SetTracerProvider(trace.NewNoopTracerProvider())
+
+	s := &service{
+		id:     testSandboxID,
+		events: make(chan interface{}, chSize),
+	}
+
+	// not traced before the sandbox is created
+	s.send(&eventstypes.TaskCreate{ContainerID: testSandboxID})
+
+	rootCtx, rootSpan := otel.Tracer("kata").Start(context.Background(), "root span")
+	s.rootCtx = rootCtx
+	rootSpan.End()
+
+	s.send(&eventstypes.TaskStart{ContainerID: testSandboxID, Pid: 42})
+	s.sendL(&eventstypes.TaskExit{ContainerID: testSandboxID, ID: testSandboxID, Pid: 42, ExitStatus: 137})
+	close(s.events)
+
+	forward(context.Background(), s.events, &failingPublisher{})
+
+	recorder.Lock()
+	defer recorder.Unlock()
+
+	// the root span, and the spans of the events sent once it is created
+	assert.Len(recorder.spans, 3)
+	root := recorder.spans[0]
+
+	start := recorder.spans[1]
+	assert.Equal(cdruntime.TaskStartEventTopic, start.Name)
+	assert.Equal(root.SpanContext.SpanID, start.ParentSpanID)
+	assert.Equal(root.SpanContext.TraceID, start.SpanContext.TraceID)
+	assert.Equal(codes.Unset, start.StatusCode)
+
+	exit := recorder.spans[2]
+	assert.Equal(cdruntime.TaskExitEventTopic, exit.Name)
+	assert.Equal(root.SpanContext.SpanID, exit.ParentSpanID)
+	assert.Equal(codes.Error, exit.StatusCode)
+
+	attributes := make(map[string]interface{})
+	for _, kv := range exit.Attributes {
+		attributes[string(kv.Key)] = kv.Value.AsInterface()
+	}
+	assert.Equal(testSandboxID, attributes["container"])
+	assert.Equal(uint32(137), attributes["exit_status"])
+}
@@ -130,6 +130,7 @@ func newExec(c *container, stdin, stdout, stderr string, terminal bool, jspec *g
 	return exec, nil
 }
 
+// getExec must be called with c.mu held.
 func (c *container) getExec(id string) (*exec, error) {
 	if c.execs == nil {
 		return nil, errdefs.ToGRPCf(errdefs.ErrNotFound, "exec does not exist %s", id)
@@ -0,0 +1,52 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package containerdshim
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/cloudevent"
+)
+
+const (
+	// type of the CloudEvents posted to the exit report sink
+	exitReportEventType = "io.katacontainers.sandbox.exit"
+
+	exitReportSinkTimeout = 5 * time.Second
+
+	// the shim exits right after the report is posted, it is only
+	// retried once
+	exitReportRetries = 1
+)
+
+// postExitReport posts the exit report of the stopped sandbox to the sink
+// when set. It is posted before returning, as the shim exits right after
+// the sandbox is deleted.
+func (s *service) postExitReport(ctx context.Context) {
+	if s.config == nil || s.config.ExitReportSink == "" {
+		return
+	}
+
+	report := s.sandbox.GetExitReport()
+	if report == nil {
+		return
+	}
+
+	e, err := cloudevent.New(fmt.Sprintf("/kata-containers/sandbox/%s", s.id), exitReportEventType, report.StopTime, report)
+	if err != nil {
+		shimLog.WithError(err).Error("failed to generate exit report event")
+		return
+	}
+	e.SetTraceContext(ctx)
+
+	client := &http.Client{Timeout: exitReportSinkTimeout}
+	if err := cloudevent.Deliver(client, s.config.ExitReportSink, e, exitReportRetries); err != nil {
+		shimLog.WithError(err).WithField("sink", s.config.ExitReportSink).Warn("failed to post exit report")
+	}
+}
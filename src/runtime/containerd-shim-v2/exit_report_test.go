@@ -0,0 +1,66 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package containerdshim
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/cloudevent"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/oci"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/vcmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostExitReport(t *testing.T) {
+	assert := assert.New(t)
+
+	type exitReportEvent struct {
+		cloudevent.Event
+		Data vc.ExitReport `json:"data"`
+	}
+
+	var events []exitReportEvent
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var e exitReportEvent
+		json.NewDecoder(r.Body).Decode(&e)
+		events = append(events, e)
+	}))
+	defer srv.Close()
+
+	stopTime := time.Date(2021, 7, 1, 12, 0, 0, 0, time.UTC)
+	sandbox := &vcmock.Sandbox{MockID: testSandboxID}
+	s := &service{
+		id:      testSandboxID,
+		sandbox: sandbox,
+		config:  &oci.RuntimeConfig{},
+	}
+
+	// no sink
+	sandbox.GetExitReportFunc = func() *vc.ExitReport {
+		return &vc.ExitReport{SandboxID: testSandboxID, StopTime: stopTime, CPUSeconds: 1.5}
+	}
+	s.postExitReport(context.Background())
+	assert.Empty(events)
+
+	s.config.ExitReportSink = srv.URL
+	s.postExitReport(context.Background())
+	assert.Len(events, 1)
+	assert.Equal(exitReportEventType, events[0].Type)
+	assert.Equal("/kata-containers/sandbox/"+testSandboxID, events[0].Source)
+	assert.True(stopTime.Equal(events[0].Time))
+	assert.Equal(1.5, events[0].Data.CPUSeconds)
+
+	// not stopped
+	sandbox.GetExitReportFunc = nil
+	s.postExitReport(context.Background())
+	assert.Len(events, 1)
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package containerdshim
+
+import (
+	cdlog "github.com/containerd/containerd/log"
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/katautils"
+	"github.com/sirupsen/logrus"
+)
+
+// the names of the sandbox and container fields used across the packages,
+// renamed in the JSON records so that they can be queried without parsing
+var logFieldAliases = map[string]string{
+	"sandbox_id":   "sandbox",
+	"sandboxid":    "sandbox",
+	"container-id": "container",
+	"cid":          "container",
+}
+
+// shimJSONFormatter formats the shim log records as JSON objects: the fields
+// of the shim logger, e.g. the sandbox and the shim pid, are set on every
+// record, including the ones logged by containerd and the vendored packages.
+type shimJSONFormatter struct {
+	logrus.JSONFormatter
+
+	fields logrus.Fields
+}
+
+func newShimJSONFormatter(fields logrus.Fields) *shimJSONFormatter {
+	return &shimJSONFormatter{
+		JSONFormatter: logrus.JSONFormatter{
+			TimestampFormat: cdlog.RFC3339NanoFixed,
+		},
+		fields: fields,
+	}
+}
+
+func (f *shimJSONFormatter) Format(e *logrus.Entry) ([]byte, error) {
+	data := make(logrus.Fields, len(e.Data)+len(f.fields))
+	for k, v := range e.Data {
+		if alias, ok := logFieldAliases[k]; ok {
+			if _, found := e.Data[alias]; !found {
+				k = alias
+			}
+		}
+		data[k] = v
+	}
+
+	for k, v := range f.fields {
+		if _, found := data[k]; !found {
+			data[k] = v
+		}
+	}
+
+	// the entry is shared by the hooks, format a copy
+	entry := *e
+	entry.Data = data
+
+	return f.JSONFormatter.Format(&entry)
+}
+
+// setShimLogFormat sets the format of the shim log, written to the log fifo
+// read by containerd. The text formatter is set by containerd.
+func setShimLogFormat(format string) {
+	if format == katautils.ShimLogFormatJSON {
+		logrus.SetFormatter(newShimJSONFormatter(shimLog.Data))
+	}
+}
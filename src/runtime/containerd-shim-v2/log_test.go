@@ -0,0 +1,56 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package containerdshim
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShimJSONFormatter(t *testing.T) {
+	assert := assert.New(t)
+
+	var buf bytes.Buffer
+	logger := logrus.New()
+	logger.Out = &buf
+	logger.Formatter = newShimJSONFormatter(logrus.Fields{
+		"sandbox": "sandbox-id",
+		"pid":     1234,
+	})
+
+	decode := func() map[string]interface{} {
+		defer buf.Reset()
+		var record map[string]interface{}
+		assert.NoError(json.Unmarshal(buf.Bytes(), &record))
+		return record
+	}
+
+	// the shim fields are set on every record
+	logger.WithField("runtime", "io.containerd.kata.v2").Warn("from containerd")
+	record := decode()
+	assert.Equal("from containerd", record["msg"])
+	assert.Equal("warning", record["level"])
+	assert.Equal("sandbox-id", record["sandbox"])
+	assert.Equal(float64(1234), record["pid"])
+	assert.Equal("io.containerd.kata.v2", record["runtime"])
+	assert.Contains(record, "time")
+
+	// the sandbox and container fields are renamed
+	entry := logger.WithFields(logrus.Fields{"sandbox_id": "other", "container-id": "ctr"})
+	entry.Warn("renamed")
+	record = decode()
+	assert.Equal("other", record["sandbox"])
+	assert.Equal("ctr", record["container"])
+	assert.NotContains(record, "sandbox_id")
+	assert.NotContains(record, "container-id")
+
+	// the entry itself is unchanged
+	assert.Contains(entry.Data, "sandbox_id")
+}
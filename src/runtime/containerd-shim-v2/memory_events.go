@@ -0,0 +1,199 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package containerdshim
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	otelLabel "go.opentelemetry.io/otel/label"
+	"google.golang.org/grpc/codes"
+
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/katautils/katatrace"
+	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/cloudevent"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/oci"
+)
+
+const (
+	// type of the CloudEvents posted to the memory events sink
+	memoryEventType = "io.katacontainers.memory"
+
+	memoryEventsSinkTimeout = 5 * time.Second
+
+	// the memory events of a container are posted at most once per interval
+	memoryEventsInterval = 10 * time.Second
+)
+
+// memoryEventData is the data of the CloudEvents of the memory events.
+type memoryEventData struct {
+	Sandbox       string `json:"sandbox"`
+	Container     string `json:"container"`
+	ContainerName string `json:"container_name,omitempty"`
+	Event         string `json:"event"`
+	Count         uint64 `json:"count"`
+}
+
+// watchMemoryEvents records the memory events of the containers in the
+// guest, e.g. a container above its memory.high limit. The host cgroups only
+// account the memory of the VM, the events tell a container is near its limit.
+func watchMemoryEvents(ctx context.Context, s *service) {
+	if s.sandbox == nil {
+		return
+	}
+
+	var sink *memoryEventsSink
+	if s.config != nil && s.config.MemoryEventsSink != "" {
+		sink = newMemoryEventsSink(s.id, s.config.MemoryEventsSink)
+		go sink.run(ctx)
+	}
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		default:
+			event, err := s.sandbox.GetMemoryEvent(ctx)
+			if err != nil {
+				shimLog.WithError(err).Warn("failed to get memory event from sandbox")
+				// The older agents don't implement GetMemoryEvent, and it
+				// can be blocked by the agent API allow-list, stop
+				// attempting to get memory events.
+				if isGRPCErrorCode(codes.NotFound, err) || isGRPCErrorCode(codes.PermissionDenied, err) || err.Error() == "Dead agent" {
+					return
+				}
+				time.Sleep(defaultCheckInterval)
+				continue
+			}
+
+			s.recordMemoryEvent(event, sink)
+		}
+	}
+}
+
+// recordMemoryEvent keeps the counter of a memory event for the metrics,
+// and queues the event to the sink when set.
+func (s *service) recordMemoryEvent(event vc.MemoryEvent, sink *memoryEventsSink) {
+	c, err := s.getContainer(event.ContainerID)
+	if err != nil {
+		shimLog.WithError(err).WithField("container", event.ContainerID).Debug("memory event of unknown container")
+		return
+	}
+
+	shimLog.WithFields(logrus.Fields{
+		"container": event.ContainerID,
+		"event":     event.Event,
+		"count":     event.Count,
+	}).Debug("container memory event")
+
+	c.mu.Lock()
+	if c.memoryEvents == nil {
+		c.memoryEvents = make(map[string]uint64)
+	}
+	c.memoryEvents[event.Event] = event.Count
+	c.mu.Unlock()
+
+	if sink == nil {
+		return
+	}
+
+	sink.queue(memoryEventData{
+		Sandbox:       s.id,
+		Container:     event.ContainerID,
+		ContainerName: oci.ContainerName(*c.spec),
+		Event:         event.Event,
+		Count:         event.Count,
+	})
+}
+
+// memoryEventsSink posts the memory events to the sink, coalesced by
+// container and event over memoryEventsInterval: the counts being
+// cumulative, only the last one of the interval is posted.
+type memoryEventsSink struct {
+	sync.Mutex
+	client  *http.Client
+	source  string
+	url     string
+	pending map[memoryEventKey]memoryEventData
+}
+
+type memoryEventKey struct {
+	container string
+	event     string
+}
+
+func newMemoryEventsSink(sandboxID, url string) *memoryEventsSink {
+	return &memoryEventsSink{
+		client:  &http.Client{Timeout: memoryEventsSinkTimeout},
+		source:  fmt.Sprintf("/kata-containers/sandbox/%s", sandboxID),
+		url:     url,
+		pending: make(map[memoryEventKey]memoryEventData),
+	}
+}
+
+// queue queues an event, replacing the queued one of the container.
+func (q *memoryEventsSink) queue(data memoryEventData) {
+	q.Lock()
+	defer q.Unlock()
+
+	q.pending[memoryEventKey{data.Container, data.Event}] = data
+}
+
+// run posts the queued events every memoryEventsInterval, one at a time,
+// until ctx is done.
+func (q *memoryEventsSink) run(ctx context.Context) {
+	ticker := time.NewTicker(memoryEventsInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			q.flush(ctx)
+		}
+	}
+}
+
+// flush posts the queued events, with the trace context of ctx.
+func (q *memoryEventsSink) flush(ctx context.Context) {
+	q.Lock()
+	pending := q.pending
+	q.pending = make(map[memoryEventKey]memoryEventData)
+	q.Unlock()
+
+	for _, data := range pending {
+		q.post(ctx, data)
+	}
+}
+
+func (q *memoryEventsSink) post(ctx context.Context, data memoryEventData) {
+	// the span of the event is the one jumped to from the posted event
+	span, ctx := katatrace.Trace(ctx, shimLog, "memory_event", shimTracingTags)
+	span.SetAttributes(otelLabel.String("container", data.Container), otelLabel.String("event", data.Event))
+	defer span.End()
+
+	shimLog.WithFields(logrus.Fields{
+		"container": data.Container,
+		"event":     data.Event,
+		"count":     data.Count,
+	}).Info("container memory event")
+
+	e, err := cloudevent.New(q.source, memoryEventType, time.Now().UTC(), data)
+	if err != nil {
+		shimLog.WithError(err).Error("failed to generate memory event")
+		return
+	}
+	e.SetTraceContext(ctx)
+
+	if err := cloudevent.Deliver(q.client, q.url, e, cloudevent.DefaultRetries); err != nil {
+		shimLog.WithError(err).WithField("sink", q.url).Warn("failed to post memory event")
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package containerdshim
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/cloudevent"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/oci"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/vcmock"
+	"github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordMemoryEvent(t *testing.T) {
+	assert := assert.New(t)
+
+	type memoryEvent struct {
+		cloudevent.Event
+		Data memoryEventData `json:"data"`
+	}
+
+	events := make(chan memoryEvent, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var e memoryEvent
+		json.NewDecoder(r.Body).Decode(&e)
+		events <- e
+	}))
+	defer srv.Close()
+
+	s := &service{
+		id:      testSandboxID,
+		sandbox: &vcmock.Sandbox{MockID: testSandboxID},
+		config:  &oci.RuntimeConfig{MemoryEventsSink: srv.URL},
+		containers: map[string]*container{
+			"foo": {
+				spec: &specs.Spec{
+					Annotations: map[string]string{
+						"io.kubernetes.cri.container-name": "app",
+					},
+				},
+			},
+		},
+	}
+
+	sink := newMemoryEventsSink(testSandboxID, srv.URL)
+
+	// the events of unknown containers are dropped
+	s.recordMemoryEvent(vc.MemoryEvent{ContainerID: "bar", Event: "high", Count: 1}, sink)
+
+	// the events of a container are coalesced until the sink is flushed
+	s.recordMemoryEvent(vc.MemoryEvent{ContainerID: "foo", Event: "high", Count: 2}, sink)
+	s.recordMemoryEvent(vc.MemoryEvent{ContainerID: "foo", Event: "high", Count: 3}, sink)
+	assert.Equal(map[string]uint64{"high": 3}, s.containers["foo"].memoryEvents)
+	assert.Len(sink.pending, 1)
+
+	sink.flush(context.Background())
+	assert.Empty(sink.pending)
+
+	select {
+	case e := <-events:
+		assert.Equal(memoryEventType, e.Type)
+		assert.Equal("/kata-containers/sandbox/"+testSandboxID, e.Source)
+		assert.Equal(memoryEventData{
+			Sandbox:       testSandboxID,
+			Container:     "foo",
+			ContainerName: "app",
+			Event:         "high",
+			Count:         3,
+		}, e.Data)
+	case <-time.After(memoryEventsSinkTimeout):
+		assert.Fail("memory event not received")
+	}
+
+	select {
+	case e := <-events:
+		assert.Fail("unexpected memory event", "%+v", e)
+	default:
+	}
+
+	// without sink, only the counters are kept
+	s.recordMemoryEvent(vc.MemoryEvent{ContainerID: "foo", Event: "max", Count: 1}, nil)
+	assert.Equal(map[string]uint64{"high": 3, "max": 1}, s.containers["foo"].memoryEvents)
+
+	s.updateContainerMetrics(context.Background())
+	assert.Equal(float64(3), gaugeValue(s.metrics().containerMemoryEvents.WithLabelValues("foo", "app", "high")))
+
+	// the series of the deleted containers are dropped
+	delete(s.containers, "foo")
+	s.updateContainerMetrics(context.Background())
+	assert.Equal(0, countMetrics(s.metrics().containerMemoryEvents))
+}
@@ -15,14 +15,25 @@ import (
 	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
 )
 
-func marshalMetrics(ctx context.Context, s *service, containerID string) (*google_protobuf.Any, error) {
-	stats, err := s.sandbox.StatsContainer(ctx, containerID)
-	if err != nil {
-		return nil, err
+func marshalMetrics(ctx context.Context, s *service, c *container) (*google_protobuf.Any, error) {
+	var metrics *cgroupsv1.Metrics
+
+	if c.cType == vc.PodSandbox {
+		// The pod sandbox container stats are the stats of the whole VM,
+		// so that the pod stats account for the VM overhead.
+		stats, err := s.sandbox.Stats(ctx)
+		if err != nil {
+			return nil, err
+		}
+		metrics = sandboxStatsToMetrics(&stats)
+	} else {
+		stats, err := s.sandbox.StatsContainer(ctx, c.id)
+		if err != nil {
+			return nil, err
+		}
+		metrics = statsToMetrics(&stats)
 	}
 
-	metrics := statsToMetrics(&stats)
-
 	data, err := typeurl.MarshalAny(metrics)
 	if err != nil {
 		return nil, err
@@ -49,6 +60,15 @@ func statsToMetrics(stats *vc.ContainerStats) *cgroupsv1.Metrics {
 	return metrics
 }
 
+func sandboxStatsToMetrics(stats *vc.SandboxStats) *cgroupsv1.Metrics {
+	return &cgroupsv1.Metrics{
+		Pids:    setPidsStats(stats.CgroupStats.PidsStats),
+		CPU:     setCPUStats(stats.CgroupStats.CPUStats),
+		Memory:  setMemoryStats(stats.CgroupStats.MemoryStats),
+		Network: setNetworkStats(stats.NetworkStats),
+	}
+}
+
 func setHugetlbStats(vcHugetlb map[string]vc.HugetlbStats) []*cgroupsv1.HugetlbStat {
 	var hugetlbStats []*cgroupsv1.HugetlbStat
 	for _, v := range vcHugetlb {
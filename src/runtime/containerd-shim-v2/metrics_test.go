@@ -11,6 +11,7 @@ import (
 	"testing"
 
 	"github.com/containerd/cgroups/stats/v1"
+	"github.com/containerd/typeurl"
 	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/vcmock"
 	"github.com/stretchr/testify/assert"
@@ -57,3 +58,48 @@ func TestStatNetworkMetric(t *testing.T) {
 	metrics := statsToMetrics(&resp)
 	assert.Equal(expectedNetwork, metrics.Network)
 }
+
+func TestMarshalSandboxMetrics(t *testing.T) {
+	assert := assert.New(t)
+
+	sandbox := &vcmock.Sandbox{
+		MockID: testSandboxID,
+	}
+
+	sandbox.StatsFunc = func() (vc.SandboxStats, error) {
+		stats := vc.SandboxStats{
+			NetworkStats: []*vc.NetworkStats{{Name: "eth0", RxBytes: 10}},
+		}
+		stats.CgroupStats.CPUStats.CPUUsage.TotalUsage = 1000
+		stats.CgroupStats.MemoryStats.Usage.Usage = 2000
+		return stats, nil
+	}
+	sandbox.StatsContainerFunc = func(contID string) (vc.ContainerStats, error) {
+		return vc.ContainerStats{
+			NetworkStats: []*vc.NetworkStats{{Name: "eth0", RxBytes: 1}},
+		}, nil
+	}
+
+	s := &service{
+		id:      testSandboxID,
+		sandbox: sandbox,
+	}
+
+	// the pod sandbox container reports the stats of the whole VM
+	data, err := marshalMetrics(context.Background(), s, &container{id: testSandboxID, cType: vc.PodSandbox})
+	assert.NoError(err)
+
+	m, err := typeurl.UnmarshalAny(data)
+	assert.NoError(err)
+	metrics := m.(*v1.Metrics)
+	assert.Equal(uint64(1000), metrics.CPU.Usage.Total)
+	assert.Equal(uint64(2000), metrics.Memory.Usage.Usage)
+	assert.Equal(uint64(10), metrics.Network[0].RxBytes)
+
+	data, err = marshalMetrics(context.Background(), s, &container{id: testContainerID, cType: vc.PodContainer})
+	assert.NoError(err)
+
+	m, err = typeurl.UnmarshalAny(data)
+	assert.NoError(err)
+	assert.Equal(uint64(1), m.(*v1.Metrics).Network[0].RxBytes)
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package containerdshim
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// maximum size of a network policy request
+const networkPolicyMaxSize = 1024 * 1024
+
+// networkPolicy is the network policy enforced in the guest, the body of an
+// nftables table.
+type networkPolicy struct {
+	Ruleset string `json:"ruleset"`
+}
+
+// networkPolicy serves the network policy enforced in the guest, replaces
+// it on PUT and removes it on DELETE. The policy is provided by an external
+// controller, e.g. from the Kubernetes NetworkPolicy objects of the pod.
+func (s *service) networkPolicy(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut, http.MethodDelete:
+		if status, err := s.authorizeManagement(r); err != nil {
+			http.Error(w, err.Error(), status)
+			return
+		}
+	default:
+		w.Header().Set("Allow", http.MethodGet+", "+http.MethodPut+", "+http.MethodDelete)
+		http.Error(w, "only GET, PUT and DELETE are supported", http.StatusMethodNotAllowed)
+		return
+	}
+	if s.sandbox == nil {
+		http.Error(w, "sandbox is not running", http.StatusServiceUnavailable)
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		var policy networkPolicy
+		if r.Method == http.MethodPut {
+			if err := json.NewDecoder(io.LimitReader(r.Body, networkPolicyMaxSize)).Decode(&policy); err != nil {
+				http.Error(w, fmt.Sprintf("invalid network policy: %v", err), http.StatusBadRequest)
+				return
+			}
+		}
+
+		s.mu.Lock()
+		err := s.sandbox.SetNetworkPolicy(s.ctx, policy.Ruleset)
+		s.mu.Unlock()
+		if err != nil {
+			shimMgtLog.WithError(err).Error("failed to set the network policy")
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		shimMgtLog.WithField("removed", policy.Ruleset == "").Info("network policy set")
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(networkPolicy{Ruleset: s.sandbox.GetNetworkPolicy()})
+}
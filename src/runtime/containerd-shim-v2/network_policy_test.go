@@ -0,0 +1,100 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package containerdshim
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/oci"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/vcmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNetworkPolicy(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "management-token")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+	tokenFile := filepath.Join(dir, "token")
+	assert.NoError(ioutil.WriteFile(tokenFile, []byte("secret\n"), 0600))
+
+	var ruleset string
+	sandbox := &vcmock.Sandbox{
+		MockID: testSandboxID,
+		SetNetworkPolicyFunc: func(r string) error {
+			if strings.Contains(r, "include") {
+				return fmt.Errorf("the network policy cannot include files")
+			}
+			ruleset = r
+			return nil
+		},
+		GetNetworkPolicyFunc: func() string {
+			return ruleset
+		},
+	}
+
+	s := &service{
+		id:         testSandboxID,
+		ctx:        context.Background(),
+		sandbox:    sandbox,
+		config:     &oci.RuntimeConfig{ManagementTokenFile: tokenFile},
+		containers: make(map[string]*container),
+	}
+
+	request := func(method, token, body string) (networkPolicy, int) {
+		r := httptest.NewRequest(method, "/network-policy", strings.NewReader(body))
+		if token != "" {
+			r.Header.Set("Authorization", "Bearer "+token)
+		}
+		rr := httptest.NewRecorder()
+		s.networkPolicy(rr, r)
+
+		var result networkPolicy
+		if rr.Code == http.StatusOK {
+			assert.NoError(json.NewDecoder(rr.Body).Decode(&result))
+		}
+		return result, rr.Code
+	}
+
+	policy := `{"ruleset":"chain input { type filter hook input priority 0; policy drop; }"}`
+
+	result, code := request(http.MethodGet, "", "")
+	assert.Equal(http.StatusOK, code)
+	assert.Empty(result.Ruleset)
+
+	// setting the policy is a management action
+	_, code = request(http.MethodPut, "", policy)
+	assert.Equal(http.StatusUnauthorized, code)
+	_, code = request(http.MethodPost, "secret", policy)
+	assert.Equal(http.StatusMethodNotAllowed, code)
+	_, code = request(http.MethodPut, "secret", "{")
+	assert.Equal(http.StatusBadRequest, code)
+	_, code = request(http.MethodPut, "secret", `{"ruleset":"include \"/etc/nftables.conf\""}`)
+	assert.Equal(http.StatusInternalServerError, code)
+
+	result, code = request(http.MethodPut, "secret", policy)
+	assert.Equal(http.StatusOK, code)
+	assert.Equal("chain input { type filter hook input priority 0; policy drop; }", result.Ruleset)
+
+	result, code = request(http.MethodDelete, "secret", "")
+	assert.Equal(http.StatusOK, code)
+	assert.Empty(result.Ruleset)
+	assert.Empty(ruleset)
+
+	s.sandbox = nil
+	_, code = request(http.MethodGet, "", "")
+	assert.Equal(http.StatusServiceUnavailable, code)
+}
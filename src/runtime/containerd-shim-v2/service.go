@@ -8,6 +8,7 @@ package containerdshim
 import (
 	"context"
 	"io/ioutil"
+	"net/http"
 	"os"
 	sysexec "os/exec"
 	"sync"
@@ -27,6 +28,7 @@ import (
 	"github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+	otelTrace "go.opentelemetry.io/otel/trace"
 	"golang.org/x/sys/unix"
 
 	"github.com/kata-containers/kata-containers/src/runtime/pkg/katautils"
@@ -73,10 +75,14 @@ var shimLog = logrus.WithFields(logrus.Fields{
 
 // New returns a new shim service that can be used via GRPC
 func New(ctx context.Context, id string, publisher cdshim.Publisher, shutdown func()) (cdshim.Shim, error) {
-	shimLog = shimLog.WithFields(logrus.Fields{
-		"sandbox": id,
-		"pid":     os.Getpid(),
-	})
+	group := os.Getenv(shimGroupEnv)
+	if group != "" {
+		shimLog = shimLog.WithField("shim-group", group)
+	} else {
+		shimLog = shimLog.WithField("sandbox", id)
+	}
+	shimLog = shimLog.WithField("pid", os.Getpid())
+
 	// Discard the log before shim init its log output. Otherwise
 	// it will output into stdio, from which containerd would like
 	// to get the shim's socket address.
@@ -88,7 +94,21 @@ func New(ctx context.Context, id string, publisher cdshim.Publisher, shutdown fu
 	vci.SetLogger(ctx, shimLog)
 	katautils.SetLogger(ctx, shimLog, shimLog.Logger.Level)
 
-	s := &service{
+	if group != "" {
+		return newShimGroup(ctx, group, publisher, shutdown), nil
+	}
+
+	s := newShimService(ctx, id, shutdown)
+
+	go s.processExits()
+
+	go forward(ctx, s.events, publisher)
+
+	return s, nil
+}
+
+func newShimService(ctx context.Context, id string, shutdown func()) *service {
+	return &service{
 		id:         id,
 		pid:        uint32(os.Getpid()),
 		ctx:        ctx,
@@ -97,12 +117,6 @@ func New(ctx context.Context, id string, publisher cdshim.Publisher, shutdown fu
 		ec:         make(chan exit, bufferSize),
 		cancel:     shutdown,
 	}
-
-	go s.processExits()
-
-	go s.forward(ctx, publisher)
-
-	return s, nil
 }
 
 type exit struct {
@@ -115,9 +129,19 @@ type exit struct {
 
 // service is the shim implementation of a remote shim over GRPC
 type service struct {
-	mu          sync.Mutex
+	// mu serializes the operations changing the sandbox, e.g. creating,
+	// starting or deleting a container, which take it for writing. The
+	// operations on the processes of a container, e.g. exec, kill or
+	// resize, only take it for reading and can run in parallel, the
+	// shim state of each container being guarded by its own lock.
+	mu          sync.RWMutex
 	eventSendMu sync.Mutex
 
+	// containersMu guards the containers map, it is only held for
+	// lookups and updates so that the state queries never wait for
+	// the operations in progress.
+	containersMu sync.RWMutex
+
 	// hypervisor pid, Since this shimv2 cannot get the container processes pid from VM,
 	// thus for the returned values needed pid, just return the hypervisor's
 	// pid directly.
@@ -134,6 +158,15 @@ type service struct {
 	events     chan interface{}
 	monitor    chan error
 
+	// managementServer is the shim management server, closed
+	// when the service stops.
+	managementServer *http.Server
+	managementMu     sync.Mutex
+
+	// shimMetrics are created on the first use, see metrics.
+	shimMetrics *shimMetrics
+	metricsOnce sync.Once
+
 	cancel func()
 
 	ec chan exit
@@ -200,7 +233,41 @@ func (s *service) StartShim(ctx context.Context, opts cdshim.StartOpts) (_ strin
 		return "", err
 	}
 
-	address, err = cdshim.SocketAddress(ctx, opts.Address, opts.ID)
+	socketID := opts.ID
+	if size := sandboxesPerShim(bundlePath); size > 1 {
+		groups, err := openShimGroups(ctx)
+		if err != nil {
+			return "", err
+		}
+		// keep the registry locked until the socket of a new shim is created
+		defer groups.close()
+
+		group, running, err := groups.join(ctx, opts.Address, opts.ID, size)
+		if err != nil {
+			return "", err
+		}
+		defer func() {
+			if retErr != nil {
+				groups.leave(opts.ID)
+			}
+		}()
+
+		if running {
+			address, err := cdshim.SocketAddress(ctx, opts.Address, group)
+			if err != nil {
+				return "", err
+			}
+			if err := cdshim.WriteAddress("address", address); err != nil {
+				return "", err
+			}
+			return address, nil
+		}
+
+		cmd.Env = append(cmd.Env, shimGroupEnv+"="+group)
+		socketID = group
+	}
+
+	address, err = cdshim.SocketAddress(ctx, opts.Address, socketID)
 	if err != nil {
 		return "", err
 	}
@@ -251,28 +318,34 @@ func (s *service) StartShim(ctx context.Context, opts cdshim.StartOpts) (_ strin
 	return address, nil
 }
 
-func (s *service) forward(ctx context.Context, publisher events.Publisher) {
-	for e := range s.events {
+func forward(ctx context.Context, events chan interface{}, publisher events.Publisher) {
+	for e := range events {
+		var span otelTrace.Span
+		if te, ok := e.(tracedEvent); ok {
+			e, span = te.event, te.span
+		}
+
 		ctx, cancel := context.WithTimeout(ctx, timeOut)
 		err := publisher.Publish(ctx, getTopic(e), e)
 		cancel()
 		if err != nil {
 			shimLog.WithError(err).Error("post event")
 		}
+		endEventSpan(span, err)
 	}
 }
 
 func (s *service) send(evt interface{}) {
 	// for unit test, it will not initialize s.events
 	if s.events != nil {
-		s.events <- evt
+		s.events <- s.traceEvent(evt)
 	}
 }
 
 func (s *service) sendL(evt interface{}) {
 	s.eventSendMu.Lock()
 	if s.events != nil {
-		s.events <- evt
+		s.events <- s.traceEvent(evt)
 	}
 	s.eventSendMu.Unlock()
 }
@@ -344,6 +417,10 @@ func (s *service) Cleanup(ctx context.Context) (_ *taskAPI.DeleteResponse, err e
 		if err != nil {
 			return nil, err
 		}
+
+		if err := leaveShimGroup(spanCtx, s.id); err != nil {
+			shimLog.WithError(err).WithField("sandbox", s.id).Warn("failed to leave shim group")
+		}
 	case vc.PodContainer:
 		sandboxID, err := oci.SandboxID(ociSpec)
 		if err != nil {
@@ -370,9 +447,6 @@ func (s *service) Create(ctx context.Context, r *taskAPI.CreateTaskRequest) (_ *
 		rpcDurationsHistogram.WithLabelValues("create").Observe(float64(time.Since(start).Nanoseconds() / int64(time.Millisecond)))
 	}()
 
-	s.mu.Lock()
-	defer s.mu.Unlock()
-
 	if err := katautils.VerifyContainerID(r.ID); err != nil {
 		return nil, err
 	}
@@ -382,22 +456,21 @@ func (s *service) Create(ctx context.Context, r *taskAPI.CreateTaskRequest) (_ *
 		err       error
 	}
 	ch := make(chan Result, 1)
+	// the lock is held by the goroutine, which may release it while the
+	// agent creates the container, see vc.WithAgentCallLock, and which
+	// goes on after a timeout
 	go func() {
-		container, err := create(ctx, s, r)
-		ch <- Result{container, err}
-	}()
+		s.mu.Lock()
+		defer s.mu.Unlock()
 
-	select {
-	case <-ctx.Done():
-		return nil, errors.Errorf("create container timeout: %v", r.ID)
-	case res := <-ch:
-		if res.err != nil {
-			return nil, res.err
+		container, err := create(ctx, s, r)
+		if err != nil {
+			ch <- Result{nil, err}
+			return
 		}
-		container := res.container
 		container.status = task.StatusCreated
 
-		s.containers[r.ID] = container
+		s.addContainer(container)
 
 		s.send(&eventstypes.TaskCreate{
 			ContainerID: r.ID,
@@ -413,6 +486,17 @@ func (s *service) Create(ctx context.Context, r *taskAPI.CreateTaskRequest) (_ *
 			Pid:        s.hpid,
 		})
 
+		ch <- Result{container, nil}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return nil, errors.Errorf("create container timeout: %v", r.ID)
+	case res := <-ch:
+		if res.err != nil {
+			return nil, res.err
+		}
+
 		return &taskAPI.CreateTaskResponse{
 			Pid: s.hpid,
 		}, nil
@@ -430,20 +514,20 @@ func (s *service) Start(ctx context.Context, r *taskAPI.StartRequest) (_ *taskAP
 		rpcDurationsHistogram.WithLabelValues("start").Observe(float64(time.Since(start).Nanoseconds() / int64(time.Millisecond)))
 	}()
 
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	//start a container
+	if r.ExecID == "" {
+		s.mu.Lock()
+		defer s.mu.Unlock()
 
-	c, err := s.getContainer(r.ID)
-	if err != nil {
-		return nil, err
-	}
+		c, err := s.getContainer(r.ID)
+		if err != nil {
+			return nil, err
+		}
 
-	// hold the send lock so that the start events are sent before any exit events in the error case
-	s.eventSendMu.Lock()
-	defer s.eventSendMu.Unlock()
+		// hold the send lock so that the start events are sent before any exit events in the error case
+		s.eventSendMu.Lock()
+		defer s.eventSendMu.Unlock()
 
-	//start a container
-	if r.ExecID == "" {
 		err = startContainer(spanCtx, s, c)
 		if err != nil {
 			return nil, errdefs.ToGRPC(err)
@@ -453,6 +537,14 @@ func (s *service) Start(ctx context.Context, r *taskAPI.StartRequest) (_ *taskAP
 			Pid:         s.hpid,
 		})
 	} else {
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+
+		c, err := s.getContainer(r.ID)
+		if err != nil {
+			return nil, err
+		}
+
 		//start an exec
 		_, err = startExec(spanCtx, s, r.ID, r.ExecID)
 		if err != nil {
@@ -463,6 +555,10 @@ func (s *service) Start(ctx context.Context, r *taskAPI.StartRequest) (_ *taskAP
 			ExecID:      r.ExecID,
 			Pid:         s.hpid,
 		})
+
+		// wait for the exec once its start is reported, so that
+		// its exit event is sent after the start event
+		go wait(spanCtx, s, c, r.ExecID)
 	}
 
 	return &taskAPI.StartResponse{
@@ -481,8 +577,13 @@ func (s *service) Delete(ctx context.Context, r *taskAPI.DeleteRequest) (_ *task
 		rpcDurationsHistogram.WithLabelValues("delete").Observe(float64(time.Since(start).Nanoseconds() / int64(time.Millisecond)))
 	}()
 
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	if r.ExecID == "" {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+	} else {
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+	}
 
 	c, err := s.getContainer(r.ID)
 	if err != nil {
@@ -508,6 +609,9 @@ func (s *service) Delete(ctx context.Context, r *taskAPI.DeleteRequest) (_ *task
 		}, nil
 	}
 	//deal with the exec case
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	execs, err := c.getExec(r.ExecID)
 	if err != nil {
 		return nil, err
@@ -533,14 +637,17 @@ func (s *service) Exec(ctx context.Context, r *taskAPI.ExecProcessRequest) (_ *p
 		err = toGRPC(err)
 	}()
 
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 
 	c, err := s.getContainer(r.ID)
 	if err != nil {
 		return nil, err
 	}
 
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	if execs := c.execs[r.ExecID]; execs != nil {
 		return nil, errdefs.ToGRPCf(errdefs.ErrAlreadyExists, "id %s", r.ExecID)
 	}
@@ -571,8 +678,8 @@ func (s *service) ResizePty(ctx context.Context, r *taskAPI.ResizePtyRequest) (_
 		rpcDurationsHistogram.WithLabelValues("resize_pty").Observe(float64(time.Since(start).Nanoseconds() / int64(time.Millisecond)))
 	}()
 
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 
 	c, err := s.getContainer(r.ID)
 	if err != nil {
@@ -581,15 +688,17 @@ func (s *service) ResizePty(ctx context.Context, r *taskAPI.ResizePtyRequest) (_
 
 	processID := c.id
 	if r.ExecID != "" {
+		c.mu.Lock()
 		execs, err := c.getExec(r.ExecID)
 		if err != nil {
+			c.mu.Unlock()
 			return nil, err
 		}
 		execs.tty.height = r.Height
 		execs.tty.width = r.Width
 
 		processID = execs.id
-
+		c.mu.Unlock()
 	}
 	err = s.sandbox.WinsizeProcess(spanCtx, c.id, processID, r.Height, r.Width)
 	if err != nil {
@@ -610,14 +719,16 @@ func (s *service) State(ctx context.Context, r *taskAPI.StateRequest) (_ *taskAP
 		rpcDurationsHistogram.WithLabelValues("state").Observe(float64(time.Since(start).Nanoseconds() / int64(time.Millisecond)))
 	}()
 
-	s.mu.Lock()
-	defer s.mu.Unlock()
-
+	// The state is only read from the shim, the service lock is not
+	// needed and the query doesn't wait for the operations in progress.
 	c, err := s.getContainer(r.ID)
 	if err != nil {
 		return nil, err
 	}
 
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	if r.ExecID == "" {
 		return &taskAPI.StateResponse{
 			ID:         c.id,
@@ -672,11 +783,11 @@ func (s *service) Pause(ctx context.Context, r *taskAPI.PauseRequest) (_ *ptypes
 		return nil, err
 	}
 
-	c.status = task.StatusPausing
+	c.setStatus(task.StatusPausing)
 
 	err = s.sandbox.PauseContainer(spanCtx, r.ID)
 	if err == nil {
-		c.status = task.StatusPaused
+		c.setStatus(task.StatusPaused)
 		s.send(&eventstypes.TaskPaused{
 			ContainerID: c.id,
 		})
@@ -684,9 +795,9 @@ func (s *service) Pause(ctx context.Context, r *taskAPI.PauseRequest) (_ *ptypes
 	}
 
 	if status, err := s.getContainerStatus(c.id); err != nil {
-		c.status = task.StatusUnknown
+		c.setStatus(task.StatusUnknown)
 	} else {
-		c.status = status
+		c.setStatus(status)
 	}
 
 	return empty, err
@@ -713,7 +824,7 @@ func (s *service) Resume(ctx context.Context, r *taskAPI.ResumeRequest) (_ *ptyp
 
 	err = s.sandbox.ResumeContainer(spanCtx, c.id)
 	if err == nil {
-		c.status = task.StatusRunning
+		c.setStatus(task.StatusRunning)
 		s.send(&eventstypes.TaskResumed{
 			ContainerID: c.id,
 		})
@@ -721,9 +832,9 @@ func (s *service) Resume(ctx context.Context, r *taskAPI.ResumeRequest) (_ *ptyp
 	}
 
 	if status, err := s.getContainerStatus(c.id); err != nil {
-		c.status = task.StatusUnknown
+		c.setStatus(task.StatusUnknown)
 	} else {
-		c.status = status
+		c.setStatus(status)
 	}
 
 	return empty, err
@@ -740,8 +851,8 @@ func (s *service) Kill(ctx context.Context, r *taskAPI.KillRequest) (_ *ptypes.E
 		rpcDurationsHistogram.WithLabelValues("kill").Observe(float64(time.Since(start).Nanoseconds() / int64(time.Millisecond)))
 	}()
 
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 
 	signum := syscall.Signal(r.Signal)
 
@@ -750,15 +861,18 @@ func (s *service) Kill(ctx context.Context, r *taskAPI.KillRequest) (_ *ptypes.E
 		return nil, err
 	}
 
+	c.mu.Lock()
 	processStatus := c.status
 	processID := c.id
 	if r.ExecID != "" {
 		execs, err := c.getExec(r.ExecID)
 		if err != nil {
+			c.mu.Unlock()
 			return nil, err
 		}
 		processID = execs.id
 		if processID == "" {
+			c.mu.Unlock()
 			shimLog.WithFields(logrus.Fields{
 				"sandbox":   s.sandbox.ID(),
 				"container": c.id,
@@ -768,6 +882,7 @@ func (s *service) Kill(ctx context.Context, r *taskAPI.KillRequest) (_ *ptypes.E
 		}
 		processStatus = execs.status
 	}
+	c.mu.Unlock()
 
 	// According to CRI specs, kubelet will call StopPodSandbox()
 	// at least once before calling RemovePodSandbox, and this call
@@ -824,25 +939,29 @@ func (s *service) CloseIO(ctx context.Context, r *taskAPI.CloseIORequest) (_ *pt
 		rpcDurationsHistogram.WithLabelValues("close_io").Observe(float64(time.Since(start).Nanoseconds() / int64(time.Millisecond)))
 	}()
 
-	s.mu.Lock()
-	defer s.mu.Unlock()
-
+	s.mu.RLock()
 	c, err := s.getContainer(r.ID)
 	if err != nil {
+		s.mu.RUnlock()
 		return nil, err
 	}
 
+	c.mu.Lock()
 	stdin := c.stdinPipe
 	stdinCloser := c.stdinCloser
 
 	if r.ExecID != "" {
 		execs, err := c.getExec(r.ExecID)
 		if err != nil {
+			c.mu.Unlock()
+			s.mu.RUnlock()
 			return nil, err
 		}
 		stdin = execs.stdinPipe
 		stdinCloser = execs.stdinCloser
 	}
+	c.mu.Unlock()
+	s.mu.RUnlock()
 
 	// wait until the stdin io copy terminated, otherwise
 	// some contents would not be forwarded to the process.
@@ -879,8 +998,8 @@ func (s *service) Connect(ctx context.Context, r *taskAPI.ConnectRequest) (_ *ta
 		rpcDurationsHistogram.WithLabelValues("connect").Observe(float64(time.Since(start).Nanoseconds() / int64(time.Millisecond)))
 	}()
 
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 
 	return &taskAPI.ConnectResponse{
 		ShimPid: s.pid,
@@ -898,17 +1017,15 @@ func (s *service) Shutdown(ctx context.Context, r *taskAPI.ShutdownRequest) (_ *
 		rpcDurationsHistogram.WithLabelValues("shutdown").Observe(float64(time.Since(start).Nanoseconds() / int64(time.Millisecond)))
 	}()
 
-	s.mu.Lock()
-	if len(s.containers) != 0 {
-		s.mu.Unlock()
+	if s.containerCount() != 0 {
 		return empty, nil
 	}
-	s.mu.Unlock()
 
 	span.End()
 	katatrace.StopTracing(s.ctx)
 
 	s.cancel()
+	s.stopManagementServer()
 
 	// Since we only send an shutdown qmp command to qemu when do stopSandbox, and
 	// didn't wait until qemu process's exit, thus we'd better to make sure it had
@@ -932,15 +1049,15 @@ func (s *service) Stats(ctx context.Context, r *taskAPI.StatsRequest) (_ *taskAP
 		rpcDurationsHistogram.WithLabelValues("stats").Observe(float64(time.Since(start).Nanoseconds() / int64(time.Millisecond)))
 	}()
 
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 
 	c, err := s.getContainer(r.ID)
 	if err != nil {
 		return nil, err
 	}
 
-	data, err := marshalMetrics(spanCtx, s, c.id)
+	data, err := marshalMetrics(spanCtx, s, c)
 	if err != nil {
 		return nil, err
 	}
@@ -995,10 +1112,7 @@ func (s *service) Wait(ctx context.Context, r *taskAPI.WaitRequest) (_ *taskAPI.
 		rpcDurationsHistogram.WithLabelValues("wait").Observe(float64(time.Since(start).Nanoseconds() / int64(time.Millisecond)))
 	}()
 
-	s.mu.Lock()
 	c, err := s.getContainer(r.ID)
-	s.mu.Unlock()
-
 	if err != nil {
 		return nil, err
 	}
@@ -1011,7 +1125,9 @@ func (s *service) Wait(ctx context.Context, r *taskAPI.WaitRequest) (_ *taskAPI.
 		// there were other waits on this process.
 		c.exitCh <- ret
 	} else { //wait for exec
+		c.mu.Lock()
 		execs, err := c.getExec(r.ExecID)
+		c.mu.Unlock()
 		if err != nil {
 			return nil, err
 		}
@@ -1022,6 +1138,9 @@ func (s *service) Wait(ctx context.Context, r *taskAPI.WaitRequest) (_ *taskAPI.
 		execs.exitCh <- ret
 	}
 
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	return &taskAPI.WaitResponse{
 		ExitStatus: ret,
 		ExitedAt:   c.exitTime,
@@ -1035,8 +1154,8 @@ func (s *service) processExits() {
 }
 
 func (s *service) checkProcesses(e exit) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 
 	id := e.execid
 	if id == "" {
@@ -1053,7 +1172,9 @@ func (s *service) checkProcesses(e exit) {
 }
 
 func (s *service) getContainer(id string) (*container, error) {
+	s.containersMu.RLock()
 	c := s.containers[id]
+	s.containersMu.RUnlock()
 
 	if c == nil {
 		return nil, errdefs.ToGRPCf(errdefs.ErrNotFound, "container does not exist %s", id)
@@ -1062,6 +1183,38 @@ func (s *service) getContainer(id string) (*container, error) {
 	return c, nil
 }
 
+func (s *service) addContainer(c *container) {
+	s.containersMu.Lock()
+	s.containers[c.id] = c
+	s.containersMu.Unlock()
+}
+
+func (s *service) removeContainer(id string) {
+	s.containersMu.Lock()
+	delete(s.containers, id)
+	s.containersMu.Unlock()
+}
+
+// listContainers returns a snapshot of the containers of the service.
+func (s *service) listContainers() []*container {
+	s.containersMu.RLock()
+	defer s.containersMu.RUnlock()
+
+	containers := make([]*container, 0, len(s.containers))
+	for _, c := range s.containers {
+		containers = append(containers, c)
+	}
+
+	return containers
+}
+
+func (s *service) containerCount() int {
+	s.containersMu.RLock()
+	defer s.containersMu.RUnlock()
+
+	return len(s.containers)
+}
+
 func (s *service) getContainerStatus(containerID string) (task.Status, error) {
 	cStatus, err := s.sandbox.StatusContainer(containerID)
 	if err != nil {
@@ -12,10 +12,16 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
+	"syscall"
 	"testing"
 
+	"github.com/containerd/containerd/api/types/task"
 	taskAPI "github.com/containerd/containerd/runtime/v2/task"
+	"github.com/containerd/typeurl"
 	ktu "github.com/kata-containers/kata-containers/src/runtime/pkg/katatestutils"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/vcmock"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -78,3 +84,61 @@ func TestServiceCreate(t *testing.T) {
 		}
 	}
 }
+
+func TestServiceConcurrentExecs(t *testing.T) {
+	assert := assert.New(t)
+
+	s, err := newService(testSandboxID)
+	assert.NoError(err)
+	s.sandbox = &vcmock.Sandbox{MockID: testSandboxID}
+
+	c, err := newContainer(s, &taskAPI.CreateTaskRequest{ID: testContainerID}, "", nil, false)
+	assert.NoError(err)
+	s.addContainer(c)
+
+	spec, err := typeurl.MarshalAny(&specs.Process{Args: []string{"/bin/sh"}})
+	assert.NoError(err)
+
+	ctx := context.Background()
+
+	// run the exec operations of a storm in parallel, the race
+	// detector checks that the shim state is properly guarded
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(execID string) {
+			defer wg.Done()
+
+			_, err := s.Exec(ctx, &taskAPI.ExecProcessRequest{ID: testContainerID, ExecID: execID, Spec: spec})
+			assert.NoError(err)
+
+			resp, err := s.State(ctx, &taskAPI.StateRequest{ID: testContainerID, ExecID: execID})
+			assert.NoError(err)
+			assert.Equal(task.StatusCreated, resp.Status)
+
+			_, err = s.ResizePty(ctx, &taskAPI.ResizePtyRequest{ID: testContainerID, ExecID: execID, Width: 80, Height: 24})
+			assert.NoError(err)
+
+			_, err = s.Kill(ctx, &taskAPI.KillRequest{ID: testContainerID, ExecID: execID, Signal: uint32(syscall.SIGTERM)})
+			assert.Error(err)
+
+			_, err = s.State(ctx, &taskAPI.StateRequest{ID: testContainerID})
+			assert.NoError(err)
+
+			_, err = s.Delete(ctx, &taskAPI.DeleteRequest{ID: testContainerID, ExecID: execID})
+			assert.NoError(err)
+		}(fmt.Sprintf("exec-%d", i))
+	}
+	wg.Wait()
+
+	c.mu.Lock()
+	assert.Empty(c.execs)
+	c.mu.Unlock()
+
+	assert.Equal(1, s.containerCount())
+	s.removeContainer(testContainerID)
+	assert.Zero(s.containerCount())
+
+	_, err = s.State(ctx, &taskAPI.StateRequest{ID: testContainerID})
+	assert.Error(err)
+}
@@ -0,0 +1,572 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package containerdshim
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/containerd/containerd/errdefs"
+	"github.com/containerd/containerd/namespaces"
+	cdshim "github.com/containerd/containerd/runtime/v2/shim"
+	taskAPI "github.com/containerd/containerd/runtime/v2/task"
+	ptypes "github.com/gogo/protobuf/types"
+	"golang.org/x/sys/unix"
+
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/katautils"
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/katautils/katatrace"
+	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
+	vcAnnotations "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/annotations"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/compatoci"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/oci"
+)
+
+const (
+	// shimGroupEnv is set to the shim group ID in the environment
+	// of a shim daemon serving several sandboxes.
+	shimGroupEnv = "KATA_SHIM_GROUP"
+
+	shimGroupPrefix    = "kata-shim-group-"
+	shimGroupsLockFile = ".lock"
+)
+
+var (
+	// shimGroupsDir holds one file per sandbox served by a shim group,
+	// containing the group ID, under a directory per containerd namespace.
+	shimGroupsDir = "/run/kata-containers/shim-groups"
+
+	// canConnect checks if the shim of a group is running
+	canConnect = cdshim.CanConnect
+
+	_ cdshim.Shim = (cdshim.Shim)(&shimGroup{})
+)
+
+// shimGroups is the registry of the sandboxes served by shim groups,
+// locked until closed.
+type shimGroups struct {
+	dir  string
+	lock *os.File
+}
+
+func openShimGroups(ctx context.Context) (*shimGroups, error) {
+	ns, err := namespaces.NamespaceRequired(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Join(shimGroupsDir, ns)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+
+	lock, err := os.OpenFile(filepath.Join(dir, shimGroupsLockFile), os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := unix.Flock(int(lock.Fd()), unix.LOCK_EX); err != nil {
+		lock.Close()
+		return nil, err
+	}
+
+	return &shimGroups{
+		dir:  dir,
+		lock: lock,
+	}, nil
+}
+
+func (g *shimGroups) close() {
+	unix.Flock(int(g.lock.Fd()), unix.LOCK_UN)
+	g.lock.Close()
+}
+
+// members returns the sandboxes of each shim group.
+func (g *shimGroups) members() (map[string][]string, error) {
+	files, err := ioutil.ReadDir(g.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	members := make(map[string][]string)
+	for _, f := range files {
+		// sandbox IDs can't start with a dot
+		if strings.HasPrefix(f.Name(), ".") {
+			continue
+		}
+
+		group, err := ioutil.ReadFile(filepath.Join(g.dir, f.Name()))
+		if err != nil {
+			return nil, err
+		}
+		members[string(group)] = append(members[string(group)], f.Name())
+	}
+
+	return members, nil
+}
+
+// join adds the sandbox to the first shim group having less than size
+// sandboxes, and returns the group and if its shim is running. When it is
+// not, the shim must be started and listening before closing the registry.
+func (g *shimGroups) join(ctx context.Context, containerdAddress, sandboxID string, size uint32) (string, bool, error) {
+	members, err := g.members()
+	if err != nil {
+		return "", false, err
+	}
+
+	for i := 0; ; i++ {
+		group := fmt.Sprintf("%s%d", shimGroupPrefix, i)
+		address, err := cdshim.SocketAddress(ctx, containerdAddress, group)
+		if err != nil {
+			return "", false, err
+		}
+
+		running := canConnect(address)
+		if !running {
+			// forget the sandboxes of a dead shim
+			for _, id := range members[group] {
+				if err := g.leave(id); err != nil {
+					return "", false, err
+				}
+			}
+			members[group] = nil
+		}
+
+		for _, id := range members[group] {
+			if id == sandboxID {
+				return group, running, nil
+			}
+		}
+
+		if uint32(len(members[group])) >= size {
+			continue
+		}
+
+		if err := ioutil.WriteFile(filepath.Join(g.dir, sandboxID), []byte(group), 0600); err != nil {
+			return "", false, err
+		}
+
+		return group, running, nil
+	}
+}
+
+func (g *shimGroups) leave(sandboxID string) error {
+	if err := os.Remove(filepath.Join(g.dir, sandboxID)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// shimGroupOf returns the shim group serving the sandbox, if any.
+func shimGroupOf(ctx context.Context, sandboxID string) (string, error) {
+	ns, err := namespaces.NamespaceRequired(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	group, err := ioutil.ReadFile(filepath.Join(shimGroupsDir, ns, sandboxID))
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+
+	return string(group), err
+}
+
+func leaveShimGroup(ctx context.Context, sandboxID string) error {
+	group, err := shimGroupOf(ctx, sandboxID)
+	if err != nil || group == "" {
+		return err
+	}
+
+	groups, err := openShimGroups(ctx)
+	if err != nil {
+		return err
+	}
+	defer groups.close()
+
+	return groups.leave(sandboxID)
+}
+
+// sandboxesPerShim returns the maximum number of sandboxes of the shim
+// serving a new sandbox. The create task options are not known when the
+// shim is started, so the config is only looked for from the config path
+// annotation, the environment and the default paths.
+func sandboxesPerShim(bundlePath string) uint32 {
+	ociSpec, err := compatoci.ParseConfigJSON(bundlePath)
+	if err != nil {
+		return 0
+	}
+
+	configPath := oci.GetSandboxConfigPath(ociSpec.Annotations)
+	if configPath == "" {
+		configPath = os.Getenv("KATA_CONF_FILE")
+	}
+
+	_, runtimeConfig, err := katautils.LoadConfiguration(configPath, true)
+	if err != nil {
+		// use a dedicated shim, which reports the error
+		// when loading the config of the create task options
+		return 0
+	}
+
+	return runtimeConfig.SandboxesPerShim
+}
+
+// checkShimGroupConfig rejects the sandboxes of a shim group running in their
+// cgroup only, set by the configuration or the annotation: each of them would
+// move the shim shared with the other sandboxes into its own cgroup.
+func checkShimGroupConfig(config *oci.RuntimeConfig, annotations map[string]string) error {
+	cgroupOnly := config.SandboxCgroupOnly
+	if value, ok := annotations[vcAnnotations.SandboxCgroupOnly]; ok {
+		var err error
+		if cgroupOnly, err = strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("invalid annotation %s: %v", vcAnnotations.SandboxCgroupOnly, err)
+		}
+	}
+
+	if cgroupOnly {
+		return fmt.Errorf("sandbox_cgroup_only is not supported by the shim groups, see sandboxes_per_shim")
+	}
+
+	return nil
+}
+
+// shimGroup is a shim serving several sandboxes. Each sandbox has its own
+// service, the group dispatches the task requests to the service of the
+// sandbox owning the task.
+type shimGroup struct {
+	mu sync.Mutex
+
+	ctx context.Context
+	id  string
+
+	// services of the sandboxes, keyed by sandbox ID
+	sandboxes map[string]*service
+
+	// services owning the tasks, keyed by container ID
+	tasks map[string]*service
+
+	events chan interface{}
+	ec     chan exit
+
+	cancel func()
+}
+
+func newShimGroup(ctx context.Context, id string, publisher cdshim.Publisher, shutdown func()) *shimGroup {
+	g := &shimGroup{
+		ctx:       ctx,
+		id:        id,
+		sandboxes: make(map[string]*service),
+		tasks:     make(map[string]*service),
+		events:    make(chan interface{}, chSize),
+		ec:        make(chan exit, bufferSize),
+		cancel:    shutdown,
+	}
+
+	go g.processExits()
+
+	go forward(ctx, g.events, publisher)
+
+	return g
+}
+
+func (g *shimGroup) processExits() {
+	for e := range g.ec {
+		if s, err := g.getService(e.id); err == nil {
+			s.checkProcesses(e)
+		}
+	}
+}
+
+func (g *shimGroup) getService(id string) (*service, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	s := g.tasks[id]
+	if s == nil {
+		return nil, errdefs.ToGRPCf(errdefs.ErrNotFound, "container does not exist %s", id)
+	}
+
+	return s, nil
+}
+
+// createService returns the service creating the task, a new service
+// when the task is a sandbox.
+func (g *shimGroup) createService(r *taskAPI.CreateTaskRequest) (*service, bool, error) {
+	ociSpec, _, err := loadSpec(r)
+	if err != nil {
+		return nil, false, err
+	}
+
+	containerType, err := oci.ContainerType(*ociSpec)
+	if err != nil {
+		return nil, false, err
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if _, ok := g.tasks[r.ID]; ok {
+		return nil, false, errdefs.ToGRPCf(errdefs.ErrAlreadyExists, "container already exists %s", r.ID)
+	}
+
+	if containerType == vc.PodSandbox {
+		ctx, cancel := context.WithCancel(g.ctx)
+		s := newShimService(ctx, r.ID, cancel)
+		s.events = g.events
+		s.ec = g.ec
+		return s, true, nil
+	}
+
+	sandboxID, err := oci.SandboxID(*ociSpec)
+	if err != nil {
+		return nil, false, err
+	}
+
+	s := g.sandboxes[sandboxID]
+	if s == nil {
+		return nil, false, errdefs.ToGRPCf(errdefs.ErrNotFound, "sandbox does not exist %s", sandboxID)
+	}
+
+	return s, false, nil
+}
+
+// stopService releases the service of a deleted sandbox.
+func (g *shimGroup) stopService(s *service) {
+	s.cancel()
+	s.stopManagementServer()
+
+	// see service.Shutdown()
+	if s.hpid != 0 {
+		syscall.Kill(int(s.hpid), syscall.SIGKILL)
+	}
+}
+
+func (g *shimGroup) Create(ctx context.Context, r *taskAPI.CreateTaskRequest) (*taskAPI.CreateTaskResponse, error) {
+	s, isSandbox, err := g.createService(r)
+	if err != nil {
+		return nil, toGRPC(err)
+	}
+
+	resp, err := s.Create(ctx, r)
+	if err != nil {
+		if isSandbox {
+			g.stopService(s)
+			if err := leaveShimGroup(g.ctx, r.ID); err != nil {
+				shimLog.WithError(err).WithField("sandbox", r.ID).Warn("failed to leave shim group")
+			}
+		}
+		return nil, err
+	}
+
+	g.mu.Lock()
+	if isSandbox {
+		g.sandboxes[r.ID] = s
+	}
+	g.tasks[r.ID] = s
+	g.mu.Unlock()
+
+	return resp, nil
+}
+
+func (g *shimGroup) Delete(ctx context.Context, r *taskAPI.DeleteRequest) (*taskAPI.DeleteResponse, error) {
+	s, err := g.getService(r.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.Delete(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.ExecID == "" {
+		g.mu.Lock()
+		delete(g.tasks, r.ID)
+		g.mu.Unlock()
+	}
+
+	return resp, nil
+}
+
+// Shutdown releases the sandboxes without containers, and stops the shim
+// once it has no sandboxes left, including the ones joining the group.
+func (g *shimGroup) Shutdown(ctx context.Context, r *taskAPI.ShutdownRequest) (*ptypes.Empty, error) {
+	groups, err := openShimGroups(g.ctx)
+	if err != nil {
+		return nil, toGRPC(err)
+	}
+	defer groups.close()
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for id, s := range g.sandboxes {
+		if s.containerCount() != 0 {
+			continue
+		}
+
+		g.stopService(s)
+		delete(g.sandboxes, id)
+		if err := groups.leave(id); err != nil {
+			shimLog.WithError(err).WithField("sandbox", id).Warn("failed to leave shim group")
+		}
+	}
+
+	if len(g.sandboxes) != 0 {
+		return empty, nil
+	}
+
+	members, err := groups.members()
+	if err != nil {
+		return nil, toGRPC(err)
+	}
+	if len(members[g.id]) != 0 {
+		return empty, nil
+	}
+
+	katatrace.StopTracing(g.ctx)
+
+	g.cancel()
+
+	os.Exit(0)
+
+	// This will never be called, but this is only there to make sure the
+	// program can compile.
+	return empty, nil
+}
+
+func (g *shimGroup) Connect(ctx context.Context, r *taskAPI.ConnectRequest) (*taskAPI.ConnectResponse, error) {
+	s, err := g.getService(r.ID)
+	if err != nil {
+		// the task is not created yet
+		return &taskAPI.ConnectResponse{
+			ShimPid: uint32(os.Getpid()),
+		}, nil
+	}
+
+	return s.Connect(ctx, r)
+}
+
+func (g *shimGroup) State(ctx context.Context, r *taskAPI.StateRequest) (*taskAPI.StateResponse, error) {
+	s, err := g.getService(r.ID)
+	if err != nil {
+		return nil, err
+	}
+	return s.State(ctx, r)
+}
+
+func (g *shimGroup) Start(ctx context.Context, r *taskAPI.StartRequest) (*taskAPI.StartResponse, error) {
+	s, err := g.getService(r.ID)
+	if err != nil {
+		return nil, err
+	}
+	return s.Start(ctx, r)
+}
+
+func (g *shimGroup) Pids(ctx context.Context, r *taskAPI.PidsRequest) (*taskAPI.PidsResponse, error) {
+	s, err := g.getService(r.ID)
+	if err != nil {
+		return nil, err
+	}
+	return s.Pids(ctx, r)
+}
+
+func (g *shimGroup) Pause(ctx context.Context, r *taskAPI.PauseRequest) (*ptypes.Empty, error) {
+	s, err := g.getService(r.ID)
+	if err != nil {
+		return nil, err
+	}
+	return s.Pause(ctx, r)
+}
+
+func (g *shimGroup) Resume(ctx context.Context, r *taskAPI.ResumeRequest) (*ptypes.Empty, error) {
+	s, err := g.getService(r.ID)
+	if err != nil {
+		return nil, err
+	}
+	return s.Resume(ctx, r)
+}
+
+func (g *shimGroup) Checkpoint(ctx context.Context, r *taskAPI.CheckpointTaskRequest) (*ptypes.Empty, error) {
+	s, err := g.getService(r.ID)
+	if err != nil {
+		return nil, err
+	}
+	return s.Checkpoint(ctx, r)
+}
+
+func (g *shimGroup) Kill(ctx context.Context, r *taskAPI.KillRequest) (*ptypes.Empty, error) {
+	s, err := g.getService(r.ID)
+	if err != nil {
+		return nil, err
+	}
+	return s.Kill(ctx, r)
+}
+
+func (g *shimGroup) Exec(ctx context.Context, r *taskAPI.ExecProcessRequest) (*ptypes.Empty, error) {
+	s, err := g.getService(r.ID)
+	if err != nil {
+		return nil, err
+	}
+	return s.Exec(ctx, r)
+}
+
+func (g *shimGroup) ResizePty(ctx context.Context, r *taskAPI.ResizePtyRequest) (*ptypes.Empty, error) {
+	s, err := g.getService(r.ID)
+	if err != nil {
+		return nil, err
+	}
+	return s.ResizePty(ctx, r)
+}
+
+func (g *shimGroup) CloseIO(ctx context.Context, r *taskAPI.CloseIORequest) (*ptypes.Empty, error) {
+	s, err := g.getService(r.ID)
+	if err != nil {
+		return nil, err
+	}
+	return s.CloseIO(ctx, r)
+}
+
+func (g *shimGroup) Update(ctx context.Context, r *taskAPI.UpdateTaskRequest) (*ptypes.Empty, error) {
+	s, err := g.getService(r.ID)
+	if err != nil {
+		return nil, err
+	}
+	return s.Update(ctx, r)
+}
+
+func (g *shimGroup) Wait(ctx context.Context, r *taskAPI.WaitRequest) (*taskAPI.WaitResponse, error) {
+	s, err := g.getService(r.ID)
+	if err != nil {
+		return nil, err
+	}
+	return s.Wait(ctx, r)
+}
+
+func (g *shimGroup) Stats(ctx context.Context, r *taskAPI.StatsRequest) (*taskAPI.StatsResponse, error) {
+	s, err := g.getService(r.ID)
+	if err != nil {
+		return nil, err
+	}
+	return s.Stats(ctx, r)
+}
+
+// Cleanup and StartShim are served by the shim binary, not by a shim daemon.
+
+func (g *shimGroup) Cleanup(ctx context.Context) (*taskAPI.DeleteResponse, error) {
+	return nil, errdefs.ToGRPCf(errdefs.ErrNotImplemented, "cleanup is not served by shim group %s", g.id)
+}
+
+func (g *shimGroup) StartShim(ctx context.Context, opts cdshim.StartOpts) (string, error) {
+	return "", errdefs.ToGRPCf(errdefs.ErrNotImplemented, "start is not served by shim group %s", g.id)
+}
@@ -0,0 +1,134 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package containerdshim
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/containerd/containerd/errdefs"
+	"github.com/containerd/containerd/namespaces"
+	cdshim "github.com/containerd/containerd/runtime/v2/shim"
+	taskAPI "github.com/containerd/containerd/runtime/v2/task"
+	vcAnnotations "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/annotations"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/oci"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShimGroupsJoin(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "shim-groups")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	savedDir, savedCanConnect := shimGroupsDir, canConnect
+	defer func() {
+		shimGroupsDir, canConnect = savedDir, savedCanConnect
+	}()
+	shimGroupsDir = dir
+
+	ctx := namespaces.WithNamespace(context.Background(), "k8s.io")
+	const containerdAddress = "/run/containerd/containerd.sock"
+
+	running := make(map[string]bool)
+	canConnect = func(address string) bool {
+		return running[address]
+	}
+	groupAddress := func(group string) string {
+		address, err := cdshim.SocketAddress(ctx, containerdAddress, group)
+		assert.NoError(err)
+		return address
+	}
+
+	join := func(sandboxID string) (string, bool) {
+		groups, err := openShimGroups(ctx)
+		assert.NoError(err)
+		defer groups.close()
+
+		group, started, err := groups.join(ctx, containerdAddress, sandboxID, 2)
+		assert.NoError(err)
+		return group, started
+	}
+
+	// no running shim, start a new one
+	group, started := join("sb1")
+	assert.Equal(shimGroupPrefix+"0", group)
+	assert.False(started)
+	running[groupAddress(group)] = true
+
+	group, started = join("sb2")
+	assert.Equal(shimGroupPrefix+"0", group)
+	assert.True(started)
+
+	// joining again returns the same group
+	group, _ = join("sb2")
+	assert.Equal(shimGroupPrefix+"0", group)
+
+	// the first group is full
+	group, started = join("sb3")
+	assert.Equal(shimGroupPrefix+"1", group)
+	assert.False(started)
+
+	group, err = shimGroupOf(ctx, "sb3")
+	assert.NoError(err)
+	assert.Equal(shimGroupPrefix+"1", group)
+
+	assert.NoError(leaveShimGroup(ctx, "sb1"))
+	group, err = shimGroupOf(ctx, "sb1")
+	assert.NoError(err)
+	assert.Empty(group)
+
+	group, _ = join("sb4")
+	assert.Equal(shimGroupPrefix+"0", group)
+
+	// the sandboxes of a dead shim are forgotten
+	running = make(map[string]bool)
+	group, started = join("sb5")
+	assert.Equal(shimGroupPrefix+"0", group)
+	assert.False(started)
+
+	for _, id := range []string{"sb2", "sb4"} {
+		group, err = shimGroupOf(ctx, id)
+		assert.NoError(err)
+		assert.Empty(group, id)
+	}
+
+	// leaving a sandbox not served by a shim group is a no-op
+	assert.NoError(leaveShimGroup(ctx, "sb1"))
+}
+
+func TestShimGroupUnknownTask(t *testing.T) {
+	assert := assert.New(t)
+
+	g := &shimGroup{
+		sandboxes: make(map[string]*service),
+		tasks:     make(map[string]*service),
+	}
+
+	_, err := g.State(context.Background(), &taskAPI.StateRequest{ID: "foo"})
+	assert.True(errdefs.IsNotFound(errdefs.FromGRPC(err)))
+
+	resp, err := g.Connect(context.Background(), &taskAPI.ConnectRequest{ID: "foo"})
+	assert.NoError(err)
+	assert.Equal(uint32(os.Getpid()), resp.ShimPid)
+	assert.Zero(resp.TaskPid)
+}
+
+func TestCheckShimGroupConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	config := &oci.RuntimeConfig{}
+	assert.NoError(checkShimGroupConfig(config, nil))
+	assert.Error(checkShimGroupConfig(config, map[string]string{vcAnnotations.SandboxCgroupOnly: "true"}))
+	assert.Error(checkShimGroupConfig(config, map[string]string{vcAnnotations.SandboxCgroupOnly: "yes"}))
+
+	config.SandboxCgroupOnly = true
+	assert.Error(checkShimGroupConfig(config, nil))
+	assert.NoError(checkShimGroupConfig(config, map[string]string{vcAnnotations.SandboxCgroupOnly: "false"}))
+}
@@ -7,20 +7,26 @@ package containerdshim
 
 import (
 	"context"
+	"encoding/json"
 	"expvar"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/http/pprof"
+	"os"
 	"path/filepath"
+	goruntime "runtime"
 	"strconv"
 	"strings"
+	"time"
 
 	cdshim "github.com/containerd/containerd/runtime/v2/shim"
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/katautils"
 	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
+	vf "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/factory"
 	vcAnnotations "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/annotations"
 	"github.com/opencontainers/runtime-spec/specs-go"
-	"github.com/prometheus/client_golang/prometheus"
 	dto "github.com/prometheus/client_model/go"
 	"github.com/prometheus/common/expfmt"
 
@@ -29,11 +35,63 @@ import (
 	mutils "github.com/kata-containers/kata-containers/src/runtime/pkg/utils"
 )
 
+const (
+	// timeouts of the connections to the management server, the attach
+	// sessions hijacking their connection are not subject to them, nor
+	// the rebuilds of the VM template and the profiles, which last longer
+	managementReadTimeout  = 10 * time.Second
+	managementWriteTimeout = time.Minute
+
+	// managementRequestTimeout bounds the requests of the handlers to
+	// the sandbox, e.g. a scrape of the metrics of a stuck agent.
+	managementRequestTimeout = 30 * time.Second
+
+	// time given to the requests in progress when the shim stops
+	managementShutdownTimeout = 5 * time.Second
+
+	// AgentMetricsUnavailableName is the metric served in place of the
+	// agent metrics when the agent does not provide them, e.g. when its
+	// API allow-list denies them.
+	AgentMetricsUnavailableName = "kata_shim_agent_metrics_unavailable"
+)
+
 var (
 	ifSupportAgentMetricsAPI = true
 	shimMgtLog               = shimLog.WithField("subsystem", "shim-management")
+
+	// runtimeVersion and runtimeCommit are the version and commit of the
+	// runtime this shim is built from, they are set by the shim binary
+	// through SetVersion.
+	runtimeVersion = "unknown"
+	runtimeCommit  = "unknown"
 )
 
+// SetVersion sets the runtime version reported by the shim management endpoint.
+func SetVersion(version, commit string) {
+	runtimeVersion = version
+	runtimeCommit = commit
+}
+
+// VersionInfo is the version of a running shim, served at /version.
+type VersionInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	GoVersion string `json:"go_version"`
+	Pid       int    `json:"pid"`
+}
+
+// shimVersion returns the version of the running shim binary, it tells
+// the sandboxes to restart when the runtime is upgraded on the node
+func (s *service) shimVersion(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(VersionInfo{
+		Version:   runtimeVersion,
+		Commit:    runtimeCommit,
+		GoVersion: goruntime.Version(),
+		Pid:       os.Getpid(),
+	})
+}
+
 // agentURL returns URL for agent
 func (s *service) agentURL(w http.ResponseWriter, r *http.Request) {
 	url, err := s.sandbox.GetAgentURL()
@@ -46,42 +104,245 @@ func (s *service) agentURL(w http.ResponseWriter, r *http.Request) {
 	fmt.Fprint(w, url)
 }
 
+// agentAllowedAPIs returns the requests served by the agent, an empty
+// list meaning that all of them are served
+func (s *service) agentAllowedAPIs(w http.ResponseWriter, r *http.Request) {
+	apis := s.sandbox.GetAgentAllowedAPIs()
+	if apis == nil {
+		apis = []string{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(apis)
+}
+
+// auditLog returns the audit log of the sandbox
+func (s *service) auditLog(w http.ResponseWriter, r *http.Request) {
+	records, err := s.sandbox.GetAuditLog()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(records)
+}
+
+// ephemeralDiskStatus returns the status of the sandbox ephemeral disk
+func (s *service) ephemeralDiskStatus(w http.ResponseWriter, r *http.Request) {
+	status, err := s.sandbox.GetEphemeralDiskStatus()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(status)
+}
+
+// guestProtectionStatus returns the guest protection of the sandbox,
+// for the attestation workflows
+func (s *service) guestProtectionStatus(w http.ResponseWriter, r *http.Request) {
+	status, err := s.sandbox.GetGuestProtectionStatus()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(status)
+}
+
+// hypervisorInvocation returns how the hypervisor of the sandbox was
+// launched, to reproduce the VM outside of Kata Containers
+func (s *service) hypervisorInvocation(w http.ResponseWriter, r *http.Request) {
+	invocation, err := s.sandbox.GetHypervisorInvocation()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(invocation)
+}
+
+// SandboxStatus is the status of the sandbox, served at /status.
+type SandboxStatus struct {
+	ID            string `json:"id"`
+	State         string `json:"state"`
+	Hypervisor    string `json:"hypervisor"`
+	HypervisorPid int    `json:"hypervisor_pid,omitempty"`
+	// Guest is the liveness of the guest, reported by the agent
+	Guest vc.GuestStatus `json:"guest"`
+	// GuestError is set when the agent failed to report the guest status,
+	// the last heartbeat of Guest being still set
+	GuestError string `json:"guest_error,omitempty"`
+}
+
+// sandboxStatus returns the status of the sandbox and the liveness of its
+// guest, so that the health checks of the VM don't need to exec in it
+func (s *service) sandboxStatus(w http.ResponseWriter, r *http.Request) {
+	sandboxStatus := s.sandbox.Status()
+	status := SandboxStatus{
+		ID:         s.sandbox.ID(),
+		State:      string(sandboxStatus.State.State),
+		Hypervisor: string(sandboxStatus.Hypervisor),
+	}
+	if pid, err := s.sandbox.GetHypervisorPid(); err == nil {
+		status.HypervisorPid = pid
+	}
+
+	guest, err := s.sandbox.GetGuestStatus(r.Context())
+	if err != nil {
+		shimMgtLog.WithError(err).Warn("failed to get the guest status")
+		status.GuestError = err.Error()
+	}
+	status.Guest = guest
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(status)
+}
+
+// sandboxStats returns the stats of the VM and of all the containers of the
+// sandbox, with their sum and the VM overhead
+func (s *service) sandboxStats(w http.ResponseWriter, r *http.Request) {
+	stats, err := s.sandbox.StatsSandbox(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(stats)
+}
+
+// factoryEnabled returns true if the sandbox was configured with a VM factory
+func (s *service) factoryEnabled() bool {
+	return s.config != nil && katautils.FactoryEnabled(s.config)
+}
+
+// factoryStatus returns the status of the VM factory
+func (s *service) factoryStatus(w http.ResponseWriter, r *http.Request) {
+	if !s.factoryEnabled() {
+		http.Error(w, "vm factory is not enabled", http.StatusNotFound)
+		return
+	}
+
+	status, err := vf.GetStatus(r.Context(), katautils.FactoryConfig(s.config))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(status)
+}
+
+// factoryFlush destroys the VM template
+func (s *service) factoryFlush(w http.ResponseWriter, r *http.Request) {
+	s.updateTemplate(w, r, vf.FlushTemplate)
+}
+
+// factoryRebuild destroys the VM template and creates a new one
+func (s *service) factoryRebuild(w http.ResponseWriter, r *http.Request) {
+	s.updateTemplate(w, r, vf.RebuildTemplate)
+}
+
+func (s *service) updateTemplate(w http.ResponseWriter, r *http.Request, update func(context.Context, vf.Config) error) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "only POST is allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !s.factoryEnabled() || !s.config.FactoryConfig.Template {
+		http.Error(w, "vm template is not enabled", http.StatusNotFound)
+		return
+	}
+
+	// the management socket of any sandbox of the node can update the
+	// template, it is restricted to the holders of the management token
+	if status, err := s.authorizeManagement(r); err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	// the template is shared by all the sandboxes of the node, it is only used
+	// when a new sandbox starts, so it is safe to update it while this one runs.
+	if err := update(r.Context(), katautils.FactoryConfig(s.config)); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
 // serveMetrics handle /metrics requests
 func (s *service) serveMetrics(w http.ResponseWriter, r *http.Request) {
+	groups := s.metricsGroups()
+	gatherer := s.metrics().gatherer(s.sandbox, groups)
 
 	// update metrics from sandbox
-	s.sandbox.UpdateRuntimeMetrics()
+	if metricsGroupEnabled(groups, katautils.ShimMetricsGroupHypervisor) {
+		s.sandbox.UpdateRuntimeMetrics()
+	}
+
+	// update metrics of each container in the sandbox
+	if metricsGroupEnabled(groups, katautils.ShimMetricsGroupContainers) {
+		s.updateContainerMetrics(r.Context())
+	}
 
 	// update metrics for shim process
-	updateShimMetrics()
+	if metricsGroupEnabled(groups, katautils.ShimMetricsGroupShim) {
+		updateShimMetrics()
+	}
+
+	// update metrics of the VM factory
+	if s.factoryEnabled() {
+		if err := vf.UpdateMetrics(r.Context(), katautils.FactoryConfig(s.config)); err != nil {
+			shimMgtLog.WithError(err).Debug("failed to update factory metrics")
+		}
+	}
 
 	// metrics gathered by shim
-	mfs, err := prometheus.DefaultGatherer.Gather()
+	mfs, err := gatherer.Gather()
 	if err != nil {
 		return
 	}
 
-	// encode the metrics
-	encoder := expfmt.NewEncoder(w, expfmt.FmtText)
+	// encode the metrics, in the protobuf delimited format when accepted,
+	// e.g. by kata-monitor, as it is cheaper to decode than the text one
+	format := expfmt.Negotiate(r.Header)
+	w.Header().Set("Content-Type", string(format))
+	encoder := expfmt.NewEncoder(w, format)
 	for _, mf := range mfs {
 		encoder.Encode(mf)
 	}
 
 	// if using an old agent, only collect shim/sandbox metrics.
 	if !ifSupportAgentMetricsAPI {
+		encoder.Encode(agentMetricsUnavailable(codes.NotFound))
 		return
 	}
 
 	// get metrics from agent
-	// can not pass context to serveMetrics, so use background context
-	agentMetrics, err := s.sandbox.GetAgentMetrics(context.Background())
+	agentMetrics, err := s.sandbox.GetAgentMetrics(r.Context())
 	if err != nil {
 		shimMgtLog.WithError(err).Error("failed GetAgentMetrics")
 		if isGRPCErrorCode(codes.NotFound, err) {
 			shimMgtLog.Warn("metrics API not supportted by this agent.")
 			ifSupportAgentMetricsAPI = false
+			encoder.Encode(agentMetricsUnavailable(codes.NotFound))
 			return
 		}
+
+		// the agent answered, but does not implement the metrics
+		// or its API allow-list denies them
+		for _, code := range []codes.Code{codes.Unimplemented, codes.PermissionDenied} {
+			if isGRPCErrorCode(code, err) {
+				encoder.Encode(agentMetricsUnavailable(code))
+				break
+			}
+		}
 	}
 
 	// decode and parse metrics from agent
@@ -95,7 +356,34 @@ func (s *service) serveMetrics(w http.ResponseWriter, r *http.Request) {
 	// collect pod overhead metrics need sleep to get the changes of cpu/memory resources usage
 	// so here only trigger the collect operation, and the data will be gathered
 	// next time collection request from Prometheus server
-	go s.setPodOverheadMetrics(context.Background())
+	if metricsGroupEnabled(groups, katautils.ShimMetricsGroupOverhead) {
+		go s.setPodOverheadMetrics(context.Background())
+	}
+
+	// walking the sandbox storage may be slow too, the same as above,
+	// only one walk runs at a time however frequent the requests are.
+	if metricsGroupEnabled(groups, katautils.ShimMetricsGroupStorage) {
+		go s.sandbox.UpdateStorageMetrics()
+	}
+}
+
+// agentMetricsUnavailable returns the metric reporting that the agent
+// metrics are not available for the reason code, so that kata-monitor
+// does not take the agent for unresponsive.
+func agentMetricsUnavailable(code codes.Code) *dto.MetricFamily {
+	one := float64(1)
+	return &dto.MetricFamily{
+		Name: mutils.String2Pointer(AgentMetricsUnavailableName),
+		Help: mutils.String2Pointer("The agent metrics are not available, by reason."),
+		Type: dto.MetricType_GAUGE.Enum(),
+		Metric: []*dto.Metric{{
+			Label: []*dto.LabelPair{{
+				Name:  mutils.String2Pointer("reason"),
+				Value: mutils.String2Pointer(code.String()),
+			}},
+			Gauge: &dto.Gauge{Value: &one},
+		}},
+	}
 }
 
 func decodeAgentMetrics(body string) []*dto.MetricFamily {
@@ -126,7 +414,7 @@ func decodeAgentMetrics(body string) []*dto.MetricFamily {
 	return list
 }
 
-func (s *service) startManagementServer(ctx context.Context, ociSpec *specs.Spec) {
+func (s *service) startManagementServer(ctx context.Context, ociSpec *specs.Spec, bundlePath string) {
 	// metrics socket will under sandbox's bundle path
 	metricsAddress := SocketAddress(s.id)
 
@@ -137,7 +425,7 @@ func (s *service) startManagementServer(ctx context.Context, ociSpec *specs.Spec
 	}
 
 	// write metrics address to filesystem
-	if err := cdshim.WriteAddress("monitor_address", metricsAddress); err != nil {
+	if err := cdshim.WriteAddress(filepath.Join(bundlePath, "monitor_address"), metricsAddress); err != nil {
 		shimMgtLog.WithError(err).Errorf("failed to write metrics address")
 		return
 	}
@@ -146,19 +434,138 @@ func (s *service) startManagementServer(ctx context.Context, ociSpec *specs.Spec
 
 	// bind handler
 	m := http.NewServeMux()
-	m.Handle("/metrics", http.HandlerFunc(s.serveMetrics))
-	m.Handle("/agent-url", http.HandlerFunc(s.agentURL))
+	// the requests of the handlers to the sandbox are bounded
+	handle := func(pattern string, handler http.HandlerFunc) {
+		m.Handle(pattern, withRequestTimeout(handler))
+	}
+	handle("/metrics", s.serveMetrics)
+	handle("/agent-url", s.agentURL)
+	handle("/version", s.shimVersion)
+	handle("/status", s.sandboxStatus)
+	handle("/stats", s.sandboxStats)
+	handle("/agent-apis", s.agentAllowedAPIs)
+	handle("/audit", s.auditLog)
+	handle("/ephemeral-disk", s.ephemeralDiskStatus)
+	handle("/guest-protection", s.guestProtectionStatus)
+	handle("/hypervisor-invocation", s.hypervisorInvocation)
+	handle("/factory", s.factoryStatus)
+	// rebuilding the VM template and the attach sessions may take longer
+	m.Handle("/factory/flush", http.HandlerFunc(s.factoryFlush))
+	m.Handle("/factory/rebuild", http.HandlerFunc(s.factoryRebuild))
+	m.Handle("/containers/", http.HandlerFunc(s.containerAttach))
+	handle("/devices", s.hotplugDevice)
+	handle("/devices/leaks", s.deviceLeaks)
+	handle("/network-policy", s.networkPolicy)
+	handle("/agent-loglevel", s.agentLogLevel)
+	handle("/debug-console", s.debugConsole)
+	m.Handle("/loglevel", mutils.NewLogLevelHandler(shimLog.Logger))
 	s.mountPprofHandle(m, ociSpec)
 
-	// register shim metrics
-	registerMetrics()
+	s.setTargetInfo()
+
+	// start serve, the requests are canceled when the service stops. The
+	// write timeout is set per request, the WriteTimeout of the server
+	// would cut off the long ones and fail the longer pprof profiles.
+	svr := &http.Server{
+		Handler:           withWriteTimeout(m),
+		ReadHeaderTimeout: managementReadTimeout,
+		ReadTimeout:       managementReadTimeout,
+		BaseContext: func(net.Listener) context.Context {
+			return s.ctx
+		},
+		ConnContext: func(ctx context.Context, c net.Conn) context.Context {
+			return context.WithValue(ctx, managementConnKey{}, c)
+		},
+	}
+
+	s.managementMu.Lock()
+	if s.ctx.Err() != nil {
+		// stopped in the meantime
+		s.managementMu.Unlock()
+		listener.Close()
+		return
+	}
+	s.managementServer = svr
+	s.managementMu.Unlock()
+
+	if err := svr.Serve(listener); err != nil && err != http.ErrServerClosed {
+		shimMgtLog.WithError(err).Error("management server failed")
+	}
+}
+
+// stopManagementServer closes the management server, the requests in
+// progress being given a short time to complete.
+func (s *service) stopManagementServer() {
+	s.managementMu.Lock()
+	svr := s.managementServer
+	s.managementMu.Unlock()
+
+	if svr == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), managementShutdownTimeout)
+	defer cancel()
+	if err := svr.Shutdown(ctx); err != nil {
+		shimMgtLog.WithError(err).Warn("failed to shut down the management server")
+		svr.Close()
+	}
+}
+
+// withRequestTimeout bounds the requests made by a handler to the sandbox
+// with the context of the request.
+func withRequestTimeout(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), managementRequestTimeout)
+		defer cancel()
+
+		handler.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
 
-	// register sandbox metrics
-	vc.RegisterMetrics()
+// managementConnKey is the context key of the connection of a request.
+type managementConnKey struct{}
+
+// withWriteTimeout sets the write deadline of the connection of each
+// request from its writeTimeout, the deadline of the previous request on
+// the connection being replaced.
+func withWriteTimeout(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if conn, ok := r.Context().Value(managementConnKey{}).(net.Conn); ok {
+			var deadline time.Time
+			if timeout := writeTimeout(r); timeout > 0 {
+				deadline = time.Now().Add(timeout)
+			}
+			conn.SetWriteDeadline(deadline)
+		}
+
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// writeTimeout returns the time given to a request to write its response,
+// no limit when zero.
+func writeTimeout(r *http.Request) time.Duration {
+	path := r.URL.Path
+	switch {
+	case path == "/factory/flush" || path == "/factory/rebuild" || strings.HasPrefix(path, "/containers/"):
+		return 0
+	case path == "/debug/pprof/profile" || path == "/debug/pprof/trace":
+		// the response is written once the profile is taken, for the
+		// given seconds, 30 by default for a CPU profile
+		seconds, err := strconv.ParseFloat(r.URL.Query().Get("seconds"), 64)
+		if err != nil || seconds <= 0 {
+			seconds = 30
+		}
+		timeout := time.Duration(seconds*float64(time.Second)) + managementWriteTimeout
+		if timeout < managementWriteTimeout {
+			// overflow
+			return 0
+		}
+		return timeout
+	}
 
-	// start serve
-	svr := &http.Server{Handler: m}
-	svr.Serve(listener)
+	return managementWriteTimeout
 }
 
 // mountPprofHandle provides a debug endpoint
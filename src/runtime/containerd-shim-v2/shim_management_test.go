@@ -6,15 +6,29 @@
 package containerdshim
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/oci"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/vcmock"
 
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func TestServeMetrics(t *testing.T) {
@@ -51,6 +65,28 @@ go_threads 23
 
 	assert.Equal(true, strings.Contains(body, "kata_agent_go_threads 23\n"))
 
+	// the protobuf delimited format is negotiated, e.g. by kata-monitor
+	rr = httptest.NewRecorder()
+	r = &http.Request{Header: http.Header{"Accept": []string{string(expfmt.FmtProtoDelim)}}}
+	s.serveMetrics(rr, r)
+	assert.Equal(string(expfmt.FmtProtoDelim), rr.Header().Get("Content-Type"))
+
+	found := false
+	decoder := expfmt.NewDecoder(rr.Body, expfmt.FmtProtoDelim)
+	for {
+		mf := &dto.MetricFamily{}
+		if err := decoder.Decode(mf); err != nil {
+			assert.Equal(io.EOF, err)
+			break
+		}
+		if mf.GetName() == "kata_agent_go_threads" {
+			found = true
+			assert.Equal(float64(23), mf.Metric[0].GetGauge().GetValue())
+		}
+	}
+	assert.True(found)
+	r = &http.Request{}
+
 	// case 2: GetAgentMetricsFunc return error
 	sandbox.GetAgentMetricsFunc = func() (string, error) {
 		return "", fmt.Errorf("some error occurred")
@@ -60,4 +96,403 @@ go_threads 23
 	assert.Equal(200, rr.Code, "response code should be 200")
 	body = rr.Body.String()
 	assert.Equal(true, len(strings.Split(body, "\n")) > 0)
+	assert.False(strings.Contains(body, AgentMetricsUnavailableName))
+
+	// case 3: the agent API allow-list denies the metrics
+	sandbox.GetAgentMetricsFunc = func() (string, error) {
+		return "", status.Error(codes.PermissionDenied, "GetMetricsRequest is blocked")
+	}
+
+	rr = httptest.NewRecorder()
+	s.serveMetrics(rr, r)
+	assert.Equal(200, rr.Code, "response code should be 200")
+	assert.Contains(rr.Body.String(), AgentMetricsUnavailableName+`{reason="PermissionDenied"} 1`)
+}
+
+func TestFactoryEndpoints(t *testing.T) {
+	assert := assert.New(t)
+
+	s := &service{
+		id:         testSandboxID,
+		sandbox:    &vcmock.Sandbox{MockID: testSandboxID},
+		containers: make(map[string]*container),
+		config:     &oci.RuntimeConfig{},
+	}
+
+	// factory not enabled
+	rr := httptest.NewRecorder()
+	s.factoryStatus(rr, httptest.NewRequest(http.MethodGet, "/factory", nil))
+	assert.Equal(http.StatusNotFound, rr.Code)
+
+	rr = httptest.NewRecorder()
+	s.factoryFlush(rr, httptest.NewRequest(http.MethodPost, "/factory/flush", nil))
+	assert.Equal(http.StatusNotFound, rr.Code)
+
+	// only POST can update the template
+	s.config.FactoryConfig.Template = true
+	rr = httptest.NewRecorder()
+	s.factoryRebuild(rr, httptest.NewRequest(http.MethodGet, "/factory/rebuild", nil))
+	assert.Equal(http.StatusMethodNotAllowed, rr.Code)
+
+	// with the management token only
+	rr = httptest.NewRecorder()
+	s.factoryRebuild(rr, httptest.NewRequest(http.MethodPost, "/factory/rebuild", nil))
+	assert.Equal(http.StatusForbidden, rr.Code)
+
+	dir, err := ioutil.TempDir("", "management-token")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+	s.config.ManagementTokenFile = filepath.Join(dir, "token")
+	assert.NoError(ioutil.WriteFile(s.config.ManagementTokenFile, []byte("secret\n"), 0600))
+
+	r := httptest.NewRequest(http.MethodPost, "/factory/flush", nil)
+	r.Header.Set("Authorization", "Bearer wrong")
+	rr = httptest.NewRecorder()
+	s.factoryFlush(rr, r)
+	assert.Equal(http.StatusUnauthorized, rr.Code)
+}
+
+func TestAuditLog(t *testing.T) {
+	assert := assert.New(t)
+
+	sandbox := &vcmock.Sandbox{
+		MockID: testSandboxID,
+	}
+
+	s := &service{
+		id:         testSandboxID,
+		sandbox:    sandbox,
+		containers: make(map[string]*container),
+	}
+
+	sandbox.GetAuditLogFunc = func() ([]vc.AuditRecord, error) {
+		return []vc.AuditRecord{{Sandbox: testSandboxID, Operation: vc.AuditExec}}, nil
+	}
+
+	rr := httptest.NewRecorder()
+	s.auditLog(rr, httptest.NewRequest(http.MethodGet, "/audit", nil))
+	assert.Equal(http.StatusOK, rr.Code)
+
+	var records []vc.AuditRecord
+	assert.NoError(json.Unmarshal(rr.Body.Bytes(), &records))
+	assert.Len(records, 1)
+	assert.Equal(vc.AuditExec, records[0].Operation)
+
+	sandbox.GetAuditLogFunc = func() ([]vc.AuditRecord, error) {
+		return nil, fmt.Errorf("audit log is not enabled")
+	}
+
+	rr = httptest.NewRecorder()
+	s.auditLog(rr, httptest.NewRequest(http.MethodGet, "/audit", nil))
+	assert.Equal(http.StatusInternalServerError, rr.Code)
+}
+
+func TestEphemeralDiskStatus(t *testing.T) {
+	assert := assert.New(t)
+
+	sandbox := &vcmock.Sandbox{
+		MockID: testSandboxID,
+	}
+
+	s := &service{
+		id:         testSandboxID,
+		sandbox:    sandbox,
+		containers: make(map[string]*container),
+	}
+
+	sandbox.GetEphemeralDiskStatusFunc = func() (vc.EphemeralDiskStatus, error) {
+		return vc.EphemeralDiskStatus{Backend: vc.EphemeralDiskFile, SizeMB: 64, Encrypted: true, Attached: true}, nil
+	}
+
+	rr := httptest.NewRecorder()
+	s.ephemeralDiskStatus(rr, httptest.NewRequest(http.MethodGet, "/ephemeral-disk", nil))
+	assert.Equal(http.StatusOK, rr.Code)
+
+	var status vc.EphemeralDiskStatus
+	assert.NoError(json.Unmarshal(rr.Body.Bytes(), &status))
+	assert.True(status.Encrypted)
+	assert.True(status.Attached)
+
+	sandbox.GetEphemeralDiskStatusFunc = func() (vc.EphemeralDiskStatus, error) {
+		return vc.EphemeralDiskStatus{}, fmt.Errorf("ephemeral disk is not enabled")
+	}
+
+	rr = httptest.NewRecorder()
+	s.ephemeralDiskStatus(rr, httptest.NewRequest(http.MethodGet, "/ephemeral-disk", nil))
+	assert.Equal(http.StatusNotFound, rr.Code)
+}
+
+func TestGuestProtectionStatus(t *testing.T) {
+	assert := assert.New(t)
+
+	sandbox := &vcmock.Sandbox{
+		MockID: testSandboxID,
+	}
+
+	s := &service{
+		id:         testSandboxID,
+		sandbox:    sandbox,
+		containers: make(map[string]*container),
+	}
+
+	sandbox.GetGuestProtectionStatusFunc = func() (vc.GuestProtectionStatus, error) {
+		return vc.GuestProtectionStatus{Confidential: true, Technology: "se", BootImageDigest: "sha256:foo"}, nil
+	}
+
+	rr := httptest.NewRecorder()
+	s.guestProtectionStatus(rr, httptest.NewRequest(http.MethodGet, "/guest-protection", nil))
+	assert.Equal(http.StatusOK, rr.Code)
+
+	var status vc.GuestProtectionStatus
+	assert.NoError(json.Unmarshal(rr.Body.Bytes(), &status))
+	assert.True(status.Confidential)
+	assert.Equal("se", status.Technology)
+	assert.Equal("sha256:foo", status.BootImageDigest)
+
+	sandbox.GetGuestProtectionStatusFunc = func() (vc.GuestProtectionStatus, error) {
+		return vc.GuestProtectionStatus{}, fmt.Errorf("boot image not found")
+	}
+
+	rr = httptest.NewRecorder()
+	s.guestProtectionStatus(rr, httptest.NewRequest(http.MethodGet, "/guest-protection", nil))
+	assert.Equal(http.StatusInternalServerError, rr.Code)
+}
+
+func TestHypervisorInvocation(t *testing.T) {
+	assert := assert.New(t)
+
+	sandbox := &vcmock.Sandbox{
+		MockID: testSandboxID,
+	}
+
+	s := &service{
+		id:         testSandboxID,
+		sandbox:    sandbox,
+		containers: make(map[string]*container),
+	}
+
+	sandbox.GetHypervisorInvocationFunc = func() (vc.HypervisorInvocation, error) {
+		return vc.HypervisorInvocation{
+			Path: "/usr/bin/cloud-hypervisor",
+			Args: []string{"--api-socket", "/run/vc/vm/foo/clh-api.sock"},
+			APICalls: []vc.HypervisorAPICall{
+				{Method: http.MethodPut, Endpoint: "/api/v1/vm.create", Body: json.RawMessage(`{"cpus":{"boot_vcpus":1}}`)},
+				{Method: http.MethodPut, Endpoint: "/api/v1/vm.boot"},
+			},
+		}, nil
+	}
+
+	rr := httptest.NewRecorder()
+	s.hypervisorInvocation(rr, httptest.NewRequest(http.MethodGet, "/hypervisor-invocation", nil))
+	assert.Equal(http.StatusOK, rr.Code)
+
+	var invocation vc.HypervisorInvocation
+	assert.NoError(json.Unmarshal(rr.Body.Bytes(), &invocation))
+	assert.Equal("/usr/bin/cloud-hypervisor", invocation.Path)
+	assert.Len(invocation.APICalls, 2)
+	assert.JSONEq(`{"cpus":{"boot_vcpus":1}}`, string(invocation.APICalls[0].Body))
+
+	sandbox.GetHypervisorInvocationFunc = func() (vc.HypervisorInvocation, error) {
+		return vc.HypervisorInvocation{}, fmt.Errorf("hypervisor invocation not recorded by firecracker")
+	}
+
+	rr = httptest.NewRecorder()
+	s.hypervisorInvocation(rr, httptest.NewRequest(http.MethodGet, "/hypervisor-invocation", nil))
+	assert.Equal(http.StatusNotFound, rr.Code)
+}
+
+func TestAgentAllowedAPIs(t *testing.T) {
+	assert := assert.New(t)
+
+	sandbox := &vcmock.Sandbox{
+		MockID: testSandboxID,
+	}
+
+	s := &service{
+		id:         testSandboxID,
+		sandbox:    sandbox,
+		containers: make(map[string]*container),
+	}
+
+	// all the APIs are allowed
+	rr := httptest.NewRecorder()
+	s.agentAllowedAPIs(rr, httptest.NewRequest(http.MethodGet, "/agent-apis", nil))
+	assert.Equal(http.StatusOK, rr.Code)
+	assert.Equal("[]", strings.TrimSpace(rr.Body.String()))
+
+	sandbox.GetAgentAllowedAPIsFunc = func() []string {
+		return []string{"grpc.CreateSandboxRequest"}
+	}
+
+	rr = httptest.NewRecorder()
+	s.agentAllowedAPIs(rr, httptest.NewRequest(http.MethodGet, "/agent-apis", nil))
+	var apis []string
+	assert.NoError(json.Unmarshal(rr.Body.Bytes(), &apis))
+	assert.Equal([]string{"grpc.CreateSandboxRequest"}, apis)
+}
+
+func TestShimVersion(t *testing.T) {
+	assert := assert.New(t)
+
+	savedVersion, savedCommit := runtimeVersion, runtimeCommit
+	defer SetVersion(savedVersion, savedCommit)
+	SetVersion("2.2.0", "abcdef")
+
+	s := &service{id: testSandboxID}
+
+	rr := httptest.NewRecorder()
+	s.shimVersion(rr, httptest.NewRequest(http.MethodGet, "/version", nil))
+	assert.Equal(http.StatusOK, rr.Code)
+
+	var info VersionInfo
+	assert.NoError(json.Unmarshal(rr.Body.Bytes(), &info))
+	assert.Equal("2.2.0", info.Version)
+	assert.Equal("abcdef", info.Commit)
+	assert.NotEmpty(info.GoVersion)
+	assert.NotZero(info.Pid)
+}
+
+func TestSandboxStatus(t *testing.T) {
+	assert := assert.New(t)
+
+	sandbox := &vcmock.Sandbox{
+		MockID: testSandboxID,
+	}
+
+	s := &service{
+		id:         testSandboxID,
+		sandbox:    sandbox,
+		containers: make(map[string]*container),
+	}
+
+	heartbeat := time.Now().UTC().Truncate(time.Second)
+	sandbox.GetGuestStatusFunc = func() (vc.GuestStatus, error) {
+		return vc.GuestStatus{
+			Uptime:        time.Minute,
+			AgentVersion:  "2.2.0",
+			LastHeartbeat: heartbeat,
+		}, nil
+	}
+
+	rr := httptest.NewRecorder()
+	s.sandboxStatus(rr, httptest.NewRequest(http.MethodGet, "/status", nil))
+	assert.Equal(http.StatusOK, rr.Code)
+
+	var status SandboxStatus
+	assert.NoError(json.Unmarshal(rr.Body.Bytes(), &status))
+	assert.Equal(testSandboxID, status.ID)
+	assert.Equal(time.Minute, status.Guest.Uptime)
+	assert.Equal("2.2.0", status.Guest.AgentVersion)
+	assert.Empty(status.GuestError)
+
+	// the heartbeat is still reported when the agent doesn't answer
+	sandbox.GetGuestStatusFunc = func() (vc.GuestStatus, error) {
+		return vc.GuestStatus{LastHeartbeat: heartbeat}, fmt.Errorf("agent is dead")
+	}
+
+	rr = httptest.NewRecorder()
+	s.sandboxStatus(rr, httptest.NewRequest(http.MethodGet, "/status", nil))
+	assert.Equal(http.StatusOK, rr.Code)
+
+	status = SandboxStatus{}
+	assert.NoError(json.Unmarshal(rr.Body.Bytes(), &status))
+	assert.Equal("agent is dead", status.GuestError)
+	assert.True(heartbeat.Equal(status.Guest.LastHeartbeat))
+}
+
+func TestWithRequestTimeout(t *testing.T) {
+	assert := assert.New(t)
+
+	var deadline time.Time
+	var ok bool
+	handler := withRequestTimeout(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		deadline, ok = r.Context().Deadline()
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.True(ok)
+	assert.WithinDuration(time.Now().Add(managementRequestTimeout), deadline, time.Second)
+}
+
+func TestWriteTimeout(t *testing.T) {
+	assert := assert.New(t)
+
+	for path, timeout := range map[string]time.Duration{
+		"/metrics":                          managementWriteTimeout,
+		"/nothing-here":                     managementWriteTimeout,
+		"/factory/rebuild":                  0,
+		"/containers/foo/attach":            0,
+		"/debug/pprof/profile":              30*time.Second + managementWriteTimeout,
+		"/debug/pprof/profile?seconds=120":  120*time.Second + managementWriteTimeout,
+		"/debug/pprof/trace?seconds=0.5":    500*time.Millisecond + managementWriteTimeout,
+		"/debug/pprof/trace?seconds=1e300":  0,
+		"/debug/pprof/profile?seconds=oops": 30*time.Second + managementWriteTimeout,
+	} {
+		assert.Equal(timeout, writeTimeout(httptest.NewRequest(http.MethodGet, path, nil)), path)
+	}
+
+	// the write deadline of the connection is replaced for each request
+	conn := &deadlineConn{deadline: time.Now()}
+	ctx := context.WithValue(context.Background(), managementConnKey{}, conn)
+	handler := withWriteTimeout(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/metrics", nil).WithContext(ctx))
+	assert.WithinDuration(time.Now().Add(managementWriteTimeout), conn.deadline, time.Second)
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/factory/rebuild", nil).WithContext(ctx))
+	assert.True(conn.deadline.IsZero())
+}
+
+type deadlineConn struct {
+	net.Conn
+	deadline time.Time
+}
+
+func (c *deadlineConn) SetWriteDeadline(t time.Time) error {
+	c.deadline = t
+	return nil
+}
+
+func TestStopManagementServer(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &service{
+		id:     testSandboxID,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+
+	// not started
+	s.stopManagementServer()
+
+	// a request stuck on the sandbox
+	started := make(chan struct{})
+	canceled := make(chan struct{})
+	svr := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-r.Context().Done()
+		close(canceled)
+	}))
+	svr.Config.BaseContext = func(net.Listener) context.Context {
+		return s.ctx
+	}
+	svr.Start()
+	defer svr.Close()
+	s.managementServer = svr.Config
+
+	go http.Get(svr.URL)
+	<-started
+
+	s.cancel()
+	s.stopManagementServer()
+
+	select {
+	case <-canceled:
+	case <-time.After(managementShutdownTimeout):
+		t.Fatal("the request was not canceled")
+	}
+
+	_, err := http.Get(svr.URL)
+	assert.Error(err)
 }
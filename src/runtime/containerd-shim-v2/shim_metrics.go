@@ -7,12 +7,20 @@ package containerdshim
 
 import (
 	"context"
+	"path/filepath"
+	goruntime "runtime"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/katautils"
 	mutils "github.com/kata-containers/kata-containers/src/runtime/pkg/utils"
 	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
+	vf "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/factory"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/cloudevent"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/oci"
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/prometheus/procfs"
 )
 
 const namespaceKatashim = "kata_shim"
@@ -71,118 +79,401 @@ var (
 		Help:      "Kata containerd shim v2 open FDs.",
 	})
 
-	katashimPodOverheadCPU = prometheus.NewGauge(prometheus.GaugeOpts{
+	katashimBuildInfo = prometheus.NewGaugeVec(prometheus.GaugeOpts{
 		Namespace: namespaceKatashim,
-		Name:      "pod_overhead_cpu",
-		Help:      "Kata Pod overhead for CPU resources(percent).",
-	})
+		Name:      "build_info",
+		Help:      "Kata containerd shim v2 build information(version, commit, Go version and hypervisor support).",
+	},
+		[]string{"version", "commit", "go_version", "hypervisor", "confidential_guest"},
+	)
 
-	katashimPodOverheadMemory = prometheus.NewGauge(prometheus.GaugeOpts{
+	katashimIODroppedBytes = prometheus.NewCounterVec(prometheus.CounterOpts{
 		Namespace: namespaceKatashim,
-		Name:      "pod_overhead_memory_in_bytes",
-		Help:      "Kata Pod overhead for memory resources(bytes).",
-	})
+		Name:      "io_dropped_bytes_total",
+		Help:      "Bytes of the containers IO streams dropped because they could not be forwarded.",
+	},
+		[]string{"stream"},
+	)
+
+	katashimIOBlockedSeconds = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespaceKatashim,
+		Name:      "io_blocked_seconds_total",
+		Help:      "Time the forwarding of the containers IO streams waited for the destination to drain(seconds).",
+	},
+		[]string{"stream"},
+	)
 )
 
-func registerMetrics() {
-	prometheus.MustRegister(rpcDurationsHistogram)
-	prometheus.MustRegister(katashimThreads)
-	prometheus.MustRegister(katashimProcStatus)
-	prometheus.MustRegister(katashimProcStat)
-	prometheus.MustRegister(katashimNetdev)
-	prometheus.MustRegister(katashimIOStat)
-	prometheus.MustRegister(katashimOpenFDs)
-	prometheus.MustRegister(katashimPodOverheadCPU)
-	prometheus.MustRegister(katashimPodOverheadMemory)
+// shimMetrics is the registry of the metrics exposed for a sandbox. It is
+// only populated on the first scrape, so an idle shim doesn't hold the
+// registered collectors. Each service has its own, as a shim group serves
+// several sandboxes: the metrics of the shim process are shared by all of
+// them, the others are the ones of the sandbox.
+type shimMetrics struct {
+	sync.Mutex
+	registry *prometheus.Registry
+	groups   map[string]bool
+
+	// containerMetricsMu serializes the updates of the per container metrics
+	containerMetricsMu sync.Mutex
+
+	podOverheadCPU         prometheus.Gauge
+	podOverheadMemory      prometheus.Gauge
+	vmBootDuration         prometheus.Gauge
+	deviceLeaks            prometheus.Gauge
+	targetInfo             *prometheus.GaugeVec
+	containerCPUTime       *prometheus.GaugeVec
+	containerMemory        *prometheus.GaugeVec
+	containerPids          *prometheus.GaugeVec
+	containerCPUThrottling *prometheus.GaugeVec
+	sandboxCPUThrottling   *prometheus.GaugeVec
+	containerMemoryEvents  *prometheus.GaugeVec
 }
 
-// updateShimMetrics will update metrics for kata shim process itself
-func updateShimMetrics() error {
-	proc, err := procfs.Self()
-	if err != nil {
-		return err
+// newShimMetrics creates the metrics of a sandbox.
+func newShimMetrics() *shimMetrics {
+	return &shimMetrics{
+		podOverheadCPU: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespaceKatashim,
+			Name:      "pod_overhead_cpu",
+			Help:      "Kata Pod overhead for CPU resources(percent).",
+		}),
+		podOverheadMemory: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespaceKatashim,
+			Name:      "pod_overhead_memory_in_bytes",
+			Help:      "Kata Pod overhead for memory resources(bytes).",
+		}),
+		vmBootDuration: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespaceKatashim,
+			Name:      "vm_boot_duration_seconds",
+			Help:      "Time to create the sandbox and boot its VM(seconds).",
+		}),
+		deviceLeaks: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespaceKatashim,
+			Name:      "device_leaks",
+			Help:      "Devices attached to the VM more or less times than they are used, at the last check.",
+		}),
+		targetInfo: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespaceKatashim,
+			Name:      "target_info",
+			Help:      "Kata sandbox metadata(runtime version, hypervisor type and guest kernel).",
+		},
+			[]string{"runtime_version", "hypervisor", "kernel_version"},
+		),
+		containerCPUTime: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespaceKatashim,
+			Name:      "container_cpu_time",
+			Help:      "CPU time consumed by the container in the guest(nanoseconds).",
+		},
+			[]string{"container_id", "container_name", "item"},
+		),
+		containerMemory: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespaceKatashim,
+			Name:      "container_memory",
+			Help:      "Memory consumed by the container in the guest(bytes).",
+		},
+			[]string{"container_id", "container_name", "item"},
+		),
+		containerPids: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespaceKatashim,
+			Name:      "container_pids",
+			Help:      "Processes of the container in the guest.",
+		},
+			[]string{"container_id", "container_name", "item"},
+		),
+		containerCPUThrottling: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespaceKatashim,
+			Name:      "container_cpu_throttling",
+			Help:      "CPU throttling of the container by its cgroup in the guest(periods, nanoseconds).",
+		},
+			[]string{"container_id", "container_name", "item"},
+		),
+		sandboxCPUThrottling: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespaceKatashim,
+			Name:      "sandbox_cpu_throttling",
+			Help:      "CPU throttling of the vCPUs by the sandbox cgroup in the host(periods, nanoseconds).",
+		},
+			[]string{"item"},
+		),
+		containerMemoryEvents: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespaceKatashim,
+			Name:      "container_memory_events",
+			Help:      "Memory events of the container in the guest(memory.events counters).",
+		},
+			[]string{"container_id", "container_name", "event"},
+		),
 	}
+}
+
+// gatherer returns the registry of the metrics of the sandbox,
+// registering the groups not registered yet.
+func (m *shimMetrics) gatherer(sandbox vc.VCSandbox, groups []string) prometheus.Gatherer {
+	m.Lock()
+	defer m.Unlock()
+
+	if m.registry == nil {
+		m.registry = prometheus.NewRegistry()
+		m.groups = make(map[string]bool)
+
+		m.registry.MustRegister(prometheus.NewGoCollector())
+		m.registry.MustRegister(prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}))
+		m.registry.MustRegister(rpcDurationsHistogram)
+		m.registry.MustRegister(m.targetInfo)
+		m.registry.MustRegister(katashimBuildInfo)
+		m.registry.MustRegister(m.vmBootDuration)
+		m.registry.MustRegister(m.deviceLeaks)
 
-	// metrics about open FDs
-	if fds, err := proc.FileDescriptorsLen(); err == nil {
-		katashimOpenFDs.Set(float64(fds))
+		// sandbox metrics
+		sandbox.RegisterMetrics(m.registry)
+
+		// VM factory metrics
+		vf.RegisterMetrics(m.registry)
+
+		// OCI hooks metrics
+		katautils.RegisterHookMetrics(m.registry)
+
+		// CloudEvents delivery metrics
+		cloudevent.RegisterMetrics(m.registry)
 	}
 
-	// network device metrics
-	if netdev, err := proc.NetDev(); err == nil {
-		// netdev: map[string]NetDevLine
-		for _, v := range netdev {
-			mutils.SetGaugeVecNetDev(katashimNetdev, v)
+	for _, group := range katautils.ShimMetricsGroups {
+		if m.groups[group] || !metricsGroupEnabled(groups, group) {
+			continue
+		}
+		m.groups[group] = true
+
+		switch group {
+		case katautils.ShimMetricsGroupShim:
+			m.registry.MustRegister(katashimThreads)
+			m.registry.MustRegister(katashimProcStatus)
+			m.registry.MustRegister(katashimProcStat)
+			m.registry.MustRegister(katashimNetdev)
+			m.registry.MustRegister(katashimIOStat)
+			m.registry.MustRegister(katashimOpenFDs)
+			m.registry.MustRegister(katashimIODroppedBytes)
+			m.registry.MustRegister(katashimIOBlockedSeconds)
+			logMissingProcStatusItems()
+		case katautils.ShimMetricsGroupHypervisor:
+			sandbox.RegisterProcessMetrics(m.registry)
+		case katautils.ShimMetricsGroupContainers:
+			m.registry.MustRegister(m.containerCPUTime)
+			m.registry.MustRegister(m.containerMemory)
+			m.registry.MustRegister(m.containerPids)
+			m.registry.MustRegister(m.containerCPUThrottling)
+			m.registry.MustRegister(m.sandboxCPUThrottling)
+			m.registry.MustRegister(m.containerMemoryEvents)
+		case katautils.ShimMetricsGroupOverhead:
+			m.registry.MustRegister(m.podOverheadCPU)
+			m.registry.MustRegister(m.podOverheadMemory)
+		case katautils.ShimMetricsGroupStorage:
+			sandbox.RegisterStorageMetrics(m.registry)
 		}
 	}
 
-	// proc stat
-	if procStat, err := proc.Stat(); err == nil {
-		katashimThreads.Set(float64(procStat.NumThreads))
-		mutils.SetGaugeVecProcStat(katashimProcStat, procStat)
+	return m.registry
+}
+
+// metricsGroupEnabled returns true if group is one of groups,
+// all the groups are enabled when groups is empty.
+func metricsGroupEnabled(groups []string, group string) bool {
+	if len(groups) == 0 {
+		return true
 	}
 
-	// proc status
-	if procStatus, err := proc.NewStatus(); err == nil {
-		mutils.SetGaugeVecProcStatus(katashimProcStatus, procStatus)
+	for _, g := range groups {
+		if g == group {
+			return true
+		}
 	}
 
-	// porc IO stat
-	if ioStat, err := proc.IO(); err == nil {
-		mutils.SetGaugeVecProcIO(katashimIOStat, ioStat)
+	return false
+}
+
+// metrics returns the metrics of the sandbox served by the service.
+func (s *service) metrics() *shimMetrics {
+	s.metricsOnce.Do(func() {
+		s.shimMetrics = newShimMetrics()
+	})
+	return s.shimMetrics
+}
+
+// metricsGroups returns the groups of optional metrics exposed for the sandbox
+func (s *service) metricsGroups() []string {
+	if s.config == nil {
+		return nil
 	}
+	return s.config.ShimMetricsGroups
+}
 
-	return nil
+// setTargetInfo records the static metadata of the sandbox as an info metric,
+// so the metrics of a sandbox can be grouped by runtime version, hypervisor and kernel.
+// The build of the shim binary is recorded as well, to track the shim versions
+// running on the nodes during the runtime upgrades.
+func (s *service) setTargetInfo() {
+	kernelVersion := guestKernelVersion(s.config.HypervisorConfig.KernelPath)
+
+	targetInfo := s.metrics().targetInfo
+	targetInfo.Reset()
+	targetInfo.WithLabelValues(runtimeVersion, string(s.config.HypervisorType), kernelVersion).Set(1)
+
+	katashimBuildInfo.Reset()
+	katashimBuildInfo.WithLabelValues(runtimeVersion, runtimeCommit, goruntime.Version(),
+		string(s.config.HypervisorType), strconv.FormatBool(s.config.HypervisorConfig.ConfidentialGuest)).Set(1)
 }
 
-// statsSandbox returns a detailed sandbox stats.
-func (s *service) statsSandbox(ctx context.Context) (vc.SandboxStats, []vc.ContainerStats, error) {
-	sandboxStats, err := s.sandbox.Stats(ctx)
+// guestKernelVersion returns the version of the guest kernel, derived from the
+// name of the kernel image (e.g. vmlinux-5.10.25-85 => 5.10.25-85).
+func guestKernelVersion(kernelPath string) string {
+	if kernelPath == "" {
+		return "unknown"
+	}
+
+	if resolved, err := filepath.EvalSymlinks(kernelPath); err == nil {
+		kernelPath = resolved
+	}
+
+	name := filepath.Base(kernelPath)
+	if idx := strings.Index(name, "-"); idx >= 0 && idx < len(name)-1 {
+		return name[idx+1:]
+	}
+
+	return name
+}
+
+// updateContainerMetrics updates the per container metrics, so the resources
+// consumed by the main container and its sidecars in the guest can be told apart.
+func (s *service) updateContainerMetrics(ctx context.Context) {
+	s.containersMu.RLock()
+	names := make(map[string]string, len(s.containers))
+	memoryEvents := make(map[string]map[string]uint64)
+	for id, c := range s.containers {
+		name := ""
+		if c.spec != nil {
+			name = oci.ContainerName(*c.spec)
+		}
+		names[id] = name
+
+		c.mu.Lock()
+		for event, count := range c.memoryEvents {
+			if memoryEvents[id] == nil {
+				memoryEvents[id] = make(map[string]uint64)
+			}
+			memoryEvents[id][event] = count
+		}
+		c.mu.Unlock()
+	}
+	s.containersMu.RUnlock()
+
+	// the stats are collected before touching the metrics, a scrape in the
+	// meantime still sees the previous values
+	stats, err := s.sandbox.StatsSandbox(ctx)
+	if err != nil {
+		shimMgtLog.WithError(err).Debug("failed to get sandbox stats")
+	}
+
+	m := s.metrics()
+	m.containerMetricsMu.Lock()
+	defer m.containerMetricsMu.Unlock()
+
+	// drop the series of the deleted containers
+	m.containerCPUTime.Reset()
+	m.containerMemory.Reset()
+	m.containerPids.Reset()
+	m.containerCPUThrottling.Reset()
+	m.sandboxCPUThrottling.Reset()
+	m.containerMemoryEvents.Reset()
+
+	for id, events := range memoryEvents {
+		for event, count := range events {
+			m.containerMemoryEvents.WithLabelValues(id, names[id], event).Set(float64(count))
+		}
+	}
+
 	if err != nil {
-		return vc.SandboxStats{}, []vc.ContainerStats{}, err
+		return
 	}
 
-	containerStats := []vc.ContainerStats{}
-	for _, c := range s.sandbox.GetAllContainers() {
-		cstats, err := s.sandbox.StatsContainer(ctx, c.ID())
-		if err != nil {
-			return vc.SandboxStats{}, []vc.ContainerStats{}, err
+	setThrottlingMetrics(m.sandboxCPUThrottling.MustCurryWith(prometheus.Labels{}), stats.VM.CgroupStats.CPUStats.ThrottlingData)
+
+	for id, name := range names {
+		if cstats, ok := stats.PerContainer[id]; ok {
+			m.setContainerMetrics(id, name, cstats)
 		}
-		containerStats = append(containerStats, cstats)
 	}
+}
+
+func (m *shimMetrics) setContainerMetrics(id, name string, stats vc.ContainerStats) {
+	if stats.CgroupStats == nil {
+		return
+	}
+
+	cpuUsage := stats.CgroupStats.CPUStats.CPUUsage
+	m.containerCPUTime.WithLabelValues(id, name, "total").Set(float64(cpuUsage.TotalUsage))
+	m.containerCPUTime.WithLabelValues(id, name, "user").Set(float64(cpuUsage.UsageInUsermode))
+	m.containerCPUTime.WithLabelValues(id, name, "kernel").Set(float64(cpuUsage.UsageInKernelmode))
+
+	memory := stats.CgroupStats.MemoryStats
+	m.containerMemory.WithLabelValues(id, name, "usage").Set(float64(memory.Usage.Usage))
+	m.containerMemory.WithLabelValues(id, name, "max_usage").Set(float64(memory.Usage.MaxUsage))
+	m.containerMemory.WithLabelValues(id, name, "limit").Set(float64(memory.Usage.Limit))
+	m.containerMemory.WithLabelValues(id, name, "cache").Set(float64(memory.Cache))
+
+	pids := stats.CgroupStats.PidsStats
+	m.containerPids.WithLabelValues(id, name, "current").Set(float64(pids.Current))
+	m.containerPids.WithLabelValues(id, name, "limit").Set(float64(pids.Limit))
+
+	setThrottlingMetrics(m.containerCPUThrottling.MustCurryWith(prometheus.Labels{"container_id": id, "container_name": name}), stats.CgroupStats.CPUStats.ThrottlingData)
+}
 
-	return sandboxStats, containerStats, nil
+// setThrottlingMetrics sets the CPU throttling of a cgroup, in the host or
+// in the guest, so the throttling at both levels can be compared.
+func setThrottlingMetrics(metric *prometheus.GaugeVec, throttling vc.ThrottlingData) {
+	metric.WithLabelValues("periods").Set(float64(throttling.Periods))
+	metric.WithLabelValues("throttled_periods").Set(float64(throttling.ThrottledPeriods))
+	metric.WithLabelValues("throttled_time").Set(float64(throttling.ThrottledTime))
 }
 
-func calcOverhead(initialSandboxStats, finishSandboxStats vc.SandboxStats, initialContainerStats, finishContainersStats []vc.ContainerStats, deltaTime float64) (float64, float64) {
-	hostInitCPU := initialSandboxStats.CgroupStats.CPUStats.CPUUsage.TotalUsage
-	guestInitCPU := uint64(0)
-	for _, cs := range initialContainerStats {
-		guestInitCPU += cs.CgroupStats.CPUStats.CPUUsage.TotalUsage
+// logMissingProcStatusItems logs the proc_status items which are not
+// exported as their fields are not present on the running kernel.
+func logMissingProcStatusItems() {
+	collector, err := mutils.HostProcCollector()
+	if err != nil {
+		return
 	}
 
-	hostFinalCPU := finishSandboxStats.CgroupStats.CPUStats.CPUUsage.TotalUsage
-	guestFinalCPU := uint64(0)
-	for _, cs := range finishContainersStats {
-		guestFinalCPU += cs.CgroupStats.CPUStats.CPUUsage.TotalUsage
+	if missing := collector.MissingStatusFields(); len(missing) > 0 {
+		shimMgtLog.WithField("kernel", collector.Kernel()).WithField("items", missing).Info("proc_status items not available on this kernel are not exported")
 	}
+}
 
-	var guestMemoryUsage uint64
-	for _, cs := range finishContainersStats {
-		guestMemoryUsage += cs.CgroupStats.MemoryStats.Usage.Usage
+// updateShimMetrics will update metrics for kata shim process itself
+func updateShimMetrics() error {
+	collector, err := mutils.HostProcCollector()
+	if err != nil {
+		return err
 	}
 
-	hostMemoryUsage := finishSandboxStats.CgroupStats.MemoryStats.Usage.Usage
+	return collector.CollectSelf(mutils.ProcMetrics{
+		OpenFDs:    katashimOpenFDs,
+		Threads:    katashimThreads,
+		NetDev:     katashimNetdev,
+		ProcStat:   katashimProcStat,
+		ProcStatus: katashimProcStatus,
+		ProcIO:     katashimIOStat,
+	})
+}
 
-	cpuUsageGuest := float64(guestFinalCPU-guestInitCPU) / deltaTime * 100
-	cpuUsageHost := float64(hostFinalCPU-hostInitCPU) / deltaTime * 100
+// calcOverhead returns the memory overhead of the pod, in bytes, and its
+// CPU overhead between two stats deltaTime nanoseconds apart, in percent.
+func calcOverhead(initialStats, finishStats vc.PodStats, deltaTime float64) (float64, float64) {
+	cpuTime := float64(finishStats.Overhead.CPUTime) - float64(initialStats.Overhead.CPUTime)
 
-	return float64(hostMemoryUsage - guestMemoryUsage), cpuUsageHost - cpuUsageGuest
+	return float64(finishStats.Overhead.Memory), cpuTime / deltaTime * 100
 }
 
 func (s *service) getPodOverhead(ctx context.Context) (float64, float64, error) {
 	initTime := time.Now().UnixNano()
-	initialSandboxStats, initialContainerStats, err := s.statsSandbox(ctx)
+	initialStats, err := s.sandbox.StatsSandbox(ctx)
 	if err != nil {
 		return 0, 0, err
 	}
@@ -192,11 +483,11 @@ func (s *service) getPodOverhead(ctx context.Context) (float64, float64, error)
 	finishtTime := time.Now().UnixNano()
 	deltaTime := float64(finishtTime - initTime)
 
-	finishSandboxStats, finishContainersStats, err := s.statsSandbox(ctx)
+	finishStats, err := s.sandbox.StatsSandbox(ctx)
 	if err != nil {
 		return 0, 0, err
 	}
-	mem, cpu := calcOverhead(initialSandboxStats, finishSandboxStats, initialContainerStats, finishContainersStats, deltaTime)
+	mem, cpu := calcOverhead(initialStats, finishStats, deltaTime)
 	return mem, cpu, nil
 }
 
@@ -205,7 +496,7 @@ func (s *service) setPodOverheadMetrics(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
-	katashimPodOverheadMemory.Set(mem)
-	katashimPodOverheadCPU.Set(cpu)
+	s.metrics().podOverheadMemory.Set(mem)
+	s.metrics().podOverheadCPU.Set(cpu)
 	return nil
 }
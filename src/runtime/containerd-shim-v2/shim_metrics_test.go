@@ -7,10 +7,16 @@ package containerdshim
 
 import (
 	"context"
+	goruntime "runtime"
 	"testing"
 
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/katautils"
 	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/oci"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/vcmock"
+	"github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -84,23 +90,24 @@ func TestStatsSandbox(t *testing.T) {
 		containers: make(map[string]*container),
 	}
 
-	initialSandboxStats, initialContainerStats, err := s.statsSandbox(context.Background())
+	initialStats, err := s.sandbox.StatsSandbox(context.Background())
 	assert.Nil(err)
-	assert.Equal(uint64(1000*1e9), initialSandboxStats.CgroupStats.CPUStats.CPUUsage.TotalUsage)
-	assert.Equal(2, len(initialContainerStats))
-	assert.Equal(uint64(100*1e9), initialContainerStats[0].CgroupStats.CPUStats.CPUUsage.TotalUsage)
-	assert.Equal(uint64(200*1e9), initialContainerStats[1].CgroupStats.CPUStats.CPUUsage.TotalUsage)
-	assert.Equal(uint64(10000), initialContainerStats[0].CgroupStats.MemoryStats.Usage.Usage)
-	assert.Equal(uint64(20000), initialContainerStats[1].CgroupStats.MemoryStats.Usage.Usage)
+	assert.Equal(uint64(1000*1e9), initialStats.VM.CgroupStats.CPUStats.CPUUsage.TotalUsage)
+	assert.Equal(2, len(initialStats.PerContainer))
+	assert.Equal(uint64(100*1e9), initialStats.PerContainer["foo"].CgroupStats.CPUStats.CPUUsage.TotalUsage)
+	assert.Equal(uint64(200*1e9), initialStats.PerContainer["bar"].CgroupStats.CPUStats.CPUUsage.TotalUsage)
+	assert.Equal(uint64(10000), initialStats.PerContainer["foo"].CgroupStats.MemoryStats.Usage.Usage)
+	assert.Equal(uint64(20000), initialStats.PerContainer["bar"].CgroupStats.MemoryStats.Usage.Usage)
+	assert.Equal(uint64(300*1e9), initialStats.Containers.CPUStats.CPUUsage.TotalUsage)
 
 	// get the 2nd stats
 	sandbox.StatsFunc = getSandboxCPUFunc(2000, 110000)
 	sandbox.StatsContainerFunc = getStatsContainerCPUFunc(200, 400, 20000, 40000)
 
-	finishSandboxStats, finishContainersStats, _ := s.statsSandbox(context.Background())
+	finishStats, _ := s.sandbox.StatsSandbox(context.Background())
 
 	// calc overhead
-	mem, cpu := calcOverhead(initialSandboxStats, finishSandboxStats, initialContainerStats, finishContainersStats, 1e9)
+	mem, cpu := calcOverhead(initialStats, finishStats, 1e9)
 
 	// 70000 = (host2.cpu - host1.cpu - (delta containers.1.cpu + delta containers.2.cpu)) * 100
 	//       = (2000 - 1000 - (200 -100 + 400 - 200)) * 100
@@ -113,3 +120,215 @@ func TestStatsSandbox(t *testing.T) {
 	//       = 50000
 	assert.Equal(float64(50000), mem)
 }
+
+func TestGuestKernelVersion(t *testing.T) {
+	assert := assert.New(t)
+
+	testCases := []struct {
+		kernelPath string
+		version    string
+	}{
+		{"", "unknown"},
+		{"/usr/share/kata-containers/vmlinux-5.10.25-85", "5.10.25-85"},
+		{"/usr/share/kata-containers/vmlinuz", "vmlinuz"},
+		{"/usr/share/kata-containers/vmlinuz-", "vmlinuz-"},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(tc.version, guestKernelVersion(tc.kernelPath))
+	}
+}
+
+func TestSetTargetInfo(t *testing.T) {
+	assert := assert.New(t)
+
+	savedVersion, savedCommit := runtimeVersion, runtimeCommit
+	defer SetVersion(savedVersion, savedCommit)
+	SetVersion("2.2.0", "abcdef")
+
+	s := &service{
+		config: &oci.RuntimeConfig{
+			HypervisorType:   vc.QemuHypervisor,
+			HypervisorConfig: vc.HypervisorConfig{ConfidentialGuest: true},
+		},
+	}
+	s.setTargetInfo()
+
+	assert.Equal(1, countMetrics(katashimBuildInfo))
+	assert.Equal(float64(1), gaugeValue(katashimBuildInfo.WithLabelValues("2.2.0", "abcdef", goruntime.Version(), "qemu", "true")))
+	assert.Equal(float64(1), gaugeValue(s.metrics().targetInfo.WithLabelValues("2.2.0", "qemu", "unknown")))
+}
+
+func gaugeValue(g prometheus.Gauge) float64 {
+	m := &dto.Metric{}
+	g.Write(m)
+	return m.GetGauge().GetValue()
+}
+
+func TestUpdateContainerMetrics(t *testing.T) {
+	assert := assert.New(t)
+
+	sandbox := &vcmock.Sandbox{
+		MockID:             testSandboxID,
+		StatsContainerFunc: getStatsContainerCPUFunc(100, 200, 10000, 20000),
+		MockContainers:     []*vcmock.Container{{MockID: "foo"}, {MockID: "bar"}},
+	}
+
+	s := &service{
+		id:      testSandboxID,
+		sandbox: sandbox,
+		containers: map[string]*container{
+			"foo": {
+				spec: &specs.Spec{
+					Annotations: map[string]string{
+						"io.kubernetes.cri.container-name": "app",
+					},
+				},
+			},
+			"bar": {
+				spec: &specs.Spec{},
+			},
+		},
+	}
+
+	s.updateContainerMetrics(context.Background())
+
+	assert.Equal(float64(100*1e9), gaugeValue(s.metrics().containerCPUTime.WithLabelValues("foo", "app", "total")))
+	assert.Equal(float64(10000), gaugeValue(s.metrics().containerMemory.WithLabelValues("foo", "app", "usage")))
+	assert.Equal(float64(200*1e9), gaugeValue(s.metrics().containerCPUTime.WithLabelValues("bar", "", "total")))
+	assert.Equal(float64(20000), gaugeValue(s.metrics().containerMemory.WithLabelValues("bar", "", "usage")))
+
+	// the previous values are kept while the stats are collected
+	statsContainer := sandbox.StatsContainerFunc
+	sandbox.StatsContainerFunc = func(containerID string) (vc.ContainerStats, error) {
+		assert.Equal(6, countMetrics(s.metrics().containerCPUTime))
+		return statsContainer(containerID)
+	}
+
+	// series of the deleted containers are dropped
+	delete(s.containers, "bar")
+	s.updateContainerMetrics(context.Background())
+
+	assert.Equal(3, countMetrics(s.metrics().containerCPUTime))
+}
+
+func TestUpdateCPUThrottlingMetrics(t *testing.T) {
+	assert := assert.New(t)
+
+	sandbox := &vcmock.Sandbox{
+		MockID: testSandboxID,
+		StatsFunc: func() (vc.SandboxStats, error) {
+			var stats vc.SandboxStats
+			stats.CgroupStats.CPUStats.ThrottlingData = vc.ThrottlingData{
+				Periods:          100,
+				ThrottledPeriods: 10,
+				ThrottledTime:    5000,
+			}
+			return stats, nil
+		},
+		StatsContainerFunc: func(contID string) (vc.ContainerStats, error) {
+			return vc.ContainerStats{
+				CgroupStats: &vc.CgroupStats{
+					CPUStats: vc.CPUStats{
+						ThrottlingData: vc.ThrottlingData{
+							Periods:          50,
+							ThrottledPeriods: 20,
+							ThrottledTime:    8000,
+						},
+					},
+				},
+			}, nil
+		},
+		MockContainers: []*vcmock.Container{{MockID: "foo"}},
+	}
+
+	s := &service{
+		id:      testSandboxID,
+		sandbox: sandbox,
+		containers: map[string]*container{
+			"foo": {
+				spec: &specs.Spec{
+					Annotations: map[string]string{
+						"io.kubernetes.cri.container-name": "app",
+					},
+				},
+			},
+		},
+	}
+
+	s.updateContainerMetrics(context.Background())
+
+	// the vCPUs in the host
+	assert.Equal(float64(100), gaugeValue(s.metrics().sandboxCPUThrottling.WithLabelValues("periods")))
+	assert.Equal(float64(10), gaugeValue(s.metrics().sandboxCPUThrottling.WithLabelValues("throttled_periods")))
+	assert.Equal(float64(5000), gaugeValue(s.metrics().sandboxCPUThrottling.WithLabelValues("throttled_time")))
+
+	// the container in the guest
+	assert.Equal(float64(50), gaugeValue(s.metrics().containerCPUThrottling.WithLabelValues("foo", "app", "periods")))
+	assert.Equal(float64(20), gaugeValue(s.metrics().containerCPUThrottling.WithLabelValues("foo", "app", "throttled_periods")))
+	assert.Equal(float64(8000), gaugeValue(s.metrics().containerCPUThrottling.WithLabelValues("foo", "app", "throttled_time")))
+}
+
+func countMetrics(c prometheus.Collector) int {
+	ch := make(chan prometheus.Metric, 100)
+	c.Collect(ch)
+	close(ch)
+	return len(ch)
+}
+
+func TestShimMetricsGatherer(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(metricsGroupEnabled(nil, katautils.ShimMetricsGroupShim))
+	assert.True(metricsGroupEnabled([]string{katautils.ShimMetricsGroupShim}, katautils.ShimMetricsGroupShim))
+	assert.False(metricsGroupEnabled([]string{katautils.ShimMetricsGroupShim}, katautils.ShimMetricsGroupStorage))
+
+	families := func(g prometheus.Gatherer) map[string]bool {
+		mfs, err := g.Gather()
+		assert.NoError(err)
+
+		names := make(map[string]bool)
+		for _, mf := range mfs {
+			names[mf.GetName()] = true
+		}
+		return names
+	}
+
+	m := newShimMetrics()
+	sandbox := &vc.Sandbox{}
+
+	names := families(m.gatherer(sandbox, []string{katautils.ShimMetricsGroupShim}))
+	assert.True(names["kata_shim_threads"])
+	assert.True(names["go_goroutines"])
+	assert.False(names["kata_shim_pod_overhead_cpu"])
+	assert.False(names["kata_hypervisor_threads"])
+
+	// the groups enabled by another sandbox are registered on demand
+	names = families(m.gatherer(sandbox, nil))
+	assert.True(names["kata_shim_threads"])
+	assert.True(names["kata_shim_pod_overhead_cpu"])
+	assert.True(names["kata_hypervisor_threads"])
+}
+
+func TestShimMetricsPerService(t *testing.T) {
+	assert := assert.New(t)
+
+	// the services of a shim group don't share the sandbox metrics
+	s1 := &service{sandbox: &vc.Sandbox{}}
+	s2 := &service{sandbox: &vc.Sandbox{}}
+	for _, s := range []*service{s1, s2} {
+		assert.NotPanics(func() {
+			s.metrics().gatherer(s.sandbox, nil)
+		})
+	}
+
+	s1.metrics().containerCPUTime.WithLabelValues("foo", "app", "total").Set(1)
+	assert.Equal(1, countMetrics(s1.metrics().containerCPUTime))
+	assert.Equal(0, countMetrics(s2.metrics().containerCPUTime))
+
+	mfs, err := s2.metrics().gatherer(s2.sandbox, nil).Gather()
+	assert.NoError(err)
+	for _, mf := range mfs {
+		assert.NotEqual("kata_shim_container_cpu_time", mf.GetName())
+	}
+}
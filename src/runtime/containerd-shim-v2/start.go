@@ -31,6 +31,16 @@ func startContainer(ctx context.Context, s *service, c *container) (retErr error
 		return err
 	}
 
+	// Run start-container OCI hooks on the host, when enabled.
+	if s.hostContainerOCIHooks() {
+		err := katautils.EnterNetNS(s.sandbox.GetNetNs(), func() error {
+			return katautils.StartContainerHooks(katautils.HookContext(ctx, s.sandbox), *c.spec, c.id, c.bundle)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
 	if c.cType.IsSandbox() {
 		err := s.sandbox.Start(ctx)
 		if err != nil {
@@ -46,6 +56,8 @@ func startContainer(ctx context.Context, s *service, c *container) (retErr error
 		// We use s.ctx(`ctx` derived from `s.ctx`) to check for cancellation of the
 		// shim context and the context passed to startContainer for tracing.
 		go watchOOMEvents(ctx, s)
+		go watchMemoryEvents(ctx, s)
+		go watchDeviceLeaks(ctx, s)
 	} else {
 		_, err := s.sandbox.StartContainer(ctx, c.id)
 		if err != nil {
@@ -55,7 +67,7 @@ func startContainer(ctx context.Context, s *service, c *container) (retErr error
 
 	// Run post-start OCI hooks.
 	err := katautils.EnterNetNS(s.sandbox.GetNetNs(), func() error {
-		return katautils.PostStartHooks(ctx, *c.spec, s.sandbox.ID(), c.bundle)
+		return katautils.PostStartHooks(katautils.HookContext(ctx, s.sandbox), *c.spec, s.sandbox.ID(), c.bundle)
 	})
 	if err != nil {
 		// log warning and continue, as defined in oci runtime spec
@@ -63,7 +75,7 @@ func startContainer(ctx context.Context, s *service, c *container) (retErr error
 		shimLog.WithError(err).Warn("Failed to run post-start hooks")
 	}
 
-	c.status = task.StatusRunning
+	c.setStatus(task.StatusRunning)
 
 	stdin, stdout, stderr, err := s.sandbox.IOStream(c.id, c.id)
 	if err != nil {
@@ -100,7 +112,9 @@ func startExec(ctx context.Context, s *service, containerID, execID string) (e *
 		return nil, err
 	}
 
+	c.mu.Lock()
 	execs, err := c.getExec(execID)
+	c.mu.Unlock()
 	if err != nil {
 		return nil, err
 	}
@@ -117,32 +131,36 @@ func startExec(ctx context.Context, s *service, containerID, execID string) (e *
 		err := fmt.Errorf("cannot enter container %s, with err %s", containerID, err)
 		return nil, err
 	}
-	execs.id = proc.Token
 
+	c.mu.Lock()
+	execs.id = proc.Token
 	execs.status = task.StatusRunning
-	if execs.tty.height != 0 && execs.tty.width != 0 {
-		err = s.sandbox.WinsizeProcess(ctx, c.id, execs.id, execs.tty.height, execs.tty.width)
+	height, width := execs.tty.height, execs.tty.width
+	c.mu.Unlock()
+
+	if height != 0 && width != 0 {
+		err = s.sandbox.WinsizeProcess(ctx, c.id, proc.Token, height, width)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	stdin, stdout, stderr, err := s.sandbox.IOStream(c.id, execs.id)
+	stdin, stdout, stderr, err := s.sandbox.IOStream(c.id, proc.Token)
 	if err != nil {
 		return nil, err
 	}
 
-	execs.stdinPipe = stdin
-
 	tty, err := newTtyIO(ctx, execs.tty.stdin, execs.tty.stdout, execs.tty.stderr, execs.tty.terminal)
 	if err != nil {
 		return nil, err
 	}
+
+	c.mu.Lock()
+	execs.stdinPipe = stdin
 	execs.ttyio = tty
+	c.mu.Unlock()
 
 	go ioCopy(execs.exitIOch, execs.stdinCloser, tty, stdin, stdout, stderr)
 
-	go wait(ctx, s, c, execID)
-
 	return execs, nil
 }
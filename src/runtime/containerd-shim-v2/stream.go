@@ -10,12 +10,22 @@ import (
 	"io"
 	"sync"
 	"syscall"
+	"time"
 
 	"github.com/containerd/fifo"
 )
 
-// The buffer size used to specify the buffer for IO streams copy
-const bufSize = 32 << 10
+const (
+	// The buffer size used to specify the buffer for IO streams copy
+	bufSize = 32 << 10
+)
+
+// the streams of a process, as labelled in the IO metrics
+const (
+	streamStdin  = "stdin"
+	streamStdout = "stdout"
+	streamStderr = "stderr"
+)
 
 var (
 	bufPool = sync.Pool{
@@ -91,9 +101,7 @@ func ioCopy(exitch, stdinCloser chan struct{}, tty *ttyIO, stdinPipe io.WriteClo
 	if tty.Stdin != nil {
 		wg.Add(1)
 		go func() {
-			p := bufPool.Get().(*[]byte)
-			defer bufPool.Put(p)
-			io.CopyBuffer(stdinPipe, tty.Stdin, *p)
+			forwardIO(streamStdin, stdinPipe, tty.Stdin, false)
 			// notify that we can close process's io safely.
 			close(stdinCloser)
 			wg.Done()
@@ -104,9 +112,7 @@ func ioCopy(exitch, stdinCloser chan struct{}, tty *ttyIO, stdinPipe io.WriteClo
 		wg.Add(1)
 
 		go func() {
-			p := bufPool.Get().(*[]byte)
-			defer bufPool.Put(p)
-			io.CopyBuffer(tty.Stdout, stdoutPipe, *p)
+			forwardIO(streamStdout, tty.Stdout, stdoutPipe, true)
 			wg.Done()
 			if tty.Stdin != nil {
 				// close stdin to make the other routine stop
@@ -119,9 +125,7 @@ func ioCopy(exitch, stdinCloser chan struct{}, tty *ttyIO, stdinPipe io.WriteClo
 	if tty.Stderr != nil && stderrPipe != nil {
 		wg.Add(1)
 		go func() {
-			p := bufPool.Get().(*[]byte)
-			defer bufPool.Put(p)
-			io.CopyBuffer(tty.Stderr, stderrPipe, *p)
+			forwardIO(streamStderr, tty.Stderr, stderrPipe, true)
 			wg.Done()
 		}()
 	}
@@ -130,3 +134,37 @@ func ioCopy(exitch, stdinCloser chan struct{}, tty *ttyIO, stdinPipe io.WriteClo
 	tty.close()
 	close(exitch)
 }
+
+// forwardIO copies src to dst until src returns EOF or an error, with a
+// single pooled buffer: a slow consumer slows the process down instead of
+// growing the memory of the shim. The time spent writing to dst is
+// accounted in the blocked time metric of the stream.
+//
+// Once a write to dst fails, src is still drained when drain is true, so
+// that the process doesn't block on its output, and the bytes read are
+// accounted as dropped. Otherwise the copy stops.
+func forwardIO(stream string, dst io.Writer, src io.Reader, drain bool) {
+	buf := bufPool.Get().(*[]byte)
+	defer bufPool.Put(buf)
+
+	var writeErr error
+	for {
+		n, err := src.Read(*buf)
+		if n > 0 {
+			if writeErr == nil {
+				start := time.Now()
+				_, writeErr = dst.Write((*buf)[:n])
+				katashimIOBlockedSeconds.WithLabelValues(stream).Add(time.Since(start).Seconds())
+			}
+			if writeErr != nil {
+				katashimIODroppedBytes.WithLabelValues(stream).Add(float64(n))
+				if !drain {
+					return
+				}
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
@@ -6,7 +6,9 @@
 package containerdshim
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"io"
 	"io/ioutil"
 	"path/filepath"
@@ -15,6 +17,8 @@ import (
 	"time"
 
 	"github.com/containerd/fifo"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -277,3 +281,63 @@ func TestIoCopy(t *testing.T) {
 	ioCopyTest("in", "out", "err")
 	ioCopyTest("in", "err", "out")
 }
+
+type failingWriter struct {
+	written int
+	limit   int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if w.written+len(p) > w.limit {
+		return 0, errors.New("broken pipe")
+	}
+	w.written += len(p)
+	return len(p), nil
+}
+
+type slowWriter struct {
+	bytes.Buffer
+}
+
+func (w *slowWriter) Write(p []byte) (int, error) {
+	time.Sleep(time.Millisecond)
+	return w.Buffer.Write(p)
+}
+
+func counterValue(c prometheus.Counter) float64 {
+	m := &dto.Metric{}
+	c.Write(m)
+	return m.GetCounter().GetValue()
+}
+
+func TestForwardIO(t *testing.T) {
+	assert := assert.New(t)
+
+	data := bytes.Repeat([]byte("0123456789abcdef"), 64<<10)
+
+	// all the data is forwarded, in order
+	var out bytes.Buffer
+	forwardIO(streamStdout, &out, bytes.NewReader(data), true)
+	assert.Equal(data, out.Bytes())
+
+	// a slow destination blocks the reads of the source
+	blocked := counterValue(katashimIOBlockedSeconds.WithLabelValues(streamStdout))
+	slow := &slowWriter{}
+	forwardIO(streamStdout, slow, bytes.NewReader(data), true)
+	assert.Equal(data, slow.Bytes())
+	assert.True(counterValue(katashimIOBlockedSeconds.WithLabelValues(streamStdout)) > blocked)
+
+	// the source is drained once the destination fails
+	dropped := counterValue(katashimIODroppedBytes.WithLabelValues(streamStderr))
+	src := bytes.NewReader(data)
+	w := &failingWriter{limit: bufSize}
+	forwardIO(streamStderr, w, src, true)
+	assert.Zero(src.Len())
+	assert.Equal(bufSize, w.written)
+	assert.Equal(float64(len(data)-bufSize), counterValue(katashimIODroppedBytes.WithLabelValues(streamStderr))-dropped)
+
+	// or the copy stops
+	src = bytes.NewReader(data)
+	forwardIO(streamStdin, &failingWriter{}, src, false)
+	assert.NotZero(src.Len())
+}
@@ -101,7 +101,16 @@ func getAddress(ctx context.Context, bundlePath, address, id string) (string, er
 		if err != nil {
 			return "", err
 		}
-		address, err := cdshim.SocketAddress(ctx, address, sandboxID)
+		// the sandbox may be served by a shim group
+		socketID, err := shimGroupOf(ctx, sandboxID)
+		if err != nil {
+			return "", err
+		}
+		if socketID == "" {
+			socketID = sandboxID
+		}
+
+		address, err := cdshim.SocketAddress(ctx, address, socketID)
 		if err != nil {
 			return "", err
 		}
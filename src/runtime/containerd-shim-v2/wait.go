@@ -32,7 +32,9 @@ func wait(ctx context.Context, s *service, c *container, execID string) (int32,
 		//wait until the io closed, then wait the container
 		<-c.exitIOch
 	} else {
+		c.mu.Lock()
 		execs, err = c.getExec(execID)
+		c.mu.Unlock()
 		if err != nil {
 			return exitCode255, err
 		}
@@ -40,7 +42,9 @@ func wait(ctx context.Context, s *service, c *container, execID string) (int32,
 		//This wait could be triggered before exec start which
 		//will get the exec's id, thus this assignment must after
 		//the exec exit, to make sure it get the exec's id.
+		c.mu.Lock()
 		processID = execs.id
+		c.mu.Unlock()
 	}
 
 	ret, err := s.sandbox.WaitProcess(ctx, c.id, processID)
@@ -53,8 +57,8 @@ func wait(ctx context.Context, s *service, c *container, execID string) (int32,
 
 	timeStamp := time.Now()
 
-	s.mu.Lock()
 	if execID == "" {
+		s.mu.Lock()
 		// Take care of the use case where it is a sandbox.
 		// Right after the container representing the sandbox has
 		// been deleted, let's make sure we stop and delete the
@@ -68,6 +72,7 @@ func wait(ctx context.Context, s *service, c *container, execID string) (int32,
 			if err = s.sandbox.Stop(ctx, true); err != nil {
 				shimLog.WithField("sandbox", s.sandbox.ID()).Error("failed to stop sandbox")
 			}
+			s.postExitReport(ctx)
 
 			if err = s.sandbox.Delete(ctx); err != nil {
 				shimLog.WithField("sandbox", s.sandbox.ID()).Error("failed to delete sandbox")
@@ -77,20 +82,24 @@ func wait(ctx context.Context, s *service, c *container, execID string) (int32,
 				shimLog.WithError(err).WithField("container", c.id).Warn("stop container failed")
 			}
 		}
+		c.mu.Lock()
 		c.status = task.StatusStopped
 		c.exit = uint32(ret)
 		c.exitTime = timeStamp
+		c.mu.Unlock()
 
 		c.exitCh <- uint32(ret)
-
+		s.mu.Unlock()
 	} else {
+		// the exec exit only changes the shim state of its container
+		c.mu.Lock()
 		execs.status = task.StatusStopped
 		execs.exitCode = ret
 		execs.exitTime = timeStamp
+		c.mu.Unlock()
 
 		execs.exitCh <- uint32(ret)
 	}
-	s.mu.Unlock()
 
 	go cReap(s, int(ret), c.id, execID, timeStamp)
 
@@ -115,12 +124,13 @@ func watchSandbox(ctx context.Context, s *service) {
 	if err != nil {
 		shimLog.WithError(err).Warn("stop sandbox failed")
 	}
+	s.postExitReport(ctx)
 	err = s.sandbox.Delete(ctx)
 	if err != nil {
 		shimLog.WithError(err).Warn("delete sandbox failed")
 	}
 
-	for _, c := range s.containers {
+	for _, c := range s.listContainers() {
 		if !c.mounted {
 			continue
 		}
@@ -159,7 +169,7 @@ func watchOOMEvents(ctx context.Context, s *service) {
 			}
 
 			// write oom file for CRI-O
-			if c, ok := s.containers[containerID]; ok && oci.IsCRIOContainerManager(c.spec) {
+			if c, err := s.getContainer(containerID); err == nil && oci.IsCRIOContainerManager(c.spec) {
 				oomPath := path.Join(c.bundle, "oom")
 				shimLog.Infof("write oom file to notify CRI-O: %s", oomPath)
 
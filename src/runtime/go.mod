@@ -27,6 +27,7 @@ require (
 	github.com/go-openapi/validate v0.18.0
 	github.com/gogo/googleapis v1.4.0 // indirect
 	github.com/gogo/protobuf v1.3.2
+	github.com/gorilla/websocket v1.4.2
 	github.com/hashicorp/go-multierror v1.0.0
 	github.com/intel-go/cpuid v0.0.0-20210602155658-5747e5cec0d9
 	github.com/juju/errors v0.0.0-20180806074554-22422dad46e1 // indirect
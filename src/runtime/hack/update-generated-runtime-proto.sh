@@ -12,3 +12,14 @@ protoc \
 Mgoogle/protobuf/empty.proto=github.com/gogo/protobuf/types,\
 plugins=grpc:protocols/cache \
 	protocols/cache/cache.proto
+
+# like the agent protocols, the device plugins one is generated with
+# protoc-gen-gogottrpc from https://github.com/containerd/ttrpc
+protoc \
+	-I=$GOPATH/src \
+	-I=$GOPATH/src/github.com/gogo/protobuf/protobuf \
+	--proto_path=protocols/deviceplugin \
+	--gogottrpc_out=\
+Mgoogle/protobuf/empty.proto=github.com/gogo/protobuf/types,\
+plugins=grpc:protocols/deviceplugin \
+	protocols/deviceplugin/deviceplugin.proto
@@ -0,0 +1,125 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package katamonitor
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+
+	"github.com/containerd/containerd/identifiers"
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/shimclient"
+)
+
+// ServeSandbox serves the requests of a sandbox at /sandboxes/<id>/...
+func (km *KataMonitor) ServeSandbox(w http.ResponseWriter, r *http.Request) {
+	path := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/sandboxes/"), "/"), "/")
+	if path[0] != "" {
+		if err := validateSandboxID(path[0]); err != nil {
+			commonServeError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	if len(path) == 4 && path[0] != "" && path[1] == "containers" && path[2] != "" && path[3] == "attach" {
+		km.ContainerAttach(w, r, path[0], path[2])
+		return
+	}
+	if len(path) == 2 && path[0] != "" && path[1] == "stats" {
+		km.SandboxStats(w, r, path[0])
+		return
+	}
+	if len(path) == 2 && path[0] != "" && path[1] == "metrics" {
+		km.SandboxMetrics(w, r, path[0])
+		return
+	}
+
+	km.SandboxTop(w, r)
+}
+
+// sameOrigin returns true if the request is not a cross-origin one from a
+// browser, so that other sites cannot run commands in the containers.
+func sameOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+
+	return strings.EqualFold(u.Host, r.Host)
+}
+
+// ContainerAttach proxies the attach websocket of a container, served at
+// /sandboxes/<id>/containers/<container id>/attach, to the shim of the
+// sandbox. The shim runs the command and streams its stdio, when its
+// MonitorAttach feature gate is enabled and the request carries its
+// management token. It is only served to the clients authenticated by the
+// listener.
+func (km *KataMonitor) ContainerAttach(w http.ResponseWriter, r *http.Request, sandboxID, containerID string) {
+	// the origin check alone is defeated by DNS rebinding, the clients
+	// must be authenticated
+	if !authenticated(r) {
+		commonServeError(w, http.StatusForbidden, fmt.Errorf("attach is only served with the token of the listener or on the unix socket"))
+		return
+	}
+	if !sameOrigin(r) {
+		commonServeError(w, http.StatusForbidden, fmt.Errorf("cross-origin attach requests are not allowed"))
+		return
+	}
+
+	if status, err := km.checkSandbox(sandboxID); err != nil {
+		commonServeError(w, status, err)
+		return
+	}
+	if err := identifiers.Validate(containerID); err != nil {
+		commonServeError(w, http.StatusBadRequest, fmt.Errorf("invalid container ID: %v", err))
+		return
+	}
+
+	if km.shimTokenFile == "" {
+		commonServeError(w, http.StatusForbidden, fmt.Errorf("attach needs the management token of the shims, see -shim-token-file"))
+		return
+	}
+	// read on each request, for the token to be rotated
+	token, err := ioutil.ReadFile(km.shimTokenFile)
+	if err != nil {
+		commonServeError(w, http.StatusInternalServerError, fmt.Errorf("cannot read the management token of the shims: %v", err))
+		return
+	}
+
+	socket := shimAddress(sandboxID)
+	proxy := &httputil.ReverseProxy{
+		Director: func(req *http.Request) {
+			req.URL.Scheme = "http"
+			req.URL.Host = "shim"
+			req.URL.Path = fmt.Sprintf("/containers/%s/attach", containerID)
+			req.Host = "shim"
+			// the token of the listener is not forwarded
+			req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+		},
+		Transport: &http.Transport{
+			DisableKeepAlives: true,
+			Dial: func(proto, addr string) (net.Conn, error) {
+				return net.Dial("unix", shimclient.DialPath(socket))
+			},
+		},
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			monitorLog.WithError(err).WithField("sandbox", sandboxID).Warn("failed to proxy attach request")
+			commonServeError(w, http.StatusBadGateway, fmt.Errorf("sandbox may be stopped or deleted"))
+		},
+	}
+
+	monitorLog.WithField("sandbox", sandboxID).WithField("container", containerID).Info("attach request")
+	proxy.ServeHTTP(w, r)
+}
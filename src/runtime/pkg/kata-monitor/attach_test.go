@@ -0,0 +1,137 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package katamonitor
+
+import (
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/shimclient"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSameOrigin(t *testing.T) {
+	assert := assert.New(t)
+
+	r := httptest.NewRequest(http.MethodGet, "http://localhost:8090/sandboxes/foo/containers/bar/attach", nil)
+	assert.True(sameOrigin(r))
+
+	r.Header.Set("Origin", "http://localhost:8090")
+	assert.True(sameOrigin(r))
+
+	r.Header.Set("Origin", "https://example.com")
+	assert.False(sameOrigin(r))
+}
+
+func TestContainerAttach(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "kata-monitor")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	// a shim echoing the attach streams, with the query of the command
+	path := filepath.Join(dir, "shim-monitor")
+	listener, err := net.Listen("unix", path)
+	assert.NoError(err)
+
+	shim := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/containers/bar/attach" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer shim-secret" {
+			http.Error(w, "invalid management token", http.StatusUnauthorized)
+			return
+		}
+		upgrader := websocket.Upgrader{Subprotocols: []string{"v4.channel.k8s.io"}}
+		ws, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer ws.Close()
+
+		ws.WriteMessage(websocket.BinaryMessage, append([]byte{1}, r.URL.RawQuery...))
+		for {
+			opcode, data, err := ws.ReadMessage()
+			if err != nil {
+				return
+			}
+			ws.WriteMessage(opcode, data)
+		}
+	})}
+	go shim.Serve(listener)
+	defer shim.Close()
+
+	saved := storedShimAddress
+	defer func() {
+		storedShimAddress = saved
+	}()
+	storedShimAddress = func(sandboxID string) (string, error) {
+		return shimclient.UnixSocketScheme + path, nil
+	}
+
+	km := &KataMonitor{
+		sandboxCache: &sandboxCache{
+			Mutex:     &sync.Mutex{},
+			sandboxes: map[string]string{"foo": "k8s.io"},
+		},
+	}
+	tokenFile := filepath.Join(dir, "token")
+	assert.NoError(ioutil.WriteFile(tokenFile, []byte("secret\n"), 0600))
+	shimTokenFile := filepath.Join(dir, "shim-token")
+	assert.NoError(ioutil.WriteFile(shimTokenFile, []byte("shim-secret\n"), 0600))
+	c := ListenerConfig{TokenFile: tokenFile}
+	srv := httptest.NewServer(c.handler(http.HandlerFunc(km.ServeSandbox)))
+	defer srv.Close()
+
+	dial := func(path string) (*websocket.Conn, error) {
+		dialer := websocket.Dialer{Subprotocols: []string{"v4.channel.k8s.io"}}
+		ws, _, err := dialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+path,
+			http.Header{"Authorization": []string{"Bearer secret"}})
+		return ws, err
+	}
+
+	// the management token of the shims is required
+	_, err = dial("/sandboxes/foo/containers/bar/attach?command=sh&tty=true")
+	assert.Error(err)
+	km.SetShimTokenFile(shimTokenFile)
+
+	ws, err := dial("/sandboxes/foo/containers/bar/attach?command=sh&tty=true")
+	assert.NoError(err)
+	defer ws.Close()
+
+	_, data, err := ws.ReadMessage()
+	assert.NoError(err)
+	assert.Equal("\x01command=sh&tty=true", string(data))
+
+	assert.NoError(ws.WriteMessage(websocket.BinaryMessage, []byte("\x00ls\n")))
+	_, data, err = ws.ReadMessage()
+	assert.NoError(err)
+	assert.Equal("\x00ls\n", string(data))
+
+	// the listeners without token don't serve the attach
+	rr := httptest.NewRecorder()
+	ListenerConfig{}.handler(http.HandlerFunc(km.ServeSandbox)).ServeHTTP(rr,
+		httptest.NewRequest(http.MethodGet, "/sandboxes/foo/containers/bar/attach", nil))
+	assert.Equal(http.StatusForbidden, rr.Code)
+
+	// unknown sandbox
+	_, err = dial("/sandboxes/baz/containers/bar/attach")
+	assert.Error(err)
+
+	// the shim rejects the unknown containers
+	_, err = dial("/sandboxes/foo/containers/baz/attach")
+	assert.Error(err)
+}
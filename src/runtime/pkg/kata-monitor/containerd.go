@@ -7,11 +7,15 @@ package katamonitor
 
 import (
 	"context"
+	"strings"
 
 	"github.com/sirupsen/logrus"
 
 	"github.com/containerd/containerd"
+	tasks "github.com/containerd/containerd/api/services/tasks/v1"
+	"github.com/containerd/containerd/api/types/task"
 	"github.com/containerd/containerd/containers"
+	"github.com/containerd/containerd/errdefs"
 	"github.com/containerd/containerd/namespaces"
 	"github.com/containerd/typeurl"
 
@@ -21,14 +25,145 @@ import (
 	"github.com/opencontainers/runtime-spec/specs-go"
 )
 
+// PodMetadataFilter lists the pod labels and annotations which are copied
+// into the labels of the sandbox metrics.
+type PodMetadataFilter struct {
+	Labels      []string
+	Annotations []string
+}
+
+// NamespaceFilter selects the containerd namespaces of the monitored
+// sandboxes, e.g. only k8s.io on the hosts also running other workloads.
+// All the namespaces but the denied ones are monitored when Allow is empty.
+type NamespaceFilter struct {
+	Allow []string
+	Deny  []string
+}
+
+// allowed returns true if the sandboxes of a namespace are monitored.
+func (f NamespaceFilter) allowed(namespace string) bool {
+	for _, ns := range f.Deny {
+		if ns == namespace {
+			return false
+		}
+	}
+
+	if len(f.Allow) == 0 {
+		return true
+	}
+	for _, ns := range f.Allow {
+		if ns == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// metricsLabels returns the metrics labels for the allow-listed labels and
+// annotations of a sandbox container. Pod labels are set as container labels
+// by the CRI plugin, while pod annotations are found in the OCI spec.
+// Like kube-state-metrics, the label names are prefixed by "label_" and
+// "annotation_" to avoid conflicts with the labels of the sandbox metrics.
+// When the pod is resolved from the Kubernetes API, its current labels are
+// used, and its controller is added as owner_kind and owner_name.
+func (f PodMetadataFilter) metricsLabels(c *containers.Container, pod *PodInfo) map[string]string {
+	labels := make(map[string]string)
+
+	podLabels := c.Labels
+	if pod != nil {
+		podLabels = pod.Labels
+
+		if owner := pod.controller(); owner != nil {
+			labels["owner_kind"] = owner.Kind
+			labels["owner_name"] = owner.Name
+		}
+	}
+
+	for _, key := range f.Labels {
+		if value, ok := podLabels[key]; ok {
+			labels["label_"+sanitizeLabelName(key)] = value
+		}
+	}
+
+	if len(f.Annotations) == 0 || c.Spec == nil {
+		return labels
+	}
+
+	v, err := typeurl.UnmarshalAny(c.Spec)
+	if err != nil {
+		monitorLog.WithError(err).WithField("container", c.ID).Warn("failed to Unmarshal container spec")
+		return labels
+	}
+
+	ociSpec, ok := v.(*specs.Spec)
+	if !ok {
+		return labels
+	}
+
+	for _, key := range f.Annotations {
+		if value, ok := ociSpec.Annotations[key]; ok {
+			labels["annotation_"+sanitizeLabelName(key)] = value
+		}
+	}
+
+	return labels
+}
+
+// withoutCollisions returns the filter without the keys whose label name
+// collides with the one of a previous key, e.g. "a.b" and "a_b", as the
+// metrics can't have the same label twice.
+func (f PodMetadataFilter) withoutCollisions() PodMetadataFilter {
+	return PodMetadataFilter{
+		Labels:      uniqueLabelNames("label_", f.Labels),
+		Annotations: uniqueLabelNames("annotation_", f.Annotations),
+	}
+}
+
+// uniqueLabelNames returns the keys with a label name of their own, the
+// keys colliding with a previous one are logged and left out.
+func uniqueLabelNames(prefix string, keys []string) []string {
+	var unique []string
+	names := make(map[string]string)
+	for _, key := range keys {
+		name := prefix + sanitizeLabelName(key)
+		if other, ok := names[name]; ok {
+			if other != key {
+				monitorLog.WithFields(logrus.Fields{"key": key, "other": other, "label": name}).
+					Warn("pod metadata key has the label name of another key, ignoring it")
+			}
+			continue
+		}
+
+		names[name] = key
+		unique = append(unique, key)
+	}
+	return unique
+}
+
+// sanitizeLabelName replaces the characters not allowed in a Prometheus
+// label name by an underscore, e.g. "app.kubernetes.io/name" => "app_kubernetes_io_name".
+func sanitizeLabelName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
 func getContainer(containersClient containers.Store, namespace, cid string) (containers.Container, error) {
 	ctx := context.Background()
 	ctx = namespaces.WithNamespace(ctx, namespace)
 	return containersClient.Get(ctx, cid)
 }
 
-// isSandboxContainer return true if the container is a sandbox container.
+// isSandboxContainer return true if the container is a sandbox container,
+// the sandboxes of the self tests are not monitored.
 func isSandboxContainer(c *containers.Container) bool {
+	if _, ok := c.Labels[selfTestLabel]; ok {
+		return false
+	}
+
 	// unmarshal from any to spec.
 	if c.Spec == nil {
 		monitorLog.WithField("container", c.ID).Error("container spec is nil")
@@ -55,6 +190,36 @@ func isSandboxContainer(c *containers.Container) bool {
 	return containerType == vc.PodSandbox
 }
 
+// getTaskState returns the state of the task of a sandbox, created when
+// the task does not exist yet. The paused sandboxes are running.
+func getTaskState(ctx context.Context, client *containerd.Client, id string) string {
+	resp, err := client.TaskService().Get(ctx, &tasks.GetRequest{ContainerID: id})
+	if err != nil {
+		if errdefs.IsNotFound(errdefs.FromGRPC(err)) {
+			return SandboxCreated
+		}
+		monitorLog.WithError(err).WithField("sandbox", id).Warn("failed to get the task of the sandbox")
+		return SandboxRunning
+	}
+
+	if resp.Process == nil {
+		return SandboxRunning
+	}
+	return taskState(resp.Process.Status)
+}
+
+// taskState returns the state of a sandbox from the status of its task.
+func taskState(status task.Status) string {
+	switch status {
+	case task.StatusCreated:
+		return SandboxCreated
+	case task.StatusStopped:
+		return SandboxStopped
+	default:
+		return SandboxRunning
+	}
+}
+
 // getSandboxes get kata sandbox from containerd.
 // this will be called only after monitor start.
 func (ka *KataMonitor) getSandboxes() (map[string]string, error) {
@@ -66,16 +231,23 @@ func (ka *KataMonitor) getSandboxes() (map[string]string, error) {
 
 	ctx := context.Background()
 
-	// first all namespaces.
-	namespaceList, err := client.NamespaceService().List(ctx)
-	if err != nil {
-		return nil, err
+	// first all namespaces, only the allowed ones are listed so that
+	// the others, which may not be accessible, are not enumerated.
+	namespaceList := ka.sandboxCache.namespaces.Allow
+	if len(namespaceList) == 0 {
+		namespaceList, err = client.NamespaceService().List(ctx)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	// map of type: <key:sandbox_id => value: namespace>
 	sandboxMap := make(map[string]string)
 
 	for _, namespace := range namespaceList {
+		if !ka.sandboxCache.namespaces.allowed(namespace) {
+			continue
+		}
 
 		initSandboxByNamespaceFunc := func(namespace string) error {
 			ctx := context.Background()
@@ -93,6 +265,8 @@ func (ka *KataMonitor) getSandboxes() (map[string]string, error) {
 				monitorLog.WithFields(logrus.Fields{"container": c.ID, "result": isc}).Debug("is this a sandbox container?")
 				if isc {
 					sandboxMap[c.ID] = namespace
+					ka.sandboxCache.setPodMetadata(&c)
+					ka.sandboxCache.setTaskState(c.ID, getTaskState(namespacedCtx, client, c.ID))
 				}
 			}
 			return nil
@@ -11,6 +11,7 @@ import (
 	criContainerdAnnotations "github.com/containerd/cri-containerd/pkg/annotations"
 	"github.com/containerd/typeurl"
 
+	"github.com/containerd/containerd/api/types/task"
 	"github.com/containerd/containerd/containers"
 	"github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/stretchr/testify/assert"
@@ -73,4 +74,89 @@ func TestIsSandboxContainer(t *testing.T) {
 		assert.Equal(tc.result, isc, "assert failed for checking if is a sandbox container")
 	}
 
+	// the sandboxes of the self tests are not monitored
+	c.Labels = map[string]string{selfTestLabel: "true"}
+	assert.False(isSandboxContainer(c))
+}
+
+func TestPodMetadataFilter(t *testing.T) {
+	assert := assert.New(t)
+
+	spec := &specs.Spec{
+		Annotations: map[string]string{
+			"example.com/cost-center": "42",
+			"ignored":                 "value",
+		},
+	}
+	any, err := typeurl.MarshalAny(spec)
+	assert.NoError(err)
+
+	c := &containers.Container{
+		ID: "sandbox",
+		Labels: map[string]string{
+			"app":                    "web",
+			"team":                   "kata",
+			"io.cri-containerd.kind": "sandbox",
+		},
+		Spec: any,
+	}
+
+	f := PodMetadataFilter{}
+	assert.Empty(f.metricsLabels(c, nil))
+
+	f = PodMetadataFilter{
+		Labels:      []string{"app", "team", "missing"},
+		Annotations: []string{"example.com/cost-center"},
+	}
+	assert.Equal(map[string]string{
+		"label_app":                          "web",
+		"label_team":                         "kata",
+		"annotation_example_com_cost_center": "42",
+	}, f.metricsLabels(c, nil))
+}
+
+func TestPodMetadataFilterCollisions(t *testing.T) {
+	assert := assert.New(t)
+
+	f := PodMetadataFilter{
+		Labels:      []string{"app.kubernetes.io/name", "team", "app_kubernetes_io_name", "team"},
+		Annotations: []string{"team"},
+	}
+	assert.Equal(PodMetadataFilter{
+		Labels:      []string{"app.kubernetes.io/name", "team"},
+		Annotations: []string{"team"},
+	}, f.withoutCollisions())
+}
+
+func TestSanitizeLabelName(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("team", sanitizeLabelName("team"))
+	assert.Equal("app_kubernetes_io_name", sanitizeLabelName("app.kubernetes.io/name"))
+	assert.Equal("a_b_c", sanitizeLabelName("a-b c"))
+}
+
+func TestTaskState(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(SandboxCreated, taskState(task.StatusCreated))
+	assert.Equal(SandboxRunning, taskState(task.StatusRunning))
+	assert.Equal(SandboxRunning, taskState(task.StatusPaused))
+	assert.Equal(SandboxStopped, taskState(task.StatusStopped))
+}
+
+func TestNamespaceFilter(t *testing.T) {
+	assert := assert.New(t)
+
+	// all the namespaces by default
+	assert.True(NamespaceFilter{}.allowed("k8s.io"))
+
+	f := NamespaceFilter{Deny: []string{"moby"}}
+	assert.True(f.allowed("k8s.io"))
+	assert.False(f.allowed("moby"))
+
+	f = NamespaceFilter{Allow: []string{"k8s.io", "moby"}, Deny: []string{"moby"}}
+	assert.True(f.allowed("k8s.io"))
+	assert.False(f.allowed("moby"))
+	assert.False(f.allowed("default"))
 }
@@ -0,0 +1,294 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package katamonitor
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	customMetricsGroupVersion = "custom.metrics.k8s.io/v1beta1"
+
+	// CustomMetricsAPIPrefix is the path of the custom metrics API.
+	CustomMetricsAPIPrefix = "/apis/" + customMetricsGroupVersion
+)
+
+// customMetrics maps the pod metrics of the custom metrics API
+// to the shim metrics they are read from.
+var customMetrics = map[string]string{
+	"kata_vm_overhead_cpu":          "kata_shim_pod_overhead_cpu",
+	"kata_vm_overhead_memory_bytes": "kata_shim_pod_overhead_memory_in_bytes",
+	"kata_vm_boot_duration_seconds": "kata_shim_vm_boot_duration_seconds",
+}
+
+// getSandboxMetrics returns the metrics of the shim of a sandbox,
+// it is replaced by the tests.
+var getSandboxMetrics = getParsedMetrics
+
+type apiResource struct {
+	Name         string   `json:"name"`
+	SingularName string   `json:"singularName"`
+	Namespaced   bool     `json:"namespaced"`
+	Kind         string   `json:"kind"`
+	Verbs        []string `json:"verbs"`
+}
+
+type apiResourceList struct {
+	Kind         string        `json:"kind"`
+	APIVersion   string        `json:"apiVersion"`
+	GroupVersion string        `json:"groupVersion"`
+	Resources    []apiResource `json:"resources"`
+}
+
+type objectReference struct {
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace"`
+	Name       string `json:"name"`
+	APIVersion string `json:"apiVersion"`
+}
+
+type metricValue struct {
+	DescribedObject objectReference `json:"describedObject"`
+	MetricName      string          `json:"metricName"`
+	Timestamp       string          `json:"timestamp"`
+	Value           string          `json:"value"`
+}
+
+type metricValueList struct {
+	Kind       string            `json:"kind"`
+	APIVersion string            `json:"apiVersion"`
+	Metadata   map[string]string `json:"metadata"`
+	Items      []metricValue     `json:"items"`
+}
+
+type apiStatus struct {
+	Kind       string `json:"kind"`
+	APIVersion string `json:"apiVersion"`
+	Status     string `json:"status"`
+	Message    string `json:"message"`
+	Reason     string `json:"reason"`
+	Code       int    `json:"code"`
+}
+
+// labelRequirement is a term of an equality based label selector.
+type labelRequirement struct {
+	key      string
+	value    string
+	operator string
+}
+
+// parseLabelSelector parses an equality based label selector,
+// e.g. "app=web,tier!=cache,canary". The set based ones are not supported.
+func parseLabelSelector(selector string) ([]labelRequirement, error) {
+	var requirements []labelRequirement
+
+	for _, term := range strings.Split(selector, ",") {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+
+		if strings.ContainsAny(term, "() ") {
+			return nil, fmt.Errorf("unsupported label selector %q, only the equality based ones are supported", term)
+		}
+
+		var r labelRequirement
+		switch {
+		case strings.Contains(term, "!="):
+			r.operator = "!="
+		case strings.Contains(term, "=="):
+			r.operator = "=="
+		case strings.Contains(term, "="):
+			r.operator = "="
+		case strings.HasPrefix(term, "!"):
+			r.operator = "!"
+			r.key = term[1:]
+		default:
+			r.key = term
+		}
+
+		if r.key == "" {
+			fields := strings.SplitN(term, r.operator, 2)
+			r.key, r.value = fields[0], fields[1]
+		}
+
+		if r.key == "" {
+			return nil, fmt.Errorf("invalid label selector %q", term)
+		}
+
+		requirements = append(requirements, r)
+	}
+
+	return requirements, nil
+}
+
+// matchLabels returns true if the labels match all the requirements.
+func matchLabels(requirements []labelRequirement, labels map[string]string) bool {
+	for _, r := range requirements {
+		value, found := labels[r.key]
+
+		switch r.operator {
+		case "=", "==":
+			if !found || value != r.value {
+				return false
+			}
+		case "!=":
+			if found && value == r.value {
+				return false
+			}
+		case "!":
+			if found {
+				return false
+			}
+		default:
+			if !found {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func writeAPIObject(w http.ResponseWriter, code int, obj interface{}) {
+	w.Header().Set(contentTypeHeader, "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(obj)
+}
+
+func writeAPIStatus(w http.ResponseWriter, code int, reason, format string, args ...interface{}) {
+	writeAPIObject(w, code, apiStatus{
+		Kind:       "Status",
+		APIVersion: "v1",
+		Status:     "Failure",
+		Message:    fmt.Sprintf(format, args...),
+		Reason:     reason,
+		Code:       code,
+	})
+}
+
+// gaugeValue returns the value of a gauge of the metric families.
+func gaugeValue(mfs []*dto.MetricFamily, name string) (float64, bool) {
+	for _, mf := range mfs {
+		if mf.GetName() != name || len(mf.Metric) == 0 || mf.Metric[0].Gauge == nil {
+			continue
+		}
+		return mf.Metric[0].Gauge.GetValue(), true
+	}
+	return 0, false
+}
+
+// CustomMetrics serves the custom metrics API of the Kata pods running on the
+// node, so that the VM overhead and boot duration can be used by the
+// autoscalers. The metrics are read from the shims of the sandboxes. The
+// clients are authenticated by the listener, with a token or a certificate
+// of its client CA, e.g. the front proxy of the API aggregation layer.
+func (km *KataMonitor) CustomMetrics(w http.ResponseWriter, r *http.Request) {
+	if !authenticated(r) && !clientCertAuthenticated(r) {
+		writeAPIStatus(w, http.StatusUnauthorized, "Unauthorized", "a client certificate of the requestheader client CA is required")
+		return
+	}
+
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, CustomMetricsAPIPrefix), "/")
+
+	if path == "" {
+		list := apiResourceList{
+			Kind:         "APIResourceList",
+			APIVersion:   "v1",
+			GroupVersion: customMetricsGroupVersion,
+			Resources:    []apiResource{},
+		}
+		for name := range customMetrics {
+			list.Resources = append(list.Resources, apiResource{
+				Name:       "pods/" + name,
+				Namespaced: true,
+				Kind:       "MetricValueList",
+				Verbs:      []string{"get"},
+			})
+		}
+		sort.Slice(list.Resources, func(i, j int) bool {
+			return list.Resources[i].Name < list.Resources[j].Name
+		})
+
+		writeAPIObject(w, http.StatusOK, list)
+		return
+	}
+
+	// namespaces/<namespace>/pods/<name or *>/<metric>
+	parts := strings.Split(path, "/")
+	if len(parts) != 5 || parts[0] != "namespaces" || parts[2] != "pods" {
+		writeAPIStatus(w, http.StatusNotFound, "NotFound", "only the metrics of the pods are served: %v", r.URL.Path)
+		return
+	}
+	namespace, name, metric := parts[1], parts[3], parts[4]
+
+	shimMetric, ok := customMetrics[metric]
+	if !ok {
+		writeAPIStatus(w, http.StatusNotFound, "NotFound", "unknown metric %v", metric)
+		return
+	}
+
+	selector, err := parseLabelSelector(r.URL.Query().Get("labelSelector"))
+	if err != nil {
+		writeAPIStatus(w, http.StatusBadRequest, "BadRequest", "%v", err)
+		return
+	}
+
+	list := metricValueList{
+		Kind:       "MetricValueList",
+		APIVersion: customMetricsGroupVersion,
+		Metadata:   map[string]string{"selfLink": r.URL.Path},
+		Items:      []metricValue{},
+	}
+
+	for _, sandboxID := range km.getSandboxList() {
+		pod := km.sandboxCache.getPod(sandboxID)
+		if pod == nil || pod.Namespace != namespace || (name != "*" && pod.Name != name) || !matchLabels(selector, pod.Labels) {
+			continue
+		}
+
+		mfs, err := getSandboxMetrics(sandboxID)
+		if err != nil {
+			monitorLog.WithError(err).WithField("sandbox_id", sandboxID).Warn("failed to get metrics for sandbox")
+			continue
+		}
+
+		value, ok := gaugeValue(mfs, shimMetric)
+		if !ok {
+			monitorLog.WithFields(logrus.Fields{"sandbox_id": sandboxID, "metric": shimMetric}).Debug("metric not exposed by the shim")
+			continue
+		}
+
+		list.Items = append(list.Items, metricValue{
+			DescribedObject: objectReference{
+				Kind:       "Pod",
+				Namespace:  pod.Namespace,
+				Name:       pod.Name,
+				APIVersion: "/v1",
+			},
+			MetricName: metric,
+			Timestamp:  time.Now().UTC().Format(time.RFC3339),
+			Value:      resource.NewMilliQuantity(int64(math.Round(value*1000)), resource.DecimalSI).String(),
+		})
+	}
+
+	if name != "*" && len(list.Items) == 0 {
+		writeAPIStatus(w, http.StatusNotFound, "NotFound", "metric %v not found for pod %v/%v", metric, namespace, name)
+		return
+	}
+
+	writeAPIObject(w, http.StatusOK, list)
+}
@@ -0,0 +1,141 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package katamonitor
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLabelSelector(t *testing.T) {
+	assert := assert.New(t)
+
+	requirements, err := parseLabelSelector("app=web, tier!=cache,env==prod,canary,!legacy")
+	assert.NoError(err)
+	assert.Equal([]labelRequirement{
+		{key: "app", value: "web", operator: "="},
+		{key: "tier", value: "cache", operator: "!="},
+		{key: "env", value: "prod", operator: "=="},
+		{key: "canary", operator: ""},
+		{key: "legacy", operator: "!"},
+	}, requirements)
+
+	labels := map[string]string{"app": "web", "env": "prod", "canary": "true"}
+	assert.True(matchLabels(requirements, labels))
+
+	labels["tier"] = "cache"
+	assert.False(matchLabels(requirements, labels))
+	delete(labels, "tier")
+
+	labels["legacy"] = "true"
+	assert.False(matchLabels(requirements, labels))
+	delete(labels, "legacy")
+
+	delete(labels, "canary")
+	assert.False(matchLabels(requirements, labels))
+
+	requirements, err = parseLabelSelector("")
+	assert.NoError(err)
+	assert.True(matchLabels(requirements, nil))
+
+	_, err = parseLabelSelector("env in (prod,qa)")
+	assert.Error(err)
+	_, err = parseLabelSelector("=web")
+	assert.Error(err)
+}
+
+func mockSandboxMetrics(values map[string]float64) func() {
+	saved := getSandboxMetrics
+
+	getSandboxMetrics = func(sandboxID string) ([]*dto.MetricFamily, error) {
+		value, ok := values[sandboxID]
+		if !ok {
+			return nil, fmt.Errorf("no shim for sandbox %s", sandboxID)
+		}
+
+		name := "kata_shim_pod_overhead_memory_in_bytes"
+		return []*dto.MetricFamily{
+			{
+				Name:   &name,
+				Metric: []*dto.Metric{{Gauge: &dto.Gauge{Value: &value}}},
+			},
+		}, nil
+	}
+
+	return func() {
+		getSandboxMetrics = saved
+	}
+}
+
+func TestCustomMetrics(t *testing.T) {
+	assert := assert.New(t)
+
+	restore := mockSandboxMetrics(map[string]float64{"web-1": 100 * 1024 * 1024, "web-2": 1.5})
+	defer restore()
+
+	sc := &sandboxCache{
+		Mutex:     &sync.Mutex{},
+		sandboxes: map[string]string{"web-1": "k8s.io", "web-2": "k8s.io", "db-1": "k8s.io"},
+	}
+	sc.setPod("web-1", &PodInfo{Name: "web-1", Namespace: "default", Labels: map[string]string{"app": "web"}})
+	sc.setPod("web-2", &PodInfo{Name: "web-2", Namespace: "default", Labels: map[string]string{"app": "web"}})
+	sc.setPod("db-1", &PodInfo{Name: "db-1", Namespace: "default", Labels: map[string]string{"app": "db"}})
+	km := &KataMonitor{sandboxCache: sc}
+
+	get := func(path string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		km.CustomMetrics(rr, withClientCert(httptest.NewRequest(http.MethodGet, CustomMetricsAPIPrefix+path, nil)))
+		return rr
+	}
+
+	// the clients are authenticated
+	rr := httptest.NewRecorder()
+	km.CustomMetrics(rr, httptest.NewRequest(http.MethodGet, CustomMetricsAPIPrefix, nil))
+	assert.Equal(http.StatusUnauthorized, rr.Code)
+
+	// discovery
+	rr = get("")
+	assert.Equal(http.StatusOK, rr.Code)
+	var resources apiResourceList
+	assert.NoError(json.Unmarshal(rr.Body.Bytes(), &resources))
+	assert.Len(resources.Resources, len(customMetrics))
+	assert.Equal("pods/kata_vm_boot_duration_seconds", resources.Resources[0].Name)
+
+	// a pod
+	rr = get("/namespaces/default/pods/web-1/kata_vm_overhead_memory_bytes")
+	assert.Equal(http.StatusOK, rr.Code)
+	var list metricValueList
+	assert.NoError(json.Unmarshal(rr.Body.Bytes(), &list))
+	assert.Len(list.Items, 1)
+	assert.Equal("web-1", list.Items[0].DescribedObject.Name)
+	assert.Equal("104857600", list.Items[0].Value)
+
+	// the pods selected by their labels, the shim of db-1 is not running
+	rr = get("/namespaces/default/pods/*/kata_vm_overhead_memory_bytes?labelSelector=app%3Dweb")
+	assert.Equal(http.StatusOK, rr.Code)
+	list = metricValueList{}
+	assert.NoError(json.Unmarshal(rr.Body.Bytes(), &list))
+	assert.Len(list.Items, 2)
+	for _, item := range list.Items {
+		if item.DescribedObject.Name == "web-2" {
+			assert.Equal("1500m", item.Value)
+		}
+	}
+
+	// not exposed by the shims
+	assert.Equal(http.StatusNotFound, get("/namespaces/default/pods/web-1/kata_vm_boot_duration_seconds").Code)
+	assert.Equal(http.StatusNotFound, get("/namespaces/other/pods/web-1/kata_vm_overhead_memory_bytes").Code)
+	assert.Equal(http.StatusNotFound, get("/namespaces/default/pods/web-1/foo").Code)
+	assert.Equal(http.StatusNotFound, get("/namespaces/default/services/web/kata_vm_overhead_memory_bytes").Code)
+	assert.Equal(http.StatusBadRequest, get("/namespaces/default/pods/*/kata_vm_overhead_memory_bytes?labelSelector=app+in+(web)").Code)
+}
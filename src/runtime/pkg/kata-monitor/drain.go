@@ -0,0 +1,143 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package katamonitor
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// ShimUpgradeTaint is set on the nodes running outdated shims while
+	// they are drained: the evicted pods prefer the other nodes, instead
+	// of restarting all together on the node being upgraded.
+	ShimUpgradeTaint = "katacontainers.io/shim-upgrade"
+
+	shimUpgradeTaintEffect = "PreferNoSchedule"
+)
+
+// DrainOptions are the options of DrainShims.
+type DrainOptions struct {
+	// MonitorURL is the URL of the kata-monitor of the node.
+	MonitorURL string
+	// VersionOlderThan is the version of the outdated shims.
+	VersionOlderThan string
+	// Node is the name of the node tainted while outdated shims run,
+	// no taint is set if empty.
+	Node string
+	// Evict evicts the pods of the outdated shims.
+	Evict bool
+	// DryRun only prints the changes.
+	DryRun bool
+}
+
+// getOutdatedShims returns the shims older than version from kata-monitor.
+func getOutdatedShims(monitorURL, version string) ([]ShimInfo, error) {
+	client := http.Client{Timeout: 30 * time.Second}
+
+	resp, err := client.Get(fmt.Sprintf("%s/shims?version-older-than=%s", monitorURL, url.QueryEscape(version)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the shims from kata-monitor: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the shims from kata-monitor: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get the shims from kata-monitor: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var shims []ShimInfo
+	if err := json.Unmarshal(body, &shims); err != nil {
+		return nil, fmt.Errorf("invalid shims from kata-monitor: %v", err)
+	}
+
+	return shims, nil
+}
+
+// DrainShims finds the sandboxes running shims older than the version, so
+// that they are restarted with the upgraded runtime. The node is tainted
+// while outdated shims run, and the pods are evicted with the eviction API,
+// respecting their disruption budgets. It is meant to be run until no
+// outdated shim is left, it returns the number of outdated shims.
+func DrainShims(kube *KubeClient, opts DrainOptions, out io.Writer) (int, error) {
+	if opts.VersionOlderThan == "" {
+		return 0, fmt.Errorf("no version provided")
+	}
+	if kube == nil && (opts.Node != "" || opts.Evict) {
+		return 0, fmt.Errorf("no Kubernetes client to taint the node or evict the pods")
+	}
+
+	shims, err := getOutdatedShims(opts.MonitorURL, opts.VersionOlderThan)
+	if err != nil {
+		return 0, err
+	}
+
+	if opts.Node != "" {
+		present := len(shims) > 0
+		taint := Taint{Key: ShimUpgradeTaint, Effect: shimUpgradeTaintEffect}
+
+		action := "removed from"
+		if present {
+			action = "added to"
+		}
+
+		if opts.DryRun {
+			fmt.Fprintf(out, "taint %s would be %s node %s (dry run)\n", ShimUpgradeTaint, action, opts.Node)
+		} else {
+			changed, err := kube.setNodeTaint(opts.Node, taint, present)
+			if err != nil {
+				return len(shims), err
+			}
+			if changed {
+				fmt.Fprintf(out, "taint %s %s node %s\n", ShimUpgradeTaint, action, opts.Node)
+			}
+		}
+	}
+
+	for _, s := range shims {
+		version := s.Version
+		if s.Error != "" {
+			version = "unknown (unreachable)"
+		} else if version == "" {
+			version = "unknown"
+		}
+
+		if s.Pod == nil {
+			fmt.Fprintf(out, "sandbox %s runs shim version %s, it is not a pod and must be restarted manually\n", s.ID, version)
+			continue
+		}
+
+		pod := fmt.Sprintf("%s/%s", s.Pod.Namespace, s.Pod.Name)
+		switch {
+		case !opts.Evict:
+			fmt.Fprintf(out, "pod %s (sandbox %s) runs shim version %s\n", pod, s.ID, version)
+		case opts.DryRun:
+			fmt.Fprintf(out, "pod %s (sandbox %s) running shim version %s would be evicted (dry run)\n", pod, s.ID, version)
+		default:
+			evicted, err := kube.evictPod(s.Pod.Namespace, s.Pod.Name)
+			if err != nil {
+				return len(shims), err
+			}
+			if evicted {
+				fmt.Fprintf(out, "pod %s (sandbox %s) running shim version %s evicted\n", pod, s.ID, version)
+			} else {
+				fmt.Fprintf(out, "pod %s (sandbox %s) running shim version %s not evicted: blocked by its disruption budget, retry later\n", pod, s.ID, version)
+			}
+		}
+	}
+
+	return len(shims), nil
+}
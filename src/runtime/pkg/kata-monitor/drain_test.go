@@ -0,0 +1,290 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package katamonitor
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/blang/semver"
+	shim "github.com/kata-containers/kata-containers/src/runtime/containerd-shim-v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func mockShimVersions(versions map[string]string) func() {
+	saved := getShimVersion
+
+	getShimVersion = func(sandboxID string) (*shim.VersionInfo, error) {
+		version, ok := versions[sandboxID]
+		if !ok {
+			return nil, fmt.Errorf("no shim for sandbox %s", sandboxID)
+		}
+		return &shim.VersionInfo{Version: version}, nil
+	}
+
+	return func() {
+		getShimVersion = saved
+	}
+}
+
+func newTestShimsMonitor() *KataMonitor {
+	return &KataMonitor{
+		sandboxCache: &sandboxCache{
+			Mutex:     &sync.Mutex{},
+			sandboxes: map[string]string{"old": "k8s.io", "new": "k8s.io", "legacy": "default", "hung": "k8s.io"},
+			pods: map[string]*PodInfo{
+				"old": {Name: "web", Namespace: "default"},
+				"new": {Name: "db", Namespace: "default"},
+			},
+		},
+	}
+}
+
+func TestShimOutdated(t *testing.T) {
+	assert := assert.New(t)
+
+	min := semver.MustParse("2.2.0")
+	assert.True(shimOutdated("2.1.1", min))
+	assert.True(shimOutdated("2.2.0-alpha1", min))
+	assert.True(shimOutdated("", min))
+	assert.True(shimOutdated("unknown", min))
+	assert.False(shimOutdated("2.2.0", min))
+	assert.False(shimOutdated("2.3.0", min))
+}
+
+func TestListShims(t *testing.T) {
+	assert := assert.New(t)
+
+	// the shim of "hung" does not answer, "legacy" predates the version endpoint
+	restore := mockShimVersions(map[string]string{"old": "2.1.1", "new": "2.2.0", "legacy": ""})
+	defer restore()
+
+	km := newTestShimsMonitor()
+
+	rr := httptest.NewRecorder()
+	km.ListShims(rr, httptest.NewRequest(http.MethodGet, "/shims", nil))
+	assert.Equal(http.StatusOK, rr.Code)
+
+	var shims []ShimInfo
+	assert.NoError(json.Unmarshal(rr.Body.Bytes(), &shims))
+	assert.Len(shims, 4)
+
+	rr = httptest.NewRecorder()
+	km.ListShims(rr, httptest.NewRequest(http.MethodGet, "/shims?version-older-than=2.2.0", nil))
+	assert.Equal(http.StatusOK, rr.Code)
+
+	// the unreachable shims may still run an old runtime
+	shims = nil
+	assert.NoError(json.Unmarshal(rr.Body.Bytes(), &shims))
+	assert.Len(shims, 3)
+	assert.Equal("hung", shims[0].ID)
+	assert.Empty(shims[0].Version)
+	assert.Contains(shims[0].Error, "no shim for sandbox hung")
+	assert.Equal("legacy", shims[1].ID)
+	assert.Nil(shims[1].Pod)
+	assert.Empty(shims[1].Error)
+	assert.Equal("old", shims[2].ID)
+	assert.Equal("2.1.1", shims[2].Version)
+	assert.Equal("web", shims[2].Pod.Name)
+
+	rr = httptest.NewRecorder()
+	km.ListShims(rr, httptest.NewRequest(http.MethodGet, "/shims?version-older-than=foo", nil))
+	assert.Equal(http.StatusBadRequest, rr.Code)
+}
+
+// testKubeNode is a node of the test API server, recording the
+// evictions and the taint patches.
+type testKubeNode struct {
+	sync.Mutex
+	taints   []Taint
+	evicted  []string
+	blocked  map[string]bool
+	patches  int
+	conflict bool
+}
+
+func (n *testKubeNode) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	n.Lock()
+	defer n.Unlock()
+
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/api/v1/nodes/node-1":
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"metadata": map[string]string{"name": "node-1", "resourceVersion": "42"},
+			"spec":     map[string]interface{}{"taints": n.taints},
+		})
+	case r.Method == http.MethodPatch && r.URL.Path == "/api/v1/nodes/node-1":
+		if n.conflict || r.Header.Get("Content-Type") != "application/merge-patch+json" {
+			http.Error(w, "conflict", http.StatusConflict)
+			return
+		}
+		var patch struct {
+			Metadata struct {
+				ResourceVersion string `json:"resourceVersion"`
+			} `json:"metadata"`
+			Spec struct {
+				Taints []Taint `json:"taints"`
+			} `json:"spec"`
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		if json.Unmarshal(body, &patch) != nil || patch.Metadata.ResourceVersion != "42" {
+			http.Error(w, "bad patch", http.StatusBadRequest)
+			return
+		}
+		n.taints = patch.Spec.Taints
+		n.patches++
+		w.Write([]byte("{}"))
+	case r.Method == http.MethodPost && r.URL.Path == "/api/v1/namespaces/default/pods/web/eviction",
+		r.Method == http.MethodPost && r.URL.Path == "/api/v1/namespaces/default/pods/db/eviction":
+		pod := r.URL.Path[len("/api/v1/namespaces/default/pods/") : len(r.URL.Path)-len("/eviction")]
+		if n.blocked[pod] {
+			http.Error(w, "Cannot evict pod as it would violate the pod's disruption budget.", http.StatusTooManyRequests)
+			return
+		}
+		n.evicted = append(n.evicted, pod)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("{}"))
+	default:
+		http.Error(w, "not found", http.StatusNotFound)
+	}
+}
+
+func TestKubeClientSetNodeTaint(t *testing.T) {
+	assert := assert.New(t)
+
+	node := &testKubeNode{taints: []Taint{{Key: "foo", Effect: "NoSchedule"}}}
+	server := httptest.NewServer(node)
+	defer server.Close()
+
+	kube, err := NewKubeClient(server.URL, "", "")
+	assert.NoError(err)
+
+	taint := Taint{Key: ShimUpgradeTaint, Effect: shimUpgradeTaintEffect}
+
+	changed, err := kube.setNodeTaint("node-1", taint, true)
+	assert.NoError(err)
+	assert.True(changed)
+	assert.Equal([]Taint{{Key: "foo", Effect: "NoSchedule"}, taint}, node.taints)
+
+	// already tainted
+	changed, err = kube.setNodeTaint("node-1", taint, true)
+	assert.NoError(err)
+	assert.False(changed)
+	assert.Equal(1, node.patches)
+
+	changed, err = kube.setNodeTaint("node-1", taint, false)
+	assert.NoError(err)
+	assert.True(changed)
+	assert.Equal([]Taint{{Key: "foo", Effect: "NoSchedule"}}, node.taints)
+
+	// the node was changed in the meantime
+	node.conflict = true
+	_, err = kube.setNodeTaint("node-1", taint, true)
+	assert.Error(err)
+
+	_, err = kube.setNodeTaint("missing", taint, true)
+	assert.Error(err)
+}
+
+func TestKubeClientEvictPod(t *testing.T) {
+	assert := assert.New(t)
+
+	node := &testKubeNode{blocked: map[string]bool{"db": true}}
+	server := httptest.NewServer(node)
+	defer server.Close()
+
+	kube, err := NewKubeClient(server.URL, "", "")
+	assert.NoError(err)
+
+	evicted, err := kube.evictPod("default", "web")
+	assert.NoError(err)
+	assert.True(evicted)
+	assert.Equal([]string{"web"}, node.evicted)
+
+	evicted, err = kube.evictPod("default", "db")
+	assert.NoError(err)
+	assert.False(evicted)
+
+	// deleted pods are gone already
+	evicted, err = kube.evictPod("default", "missing")
+	assert.NoError(err)
+	assert.True(evicted)
+}
+
+func TestDrainShims(t *testing.T) {
+	assert := assert.New(t)
+
+	restore := mockShimVersions(map[string]string{"old": "2.1.1", "new": "2.1.0", "legacy": ""})
+	defer restore()
+
+	km := newTestShimsMonitor()
+	monitor := httptest.NewServer(http.HandlerFunc(km.ListShims))
+	defer monitor.Close()
+
+	node := &testKubeNode{blocked: map[string]bool{"db": true}}
+	kubeServer := httptest.NewServer(node)
+	defer kubeServer.Close()
+
+	kube, err := NewKubeClient(kubeServer.URL, "", "")
+	assert.NoError(err)
+
+	opts := DrainOptions{
+		MonitorURL:       monitor.URL,
+		VersionOlderThan: "2.2.0",
+		Node:             "node-1",
+		Evict:            true,
+	}
+
+	// nothing is changed in dry run
+	var out bytes.Buffer
+	opts.DryRun = true
+	outdated, err := DrainShims(kube, opts, &out)
+	assert.NoError(err)
+	assert.Equal(4, outdated)
+	assert.Empty(node.taints)
+	assert.Empty(node.evicted)
+	assert.Contains(out.String(), "would be evicted")
+
+	out.Reset()
+	opts.DryRun = false
+	outdated, err = DrainShims(kube, opts, &out)
+	assert.NoError(err)
+	assert.Equal(4, outdated)
+	assert.Equal([]Taint{{Key: ShimUpgradeTaint, Effect: shimUpgradeTaintEffect}}, node.taints)
+	assert.Equal([]string{"web"}, node.evicted)
+	assert.Contains(out.String(), "sandbox legacy runs shim version unknown")
+	assert.Contains(out.String(), "sandbox hung runs shim version unknown (unreachable)")
+	assert.Contains(out.String(), "pod default/db (sandbox new) running shim version 2.1.0 not evicted")
+
+	// the node stays tainted while a shim is unreachable
+	restoreUpgraded := mockShimVersions(map[string]string{"old": "2.2.0", "new": "2.2.0", "legacy": "2.2.0"})
+	outdated, err = DrainShims(kube, opts, &out)
+	assert.NoError(err)
+	assert.Equal(1, outdated)
+	assert.NotEmpty(node.taints)
+	restoreUpgraded()
+
+	// the node is untainted once all the shims are upgraded
+	defer mockShimVersions(map[string]string{"old": "2.2.0", "new": "2.2.0", "legacy": "2.2.0", "hung": "2.2.0"})()
+	outdated, err = DrainShims(kube, opts, &out)
+	assert.NoError(err)
+	assert.Zero(outdated)
+	assert.Empty(node.taints)
+
+	// no Kubernetes client to evict the pods
+	_, err = DrainShims(nil, opts, &out)
+	assert.Error(err)
+
+	opts.VersionOlderThan = ""
+	_, err = DrainShims(kube, opts, &out)
+	assert.Error(err)
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package katamonitor
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	httpRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: promNamespaceMonitor,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests served by kata-monitor.",
+	},
+		[]string{"handler", "code", "method"},
+	)
+
+	httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: promNamespaceMonitor,
+		Name:      "http_request_duration_seconds",
+		Help:      "Time to serve the HTTP requests(seconds).",
+		Buckets:   prometheus.ExponentialBuckets(0.001, 2, 15),
+	},
+		[]string{"handler", "method"},
+	)
+
+	httpRequestsInFlight = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: promNamespaceMonitor,
+		Name:      "http_requests_in_flight",
+		Help:      "HTTP requests being served by kata-monitor.",
+	},
+		[]string{"handler"},
+	)
+
+	httpResponseSize = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: promNamespaceMonitor,
+		Name:      "http_response_size_bytes",
+		Help:      "Size of the HTTP responses(bytes).",
+		Buckets:   prometheus.ExponentialBuckets(256, 4, 10),
+	},
+		[]string{"handler", "method"},
+	)
+)
+
+func registerHTTPMetrics() {
+	prometheus.MustRegister(httpRequestsTotal)
+	prometheus.MustRegister(httpRequestDuration)
+	prometheus.MustRegister(httpRequestsInFlight)
+	prometheus.MustRegister(httpResponseSize)
+}
+
+// InstrumentHandler counts the requests served by a handler of kata-monitor
+// and records their latency and response size, for the monitor itself to be
+// capacity planned. The handler name is the path it is registered at.
+func InstrumentHandler(name string, handler http.Handler) http.Handler {
+	labels := prometheus.Labels{"handler": name}
+
+	return promhttp.InstrumentHandlerInFlight(httpRequestsInFlight.With(labels),
+		promhttp.InstrumentHandlerDuration(httpRequestDuration.MustCurryWith(labels),
+			promhttp.InstrumentHandlerCounter(httpRequestsTotal.MustCurryWith(labels),
+				promhttp.InstrumentHandlerResponseSize(httpResponseSize.MustCurryWith(labels), handler),
+			),
+		),
+	)
+}
@@ -0,0 +1,52 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package katamonitor
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInstrumentHandler(t *testing.T) {
+	assert := assert.New(t)
+
+	handler := InstrumentHandler("/test", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "only GET is supported", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Write([]byte("hello"))
+	}))
+
+	for _, method := range []string{http.MethodGet, http.MethodGet, http.MethodPost} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(method, "/test", nil))
+	}
+
+	var m dto.Metric
+	assert.NoError(httpRequestsTotal.WithLabelValues("/test", "200", "get").Write(&m))
+	assert.Equal(float64(2), m.GetCounter().GetValue())
+	assert.NoError(httpRequestsTotal.WithLabelValues("/test", "405", "post").Write(&m))
+	assert.Equal(float64(1), m.GetCounter().GetValue())
+
+	assert.NoError(httpRequestsInFlight.WithLabelValues("/test").Write(&m))
+	assert.Equal(float64(0), m.GetGauge().GetValue())
+
+	observer, err := httpResponseSize.GetMetricWithLabelValues("/test", "get")
+	assert.NoError(err)
+	assert.NoError(observer.(prometheus.Metric).Write(&m))
+	assert.Equal(uint64(2), m.GetHistogram().GetSampleCount())
+	assert.Equal(float64(10), m.GetHistogram().GetSampleSum())
+
+	observer, err = httpRequestDuration.GetMetricWithLabelValues("/test", "post")
+	assert.NoError(err)
+	assert.NoError(observer.(prometheus.Metric).Write(&m))
+	assert.Equal(uint64(1), m.GetHistogram().GetSampleCount())
+}
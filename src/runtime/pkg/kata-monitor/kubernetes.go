@@ -0,0 +1,366 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package katamonitor
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/containerd/containerd/containers"
+)
+
+const (
+	// the service account files mounted in the pods
+	inClusterTokenFile = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+	inClusterCAFile    = "/var/run/secrets/kubernetes.io/serviceaccount/ca.crt"
+
+	// the labels of the sandbox containers set by the CRI plugin
+	criPodNameLabel      = "io.kubernetes.pod.name"
+	criPodNamespaceLabel = "io.kubernetes.pod.namespace"
+	criPodUIDLabel       = "io.kubernetes.pod.uid"
+	criKindLabel         = "io.cri-containerd.kind"
+
+	kubeRequestTimeout = 10 * time.Second
+)
+
+// OwnerReference is an owner of a pod, e.g. its ReplicaSet.
+type OwnerReference struct {
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	UID        string `json:"uid"`
+	Controller bool   `json:"controller,omitempty"`
+}
+
+// PodInfo is the metadata of the pod of a sandbox.
+type PodInfo struct {
+	Name            string            `json:"name"`
+	Namespace       string            `json:"namespace"`
+	UID             string            `json:"uid"`
+	Labels          map[string]string `json:"labels,omitempty"`
+	OwnerReferences []OwnerReference  `json:"ownerReferences,omitempty"`
+}
+
+// controller returns the managing owner of the pod, if any.
+func (p *PodInfo) controller() *OwnerReference {
+	for i := range p.OwnerReferences {
+		if p.OwnerReferences[i].Controller {
+			return &p.OwnerReferences[i]
+		}
+	}
+	return nil
+}
+
+// KubeClient gets the pods of the sandboxes from the Kubernetes API server.
+type KubeClient struct {
+	apiServer string
+	tokenFile string
+	client    *http.Client
+}
+
+// NewKubeClient returns a client of the Kubernetes API server. The in-cluster
+// configuration of the kata-monitor pod is used for the unset parameters.
+func NewKubeClient(apiServer, tokenFile, caFile string) (*KubeClient, error) {
+	if apiServer == "" {
+		host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+		if host == "" || port == "" {
+			return nil, fmt.Errorf("no Kubernetes API server provided and not running in a cluster")
+		}
+		apiServer = "https://" + net.JoinHostPort(host, port)
+		if tokenFile == "" {
+			tokenFile = inClusterTokenFile
+		}
+		if caFile == "" {
+			caFile = inClusterCAFile
+		}
+	}
+
+	if _, err := url.Parse(apiServer); err != nil {
+		return nil, fmt.Errorf("invalid Kubernetes API server %v: %v", apiServer, err)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if caFile != "" {
+		ca, err := ioutil.ReadFile(caFile)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("no certificate found in %v", caFile)
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+
+	return &KubeClient{
+		apiServer: strings.TrimSuffix(apiServer, "/"),
+		tokenFile: tokenFile,
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   kubeRequestTimeout,
+		},
+	}, nil
+}
+
+// request sends a request to the API server and decodes the JSON response
+// in result when not nil. It returns the status of the response, and an
+// error if it is not a success.
+func (kc *KubeClient) request(method, path, contentType string, body []byte, result interface{}) (int, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequest(method, kc.apiServer+path, reader)
+	if err != nil {
+		return 0, err
+	}
+
+	// the bound service account tokens are rotated, read it every time
+	if kc.tokenFile != "" {
+		token, err := ioutil.ReadFile(kc.tokenFile)
+		if err != nil {
+			return 0, err
+		}
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	}
+	req.Header.Set("Accept", "application/json")
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := kc.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return resp.StatusCode, fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	if result != nil {
+		if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+			return resp.StatusCode, fmt.Errorf("invalid response: %v", err)
+		}
+	}
+
+	return resp.StatusCode, nil
+}
+
+// getPod returns the metadata of a pod.
+func (kc *KubeClient) getPod(namespace, name string) (*PodInfo, error) {
+	var pod struct {
+		Metadata PodInfo `json:"metadata"`
+	}
+
+	if _, err := kc.request(http.MethodGet,
+		fmt.Sprintf("/api/v1/namespaces/%s/pods/%s", url.PathEscape(namespace), url.PathEscape(name)), "", nil, &pod); err != nil {
+		return nil, fmt.Errorf("failed to get pod %s/%s: %v", namespace, name, err)
+	}
+
+	return &pod.Metadata, nil
+}
+
+// evictPod evicts a pod with the eviction API, respecting its disruption
+// budgets. It returns false if the eviction is blocked by a budget.
+func (kc *KubeClient) evictPod(namespace, name string) (bool, error) {
+	eviction, err := json.Marshal(map[string]interface{}{
+		"apiVersion": "policy/v1beta1",
+		"kind":       "Eviction",
+		"metadata": map[string]string{
+			"name":      name,
+			"namespace": namespace,
+		},
+	})
+	if err != nil {
+		return false, err
+	}
+
+	status, err := kc.request(http.MethodPost,
+		fmt.Sprintf("/api/v1/namespaces/%s/pods/%s/eviction", url.PathEscape(namespace), url.PathEscape(name)),
+		"application/json", eviction, nil)
+	switch {
+	case status == http.StatusTooManyRequests:
+		return false, nil
+	case status == http.StatusNotFound:
+		// deleted in the meantime
+		return true, nil
+	case err != nil:
+		return false, fmt.Errorf("failed to evict pod %s/%s: %v", namespace, name, err)
+	}
+
+	return true, nil
+}
+
+// the claims of the pod of the bound service account tokens
+const (
+	serviceAccountUserPrefix = "system:serviceaccount:"
+	tokenPodNameExtra        = "authentication.kubernetes.io/pod-name"
+	tokenPodUIDExtra         = "authentication.kubernetes.io/pod-uid"
+)
+
+// reviewToken authenticates a service account token with the TokenReview
+// API. It returns the pod the token is bound to, or nil if the token is not
+// authenticated for the audience or not bound to a pod.
+func (kc *KubeClient) reviewToken(token, audience string) (*PodInfo, error) {
+	review, err := json.Marshal(map[string]interface{}{
+		"apiVersion": "authentication.k8s.io/v1",
+		"kind":       "TokenReview",
+		"spec": map[string]interface{}{
+			"token":     token,
+			"audiences": []string{audience},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Status struct {
+			Authenticated bool `json:"authenticated"`
+			User          struct {
+				Username string              `json:"username"`
+				Extra    map[string][]string `json:"extra"`
+			} `json:"user"`
+			Audiences []string `json:"audiences"`
+		} `json:"status"`
+	}
+	if _, err := kc.request(http.MethodPost, "/apis/authentication.k8s.io/v1/tokenreviews",
+		"application/json", review, &result); err != nil {
+		return nil, fmt.Errorf("failed to review token: %v", err)
+	}
+
+	status := result.Status
+	if !status.Authenticated || !strings.HasPrefix(status.User.Username, serviceAccountUserPrefix) {
+		return nil, nil
+	}
+	if len(status.Audiences) > 0 {
+		found := false
+		for _, a := range status.Audiences {
+			found = found || a == audience
+		}
+		if !found {
+			return nil, nil
+		}
+	}
+
+	// system:serviceaccount:<namespace>:<name>
+	account := strings.SplitN(strings.TrimPrefix(status.User.Username, serviceAccountUserPrefix), ":", 2)
+	names, uids := status.User.Extra[tokenPodNameExtra], status.User.Extra[tokenPodUIDExtra]
+	if len(account) != 2 || len(names) != 1 || names[0] == "" {
+		return nil, nil
+	}
+
+	pod := &PodInfo{
+		Name:      names[0],
+		Namespace: account[0],
+	}
+	if len(uids) == 1 {
+		pod.UID = uids[0]
+	}
+
+	return pod, nil
+}
+
+// Taint is a taint of a node.
+type Taint struct {
+	Key       string `json:"key"`
+	Value     string `json:"value,omitempty"`
+	Effect    string `json:"effect"`
+	TimeAdded string `json:"timeAdded,omitempty"`
+}
+
+// setNodeTaint adds the taint to the node, or removes the taint with the
+// same key and effect when present is false. It returns true if the node
+// was changed.
+func (kc *KubeClient) setNodeTaint(node string, taint Taint, present bool) (bool, error) {
+	var n struct {
+		Metadata struct {
+			ResourceVersion string `json:"resourceVersion"`
+		} `json:"metadata"`
+		Spec struct {
+			Taints []Taint `json:"taints,omitempty"`
+		} `json:"spec"`
+	}
+
+	path := "/api/v1/nodes/" + url.PathEscape(node)
+	if _, err := kc.request(http.MethodGet, path, "", nil, &n); err != nil {
+		return false, fmt.Errorf("failed to get node %s: %v", node, err)
+	}
+
+	taints := []Taint{}
+	found := false
+	for _, t := range n.Spec.Taints {
+		if t.Key == taint.Key && t.Effect == taint.Effect {
+			found = true
+			if !present {
+				continue
+			}
+		}
+		taints = append(taints, t)
+	}
+
+	if found == present {
+		return false, nil
+	}
+	if present {
+		taints = append(taints, taint)
+	}
+
+	// the resource version makes the patch fail if the taints were
+	// changed in the meantime
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]string{"resourceVersion": n.Metadata.ResourceVersion},
+		"spec":     map[string]interface{}{"taints": taints},
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if _, err := kc.request(http.MethodPatch, path, "application/merge-patch+json", patch, nil); err != nil {
+		return false, fmt.Errorf("failed to update the taints of node %s: %v", node, err)
+	}
+
+	return true, nil
+}
+
+// criPod returns the pod of a sandbox container as known by the CRI plugin,
+// from the container labels: the pod labels are the ones set when the pod
+// was created and the owners are unknown. It returns nil if the sandbox is
+// not a Kubernetes pod.
+func criPod(c *containers.Container) *PodInfo {
+	name, namespace := c.Labels[criPodNameLabel], c.Labels[criPodNamespaceLabel]
+	if name == "" || namespace == "" {
+		return nil
+	}
+
+	pod := &PodInfo{
+		Name:      name,
+		Namespace: namespace,
+		UID:       c.Labels[criPodUIDLabel],
+		Labels:    make(map[string]string),
+	}
+
+	for key, value := range c.Labels {
+		if key != criKindLabel && !strings.HasPrefix(key, "io.kubernetes.") {
+			pod.Labels[key] = value
+		}
+	}
+
+	return pod
+}
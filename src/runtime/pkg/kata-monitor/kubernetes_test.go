@@ -0,0 +1,192 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package katamonitor
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/containerd/containerd/containers"
+	"github.com/stretchr/testify/assert"
+)
+
+const testPod = `{
+  "kind": "Pod",
+  "apiVersion": "v1",
+  "metadata": {
+    "name": "web-6d4b75cb6d-x2x7k",
+    "namespace": "default",
+    "uid": "6a9f8e5c-4a3e-4d5b-9a1b-1f2c3d4e5f60",
+    "labels": {"app": "web", "pod-template-hash": "6d4b75cb6d"},
+    "ownerReferences": [
+      {"apiVersion": "apps/v1", "kind": "ReplicaSet", "name": "web-6d4b75cb6d", "uid": "1234", "controller": true}
+    ]
+  },
+  "spec": {"nodeName": "node-1"}
+}`
+
+func newTestKubeServer(token string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if token != "" && r.Header.Get("Authorization") != "Bearer "+token {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/api/v1/namespaces/default/pods/web-6d4b75cb6d-x2x7k" {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, testPod)
+	}))
+}
+
+func TestKubeClientGetPod(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "kata-monitor")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	tokenFile := filepath.Join(dir, "token")
+	assert.NoError(ioutil.WriteFile(tokenFile, []byte("secret\n"), 0600))
+
+	server := newTestKubeServer("secret")
+	defer server.Close()
+
+	kube, err := NewKubeClient(server.URL, tokenFile, "")
+	assert.NoError(err)
+
+	pod, err := kube.getPod("default", "web-6d4b75cb6d-x2x7k")
+	assert.NoError(err)
+	assert.Equal("web-6d4b75cb6d-x2x7k", pod.Name)
+	assert.Equal("web", pod.Labels["app"])
+	assert.Equal("ReplicaSet", pod.controller().Kind)
+
+	_, err = kube.getPod("default", "missing")
+	assert.Error(err)
+
+	// wrong token
+	assert.NoError(ioutil.WriteFile(tokenFile, []byte("foo"), 0600))
+	_, err = kube.getPod("default", "web-6d4b75cb6d-x2x7k")
+	assert.Error(err)
+}
+
+func TestCRIPod(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Nil(criPod(&containers.Container{ID: "sandbox"}))
+
+	pod := criPod(&containers.Container{
+		ID: "sandbox",
+		Labels: map[string]string{
+			criPodNameLabel:      "web-6d4b75cb6d-x2x7k",
+			criPodNamespaceLabel: "default",
+			criPodUIDLabel:       "6a9f8e5c",
+			criKindLabel:         "sandbox",
+			"app":                "web",
+		},
+	})
+	assert.Equal(&PodInfo{
+		Name:      "web-6d4b75cb6d-x2x7k",
+		Namespace: "default",
+		UID:       "6a9f8e5c",
+		Labels:    map[string]string{"app": "web"},
+	}, pod)
+}
+
+func TestNewKubeClientInCluster(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, env := range []string{"KUBERNETES_SERVICE_HOST", "KUBERNETES_SERVICE_PORT"} {
+		if value, ok := os.LookupEnv(env); ok {
+			defer os.Setenv(env, value)
+		} else {
+			defer os.Unsetenv(env)
+		}
+		os.Unsetenv(env)
+	}
+
+	_, err := NewKubeClient("", "", "")
+	assert.Error(err)
+
+	os.Setenv("KUBERNETES_SERVICE_HOST", "10.0.0.1")
+	os.Setenv("KUBERNETES_SERVICE_PORT", "443")
+
+	// the CA of the in-cluster configuration is missing
+	_, err = NewKubeClient("", "", "")
+	assert.Error(err)
+}
+
+func TestSandboxPodMetadata(t *testing.T) {
+	assert := assert.New(t)
+
+	server := newTestKubeServer("")
+	defer server.Close()
+
+	kube, err := NewKubeClient(server.URL, "", "")
+	assert.NoError(err)
+
+	sc := &sandboxCache{
+		Mutex:       &sync.Mutex{},
+		sandboxes:   map[string]string{"sandbox": "k8s.io"},
+		podMetadata: PodMetadataFilter{Labels: []string{"app"}},
+		kube:        kube,
+	}
+	km := &KataMonitor{sandboxCache: sc}
+
+	c := &containers.Container{
+		ID: "sandbox",
+		Labels: map[string]string{
+			criPodNameLabel:      "web-6d4b75cb6d-x2x7k",
+			criPodNamespaceLabel: "default",
+			// set at creation, changed since then
+			"app": "old",
+		},
+	}
+
+	// the labels of the CRI are used until the pod is resolved
+	sc.setPodMetadata(c)
+	assert.Eventually(func() bool {
+		return sc.getMetricsLabels("sandbox")["owner_kind"] != ""
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(map[string]string{
+		"label_app":  "web",
+		"owner_kind": "ReplicaSet",
+		"owner_name": "web-6d4b75cb6d",
+	}, sc.getMetricsLabels("sandbox"))
+
+	rr := httptest.NewRecorder()
+	km.ListSandboxes(rr, httptest.NewRequest(http.MethodGet, "/sandboxes?format=json", nil))
+	assert.Equal(http.StatusOK, rr.Code)
+
+	var sandboxes []SandboxInfo
+	assert.NoError(json.Unmarshal(rr.Body.Bytes(), &sandboxes))
+	assert.Len(sandboxes, 1)
+	assert.Equal("k8s.io", sandboxes[0].Namespace)
+	assert.Equal("default", sandboxes[0].Pod.Namespace)
+
+	rr = httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/sandboxes", nil)
+	r.Header.Set("Accept", "text/plain")
+	km.ListSandboxes(rr, r)
+	assert.Equal("sandbox\n", rr.Body.String())
+
+	_, deleted := sc.deleteIfExists("sandbox")
+	assert.True(deleted)
+	assert.Nil(sc.getPod("sandbox"))
+
+	// the pod resolved after the sandbox is deleted is dropped
+	sc.resolvePod(c, criPod(c))
+	assert.Nil(sc.getPod("sandbox"))
+	assert.Nil(sc.getMetricsLabels("sandbox"))
+}
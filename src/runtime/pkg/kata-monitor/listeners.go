@@ -0,0 +1,261 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package katamonitor
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// unixSocketPrefix prefixes the listener addresses of the unix sockets.
+const unixSocketPrefix = "unix://"
+
+// ListenerConfig is a listener of kata-monitor, with its own authentication:
+// e.g. a unix socket for the agents of the node and a TCP address for
+// Prometheus.
+type ListenerConfig struct {
+	// Address is a TCP address, or the path of a unix socket
+	// prefixed with unix://.
+	Address string
+
+	// TLSCertFile and TLSKeyFile are the TLS certificate and private
+	// key of the listener, which serves HTTPS when they are set.
+	TLSCertFile string
+	TLSKeyFile  string
+
+	// TokenFile is the file of the bearer token required by the
+	// requests, none is required when empty.
+	TokenFile string
+
+	// ClientCAFile is the file of the CA certificates of the TLS client
+	// certificates, e.g. the requestheader client CA of the Kubernetes
+	// API aggregation layer. The clients with a certificate of these CAs
+	// are granted the custom metrics API only.
+	ClientCAFile string
+
+	// ClientNames are the common names allowed for the client
+	// certificates of ClientCAFile, any of them if empty.
+	ClientNames []string
+
+	// SocketMode is the permissions of the unix socket.
+	SocketMode os.FileMode
+
+	// Sandboxes authorizes the requests to the read-only endpoints of
+	// a sandbox with the service account token of its pod as well,
+	// when the token of TokenFile is not presented.
+	Sandboxes *SandboxAuthorizer
+}
+
+func (c ListenerConfig) socketPath() (string, bool) {
+	if !strings.HasPrefix(c.Address, unixSocketPrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(c.Address, unixSocketPrefix), true
+}
+
+// listen creates the listener, the stale unix socket of a previous
+// kata-monitor being removed.
+func (c ListenerConfig) listen() (net.Listener, error) {
+	path, ok := c.socketPath()
+	if !ok {
+		return net.Listen("tcp", c.Address)
+	}
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if c.SocketMode != 0 {
+		if err := os.Chmod(path, c.SocketMode); err != nil {
+			l.Close()
+			return nil, err
+		}
+	}
+
+	return l, nil
+}
+
+// tlsConfig returns the TLS configuration verifying the optional client
+// certificates of ClientCAFile.
+func (c ListenerConfig) tlsConfig() (*tls.Config, error) {
+	if c.TLSCertFile == "" {
+		return nil, fmt.Errorf("a client CA requires a TLS certificate")
+	}
+
+	content, err := ioutil.ReadFile(c.ClientCAFile)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(content) {
+		return nil, fmt.Errorf("no certificate in %s", c.ClientCAFile)
+	}
+
+	return &tls.Config{
+		ClientAuth: tls.VerifyClientCertIfGiven,
+		ClientCAs:  pool,
+	}, nil
+}
+
+// verifiedClient returns true if the client of the request presented a
+// certificate of ClientCAFile, with one of the allowed names.
+func (c ListenerConfig) verifiedClient(r *http.Request) bool {
+	if c.ClientCAFile == "" || r.TLS == nil || len(r.TLS.VerifiedChains) == 0 {
+		return false
+	}
+	if len(c.ClientNames) == 0 {
+		return true
+	}
+
+	name := r.TLS.VerifiedChains[0][0].Subject.CommonName
+	for _, n := range c.ClientNames {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// authorize checks the bearer token of a request against the one of the
+// token file, read on each request for the token to be rotated.
+func (c ListenerConfig) authorize(r *http.Request) (int, error) {
+	content, err := ioutil.ReadFile(c.TokenFile)
+	if err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("cannot read the token: %v", err)
+	}
+	token := strings.TrimSpace(string(content))
+	if token == "" {
+		return http.StatusForbidden, fmt.Errorf("the token is empty")
+	}
+
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "Bearer ") ||
+		subtle.ConstantTimeCompare([]byte(strings.TrimPrefix(auth, "Bearer ")), []byte(token)) != 1 {
+		return http.StatusUnauthorized, fmt.Errorf("invalid token")
+	}
+
+	return http.StatusOK, nil
+}
+
+// authenticatedKey marks the requests whose client is authenticated by the
+// listener, with its token or by the permissions of its unix socket.
+type authenticatedKey struct{}
+
+// authenticated returns true if the client of the request is authenticated
+// by the listener, as required by the endpoints running commands.
+func authenticated(r *http.Request) bool {
+	ok, _ := r.Context().Value(authenticatedKey{}).(bool)
+	return ok
+}
+
+func withAuthenticated(r *http.Request) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), authenticatedKey{}, true))
+}
+
+// clientCertKey marks the requests whose client presented a certificate of
+// the client CA of the listener.
+type clientCertKey struct{}
+
+// clientCertAuthenticated returns true if the client of the request
+// presented a certificate of the client CA of the listener, as required by
+// the custom metrics API from the unauthenticated clients.
+func clientCertAuthenticated(r *http.Request) bool {
+	ok, _ := r.Context().Value(clientCertKey{}).(bool)
+	return ok
+}
+
+func withClientCert(r *http.Request) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), clientCertKey{}, true))
+}
+
+// handler returns the handler of the listener, checking the token of the
+// requests if required.
+func (c ListenerConfig) handler(handler http.Handler) http.Handler {
+	if c.TokenFile == "" {
+		_, isSocket := c.socketPath()
+		if !isSocket && c.ClientCAFile == "" {
+			return handler
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if isSocket {
+				r = withAuthenticated(r)
+			}
+			if c.verifiedClient(r) {
+				r = withClientCert(r)
+			}
+			handler.ServeHTTP(w, r)
+		})
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status, err := c.authorize(r)
+		if err == nil {
+			r = withAuthenticated(r)
+		} else if status == http.StatusUnauthorized && c.verifiedClient(r) {
+			// the client certificates only grant the custom metrics API
+			if strings.HasPrefix(r.URL.Path, CustomMetricsAPIPrefix) {
+				r, err = withClientCert(r), nil
+			}
+		} else if status == http.StatusUnauthorized && c.Sandboxes != nil {
+			// the sandbox tokens only grant the read-only endpoints
+			if sandboxID, ok := sandboxScopedRequest(r); ok {
+				status, err = c.Sandboxes.authorize(r, sandboxID)
+			}
+		}
+		if err != nil {
+			http.Error(w, err.Error(), status)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// Serve serves the handler on all the listeners, until one of them fails.
+func Serve(handler http.Handler, listeners ...ListenerConfig) error {
+	if len(listeners) == 0 {
+		return fmt.Errorf("no listen address")
+	}
+
+	errs := make(chan error, len(listeners))
+	for _, c := range listeners {
+		l, err := c.listen()
+		if err != nil {
+			return fmt.Errorf("failed to listen on %s: %v", c.Address, err)
+		}
+
+		monitorLog.WithField("address", c.Address).WithField("tls", c.TLSCertFile != "").
+			WithField("token", c.TokenFile != "").WithField("client_ca", c.ClientCAFile != "").
+			WithField("sandbox_tokens", c.Sandboxes != nil).Info("listening")
+
+		svr := &http.Server{Handler: c.handler(handler)}
+		if c.ClientCAFile != "" {
+			if svr.TLSConfig, err = c.tlsConfig(); err != nil {
+				l.Close()
+				return fmt.Errorf("failed to listen on %s: %v", c.Address, err)
+			}
+		}
+		go func(c ListenerConfig) {
+			if c.TLSCertFile != "" {
+				errs <- svr.ServeTLS(l, c.TLSCertFile, c.TLSKeyFile)
+				return
+			}
+			errs <- svr.Serve(l)
+		}(c)
+	}
+
+	return <-errs
+}
@@ -0,0 +1,172 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package katamonitor
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListenerConfigHandler(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "kata-monitor")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+	tokenFile := filepath.Join(dir, "token")
+	assert.NoError(ioutil.WriteFile(tokenFile, []byte("secret\n"), 0600))
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	request := func(c ListenerConfig, token string) int {
+		r := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		if token != "" {
+			r.Header.Set("Authorization", "Bearer "+token)
+		}
+		rr := httptest.NewRecorder()
+		c.handler(ok).ServeHTTP(rr, r)
+		return rr.Code
+	}
+
+	// no token required
+	assert.Equal(http.StatusOK, request(ListenerConfig{}, ""))
+
+	c := ListenerConfig{TokenFile: tokenFile}
+	assert.Equal(http.StatusUnauthorized, request(c, ""))
+	assert.Equal(http.StatusUnauthorized, request(c, "wrong"))
+	assert.Equal(http.StatusOK, request(c, "secret"))
+
+	c.TokenFile = filepath.Join(dir, "missing")
+	assert.Equal(http.StatusInternalServerError, request(c, "secret"))
+
+	// the clients authenticated by the listener
+	isAuthenticated := func(c ListenerConfig, token string) bool {
+		var result bool
+		r := httptest.NewRequest(http.MethodGet, "/sandboxes", nil)
+		r.Header.Set("Authorization", "Bearer "+token)
+		c.handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			result = authenticated(r)
+		})).ServeHTTP(httptest.NewRecorder(), r)
+		return result
+	}
+	assert.False(isAuthenticated(ListenerConfig{Address: ":8090"}, ""))
+	assert.True(isAuthenticated(ListenerConfig{Address: ":8090", TokenFile: tokenFile}, "secret"))
+	assert.True(isAuthenticated(ListenerConfig{Address: "unix:///run/kata-monitor.sock"}, ""))
+}
+
+func TestListenerConfigClientCert(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "kata-monitor")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+	tokenFile := filepath.Join(dir, "token")
+	assert.NoError(ioutil.WriteFile(tokenFile, []byte("secret"), 0600))
+
+	// the status of a request of a client verified by the TLS handshake,
+	// and whether it is authenticated by its certificate
+	request := func(c ListenerConfig, path, name string) (int, bool) {
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		if name != "" {
+			cert := &x509.Certificate{Subject: pkix.Name{CommonName: name}}
+			r.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}
+		}
+		var result bool
+		rr := httptest.NewRecorder()
+		c.handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			result = clientCertAuthenticated(r)
+		})).ServeHTTP(rr, r)
+		return rr.Code, result
+	}
+
+	c := ListenerConfig{Address: ":8090", ClientCAFile: filepath.Join(dir, "ca.crt")}
+	code, ok := request(c, CustomMetricsAPIPrefix, "front-proxy-client")
+	assert.Equal(http.StatusOK, code)
+	assert.True(ok)
+	_, ok = request(c, CustomMetricsAPIPrefix, "")
+	assert.False(ok)
+
+	// only the allowed names
+	c.ClientNames = []string{"aggregator"}
+	_, ok = request(c, CustomMetricsAPIPrefix, "front-proxy-client")
+	assert.False(ok)
+	_, ok = request(c, CustomMetricsAPIPrefix, "aggregator")
+	assert.True(ok)
+
+	// the certificates replace the token on the custom metrics API only
+	c.TokenFile = tokenFile
+	code, ok = request(c, CustomMetricsAPIPrefix+"/namespaces/default/pods/web/kata_vm_overhead_cpu", "aggregator")
+	assert.Equal(http.StatusOK, code)
+	assert.True(ok)
+	code, _ = request(c, "/sandboxes", "aggregator")
+	assert.Equal(http.StatusUnauthorized, code)
+
+	// not without TLS
+	_, err = ListenerConfig{ClientCAFile: c.ClientCAFile}.tlsConfig()
+	assert.Error(err)
+}
+
+func TestServe(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "kata-monitor")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+	socket := filepath.Join(dir, "monitor.sock")
+	tokenFile := filepath.Join(dir, "token")
+	assert.NoError(ioutil.WriteFile(tokenFile, []byte("secret"), 0600))
+
+	// stale socket of a previous kata-monitor
+	assert.NoError(ioutil.WriteFile(socket, nil, 0600))
+
+	assert.Error(Serve(http.NotFoundHandler()))
+
+	go Serve(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+		ListenerConfig{Address: unixSocketPrefix + socket, TokenFile: tokenFile, SocketMode: 0640},
+	)
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return (&net.Dialer{}).DialContext(ctx, "unix", socket)
+			},
+		},
+		Timeout: 5 * time.Second,
+	}
+	get := func(token string) int {
+		r, err := http.NewRequest(http.MethodGet, "http://kata-monitor/metrics", nil)
+		assert.NoError(err)
+		if token != "" {
+			r.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := client.Do(r)
+		if err != nil {
+			return 0
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	assert.Eventually(func() bool {
+		return get("secret") == http.StatusOK
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(http.StatusUnauthorized, get(""))
+
+	info, err := os.Stat(socket)
+	assert.NoError(err)
+	assert.Equal(os.ModeSocket|0640, info.Mode())
+}
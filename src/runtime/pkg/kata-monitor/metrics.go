@@ -8,6 +8,7 @@ package katamonitor
 import (
 	"bytes"
 	"compress/gzip"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
@@ -19,9 +20,12 @@ import (
 
 	"github.com/kata-containers/kata-containers/src/runtime/pkg/types"
 	mutils "github.com/kata-containers/kata-containers/src/runtime/pkg/utils"
+	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/common/expfmt"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 
 	dto "github.com/prometheus/client_model/go"
 )
@@ -30,8 +34,19 @@ const (
 	promNamespaceMonitor  = "kata_monitor"
 	contentTypeHeader     = "Content-Type"
 	contentEncodingHeader = "Content-Encoding"
+
+	// the build information metric of the shims, and the version of
+	// the shims not exposing it
+	shimBuildInfoMetric = "kata_shim_build_info"
+	unknownShimVersion  = "unknown"
+
+	// the largest metrics response of a shim, bounding the memory used
+	// to decode it
+	maxShimMetricsSize = 32 << 20
 )
 
+var errShimMetricsTooLarge = fmt.Errorf("metrics of the shim larger than %d bytes", maxShimMetricsSize)
+
 var (
 	runningShimCount = prometheus.NewGauge(prometheus.GaugeOpts{
 		Namespace: promNamespaceMonitor,
@@ -39,6 +54,12 @@ var (
 		Help:      "Running shim count(running sandboxes).",
 	})
 
+	sandboxTaskStates = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: promNamespaceMonitor,
+		Name:      "sandboxes",
+		Help:      "Sandboxes by the state of their task, created, running or stopped.",
+	}, []string{"state"})
+
 	scrapeCount = prometheus.NewCounter(prometheus.CounterOpts{
 		Namespace: promNamespaceMonitor,
 		Name:      "scrape_count",
@@ -58,6 +79,50 @@ var (
 		Buckets:   prometheus.ExponentialBuckets(1, 2, 10),
 	})
 
+	scrapeShimsInFlight = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: promNamespaceMonitor,
+		Name:      "scrape_shims_in_flight",
+		Help:      "Shims being scraped concurrently.",
+	})
+
+	lastScrapeShims = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: promNamespaceMonitor,
+		Name:      "last_scrape_shims",
+		Help:      "Shims scraped by the last scrape.",
+	})
+
+	lastScrapeFailedShims = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: promNamespaceMonitor,
+		Name:      "last_scrape_failed_shims",
+		Help:      "Shims failing to be scraped by the last scrape.",
+	})
+
+	lastScrapeSlowestShim = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: promNamespaceMonitor,
+		Name:      "last_scrape_slowest_shim_seconds",
+		Help:      "Time to scrape the slowest shim of the last scrape(seconds).",
+	})
+
+	eventsDisconnects = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: promNamespaceMonitor,
+		Name:      "containerd_events_disconnects_total",
+		Help:      "Failures of the containerd events stream, resubscribed after a backoff.",
+	})
+
+	eventsResyncs = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: promNamespaceMonitor,
+		Name:      "containerd_events_resyncs_total",
+		Help:      "Resyncs of the sandbox cache after resubscribing to the containerd events.",
+	})
+
+	admissionRejections = &admissionCollector{
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(promNamespaceMonitor, "", "admission_rejections_total"),
+			"Sandboxes not fitting in the host free memory, rejected in hard mode and launched anyway in soft mode.",
+			[]string{"mode"}, nil,
+		),
+	}
+
 	gzipPool = sync.Pool{
 		New: func() interface{} {
 			return gzip.NewWriter(nil)
@@ -67,14 +132,54 @@ var (
 
 func registerMetrics() {
 	prometheus.MustRegister(runningShimCount)
+	prometheus.MustRegister(sandboxTaskStates)
 	prometheus.MustRegister(scrapeCount)
 	prometheus.MustRegister(scrapeFailedCount)
 	prometheus.MustRegister(scrapeDurationsHistogram)
+	prometheus.MustRegister(scrapeShimsInFlight)
+	prometheus.MustRegister(lastScrapeShims)
+	prometheus.MustRegister(lastScrapeFailedShims)
+	prometheus.MustRegister(lastScrapeSlowestShim)
+	prometheus.MustRegister(admissionRejections)
+	prometheus.MustRegister(eventsDisconnects)
+	prometheus.MustRegister(eventsResyncs)
+	registerHTTPMetrics()
 }
 
-// getMonitorAddress get metrics address for a sandbox, the abstract unix socket address is saved
-// in `metrics_address` with the same place of `address`.
+// admissionCollector exports the counts of the admission rejections kept by
+// the runtimes on the host: the shims of the rejected sandboxes exit before
+// they are scraped.
+type admissionCollector struct {
+	desc *prometheus.Desc
+}
+
+func (c *admissionCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *admissionCollector) Collect(ch chan<- prometheus.Metric) {
+	rejections, err := vc.GetAdmissionRejections()
+	if err != nil {
+		monitorLog.WithError(err).Warn("failed to get the admission rejections")
+		return
+	}
+
+	for mode, count := range rejections {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.CounterValue, float64(count), mode)
+	}
+}
+
+// getMonitorAddress get metrics address for a sandbox, the unix socket address is saved
+// in `monitor_address` with the same place of `address`. It is an abstract socket address,
+// or a pathname socket one prefixed with unix://.
 func (km *KataMonitor) getMonitorAddress(sandboxID, namespace string) (string, error) {
+	if err := validateSandboxID(sandboxID); err != nil {
+		return "", err
+	}
+	if err := validateNamespace(namespace); err != nil {
+		return "", err
+	}
+
 	path := filepath.Join(km.containerdStatePath, types.ContainerdRuntimeTaskPath, namespace, sandboxID, "monitor_address")
 	data, err := ioutil.ReadFile(path)
 	if err != nil {
@@ -83,17 +188,30 @@ func (km *KataMonitor) getMonitorAddress(sandboxID, namespace string) (string, e
 	return string(data), nil
 }
 
+// getSandboxMonitorAddress returns the metrics address of a sandbox in the cache.
+func (km *KataMonitor) getSandboxMonitorAddress(sandboxID string) (string, error) {
+	namespace, err := km.getSandboxNamespace(sandboxID)
+	if err != nil {
+		return "", err
+	}
+
+	return km.getMonitorAddress(sandboxID, namespace)
+}
+
 // ProcessMetricsRequest get metrics from shim/hypervisor/vm/agent and return metrics to client.
 func (km *KataMonitor) ProcessMetricsRequest(w http.ResponseWriter, r *http.Request) {
 	start := time.Now()
 
 	scrapeCount.Inc()
+	traceID := traceIDFromRequest(r)
 	defer func() {
-		scrapeDurationsHistogram.Observe(float64(time.Since(start).Nanoseconds() / int64(time.Millisecond)))
+		observeScrapeDuration(time.Since(start), traceID)
 	}()
 
 	// prepare writer for writing response.
-	contentType := expfmt.Negotiate(r.Header)
+	// OpenMetrics is negotiated when accepted by the client, as exemplars
+	// can only be exposed in this format.
+	contentType := expfmt.NegotiateIncludingOpenMetrics(r.Header)
 
 	// set response header
 	header := w.Header()
@@ -114,6 +232,10 @@ func (km *KataMonitor) ProcessMetricsRequest(w http.ResponseWriter, r *http.Requ
 
 	// create encoder to encode metrics.
 	encoder := expfmt.NewEncoder(writer, contentType)
+	if closer, ok := encoder.(expfmt.Closer); ok {
+		// OpenMetrics needs a final `# EOF` line.
+		defer closer.Close()
+	}
 
 	// gather metrics collected for management agent.
 	mfs, err := prometheus.DefaultGatherer.Gather()
@@ -136,6 +258,52 @@ func (km *KataMonitor) ProcessMetricsRequest(w http.ResponseWriter, r *http.Requ
 	}
 }
 
+// SandboxMetrics serves the metrics of a sandbox at /sandboxes/<id>/metrics,
+// with the labels of its pod, e.g. for its owner to read them without
+// access to the metrics of the node.
+func (km *KataMonitor) SandboxMetrics(w http.ResponseWriter, r *http.Request, sandboxID string) {
+	if status, err := km.checkSandbox(sandboxID); err != nil {
+		commonServeError(w, status, err)
+		return
+	}
+
+	mfs, err := getParsedMetrics(sandboxID)
+	if err != nil {
+		commonServeError(w, http.StatusBadGateway, err)
+		return
+	}
+	addMetricsLabels(mfs, km.sandboxCache.getMetricsLabels(sandboxID))
+
+	contentType := expfmt.Negotiate(r.Header)
+	w.Header().Set(contentTypeHeader, string(contentType))
+	if err := encodeMetricFamily(mfs, expfmt.NewEncoder(w, contentType)); err != nil {
+		monitorLog.WithError(err).WithField("sandbox_id", sandboxID).Warn("failed to encode metrics")
+	}
+}
+
+// traceIDFromRequest returns the trace id carried by the W3C trace context
+// headers of the scrape request, or an empty string if there is none.
+func traceIDFromRequest(r *http.Request) string {
+	ctx := propagation.TraceContext{}.Extract(r.Context(), r.Header)
+	sc := trace.RemoteSpanContextFromContext(ctx)
+	if !sc.HasTraceID() {
+		return ""
+	}
+	return sc.TraceID.String()
+}
+
+// observeScrapeDuration records the scrape duration, with the trace id of the
+// scrape attached as an exemplar if present, so the scrape can be correlated
+// with its trace.
+func observeScrapeDuration(d time.Duration, traceID string) {
+	value := float64(d.Nanoseconds() / int64(time.Millisecond))
+	if observer, ok := scrapeDurationsHistogram.(prometheus.ExemplarObserver); ok && traceID != "" {
+		observer.ObserveWithExemplar(value, prometheus.Labels{"trace_id": traceID})
+		return
+	}
+	scrapeDurationsHistogram.Observe(value)
+}
+
 func encodeMetricFamily(mfs []*dto.MetricFamily, encoder expfmt.Encoder) error {
 	for i := range mfs {
 		metricFamily := mfs[i]
@@ -158,29 +326,54 @@ func (km *KataMonitor) aggregateSandboxMetrics(encoder expfmt.Encoder) error {
 	sandboxes := km.sandboxCache.getAllSandboxes()
 	// save running kata pods as a metrics.
 	runningShimCount.Set(float64(len(sandboxes)))
+	for state, count := range km.sandboxCache.countTaskStates() {
+		sandboxTaskStates.WithLabelValues(state).Set(float64(count))
+	}
 
 	if len(sandboxes) == 0 {
+		km.scrapeStatus.prune(sandboxes)
+		lastScrapeShims.Set(0)
+		lastScrapeFailedShims.Set(0)
+		lastScrapeSlowestShim.Set(0)
 		return nil
 	}
 
-	// sandboxMetricsList contains list of MetricFamily list from one sandbox.
-	sandboxMetricsList := make([][]*dto.MetricFamily, 0)
-
 	wg := &sync.WaitGroup{}
 	// used to receive response
 	results := make(chan []*dto.MetricFamily, len(sandboxes))
 
 	monitorLog.WithField("sandbox_count", len(sandboxes)).Debugf("sandboxes count")
 
+	// the fan-out of the scrape to the shims
+	var failed int64
+	var slowest time.Duration
+	var fanoutLock sync.Mutex
+
 	// get metrics from sandbox's shim
 	for sandboxID, namespace := range sandboxes {
 		wg.Add(1)
 		go func(sandboxID, namespace string, results chan<- []*dto.MetricFamily) {
+			scrapeShimsInFlight.Inc()
+			start := time.Now()
 			sandboxMetrics, err := getParsedMetrics(sandboxID)
+			scrapeShimsInFlight.Dec()
+
+			fanoutLock.Lock()
+			if d := time.Since(start); d > slowest {
+				slowest = d
+			}
+			if err != nil {
+				failed++
+			}
+			fanoutLock.Unlock()
+
+			km.scrapeStatus.record(sandboxID, start, err)
 			if err != nil {
 				monitorLog.WithError(err).WithField("sandbox_id", sandboxID).Errorf("failed to get metrics for sandbox")
 			}
 
+			addMetricsLabels(sandboxMetrics, km.sandboxCache.getMetricsLabels(sandboxID))
+
 			results <- sandboxMetrics
 			wg.Done()
 			monitorLog.WithField("sandbox_id", sandboxID).Debug("job finished")
@@ -189,57 +382,199 @@ func (km *KataMonitor) aggregateSandboxMetrics(encoder expfmt.Encoder) error {
 		monitorLog.WithField("sandbox_id", sandboxID).Debug("job started")
 	}
 
-	wg.Wait()
-	monitorLog.Debug("all job finished")
-	close(results)
+	go func() {
+		wg.Wait()
+		monitorLog.Debug("all job finished")
+		close(results)
+	}()
 
-	// get all job result from chan
+	// metricsMap used to aggregate metrics from multiple sandboxes, key is
+	// MetricFamily.Name. The metrics of each sandbox are merged as soon as
+	// they are received, so that they are not held twice.
+	metricsMap := make(map[string]*dto.MetricFamily)
+	scraped := 0
 	for sandboxMetrics := range results {
 		if sandboxMetrics != nil {
-			sandboxMetricsList = append(sandboxMetricsList, sandboxMetrics)
+			mergeMetricFamilies(metricsMap, sandboxMetrics)
+			scraped++
 		}
 	}
 
-	if len(sandboxMetricsList) == 0 {
+	km.scrapeStatus.prune(sandboxes)
+	lastScrapeShims.Set(float64(len(sandboxes)))
+	lastScrapeFailedShims.Set(float64(failed))
+	lastScrapeSlowestShim.Set(slowest.Seconds())
+
+	if scraped == 0 {
 		return nil
 	}
 
-	// metricsMap used to aggregate metrics from multiple sandboxes
-	// key is MetricFamily.Name, and value is list of MetricFamily from multiple sandboxes
-	metricsMap := make(map[string]*dto.MetricFamily)
-	// merge MetricFamily list for the same MetricFamily.Name from multiple sandboxes.
-	for i := range sandboxMetricsList {
-		sandboxMetrics := sandboxMetricsList[i]
-		for j := range sandboxMetrics {
-			mf := sandboxMetrics[j]
-			key := *mf.Name
-
-			// add MetricFamily.Metric to the exists MetricFamily instance
-			if oldmf, found := metricsMap[key]; found {
-				oldmf.Metric = append(oldmf.Metric, mf.Metric...)
-			} else {
-				metricsMap[key] = mf
-			}
+	if mf := shimVersionCounts(metricsMap[shimBuildInfoMetric], scraped); mf != nil {
+		if err := encoder.Encode(mf); err != nil {
+			return err
 		}
 	}
 
 	// write metrics to response.
-	for _, mf := range metricsMap {
+	return encodeMergedMetrics(metricsMap, encoder)
+}
+
+// mergeMetricFamilies merges the MetricFamily list of a sandbox into the
+// ones of the other sandboxes, by MetricFamily.Name.
+func mergeMetricFamilies(metricsMap map[string]*dto.MetricFamily, mfs []*dto.MetricFamily) {
+	for _, mf := range mfs {
+		key := mf.GetName()
+
+		// add MetricFamily.Metric to the exists MetricFamily instance
+		if oldmf, found := metricsMap[key]; found {
+			oldmf.Metric = append(oldmf.Metric, mf.Metric...)
+		} else {
+			metricsMap[key] = mf
+		}
+	}
+}
+
+// encodeMergedMetrics encodes the merged metrics by name, each MetricFamily
+// being removed from the map once written so that its memory can be
+// reclaimed while the next ones are written.
+func encodeMergedMetrics(metricsMap map[string]*dto.MetricFamily, encoder expfmt.Encoder) error {
+	names := make([]string, 0, len(metricsMap))
+	for name := range metricsMap {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		mf := metricsMap[name]
+		delete(metricsMap, name)
+
 		if err := encoder.Encode(mf); err != nil {
 			return err
 		}
 	}
+
 	return nil
+}
+
+// shimVersionCounts returns the number of running shims per version, from
+// the build information metrics of the shims, to track the nodes running
+// mixed versions. The shims without build information have an unknown version.
+func shimVersionCounts(buildInfo *dto.MetricFamily, shims int) *dto.MetricFamily {
+	counts := make(map[string]int)
+	if buildInfo != nil {
+		for _, metric := range buildInfo.Metric {
+			for _, label := range metric.Label {
+				if label.GetName() == "version" {
+					counts[label.GetValue()]++
+					shims--
+					break
+				}
+			}
+		}
+	}
+	if shims > 0 {
+		counts[unknownShimVersion] += shims
+	}
+
+	if len(counts) == 0 {
+		return nil
+	}
+
+	versions := make([]string, 0, len(counts))
+	for version := range counts {
+		versions = append(versions, version)
+	}
+	sort.Strings(versions)
+
+	mf := &dto.MetricFamily{
+		Name: mutils.String2Pointer(promNamespaceMonitor + "_running_shim_versions"),
+		Help: mutils.String2Pointer("Running shim count per shim version."),
+		Type: dto.MetricType_GAUGE.Enum(),
+	}
+	for _, version := range versions {
+		value := float64(counts[version])
+		mf.Metric = append(mf.Metric, &dto.Metric{
+			Label: []*dto.LabelPair{{
+				Name:  mutils.String2Pointer("version"),
+				Value: mutils.String2Pointer(version),
+			}},
+			Gauge: &dto.Gauge{Value: &value},
+		})
+	}
 
+	return mf
 }
 
+// getParsedMetrics decodes the metrics of a sandbox as they are read from
+// its shim, without buffering the response. The response is limited to
+// maxShimMetricsSize.
 func getParsedMetrics(sandboxID string) ([]*dto.MetricFamily, error) {
-	body, err := doGet(sandboxID, defaultTimeout, "metrics")
+	body, format, err := newShimClient(sandboxID, defaultTimeout).MetricsStream()
 	if err != nil {
 		return nil, err
 	}
+	defer body.Close()
+
+	return decodeShimMetrics(sandboxID, body, format, maxShimMetricsSize)
+}
+
+// decodeShimMetrics decodes the metrics of a sandbox from the response of
+// its shim, in the protobuf delimited or text format, failing if it is
+// larger than maxSize.
+func decodeShimMetrics(sandboxID string, body io.Reader, format expfmt.Format, maxSize int64) ([]*dto.MetricFamily, error) {
+	r := &sizeLimitedReader{r: body, remaining: maxSize}
+	list, err := decodePrometheusMetrics(sandboxID, r, format)
+	// the decoder ends without error when the reader fails at the start
+	// of a line
+	if r.remaining < 0 {
+		return nil, errShimMetricsTooLarge
+	}
+	return list, err
+}
+
+// sizeLimitedReader fails when more than remaining bytes are read, instead
+// of truncating the metrics like io.LimitReader.
+type sizeLimitedReader struct {
+	r         io.Reader
+	remaining int64
+}
+
+func (l *sizeLimitedReader) Read(p []byte) (int, error) {
+	// one byte more than the limit is read to detect it is exceeded
+	if int64(len(p)) > l.remaining+1 {
+		p = p[:l.remaining+1]
+	}
+
+	n, err := l.r.Read(p)
+	if l.remaining -= int64(n); l.remaining < 0 {
+		return n, errShimMetricsTooLarge
+	}
+	return n, err
+}
+
+// addMetricsLabels adds the labels to all metrics of the MetricFamily list,
+// the labels are sorted by name to have a stable output.
+func addMetricsLabels(mfs []*dto.MetricFamily, labels map[string]string) {
+	if len(labels) == 0 {
+		return
+	}
 
-	return parsePrometheusMetrics(sandboxID, body)
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, mf := range mfs {
+		for _, metric := range mf.Metric {
+			for _, name := range names {
+				metric.Label = append(metric.Label, &dto.LabelPair{
+					Name:  mutils.String2Pointer(name),
+					Value: mutils.String2Pointer(labels[name]),
+				})
+			}
+		}
+	}
 }
 
 // GetSandboxMetrics will get sandbox's metrics from shim
@@ -255,8 +590,14 @@ func GetSandboxMetrics(sandboxID string) (string, error) {
 // parsePrometheusMetrics will decode metrics from Prometheus text format
 // and return array of *dto.MetricFamily with an ASC order
 func parsePrometheusMetrics(sandboxID string, body []byte) ([]*dto.MetricFamily, error) {
-	reader := bytes.NewReader(body)
-	decoder := expfmt.NewDecoder(reader, expfmt.FmtText)
+	return decodePrometheusMetrics(sandboxID, bytes.NewReader(body), expfmt.FmtText)
+}
+
+// decodePrometheusMetrics decodes the metrics of a sandbox in format as they
+// are read from reader, see parsePrometheusMetrics. The text decoder is used
+// for the unknown formats.
+func decodePrometheusMetrics(sandboxID string, reader io.Reader, format expfmt.Format) ([]*dto.MetricFamily, error) {
+	decoder := expfmt.NewDecoder(reader, format)
 
 	// decode metrics from sandbox to MetricFamily
 	list := make([]*dto.MetricFamily, 0)
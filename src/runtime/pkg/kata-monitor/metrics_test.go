@@ -7,10 +7,12 @@ package katamonitor
 
 import (
 	"bytes"
+	"net/http"
 	"strings"
 	"testing"
 
 	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
 	"github.com/prometheus/common/expfmt"
 	"github.com/stretchr/testify/assert"
 )
@@ -137,3 +139,134 @@ func TestEncodeMetricFamily(t *testing.T) {
 		}
 	}
 }
+
+func TestTraceIDFromRequest(t *testing.T) {
+	assert := assert.New(t)
+
+	r, err := http.NewRequest("GET", "http://localhost:8090/metrics", nil)
+	assert.NoError(err)
+	assert.Equal("", traceIDFromRequest(r))
+
+	r.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	assert.Equal("4bf92f3577b34da6a3ce929d0e0e4736", traceIDFromRequest(r))
+
+	r.Header.Set("traceparent", "invalid")
+	assert.Equal("", traceIDFromRequest(r))
+}
+
+func TestAddMetricsLabels(t *testing.T) {
+	assert := assert.New(t)
+	sandboxID := "sandboxID-abc"
+
+	list, err := parsePrometheusMetrics(sandboxID, []byte(shimMetricBody))
+	assert.NoError(err)
+
+	addMetricsLabels(list, nil)
+	for _, mf := range list {
+		for _, m := range mf.Metric {
+			assert.Equal(1, len(m.Label))
+		}
+	}
+
+	addMetricsLabels(list, map[string]string{"label_team": "kata", "label_app": "web"})
+	for _, mf := range list {
+		for _, m := range mf.Metric {
+			assert.Equal(3, len(m.Label))
+			assert.Equal("sandbox_id", m.Label[0].GetName())
+			assert.Equal("label_app", m.Label[1].GetName())
+			assert.Equal("web", m.Label[1].GetValue())
+			assert.Equal("label_team", m.Label[2].GetName())
+			assert.Equal("kata", m.Label[2].GetValue())
+		}
+	}
+}
+
+func TestShimVersionCounts(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Nil(shimVersionCounts(nil, 0))
+
+	// the build information of three shims, aggregated by kata-monitor
+	list, err := parsePrometheusMetrics("sandboxID-abc", []byte(`# HELP kata_shim_build_info Kata containerd shim v2 build information.
+# TYPE kata_shim_build_info gauge
+kata_shim_build_info{version="2.1.1",commit="abcdef"} 1
+kata_shim_build_info{version="2.2.0",commit="123456"} 1
+kata_shim_build_info{version="2.2.0",commit="123456"} 1
+`))
+	assert.NoError(err)
+	assert.Len(list, 1)
+
+	// one more shim predates the build information
+	mf := shimVersionCounts(list[0], 4)
+	assert.Equal("kata_monitor_running_shim_versions", mf.GetName())
+
+	counts := make(map[string]float64)
+	for _, m := range mf.Metric {
+		assert.Len(m.Label, 1)
+		counts[m.Label[0].GetValue()] = m.GetGauge().GetValue()
+	}
+	assert.Equal(map[string]float64{"2.1.1": 1, "2.2.0": 2, unknownShimVersion: 1}, counts)
+
+	mf = shimVersionCounts(nil, 2)
+	assert.Len(mf.Metric, 1)
+	assert.Equal(unknownShimVersion, mf.Metric[0].Label[0].GetValue())
+	assert.Equal(float64(2), mf.Metric[0].GetGauge().GetValue())
+}
+
+func TestMergeMetricFamilies(t *testing.T) {
+	assert := assert.New(t)
+
+	metricsMap := make(map[string]*dto.MetricFamily)
+	for _, sandboxID := range []string{"sandbox-a", "sandbox-b"} {
+		list, err := parsePrometheusMetrics(sandboxID, []byte(shimMetricBody))
+		assert.NoError(err)
+		mergeMetricFamilies(metricsMap, list)
+	}
+
+	assert.Len(metricsMap, 4)
+	assert.Len(metricsMap["ttt"].Metric, 2)
+
+	buf := &bytes.Buffer{}
+	assert.NoError(encodeMergedMetrics(metricsMap, expfmt.NewEncoder(buf, expfmt.FmtText)))
+	assert.Empty(metricsMap)
+
+	// each family is written once, sorted by name
+	var names []string
+	for _, line := range strings.Split(buf.String(), "\n") {
+		if strings.HasPrefix(line, "# TYPE ") {
+			names = append(names, strings.Fields(line)[2])
+		}
+	}
+	assert.Equal([]string{"kata_shim_go_gc_duration_seconds", "kata_shim_go_threads", "kata_shim_process_open_fds", "ttt"}, names)
+	assert.Contains(buf.String(), `ttt{sandbox_id="sandbox-b"} 999`)
+}
+
+func TestDecodeShimMetrics(t *testing.T) {
+	assert := assert.New(t)
+
+	// the metrics of the size limit are decoded
+	list, err := decodeShimMetrics("sandboxID-abc", strings.NewReader(shimMetricBody), expfmt.FmtText, int64(len(shimMetricBody)))
+	assert.NoError(err)
+	assert.Len(list, 4)
+
+	for _, size := range []int{len(shimMetricBody) - 1, len(shimMetricBody) / 2} {
+		_, err = decodeShimMetrics("sandboxID-abc", strings.NewReader(shimMetricBody), expfmt.FmtText, int64(size))
+		assert.Equal(errShimMetricsTooLarge, err, size)
+	}
+
+	// the same metrics in the protobuf delimited format
+	buf := &bytes.Buffer{}
+	encoder := expfmt.NewEncoder(buf, expfmt.FmtProtoDelim)
+	decoder := expfmt.NewDecoder(strings.NewReader(shimMetricBody), expfmt.FmtText)
+	for {
+		mf := &dto.MetricFamily{}
+		if err := decoder.Decode(mf); err != nil {
+			break
+		}
+		assert.NoError(encoder.Encode(mf))
+	}
+
+	protoList, err := decodeShimMetrics("sandboxID-abc", buf, expfmt.FmtProtoDelim, maxShimMetricsSize)
+	assert.NoError(err)
+	assert.Equal(list, protoList)
+}
@@ -13,6 +13,7 @@ import (
 
 	"github.com/containerd/containerd/defaults"
 	srvconfig "github.com/containerd/containerd/services/server/config"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sirupsen/logrus"
 
 	// register grpc event types
@@ -33,10 +34,22 @@ type KataMonitor struct {
 	containerdConfigFile string
 	containerdStatePath  string
 	sandboxCache         *sandboxCache
+	problems             *problemDetector
+	usage                *usageHistory
+	scrapeStatus         *scrapeStatuses
+	shimTokenFile        string
 }
 
-// NewKataMonitor create and return a new KataMonitor instance
-func NewKataMonitor(containerdAddr, containerdConfigFile string) (*KataMonitor, error) {
+// SetShimTokenFile sets the file of the management token of the shims,
+// sent with the requests proxied to their privileged endpoints.
+func (km *KataMonitor) SetShimTokenFile(path string) {
+	km.shimTokenFile = path
+}
+
+// NewKataMonitor create and return a new KataMonitor instance,
+// kube resolves the pods of the sandboxes when not nil, and only the
+// sandboxes of the namespaces selected by namespaces are monitored.
+func NewKataMonitor(containerdAddr, containerdConfigFile string, podMetadata PodMetadataFilter, namespaces NamespaceFilter, kube *KubeClient) (*KataMonitor, error) {
 	if containerdAddr == "" {
 		return nil, fmt.Errorf("containerd serve address missing")
 	}
@@ -54,19 +67,29 @@ func NewKataMonitor(containerdAddr, containerdConfigFile string) (*KataMonitor,
 		containerdConfigFile: containerdConfigFile,
 		containerdStatePath:  containerdConf.State,
 		sandboxCache: &sandboxCache{
-			Mutex:     &sync.Mutex{},
-			sandboxes: make(map[string]string),
+			Mutex:         &sync.Mutex{},
+			sandboxes:     make(map[string]string),
+			metricsLabels: make(map[string]map[string]string),
+			podMetadata:   podMetadata.withoutCollisions(),
+			namespaces:    namespaces,
+			pods:          make(map[string]*PodInfo),
+			kube:          kube,
 		},
+		scrapeStatus: newScrapeStatuses(),
 	}
 
 	if err := km.initSandboxCache(); err != nil {
 		return nil, err
 	}
 
+	// dial the shims at the address they stored
+	storedShimAddress = km.getSandboxMonitorAddress
+
 	// register metrics
 	registerMetrics()
+	prometheus.MustRegister(km.scrapeStatus)
 
-	go km.sandboxCache.startEventsListener(km.containerdAddr)
+	go km.sandboxCache.startEventsListener(km.containerdAddr, km.getSandboxes)
 
 	return km, nil
 }
@@ -87,22 +110,28 @@ func (km *KataMonitor) GetAgentURL(w http.ResponseWriter, r *http.Request) {
 		commonServeError(w, http.StatusBadRequest, err)
 		return
 	}
+	if status, err := km.checkSandbox(sandboxID); err != nil {
+		commonServeError(w, status, err)
+		return
+	}
 
-	data, err := doGet(sandboxID, defaultTimeout, "agent-url")
+	url, err := newShimClient(sandboxID, defaultTimeout).AgentURL()
 	if err != nil {
 		commonServeError(w, http.StatusBadRequest, err)
 		return
 	}
 
-	fmt.Fprintln(w, string(data))
+	fmt.Fprintln(w, url)
 }
 
-// ListSandboxes list all sandboxes running in Kata
-func (km *KataMonitor) ListSandboxes(w http.ResponseWriter, r *http.Request) {
-	sandboxes := km.getSandboxList()
-	for _, s := range sandboxes {
-		w.Write([]byte(fmt.Sprintf("%s\n", s)))
-	}
+// SandboxInfo describes a sandbox in the JSON output of ListSandboxes.
+type SandboxInfo struct {
+	ID string `json:"id"`
+	// Namespace is the containerd namespace of the sandbox.
+	Namespace string   `json:"namespace"`
+	Pod       *PodInfo `json:"pod,omitempty"`
+	// State is the state of the sandbox, see SandboxRunning.
+	State string `json:"state,omitempty"`
 }
 
 func (km *KataMonitor) getSandboxList() []string {
@@ -10,6 +10,8 @@ import (
 	"io"
 	"net"
 	"net/http"
+
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/shimclient"
 )
 
 func serveError(w http.ResponseWriter, status int, txt string) {
@@ -26,12 +28,7 @@ func (km *KataMonitor) composeSocketAddress(r *http.Request) (string, error) {
 		return "", err
 	}
 
-	namespace, err := km.getSandboxNamespace(sandbox)
-	if err != nil {
-		return "", err
-	}
-
-	return km.getMonitorAddress(sandbox, namespace)
+	return km.getSandboxMonitorAddress(sandbox)
 }
 
 func (km *KataMonitor) proxyRequest(w http.ResponseWriter, r *http.Request) {
@@ -47,7 +44,7 @@ func (km *KataMonitor) proxyRequest(w http.ResponseWriter, r *http.Request) {
 	transport := &http.Transport{
 		DisableKeepAlives: true,
 		Dial: func(proto, addr string) (conn net.Conn, err error) {
-			return net.Dial("unix", "\x00"+socket)
+			return net.Dial("unix", shimclient.DialPath(socket))
 		},
 	}
 
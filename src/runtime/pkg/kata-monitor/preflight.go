@@ -0,0 +1,280 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package katamonitor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// PreflightReadyLabel is set on the node to "true" when the preflight
+	// checks pass, to "false" otherwise, so that the Kata pods can be
+	// scheduled on the verified nodes only with a node selector or
+	// affinity.
+	PreflightReadyLabel = "katacontainers.io/kata-runtime-ready"
+
+	// PreflightVersionLabel is set on the node to the runtime version.
+	PreflightVersionLabel = "katacontainers.io/kata-runtime-version"
+
+	// PreflightFeatureLabelPrefix prefixes the labels set on the node to
+	// "true" for the features available to the sandboxes, e.g.
+	// feature.katacontainers.io/virtio-fs.
+	PreflightFeatureLabelPrefix = "feature.katacontainers.io/"
+
+	// PreflightCondition is the condition of the node reporting the
+	// result of the preflight checks.
+	PreflightCondition = "KataContainersReady"
+
+	// the longest message of the condition, the output of the checks
+	// is truncated
+	preflightMessageMax = 1024
+
+	// the longest value of a label
+	labelValueMax = 63
+)
+
+var (
+	preflightReady = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: promNamespaceMonitor,
+		Name:      "preflight_ready",
+		Help:      "Result of the last preflight checks of the node, 1 if they passed.",
+	})
+
+	preflightLastRun = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: promNamespaceMonitor,
+		Name:      "preflight_last_run_timestamp_seconds",
+		Help:      "Time of the last preflight checks(seconds since epoch).",
+	})
+
+	// the characters not allowed in label values
+	labelValueInvalid = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+)
+
+// PreflightConfig is the configuration of the preflight checks.
+type PreflightConfig struct {
+	// Runtime is the kata-runtime binary running the checks.
+	Runtime string
+	// ConfigPath is the runtime configuration file checked, the
+	// default one is used if empty.
+	ConfigPath string
+	// Node is the name of the node labeled with the results.
+	Node     string
+	Interval time.Duration
+	Timeout  time.Duration
+}
+
+// preflightResult is the result of the preflight checks.
+type preflightResult struct {
+	Ready    bool
+	Message  string
+	Version  string
+	Features []string
+}
+
+// StartPreflight runs the checks of kata-runtime periodically, updating the
+// labels and the KataContainersReady condition of the node with the results,
+// so that the Kata pods are only scheduled on the verified nodes.
+func StartPreflight(kube *KubeClient, config PreflightConfig) error {
+	if kube == nil {
+		return fmt.Errorf("no Kubernetes client to label the node")
+	}
+	if config.Node == "" {
+		return fmt.Errorf("no node name provided")
+	}
+
+	prometheus.MustRegister(preflightReady)
+	prometheus.MustRegister(preflightLastRun)
+
+	go func() {
+		for {
+			ctx, cancel := context.WithTimeout(context.Background(), config.Timeout)
+			result := runPreflight(ctx, config)
+			cancel()
+
+			recordPreflight(result, time.Now())
+			if err := kube.updateNodePreflight(config.Node, result, time.Now()); err != nil {
+				monitorLog.WithError(err).Error("failed to update the node with the preflight checks")
+			}
+
+			time.Sleep(config.Interval)
+		}
+	}()
+
+	return nil
+}
+
+// recordPreflight records the result of the preflight checks in the metrics.
+func recordPreflight(result preflightResult, now time.Time) {
+	preflightLastRun.Set(float64(now.Unix()))
+
+	if !result.Ready {
+		monitorLog.WithField("message", result.Message).Error("preflight checks failed")
+		preflightReady.Set(0)
+		return
+	}
+
+	monitorLog.WithField("features", result.Features).Debug("preflight checks passed")
+	preflightReady.Set(1)
+}
+
+// runPreflight runs the check and env commands of kata-runtime, the node
+// is ready if both succeed.
+func runPreflight(ctx context.Context, config PreflightConfig) preflightResult {
+	var args []string
+	if config.ConfigPath != "" {
+		args = append(args, "--config", config.ConfigPath)
+	}
+
+	out, err := exec.CommandContext(ctx, config.Runtime, append(args, "check", "--no-network-checks")...).CombinedOutput()
+	if err != nil {
+		return preflightResult{Message: preflightMessage(fmt.Sprintf("%s check failed: %v: %s", config.Runtime, err, out))}
+	}
+
+	out, err = exec.CommandContext(ctx, config.Runtime, append(args, "env", "--json")...).Output()
+	if err != nil {
+		return preflightResult{Message: preflightMessage(fmt.Sprintf("%s env failed: %v", config.Runtime, err))}
+	}
+
+	var env struct {
+		Runtime struct {
+			Version struct {
+				Version struct {
+					Semver string
+				}
+			}
+		}
+		Features []string
+	}
+	if err := json.Unmarshal(out, &env); err != nil {
+		return preflightResult{Message: preflightMessage(fmt.Sprintf("invalid %s env output: %v", config.Runtime, err))}
+	}
+
+	return preflightResult{
+		Ready:    true,
+		Message:  "kata-runtime checks passed",
+		Version:  env.Runtime.Version.Version.Semver,
+		Features: env.Features,
+	}
+}
+
+// preflightMessage returns the message of the condition from the output
+// of the checks, keeping its end which holds the errors.
+func preflightMessage(message string) string {
+	message = strings.TrimSpace(message)
+	if len(message) > preflightMessageMax {
+		message = "..." + message[len(message)-preflightMessageMax+3:]
+	}
+	return message
+}
+
+// labelValue returns s as a valid label value.
+func labelValue(s string) string {
+	s = labelValueInvalid.ReplaceAllString(s, "_")
+	if len(s) > labelValueMax {
+		s = s[:labelValueMax]
+	}
+	return strings.Trim(s, "._-")
+}
+
+// NodeCondition is a condition of a node.
+type NodeCondition struct {
+	Type               string `json:"type"`
+	Status             string `json:"status"`
+	Reason             string `json:"reason,omitempty"`
+	Message            string `json:"message,omitempty"`
+	LastHeartbeatTime  string `json:"lastHeartbeatTime,omitempty"`
+	LastTransitionTime string `json:"lastTransitionTime,omitempty"`
+}
+
+// updateNodePreflight sets the labels and the condition of the node from
+// the result of the preflight checks. The feature labels of the features
+// not available anymore are removed.
+func (kc *KubeClient) updateNodePreflight(node string, result preflightResult, now time.Time) error {
+	var n struct {
+		Metadata struct {
+			Labels map[string]string `json:"labels,omitempty"`
+		} `json:"metadata"`
+		Status struct {
+			Conditions []NodeCondition `json:"conditions,omitempty"`
+		} `json:"status"`
+	}
+
+	path := "/api/v1/nodes/" + url.PathEscape(node)
+	if _, err := kc.request(http.MethodGet, path, "", nil, &n); err != nil {
+		return fmt.Errorf("failed to get node %s: %v", node, err)
+	}
+
+	// a null value removes the label
+	labels := map[string]interface{}{
+		PreflightReadyLabel:   fmt.Sprint(result.Ready),
+		PreflightVersionLabel: nil,
+	}
+	for key := range n.Metadata.Labels {
+		if strings.HasPrefix(key, PreflightFeatureLabelPrefix) {
+			labels[key] = nil
+		}
+	}
+	if result.Ready {
+		labels[PreflightVersionLabel] = labelValue(result.Version)
+		for _, feature := range result.Features {
+			labels[PreflightFeatureLabelPrefix+feature] = "true"
+		}
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{"labels": labels},
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, err := kc.request(http.MethodPatch, path, "application/merge-patch+json", patch, nil); err != nil {
+		return fmt.Errorf("failed to update the labels of node %s: %v", node, err)
+	}
+
+	timestamp := now.UTC().Format(time.RFC3339)
+	condition := NodeCondition{
+		Type:               PreflightCondition,
+		Status:             "False",
+		Reason:             "KataPreflightFailed",
+		Message:            result.Message,
+		LastHeartbeatTime:  timestamp,
+		LastTransitionTime: timestamp,
+	}
+	if result.Ready {
+		condition.Status = "True"
+		condition.Reason = "KataPreflightPassed"
+	}
+	for _, c := range n.Status.Conditions {
+		if c.Type == condition.Type && c.Status == condition.Status && c.LastTransitionTime != "" {
+			condition.LastTransitionTime = c.LastTransitionTime
+		}
+	}
+
+	// the conditions are merged by type
+	patch, err = json.Marshal(map[string]interface{}{
+		"status": map[string]interface{}{"conditions": []NodeCondition{condition}},
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, err := kc.request(http.MethodPatch, path+"/status", "application/strategic-merge-patch+json", patch, nil); err != nil {
+		return fmt.Errorf("failed to update the conditions of node %s: %v", node, err)
+	}
+
+	return nil
+}
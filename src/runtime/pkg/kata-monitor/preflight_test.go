@@ -0,0 +1,152 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package katamonitor
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// the fake kata-runtime prints the env of the runtime, its check fails
+// when the configuration file is "broken"
+const fakeRuntime = `#!/bin/sh
+if [ "$1" = "--config" ]; then
+	config="$2"
+	shift 2
+fi
+case "$1" in
+check)
+	if [ "$config" = "broken" ]; then
+		echo "ERROR: kernel module vhost_vsock not loaded" >&2
+		exit 1
+	fi
+	;;
+env)
+	echo '{"Runtime":{"Version":{"Version":{"Semver":"2.2.0-alpha0+foo"}}},"Features":["virtio-fs","vsock"]}'
+	;;
+esac
+`
+
+func TestRunPreflight(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "kata-monitor")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	runtime := filepath.Join(dir, "kata-runtime")
+	assert.NoError(ioutil.WriteFile(runtime, []byte(fakeRuntime), 0700))
+
+	result := runPreflight(context.Background(), PreflightConfig{Runtime: runtime})
+	assert.True(result.Ready)
+	assert.Equal("2.2.0-alpha0+foo", result.Version)
+	assert.Equal([]string{"virtio-fs", "vsock"}, result.Features)
+
+	result = runPreflight(context.Background(), PreflightConfig{Runtime: runtime, ConfigPath: "broken"})
+	assert.False(result.Ready)
+	assert.Contains(result.Message, "vhost_vsock not loaded")
+
+	result = runPreflight(context.Background(), PreflightConfig{Runtime: filepath.Join(dir, "missing")})
+	assert.False(result.Ready)
+}
+
+func TestPreflightMessage(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("failed", preflightMessage(" failed\n"))
+
+	message := preflightMessage(strings.Repeat("a", 2000) + "error")
+	assert.Len(message, preflightMessageMax)
+	assert.True(strings.HasPrefix(message, "..."))
+	assert.True(strings.HasSuffix(message, "error"))
+}
+
+func TestLabelValue(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("2.2.0-alpha0_foo", labelValue("2.2.0-alpha0+foo"))
+	assert.Equal("", labelValue(""))
+	assert.Len(labelValue(strings.Repeat("a", 100)), labelValueMax)
+}
+
+func TestUpdateNodePreflight(t *testing.T) {
+	assert := assert.New(t)
+
+	patches := make(map[string]map[string]interface{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			if r.URL.Path != "/api/v1/nodes/node-1" {
+				http.Error(w, "not found", http.StatusNotFound)
+				return
+			}
+			w.Write([]byte(`{
+  "metadata": {"labels": {"feature.katacontainers.io/guest-swap": "true", "kubernetes.io/os": "linux"}},
+  "status": {"conditions": [
+    {"type": "KataContainersReady", "status": "True", "lastTransitionTime": "2021-06-01T00:00:00Z"}
+  ]}
+}`))
+		case http.MethodPatch:
+			var patch map[string]interface{}
+			assert.NoError(json.NewDecoder(r.Body).Decode(&patch))
+			patches[r.URL.Path+" "+r.Header.Get("Content-Type")] = patch
+			w.Write([]byte("{}"))
+		}
+	}))
+	defer server.Close()
+
+	kube, err := NewKubeClient(server.URL, "", "")
+	assert.NoError(err)
+
+	now := time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC)
+	result := preflightResult{Ready: true, Message: "passed", Version: "2.2.0", Features: []string{"virtio-fs"}}
+	assert.NoError(kube.updateNodePreflight("node-1", result, now))
+
+	assert.Equal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": map[string]interface{}{
+				PreflightReadyLabel:                    "true",
+				PreflightVersionLabel:                  "2.2.0",
+				"feature.katacontainers.io/virtio-fs":  "true",
+				"feature.katacontainers.io/guest-swap": nil,
+			},
+		},
+	}, patches["/api/v1/nodes/node-1 application/merge-patch+json"])
+
+	condition := patches["/api/v1/nodes/node-1/status application/strategic-merge-patch+json"]["status"].(map[string]interface{})["conditions"].([]interface{})[0].(map[string]interface{})
+	assert.Equal("True", condition["status"])
+	assert.Equal("2021-07-01T00:00:00Z", condition["lastHeartbeatTime"])
+	// the status did not change
+	assert.Equal("2021-06-01T00:00:00Z", condition["lastTransitionTime"])
+
+	result = preflightResult{Message: "failed"}
+	assert.NoError(kube.updateNodePreflight("node-1", result, now))
+
+	labels := patches["/api/v1/nodes/node-1 application/merge-patch+json"]["metadata"].(map[string]interface{})["labels"]
+	assert.Equal(map[string]interface{}{
+		PreflightReadyLabel:                    "false",
+		PreflightVersionLabel:                  nil,
+		"feature.katacontainers.io/guest-swap": nil,
+	}, labels)
+
+	condition = patches["/api/v1/nodes/node-1/status application/strategic-merge-patch+json"]["status"].(map[string]interface{})["conditions"].([]interface{})[0].(map[string]interface{})
+	assert.Equal("False", condition["status"])
+	assert.Equal("KataPreflightFailed", condition["reason"])
+	assert.Equal("failed", condition["message"])
+	assert.Equal("2021-07-01T00:00:00Z", condition["lastTransitionTime"])
+
+	assert.Error(kube.updateNodePreflight("node-2", result, now))
+}
@@ -0,0 +1,289 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package katamonitor
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	shim "github.com/kata-containers/kata-containers/src/runtime/containerd-shim-v2"
+)
+
+// The problems reported to the Node Problem Detector.
+const (
+	// ProblemShimUnresponsive: the shim of a sandbox does not answer.
+	ProblemShimUnresponsive = "KataShimUnresponsive"
+
+	// ProblemAgentUnresponsive: the agent of a sandbox does not answer,
+	// its metrics are missing from the ones of the shim.
+	ProblemAgentUnresponsive = "KataAgentUnresponsive"
+
+	// ProblemHypervisorCrashLooping: the sandbox of a pod is recreated
+	// again and again, its VM crashes or fails to boot.
+	ProblemHypervisorCrashLooping = "KataHypervisorCrashLooping"
+)
+
+// Exit codes of the Node Problem Detector custom plugins
+const (
+	NPDExitOK      = 0
+	NPDExitProblem = 1
+	NPDExitUnknown = 2
+)
+
+const (
+	// consecutive failed checks of a sandbox before reporting it
+	unresponsiveChecks = 3
+
+	// sandboxes created for a pod within the restart window
+	// before reporting it
+	crashLoopRestarts = 3
+	crashLoopWindow   = 10 * time.Minute
+)
+
+// Problem is a problem observed on the node.
+type Problem struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+	// Sandboxes are the sandboxes with the problem.
+	Sandboxes []string `json:"sandboxes,omitempty"`
+}
+
+// problemDetector observes the sandboxes periodically to detect the
+// problems Kubernetes cannot see, as the pods look running.
+type problemDetector struct {
+	sync.Mutex
+
+	// consecutive failed checks of the sandboxes
+	shimFailures  map[string]int
+	agentFailures map[string]int
+
+	// the sandboxes seen, and the creation times of the sandboxes of each
+	// pod, by pod UID
+	seen         map[string]bool
+	podSandboxes map[string][]time.Time
+	pods         map[string]*PodInfo
+
+	problems []Problem
+}
+
+func newProblemDetector() *problemDetector {
+	return &problemDetector{
+		shimFailures:  make(map[string]int),
+		agentFailures: make(map[string]int),
+		seen:          make(map[string]bool),
+		podSandboxes:  make(map[string][]time.Time),
+		pods:          make(map[string]*PodInfo),
+	}
+}
+
+// hasAgentMetrics returns true if the metrics of a shim include the ones
+// of its agent, or report them as not available: the agent answered, but
+// does not implement them or its API allow-list denies them, which does
+// not make it unresponsive.
+func hasAgentMetrics(sandboxID string) (bool, error) {
+	mfs, err := getSandboxMetrics(sandboxID)
+	if err != nil {
+		return false, err
+	}
+
+	for _, mf := range mfs {
+		if strings.HasPrefix(mf.GetName(), "kata_agent_") || mf.GetName() == shim.AgentMetricsUnavailableName {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// check observes the running sandboxes and updates the problems.
+func (d *problemDetector) check(sc *sandboxCache, now time.Time) {
+	type result struct {
+		shimFailed bool
+		agent      bool
+	}
+
+	// the shims are requested without holding the lock
+	sandboxes := make(map[string]result)
+	for id := range sc.getAllSandboxes() {
+		agent, err := hasAgentMetrics(id)
+		sandboxes[id] = result{shimFailed: err != nil, agent: agent}
+	}
+
+	d.Lock()
+	defer d.Unlock()
+
+	for id, r := range sandboxes {
+		if !d.seen[id] {
+			d.seen[id] = true
+			if pod := sc.getPod(id); pod != nil && pod.UID != "" {
+				d.podSandboxes[pod.UID] = append(d.podSandboxes[pod.UID], now)
+				d.pods[pod.UID] = pod
+			}
+		}
+
+		if r.shimFailed {
+			d.shimFailures[id]++
+			continue
+		}
+		d.shimFailures[id] = 0
+
+		if r.agent {
+			d.agentFailures[id] = 0
+		} else {
+			d.agentFailures[id]++
+		}
+	}
+
+	// forget the deleted sandboxes, but not their creation times
+	for id := range d.seen {
+		if _, found := sandboxes[id]; !found {
+			delete(d.seen, id)
+			delete(d.shimFailures, id)
+			delete(d.agentFailures, id)
+		}
+	}
+
+	var problems []Problem
+
+	unresponsive := func(problemType, what string, failures map[string]int) {
+		var ids []string
+		for id, n := range failures {
+			if n >= unresponsiveChecks {
+				ids = append(ids, id)
+			}
+		}
+		if len(ids) == 0 {
+			return
+		}
+		sort.Strings(ids)
+		problems = append(problems, Problem{
+			Type:      problemType,
+			Message:   fmt.Sprintf("%s of sandboxes %s not responding", what, strings.Join(ids, ", ")),
+			Sandboxes: ids,
+		})
+	}
+	unresponsive(ProblemShimUnresponsive, "shims", d.shimFailures)
+	unresponsive(ProblemAgentUnresponsive, "agents", d.agentFailures)
+
+	var crashLooping []string
+	for uid, times := range d.podSandboxes {
+		var recent []time.Time
+		for _, t := range times {
+			if now.Sub(t) < crashLoopWindow {
+				recent = append(recent, t)
+			}
+		}
+
+		if len(recent) == 0 {
+			delete(d.podSandboxes, uid)
+			delete(d.pods, uid)
+			continue
+		}
+		d.podSandboxes[uid] = recent
+
+		if len(recent) >= crashLoopRestarts {
+			pod := d.pods[uid]
+			crashLooping = append(crashLooping, fmt.Sprintf("%s/%s (%d sandboxes)", pod.Namespace, pod.Name, len(recent)))
+		}
+	}
+	if len(crashLooping) > 0 {
+		sort.Strings(crashLooping)
+		problems = append(problems, Problem{
+			Type:    ProblemHypervisorCrashLooping,
+			Message: fmt.Sprintf("sandboxes of pods %s recreated in the last %v", strings.Join(crashLooping, ", "), crashLoopWindow),
+		})
+	}
+
+	d.problems = problems
+}
+
+// sandboxState returns the state of a sandbox from its failed checks.
+func (d *problemDetector) sandboxState(id string) string {
+	d.Lock()
+	defer d.Unlock()
+
+	switch {
+	case d.shimFailures[id] >= unresponsiveChecks:
+		return SandboxShimUnresponsive
+	case d.agentFailures[id] >= unresponsiveChecks:
+		return SandboxAgentUnresponsive
+	}
+	return SandboxRunning
+}
+
+func (d *problemDetector) getProblems() []Problem {
+	d.Lock()
+	defer d.Unlock()
+
+	if d.problems == nil {
+		return []Problem{}
+	}
+	return d.problems
+}
+
+// StartProblemDetector checks the sandboxes for problems every interval.
+func (km *KataMonitor) StartProblemDetector(interval time.Duration) {
+	km.problems = newProblemDetector()
+
+	go func() {
+		for {
+			km.problems.check(km.sandboxCache, time.Now())
+			time.Sleep(interval)
+		}
+	}()
+}
+
+// ListProblems returns the problems observed on the node
+func (km *KataMonitor) ListProblems(w http.ResponseWriter, r *http.Request) {
+	if km.problems == nil {
+		commonServeError(w, http.StatusNotFound, fmt.Errorf("problem detector is not enabled"))
+		return
+	}
+
+	w.Header().Set(contentTypeHeader, "application/json")
+	json.NewEncoder(w).Encode(km.problems.getProblems())
+}
+
+// CheckProblem implements a Node Problem Detector custom plugin: it gets the
+// problems from the kata-monitor serving url and returns the exit code and
+// the message of the plugin for the problem type.
+func CheckProblem(url, problemType string) (int, string) {
+	client := http.Client{Timeout: defaultTimeout}
+
+	resp, err := client.Get(url + "/problems")
+	if err != nil {
+		return NPDExitUnknown, fmt.Sprintf("failed to get the problems from kata-monitor: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return NPDExitUnknown, fmt.Sprintf("failed to get the problems from kata-monitor: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return NPDExitUnknown, fmt.Sprintf("failed to get the problems from kata-monitor: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var problems []Problem
+	if err := json.Unmarshal(body, &problems); err != nil {
+		return NPDExitUnknown, fmt.Sprintf("invalid problems from kata-monitor: %v", err)
+	}
+
+	for _, p := range problems {
+		if p.Type == problemType {
+			return NPDExitProblem, p.Message
+		}
+	}
+
+	return NPDExitOK, fmt.Sprintf("no %s problem", problemType)
+}
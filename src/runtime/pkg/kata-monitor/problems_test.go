@@ -0,0 +1,170 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package katamonitor
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	shim "github.com/kata-containers/kata-containers/src/runtime/containerd-shim-v2"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+)
+
+func mockShims(shims map[string]bool) func() {
+	saved := getSandboxMetrics
+
+	getSandboxMetrics = func(sandboxID string) ([]*dto.MetricFamily, error) {
+		agent, ok := shims[sandboxID]
+		if !ok {
+			return nil, fmt.Errorf("no shim for sandbox %s", sandboxID)
+		}
+
+		shimMetric, agentMetric := "kata_shim_threads", "kata_agent_threads"
+		mfs := []*dto.MetricFamily{{Name: &shimMetric}}
+		if agent {
+			mfs = append(mfs, &dto.MetricFamily{Name: &agentMetric})
+		}
+		return mfs, nil
+	}
+
+	return func() {
+		getSandboxMetrics = saved
+	}
+}
+
+func problemTypes(problems []Problem) []string {
+	types := []string{}
+	for _, p := range problems {
+		types = append(types, p.Type)
+	}
+	return types
+}
+
+func TestProblemDetectorUnresponsive(t *testing.T) {
+	assert := assert.New(t)
+
+	// the shim of "hung" does not answer, the agent of "no-agent" neither
+	restore := mockShims(map[string]bool{"ok": true, "no-agent": false})
+	defer restore()
+
+	sc := &sandboxCache{
+		Mutex:     &sync.Mutex{},
+		sandboxes: map[string]string{"ok": "k8s.io", "no-agent": "k8s.io", "hung": "k8s.io"},
+	}
+	d := newProblemDetector()
+	now := time.Now()
+
+	for i := 0; i < unresponsiveChecks-1; i++ {
+		d.check(sc, now)
+		assert.Empty(d.getProblems())
+	}
+
+	d.check(sc, now)
+	problems := d.getProblems()
+	assert.Equal([]string{ProblemShimUnresponsive, ProblemAgentUnresponsive}, problemTypes(problems))
+	assert.Equal([]string{"hung"}, problems[0].Sandboxes)
+	assert.Equal([]string{"no-agent"}, problems[1].Sandboxes)
+
+	// deleted sandboxes are forgotten
+	sc.deleteIfExists("hung")
+	sc.deleteIfExists("no-agent")
+	d.check(sc, now)
+	assert.Empty(d.getProblems())
+}
+
+func TestProblemDetectorAgentMetricsUnavailable(t *testing.T) {
+	assert := assert.New(t)
+
+	saved := getSandboxMetrics
+	defer func() {
+		getSandboxMetrics = saved
+	}()
+
+	// the agent API allow-list denies the metrics
+	getSandboxMetrics = func(sandboxID string) ([]*dto.MetricFamily, error) {
+		shimMetric, unavailable := "kata_shim_threads", shim.AgentMetricsUnavailableName
+		return []*dto.MetricFamily{{Name: &shimMetric}, {Name: &unavailable}}, nil
+	}
+
+	sc := &sandboxCache{
+		Mutex:     &sync.Mutex{},
+		sandboxes: map[string]string{"denied": "k8s.io"},
+	}
+	d := newProblemDetector()
+
+	for i := 0; i < unresponsiveChecks; i++ {
+		d.check(sc, time.Now())
+	}
+	assert.Empty(d.getProblems())
+}
+
+func TestProblemDetectorCrashLooping(t *testing.T) {
+	assert := assert.New(t)
+
+	restore := mockShims(map[string]bool{})
+	defer restore()
+
+	sc := &sandboxCache{
+		Mutex:     &sync.Mutex{},
+		sandboxes: make(map[string]string),
+	}
+	d := newProblemDetector()
+	now := time.Now()
+
+	// the sandbox of the pod is recreated after each crash
+	for i := 0; i < crashLoopRestarts; i++ {
+		assert.Empty(d.getProblems())
+
+		id := fmt.Sprintf("sandbox-%d", i)
+		sc.putIfNotExists(id, "k8s.io")
+		sc.setPod(id, &PodInfo{Name: "web", Namespace: "default", UID: "1234"})
+		d.check(sc, now)
+		sc.deleteIfExists(id)
+
+		now = now.Add(time.Minute)
+	}
+
+	assert.Equal([]string{ProblemHypervisorCrashLooping}, problemTypes(d.getProblems()))
+	assert.Contains(d.getProblems()[0].Message, "default/web")
+
+	// the pod is fine for a while
+	d.check(sc, now.Add(crashLoopWindow))
+	assert.Empty(d.getProblems())
+}
+
+func TestCheckProblem(t *testing.T) {
+	assert := assert.New(t)
+
+	km := &KataMonitor{}
+
+	server := httptest.NewServer(http.HandlerFunc(km.ListProblems))
+	defer server.Close()
+
+	// the detector is not enabled
+	code, _ := CheckProblem(server.URL, ProblemAgentUnresponsive)
+	assert.Equal(NPDExitUnknown, code)
+
+	km.problems = newProblemDetector()
+	code, _ = CheckProblem(server.URL, ProblemAgentUnresponsive)
+	assert.Equal(NPDExitOK, code)
+
+	km.problems.problems = []Problem{{Type: ProblemAgentUnresponsive, Message: "agents of sandboxes foo not responding"}}
+	code, message := CheckProblem(server.URL, ProblemAgentUnresponsive)
+	assert.Equal(NPDExitProblem, code)
+	assert.Equal("agents of sandboxes foo not responding", message)
+
+	code, _ = CheckProblem(server.URL, ProblemShimUnresponsive)
+	assert.Equal(NPDExitOK, code)
+
+	server.Close()
+	code, _ = CheckProblem(server.URL, ProblemAgentUnresponsive)
+	assert.Equal(NPDExitUnknown, code)
+}
@@ -0,0 +1,168 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package katamonitor
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/containerd/containerd/identifiers"
+)
+
+// the idle clients are forgotten by the rate limiter once their bucket is
+// full, checked at most once per sweep interval
+const rateLimitSweepInterval = time.Minute
+
+// validateSandboxID checks that a sandbox ID read from a request is a
+// containerd identifier, which is safe to use in the paths of the shim
+// sockets, i.e. without separators nor "..".
+func validateSandboxID(id string) error {
+	if err := identifiers.Validate(id); err != nil {
+		return fmt.Errorf("invalid sandbox ID: %v", err)
+	}
+	return nil
+}
+
+// validateNamespace checks that a containerd namespace is an identifier,
+// before using it in a path.
+func validateNamespace(namespace string) error {
+	if err := identifiers.Validate(namespace); err != nil {
+		return fmt.Errorf("invalid namespace: %v", err)
+	}
+	return nil
+}
+
+// getSandboxIDFromReq returns the sandbox ID of the sandbox query parameter
+// of a request.
+func getSandboxIDFromReq(r *http.Request) (string, error) {
+	sandbox := r.URL.Query().Get("sandbox")
+	if sandbox == "" {
+		return "", fmt.Errorf("sandbox not found in %+v", r.URL.Query())
+	}
+	if err := validateSandboxID(sandbox); err != nil {
+		return "", err
+	}
+	return sandbox, nil
+}
+
+// checkSandbox checks that the sandbox of a request is valid and monitored,
+// returning the HTTP status of the error otherwise. The shims of the
+// sandboxes are only dialed once checked.
+func (km *KataMonitor) checkSandbox(sandboxID string) (int, error) {
+	if err := validateSandboxID(sandboxID); err != nil {
+		return http.StatusBadRequest, err
+	}
+	if _, err := km.getSandboxNamespace(sandboxID); err != nil {
+		return http.StatusNotFound, fmt.Errorf("sandbox %s not found", sandboxID)
+	}
+	return http.StatusOK, nil
+}
+
+// tokenBucket is the request budget of a client.
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// RateLimiter limits the rate of the requests of each client, identified by
+// its remote address, so that a misbehaving client cannot overload the
+// monitor and the shims it dials.
+type RateLimiter struct {
+	sync.Mutex
+	// rate is the number of requests allowed per second, up to burst
+	// requests at once
+	rate      float64
+	burst     float64
+	clients   map[string]*tokenBucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewRateLimiter returns a rate limiter allowing rate requests per second
+// to each client, with bursts of up to burst requests.
+func NewRateLimiter(rate float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &RateLimiter{
+		rate:    rate,
+		burst:   float64(burst),
+		clients: make(map[string]*tokenBucket),
+		now:     time.Now,
+	}
+}
+
+// allow returns true if the client can send a request now, the delay
+// before its next request otherwise.
+func (l *RateLimiter) allow(client string) (bool, time.Duration) {
+	l.Lock()
+	defer l.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	b, ok := l.clients[client]
+	if !ok {
+		b = &tokenBucket{tokens: l.burst, last: now}
+		l.clients[client] = b
+	}
+
+	b.tokens += now.Sub(b.last).Seconds() * l.rate
+	if b.tokens > l.burst {
+		b.tokens = l.burst
+	}
+	b.last = now
+
+	if b.tokens < 1 {
+		return false, time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+	}
+	b.tokens--
+
+	return true, 0
+}
+
+// sweep forgets the clients whose bucket is full again.
+func (l *RateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < rateLimitSweepInterval {
+		return
+	}
+	l.lastSweep = now
+
+	refill := time.Duration(l.burst / l.rate * float64(time.Second))
+	for client, b := range l.clients {
+		if now.Sub(b.last) >= refill {
+			delete(l.clients, client)
+		}
+	}
+}
+
+// Handler returns the handler rejecting the requests above the rate of
+// their client with 429 Too Many Requests.
+func (l *RateLimiter) Handler(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ok, delay := l.allow(requestClient(r)); !ok {
+			w.Header().Set("Retry-After", strconv.Itoa(int(delay/time.Second)+1))
+			commonServeError(w, http.StatusTooManyRequests, fmt.Errorf("too many requests"))
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// requestClient returns the client of a request: its IP address, the
+// clients of the unix socket sharing the same budget.
+func requestClient(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
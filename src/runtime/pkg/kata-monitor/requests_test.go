@@ -0,0 +1,132 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package katamonitor
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateSandboxID(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.NoError(validateSandboxID("1a9ab65be63b8b03dfd0c75036d27f0ed09eab38abb45337fea83acd3cd7bacd"))
+	assert.NoError(validateSandboxID("sandbox_1.test-2"))
+
+	for _, id := range []string{"", "..", "../../../etc", "a/b", "a..b", ".a", "a b", "a%2Fb", strings.Repeat("a", 100)} {
+		assert.Error(validateSandboxID(id), id)
+	}
+
+	assert.NoError(validateNamespace("k8s.io"))
+	assert.Error(validateNamespace("../k8s.io"))
+}
+
+func TestGetSandboxIDFromReq(t *testing.T) {
+	assert := assert.New(t)
+
+	id, err := getSandboxIDFromReq(httptest.NewRequest(http.MethodGet, "/agent-url?sandbox=foo", nil))
+	assert.NoError(err)
+	assert.Equal("foo", id)
+
+	_, err = getSandboxIDFromReq(httptest.NewRequest(http.MethodGet, "/agent-url", nil))
+	assert.Error(err)
+	_, err = getSandboxIDFromReq(httptest.NewRequest(http.MethodGet, "/agent-url?sandbox=..%2Ffoo", nil))
+	assert.Error(err)
+}
+
+func TestCheckSandbox(t *testing.T) {
+	assert := assert.New(t)
+
+	km := &KataMonitor{
+		sandboxCache: &sandboxCache{
+			Mutex:     &sync.Mutex{},
+			sandboxes: map[string]string{"foo": "k8s.io"},
+		},
+	}
+
+	status, err := km.checkSandbox("foo")
+	assert.NoError(err)
+	assert.Equal(http.StatusOK, status)
+
+	status, err = km.checkSandbox("bar")
+	assert.Error(err)
+	assert.Equal(http.StatusNotFound, status)
+
+	status, err = km.checkSandbox("../foo")
+	assert.Error(err)
+	assert.Equal(http.StatusBadRequest, status)
+
+	// the namespace is checked before being used in a path too
+	_, err = km.getMonitorAddress("foo", "..")
+	assert.Error(err)
+
+	rr := httptest.NewRecorder()
+	km.ServeSandbox(rr, httptest.NewRequest(http.MethodGet, "/sandboxes/..%2F..%2Ffoo/stats", nil))
+	assert.Equal(http.StatusBadRequest, rr.Code)
+}
+
+func TestRateLimiter(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Unix(0, 0)
+	l := NewRateLimiter(2, 3)
+	l.now = func() time.Time { return now }
+
+	// the burst, then the rate
+	for i := 0; i < 3; i++ {
+		ok, _ := l.allow("10.0.0.1")
+		assert.True(ok)
+	}
+	ok, delay := l.allow("10.0.0.1")
+	assert.False(ok)
+	assert.Equal(500*time.Millisecond, delay)
+
+	// the other clients have their own budget
+	ok, _ = l.allow("10.0.0.2")
+	assert.True(ok)
+
+	now = now.Add(500 * time.Millisecond)
+	ok, _ = l.allow("10.0.0.1")
+	assert.True(ok)
+	ok, _ = l.allow("10.0.0.1")
+	assert.False(ok)
+
+	// the idle clients are forgotten
+	now = now.Add(rateLimitSweepInterval)
+	ok, _ = l.allow("10.0.0.3")
+	assert.True(ok)
+	assert.Len(l.clients, 1)
+}
+
+func TestRateLimiterHandler(t *testing.T) {
+	assert := assert.New(t)
+
+	l := NewRateLimiter(1, 1)
+	handler := l.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	request := func(remoteAddr string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/sandboxes", nil)
+		r.RemoteAddr = remoteAddr
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, r)
+		return rr
+	}
+
+	assert.Equal(http.StatusOK, request("10.0.0.1:1234").Code)
+
+	// the same client from another port
+	rr := request("10.0.0.1:1235")
+	assert.Equal(http.StatusTooManyRequests, rr.Code)
+	assert.Equal("1", rr.Header().Get("Retry-After"))
+
+	assert.Equal(http.StatusOK, request("@").Code)
+}
@@ -10,8 +10,10 @@ import (
 	"fmt"
 	"regexp"
 	"sync"
+	"time"
 
 	"github.com/containerd/containerd"
+	"github.com/containerd/containerd/containers"
 	"github.com/sirupsen/logrus"
 
 	"encoding/json"
@@ -25,9 +27,41 @@ import (
 	_ "github.com/containerd/containerd/api/events"
 )
 
+const (
+	// the delays before resubscribing to the containerd events after
+	// a failure, doubled until the sandboxes are resynced
+	eventsMinBackoff = time.Second
+	eventsMaxBackoff = time.Minute
+
+	// maxPodRequests bounds the requests resolving the pods of the
+	// sandboxes in flight, e.g. when the cache is resynced
+	maxPodRequests = 4
+)
+
+// podRequests limits the concurrent requests resolving the pods.
+var podRequests = make(chan struct{}, maxPodRequests)
+
 type sandboxCache struct {
 	*sync.Mutex
 	sandboxes map[string]string
+	// metricsLabels holds the extra metrics labels of a sandbox,
+	// resolved from its pod metadata.
+	metricsLabels map[string]map[string]string
+	podMetadata   PodMetadataFilter
+
+	// namespaces selects the containerd namespaces of the sandboxes
+	// of the cache.
+	namespaces NamespaceFilter
+
+	// pods holds the pods of the sandboxes, resolved from
+	// the Kubernetes API when kube is set, from the CRI otherwise.
+	pods map[string]*PodInfo
+	kube *KubeClient
+
+	// taskStates holds the states of the tasks of the sandboxes, from
+	// the task events, see SandboxCreated. The sandboxes without a state
+	// are running.
+	taskStates map[string]string
 }
 
 func (sc *sandboxCache) getAllSandboxes() map[string]string {
@@ -53,6 +87,9 @@ func (sc *sandboxCache) deleteIfExists(id string) (string, bool) {
 
 	if val, found := sc.sandboxes[id]; found {
 		delete(sc.sandboxes, id)
+		delete(sc.metricsLabels, id)
+		delete(sc.pods, id)
+		delete(sc.taskStates, id)
 		return val, true
 	}
 
@@ -73,14 +110,198 @@ func (sc *sandboxCache) putIfNotExists(id, value string) bool {
 	return false
 }
 
+func (sc *sandboxCache) getMetricsLabels(id string) map[string]string {
+	sc.Lock()
+	defer sc.Unlock()
+	return sc.metricsLabels[id]
+}
+
+func (sc *sandboxCache) setMetricsLabels(id string, labels map[string]string) {
+	sc.Lock()
+	defer sc.Unlock()
+
+	if len(labels) == 0 {
+		delete(sc.metricsLabels, id)
+		return
+	}
+
+	if sc.metricsLabels == nil {
+		sc.metricsLabels = make(map[string]map[string]string)
+	}
+	sc.metricsLabels[id] = labels
+}
+
+func (sc *sandboxCache) getPod(id string) *PodInfo {
+	sc.Lock()
+	defer sc.Unlock()
+	return sc.pods[id]
+}
+
+func (sc *sandboxCache) setPod(id string, pod *PodInfo) {
+	sc.Lock()
+	defer sc.Unlock()
+
+	if pod == nil {
+		delete(sc.pods, id)
+		return
+	}
+
+	if sc.pods == nil {
+		sc.pods = make(map[string]*PodInfo)
+	}
+	sc.pods[id] = pod
+}
+
+// getTaskState returns the state of the task of a sandbox.
+func (sc *sandboxCache) getTaskState(id string) string {
+	sc.Lock()
+	defer sc.Unlock()
+
+	if state, found := sc.taskStates[id]; found {
+		return state
+	}
+	return SandboxRunning
+}
+
+func (sc *sandboxCache) setTaskState(id, state string) {
+	sc.Lock()
+	defer sc.Unlock()
+
+	if sc.taskStates == nil {
+		sc.taskStates = make(map[string]string)
+	}
+	sc.taskStates[id] = state
+}
+
+// countTaskStates returns the number of sandboxes by the state of their task.
+func (sc *sandboxCache) countTaskStates() map[string]int {
+	sc.Lock()
+	defer sc.Unlock()
+
+	counts := map[string]int{
+		SandboxCreated: 0,
+		SandboxRunning: 0,
+		SandboxStopped: 0,
+	}
+	for id := range sc.sandboxes {
+		state, found := sc.taskStates[id]
+		if !found {
+			state = SandboxRunning
+		}
+		counts[state]++
+	}
+	return counts
+}
+
+// setPodMetadata sets the pod of a sandbox container and the metrics
+// labels of the sandbox from the CRI labels. The pod is then resolved from
+// the Kubernetes API, if enabled, without blocking the caller, e.g. the
+// events listener. The pod is only resolved when the sandbox is added to
+// the cache.
+func (sc *sandboxCache) setPodMetadata(c *containers.Container) {
+	pod := criPod(c)
+
+	sc.setPod(c.ID, pod)
+	sc.setMetricsLabels(c.ID, sc.podMetadata.metricsLabels(c, pod))
+
+	if sc.kube != nil && pod != nil {
+		go sc.resolvePod(c, pod)
+	}
+}
+
+// resolvePod gets the pod of a sandbox container from the Kubernetes API,
+// and replaces its CRI pod criPod with it, unless the sandbox was deleted
+// or its pod set again meanwhile.
+func (sc *sandboxCache) resolvePod(c *containers.Container, criPod *PodInfo) {
+	podRequests <- struct{}{}
+	pod, err := sc.kube.getPod(criPod.Namespace, criPod.Name)
+	<-podRequests
+
+	if err != nil {
+		monitorLog.WithError(err).WithField("sandbox", c.ID).Warn("failed to get the pod of the sandbox")
+		return
+	}
+
+	labels := sc.podMetadata.metricsLabels(c, pod)
+
+	sc.Lock()
+	defer sc.Unlock()
+
+	if sc.pods[c.ID] != criPod {
+		return
+	}
+
+	sc.pods[c.ID] = pod
+	if len(labels) == 0 {
+		delete(sc.metricsLabels, c.ID)
+	} else {
+		if sc.metricsLabels == nil {
+			sc.metricsLabels = make(map[string]map[string]string)
+		}
+		sc.metricsLabels[c.ID] = labels
+	}
+}
+
 func (sc *sandboxCache) init(sandboxes map[string]string) {
 	sc.Lock()
 	defer sc.Unlock()
 	sc.sandboxes = sandboxes
 }
 
-// startEventsListener will boot a thread to listen container events to manage sandbox cache
-func (sc *sandboxCache) startEventsListener(addr string) error {
+// resync replaces the sandboxes of the cache with the full list, e.g. after
+// the events of the sandboxes created or deleted were missed, dropping the
+// metadata of the sandboxes not running anymore.
+func (sc *sandboxCache) resync(sandboxes map[string]string) {
+	sc.Lock()
+	defer sc.Unlock()
+
+	for id := range sc.sandboxes {
+		if _, found := sandboxes[id]; !found {
+			delete(sc.metricsLabels, id)
+			delete(sc.pods, id)
+			delete(sc.taskStates, id)
+		}
+	}
+	sc.sandboxes = sandboxes
+}
+
+// startEventsListener listens to the container events to manage the sandbox
+// cache. The events are resubscribed with a backoff when the stream fails,
+// e.g. when containerd restarts, and the cache is resynced with the list of
+// sandboxes returned by getSandboxes once subscribed again.
+func (sc *sandboxCache) startEventsListener(addr string, getSandboxes func() (map[string]string, error)) {
+	backoff := eventsMinBackoff
+	var onSubscribed func() error
+
+	for {
+		err := sc.listenEvents(addr, onSubscribed)
+		eventsDisconnects.Inc()
+		monitorLog.WithError(err).WithField("backoff", backoff).Warn("containerd events stream failed, resubscribing")
+
+		time.Sleep(backoff)
+		if backoff *= 2; backoff > eventsMaxBackoff {
+			backoff = eventsMaxBackoff
+		}
+
+		onSubscribed = func() error {
+			sandboxes, err := getSandboxes()
+			if err != nil {
+				return fmt.Errorf("failed to resync the sandboxes: %v", err)
+			}
+
+			sc.resync(sandboxes)
+			eventsResyncs.Inc()
+			backoff = eventsMinBackoff
+			monitorLog.WithField("sandboxes", len(sandboxes)).Info("resynced the sandbox cache")
+			return nil
+		}
+	}
+}
+
+// listenEvents subscribes to the container events and updates the sandbox
+// cache until the events stream fails. onSubscribed, if not nil, is called
+// once subscribed, before handling the events.
+func (sc *sandboxCache) listenEvents(addr string, onSubscribed func() error) error {
 	client, err := containerd.New(addr)
 	if err != nil {
 		return err
@@ -92,10 +313,13 @@ func (sc *sandboxCache) startEventsListener(addr string) error {
 	eventsClient := client.EventService()
 	containerClient := client.ContainerService()
 
-	// only need create/delete events.
+	// only need create/delete events, and the start/exit of the tasks
+	// for the states of the sandboxes.
 	eventFilters := []string{
 		`topic=="/containers/create"`,
 		`topic=="/containers/delete"`,
+		`topic=="/tasks/start"`,
+		`topic=="/tasks/exit"`,
 	}
 
 	runtimeNameRegexp, err := regexp.Compile(types.KataRuntimeNameRegexp)
@@ -103,16 +327,35 @@ func (sc *sandboxCache) startEventsListener(addr string) error {
 		return err
 	}
 
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
 	eventsCh, errCh := eventsClient.Subscribe(ctx, eventFilters...)
+
+	// the events of the sandboxes created or deleted while resyncing are
+	// queued, the subscription comes first so that none is missed
+	if onSubscribed != nil {
+		if err := onSubscribed(); err != nil {
+			return err
+		}
+	}
+
 	for {
 		var e *events.Envelope
 		select {
 		case e = <-eventsCh:
 		case err = <-errCh:
-			monitorLog.WithError(err).Warn("get error from error chan")
+			if err == nil {
+				err = fmt.Errorf("events stream closed")
+			}
 			return err
 		}
 
+		// the events of the namespaces not monitored are skipped
+		if e != nil && !sc.namespaces.allowed(e.Namespace) {
+			continue
+		}
+
 		if e != nil {
 			var eventBody []byte
 			if e.Event != nil {
@@ -159,6 +402,8 @@ func (sc *sandboxCache) startEventsListener(addr string) error {
 				if isSandboxContainer(&c) {
 					// we can simply put the contaienrid in sandboxes list if the container is a sandbox container
 					sc.putIfNotExists(cc.ID, e.Namespace)
+					sc.setTaskState(cc.ID, SandboxCreated)
+					sc.setPodMetadata(&c)
 					monitorLog.WithField("container", cc.ID).Info("add sandbox to cache")
 				}
 			} else if e.Topic == "/containers/delete" {
@@ -179,6 +424,53 @@ func (sc *sandboxCache) startEventsListener(addr string) error {
 				// the last container in a sandbox is deleted, means the VM will stop.
 				_, deleted := sc.deleteIfExists(cd.ID)
 				monitorLog.WithFields(logrus.Fields{"container": cd.ID, "result": deleted}).Info("delete sandbox from cache")
+			} else if e.Topic == "/tasks/start" {
+				// Namespace: k8s.io
+				// Topic: /tasks/start
+				// Event: {
+				//          "container_id":"6a2e22e6fffaf1dec63ddabf587ed56069b1809ba67a0d7872fc470528364e66",
+				//          "pid":1234
+				//        }
+				ts := eventstypes.TaskStart{}
+				if err := json.Unmarshal(eventBody, &ts); err != nil {
+					monitorLog.WithError(err).WithField("body", string(eventBody)).Warn("unmarshal TaskStart failed")
+					continue
+				}
+
+				// the tasks of the other containers of the sandboxes are skipped
+				if _, err := sc.getSandboxNamespace(ts.ContainerID); err != nil {
+					continue
+				}
+
+				sc.setTaskState(ts.ContainerID, SandboxRunning)
+				monitorLog.WithField("sandbox", ts.ContainerID).Debug("sandbox task started")
+			} else if e.Topic == "/tasks/exit" {
+				// Namespace: k8s.io
+				// Topic: /tasks/exit
+				// Event: {
+				//          "container_id":"6a2e22e6fffaf1dec63ddabf587ed56069b1809ba67a0d7872fc470528364e66",
+				//          "id":"6a2e22e6fffaf1dec63ddabf587ed56069b1809ba67a0d7872fc470528364e66",
+				//          "pid":1234,
+				//          "exit_status":137,
+				//          "exited_at":"2021-07-01T00:00:00Z"
+				//        }
+				te := eventstypes.TaskExit{}
+				if err := json.Unmarshal(eventBody, &te); err != nil {
+					monitorLog.WithError(err).WithField("body", string(eventBody)).Warn("unmarshal TaskExit failed")
+					continue
+				}
+
+				// the exits of the exec processes, whose ID is not
+				// the container one, are skipped
+				if te.ID != te.ContainerID {
+					continue
+				}
+				if _, err := sc.getSandboxNamespace(te.ContainerID); err != nil {
+					continue
+				}
+
+				sc.setTaskState(te.ContainerID, SandboxStopped)
+				monitorLog.WithFields(logrus.Fields{"sandbox": te.ContainerID, "exit_status": te.ExitStatus}).Info("sandbox task exited")
 			} else {
 				monitorLog.WithFields(logrus.Fields{"Namespace": e.Namespace, "Topic": e.Topic, "Event": string(eventBody)}).Error("other events")
 			}
@@ -37,13 +37,59 @@ func TestSandboxCache(t *testing.T) {
 	b = sc.putIfNotExists(id, "new-value")
 	assert.Equal(false, b)
 
+	sc.setMetricsLabels(id, map[string]string{"label_app": "web"})
+	assert.Equal(map[string]string{"label_app": "web"}, sc.getMetricsLabels(id))
+
 	v, b := sc.deleteIfExists(id)
 	assert.Equal(value, v)
 	assert.Equal(true, b)
 	assert.Equal(1, len(scMap))
+	assert.Nil(sc.getMetricsLabels(id))
 
 	v, b = sc.deleteIfExists(id)
 	assert.Equal("", v)
 	assert.Equal(false, b)
 	assert.Equal(1, len(scMap))
 }
+
+func TestSandboxCacheResync(t *testing.T) {
+	assert := assert.New(t)
+	sc := &sandboxCache{
+		Mutex:         &sync.Mutex{},
+		sandboxes:     map[string]string{"deleted": "k8s.io", "running": "k8s.io"},
+		metricsLabels: make(map[string]map[string]string),
+		pods:          make(map[string]*PodInfo),
+	}
+
+	sc.setMetricsLabels("deleted", map[string]string{"label_app": "web"})
+	sc.setPod("deleted", &PodInfo{Name: "web"})
+	sc.setMetricsLabels("running", map[string]string{"label_app": "db"})
+
+	// a sandbox was deleted and another one created while disconnected
+	sc.resync(map[string]string{"running": "k8s.io", "created": "default"})
+
+	assert.Equal(map[string]string{"running": "k8s.io", "created": "default"}, sc.getAllSandboxes())
+	assert.Nil(sc.getMetricsLabels("deleted"))
+	assert.Nil(sc.getPod("deleted"))
+	assert.Equal(map[string]string{"label_app": "db"}, sc.getMetricsLabels("running"))
+}
+
+func TestSandboxCacheTaskStates(t *testing.T) {
+	assert := assert.New(t)
+	sc := &sandboxCache{
+		Mutex:     &sync.Mutex{},
+		sandboxes: map[string]string{"created": "k8s.io", "running": "k8s.io", "stopped": "k8s.io"},
+	}
+
+	// the sandboxes without a state are running
+	assert.Equal(SandboxRunning, sc.getTaskState("running"))
+
+	sc.setTaskState("created", SandboxCreated)
+	sc.setTaskState("stopped", SandboxStopped)
+	assert.Equal(SandboxCreated, sc.getTaskState("created"))
+	assert.Equal(map[string]int{SandboxCreated: 1, SandboxRunning: 1, SandboxStopped: 1}, sc.countTaskStates())
+
+	sc.deleteIfExists("stopped")
+	assert.Equal(SandboxRunning, sc.getTaskState("stopped"))
+	assert.Equal(map[string]int{SandboxCreated: 1, SandboxRunning: 1, SandboxStopped: 0}, sc.countTaskStates())
+}
@@ -0,0 +1,221 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package katamonitor
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SandboxListVersion is the version of the JSON response of ListSandboxes.
+const SandboxListVersion = "v1"
+
+// The states of the sandboxes in the output of ListSandboxes. A sandbox is
+// created until its task is started, and stopped once its task exited. The
+// unresponsive ones are reported by the problem detector, when enabled.
+const (
+	SandboxCreated           = "created"
+	SandboxRunning           = "running"
+	SandboxStopped           = "stopped"
+	SandboxShimUnresponsive  = "shim_unresponsive"
+	SandboxAgentUnresponsive = "agent_unresponsive"
+)
+
+// SandboxList is the JSON response of ListSandboxes.
+type SandboxList struct {
+	APIVersion string        `json:"apiVersion"`
+	Sandboxes  []SandboxInfo `json:"sandboxes"`
+	// Continue is the token of the next page, empty on the last one.
+	Continue string `json:"continue,omitempty"`
+}
+
+// sandboxListOptions are the filters and the page of a sandbox list.
+type sandboxListOptions struct {
+	namespace string
+	state     string
+	limit     int
+	// the sandboxes are listed after this one, by ID
+	after string
+}
+
+func parseSandboxListOptions(r *http.Request) (sandboxListOptions, error) {
+	query := r.URL.Query()
+	opts := sandboxListOptions{
+		namespace: query.Get("namespace"),
+		state:     query.Get("state"),
+	}
+
+	switch opts.state {
+	case "", SandboxCreated, SandboxRunning, SandboxStopped, SandboxShimUnresponsive, SandboxAgentUnresponsive:
+	default:
+		return opts, fmt.Errorf("invalid state %q", opts.state)
+	}
+
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit <= 0 {
+			return opts, fmt.Errorf("invalid limit %q", value)
+		}
+		opts.limit = limit
+	}
+
+	if value := query.Get("continue"); value != "" {
+		after, err := base64.RawURLEncoding.DecodeString(value)
+		if err != nil || len(after) == 0 {
+			return opts, fmt.Errorf("invalid continue token %q", value)
+		}
+		opts.after = string(after)
+	}
+
+	return opts, nil
+}
+
+// acceptQuality returns the quality of a media type in an Accept header,
+// from its most specific media range.
+func acceptQuality(accept, mediaType string) float64 {
+	if accept == "" {
+		return 1
+	}
+
+	typ := strings.SplitN(mediaType, "/", 2)[0]
+	quality, specificity := 0.0, -1
+	for _, r := range strings.Split(accept, ",") {
+		params := strings.Split(r, ";")
+		mediaRange := strings.ToLower(strings.TrimSpace(params[0]))
+
+		s := -1
+		switch mediaRange {
+		case mediaType:
+			s = 2
+		case typ + "/*":
+			s = 1
+		case "*/*":
+			s = 0
+		}
+		if s <= specificity {
+			continue
+		}
+
+		q := 1.0
+		for _, p := range params[1:] {
+			p = strings.TrimSpace(p)
+			if strings.HasPrefix(p, "q=") {
+				if v, err := strconv.ParseFloat(strings.TrimPrefix(p, "q="), 64); err == nil {
+					q = v
+				}
+			}
+		}
+		quality, specificity = q, s
+	}
+
+	return quality
+}
+
+// plainTextPreferred returns true if the request prefers the newline
+// delimited list of the sandboxes to the JSON one.
+func plainTextPreferred(r *http.Request) bool {
+	accept := r.Header.Get("Accept")
+	return acceptQuality(accept, "text/plain") > acceptQuality(accept, "application/json")
+}
+
+// sandboxState returns the state of a sandbox, the running ones being
+// checked by the problem detector.
+func (km *KataMonitor) sandboxState(id string) string {
+	if state := km.sandboxCache.getTaskState(id); state != SandboxRunning {
+		return state
+	}
+	if km.problems == nil {
+		return SandboxRunning
+	}
+	return km.problems.sandboxState(id)
+}
+
+// listSandboxes returns the sandboxes matching the options, sorted by ID,
+// and the ID of the last one if there are more.
+func (km *KataMonitor) listSandboxes(opts sandboxListOptions) ([]SandboxInfo, string) {
+	ids := km.getSandboxList()
+	sort.Strings(ids)
+
+	result := []SandboxInfo{}
+	for _, id := range ids {
+		if id <= opts.after {
+			continue
+		}
+
+		namespace, err := km.getSandboxNamespace(id)
+		if err != nil {
+			// deleted in the meantime
+			continue
+		}
+		if opts.namespace != "" && namespace != opts.namespace {
+			continue
+		}
+
+		state := km.sandboxState(id)
+		if opts.state != "" && state != opts.state {
+			continue
+		}
+
+		if opts.limit > 0 && len(result) == opts.limit {
+			return result, result[len(result)-1].ID
+		}
+
+		result = append(result, SandboxInfo{
+			ID:        id,
+			Namespace: namespace,
+			Pod:       km.sandboxCache.getPod(id),
+			State:     state,
+		})
+	}
+
+	return result, ""
+}
+
+// ListSandboxes lists the sandboxes running in Kata as a SandboxList,
+// filtered by the namespace and state queries, and paginated by the limit
+// and continue ones. The newline delimited list of the sandbox IDs is
+// returned when text/plain is preferred by the Accept header, and the JSON
+// array of the sandboxes when the format=json query is set, as before the
+// SandboxList.
+func (km *KataMonitor) ListSandboxes(w http.ResponseWriter, r *http.Request) {
+	opts, err := parseSandboxListOptions(r)
+	if err != nil {
+		commonServeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	sandboxes, last := km.listSandboxes(opts)
+
+	if r.URL.Query().Get("format") == "json" {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(sandboxes)
+		return
+	}
+
+	if plainTextPreferred(r) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		for _, s := range sandboxes {
+			fmt.Fprintln(w, s.ID)
+		}
+		return
+	}
+
+	list := SandboxList{
+		APIVersion: SandboxListVersion,
+		Sandboxes:  sandboxes,
+	}
+	if last != "" {
+		list.Continue = base64.RawURLEncoding.EncodeToString([]byte(last))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
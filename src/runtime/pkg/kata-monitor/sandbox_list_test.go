@@ -0,0 +1,143 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package katamonitor
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlainTextPreferred(t *testing.T) {
+	assert := assert.New(t)
+
+	for accept, plain := range map[string]bool{
+		"":                                   false,
+		"*/*":                                false,
+		"application/json":                   false,
+		"text/plain":                         true,
+		"text/*":                             true,
+		"text/plain, application/json":       false,
+		"text/plain, application/json;q=0.5": true,
+		"text/plain;q=0.1, */*":              false,
+		"text/*;q=0.9, text/plain;q=0, */*":  false,
+	} {
+		r := httptest.NewRequest(http.MethodGet, "/sandboxes", nil)
+		if accept != "" {
+			r.Header.Set("Accept", accept)
+		}
+		assert.Equal(plain, plainTextPreferred(r), accept)
+	}
+}
+
+func TestListSandboxes(t *testing.T) {
+	assert := assert.New(t)
+
+	km := &KataMonitor{
+		sandboxCache: &sandboxCache{
+			Mutex: &sync.Mutex{},
+			sandboxes: map[string]string{
+				"a": "k8s.io",
+				"b": "k8s.io",
+				"c": "default",
+				"d": "k8s.io",
+			},
+		},
+		problems: newProblemDetector(),
+	}
+	km.problems.shimFailures["b"] = unresponsiveChecks
+
+	list := func(query string) (int, SandboxList) {
+		rr := httptest.NewRecorder()
+		km.ListSandboxes(rr, httptest.NewRequest(http.MethodGet, "/sandboxes"+query, nil))
+
+		var l SandboxList
+		if rr.Code == http.StatusOK {
+			assert.NoError(json.Unmarshal(rr.Body.Bytes(), &l))
+			assert.Equal(SandboxListVersion, l.APIVersion)
+		}
+		return rr.Code, l
+	}
+
+	ids := func(l SandboxList) []string {
+		var result []string
+		for _, s := range l.Sandboxes {
+			result = append(result, s.ID)
+		}
+		return result
+	}
+
+	_, l := list("")
+	assert.Equal([]string{"a", "b", "c", "d"}, ids(l))
+	assert.Equal(SandboxShimUnresponsive, l.Sandboxes[1].State)
+	assert.Empty(l.Continue)
+
+	_, l = list("?namespace=k8s.io&state=running")
+	assert.Equal([]string{"a", "d"}, ids(l))
+
+	// the pages of 2 sandboxes
+	_, l = list("?limit=2")
+	assert.Equal([]string{"a", "b"}, ids(l))
+	assert.NotEmpty(l.Continue)
+
+	_, l = list("?limit=2&continue=" + l.Continue)
+	assert.Equal([]string{"c", "d"}, ids(l))
+	assert.Empty(l.Continue)
+
+	for _, query := range []string{"?limit=0", "?limit=x", "?state=paused", "?continue=%25"} {
+		code, _ := list(query)
+		assert.Equal(http.StatusBadRequest, code, query)
+	}
+
+	// the formats before the SandboxList
+	rr := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/sandboxes?namespace=default", nil)
+	r.Header.Set("Accept", "text/plain")
+	km.ListSandboxes(rr, r)
+	assert.Equal("c\n", rr.Body.String())
+
+	rr = httptest.NewRecorder()
+	km.ListSandboxes(rr, httptest.NewRequest(http.MethodGet, "/sandboxes?format=json&namespace=default", nil))
+	var sandboxes []SandboxInfo
+	assert.NoError(json.Unmarshal(rr.Body.Bytes(), &sandboxes))
+	assert.Equal([]SandboxInfo{{ID: "c", Namespace: "default", State: SandboxRunning}}, sandboxes)
+}
+
+func TestListSandboxesTaskStates(t *testing.T) {
+	assert := assert.New(t)
+
+	km := &KataMonitor{
+		sandboxCache: &sandboxCache{
+			Mutex: &sync.Mutex{},
+			sandboxes: map[string]string{
+				"a": "k8s.io",
+				"b": "k8s.io",
+				"c": "k8s.io",
+			},
+			taskStates: map[string]string{
+				"a": SandboxCreated,
+				"c": SandboxStopped,
+			},
+		},
+		problems: newProblemDetector(),
+	}
+	// the problems of the sandboxes not running are not reported
+	km.problems.shimFailures["c"] = unresponsiveChecks
+
+	sandboxes, _ := km.listSandboxes(sandboxListOptions{})
+	assert.Len(sandboxes, 3)
+	assert.Equal(SandboxCreated, sandboxes[0].State)
+	assert.Equal(SandboxRunning, sandboxes[1].State)
+	assert.Equal(SandboxStopped, sandboxes[2].State)
+
+	sandboxes, _ = km.listSandboxes(sandboxListOptions{state: SandboxCreated})
+	assert.Len(sandboxes, 1)
+	assert.Equal("a", sandboxes[0].ID)
+}
@@ -0,0 +1,37 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package katamonitor
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/shimclient"
+)
+
+// SandboxStats serves the stats of a sandbox at /sandboxes/<id>/stats: the
+// stats of its VM and of all its containers, with their sum and the VM
+// overhead, as returned by its shim.
+func (km *KataMonitor) SandboxStats(w http.ResponseWriter, r *http.Request, sandboxID string) {
+	if status, err := km.checkSandbox(sandboxID); err != nil {
+		commonServeError(w, status, err)
+		return
+	}
+
+	stats, err := newShimClient(sandboxID, defaultTimeout).Stats()
+	if err != nil {
+		if shimclient.IsNotFound(err) {
+			commonServeError(w, http.StatusNotFound, fmt.Errorf("the shim of sandbox %s does not serve the stats", sandboxID))
+			return
+		}
+		commonServeError(w, http.StatusBadGateway, err)
+		return
+	}
+
+	w.Header().Set(contentTypeHeader, "application/json")
+	json.NewEncoder(w).Encode(stats)
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package katamonitor
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/shimclient"
+	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSandboxStats(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "kata-monitor")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "shim-monitor")
+	listener, err := net.Listen("unix", path)
+	assert.NoError(err)
+
+	shim := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/stats" {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(vc.PodStats{Overhead: vc.PodOverhead{Memory: 1024}})
+	})}
+	go shim.Serve(listener)
+	defer shim.Close()
+
+	saved := storedShimAddress
+	defer func() {
+		storedShimAddress = saved
+	}()
+	storedShimAddress = func(sandboxID string) (string, error) {
+		return shimclient.UnixSocketScheme + path, nil
+	}
+
+	km := &KataMonitor{
+		sandboxCache: &sandboxCache{
+			Mutex:     &sync.Mutex{},
+			sandboxes: map[string]string{"foo": "k8s.io"},
+		},
+	}
+
+	rr := httptest.NewRecorder()
+	km.ServeSandbox(rr, httptest.NewRequest(http.MethodGet, "/sandboxes/foo/stats", nil))
+	assert.Equal(http.StatusOK, rr.Code)
+
+	var stats vc.PodStats
+	assert.NoError(json.Unmarshal(rr.Body.Bytes(), &stats))
+	assert.Equal(uint64(1024), stats.Overhead.Memory)
+
+	rr = httptest.NewRecorder()
+	km.ServeSandbox(rr, httptest.NewRequest(http.MethodGet, "/sandboxes/bar/stats", nil))
+	assert.Equal(http.StatusNotFound, rr.Code)
+}
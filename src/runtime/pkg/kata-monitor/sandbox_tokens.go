@@ -0,0 +1,172 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package katamonitor
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// the reviews of the tokens are cached for the time of a debugging
+	// session not to call the API server on each request, the revoked
+	// tokens being accepted until then
+	sandboxTokenCacheTTL  = time.Minute
+	sandboxTokenCacheSize = 1024
+
+	// DefaultSandboxTokenAudience is the audience of the service account
+	// tokens granting access to the sandboxes.
+	DefaultSandboxTokenAudience = "kata-monitor"
+)
+
+var sandboxTokenAuthorizations = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: promNamespaceMonitor,
+	Name:      "sandbox_token_authorizations_total",
+	Help:      "Requests authorized with the service account token of a pod, by result: granted, denied or error.",
+}, []string{"result"})
+
+// sandboxScopedEndpoints are the read-only endpoints of a sandbox served
+// to the owner of its pod, at /sandboxes/<id>/<endpoint>.
+var sandboxScopedEndpoints = map[string]bool{
+	"metrics": true,
+	"stats":   true,
+	"top":     true,
+}
+
+// tokenReview is a cached review of a token, pod being nil if the token
+// was not authenticated.
+type tokenReview struct {
+	pod     *PodInfo
+	expires time.Time
+}
+
+// SandboxAuthorizer grants the pods access to the read-only endpoints of
+// their own sandbox with their service account token, for the workload
+// owners to debug their pods without access to the whole node. The tokens
+// are authenticated with the TokenReview API, and must be bound to the pod
+// of the sandbox.
+type SandboxAuthorizer struct {
+	sync.Mutex
+	km       *KataMonitor
+	kube     *KubeClient
+	audience string
+	reviews  map[[sha256.Size]byte]tokenReview
+	now      func() time.Time
+}
+
+// NewSandboxAuthorizer returns the authorizer of the sandbox tokens,
+// reviewed with the Kubernetes API for the audience.
+func (km *KataMonitor) NewSandboxAuthorizer(kube *KubeClient, audience string) (*SandboxAuthorizer, error) {
+	if kube == nil {
+		return nil, fmt.Errorf("no Kubernetes client to review the tokens")
+	}
+	if audience == "" {
+		audience = DefaultSandboxTokenAudience
+	}
+
+	prometheus.MustRegister(sandboxTokenAuthorizations)
+
+	return &SandboxAuthorizer{
+		km:       km,
+		kube:     kube,
+		audience: audience,
+		reviews:  make(map[[sha256.Size]byte]tokenReview),
+		now:      time.Now,
+	}, nil
+}
+
+// sandboxScopedRequest returns the sandbox of a request which can be
+// authorized with a sandbox token: a GET of one of its read-only endpoints.
+func sandboxScopedRequest(r *http.Request) (string, bool) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return "", false
+	}
+	if !strings.HasPrefix(r.URL.Path, "/sandboxes/") {
+		return "", false
+	}
+
+	path := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/sandboxes/"), "/"), "/")
+	if len(path) != 2 || !sandboxScopedEndpoints[path[1]] || validateSandboxID(path[0]) != nil {
+		return "", false
+	}
+
+	return path[0], true
+}
+
+// authorize checks that the bearer token of a request is bound to the pod
+// of the sandbox.
+func (a *SandboxAuthorizer) authorize(r *http.Request, sandboxID string) (int, error) {
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "Bearer ") || strings.TrimPrefix(auth, "Bearer ") == "" {
+		return http.StatusUnauthorized, fmt.Errorf("invalid token")
+	}
+
+	pod, err := a.review(strings.TrimPrefix(auth, "Bearer "))
+	if err != nil {
+		sandboxTokenAuthorizations.WithLabelValues("error").Inc()
+		monitorLog.WithError(err).Warn("failed to review sandbox token")
+		return http.StatusServiceUnavailable, fmt.Errorf("cannot review the token")
+	}
+	if pod == nil {
+		sandboxTokenAuthorizations.WithLabelValues("denied").Inc()
+		return http.StatusUnauthorized, fmt.Errorf("invalid token")
+	}
+
+	sandboxPod := a.km.sandboxCache.getPod(sandboxID)
+	if sandboxPod == nil || sandboxPod.Namespace != pod.Namespace || sandboxPod.Name != pod.Name ||
+		(sandboxPod.UID != "" && pod.UID != "" && sandboxPod.UID != pod.UID) {
+		sandboxTokenAuthorizations.WithLabelValues("denied").Inc()
+		monitorLog.WithField("sandbox_id", sandboxID).WithField("pod", pod.Namespace+"/"+pod.Name).
+			Info("sandbox token denied")
+		return http.StatusForbidden, fmt.Errorf("the token does not grant access to sandbox %s", sandboxID)
+	}
+
+	sandboxTokenAuthorizations.WithLabelValues("granted").Inc()
+	return http.StatusOK, nil
+}
+
+// review returns the pod of a token, reviewed by the API server unless a
+// review of the token is cached. The failed reviews are not cached.
+func (a *SandboxAuthorizer) review(token string) (*PodInfo, error) {
+	key := sha256.Sum256([]byte(token))
+	now := a.now()
+
+	a.Lock()
+	cached, ok := a.reviews[key]
+	a.Unlock()
+	if ok && now.Before(cached.expires) {
+		return cached.pod, nil
+	}
+
+	pod, err := a.kube.reviewToken(token, a.audience)
+	if err != nil {
+		return nil, err
+	}
+
+	a.Lock()
+	defer a.Unlock()
+
+	if len(a.reviews) >= sandboxTokenCacheSize {
+		for k, r := range a.reviews {
+			if !now.Before(r.expires) {
+				delete(a.reviews, k)
+			}
+		}
+		// too many live tokens, start over rather than tracking their use
+		if len(a.reviews) >= sandboxTokenCacheSize {
+			a.reviews = make(map[[sha256.Size]byte]tokenReview)
+		}
+	}
+	a.reviews[key] = tokenReview{pod: pod, expires: now.Add(sandboxTokenCacheTTL)}
+
+	return pod, nil
+}
@@ -0,0 +1,234 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package katamonitor
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestTokenReviewServer reviews the "web-token" token, bound to the
+// default/web pod, and the "other-token" token bound to the default/other
+// pod, counting the reviews.
+func newTestTokenReviewServer(reviews *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/apis/authentication.k8s.io/v1/tokenreviews" {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		*reviews++
+
+		var review struct {
+			Spec struct {
+				Token     string   `json:"token"`
+				Audiences []string `json:"audiences"`
+			} `json:"spec"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&review); err != nil || len(review.Spec.Audiences) != 1 {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+
+		pods := map[string]string{"web-token": "web", "other-token": "other"}
+		pod, ok := pods[review.Spec.Token]
+		if !ok || review.Spec.Audiences[0] != "kata-monitor" {
+			fmt.Fprint(w, `{"status": {"authenticated": false}}`)
+			return
+		}
+		fmt.Fprintf(w, `{"status": {
+  "authenticated": true,
+  "user": {
+    "username": "system:serviceaccount:default:%s",
+    "extra": {
+      "authentication.kubernetes.io/pod-name": ["%s"],
+      "authentication.kubernetes.io/pod-uid": ["uid-%s"]
+    }
+  },
+  "audiences": ["kata-monitor"]
+}}`, pod, pod, pod)
+	}))
+}
+
+func TestKubeClientReviewToken(t *testing.T) {
+	assert := assert.New(t)
+
+	reviews := 0
+	server := newTestTokenReviewServer(&reviews)
+	defer server.Close()
+
+	kube, err := NewKubeClient(server.URL, "", "")
+	assert.NoError(err)
+
+	pod, err := kube.reviewToken("web-token", "kata-monitor")
+	assert.NoError(err)
+	assert.Equal(&PodInfo{Name: "web", Namespace: "default", UID: "uid-web"}, pod)
+
+	pod, err = kube.reviewToken("web-token", "other-audience")
+	assert.NoError(err)
+	assert.Nil(pod)
+
+	pod, err = kube.reviewToken("wrong", "kata-monitor")
+	assert.NoError(err)
+	assert.Nil(pod)
+
+	server.Close()
+	_, err = kube.reviewToken("web-token", "kata-monitor")
+	assert.Error(err)
+}
+
+func TestSandboxScopedRequest(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, path := range []string{"/sandboxes/foo/metrics", "/sandboxes/foo/stats", "/sandboxes/foo/top/"} {
+		id, ok := sandboxScopedRequest(httptest.NewRequest(http.MethodGet, path, nil))
+		assert.True(ok, path)
+		assert.Equal("foo", id)
+	}
+
+	for _, path := range []string{
+		"/metrics", "/sandboxes", "/sandboxes/", "/sandboxes/foo", "/agent-url?sandbox=foo",
+		"/sandboxes/foo/containers/bar/attach", "/sandboxes/../metrics", "/sandboxes/foo/../bar/metrics",
+	} {
+		_, ok := sandboxScopedRequest(httptest.NewRequest(http.MethodGet, path, nil))
+		assert.False(ok, path)
+	}
+
+	_, ok := sandboxScopedRequest(httptest.NewRequest(http.MethodPost, "/sandboxes/foo/stats", nil))
+	assert.False(ok)
+}
+
+func TestSandboxAuthorizer(t *testing.T) {
+	assert := assert.New(t)
+
+	reviews := 0
+	server := newTestTokenReviewServer(&reviews)
+	defer server.Close()
+
+	kube, err := NewKubeClient(server.URL, "", "")
+	assert.NoError(err)
+
+	km := &KataMonitor{
+		sandboxCache: &sandboxCache{
+			Mutex:     &sync.Mutex{},
+			sandboxes: map[string]string{"web-sandbox": "k8s.io", "db-sandbox": "k8s.io"},
+			pods: map[string]*PodInfo{
+				"web-sandbox": {Name: "web", Namespace: "default", UID: "uid-web"},
+				"db-sandbox":  {Name: "db", Namespace: "default", UID: "uid-db"},
+			},
+		},
+	}
+
+	now := time.Unix(0, 0)
+	a := &SandboxAuthorizer{
+		km:       km,
+		kube:     kube,
+		audience: DefaultSandboxTokenAudience,
+		reviews:  make(map[[sha256.Size]byte]tokenReview),
+		now:      func() time.Time { return now },
+	}
+
+	authorize := func(token, sandboxID string) int {
+		r := httptest.NewRequest(http.MethodGet, "/sandboxes/"+sandboxID+"/stats", nil)
+		if token != "" {
+			r.Header.Set("Authorization", "Bearer "+token)
+		}
+		status, _ := a.authorize(r, sandboxID)
+		return status
+	}
+
+	assert.Equal(http.StatusOK, authorize("web-token", "web-sandbox"))
+	assert.Equal(http.StatusForbidden, authorize("web-token", "db-sandbox"))
+	assert.Equal(http.StatusForbidden, authorize("web-token", "missing"))
+	assert.Equal(http.StatusUnauthorized, authorize("wrong", "web-sandbox"))
+	assert.Equal(http.StatusUnauthorized, authorize("", "web-sandbox"))
+
+	// the reviews are cached, the denied ones too
+	assert.Equal(2, reviews)
+	assert.Equal(http.StatusUnauthorized, authorize("wrong", "web-sandbox"))
+	assert.Equal(2, reviews)
+
+	now = now.Add(sandboxTokenCacheTTL)
+	assert.Equal(http.StatusOK, authorize("web-token", "web-sandbox"))
+	assert.Equal(3, reviews)
+
+	// a pod with the same name, recreated
+	km.sandboxCache.setPod("web-sandbox", &PodInfo{Name: "web", Namespace: "default", UID: "uid-new"})
+	assert.Equal(http.StatusForbidden, authorize("web-token", "web-sandbox"))
+
+	server.Close()
+	assert.Equal(http.StatusServiceUnavailable, authorize("other-token", "web-sandbox"))
+}
+
+func TestListenerConfigHandlerSandboxes(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "kata-monitor")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+	tokenFile := filepath.Join(dir, "token")
+	assert.NoError(ioutil.WriteFile(tokenFile, []byte("secret\n"), 0600))
+
+	reviews := 0
+	server := newTestTokenReviewServer(&reviews)
+	defer server.Close()
+
+	kube, err := NewKubeClient(server.URL, "", "")
+	assert.NoError(err)
+
+	km := &KataMonitor{
+		sandboxCache: &sandboxCache{
+			Mutex:     &sync.Mutex{},
+			sandboxes: map[string]string{"web-sandbox": "k8s.io"},
+			pods: map[string]*PodInfo{
+				"web-sandbox": {Name: "web", Namespace: "default", UID: "uid-web"},
+			},
+		},
+	}
+
+	c := ListenerConfig{
+		TokenFile: tokenFile,
+		Sandboxes: &SandboxAuthorizer{
+			km:       km,
+			kube:     kube,
+			audience: DefaultSandboxTokenAudience,
+			reviews:  make(map[[sha256.Size]byte]tokenReview),
+			now:      time.Now,
+		},
+	}
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	request := func(method, path, token string) int {
+		r := httptest.NewRequest(method, path, nil)
+		r.Header.Set("Authorization", "Bearer "+token)
+		rr := httptest.NewRecorder()
+		c.handler(ok).ServeHTTP(rr, r)
+		return rr.Code
+	}
+
+	// the token of the listener grants access to everything
+	assert.Equal(http.StatusOK, request(http.MethodGet, "/metrics", "secret"))
+	assert.Equal(http.StatusOK, request(http.MethodGet, "/sandboxes/web-sandbox/metrics", "secret"))
+
+	// the pod token only to the read-only endpoints of its sandbox
+	assert.Equal(http.StatusOK, request(http.MethodGet, "/sandboxes/web-sandbox/metrics", "web-token"))
+	assert.Equal(http.StatusOK, request(http.MethodGet, "/sandboxes/web-sandbox/top", "web-token"))
+	assert.Equal(http.StatusUnauthorized, request(http.MethodGet, "/metrics", "web-token"))
+	assert.Equal(http.StatusUnauthorized, request(http.MethodGet, "/sandboxes", "web-token"))
+	assert.Equal(http.StatusUnauthorized, request(http.MethodGet, "/sandboxes/web-sandbox/containers/c/attach", "web-token"))
+	assert.Equal(http.StatusForbidden, request(http.MethodGet, "/sandboxes/db-sandbox/metrics", "web-token"))
+	assert.Equal(1, reviews)
+}
@@ -0,0 +1,139 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package katamonitor
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// the last scrape errors kept for each sandbox
+const maxScrapeErrors = 10
+
+// ScrapeError is a failed scrape of the metrics of a sandbox.
+type ScrapeError struct {
+	Time  time.Time `json:"time"`
+	Error string    `json:"error"`
+}
+
+// ScrapeStatus is the status of the scrapes of the metrics of a sandbox.
+type ScrapeStatus struct {
+	SandboxID   string    `json:"sandbox_id"`
+	LastScrape  time.Time `json:"last_scrape"`
+	LastSuccess time.Time `json:"last_success"`
+	// ConsecutiveFailures is the number of failed scrapes since the
+	// last successful one.
+	ConsecutiveFailures int `json:"consecutive_failures"`
+	// Errors are the last errors, the most recent one last.
+	Errors []ScrapeError `json:"errors,omitempty"`
+}
+
+// scrapeStatuses keeps the scrape status of the sandboxes, and exports
+// their failure streaks.
+type scrapeStatuses struct {
+	sync.Mutex
+	statuses map[string]*ScrapeStatus
+	desc     *prometheus.Desc
+}
+
+func newScrapeStatuses() *scrapeStatuses {
+	return &scrapeStatuses{
+		statuses: make(map[string]*ScrapeStatus),
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(promNamespaceMonitor, "", "scrape_failure_streak"),
+			"Consecutive failed scrapes of the metrics of a sandbox.",
+			[]string{"sandbox_id"}, nil,
+		),
+	}
+}
+
+// record records the result of a scrape of the metrics of a sandbox.
+func (s *scrapeStatuses) record(sandboxID string, now time.Time, err error) {
+	s.Lock()
+	defer s.Unlock()
+
+	status, ok := s.statuses[sandboxID]
+	if !ok {
+		status = &ScrapeStatus{SandboxID: sandboxID}
+		s.statuses[sandboxID] = status
+	}
+
+	status.LastScrape = now
+	if err == nil {
+		status.LastSuccess = now
+		status.ConsecutiveFailures = 0
+		return
+	}
+
+	status.ConsecutiveFailures++
+	status.Errors = append(status.Errors, ScrapeError{Time: now, Error: err.Error()})
+	if len(status.Errors) > maxScrapeErrors {
+		status.Errors = status.Errors[len(status.Errors)-maxScrapeErrors:]
+	}
+}
+
+// prune forgets the sandboxes which are gone.
+func (s *scrapeStatuses) prune(sandboxes map[string]string) {
+	s.Lock()
+	defer s.Unlock()
+
+	for id := range s.statuses {
+		if _, ok := sandboxes[id]; !ok {
+			delete(s.statuses, id)
+		}
+	}
+}
+
+// list returns the scrape statuses, sorted by sandbox.
+func (s *scrapeStatuses) list() []ScrapeStatus {
+	s.Lock()
+	defer s.Unlock()
+
+	result := make([]ScrapeStatus, 0, len(s.statuses))
+	for _, status := range s.statuses {
+		status := *status
+		status.Errors = append([]ScrapeError(nil), status.Errors...)
+		result = append(result, status)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].SandboxID < result[j].SandboxID
+	})
+
+	return result
+}
+
+func (s *scrapeStatuses) Describe(ch chan<- *prometheus.Desc) {
+	ch <- s.desc
+}
+
+func (s *scrapeStatuses) Collect(ch chan<- prometheus.Metric) {
+	for _, status := range s.list() {
+		ch <- prometheus.MustNewConstMetric(s.desc, prometheus.GaugeValue, float64(status.ConsecutiveFailures), status.SandboxID)
+	}
+}
+
+// ScrapeStatus returns the status of the scrapes of the metrics of the
+// sandboxes, with their last errors, only the failing ones with the
+// failing query.
+func (km *KataMonitor) ScrapeStatus(w http.ResponseWriter, r *http.Request) {
+	_, failing := r.URL.Query()["failing"]
+
+	result := []ScrapeStatus{}
+	for _, status := range km.scrapeStatus.list() {
+		if failing && status.ConsecutiveFailures == 0 {
+			continue
+		}
+		result = append(result, status)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
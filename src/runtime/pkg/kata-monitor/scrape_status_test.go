@@ -0,0 +1,94 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package katamonitor
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScrapeStatuses(t *testing.T) {
+	assert := assert.New(t)
+
+	s := newScrapeStatuses()
+	now := time.Now()
+
+	for i := 0; i < maxScrapeErrors+2; i++ {
+		s.record("failing", now.Add(time.Duration(i)*time.Second), fmt.Errorf("error %d", i))
+	}
+	s.record("flaky", now, fmt.Errorf("timeout"))
+	s.record("flaky", now.Add(time.Second), nil)
+
+	statuses := s.list()
+	assert.Len(statuses, 2)
+
+	assert.Equal("failing", statuses[0].SandboxID)
+	assert.Equal(maxScrapeErrors+2, statuses[0].ConsecutiveFailures)
+	assert.True(statuses[0].LastSuccess.IsZero())
+	assert.Len(statuses[0].Errors, maxScrapeErrors)
+	assert.Equal("error 2", statuses[0].Errors[0].Error)
+	assert.Equal(ScrapeError{Time: now.Add(time.Duration(maxScrapeErrors+1) * time.Second), Error: fmt.Sprintf("error %d", maxScrapeErrors+1)},
+		statuses[0].Errors[maxScrapeErrors-1])
+
+	// the errors are kept after a success
+	assert.Equal(ScrapeStatus{
+		SandboxID:   "flaky",
+		LastScrape:  now.Add(time.Second),
+		LastSuccess: now.Add(time.Second),
+		Errors:      []ScrapeError{{Time: now, Error: "timeout"}},
+	}, statuses[1])
+
+	// failure streaks
+	ch := make(chan prometheus.Metric, 2)
+	s.Collect(ch)
+	close(ch)
+	streaks := make(map[string]float64)
+	for metric := range ch {
+		var m dto.Metric
+		assert.NoError(metric.Write(&m))
+		streaks[m.Label[0].GetValue()] = m.GetGauge().GetValue()
+	}
+	assert.Equal(map[string]float64{"failing": maxScrapeErrors + 2, "flaky": 0}, streaks)
+
+	s.prune(map[string]string{"flaky": "k8s.io"})
+	statuses = s.list()
+	assert.Len(statuses, 1)
+	assert.Equal("flaky", statuses[0].SandboxID)
+}
+
+func TestScrapeStatusHandler(t *testing.T) {
+	assert := assert.New(t)
+
+	km := &KataMonitor{scrapeStatus: newScrapeStatuses()}
+	km.scrapeStatus.record("ok", time.Now(), nil)
+	km.scrapeStatus.record("failing", time.Now(), fmt.Errorf("connection refused"))
+
+	get := func(url string) []ScrapeStatus {
+		rr := httptest.NewRecorder()
+		km.ScrapeStatus(rr, httptest.NewRequest(http.MethodGet, url, nil))
+		assert.Equal(http.StatusOK, rr.Code)
+
+		var statuses []ScrapeStatus
+		assert.NoError(json.Unmarshal(rr.Body.Bytes(), &statuses))
+		return statuses
+	}
+
+	assert.Len(get("/scrape-status"), 2)
+
+	statuses := get("/scrape-status?failing")
+	assert.Len(statuses, 1)
+	assert.Equal("failing", statuses[0].SandboxID)
+	assert.Equal(1, statuses[0].ConsecutiveFailures)
+	assert.Equal("connection refused", statuses[0].Errors[0].Error)
+}
@@ -0,0 +1,171 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package katamonitor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/containerd/containerd"
+	"github.com/containerd/containerd/cio"
+	"github.com/containerd/containerd/errdefs"
+	"github.com/containerd/containerd/namespaces"
+	"github.com/containerd/containerd/oci"
+	crioption "github.com/containerd/containerd/pkg/runtimeoptions/v1"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// selfTestLabel labels the containers of the self tests, their
+	// sandboxes are not monitored.
+	selfTestLabel = "io.katacontainers.monitor.self-test"
+
+	// the containerd namespace of the self tests
+	selfTestNamespace = "kata-monitor"
+)
+
+var (
+	selfTestTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: promNamespaceMonitor,
+		Name:      "self_test_total",
+		Help:      "Self tests launching a Kata sandbox, by result.",
+	},
+		[]string{"result"},
+	)
+
+	selfTestDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: promNamespaceMonitor,
+		Name:      "self_test_duration_seconds",
+		Help:      "Time to launch the sandbox of the successful self tests(seconds).",
+		Buckets:   prometheus.ExponentialBuckets(0.25, 2, 10),
+	})
+
+	selfTestLastSuccess = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: promNamespaceMonitor,
+		Name:      "self_test_last_success_timestamp_seconds",
+		Help:      "Time of the last successful self test(seconds since epoch).",
+	})
+)
+
+// SelfTestConfig is the configuration of the self tests.
+type SelfTestConfig struct {
+	// Image is the image of the container of the sandbox,
+	// pulled if not present.
+	Image string
+	// Runtime is the containerd runtime of the sandbox.
+	Runtime string
+	// ConfigPath is the runtime configuration file of the sandbox,
+	// the default one is used if empty.
+	ConfigPath string
+	Interval   time.Duration
+	Timeout    time.Duration
+}
+
+// StartSelfTest launches a Kata sandbox periodically and tears it down, as
+// a canary detecting a broken runtime or configuration on the node before
+// the pods are scheduled. The results are exported as metrics.
+func (km *KataMonitor) StartSelfTest(config SelfTestConfig) {
+	prometheus.MustRegister(selfTestTotal)
+	prometheus.MustRegister(selfTestDuration)
+	prometheus.MustRegister(selfTestLastSuccess)
+
+	go func() {
+		for {
+			ctx, cancel := context.WithTimeout(context.Background(), config.Timeout)
+			duration, err := km.runSelfTest(ctx, config)
+			cancel()
+
+			recordSelfTest(duration, err, time.Now())
+			time.Sleep(config.Interval)
+		}
+	}()
+}
+
+// recordSelfTest records the result of a self test in the metrics.
+func recordSelfTest(duration time.Duration, err error, now time.Time) {
+	if err != nil {
+		monitorLog.WithError(err).Error("self test failed")
+		selfTestTotal.WithLabelValues("failure").Inc()
+		return
+	}
+
+	monitorLog.WithField("duration", duration).Debug("self test succeeded")
+	selfTestTotal.WithLabelValues("success").Inc()
+	selfTestDuration.Observe(duration.Seconds())
+	selfTestLastSuccess.Set(float64(now.Unix()))
+}
+
+// runSelfTest creates a sandbox with the containerd runtime, as the CRI
+// plugin does for a pod, and waits for its container to be running. It
+// returns the time taken to launch the sandbox.
+func (km *KataMonitor) runSelfTest(ctx context.Context, config SelfTestConfig) (time.Duration, error) {
+	client, err := containerd.New(km.containerdAddr)
+	if err != nil {
+		return 0, err
+	}
+	defer client.Close()
+
+	ctx = namespaces.WithNamespace(ctx, selfTestNamespace)
+
+	image, err := client.GetImage(ctx, config.Image)
+	if errdefs.IsNotFound(err) {
+		image, err = client.Pull(ctx, config.Image, containerd.WithPullUnpack)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get self test image %s: %v", config.Image, err)
+	}
+
+	var runtimeOptions interface{}
+	if config.ConfigPath != "" {
+		runtimeOptions = &crioption.Options{ConfigPath: config.ConfigPath}
+	}
+
+	// the sandbox is torn down even if the self test timed out
+	cleanupCtx, cancel := context.WithTimeout(namespaces.WithNamespace(context.Background(), selfTestNamespace), config.Timeout)
+	defer cancel()
+
+	id := fmt.Sprintf("kata-monitor-self-test-%d", time.Now().UnixNano())
+	start := time.Now()
+
+	container, err := client.NewContainer(ctx, id,
+		containerd.WithContainerLabels(map[string]string{selfTestLabel: "true"}),
+		containerd.WithNewSnapshot(id, image),
+		containerd.WithNewSpec(oci.WithImageConfig(image), oci.WithHostname(id)),
+		containerd.WithRuntime(config.Runtime, runtimeOptions))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create self test container: %v", err)
+	}
+	defer func() {
+		if err := container.Delete(cleanupCtx, containerd.WithSnapshotCleanup); err != nil {
+			monitorLog.WithError(err).WithField("container", id).Warn("failed to delete self test container")
+		}
+	}()
+
+	task, err := container.NewTask(ctx, cio.NullIO)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create self test sandbox: %v", err)
+	}
+	defer func() {
+		if _, err := task.Delete(cleanupCtx, containerd.WithProcessKill); err != nil {
+			monitorLog.WithError(err).WithField("container", id).Warn("failed to delete self test sandbox")
+		}
+	}()
+
+	if err := task.Start(ctx); err != nil {
+		return 0, fmt.Errorf("failed to start self test sandbox: %v", err)
+	}
+
+	status, err := task.Status(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get self test sandbox status: %v", err)
+	}
+	if status.Status != containerd.Running {
+		return 0, fmt.Errorf("self test sandbox is %s, not running", status.Status)
+	}
+
+	return time.Since(start), nil
+}
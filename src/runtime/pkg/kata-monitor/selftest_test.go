@@ -0,0 +1,43 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package katamonitor
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordSelfTest(t *testing.T) {
+	assert := assert.New(t)
+
+	selfTestTotal.Reset()
+
+	now := time.Now()
+	recordSelfTest(2*time.Second, nil, now)
+	recordSelfTest(0, errors.New("failed to start self test sandbox"), now.Add(time.Minute))
+
+	m := &dto.Metric{}
+	assert.NoError(selfTestTotal.WithLabelValues("success").Write(m))
+	assert.Equal(float64(1), m.GetCounter().GetValue())
+
+	m = &dto.Metric{}
+	assert.NoError(selfTestTotal.WithLabelValues("failure").Write(m))
+	assert.Equal(float64(1), m.GetCounter().GetValue())
+
+	m = &dto.Metric{}
+	assert.NoError(selfTestDuration.Write(m))
+	assert.Equal(uint64(1), m.GetHistogram().GetSampleCount())
+	assert.Equal(float64(2), m.GetHistogram().GetSampleSum())
+
+	// the failures don't change the last success
+	m = &dto.Metric{}
+	assert.NoError(selfTestLastSuccess.Write(m))
+	assert.Equal(float64(now.Unix()), m.GetGauge().GetValue())
+}
@@ -7,17 +7,17 @@ package katamonitor
 
 import (
 	"fmt"
-	"io/ioutil"
-	"net"
 	"net/http"
 	"time"
 
-	shim "github.com/kata-containers/kata-containers/src/runtime/containerd-shim-v2"
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/shimclient"
 )
 
-const (
-	defaultTimeout = 3 * time.Second
-)
+const defaultTimeout = 3 * time.Second
+
+// storedShimAddress returns the management socket address stored by the
+// shim of a sandbox, it is set by the KataMonitor.
+var storedShimAddress func(sandboxID string) (string, error)
 
 func commonServeError(w http.ResponseWriter, status int, err error) {
 	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
@@ -27,58 +27,28 @@ func commonServeError(w http.ResponseWriter, status int, err error) {
 	}
 }
 
-func getSandboxIDFromReq(r *http.Request) (string, error) {
-	sandbox := r.URL.Query().Get("sandbox")
-	if sandbox != "" {
-		return sandbox, nil
-	}
-	return "", fmt.Errorf("sandbox not found in %+v", r.URL.Query())
-}
-
 // BuildShimClient builds and returns an http client for communicating with the provided sandbox
 func BuildShimClient(sandboxID string, timeout time.Duration) (*http.Client, error) {
-	return buildUnixSocketClient(shim.SocketAddress(sandboxID), timeout)
+	return shimclient.NewHTTPClient(shimAddress(sandboxID), timeout), nil
 }
 
-// buildUnixSocketClient build http client for Unix socket
-func buildUnixSocketClient(socketAddr string, timeout time.Duration) (*http.Client, error) {
-	transport := &http.Transport{
-		DisableKeepAlives: true,
-		Dial: func(proto, addr string) (conn net.Conn, err error) {
-			return net.Dial("unix", "\x00"+socketAddr)
-		},
-	}
-
-	client := &http.Client{
-		Transport: transport,
-	}
+// newShimClient returns a management API client of the shim of a sandbox.
+func newShimClient(sandboxID string, timeout time.Duration) *shimclient.Client {
+	return shimclient.New(shimAddress(sandboxID), timeout)
+}
 
-	if timeout > 0 {
-		client.Timeout = timeout
+// shimAddress returns the management socket address of the shim of a
+// sandbox: the one stored by the shim when known, the default one otherwise.
+func shimAddress(sandboxID string) string {
+	if storedShimAddress != nil {
+		if address, err := storedShimAddress(sandboxID); err == nil && address != "" {
+			return address
+		}
 	}
 
-	return client, nil
+	return shimclient.DefaultAddress(sandboxID)
 }
 
 func doGet(sandboxID string, timeoutInSeconds time.Duration, urlPath string) ([]byte, error) {
-	client, err := BuildShimClient(sandboxID, timeoutInSeconds)
-	if err != nil {
-		return nil, err
-	}
-
-	resp, err := client.Get(fmt.Sprintf("http://shim/%s", urlPath))
-	if err != nil {
-		return nil, err
-	}
-
-	defer func() {
-		resp.Body.Close()
-	}()
-
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	return body, nil
+	return newShimClient(sandboxID, timeoutInSeconds).Get("/" + urlPath)
 }
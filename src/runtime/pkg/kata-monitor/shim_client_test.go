@@ -0,0 +1,72 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package katamonitor
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	shim "github.com/kata-containers/kata-containers/src/runtime/containerd-shim-v2"
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/shimclient"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShimAddress(t *testing.T) {
+	assert := assert.New(t)
+
+	saved := storedShimAddress
+	defer func() {
+		storedShimAddress = saved
+	}()
+
+	storedShimAddress = nil
+	assert.Equal(shim.SocketAddress("foo"), shimAddress("foo"))
+
+	storedShimAddress = func(sandboxID string) (string, error) {
+		if sandboxID == "foo" {
+			return "unix:///tmp/foo.sock", nil
+		}
+		return "", fmt.Errorf("sandbox %s not in cache", sandboxID)
+	}
+	assert.Equal("unix:///tmp/foo.sock", shimAddress("foo"))
+	assert.Equal(shim.SocketAddress("bar"), shimAddress("bar"))
+}
+
+func TestPathnameSocketClient(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "kata-monitor")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	// a shim serving on a pathname socket
+	path := filepath.Join(dir, "shim-monitor")
+	listener, err := net.Listen("unix", path)
+	assert.NoError(err)
+
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, r.URL.Path)
+	})}
+	go srv.Serve(listener)
+	defer srv.Close()
+
+	saved := storedShimAddress
+	defer func() {
+		storedShimAddress = saved
+	}()
+	storedShimAddress = func(sandboxID string) (string, error) {
+		return shimclient.UnixSocketScheme + path, nil
+	}
+
+	body, err := doGet("foo", defaultTimeout, "agent-url")
+	assert.NoError(err)
+	assert.Equal("/agent-url", string(body))
+}
@@ -0,0 +1,104 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package katamonitor
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/blang/semver"
+	shim "github.com/kata-containers/kata-containers/src/runtime/containerd-shim-v2"
+)
+
+// ShimInfo describes the shim of a sandbox in the JSON output of ListShims.
+type ShimInfo struct {
+	SandboxInfo
+	shim.VersionInfo
+
+	// Error is why the shim could not be reached, its version is then
+	// unknown.
+	Error string `json:"error,omitempty"`
+}
+
+// getShimVersion returns the version of the shim of a sandbox,
+// it is replaced by the tests.
+var getShimVersion = getShimVersionInfo
+
+// getShimVersionInfo gets the version from the shim management endpoint. The
+// shims older than the endpoint have an empty version.
+func getShimVersionInfo(sandboxID string) (*shim.VersionInfo, error) {
+	info, err := newShimClient(sandboxID, defaultTimeout).Version()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the version of shim %s: %v", sandboxID, err)
+	}
+	return info, nil
+}
+
+// shimOutdated returns true if the shim version is older than min. The
+// unknown versions, e.g. of the shims older than the version endpoint,
+// are outdated.
+func shimOutdated(version string, min semver.Version) bool {
+	v, err := semver.ParseTolerant(version)
+	if err != nil {
+		return true
+	}
+	return v.LT(min)
+}
+
+// ListShims lists the shims of the sandboxes with their version as JSON,
+// only the ones older than the version-older-than query when set. The
+// unreachable shims have an unknown version, so they are outdated: they
+// may still run an old runtime.
+func (km *KataMonitor) ListShims(w http.ResponseWriter, r *http.Request) {
+	var min *semver.Version
+	if value := r.URL.Query().Get("version-older-than"); value != "" {
+		v, err := semver.ParseTolerant(value)
+		if err != nil {
+			commonServeError(w, http.StatusBadRequest, fmt.Errorf("invalid version %q: %v", value, err))
+			return
+		}
+		min = &v
+	}
+
+	sandboxes := km.getSandboxList()
+	sort.Strings(sandboxes)
+
+	result := []ShimInfo{}
+	for _, s := range sandboxes {
+		namespace, err := km.getSandboxNamespace(s)
+		if err != nil {
+			// deleted in the meantime
+			continue
+		}
+
+		info := ShimInfo{
+			SandboxInfo: SandboxInfo{
+				ID:        s,
+				Namespace: namespace,
+				Pod:       km.sandboxCache.getPod(s),
+			},
+		}
+
+		version, err := getShimVersion(s)
+		if err != nil {
+			monitorLog.WithError(err).WithField("sandbox", s).Warn("failed to get the shim version")
+			info.Error = err.Error()
+		} else {
+			info.VersionInfo = *version
+		}
+
+		if min != nil && !shimOutdated(info.Version, *min) {
+			continue
+		}
+
+		result = append(result, info)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
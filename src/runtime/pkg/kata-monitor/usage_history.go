@@ -0,0 +1,265 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package katamonitor
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+)
+
+// the processes of a sandbox running on the host
+var usageProcesses = []string{"kata_hypervisor", "kata_shim", "kata_virtiofsd"}
+
+// the clock ticks per second of the CPU times of /proc/<pid>/stat
+const userHZ = 100
+
+// UsageSample is the host resource usage of the processes of a sandbox:
+// the hypervisor, the shim and virtiofsd.
+type UsageSample struct {
+	Time time.Time `json:"time"`
+	// CPU is the CPU usage since the previous sample (percent of a CPU).
+	CPU         float64 `json:"cpu"`
+	MemoryBytes uint64  `json:"memory_bytes"`
+}
+
+// SandboxTop is the usage history of a sandbox, oldest sample first.
+type SandboxTop struct {
+	ID       string        `json:"id"`
+	Interval string        `json:"interval"`
+	Samples  []UsageSample `json:"samples"`
+}
+
+// usageRing keeps the last samples of a sandbox.
+type usageRing struct {
+	samples []UsageSample
+	next    int
+	full    bool
+
+	// the CPU time of the previous observation, in clock ticks
+	lastTicks float64
+	lastTime  time.Time
+}
+
+func newUsageRing(size int) *usageRing {
+	return &usageRing{samples: make([]UsageSample, size)}
+}
+
+func (r *usageRing) add(s UsageSample) {
+	r.samples[r.next] = s
+	r.next = (r.next + 1) % len(r.samples)
+	r.full = r.full || r.next == 0
+}
+
+// list returns the samples, oldest first.
+func (r *usageRing) list() []UsageSample {
+	if !r.full {
+		return append([]UsageSample{}, r.samples[:r.next]...)
+	}
+	return append(append([]UsageSample{}, r.samples[r.next:]...), r.samples[:r.next]...)
+}
+
+// observe adds a sample from the CPU time and the memory of the processes,
+// the first observation only sets the reference of the CPU usage.
+func (r *usageRing) observe(now time.Time, ticks float64, memory uint64) {
+	defer func() {
+		r.lastTicks, r.lastTime = ticks, now
+	}()
+
+	// the first observation, or a process restarted
+	if r.lastTime.IsZero() || ticks < r.lastTicks {
+		return
+	}
+
+	elapsed := now.Sub(r.lastTime).Seconds()
+	if elapsed <= 0 {
+		return
+	}
+
+	r.add(UsageSample{
+		Time:        now,
+		CPU:         (ticks - r.lastTicks) / userHZ / elapsed * 100,
+		MemoryBytes: memory,
+	})
+}
+
+// usageHistory records the resource usage of the sandboxes periodically.
+type usageHistory struct {
+	sync.Mutex
+
+	interval  time.Duration
+	size      int
+	sandboxes map[string]*usageRing
+}
+
+func newUsageHistory(interval, duration time.Duration) *usageHistory {
+	size := int(duration / interval)
+	if size < 1 {
+		size = 1
+	}
+
+	return &usageHistory{
+		interval:  interval,
+		size:      size,
+		sandboxes: make(map[string]*usageRing),
+	}
+}
+
+// sumItems returns the sum of the metrics of the family with the item label.
+func sumItems(mfs []*dto.MetricFamily, name string, items ...string) (float64, bool) {
+	var sum float64
+	found := false
+
+	for _, mf := range mfs {
+		if mf.GetName() != name {
+			continue
+		}
+		for _, m := range mf.Metric {
+			if m.Gauge == nil {
+				continue
+			}
+			for _, l := range m.Label {
+				if l.GetName() != "item" {
+					continue
+				}
+				for _, item := range items {
+					if l.GetValue() == item {
+						sum += m.Gauge.GetValue()
+						found = true
+					}
+				}
+			}
+		}
+	}
+
+	return sum, found
+}
+
+// sandboxUsage returns the CPU time in clock ticks and the resident memory
+// of the processes of a sandbox.
+func sandboxUsage(sandboxID string) (float64, uint64, error) {
+	mfs, err := getSandboxMetrics(sandboxID)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var ticks, memory float64
+	found := false
+	for _, p := range usageProcesses {
+		if t, ok := sumItems(mfs, p+"_proc_stat", "utime", "stime"); ok {
+			ticks += t
+			found = true
+		}
+		if m, ok := sumItems(mfs, p+"_proc_status", "vmrss"); ok {
+			memory += m
+		}
+	}
+
+	if !found {
+		return 0, 0, fmt.Errorf("no process statistics in the metrics of sandbox %s", sandboxID)
+	}
+
+	return ticks, uint64(memory), nil
+}
+
+// record samples the usage of the running sandboxes.
+func (h *usageHistory) record(sc *sandboxCache, now time.Time) {
+	type usage struct {
+		ticks  float64
+		memory uint64
+	}
+
+	// the shims are requested without holding the lock
+	sandboxes := make(map[string]*usage)
+	for id := range sc.getAllSandboxes() {
+		ticks, memory, err := sandboxUsage(id)
+		if err != nil {
+			monitorLog.WithError(err).WithField("sandbox_id", id).Debug("failed to get the usage of sandbox")
+			sandboxes[id] = nil
+			continue
+		}
+		sandboxes[id] = &usage{ticks, memory}
+	}
+
+	h.Lock()
+	defer h.Unlock()
+
+	for id, u := range sandboxes {
+		r, ok := h.sandboxes[id]
+		if !ok {
+			r = newUsageRing(h.size)
+			h.sandboxes[id] = r
+		}
+		if u != nil {
+			r.observe(now, u.ticks, u.memory)
+		}
+	}
+
+	// forget the deleted sandboxes
+	for id := range h.sandboxes {
+		if _, found := sandboxes[id]; !found {
+			delete(h.sandboxes, id)
+		}
+	}
+}
+
+func (h *usageHistory) get(sandboxID string) ([]UsageSample, bool) {
+	h.Lock()
+	defer h.Unlock()
+
+	r, ok := h.sandboxes[sandboxID]
+	if !ok {
+		return nil, false
+	}
+	return r.list(), true
+}
+
+// StartUsageHistory records the resource usage of the sandboxes every
+// interval, keeping the samples of the last duration.
+func (km *KataMonitor) StartUsageHistory(interval, duration time.Duration) {
+	km.usage = newUsageHistory(interval, duration)
+
+	go func() {
+		for {
+			km.usage.record(km.sandboxCache, time.Now())
+			time.Sleep(interval)
+		}
+	}()
+}
+
+// SandboxTop returns the recent resource usage of a sandbox,
+// served at /sandboxes/<id>/top
+func (km *KataMonitor) SandboxTop(w http.ResponseWriter, r *http.Request) {
+	path := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/sandboxes/"), "/"), "/")
+	if len(path) != 2 || path[0] == "" || path[1] != "top" {
+		commonServeError(w, http.StatusNotFound, fmt.Errorf("invalid path %s", r.URL.Path))
+		return
+	}
+	sandboxID := path[0]
+
+	if km.usage == nil {
+		commonServeError(w, http.StatusNotFound, fmt.Errorf("usage history is not enabled"))
+		return
+	}
+
+	samples, ok := km.usage.get(sandboxID)
+	if !ok {
+		commonServeError(w, http.StatusNotFound, fmt.Errorf("sandbox %s not found", sandboxID))
+		return
+	}
+
+	w.Header().Set(contentTypeHeader, "application/json")
+	json.NewEncoder(w).Encode(SandboxTop{
+		ID:       sandboxID,
+		Interval: km.usage.interval.String(),
+		Samples:  samples,
+	})
+}
@@ -0,0 +1,129 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package katamonitor
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+)
+
+func procGauge(name, item string, value float64) *dto.MetricFamily {
+	labelName := "item"
+	return &dto.MetricFamily{
+		Name: &name,
+		Metric: []*dto.Metric{{
+			Label: []*dto.LabelPair{{Name: &labelName, Value: &item}},
+			Gauge: &dto.Gauge{Value: &value},
+		}},
+	}
+}
+
+// mockSandboxUsage mocks the shim metrics of the sandboxes,
+// with the CPU ticks of their processes
+func mockSandboxUsage(ticks map[string]float64) func() {
+	saved := getSandboxMetrics
+
+	getSandboxMetrics = func(sandboxID string) ([]*dto.MetricFamily, error) {
+		t, ok := ticks[sandboxID]
+		if !ok {
+			return nil, fmt.Errorf("no shim for sandbox %s", sandboxID)
+		}
+		return []*dto.MetricFamily{
+			procGauge("kata_hypervisor_proc_stat", "utime", t),
+			procGauge("kata_hypervisor_proc_stat", "stime", t),
+			procGauge("kata_hypervisor_proc_status", "vmrss", 1000),
+			procGauge("kata_shim_proc_status", "vmrss", 24),
+		}, nil
+	}
+
+	return func() {
+		getSandboxMetrics = saved
+	}
+}
+
+func TestUsageRing(t *testing.T) {
+	assert := assert.New(t)
+
+	r := newUsageRing(3)
+	assert.Empty(r.list())
+
+	now := time.Now()
+	r.observe(now, 0, 1)
+	assert.Empty(r.list())
+
+	for i := 1; i <= 4; i++ {
+		r.observe(now.Add(time.Duration(i)*time.Second), float64(i*10), uint64(i))
+	}
+
+	samples := r.list()
+	assert.Len(samples, 3)
+	for i, s := range samples {
+		assert.Equal(uint64(i+2), s.MemoryBytes)
+		// 10 ticks in a second
+		assert.Equal(10.0, s.CPU)
+	}
+
+	// the process restarted
+	r.observe(now.Add(5*time.Second), 0, 5)
+	assert.Equal(uint64(4), r.list()[2].MemoryBytes)
+}
+
+func TestSandboxTop(t *testing.T) {
+	assert := assert.New(t)
+
+	ticks := map[string]float64{"sandbox": 0}
+	restore := mockSandboxUsage(ticks)
+	defer restore()
+
+	sc := &sandboxCache{
+		Mutex:     &sync.Mutex{},
+		sandboxes: map[string]string{"sandbox": "k8s.io"},
+	}
+	km := &KataMonitor{sandboxCache: sc}
+
+	get := func(path string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		km.SandboxTop(rr, httptest.NewRequest(http.MethodGet, path, nil))
+		return rr
+	}
+
+	// the history is not enabled
+	assert.Equal(http.StatusNotFound, get("/sandboxes/sandbox/top").Code)
+
+	km.usage = newUsageHistory(10*time.Second, time.Minute)
+	now := time.Now()
+	km.usage.record(sc, now)
+	ticks["sandbox"] = 500
+	km.usage.record(sc, now.Add(10*time.Second))
+
+	rr := get("/sandboxes/sandbox/top")
+	assert.Equal(http.StatusOK, rr.Code)
+
+	var top SandboxTop
+	assert.NoError(json.Unmarshal(rr.Body.Bytes(), &top))
+	assert.Equal("sandbox", top.ID)
+	assert.Equal("10s", top.Interval)
+	assert.Len(top.Samples, 1)
+	// utime and stime of 500 ticks each in 10s
+	assert.Equal(100.0, top.Samples[0].CPU)
+	assert.Equal(uint64(1024), top.Samples[0].MemoryBytes)
+
+	assert.Equal(http.StatusNotFound, get("/sandboxes/unknown/top").Code)
+	assert.Equal(http.StatusNotFound, get("/sandboxes/sandbox/foo").Code)
+
+	// deleted sandboxes are forgotten
+	sc.deleteIfExists("sandbox")
+	km.usage.record(sc, now.Add(20*time.Second))
+	assert.Equal(http.StatusNotFound, get("/sandboxes/sandbox/top").Code)
+}
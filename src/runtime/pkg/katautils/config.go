@@ -10,12 +10,15 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"os"
 	"path/filepath"
 	goruntime "runtime"
 	"strings"
+	"time"
 
 	"github.com/BurntSushi/toml"
 	govmmQemu "github.com/kata-containers/govmm/qemu"
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/katautils/featuregate"
 	"github.com/kata-containers/kata-containers/src/runtime/pkg/katautils/katatrace"
 	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/device/config"
@@ -29,6 +32,24 @@ const (
 	defaultHypervisor = vc.QemuHypervisor
 )
 
+// groups of optional shim metrics, see shim_metrics_groups in the configuration
+const (
+	ShimMetricsGroupShim       = "shim"
+	ShimMetricsGroupHypervisor = "hypervisor"
+	ShimMetricsGroupContainers = "containers"
+	ShimMetricsGroupOverhead   = "overhead"
+	ShimMetricsGroupStorage    = "storage"
+)
+
+// ShimMetricsGroups are the groups of optional shim metrics.
+var ShimMetricsGroups = []string{
+	ShimMetricsGroupShim,
+	ShimMetricsGroupHypervisor,
+	ShimMetricsGroupContainers,
+	ShimMetricsGroupOverhead,
+	ShimMetricsGroupStorage,
+}
+
 // The TOML configuration file contains a number of sections (or
 // tables). The names of these tables are in dotted ("nested table")
 // form:
@@ -73,91 +94,168 @@ type factory struct {
 }
 
 type hypervisor struct {
-	Path                    string   `toml:"path"`
-	JailerPath              string   `toml:"jailer_path"`
-	Kernel                  string   `toml:"kernel"`
-	CtlPath                 string   `toml:"ctlpath"`
-	Initrd                  string   `toml:"initrd"`
-	Image                   string   `toml:"image"`
-	Firmware                string   `toml:"firmware"`
-	MachineAccelerators     string   `toml:"machine_accelerators"`
-	CPUFeatures             string   `toml:"cpu_features"`
-	KernelParams            string   `toml:"kernel_params"`
-	MachineType             string   `toml:"machine_type"`
-	BlockDeviceDriver       string   `toml:"block_device_driver"`
-	EntropySource           string   `toml:"entropy_source"`
-	SharedFS                string   `toml:"shared_fs"`
-	VirtioFSDaemon          string   `toml:"virtio_fs_daemon"`
-	VirtioFSCache           string   `toml:"virtio_fs_cache"`
-	VhostUserStorePath      string   `toml:"vhost_user_store_path"`
-	FileBackedMemRootDir    string   `toml:"file_mem_backend"`
-	GuestHookPath           string   `toml:"guest_hook_path"`
-	GuestMemoryDumpPath     string   `toml:"guest_memory_dump_path"`
-	HypervisorPathList      []string `toml:"valid_hypervisor_paths"`
-	JailerPathList          []string `toml:"valid_jailer_paths"`
-	CtlPathList             []string `toml:"valid_ctlpaths"`
-	VirtioFSDaemonList      []string `toml:"valid_virtio_fs_daemon_paths"`
-	VirtioFSExtraArgs       []string `toml:"virtio_fs_extra_args"`
-	PFlashList              []string `toml:"pflashes"`
-	VhostUserStorePathList  []string `toml:"valid_vhost_user_store_paths"`
-	FileBackedMemRootList   []string `toml:"valid_file_mem_backends"`
-	EntropySourceList       []string `toml:"valid_entropy_sources"`
-	EnableAnnotations       []string `toml:"enable_annotations"`
-	RxRateLimiterMaxRate    uint64   `toml:"rx_rate_limiter_max_rate"`
-	TxRateLimiterMaxRate    uint64   `toml:"tx_rate_limiter_max_rate"`
-	VirtioFSCacheSize       uint32   `toml:"virtio_fs_cache_size"`
-	NumVCPUs                int32    `toml:"default_vcpus"`
-	DefaultMaxVCPUs         uint32   `toml:"default_maxvcpus"`
-	MemorySize              uint32   `toml:"default_memory"`
-	MemSlots                uint32   `toml:"memory_slots"`
-	MemOffset               uint64   `toml:"memory_offset"`
-	DefaultBridges          uint32   `toml:"default_bridges"`
-	Msize9p                 uint32   `toml:"msize_9p"`
-	PCIeRootPort            uint32   `toml:"pcie_root_port"`
-	BlockDeviceCacheSet     bool     `toml:"block_device_cache_set"`
-	BlockDeviceCacheDirect  bool     `toml:"block_device_cache_direct"`
-	BlockDeviceCacheNoflush bool     `toml:"block_device_cache_noflush"`
-	EnableVhostUserStore    bool     `toml:"enable_vhost_user_store"`
-	DisableBlockDeviceUse   bool     `toml:"disable_block_device_use"`
-	MemPrealloc             bool     `toml:"enable_mem_prealloc"`
-	HugePages               bool     `toml:"enable_hugepages"`
-	VirtioMem               bool     `toml:"enable_virtio_mem"`
-	IOMMU                   bool     `toml:"enable_iommu"`
-	IOMMUPlatform           bool     `toml:"enable_iommu_platform"`
-	Swap                    bool     `toml:"enable_swap"`
-	Debug                   bool     `toml:"enable_debug"`
-	DisableNestingChecks    bool     `toml:"disable_nesting_checks"`
-	EnableIOThreads         bool     `toml:"enable_iothreads"`
-	DisableImageNvdimm      bool     `toml:"disable_image_nvdimm"`
-	HotplugVFIOOnRootBus    bool     `toml:"hotplug_vfio_on_root_bus"`
-	DisableVhostNet         bool     `toml:"disable_vhost_net"`
-	GuestMemoryDumpPaging   bool     `toml:"guest_memory_dump_paging"`
-	ConfidentialGuest       bool     `toml:"confidential_guest"`
+	Path                      string   `toml:"path"`
+	JailerPath                string   `toml:"jailer_path"`
+	Kernel                    string   `toml:"kernel"`
+	CtlPath                   string   `toml:"ctlpath"`
+	Initrd                    string   `toml:"initrd"`
+	Image                     string   `toml:"image"`
+	RootfsDisk                string   `toml:"rootfs_disk"`
+	RootfsDiskFormat          string   `toml:"rootfs_disk_format"`
+	RootfsDiskOverlayDir      string   `toml:"rootfs_disk_overlay_dir"`
+	Firmware                  string   `toml:"firmware"`
+	MachineAccelerators       string   `toml:"machine_accelerators"`
+	CPUFeatures               string   `toml:"cpu_features"`
+	CPUModel                  string   `toml:"cpu_model"`
+	KernelParams              string   `toml:"kernel_params"`
+	MachineType               string   `toml:"machine_type"`
+	BlockDeviceDriver         string   `toml:"block_device_driver"`
+	EntropySource             string   `toml:"entropy_source"`
+	SharedFS                  string   `toml:"shared_fs"`
+	VirtioFSDaemon            string   `toml:"virtio_fs_daemon"`
+	VirtioFSCache             string   `toml:"virtio_fs_cache"`
+	VhostUserStorePath        string   `toml:"vhost_user_store_path"`
+	FileBackedMemRootDir      string   `toml:"file_mem_backend"`
+	GuestHookPath             string   `toml:"guest_hook_path"`
+	GuestMemoryDumpPath       string   `toml:"guest_memory_dump_path"`
+	SELinuxLabel              string   `toml:"selinux_label"`
+	GICVersion                string   `toml:"gic_version"`
+	MMUMode                   string   `toml:"mmu_mode"`
+	VMMIOClass                string   `toml:"vmm_io_class"`
+	HypervisorPathList        []string `toml:"valid_hypervisor_paths"`
+	JailerPathList            []string `toml:"valid_jailer_paths"`
+	CtlPathList               []string `toml:"valid_ctlpaths"`
+	VirtioFSDaemonList        []string `toml:"valid_virtio_fs_daemon_paths"`
+	VirtioFSExtraArgs         []string `toml:"virtio_fs_extra_args"`
+	VirtioFSDaemonIDs         string   `toml:"virtio_fs_daemon_ids"`
+	PFlashList                []string `toml:"pflashes"`
+	VhostUserStorePathList    []string `toml:"valid_vhost_user_store_paths"`
+	VhostUserSocketList       []string `toml:"valid_vhost_user_sockets"`
+	FileBackedMemRootList     []string `toml:"valid_file_mem_backends"`
+	EntropySourceList         []string `toml:"valid_entropy_sources"`
+	RootfsDiskList            []string `toml:"valid_rootfs_disks"`
+	SEHostKeyDocuments        []string `toml:"se_host_key_documents"`
+	EnableAnnotations         []string `toml:"enable_annotations"`
+	VMMSandboxingSyscalls     []string `toml:"vmm_sandboxing_syscalls"`
+	VMMSandboxingCapabilities []string `toml:"vmm_sandboxing_capabilities"`
+	HypervisorIDs             string   `toml:"hypervisor_ids"`
+	RxRateLimiterMaxRate      uint64   `toml:"rx_rate_limiter_max_rate"`
+	TxRateLimiterMaxRate      uint64   `toml:"tx_rate_limiter_max_rate"`
+	VirtioFSCacheSize         uint32   `toml:"virtio_fs_cache_size"`
+	NumVCPUs                  int32    `toml:"default_vcpus"`
+	DefaultMaxVCPUs           uint32   `toml:"default_maxvcpus"`
+	MemorySize                uint32   `toml:"default_memory"`
+	MemSlots                  uint32   `toml:"memory_slots"`
+	MemOffset                 uint64   `toml:"memory_offset"`
+	DefaultBridges            uint32   `toml:"default_bridges"`
+	Msize9p                   uint32   `toml:"msize_9p"`
+	PCIeRootPort              uint32   `toml:"pcie_root_port"`
+	SMTMode                   uint32   `toml:"smt_mode"`
+	MemoryBlockSizeMB         uint32   `toml:"memory_block_size_mb"`
+	VMMOOMScoreAdj            int      `toml:"vmm_oom_score_adj"`
+	VMMNice                   int      `toml:"vmm_nice"`
+	VMMIOPriority             uint32   `toml:"vmm_io_priority"`
+	VCPURealtimePriority      uint32   `toml:"vcpu_realtime_priority"`
+	BlockDeviceCacheSet       bool     `toml:"block_device_cache_set"`
+	BlockDeviceCacheDirect    bool     `toml:"block_device_cache_direct"`
+	BlockDeviceCacheNoflush   bool     `toml:"block_device_cache_noflush"`
+	EnableVhostUserStore      bool     `toml:"enable_vhost_user_store"`
+	DisableBlockDeviceUse     bool     `toml:"disable_block_device_use"`
+	LazyBlockDeviceAttach     bool     `toml:"lazy_block_device_attach"`
+	MemPrealloc               bool     `toml:"enable_mem_prealloc"`
+	HugePages                 bool     `toml:"enable_hugepages"`
+	VirtioMem                 bool     `toml:"enable_virtio_mem"`
+	IOMMU                     bool     `toml:"enable_iommu"`
+	IOMMUPlatform             bool     `toml:"enable_iommu_platform"`
+	Swap                      bool     `toml:"enable_swap"`
+	Debug                     bool     `toml:"enable_debug"`
+	DisableNestingChecks      bool     `toml:"disable_nesting_checks"`
+	EnableIOThreads           bool     `toml:"enable_iothreads"`
+	DisableImageNvdimm        bool     `toml:"disable_image_nvdimm"`
+	DisableITS                bool     `toml:"disable_its"`
+	HotplugVFIOOnRootBus      bool     `toml:"hotplug_vfio_on_root_bus"`
+	DisableVhostNet           bool     `toml:"disable_vhost_net"`
+	GuestMemoryDumpPaging     bool     `toml:"guest_memory_dump_paging"`
+	ConfidentialGuest         bool     `toml:"confidential_guest"`
+	VMMSandboxing             bool     `toml:"enable_vmm_sandboxing"`
+	SELinuxCategories         bool     `toml:"enable_selinux_categories"`
+
+	// NUMANodes is the guest NUMA topology, in inline tables
+	NUMANodes []numaNode `toml:"guest_numa_nodes"`
+}
+
+type numaNode struct {
+	VCPUs      []uint32 `toml:"vcpus"`
+	MemorySize uint32   `toml:"memory"`
 }
 
 type runtime struct {
-	InterNetworkModel   string   `toml:"internetworking_model"`
-	JaegerEndpoint      string   `toml:"jaeger_endpoint"`
-	JaegerUser          string   `toml:"jaeger_user"`
-	JaegerPassword      string   `toml:"jaeger_password"`
-	SandboxBindMounts   []string `toml:"sandbox_bind_mounts"`
-	Experimental        []string `toml:"experimental"`
-	Debug               bool     `toml:"enable_debug"`
-	Tracing             bool     `toml:"enable_tracing"`
-	DisableNewNetNs     bool     `toml:"disable_new_netns"`
-	DisableGuestSeccomp bool     `toml:"disable_guest_seccomp"`
-	SandboxCgroupOnly   bool     `toml:"sandbox_cgroup_only"`
-	EnablePprof         bool     `toml:"enable_pprof"`
+	InterNetworkModel    string   `toml:"internetworking_model"`
+	JaegerEndpoint       string   `toml:"jaeger_endpoint"`
+	JaegerUser           string   `toml:"jaeger_user"`
+	JaegerPassword       string   `toml:"jaeger_password"`
+	AuditLogDir          string   `toml:"audit_log_dir"`
+	AuditLogSink         string   `toml:"audit_log_sink"`
+	MemoryEventsSink     string   `toml:"memory_events_sink"`
+	ExitReportSink       string   `toml:"exit_report_sink"`
+	EphemeralDiskBackend string   `toml:"ephemeral_disk_backend"`
+	EphemeralDiskVG      string   `toml:"ephemeral_disk_volume_group"`
+	EphemeralDiskDir     string   `toml:"ephemeral_disk_dir"`
+	RootfsDedupDir       string   `toml:"rootfs_dedup_dir"`
+	SandboxBindMounts    []string `toml:"sandbox_bind_mounts"`
+	DevicePluginClasses  []string `toml:"device_plugin_classes"`
+	Experimental         []string `toml:"experimental"`
+	ShimMetricsGroups    []string `toml:"shim_metrics_groups"`
+	ShimLogFormat        string   `toml:"shim_log_format"`
+	FeatureGates         string   `toml:"feature_gates"`
+	ManagementTokenFile  string   `toml:"management_token_file"`
+	CPUQuotaPolicy       string   `toml:"cpu_quota_policy"`
+	Debug                bool     `toml:"enable_debug"`
+	Tracing              bool     `toml:"enable_tracing"`
+	DisableNewNetNs      bool     `toml:"disable_new_netns"`
+	DisableGuestSeccomp  bool     `toml:"disable_guest_seccomp"`
+	GuestOCIHooks        bool     `toml:"guest_oci_hooks"`
+	HostContainerHooks   bool     `toml:"host_container_oci_hooks"`
+	SandboxCgroupOnly    bool     `toml:"sandbox_cgroup_only"`
+	SharePidNs           bool     `toml:"share_pid_ns"`
+	SandboxesPerShim     uint32   `toml:"sandboxes_per_shim"`
+	ContainerParallelism uint32   `toml:"container_parallelism"`
+	EphemeralDiskSizeMB  uint32   `toml:"ephemeral_disk_size_mb"`
+	EphemeralDiskMaxMB   uint32   `toml:"ephemeral_disk_max_size_mb"`
+	EnablePprof          bool     `toml:"enable_pprof"`
+	EnableAuditLog       bool     `toml:"enable_audit_log"`
+	EphemeralDiskEncrypt bool     `toml:"ephemeral_disk_encryption"`
+	RootfsDedup          bool     `toml:"rootfs_dedup"`
+	DeviceReuseTimeout   uint32   `toml:"device_reuse_timeout"`
+	InitData             string   `toml:"initdata"`
+	InitDataCertificates []string `toml:"initdata_certificates"`
+	InitDataHTTPProxy    string   `toml:"initdata_http_proxy"`
+	InitDataHTTPSProxy   string   `toml:"initdata_https_proxy"`
+	InitDataNoProxy      string   `toml:"initdata_no_proxy"`
+	AdmissionCheck       string   `toml:"admission_check"`
+	AdmissionOverhead    uint32   `toml:"admission_memory_overhead"`
+	GuestSwapSizeMB      uint32   `toml:"guest_swap_size_mb"`
+	GuestSwapMaxSizeMB   uint32   `toml:"guest_swap_max_size_mb"`
+	GuestSwappiness      uint32   `toml:"guest_swappiness"`
+	GuestSwapDir         string   `toml:"guest_swap_dir"`
+	SharedMemoryValid    []string `toml:"shared_memory_valid_regions"`
+	SharedMemoryRegionMB uint32   `toml:"shared_memory_max_region_size_mb"`
+	SharedMemoryMaxMB    uint32   `toml:"shared_memory_max_size_mb"`
 }
 
 type agent struct {
 	TraceMode           string   `toml:"trace_mode"`
 	TraceType           string   `toml:"trace_type"`
 	KernelModules       []string `toml:"kernel_modules"`
+	MetricsGroups       []string `toml:"metrics_groups"`
+	PolicyFile          string   `toml:"policy_file"`
+	AllowedAPIs         []string `toml:"allowed_apis"`
 	Debug               bool     `toml:"enable_debug"`
 	Tracing             bool     `toml:"enable_tracing"`
 	DebugConsoleEnabled bool     `toml:"debug_console_enabled"`
 	DialTimeout         uint32   `toml:"dial_timeout"`
+	DialBackoffInitial  uint32   `toml:"dial_backoff_initial_ms"`
+	DialBackoffMax      uint32   `toml:"dial_backoff_max_ms"`
 }
 
 type netmon struct {
@@ -226,6 +324,33 @@ func (h hypervisor) image() (string, error) {
 	return ResolvePath(p)
 }
 
+func (h hypervisor) rootfsDisk() (string, error) {
+	if h.RootfsDisk == "" {
+		return "", nil
+	}
+
+	return ResolvePath(h.RootfsDisk)
+}
+
+func (h hypervisor) rootfsDiskFormat() (string, error) {
+	switch h.RootfsDiskFormat {
+	case "":
+		return "raw", nil
+	case "raw", "qcow2":
+		return h.RootfsDiskFormat, nil
+	}
+
+	return "", fmt.Errorf("Invalid rootfs disk format %q, must be raw or qcow2", h.RootfsDiskFormat)
+}
+
+func (h hypervisor) rootfsDiskOverlayDir() string {
+	if h.RootfsDiskOverlayDir == "" {
+		return defaultRootfsDiskOverlayDir
+	}
+
+	return h.RootfsDiskOverlayDir
+}
+
 func (h hypervisor) firmware() (string, error) {
 	p := h.Firmware
 
@@ -424,6 +549,18 @@ func (h hypervisor) msize9p() uint32 {
 	return h.Msize9p
 }
 
+func (h hypervisor) guestNUMANodes() []vc.NUMANode {
+	var nodes []vc.NUMANode
+	for _, n := range h.NUMANodes {
+		nodes = append(nodes, vc.NUMANode{
+			VCPUs:      n.VCPUs,
+			MemorySize: n.MemorySize,
+		})
+	}
+
+	return nodes
+}
+
 func (h hypervisor) guestHookPath() string {
 	if h.GuestHookPath == "" {
 		return defaultGuestHookPath
@@ -479,6 +616,14 @@ func (a agent) dialTimout() uint32 {
 	return a.DialTimeout
 }
 
+func (a agent) dialBackoffInitial() uint32 {
+	return a.DialBackoffInitial
+}
+
+func (a agent) dialBackoffMax() uint32 {
+	return a.DialBackoffMax
+}
+
 func (a agent) debug() bool {
 	return a.Debug
 }
@@ -499,6 +644,22 @@ func (a agent) kernelModules() []string {
 	return a.KernelModules
 }
 
+func (a agent) metricsGroups() []string {
+	return a.MetricsGroups
+}
+
+func (a agent) policyFile() string {
+	return a.PolicyFile
+}
+
+func (a agent) allowedAPIs() []string {
+	// an empty list allows all the APIs
+	if len(a.AllowedAPIs) == 0 {
+		return nil
+	}
+	return a.AllowedAPIs
+}
+
 func (n netmon) enable() bool {
 	return n.Enable
 }
@@ -569,6 +730,7 @@ func newFirecrackerHypervisorConfig(h hypervisor) (vc.HypervisorConfig, error) {
 		EntropySourceList:     h.EntropySourceList,
 		DefaultBridges:        h.defaultBridges(),
 		DisableBlockDeviceUse: h.DisableBlockDeviceUse,
+		LazyBlockDeviceAttach: h.LazyBlockDeviceAttach,
 		HugePages:             h.HugePages,
 		Mlock:                 !h.Swap,
 		Debug:                 h.Debug,
@@ -594,24 +756,40 @@ func newQemuHypervisorConfig(h hypervisor) (vc.HypervisorConfig, error) {
 		return vc.HypervisorConfig{}, err
 	}
 
-	initrd, image, err := h.getInitrdAndImage()
+	rootfsDisk, err := h.rootfsDisk()
 	if err != nil {
 		return vc.HypervisorConfig{}, err
 	}
 
-	pflashes, err := h.PFlash()
+	rootfsDiskFormat, err := h.rootfsDiskFormat()
 	if err != nil {
 		return vc.HypervisorConfig{}, err
 	}
 
-	if image != "" && initrd != "" {
-		return vc.HypervisorConfig{},
-			errors.New("having both an image and an initrd defined in the configuration file is not supported")
+	var initrd, image string
+
+	// The guest image and initrd are not used when
+	// the guest boots from a rootfs disk.
+	if rootfsDisk == "" {
+		initrd, image, err = h.getInitrdAndImage()
+		if err != nil {
+			return vc.HypervisorConfig{}, err
+		}
+
+		if image != "" && initrd != "" {
+			return vc.HypervisorConfig{},
+				errors.New("having both an image and an initrd defined in the configuration file is not supported")
+		}
+
+		if image == "" && initrd == "" {
+			return vc.HypervisorConfig{},
+				errors.New("either image or initrd must be defined in the configuration file")
+		}
 	}
 
-	if image == "" && initrd == "" {
-		return vc.HypervisorConfig{},
-			errors.New("either image or initrd must be defined in the configuration file")
+	pflashes, err := h.PFlash()
+	if err != nil {
+		return vc.HypervisorConfig{}, err
 	}
 
 	firmware, err := h.firmware()
@@ -655,62 +833,87 @@ func newQemuHypervisorConfig(h hypervisor) (vc.HypervisorConfig, error) {
 	txRateLimiterMaxRate := h.getTxRateLimiterCfg()
 
 	return vc.HypervisorConfig{
-		HypervisorPath:          hypervisor,
-		HypervisorPathList:      h.HypervisorPathList,
-		KernelPath:              kernel,
-		InitrdPath:              initrd,
-		ImagePath:               image,
-		FirmwarePath:            firmware,
-		PFlash:                  pflashes,
-		MachineAccelerators:     machineAccelerators,
-		CPUFeatures:             cpuFeatures,
-		KernelParams:            vc.DeserializeParams(strings.Fields(kernelParams)),
-		HypervisorMachineType:   machineType,
-		NumVCPUs:                h.defaultVCPUs(),
-		DefaultMaxVCPUs:         h.defaultMaxVCPUs(),
-		MemorySize:              h.defaultMemSz(),
-		MemSlots:                h.defaultMemSlots(),
-		MemOffset:               h.defaultMemOffset(),
-		VirtioMem:               h.VirtioMem,
-		EntropySource:           h.GetEntropySource(),
-		EntropySourceList:       h.EntropySourceList,
-		DefaultBridges:          h.defaultBridges(),
-		DisableBlockDeviceUse:   h.DisableBlockDeviceUse,
-		SharedFS:                sharedFS,
-		VirtioFSDaemon:          h.VirtioFSDaemon,
-		VirtioFSDaemonList:      h.VirtioFSDaemonList,
-		VirtioFSCacheSize:       h.VirtioFSCacheSize,
-		VirtioFSCache:           h.defaultVirtioFSCache(),
-		VirtioFSExtraArgs:       h.VirtioFSExtraArgs,
-		MemPrealloc:             h.MemPrealloc,
-		HugePages:               h.HugePages,
-		IOMMU:                   h.IOMMU,
-		IOMMUPlatform:           h.getIOMMUPlatform(),
-		FileBackedMemRootDir:    h.FileBackedMemRootDir,
-		FileBackedMemRootList:   h.FileBackedMemRootList,
-		Mlock:                   !h.Swap,
-		Debug:                   h.Debug,
-		DisableNestingChecks:    h.DisableNestingChecks,
-		BlockDeviceDriver:       blockDriver,
-		BlockDeviceCacheSet:     h.BlockDeviceCacheSet,
-		BlockDeviceCacheDirect:  h.BlockDeviceCacheDirect,
-		BlockDeviceCacheNoflush: h.BlockDeviceCacheNoflush,
-		EnableIOThreads:         h.EnableIOThreads,
-		Msize9p:                 h.msize9p(),
-		DisableImageNvdimm:      h.DisableImageNvdimm,
-		HotplugVFIOOnRootBus:    h.HotplugVFIOOnRootBus,
-		PCIeRootPort:            h.PCIeRootPort,
-		DisableVhostNet:         h.DisableVhostNet,
-		EnableVhostUserStore:    h.EnableVhostUserStore,
-		VhostUserStorePath:      h.vhostUserStorePath(),
-		VhostUserStorePathList:  h.VhostUserStorePathList,
-		GuestHookPath:           h.guestHookPath(),
-		RxRateLimiterMaxRate:    rxRateLimiterMaxRate,
-		TxRateLimiterMaxRate:    txRateLimiterMaxRate,
-		EnableAnnotations:       h.EnableAnnotations,
-		GuestMemoryDumpPath:     h.GuestMemoryDumpPath,
-		GuestMemoryDumpPaging:   h.GuestMemoryDumpPaging,
-		ConfidentialGuest:       h.ConfidentialGuest,
+		HypervisorPath:            hypervisor,
+		HypervisorPathList:        h.HypervisorPathList,
+		KernelPath:                kernel,
+		InitrdPath:                initrd,
+		ImagePath:                 image,
+		RootfsDiskPath:            rootfsDisk,
+		RootfsDiskFormat:          rootfsDiskFormat,
+		RootfsDiskOverlayDir:      h.rootfsDiskOverlayDir(),
+		RootfsDiskList:            h.RootfsDiskList,
+		FirmwarePath:              firmware,
+		PFlash:                    pflashes,
+		MachineAccelerators:       machineAccelerators,
+		CPUFeatures:               cpuFeatures,
+		CPUModel:                  strings.TrimSpace(h.CPUModel),
+		KernelParams:              vc.DeserializeParams(strings.Fields(kernelParams)),
+		HypervisorMachineType:     machineType,
+		NumVCPUs:                  h.defaultVCPUs(),
+		DefaultMaxVCPUs:           h.defaultMaxVCPUs(),
+		MemorySize:                h.defaultMemSz(),
+		MemSlots:                  h.defaultMemSlots(),
+		MemOffset:                 h.defaultMemOffset(),
+		VirtioMem:                 h.VirtioMem,
+		EntropySource:             h.GetEntropySource(),
+		EntropySourceList:         h.EntropySourceList,
+		DefaultBridges:            h.defaultBridges(),
+		DisableBlockDeviceUse:     h.DisableBlockDeviceUse,
+		LazyBlockDeviceAttach:     h.LazyBlockDeviceAttach,
+		SharedFS:                  sharedFS,
+		VirtioFSDaemon:            h.VirtioFSDaemon,
+		VirtioFSDaemonList:        h.VirtioFSDaemonList,
+		VirtioFSCacheSize:         h.VirtioFSCacheSize,
+		VirtioFSCache:             h.defaultVirtioFSCache(),
+		VirtioFSExtraArgs:         h.VirtioFSExtraArgs,
+		VirtioFSDaemonIDs:         h.VirtioFSDaemonIDs,
+		MemPrealloc:               h.MemPrealloc,
+		HugePages:                 h.HugePages,
+		IOMMU:                     h.IOMMU,
+		IOMMUPlatform:             h.getIOMMUPlatform(),
+		FileBackedMemRootDir:      h.FileBackedMemRootDir,
+		FileBackedMemRootList:     h.FileBackedMemRootList,
+		Mlock:                     !h.Swap,
+		Debug:                     h.Debug,
+		DisableNestingChecks:      h.DisableNestingChecks,
+		BlockDeviceDriver:         blockDriver,
+		BlockDeviceCacheSet:       h.BlockDeviceCacheSet,
+		BlockDeviceCacheDirect:    h.BlockDeviceCacheDirect,
+		BlockDeviceCacheNoflush:   h.BlockDeviceCacheNoflush,
+		EnableIOThreads:           h.EnableIOThreads,
+		Msize9p:                   h.msize9p(),
+		DisableImageNvdimm:        h.DisableImageNvdimm,
+		GICVersion:                h.GICVersion,
+		DisableITS:                h.DisableITS,
+		SMTMode:                   h.SMTMode,
+		MMUMode:                   h.MMUMode,
+		MemoryBlockSizeMB:         h.MemoryBlockSizeMB,
+		SEHostKeyDocuments:        h.SEHostKeyDocuments,
+		HotplugVFIOOnRootBus:      h.HotplugVFIOOnRootBus,
+		PCIeRootPort:              h.PCIeRootPort,
+		DisableVhostNet:           h.DisableVhostNet,
+		EnableVhostUserStore:      h.EnableVhostUserStore,
+		VhostUserStorePath:        h.vhostUserStorePath(),
+		VhostUserStorePathList:    h.VhostUserStorePathList,
+		VhostUserSocketList:       h.VhostUserSocketList,
+		GuestHookPath:             h.guestHookPath(),
+		RxRateLimiterMaxRate:      rxRateLimiterMaxRate,
+		TxRateLimiterMaxRate:      txRateLimiterMaxRate,
+		EnableAnnotations:         h.EnableAnnotations,
+		GuestMemoryDumpPath:       h.GuestMemoryDumpPath,
+		GuestMemoryDumpPaging:     h.GuestMemoryDumpPaging,
+		ConfidentialGuest:         h.ConfidentialGuest,
+		VMMSandboxing:             h.VMMSandboxing,
+		VMMSandboxingSyscalls:     h.VMMSandboxingSyscalls,
+		VMMSandboxingCapabilities: h.VMMSandboxingCapabilities,
+		HypervisorIDs:             h.HypervisorIDs,
+		VMMOOMScoreAdj:            h.VMMOOMScoreAdj,
+		VMMNice:                   h.VMMNice,
+		VMMIOClass:                h.VMMIOClass,
+		VMMIOPriority:             h.VMMIOPriority,
+		VCPURealtimePriority:      h.VCPURealtimePriority,
+		SELinuxProcessLabel:       h.SELinuxLabel,
+		SELinuxCategories:         h.SELinuxCategories,
 	}, nil
 }
 
@@ -811,6 +1014,7 @@ func newClhHypervisorConfig(h hypervisor) (vc.HypervisorConfig, error) {
 	}
 
 	machineAccelerators := h.machineAccelerators()
+	cpuFeatures := h.cpuFeatures()
 	kernelParams := h.kernelParams()
 	machineType := h.machineType()
 
@@ -827,50 +1031,66 @@ func newClhHypervisorConfig(h hypervisor) (vc.HypervisorConfig, error) {
 	}
 
 	return vc.HypervisorConfig{
-		HypervisorPath:          hypervisor,
-		HypervisorPathList:      h.HypervisorPathList,
-		KernelPath:              kernel,
-		InitrdPath:              initrd,
-		ImagePath:               image,
-		FirmwarePath:            firmware,
-		MachineAccelerators:     machineAccelerators,
-		KernelParams:            vc.DeserializeParams(strings.Fields(kernelParams)),
-		HypervisorMachineType:   machineType,
-		NumVCPUs:                h.defaultVCPUs(),
-		DefaultMaxVCPUs:         h.defaultMaxVCPUs(),
-		MemorySize:              h.defaultMemSz(),
-		MemSlots:                h.defaultMemSlots(),
-		MemOffset:               h.defaultMemOffset(),
-		VirtioMem:               h.VirtioMem,
-		EntropySource:           h.GetEntropySource(),
-		EntropySourceList:       h.EntropySourceList,
-		DefaultBridges:          h.defaultBridges(),
-		DisableBlockDeviceUse:   h.DisableBlockDeviceUse,
-		SharedFS:                sharedFS,
-		VirtioFSDaemon:          h.VirtioFSDaemon,
-		VirtioFSDaemonList:      h.VirtioFSDaemonList,
-		VirtioFSCacheSize:       h.VirtioFSCacheSize,
-		VirtioFSCache:           h.VirtioFSCache,
-		MemPrealloc:             h.MemPrealloc,
-		HugePages:               h.HugePages,
-		FileBackedMemRootDir:    h.FileBackedMemRootDir,
-		FileBackedMemRootList:   h.FileBackedMemRootList,
-		Mlock:                   !h.Swap,
-		Debug:                   h.Debug,
-		DisableNestingChecks:    h.DisableNestingChecks,
-		BlockDeviceDriver:       blockDriver,
-		BlockDeviceCacheSet:     h.BlockDeviceCacheSet,
-		BlockDeviceCacheDirect:  h.BlockDeviceCacheDirect,
-		BlockDeviceCacheNoflush: h.BlockDeviceCacheNoflush,
-		EnableIOThreads:         h.EnableIOThreads,
-		Msize9p:                 h.msize9p(),
-		HotplugVFIOOnRootBus:    h.HotplugVFIOOnRootBus,
-		PCIeRootPort:            h.PCIeRootPort,
-		DisableVhostNet:         true,
-		GuestHookPath:           h.guestHookPath(),
-		VirtioFSExtraArgs:       h.VirtioFSExtraArgs,
-		SGXEPCSize:              defaultSGXEPCSize,
-		EnableAnnotations:       h.EnableAnnotations,
+		HypervisorPath:            hypervisor,
+		HypervisorPathList:        h.HypervisorPathList,
+		KernelPath:                kernel,
+		InitrdPath:                initrd,
+		ImagePath:                 image,
+		FirmwarePath:              firmware,
+		MachineAccelerators:       machineAccelerators,
+		CPUFeatures:               cpuFeatures,
+		CPUModel:                  strings.TrimSpace(h.CPUModel),
+		KernelParams:              vc.DeserializeParams(strings.Fields(kernelParams)),
+		HypervisorMachineType:     machineType,
+		NumVCPUs:                  h.defaultVCPUs(),
+		DefaultMaxVCPUs:           h.defaultMaxVCPUs(),
+		MemorySize:                h.defaultMemSz(),
+		MemSlots:                  h.defaultMemSlots(),
+		MemOffset:                 h.defaultMemOffset(),
+		VirtioMem:                 h.VirtioMem,
+		EntropySource:             h.GetEntropySource(),
+		EntropySourceList:         h.EntropySourceList,
+		DefaultBridges:            h.defaultBridges(),
+		DisableBlockDeviceUse:     h.DisableBlockDeviceUse,
+		LazyBlockDeviceAttach:     h.LazyBlockDeviceAttach,
+		SharedFS:                  sharedFS,
+		VirtioFSDaemon:            h.VirtioFSDaemon,
+		VirtioFSDaemonList:        h.VirtioFSDaemonList,
+		VirtioFSCacheSize:         h.VirtioFSCacheSize,
+		VirtioFSCache:             h.VirtioFSCache,
+		MemPrealloc:               h.MemPrealloc,
+		HugePages:                 h.HugePages,
+		FileBackedMemRootDir:      h.FileBackedMemRootDir,
+		FileBackedMemRootList:     h.FileBackedMemRootList,
+		Mlock:                     !h.Swap,
+		Debug:                     h.Debug,
+		DisableNestingChecks:      h.DisableNestingChecks,
+		BlockDeviceDriver:         blockDriver,
+		BlockDeviceCacheSet:       h.BlockDeviceCacheSet,
+		BlockDeviceCacheDirect:    h.BlockDeviceCacheDirect,
+		BlockDeviceCacheNoflush:   h.BlockDeviceCacheNoflush,
+		EnableIOThreads:           h.EnableIOThreads,
+		Msize9p:                   h.msize9p(),
+		HotplugVFIOOnRootBus:      h.HotplugVFIOOnRootBus,
+		PCIeRootPort:              h.PCIeRootPort,
+		DisableVhostNet:           true,
+		GuestHookPath:             h.guestHookPath(),
+		VirtioFSExtraArgs:         h.VirtioFSExtraArgs,
+		VirtioFSDaemonIDs:         h.VirtioFSDaemonIDs,
+		SGXEPCSize:                defaultSGXEPCSize,
+		NUMANodes:                 h.guestNUMANodes(),
+		EnableAnnotations:         h.EnableAnnotations,
+		VMMSandboxing:             h.VMMSandboxing,
+		VMMSandboxingSyscalls:     h.VMMSandboxingSyscalls,
+		VMMSandboxingCapabilities: h.VMMSandboxingCapabilities,
+		HypervisorIDs:             h.HypervisorIDs,
+		VMMOOMScoreAdj:            h.VMMOOMScoreAdj,
+		VMMNice:                   h.VMMNice,
+		VMMIOClass:                h.VMMIOClass,
+		VMMIOPriority:             h.VMMIOPriority,
+		VCPURealtimePriority:      h.VCPURealtimePriority,
+		SELinuxProcessLabel:       h.SELinuxLabel,
+		SELinuxCategories:         h.SELinuxCategories,
 	}, nil
 }
 
@@ -921,6 +1141,11 @@ func updateRuntimeConfigHypervisor(configPath string, tomlConf tomlConfig, confi
 
 func updateRuntimeConfigAgent(configPath string, tomlConf tomlConfig, config *oci.RuntimeConfig) error {
 	for _, agent := range tomlConf.Agent {
+		metricsGroups, err := vc.ParseMetricsGroups(agent.metricsGroups(), vc.AgentMetricsGroups)
+		if err != nil {
+			return fmt.Errorf("invalid metrics_groups in %s: %v", configPath, err)
+		}
+
 		config.AgentConfig = vc.KataAgentConfig{
 			LongLiveConn:       true,
 			Debug:              agent.debug(),
@@ -930,6 +1155,11 @@ func updateRuntimeConfigAgent(configPath string, tomlConf tomlConfig, config *oc
 			KernelModules:      agent.kernelModules(),
 			EnableDebugConsole: agent.debugConsoleEnabled(),
 			DialTimeout:        agent.dialTimout(),
+			DialBackoffInitial: agent.dialBackoffInitial(),
+			DialBackoffMax:     agent.dialBackoffMax(),
+			MetricsGroups:      metricsGroups,
+			PolicyFile:         agent.policyFile(),
+			AllowedAPIs:        agent.allowedAPIs(),
 		}
 	}
 
@@ -1139,13 +1369,83 @@ func LoadConfiguration(configPath string, ignoreLogging bool) (resolvedConfigPat
 	}
 
 	config.DisableGuestSeccomp = tomlConf.Runtime.DisableGuestSeccomp
+	config.GuestOCIHooks = tomlConf.Runtime.GuestOCIHooks
+	config.HostContainerOCIHooks = tomlConf.Runtime.HostContainerHooks
 
 	config.SandboxCgroupOnly = tomlConf.Runtime.SandboxCgroupOnly
+	config.SharePidNs = tomlConf.Runtime.SharePidNs
+	config.SandboxesPerShim = tomlConf.Runtime.SandboxesPerShim
+	config.ContainerParallelism = tomlConf.Runtime.ContainerParallelism
+	if config.ShimMetricsGroups, err = vc.ParseMetricsGroups(tomlConf.Runtime.ShimMetricsGroups, ShimMetricsGroups); err != nil {
+		return "", config, fmt.Errorf("invalid shim_metrics_groups in %s: %v", resolved, err)
+	}
+	config.ShimLogFormat = tomlConf.Runtime.ShimLogFormat
+	config.MemoryEventsSink = tomlConf.Runtime.MemoryEventsSink
+	config.ExitReportSink = tomlConf.Runtime.ExitReportSink
+	config.ManagementTokenFile = tomlConf.Runtime.ManagementTokenFile
+	config.CPUQuotaPolicy = vc.CPUQuotaPolicy(tomlConf.Runtime.CPUQuotaPolicy)
 	config.DisableNewNetNs = tomlConf.Runtime.DisableNewNetNs
 	config.EnablePprof = tomlConf.Runtime.EnablePprof
+	config.RootfsDedup = tomlConf.Runtime.RootfsDedup
+	config.RootfsDedupDir = tomlConf.Runtime.RootfsDedupDir
+	if config.RootfsDedupDir == "" {
+		config.RootfsDedupDir = defaultRootfsDedupDir
+	}
+	config.DeviceReuseTimeout = time.Duration(tomlConf.Runtime.DeviceReuseTimeout) * time.Second
+	config.DevicePluginClasses = tomlConf.Runtime.DevicePluginClasses
 	config.JaegerEndpoint = tomlConf.Runtime.JaegerEndpoint
 	config.JaegerUser = tomlConf.Runtime.JaegerUser
 	config.JaegerPassword = tomlConf.Runtime.JaegerPassword
+	config.AuditConfig = vc.AuditConfig{
+		Enable: tomlConf.Runtime.EnableAuditLog,
+		Dir:    tomlConf.Runtime.AuditLogDir,
+		Sink:   tomlConf.Runtime.AuditLogSink,
+	}
+	config.EphemeralDiskConfig = vc.EphemeralDiskConfig{
+		Backend:     tomlConf.Runtime.EphemeralDiskBackend,
+		VolumeGroup: tomlConf.Runtime.EphemeralDiskVG,
+		Dir:         tomlConf.Runtime.EphemeralDiskDir,
+		SizeMB:      tomlConf.Runtime.EphemeralDiskSizeMB,
+		MaxSizeMB:   tomlConf.Runtime.EphemeralDiskMaxMB,
+		Encrypt:     tomlConf.Runtime.EphemeralDiskEncrypt,
+	}
+	if config.EphemeralDiskConfig.Dir == "" {
+		config.EphemeralDiskConfig.Dir = defaultEphemeralDiskDir
+	}
+	if err = config.EphemeralDiskConfig.Validate(); err != nil {
+		return "", config, err
+	}
+	config.InitDataConfig = vc.InitDataConfig{
+		Method:       tomlConf.Runtime.InitData,
+		Certificates: tomlConf.Runtime.InitDataCertificates,
+		HTTPProxy:    tomlConf.Runtime.InitDataHTTPProxy,
+		HTTPSProxy:   tomlConf.Runtime.InitDataHTTPSProxy,
+		NoProxy:      tomlConf.Runtime.InitDataNoProxy,
+	}
+	if err = config.InitDataConfig.Validate(config.HypervisorType); err != nil {
+		return "", config, err
+	}
+	config.AdmissionConfig = vc.AdmissionConfig{
+		Mode:              tomlConf.Runtime.AdmissionCheck,
+		MemoryOverheadMiB: tomlConf.Runtime.AdmissionOverhead,
+	}
+	if err = config.AdmissionConfig.Validate(); err != nil {
+		return "", config, err
+	}
+	config.GuestSwapConfig = vc.GuestSwapConfig{
+		SizeMB:     tomlConf.Runtime.GuestSwapSizeMB,
+		MaxSizeMB:  tomlConf.Runtime.GuestSwapMaxSizeMB,
+		Swappiness: tomlConf.Runtime.GuestSwappiness,
+		Dir:        tomlConf.Runtime.GuestSwapDir,
+	}
+	if err = config.GuestSwapConfig.Validate(); err != nil {
+		return "", config, err
+	}
+	config.SharedMemoryConfig = vc.SharedMemoryConfig{
+		ValidRegions:    tomlConf.Runtime.SharedMemoryValid,
+		MaxRegionSizeMB: tomlConf.Runtime.SharedMemoryRegionMB,
+		MaxSizeMB:       tomlConf.Runtime.SharedMemoryMaxMB,
+	}
 	for _, f := range tomlConf.Runtime.Experimental {
 		feature := exp.Get(f)
 		if feature == nil {
@@ -1154,6 +1454,11 @@ func LoadConfiguration(configPath string, ignoreLogging bool) (resolvedConfigPat
 		config.Experimental = append(config.Experimental, *feature)
 	}
 
+	if err = updateFeatureGates(tomlConf.Runtime.FeatureGates); err != nil {
+		return "", config, err
+	}
+	config.FeatureGates = featuregate.All()
+
 	if err = validateBindMounts(tomlConf.Runtime.SandboxBindMounts); err != nil {
 		return "", config, err
 	}
@@ -1166,6 +1471,27 @@ func LoadConfiguration(configPath string, ignoreLogging bool) (resolvedConfigPat
 	return resolved, config, nil
 }
 
+// updateFeatureGates sets the feature gates from the feature_gates option,
+// the ones set in the environment taking precedence.
+func updateFeatureGates(value string) error {
+	gates, err := featuregate.Parse(value)
+	if err != nil {
+		return fmt.Errorf("Invalid feature_gates: %v", err)
+	}
+
+	envGates, err := featuregate.Parse(os.Getenv(featuregate.EnvVar))
+	if err != nil {
+		return fmt.Errorf("Invalid %s: %v", featuregate.EnvVar, err)
+	}
+
+	for name, on := range envGates {
+		gates[name] = on
+	}
+	featuregate.Set(gates)
+
+	return nil
+}
+
 // Verify that bind mounts exist
 func validateBindMounts(mounts []string) error {
 	if len(mounts) == 0 {
@@ -1175,7 +1501,8 @@ func validateBindMounts(mounts []string) error {
 	bases := make(map[string]struct{})
 
 	for _, m := range mounts {
-		path, err := ResolvePath(m)
+		path, _ := vc.ParseSandboxBindMount(m)
+		path, err := ResolvePath(path)
 		if err != nil {
 			return fmt.Errorf("sandbox-bindmounts: Failed to resolve path: %s: %v", m, err)
 		}
@@ -1231,13 +1558,43 @@ func checkConfig(config oci.RuntimeConfig) error {
 		return err
 	}
 
+	if config.HypervisorType == vc.QemuHypervisor {
+		if err := vc.CheckQemuArchConfig(config.HypervisorConfig, goruntime.GOARCH); err != nil {
+			return err
+		}
+	}
+
 	if err := checkFactoryConfig(config); err != nil {
 		return err
 	}
 
+	if err := checkShimLogFormat(config.ShimLogFormat); err != nil {
+		return err
+	}
+
+	if err := config.CPUQuotaPolicy.Valid(); err != nil {
+		return err
+	}
+
+	// each sandbox would move the shim shared with the other sandboxes
+	// into its own cgroup
+	if config.SandboxesPerShim > 1 && config.SandboxCgroupOnly {
+		return fmt.Errorf("config sandboxes_per_shim conflicts with sandbox_cgroup_only")
+	}
+
 	return nil
 }
 
+// checkShimLogFormat checks the format of the shim log is supported.
+func checkShimLogFormat(format string) error {
+	switch format {
+	case "", ShimLogFormatText, ShimLogFormatJSON:
+		return nil
+	}
+
+	return fmt.Errorf("invalid shim_log_format %q, valid formats are %q and %q", format, ShimLogFormatText, ShimLogFormatJSON)
+}
+
 // checkNetNsConfig performs sanity checks on disable_new_netns config.
 // Because it is an expert option and conflicts with some other common configs.
 func checkNetNsConfig(config oci.RuntimeConfig) error {
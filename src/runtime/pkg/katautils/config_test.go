@@ -19,6 +19,7 @@ import (
 	"syscall"
 	"testing"
 
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/katautils/featuregate"
 	ktu "github.com/kata-containers/kata-containers/src/runtime/pkg/katatestutils"
 	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/oci"
@@ -205,6 +206,7 @@ func createAllRuntimeConfigFiles(dir, hypervisor string) (config testRuntimeConf
 		JaegerPassword:  jaegerPassword,
 
 		FactoryConfig: factoryConfig,
+		FeatureGates:  featuregate.All(),
 	}
 
 	err = SetKernelParams(&runtimeConfig)
@@ -617,6 +619,7 @@ func TestMinimalRuntimeConfig(t *testing.T) {
 		NetmonConfig: expectedNetmonConfig,
 
 		FactoryConfig: expectedFactoryConfig,
+		FeatureGates:  featuregate.All(),
 	}
 	err = SetKernelParams(&expectedConfig)
 	if err != nil {
@@ -883,6 +886,10 @@ func TestNewClhHypervisorConfig(t *testing.T) {
 		Image:          imagePath,
 		VirtioFSDaemon: virtioFsDaemon,
 		VirtioFSCache:  "always",
+		NUMANodes: []numaNode{
+			{VCPUs: []uint32{0}, MemorySize: 1024},
+			{VCPUs: []uint32{1}, MemorySize: 1024},
+		},
 	}
 	config, err := newClhHypervisorConfig(hypervisor)
 	if err != nil {
@@ -913,6 +920,10 @@ func TestNewClhHypervisorConfig(t *testing.T) {
 		t.Errorf("Expected VirtioFSCache %v, got %v", true, config.VirtioFSCache)
 	}
 
+	assert.Equal([]vc.NUMANode{
+		{VCPUs: []uint32{0}, MemorySize: 1024},
+		{VCPUs: []uint32{1}, MemorySize: 1024},
+	}, config.NUMANodes)
 }
 
 func TestHypervisorDefaults(t *testing.T) {
@@ -1571,6 +1582,45 @@ func TestCheckNetNsConfig(t *testing.T) {
 	assert.Error(err)
 }
 
+func TestCheckConfigQemuArch(t *testing.T) {
+	assert := assert.New(t)
+
+	// a machine type of another architecture
+	machineType := vc.QemuPseries
+	if goruntime.GOARCH == "ppc64le" {
+		machineType = vc.QemuQ35
+	}
+
+	config := oci.RuntimeConfig{
+		HypervisorType: vc.QemuHypervisor,
+		HypervisorConfig: vc.HypervisorConfig{
+			HypervisorMachineType: machineType,
+			MemorySize:            defaultMemSize,
+		},
+	}
+	assert.Error(checkConfig(config))
+
+	// only checked for qemu
+	config.HypervisorType = vc.ClhHypervisor
+	assert.NoError(checkConfig(config))
+}
+
+func TestCheckConfigShimGroups(t *testing.T) {
+	assert := assert.New(t)
+
+	config := oci.RuntimeConfig{
+		HypervisorConfig: vc.HypervisorConfig{
+			MemorySize: defaultMemSize,
+		},
+		SandboxesPerShim:  2,
+		SandboxCgroupOnly: true,
+	}
+	assert.Error(checkConfig(config))
+
+	config.SandboxesPerShim = 1
+	assert.NoError(checkConfig(config))
+}
+
 func TestCheckFactoryConfig(t *testing.T) {
 	assert := assert.New(t)
 
@@ -1612,6 +1662,16 @@ func TestCheckFactoryConfig(t *testing.T) {
 	}
 }
 
+func TestCheckShimLogFormat(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, format := range []string{"", ShimLogFormatText, ShimLogFormatJSON} {
+		assert.NoError(checkShimLogFormat(format), "format %q", format)
+	}
+
+	assert.Error(checkShimLogFormat("logfmt"))
+}
+
 func TestValidateBindMounts(t *testing.T) {
 	assert := assert.New(t)
 
@@ -1650,6 +1710,9 @@ func TestValidateBindMounts(t *testing.T) {
 		{"non existent path with existing path", []string{unique, "/this/does/not/exist"}, true},
 		{"non existent path with duplicates", []string{duplicate1, duplicate2, "/this/does/not/exist"}, true},
 		{"no paths", []string{}, false},
+		{"read-only and read-write paths", []string{tmpdir1 + ":ro", tmpdir2 + ":rw"}, false},
+		{"flag of a non existent path", []string{"/this/does/not/exist:rw"}, true},
+		{"same base name with different flags", []string{duplicate1 + ":ro", duplicate2 + ":rw"}, true},
 	}
 	for i, d := range data {
 		err := validateBindMounts(d.mounts)
@@ -1660,3 +1723,28 @@ func TestValidateBindMounts(t *testing.T) {
 		}
 	}
 }
+
+func TestUpdateFeatureGates(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.NoError(featuregate.Register(featuregate.Gate{Name: "MockConfigGate", Description: "mock gate"}))
+	assert.NoError(featuregate.Register(featuregate.Gate{Name: "MockEnvGate", Description: "mock gate"}))
+	defer featuregate.Set(nil)
+
+	assert.NoError(updateFeatureGates("MockConfigGate=true"))
+	assert.True(featuregate.Enabled("MockConfigGate"))
+	assert.False(featuregate.Enabled("MockEnvGate"))
+
+	// the environment takes precedence over the configuration
+	os.Setenv(featuregate.EnvVar, "MockConfigGate=false,MockEnvGate=true")
+	defer os.Unsetenv(featuregate.EnvVar)
+
+	assert.NoError(updateFeatureGates("MockConfigGate=true"))
+	assert.False(featuregate.Enabled("MockConfigGate"))
+	assert.True(featuregate.Enabled("MockEnvGate"))
+
+	assert.Error(updateFeatureGates("MockUnknownGate=true"))
+
+	os.Setenv(featuregate.EnvVar, "MockEnvGate")
+	assert.Error(updateFeatureGates(""))
+}
@@ -59,12 +59,14 @@ func needSystemd(config vc.HypervisorConfig) bool {
 	return config.ImagePath != ""
 }
 
-// HandleFactory  set the factory
-func HandleFactory(ctx context.Context, vci vc.VC, runtimeConfig *oci.RuntimeConfig) {
-	if !runtimeConfig.FactoryConfig.Template && runtimeConfig.FactoryConfig.VMCacheNumber == 0 {
-		return
-	}
-	factoryConfig := vf.Config{
+// FactoryEnabled returns true if the VM template or the VMCache is enabled.
+func FactoryEnabled(runtimeConfig *oci.RuntimeConfig) bool {
+	return runtimeConfig.FactoryConfig.Template || runtimeConfig.FactoryConfig.VMCacheNumber > 0
+}
+
+// FactoryConfig returns the VM factory config used by the runtime.
+func FactoryConfig(runtimeConfig *oci.RuntimeConfig) vf.Config {
+	return vf.Config{
 		Template:        runtimeConfig.FactoryConfig.Template,
 		TemplatePath:    runtimeConfig.FactoryConfig.TemplatePath,
 		VMCache:         runtimeConfig.FactoryConfig.VMCacheNumber > 0,
@@ -75,6 +77,14 @@ func HandleFactory(ctx context.Context, vci vc.VC, runtimeConfig *oci.RuntimeCon
 			AgentConfig:      runtimeConfig.AgentConfig,
 		},
 	}
+}
+
+// HandleFactory  set the factory
+func HandleFactory(ctx context.Context, vci vc.VC, runtimeConfig *oci.RuntimeConfig) {
+	if !FactoryEnabled(runtimeConfig) {
+		return
+	}
+	factoryConfig := FactoryConfig(runtimeConfig)
 
 	kataUtilsLogger.WithField("factory", factoryConfig).Info("load vm factory")
 
@@ -152,9 +162,13 @@ func CreateSandbox(ctx context.Context, vci vc.VC, ociSpec specs.Spec, runtimeCo
 		}
 	}()
 
-	// Run pre-start OCI hooks.
+	// Run pre-start and create-runtime OCI hooks, before the VM is started.
+	hookCtx := WithHookSandbox(ctx, sandboxConfig.ID, 0)
 	err = EnterNetNS(sandboxConfig.NetworkConfig.NetNSPath, func() error {
-		return PreStartHooks(ctx, ociSpec, containerID, bundlePath)
+		if err := PreStartHooks(hookCtx, ociSpec, containerID, bundlePath); err != nil {
+			return err
+		}
+		return CreateRuntimeHooks(hookCtx, ociSpec, containerID, bundlePath)
 	})
 	if err != nil {
 		return nil, vc.Process{}, err
@@ -177,6 +191,33 @@ func CreateSandbox(ctx context.Context, vci vc.VC, ociSpec specs.Spec, runtimeCo
 	return sandbox, containers[0].Process(), nil
 }
 
+// PlanSandbox returns the plan of the sandbox of the OCI spec, resolved by a
+// dry run of its creation: neither the network namespace nor the sandbox are
+// created, and the OCI hooks are not run.
+func PlanSandbox(ctx context.Context, vci vc.VC, ociSpec specs.Spec, runtimeConfig oci.RuntimeConfig, containerID, bundlePath string) (*vc.SandboxPlan, error) {
+	span, ctx := katatrace.Trace(ctx, nil, "PlanSandbox", createTracingTags)
+	katatrace.AddTag(span, "container_id", containerID)
+	defer span.End()
+
+	sandboxConfig, err := oci.SandboxConfig(ociSpec, runtimeConfig, bundlePath, containerID, "", true, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkForFIPS(&sandboxConfig); err != nil {
+		return nil, err
+	}
+
+	sandboxConfig.DryRun = true
+
+	sandbox, err := vci.CreateSandbox(ctx, sandboxConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return sandbox.GetDryRunPlan(), nil
+}
+
 var procFIPS = "/proc/sys/crypto/fips_enabled"
 
 func checkForFIPS(sandboxConfig *vc.SandboxConfig) error {
@@ -244,9 +285,13 @@ func CreateContainer(ctx context.Context, sandbox vc.VCSandbox, ociSpec specs.Sp
 		return vc.Process{}, err
 	}
 
-	// Run pre-start OCI hooks.
+	// Run pre-start and create-runtime OCI hooks.
+	hookCtx := HookContext(ctx, sandbox)
 	err = EnterNetNS(sandbox.GetNetNs(), func() error {
-		return PreStartHooks(ctx, ociSpec, containerID, bundlePath)
+		if err := PreStartHooks(hookCtx, ociSpec, containerID, bundlePath); err != nil {
+			return err
+		}
+		return CreateRuntimeHooks(hookCtx, ociSpec, containerID, bundlePath)
 	})
 	if err != nil {
 		return vc.Process{}, err
@@ -330,6 +330,42 @@ func TestCreateSandboxFail(t *testing.T) {
 	assert.True(vcmock.IsMockError(err))
 }
 
+func TestPlanSandbox(t *testing.T) {
+	assert := assert.New(t)
+
+	tmpdir, err := ioutil.TempDir("", "")
+	assert.NoError(err)
+	defer os.RemoveAll(tmpdir)
+
+	runtimeConfig, err := newTestRuntimeConfig(tmpdir, testConsole, true)
+	assert.NoError(err)
+
+	bundlePath := filepath.Join(tmpdir, "bundle")
+	err = makeOCIBundle(bundlePath)
+	assert.NoError(err)
+
+	spec, err := compatoci.ParseConfigJSON(bundlePath)
+	assert.NoError(err)
+
+	plan := &vc.SandboxPlan{ID: testContainerID}
+	testingImpl.CreateSandboxFunc = func(ctx context.Context, sandboxConfig vc.SandboxConfig) (vc.VCSandbox, error) {
+		if !sandboxConfig.DryRun {
+			return nil, errors.New("not a dry run")
+		}
+		return &vcmock.Sandbox{
+			MockID:            sandboxConfig.ID,
+			GetDryRunPlanFunc: func() *vc.SandboxPlan { return plan },
+		}, nil
+	}
+	defer func() {
+		testingImpl.CreateSandboxFunc = nil
+	}()
+
+	result, err := PlanSandbox(context.Background(), testingImpl, spec, runtimeConfig, testContainerID, bundlePath)
+	assert.NoError(err)
+	assert.Equal(plan, result)
+}
+
 func TestCheckForFips(t *testing.T) {
 	assert := assert.New(t)
 
@@ -0,0 +1,145 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+// Package featuregate holds the feature gates of the runtime: the subsystems
+// shipped disabled (or enabled) by default, which can be switched per node
+// from the configuration file or the environment, without new binaries.
+//
+// The packages owning a subsystem register its gate on init, and consult it
+// with Enabled.
+package featuregate
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// EnvVar is the environment variable setting the feature gates, on top
+// of the feature_gates option of the configuration file.
+const EnvVar = "KATA_FEATURE_GATES"
+
+const nameRegStr = "^[A-Z][A-Za-z0-9]*$"
+
+// Gate is a feature gate.
+type Gate struct {
+	// Name is the name of the gate, in upper camel case, e.g. "GuestPull".
+	Name        string
+	Description string
+	// Default is the state of the gate when it is not set.
+	Default bool
+}
+
+var (
+	lock    sync.RWMutex
+	gates   = make(map[string]Gate)
+	enabled = make(map[string]bool)
+
+	nameReg = regexp.MustCompile(nameRegStr)
+)
+
+// Register registers a new feature gate.
+func Register(gate Gate) error {
+	if gate.Name == "" || gate.Description == "" {
+		return fmt.Errorf("feature gate must have valid name and description")
+	}
+	if !nameReg.MatchString(gate.Name) {
+		return fmt.Errorf("feature gate name must be in the format %q", nameRegStr)
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	if _, ok := gates[gate.Name]; ok {
+		return fmt.Errorf("feature gate %q had been registered before", gate.Name)
+	}
+	gates[gate.Name] = gate
+
+	return nil
+}
+
+// Parse parses a list of feature gates in the format
+// "GuestPull=true,VirtioMem=false", all of them must be registered.
+func Parse(value string) (map[string]bool, error) {
+	result := make(map[string]bool)
+
+	lock.RLock()
+	defer lock.RUnlock()
+
+	for _, s := range strings.Split(value, ",") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+
+		fields := strings.SplitN(s, "=", 2)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("invalid feature gate %q, expected <name>=<true|false>", s)
+		}
+
+		name := strings.TrimSpace(fields[0])
+		if _, ok := gates[name]; !ok {
+			return nil, fmt.Errorf("unknown feature gate %q", name)
+		}
+
+		on, err := strconv.ParseBool(strings.TrimSpace(fields[1]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid value of feature gate %q: %v", name, err)
+		}
+
+		result[name] = on
+	}
+
+	return result, nil
+}
+
+// Set sets the state of the feature gates, the others are reset
+// to their default.
+func Set(values map[string]bool) {
+	lock.Lock()
+	defer lock.Unlock()
+
+	enabled = make(map[string]bool)
+	for name, on := range values {
+		enabled[name] = on
+	}
+}
+
+// Enabled returns true if the feature gate is enabled, the unknown
+// gates are disabled.
+func Enabled(name string) bool {
+	lock.RLock()
+	defer lock.RUnlock()
+
+	gate, ok := gates[name]
+	if !ok {
+		return false
+	}
+
+	if on, ok := enabled[name]; ok {
+		return on
+	}
+
+	return gate.Default
+}
+
+// All returns the state of all the registered feature gates.
+func All() map[string]bool {
+	lock.RLock()
+	defer lock.RUnlock()
+
+	result := make(map[string]bool)
+	for name, gate := range gates {
+		on, ok := enabled[name]
+		if !ok {
+			on = gate.Default
+		}
+		result[name] = on
+	}
+
+	return result
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package featuregate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegister(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Error(Register(Gate{Name: "MockRegister"}))
+	assert.Error(Register(Gate{Name: "mock_register", Description: "mock gate"}))
+
+	assert.NoError(Register(Gate{Name: "MockRegister", Description: "mock gate"}))
+	assert.Error(Register(Gate{Name: "MockRegister", Description: "mock gate"}))
+}
+
+func TestFeatureGates(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.NoError(Register(Gate{Name: "MockOff", Description: "mock gate disabled by default"}))
+	assert.NoError(Register(Gate{Name: "MockOn", Description: "mock gate enabled by default", Default: true}))
+	defer Set(nil)
+
+	assert.False(Enabled("MockOff"))
+	assert.True(Enabled("MockOn"))
+	assert.False(Enabled("MockUnknown"))
+
+	values, err := Parse(" MockOff=true, MockOn=false,")
+	assert.NoError(err)
+	assert.Equal(map[string]bool{"MockOff": true, "MockOn": false}, values)
+
+	Set(values)
+	assert.True(Enabled("MockOff"))
+	assert.False(Enabled("MockOn"))
+	assert.False(All()["MockOn"])
+
+	// the gates not set are back to their default
+	Set(map[string]bool{"MockOn": false})
+	assert.False(Enabled("MockOff"))
+
+	values, err = Parse("")
+	assert.NoError(err)
+	assert.Empty(values)
+
+	for _, value := range []string{"MockOff", "MockOff=maybe", "MockUnknown=true"} {
+		_, err = Parse(value)
+		assert.Error(err, value)
+	}
+}
@@ -12,11 +12,15 @@ import (
 	"encoding/json"
 	"fmt"
 	"os/exec"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/kata-containers/kata-containers/src/runtime/pkg/katautils/katatrace"
+	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
 	"github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sirupsen/logrus"
 )
 
@@ -32,6 +36,122 @@ func hookLogger() *logrus.Entry {
 	return kataUtilsLogger.WithField("subsystem", "hook")
 }
 
+// HookSandboxIDEnv is the environment variable giving the sandbox ID to the
+// OCI hooks run on the host.
+const HookSandboxIDEnv = "KATA_SANDBOX_ID"
+
+// HookVMPidEnv is the environment variable giving the pid of the hypervisor
+// to the OCI hooks run on the host, once the VM is started.
+const HookVMPidEnv = "KATA_VM_PID"
+
+var (
+	hookDurations = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "kata_shim",
+		Name:      "oci_hook_duration_seconds",
+		Help:      "Duration of the OCI hooks run on the host.",
+		Buckets:   prometheus.ExponentialBuckets(0.01, 4, 8),
+	},
+		[]string{"type", "result"},
+	)
+
+	hookFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "kata_shim",
+		Name:      "oci_hook_failures_total",
+		Help:      "Failures of the OCI hooks run on the host.",
+	},
+		[]string{"type", "reason"},
+	)
+)
+
+// RegisterHookMetrics registers the metrics of the OCI hooks run on the host.
+func RegisterHookMetrics(r prometheus.Registerer) {
+	r.MustRegister(hookDurations)
+	r.MustRegister(hookFailures)
+}
+
+type hookSandboxKey struct{}
+
+type hookSandbox struct {
+	id    string
+	vmPid int
+}
+
+// WithHookSandbox returns a context passing the sandbox ID and the pid of the
+// hypervisor, 0 if the VM isn't started, to the hooks run with it.
+func WithHookSandbox(ctx context.Context, sandboxID string, vmPid int) context.Context {
+	return context.WithValue(ctx, hookSandboxKey{}, hookSandbox{id: sandboxID, vmPid: vmPid})
+}
+
+// HookContext returns a context passing the sandbox ID and the pid of its
+// hypervisor to the hooks run with it.
+func HookContext(ctx context.Context, sandbox vc.VCSandbox) context.Context {
+	// not set when the VM isn't running
+	pid, _ := sandbox.GetHypervisorPid()
+
+	return WithHookSandbox(ctx, sandbox.ID(), pid)
+}
+
+// HookError is the error of a failed OCI hook.
+type HookError struct {
+	Path string
+	// ExitCode is the exit code of the hook, -1 if it didn't exit.
+	ExitCode int
+	TimedOut bool
+	Stdout   string
+	Stderr   string
+	Err      error
+}
+
+func (e *HookError) Error() string {
+	if e.TimedOut {
+		return fmt.Sprintf("hook %s timeout", e.Path)
+	}
+
+	return fmt.Sprintf("%s: stdout: %s, stderr: %s", e.Err, e.Stdout, e.Stderr)
+}
+
+func (e *HookError) Unwrap() error {
+	return e.Err
+}
+
+// reason returns the failure reason of the metrics.
+func (e *HookError) reason() string {
+	switch {
+	case e.TimedOut:
+		return "timeout"
+	case e.ExitCode >= 0:
+		return "exit"
+	default:
+		return "start"
+	}
+}
+
+// hookEnv returns the environment of a hook, with the sandbox variables
+// not set by the hook.
+func hookEnv(ctx context.Context, hook specs.Hook) []string {
+	sandbox, ok := ctx.Value(hookSandboxKey{}).(hookSandbox)
+	if !ok {
+		return hook.Env
+	}
+
+	env := append([]string{}, hook.Env...)
+	set := func(key, value string) {
+		for _, e := range hook.Env {
+			if strings.HasPrefix(e, key+"=") {
+				return
+			}
+		}
+		env = append(env, key+"="+value)
+	}
+
+	set(HookSandboxIDEnv, sandbox.id)
+	if sandbox.vmPid > 0 {
+		set(HookVMPidEnv, strconv.Itoa(sandbox.vmPid))
+	}
+
+	return env
+}
+
 func runHook(ctx context.Context, hook specs.Hook, cid, bundlePath string) error {
 	span, _ := katatrace.Trace(ctx, hookLogger(), "runHook", hookTracingTags)
 	defer span.End()
@@ -56,19 +176,37 @@ func runHook(ctx context.Context, hook specs.Hook, cid, bundlePath string) error
 	cmd := &exec.Cmd{
 		Path:   hook.Path,
 		Args:   hook.Args,
-		Env:    hook.Env,
+		Env:    hookEnv(ctx, hook),
 		Stdin:  bytes.NewReader(stateJSON),
 		Stdout: &stdout,
 		Stderr: &stderr,
+		// the processes started by the hook are killed with it
+		SysProcAttr: &syscall.SysProcAttr{Setpgid: true},
+	}
+
+	hookErr := func(err error, timedOut bool) error {
+		exitCode := -1
+		if cmd.ProcessState != nil && !timedOut {
+			exitCode = cmd.ProcessState.ExitCode()
+		}
+
+		return &HookError{
+			Path:     hook.Path,
+			ExitCode: exitCode,
+			TimedOut: timedOut,
+			Stdout:   stdout.String(),
+			Stderr:   stderr.String(),
+			Err:      err,
+		}
 	}
 
 	if err := cmd.Start(); err != nil {
-		return err
+		return hookErr(err, false)
 	}
 
 	if hook.Timeout == nil {
 		if err := cmd.Wait(); err != nil {
-			return fmt.Errorf("%s: stdout: %s, stderr: %s", err, stdout.String(), stderr.String())
+			return hookErr(err, false)
 		}
 	} else {
 		done := make(chan error, 1)
@@ -80,14 +218,16 @@ func runHook(ctx context.Context, hook specs.Hook, cid, bundlePath string) error
 		select {
 		case err := <-done:
 			if err != nil {
-				return fmt.Errorf("%s: stdout: %s, stderr: %s", err, stdout.String(), stderr.String())
+				return hookErr(err, false)
 			}
 		case <-time.After(time.Duration(*hook.Timeout) * time.Second):
-			if err := syscall.Kill(cmd.Process.Pid, syscall.SIGKILL); err != nil {
+			if err := syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL); err != nil {
 				return err
 			}
+			// the output is complete once the hook is reaped
+			<-done
 
-			return fmt.Errorf("Hook timeout")
+			return hookErr(fmt.Errorf("Hook timeout"), true)
 		}
 	}
 
@@ -100,14 +240,36 @@ func runHooks(ctx context.Context, hooks []specs.Hook, cid, bundlePath, hookType
 	defer span.End()
 
 	for _, hook := range hooks {
-		if err := runHook(ctx, hook, cid, bundlePath); err != nil {
-			hookLogger().WithFields(logrus.Fields{
-				"hook-type": hookType,
-				"error":     err,
-			}).Error("hook error")
+		start := time.Now()
+		err := runHook(ctx, hook, cid, bundlePath)
+		duration := time.Since(start)
+
+		fields := logrus.Fields{
+			"hook-type": hookType,
+			"hook-path": hook.Path,
+			"container": cid,
+			"duration":  duration,
+		}
 
-			return err
+		if err == nil {
+			hookDurations.WithLabelValues(hookType, "success").Observe(duration.Seconds())
+			hookLogger().WithFields(fields).Debug("hook completed")
+			continue
 		}
+
+		reason := "error"
+		if e, ok := err.(*HookError); ok {
+			reason = e.reason()
+			fields["exit-code"] = e.ExitCode
+			fields["timed-out"] = e.TimedOut
+		}
+		fields["error"] = err
+
+		hookDurations.WithLabelValues(hookType, "failure").Observe(duration.Seconds())
+		hookFailures.WithLabelValues(hookType, reason).Inc()
+		hookLogger().WithFields(fields).Error("hook error")
+
+		return err
 	}
 
 	return nil
@@ -123,6 +285,39 @@ func PreStartHooks(ctx context.Context, spec specs.Spec, cid, bundlePath string)
 	return runHooks(ctx, spec.Hooks.Prestart, cid, bundlePath, "pre-start")
 }
 
+// CreateRuntimeHooks run the hooks after the container is created, in the
+// runtime namespace, right after the pre-start hooks
+func CreateRuntimeHooks(ctx context.Context, spec specs.Spec, cid, bundlePath string) error {
+	// If no hook available, nothing needs to be done.
+	if spec.Hooks == nil {
+		return nil
+	}
+
+	return runHooks(ctx, spec.Hooks.CreateRuntime, cid, bundlePath, "create-runtime")
+}
+
+// CreateContainerHooks run the hooks after the create-runtime hooks, when
+// they are not run by the agent in the container namespaces
+func CreateContainerHooks(ctx context.Context, spec specs.Spec, cid, bundlePath string) error {
+	// If no hook available, nothing needs to be done.
+	if spec.Hooks == nil {
+		return nil
+	}
+
+	return runHooks(ctx, spec.Hooks.CreateContainer, cid, bundlePath, "create-container")
+}
+
+// StartContainerHooks run the hooks before the container process is started,
+// when they are not run by the agent in the container namespaces
+func StartContainerHooks(ctx context.Context, spec specs.Spec, cid, bundlePath string) error {
+	// If no hook available, nothing needs to be done.
+	if spec.Hooks == nil {
+		return nil
+	}
+
+	return runHooks(ctx, spec.Hooks.StartContainer, cid, bundlePath, "start-container")
+}
+
 // PostStartHooks run the hooks just after start container
 func PostStartHooks(ctx context.Context, spec specs.Spec, cid, bundlePath string) error {
 	// If no hook available, nothing needs to be done.
@@ -122,6 +122,48 @@ func TestPreStartHooks(t *testing.T) {
 	assert.Error(err)
 }
 
+func TestCreateRuntimeHooks(t *testing.T) {
+	if tc.NotValid(ktu.NeedRoot()) {
+		t.Skip(ktu.TestDisabledNeedRoot)
+	}
+
+	assert := assert.New(t)
+
+	ctx := context.Background()
+
+	// Hooks field is nil
+	spec := specs.Spec{}
+	err := CreateRuntimeHooks(ctx, spec, "", "")
+	assert.NoError(err)
+
+	// Hooks list is empty
+	spec = specs.Spec{
+		Hooks: &specs.Hooks{},
+	}
+	err = CreateRuntimeHooks(ctx, spec, "", "")
+	assert.NoError(err)
+
+	// Run with timeout 0
+	hook := createHook(0)
+	spec = specs.Spec{
+		Hooks: &specs.Hooks{
+			CreateRuntime: []specs.Hook{hook},
+		},
+	}
+	err = CreateRuntimeHooks(ctx, spec, testSandboxID, testBundlePath)
+	assert.NoError(err)
+
+	// Failure due to wrong hook
+	hook = createWrongHook()
+	spec = specs.Spec{
+		Hooks: &specs.Hooks{
+			CreateRuntime: []specs.Hook{hook},
+		},
+	}
+	err = CreateRuntimeHooks(ctx, spec, testSandboxID, testBundlePath)
+	assert.Error(err)
+}
+
 func TestPostStartHooks(t *testing.T) {
 	if tc.NotValid(ktu.NeedRoot()) {
 		t.Skip(ktu.TestDisabledNeedRoot)
@@ -205,3 +247,73 @@ func TestPostStopHooks(t *testing.T) {
 	err = PostStopHooks(ctx, spec, testSandboxID, testBundlePath)
 	assert.Error(err)
 }
+
+func TestHookEnv(t *testing.T) {
+	assert := assert.New(t)
+
+	hook := specs.Hook{
+		Path: "/bin/true",
+		Env:  []string{"FOO=bar", HookVMPidEnv + "=1"},
+	}
+
+	// no sandbox
+	assert.Equal(hook.Env, hookEnv(context.Background(), hook))
+
+	ctx := WithHookSandbox(context.Background(), testSandboxID, 42)
+	assert.Equal([]string{"FOO=bar", HookVMPidEnv + "=1", HookSandboxIDEnv + "=" + testSandboxID}, hookEnv(ctx, hook))
+
+	// the VM isn't started
+	ctx = WithHookSandbox(context.Background(), testSandboxID, 0)
+	hook.Env = nil
+	assert.Equal([]string{HookSandboxIDEnv + "=" + testSandboxID}, hookEnv(ctx, hook))
+}
+
+func TestRunHookError(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx := WithHookSandbox(context.Background(), testSandboxID, 42)
+	hook := specs.Hook{
+		Path: "/bin/sh",
+		Args: []string{"sh", "-c", `test "$` + HookSandboxIDEnv + `" = "` + testSandboxID + `" && test "$` + HookVMPidEnv + `" = 42`},
+	}
+	assert.NoError(runHook(ctx, hook, testContainerID, testBundlePath))
+
+	hook.Args = []string{"sh", "-c", "echo failed >&2; exit 3"}
+	err := runHook(ctx, hook, testContainerID, testBundlePath)
+	hookErr, ok := err.(*HookError)
+	assert.True(ok)
+	assert.Equal(3, hookErr.ExitCode)
+	assert.Equal("failed\n", hookErr.Stderr)
+	assert.Equal("exit", hookErr.reason())
+
+	timeout := 1
+	hook.Args = []string{"sh", "-c", "sleep 5"}
+	hook.Timeout = &timeout
+	err = runHook(ctx, hook, testContainerID, testBundlePath)
+	hookErr, ok = err.(*HookError)
+	assert.True(ok)
+	assert.True(hookErr.TimedOut)
+	assert.Equal("timeout", hookErr.reason())
+
+	hook.Path = "/does/not/exist"
+	err = runHook(ctx, hook, testContainerID, testBundlePath)
+	hookErr, ok = err.(*HookError)
+	assert.True(ok)
+	assert.Equal(-1, hookErr.ExitCode)
+	assert.Equal("start", hookErr.reason())
+}
+
+func TestStartContainerHooks(t *testing.T) {
+	assert := assert.New(t)
+
+	spec := specs.Spec{}
+	assert.NoError(CreateContainerHooks(context.Background(), spec, testContainerID, testBundlePath))
+	assert.NoError(StartContainerHooks(context.Background(), spec, testContainerID, testBundlePath))
+
+	spec.Hooks = &specs.Hooks{
+		CreateContainer: []specs.Hook{{Path: "/bin/true", Args: []string{"true"}}},
+		StartContainer:  []specs.Hook{{Path: "/bin/false", Args: []string{"false"}}},
+	}
+	assert.NoError(CreateContainerHooks(context.Background(), spec, testContainerID, testBundlePath))
+	assert.Error(StartContainerHooks(context.Background(), spec, testContainerID, testBundlePath))
+}
@@ -15,6 +15,12 @@ import (
 	lSyslog "github.com/sirupsen/logrus/hooks/syslog"
 )
 
+// Formats of the shim log
+const (
+	ShimLogFormatText = "text"
+	ShimLogFormatJSON = "json"
+)
+
 // Default our log level to 'Warn', rather than the logrus default
 // of 'Info', which is rather noisy.
 var originalLoggerLevel = logrus.WarnLevel
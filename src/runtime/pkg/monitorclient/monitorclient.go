@@ -0,0 +1,214 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+// Package monitorclient is a client of the HTTP API served by kata-monitor.
+package monitorclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	katamonitor "github.com/kata-containers/kata-containers/src/runtime/pkg/kata-monitor"
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/shimclient"
+	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
+)
+
+// Client is a client of kata-monitor.
+type Client struct {
+	base url.URL
+	http *http.Client
+}
+
+// New returns a client of the kata-monitor listening at address, a
+// host:port or an http:// URL. The requests time out after timeout when
+// not zero.
+func New(address string, timeout time.Duration) (*Client, error) {
+	if !strings.Contains(address, "://") {
+		address = "http://" + address
+	}
+
+	base, err := url.Parse(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid kata-monitor address %q: %v", address, err)
+	}
+	if base.Scheme != "http" && base.Scheme != "https" {
+		return nil, fmt.Errorf("invalid kata-monitor address %q: unsupported scheme %s", address, base.Scheme)
+	}
+
+	return &Client{
+		base: *base,
+		http: &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// HTTPClient returns the HTTP client of kata-monitor, for the endpoints
+// not wrapped by Client.
+func (c *Client) HTTPClient() *http.Client {
+	return c.http
+}
+
+// URL returns the URL of a kata-monitor path, e.g. /metrics.
+func (c *Client) URL(path string, query url.Values) string {
+	u := c.base
+	u.Path = strings.TrimSuffix(u.Path, "/") + path
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+func (c *Client) do(method, path string, query url.Values, accept string) ([]byte, error) {
+	req, err := http.NewRequest(method, c.URL(path, query), nil)
+	if err != nil {
+		return nil, err
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := shimclient.CheckResponse(resp); err != nil {
+		return nil, err
+	}
+
+	return ioutil.ReadAll(resp.Body)
+}
+
+func (c *Client) getJSON(path string, query url.Values, v interface{}) error {
+	body, err := c.do(http.MethodGet, path, query, "application/json")
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("invalid response of %s: %v", path, err)
+	}
+
+	return nil
+}
+
+// Metrics returns the metrics of kata-monitor and of all the sandboxes,
+// in the Prometheus text format.
+func (c *Client) Metrics() ([]byte, error) {
+	return c.do(http.MethodGet, "/metrics", nil, "")
+}
+
+// SandboxListOptions filters and paginates the sandboxes of ListSandboxes.
+type SandboxListOptions struct {
+	// Namespace is the containerd namespace of the sandboxes
+	Namespace string
+	// State is the state of the sandboxes, e.g. katamonitor.SandboxRunning
+	State string
+	// Limit is the maximum number of sandboxes returned, all of them
+	// when zero
+	Limit int
+	// Continue is the token of the page, returned with the previous one
+	Continue string
+}
+
+// ListSandboxes returns a page of the sandboxes of the node.
+func (c *Client) ListSandboxes(opts SandboxListOptions) (*katamonitor.SandboxList, error) {
+	query := url.Values{}
+	if opts.Namespace != "" {
+		query.Set("namespace", opts.Namespace)
+	}
+	if opts.State != "" {
+		query.Set("state", opts.State)
+	}
+	if opts.Limit > 0 {
+		query.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	if opts.Continue != "" {
+		query.Set("continue", opts.Continue)
+	}
+
+	var list katamonitor.SandboxList
+	if err := c.getJSON("/sandboxes", query, &list); err != nil {
+		return nil, err
+	}
+	if list.APIVersion != katamonitor.SandboxListVersion {
+		return nil, fmt.Errorf("unsupported sandbox list version %q", list.APIVersion)
+	}
+
+	return &list, nil
+}
+
+// Sandboxes returns all the sandboxes of the node.
+func (c *Client) Sandboxes() ([]katamonitor.SandboxInfo, error) {
+	list, err := c.ListSandboxes(SandboxListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return list.Sandboxes, nil
+}
+
+// SandboxStats returns the stats of the VM and of all the containers of a
+// sandbox.
+func (c *Client) SandboxStats(sandboxID string) (*vc.PodStats, error) {
+	var stats vc.PodStats
+	if err := c.getJSON("/sandboxes/"+url.PathEscape(sandboxID)+"/stats", nil, &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// Shims returns the shims of the sandboxes with their version, only the
+// ones older than olderThan when not empty.
+func (c *Client) Shims(olderThan string) ([]katamonitor.ShimInfo, error) {
+	query := url.Values{}
+	if olderThan != "" {
+		query.Set("version-older-than", olderThan)
+	}
+
+	var shims []katamonitor.ShimInfo
+	err := c.getJSON("/shims", query, &shims)
+	return shims, err
+}
+
+// Problems returns the problems found by the problem detector.
+func (c *Client) Problems() ([]katamonitor.Problem, error) {
+	var problems []katamonitor.Problem
+	err := c.getJSON("/problems", nil, &problems)
+	return problems, err
+}
+
+// AgentURL returns the URL of the agent of a sandbox.
+func (c *Client) AgentURL(sandboxID string) (string, error) {
+	body, err := c.do(http.MethodGet, "/agent-url", url.Values{"sandbox": {sandboxID}}, "")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(body)), nil
+}
+
+// LogLevel returns the log level of kata-monitor.
+func (c *Client) LogLevel() (string, error) {
+	body, err := c.do(http.MethodGet, "/loglevel", nil, "")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(body)), nil
+}
+
+// SetLogLevel changes the log level of kata-monitor, the previous level
+// being restored after timeout when not zero.
+func (c *Client) SetLogLevel(level string, timeout time.Duration) error {
+	query := url.Values{"level": {level}}
+	if timeout > 0 {
+		query.Set("timeout", timeout.String())
+	}
+
+	_, err := c.do(http.MethodPut, "/loglevel", query, "")
+	return err
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package monitorclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	katamonitor "github.com/kata-containers/kata-containers/src/runtime/pkg/kata-monitor"
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/shimclient"
+	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	assert := assert.New(t)
+
+	c, err := New("127.0.0.1:8090", 0)
+	assert.NoError(err)
+	assert.Equal("http://127.0.0.1:8090/metrics", c.URL("/metrics", nil))
+
+	c, err = New("https://monitor.example.com/kata/", 0)
+	assert.NoError(err)
+	assert.Equal("https://monitor.example.com/kata/shims?version-older-than=2.2.0",
+		c.URL("/shims", map[string][]string{"version-older-than": {"2.2.0"}}))
+
+	_, err = New("unix:///run/kata-monitor.sock", 0)
+	assert.Error(err)
+}
+
+func TestClient(t *testing.T) {
+	assert := assert.New(t)
+
+	m := http.NewServeMux()
+	m.HandleFunc("/sandboxes", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal("application/json", r.Header.Get("Accept"))
+		list := katamonitor.SandboxList{
+			APIVersion: katamonitor.SandboxListVersion,
+			Sandboxes:  []katamonitor.SandboxInfo{{ID: "foo", Namespace: "k8s.io"}},
+		}
+		if r.URL.Query().Get("limit") == "1" {
+			list.Continue = "Zm9v"
+		}
+		json.NewEncoder(w).Encode(list)
+	})
+	m.HandleFunc("/shims", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal("2.2.0", r.URL.Query().Get("version-older-than"))
+		shims := []katamonitor.ShimInfo{{SandboxInfo: katamonitor.SandboxInfo{ID: "foo"}}}
+		shims[0].Version = "2.1.0"
+		json.NewEncoder(w).Encode(shims)
+	})
+	m.HandleFunc("/sandboxes/foo/stats", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(vc.PodStats{Overhead: vc.PodOverhead{CPUTime: 42}})
+	})
+	m.HandleFunc("/problems", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "problem detector is not enabled", http.StatusNotFound)
+	})
+	m.HandleFunc("/agent-url", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "vsock://%s:1024\n", r.URL.Query().Get("sandbox"))
+	})
+
+	srv := httptest.NewServer(m)
+	defer srv.Close()
+
+	c, err := New(srv.URL, time.Second)
+	assert.NoError(err)
+
+	sandboxes, err := c.Sandboxes()
+	assert.NoError(err)
+	assert.Equal([]katamonitor.SandboxInfo{{ID: "foo", Namespace: "k8s.io"}}, sandboxes)
+
+	list, err := c.ListSandboxes(SandboxListOptions{Namespace: "k8s.io", Limit: 1})
+	assert.NoError(err)
+	assert.Equal("Zm9v", list.Continue)
+
+	stats, err := c.SandboxStats("foo")
+	assert.NoError(err)
+	assert.Equal(uint64(42), stats.Overhead.CPUTime)
+
+	shims, err := c.Shims("2.2.0")
+	assert.NoError(err)
+	assert.Len(shims, 1)
+	assert.Equal("2.1.0", shims[0].Version)
+
+	_, err = c.Problems()
+	assert.True(shimclient.IsNotFound(err))
+
+	url, err := c.AgentURL("42")
+	assert.NoError(err)
+	assert.Equal("vsock://42:1024", url)
+}
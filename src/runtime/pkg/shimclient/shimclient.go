@@ -0,0 +1,321 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+// Package shimclient is a client of the management API served by the
+// containerd shim of each sandbox, on an abstract or pathname unix socket.
+package shimclient
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	shim "github.com/kata-containers/kata-containers/src/runtime/containerd-shim-v2"
+	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
+	vf "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/factory"
+	"github.com/prometheus/common/expfmt"
+)
+
+// UnixSocketScheme prefixes the addresses of the pathname sockets, the
+// other addresses are abstract sockets, as for containerd shims.
+const UnixSocketScheme = "unix://"
+
+// maximum size of the error messages kept from the responses
+const maxErrorMessageSize = 4096
+
+// the formats of the metrics accepted by MetricsStream, the protobuf
+// delimited one being preferred
+var metricsAccept = string(expfmt.FmtProtoDelim) + ";q=0.7," + string(expfmt.FmtText) + ";q=0.3"
+
+// StatusError is returned when the shim answers with an unexpected status.
+type StatusError struct {
+	StatusCode int
+	Status     string
+	// Message is the body of the response
+	Message string
+}
+
+func (e *StatusError) Error() string {
+	if e.Message == "" {
+		return e.Status
+	}
+	return fmt.Sprintf("%s: %s", e.Status, e.Message)
+}
+
+// IsNotFound returns true if err is a 404 response, e.g. of an endpoint
+// the shim is older than.
+func IsNotFound(err error) bool {
+	var statusErr *StatusError
+	return errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound
+}
+
+// CheckResponse returns a StatusError if the status of resp is not 2xx.
+func CheckResponse(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxErrorMessageSize))
+	return &StatusError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Message:    strings.TrimSpace(string(body)),
+	}
+}
+
+// DefaultAddress returns the management socket address of the shim of a
+// sandbox, as a pathname socket if the shim created it on the filesystem.
+func DefaultAddress(sandboxID string) string {
+	address := shim.SocketAddress(sandboxID)
+	if fi, err := os.Stat(address); err == nil && fi.Mode()&os.ModeSocket != 0 {
+		return UnixSocketScheme + address
+	}
+
+	return address
+}
+
+// DialPath returns the path to dial for a socket address, abstract unless
+// the address is a unix:// one.
+func DialPath(address string) string {
+	if strings.HasPrefix(address, UnixSocketScheme) {
+		return strings.TrimPrefix(address, UnixSocketScheme)
+	}
+	return "\x00" + address
+}
+
+// NewHTTPClient returns an HTTP client sending all its requests to the
+// socket address, whatever the host of their URL.
+func NewHTTPClient(address string, timeout time.Duration) *http.Client {
+	transport := &http.Transport{
+		DisableKeepAlives: true,
+		Dial: func(proto, addr string) (net.Conn, error) {
+			return net.Dial("unix", DialPath(address))
+		},
+	}
+
+	client := &http.Client{
+		Transport: transport,
+	}
+
+	if timeout > 0 {
+		client.Timeout = timeout
+	}
+
+	return client
+}
+
+// Client is a client of the management API of a shim.
+type Client struct {
+	http *http.Client
+}
+
+// New returns a client of the shim serving its management API at the
+// socket address, see DefaultAddress. The requests time out after timeout
+// when not zero.
+func New(address string, timeout time.Duration) *Client {
+	return &Client{http: NewHTTPClient(address, timeout)}
+}
+
+// HTTPClient returns the HTTP client of the shim, for the endpoints not
+// wrapped by Client.
+func (c *Client) HTTPClient() *http.Client {
+	return c.http
+}
+
+// open sends a request and returns its response, the caller closes its
+// body.
+func (c *Client) open(method, path string, query url.Values, header http.Header) (*http.Response, error) {
+	u := url.URL{Scheme: "http", Host: "shim", Path: path, RawQuery: query.Encode()}
+
+	req, err := http.NewRequest(method, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := CheckResponse(resp); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (c *Client) do(method, path string, query url.Values) ([]byte, error) {
+	resp, err := c.open(method, path, query, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	return ioutil.ReadAll(resp.Body)
+}
+
+// Get returns the body of a GET request of path, e.g. /metrics.
+func (c *Client) Get(path string) ([]byte, error) {
+	return c.do(http.MethodGet, path, nil)
+}
+
+func (c *Client) getJSON(path string, v interface{}) error {
+	body, err := c.Get(path)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("invalid response of %s: %v", path, err)
+	}
+
+	return nil
+}
+
+// Metrics returns the metrics of the shim, the hypervisor and the agent,
+// in the Prometheus text format.
+func (c *Client) Metrics() ([]byte, error) {
+	return c.Get("/metrics")
+}
+
+// MetricsStream returns the body of the metrics response, to be decoded as
+// it is read instead of buffering the metrics, and its format. The protobuf
+// delimited format, cheaper to decode, is requested, the shims older than it
+// answer with the text format. The caller closes the body.
+func (c *Client) MetricsStream() (io.ReadCloser, expfmt.Format, error) {
+	header := http.Header{"Accept": []string{metricsAccept}}
+	resp, err := c.open(http.MethodGet, "/metrics", nil, header)
+	if err != nil {
+		return nil, expfmt.FmtUnknown, err
+	}
+
+	return resp.Body, expfmt.ResponseFormat(resp.Header), nil
+}
+
+// AgentURL returns the URL of the agent of the sandbox.
+func (c *Client) AgentURL() (string, error) {
+	body, err := c.Get("/agent-url")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(body)), nil
+}
+
+// AgentAPIs returns the requests served by the agent, all of them when
+// empty.
+func (c *Client) AgentAPIs() ([]string, error) {
+	var apis []string
+	err := c.getJSON("/agent-apis", &apis)
+	return apis, err
+}
+
+// Version returns the version of the shim. The shims older than the
+// version endpoint have an empty version.
+func (c *Client) Version() (*shim.VersionInfo, error) {
+	var info shim.VersionInfo
+	if err := c.getJSON("/version", &info); err != nil {
+		if IsNotFound(err) {
+			return &shim.VersionInfo{}, nil
+		}
+		return nil, err
+	}
+	return &info, nil
+}
+
+// AuditLog returns the audit log of the sandbox.
+func (c *Client) AuditLog() ([]vc.AuditRecord, error) {
+	var records []vc.AuditRecord
+	err := c.getJSON("/audit", &records)
+	return records, err
+}
+
+// EphemeralDisk returns the status of the ephemeral disk of the sandbox.
+func (c *Client) EphemeralDisk() (*vc.EphemeralDiskStatus, error) {
+	var status vc.EphemeralDiskStatus
+	if err := c.getJSON("/ephemeral-disk", &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// GuestProtection returns the guest protection of the sandbox VM.
+func (c *Client) GuestProtection() (*vc.GuestProtectionStatus, error) {
+	var status vc.GuestProtectionStatus
+	if err := c.getJSON("/guest-protection", &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// HypervisorInvocation returns how the hypervisor of the sandbox was
+// launched.
+func (c *Client) HypervisorInvocation() (*vc.HypervisorInvocation, error) {
+	var invocation vc.HypervisorInvocation
+	if err := c.getJSON("/hypervisor-invocation", &invocation); err != nil {
+		return nil, err
+	}
+	return &invocation, nil
+}
+
+// Status returns the status of the sandbox and the liveness of its guest.
+func (c *Client) Status() (*shim.SandboxStatus, error) {
+	var status shim.SandboxStatus
+	if err := c.getJSON("/status", &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// Stats returns the stats of the VM and of all the containers of the
+// sandbox.
+func (c *Client) Stats() (*vc.PodStats, error) {
+	var stats vc.PodStats
+	if err := c.getJSON("/stats", &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// Factory returns the status of the VM factory of the sandbox.
+func (c *Client) Factory() (*vf.Status, error) {
+	var status vf.Status
+	if err := c.getJSON("/factory", &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// LogLevel returns the log level of the shim.
+func (c *Client) LogLevel() (string, error) {
+	body, err := c.Get("/loglevel")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(body)), nil
+}
+
+// SetLogLevel changes the log level of the shim, the previous level being
+// restored after timeout when not zero.
+func (c *Client) SetLogLevel(level string, timeout time.Duration) error {
+	query := url.Values{"level": {level}}
+	if timeout > 0 {
+		query.Set("timeout", timeout.String())
+	}
+
+	_, err := c.do(http.MethodPut, "/loglevel", query)
+	return err
+}
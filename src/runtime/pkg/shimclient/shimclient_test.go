@@ -0,0 +1,159 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package shimclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	shim "github.com/kata-containers/kata-containers/src/runtime/containerd-shim-v2"
+	mutils "github.com/kata-containers/kata-containers/src/runtime/pkg/utils"
+	vc "github.com/kata-containers/kata-containers/src/runtime/virtcontainers"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDialPath(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("\x00/run/vc/foo/shim-monitor", DialPath("/run/vc/foo/shim-monitor"))
+	assert.Equal("/run/vc/foo/shim-monitor", DialPath("unix:///run/vc/foo/shim-monitor"))
+}
+
+func TestDefaultAddress(t *testing.T) {
+	assert.Equal(t, shim.SocketAddress("foo"), DefaultAddress("foo"))
+}
+
+// newShim serves the management API on a pathname socket, returning its
+// address.
+func newShim(t *testing.T, handler http.Handler) (string, func()) {
+	dir, err := ioutil.TempDir("", "shimclient")
+	assert.NoError(t, err)
+
+	path := filepath.Join(dir, "shim-monitor")
+	listener, err := net.Listen("unix", path)
+	assert.NoError(t, err)
+
+	srv := &http.Server{Handler: handler}
+	go srv.Serve(listener)
+
+	return UnixSocketScheme + path, func() {
+		srv.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestClient(t *testing.T) {
+	assert := assert.New(t)
+
+	level := "info"
+	m := http.NewServeMux()
+	m.HandleFunc("/agent-url", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "vsock://42:1024")
+	})
+	m.HandleFunc("/agent-apis", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]string{"ExecProcess"})
+	})
+	m.HandleFunc("/version", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(shim.VersionInfo{Version: "2.2.0", Pid: 42})
+	})
+	m.HandleFunc("/status", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(shim.SandboxStatus{ID: "foo", State: "running"})
+	})
+	m.HandleFunc("/hypervisor-invocation", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(vc.HypervisorInvocation{Path: "/usr/bin/qemu-system-x86_64", Args: []string{"-name", "sandbox-foo"}})
+	})
+	m.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		format := expfmt.Negotiate(r.Header)
+		w.Header().Set("Content-Type", string(format))
+		value := float64(42)
+		expfmt.NewEncoder(w, format).Encode(&dto.MetricFamily{
+			Name:   mutils.String2Pointer("kata_shim_fds"),
+			Type:   dto.MetricType_GAUGE.Enum(),
+			Metric: []*dto.Metric{{Gauge: &dto.Gauge{Value: &value}}},
+		})
+	})
+	m.HandleFunc("/ephemeral-disk", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "no ephemeral disk", http.StatusNotFound)
+	})
+	m.HandleFunc("/loglevel", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			assert.Equal("1m0s", r.URL.Query().Get("timeout"))
+			level = r.URL.Query().Get("level")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		fmt.Fprintln(w, level)
+	})
+
+	address, cleanup := newShim(t, m)
+	defer cleanup()
+
+	c := New(address, time.Second)
+
+	url, err := c.AgentURL()
+	assert.NoError(err)
+	assert.Equal("vsock://42:1024", url)
+
+	apis, err := c.AgentAPIs()
+	assert.NoError(err)
+	assert.Equal([]string{"ExecProcess"}, apis)
+
+	version, err := c.Version()
+	assert.NoError(err)
+	assert.Equal("2.2.0", version.Version)
+	assert.Equal(42, version.Pid)
+
+	status, err := c.Status()
+	assert.NoError(err)
+	assert.Equal("foo", status.ID)
+	assert.Equal("running", status.State)
+
+	invocation, err := c.HypervisorInvocation()
+	assert.NoError(err)
+	assert.Equal([]string{"-name", "sandbox-foo"}, invocation.Args)
+
+	stream, format, err := c.MetricsStream()
+	assert.NoError(err)
+	assert.Equal(expfmt.FmtProtoDelim, format)
+	var mf dto.MetricFamily
+	assert.NoError(expfmt.NewDecoder(stream, format).Decode(&mf))
+	assert.NoError(stream.Close())
+	assert.Equal("kata_shim_fds", mf.GetName())
+	assert.Equal(float64(42), mf.Metric[0].GetGauge().GetValue())
+
+	_, err = c.EphemeralDisk()
+	assert.True(IsNotFound(err))
+	assert.Equal("404 Not Found: no ephemeral disk", err.Error())
+
+	_, err = c.GuestProtection()
+	assert.True(IsNotFound(err))
+
+	assert.NoError(c.SetLogLevel("debug", time.Minute))
+	level, err = c.LogLevel()
+	assert.NoError(err)
+	assert.Equal("debug", level)
+}
+
+func TestClientOldShim(t *testing.T) {
+	assert := assert.New(t)
+
+	address, cleanup := newShim(t, http.NotFoundHandler())
+	defer cleanup()
+
+	// the shims older than the version endpoint have an empty version
+	version, err := New(address, time.Second).Version()
+	assert.NoError(err)
+	assert.Equal(&shim.VersionInfo{}, version)
+}
@@ -0,0 +1,100 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package utils
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// LogLevelHandler is a HTTP handler to get and change the level of a logger
+// at runtime:
+//
+//	GET /loglevel                         returns the current level
+//	PUT /loglevel?level=debug             changes the level
+//	PUT /loglevel?level=debug&timeout=10m changes the level, and restores
+//	                                      the previous one after timeout
+type LogLevelHandler struct {
+	sync.Mutex
+	logger *logrus.Logger
+	timer  *time.Timer
+	// level to restore when the timer fires
+	revertLevel logrus.Level
+}
+
+// NewLogLevelHandler returns a LogLevelHandler changing the level of logger.
+func NewLogLevelHandler(logger *logrus.Logger) *LogLevelHandler {
+	return &LogLevelHandler{logger: logger}
+}
+
+// ServeHTTP handles the /loglevel requests.
+func (h *LogLevelHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		fmt.Fprintln(w, h.logger.GetLevel().String())
+	case http.MethodPut:
+		level, err := logrus.ParseLevel(r.URL.Query().Get("level"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var timeout time.Duration
+		if t := r.URL.Query().Get("timeout"); t != "" {
+			if timeout, err = time.ParseDuration(t); err != nil || timeout <= 0 {
+				http.Error(w, fmt.Sprintf("invalid timeout %q", t), http.StatusBadRequest)
+				return
+			}
+		}
+
+		h.SetLevel(level, timeout)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "only GET and PUT are allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// SetLevel changes the level of the logger. If timeout is not zero, the level
+// in use before the change is restored once the timeout expires.
+func (h *LogLevelHandler) SetLevel(level logrus.Level, timeout time.Duration) {
+	h.Lock()
+	defer h.Unlock()
+
+	// a pending revert restores the level set before the first
+	// temporary change, not the temporary one.
+	revertLevel := h.logger.GetLevel()
+	if h.timer != nil {
+		h.timer.Stop()
+		h.timer = nil
+		revertLevel = h.revertLevel
+	}
+
+	h.logger.SetLevel(level)
+
+	if timeout == 0 {
+		return
+	}
+
+	h.revertLevel = revertLevel
+	var timer *time.Timer
+	timer = time.AfterFunc(timeout, func() {
+		h.Lock()
+		defer h.Unlock()
+
+		// the level was changed again in the meantime
+		if h.timer != timer {
+			return
+		}
+
+		h.logger.SetLevel(h.revertLevel)
+		h.timer = nil
+	})
+	h.timer = timer
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogLevelHandler(t *testing.T) {
+	assert := assert.New(t)
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.InfoLevel)
+	h := NewLogLevelHandler(logger)
+
+	testCases := []struct {
+		method string
+		query  string
+		code   int
+		level  logrus.Level
+	}{
+		{http.MethodPut, "level=debug", http.StatusNoContent, logrus.DebugLevel},
+		{http.MethodPut, "level=foo", http.StatusBadRequest, logrus.DebugLevel},
+		{http.MethodPut, "level=warn&timeout=foo", http.StatusBadRequest, logrus.DebugLevel},
+		{http.MethodPut, "level=warn&timeout=-1s", http.StatusBadRequest, logrus.DebugLevel},
+		{http.MethodPost, "level=warn", http.StatusMethodNotAllowed, logrus.DebugLevel},
+		{http.MethodPut, "level=warn", http.StatusNoContent, logrus.WarnLevel},
+	}
+
+	for _, tc := range testCases {
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, httptest.NewRequest(tc.method, "/loglevel?"+tc.query, nil))
+		assert.Equal(tc.code, rr.Code, tc.query)
+		assert.Equal(tc.level, logger.GetLevel(), tc.query)
+	}
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/loglevel", nil))
+	assert.Equal(http.StatusOK, rr.Code)
+	assert.Equal("warning", strings.TrimSpace(rr.Body.String()))
+}
+
+func TestLogLevelHandlerRevert(t *testing.T) {
+	assert := assert.New(t)
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.InfoLevel)
+	h := NewLogLevelHandler(logger)
+
+	h.SetLevel(logrus.DebugLevel, time.Hour)
+	assert.Equal(logrus.DebugLevel, logger.GetLevel())
+
+	// the pending revert restores the level in use
+	// before the first temporary change
+	h.SetLevel(logrus.TraceLevel, 10*time.Millisecond)
+	assert.Equal(logrus.TraceLevel, logger.GetLevel())
+
+	assert.Eventually(func() bool {
+		return logger.GetLevel() == logrus.InfoLevel
+	}, time.Second, 5*time.Millisecond)
+
+	// a permanent change cancels the pending revert
+	h.SetLevel(logrus.DebugLevel, 10*time.Millisecond)
+	h.SetLevel(logrus.ErrorLevel, 0)
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(logrus.ErrorLevel, logger.GetLevel())
+}
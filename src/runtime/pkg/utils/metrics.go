@@ -31,29 +31,6 @@ func SetGaugeVecNetDev(gv *prometheus.GaugeVec, v procfs.NetDevLine) {
 	gv.WithLabelValues(v.Name, "sent_fifo").Set(float64(v.TxFIFO))
 }
 
-// SetGaugeVecProcStatus set gauge for ProcStatus
-func SetGaugeVecProcStatus(gv *prometheus.GaugeVec, procStatus procfs.ProcStatus) {
-	gv.WithLabelValues("vmpeak").Set(float64(procStatus.VmPeak))
-	gv.WithLabelValues("vmsize").Set(float64(procStatus.VmSize))
-	gv.WithLabelValues("vmlck").Set(float64(procStatus.VmLck))
-	gv.WithLabelValues("vmpin").Set(float64(procStatus.VmPin))
-	gv.WithLabelValues("vmhwm").Set(float64(procStatus.VmHWM))
-	gv.WithLabelValues("vmrss").Set(float64(procStatus.VmRSS))
-	gv.WithLabelValues("rssanon").Set(float64(procStatus.RssAnon))
-	gv.WithLabelValues("rssfile").Set(float64(procStatus.RssFile))
-	gv.WithLabelValues("rssshmem").Set(float64(procStatus.RssShmem))
-	gv.WithLabelValues("vmdata").Set(float64(procStatus.VmData))
-	gv.WithLabelValues("vmstk").Set(float64(procStatus.VmStk))
-	gv.WithLabelValues("vmexe").Set(float64(procStatus.VmExe))
-	gv.WithLabelValues("vmlib").Set(float64(procStatus.VmLib))
-	gv.WithLabelValues("vmpte").Set(float64(procStatus.VmPTE))
-	gv.WithLabelValues("vmpmd").Set(float64(procStatus.VmPMD))
-	gv.WithLabelValues("vmswap").Set(float64(procStatus.VmSwap))
-	gv.WithLabelValues("hugetlbpages").Set(float64(procStatus.HugetlbPages))
-	gv.WithLabelValues("voluntary_ctxt_switches").Set(float64(procStatus.VoluntaryCtxtSwitches))
-	gv.WithLabelValues("nonvoluntary_ctxt_switches").Set(float64(procStatus.NonVoluntaryCtxtSwitches))
-}
-
 // SetGaugeVecProcIO set gauge for ProcIO
 func SetGaugeVecProcIO(gv *prometheus.GaugeVec, ioStat procfs.ProcIO) {
 	gv.WithLabelValues("rchar").Set(float64(ioStat.RChar))
@@ -0,0 +1,231 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package utils
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/procfs"
+)
+
+// procStatusField is a field of /proc/<pid>/status exported by the
+// proc_status metrics.
+type procStatusField struct {
+	// label is the item label of the metric
+	label string
+	// name is the name of the field in the status file
+	name  string
+	value func(procfs.ProcStatus) uint64
+}
+
+// procStatusFields are the fields of the proc_status metrics. Some of them
+// are not present on all the kernels, e.g. VmPMD was removed in 4.15.
+var procStatusFields = []procStatusField{
+	{"vmpeak", "VmPeak", func(s procfs.ProcStatus) uint64 { return s.VmPeak }},
+	{"vmsize", "VmSize", func(s procfs.ProcStatus) uint64 { return s.VmSize }},
+	{"vmlck", "VmLck", func(s procfs.ProcStatus) uint64 { return s.VmLck }},
+	{"vmpin", "VmPin", func(s procfs.ProcStatus) uint64 { return s.VmPin }},
+	{"vmhwm", "VmHWM", func(s procfs.ProcStatus) uint64 { return s.VmHWM }},
+	{"vmrss", "VmRSS", func(s procfs.ProcStatus) uint64 { return s.VmRSS }},
+	{"rssanon", "RssAnon", func(s procfs.ProcStatus) uint64 { return s.RssAnon }},
+	{"rssfile", "RssFile", func(s procfs.ProcStatus) uint64 { return s.RssFile }},
+	{"rssshmem", "RssShmem", func(s procfs.ProcStatus) uint64 { return s.RssShmem }},
+	{"vmdata", "VmData", func(s procfs.ProcStatus) uint64 { return s.VmData }},
+	{"vmstk", "VmStk", func(s procfs.ProcStatus) uint64 { return s.VmStk }},
+	{"vmexe", "VmExe", func(s procfs.ProcStatus) uint64 { return s.VmExe }},
+	{"vmlib", "VmLib", func(s procfs.ProcStatus) uint64 { return s.VmLib }},
+	{"vmpte", "VmPTE", func(s procfs.ProcStatus) uint64 { return s.VmPTE }},
+	{"vmpmd", "VmPMD", func(s procfs.ProcStatus) uint64 { return s.VmPMD }},
+	{"vmswap", "VmSwap", func(s procfs.ProcStatus) uint64 { return s.VmSwap }},
+	{"hugetlbpages", "HugetlbPages", func(s procfs.ProcStatus) uint64 { return s.HugetlbPages }},
+	{"voluntary_ctxt_switches", "voluntary_ctxt_switches", func(s procfs.ProcStatus) uint64 { return s.VoluntaryCtxtSwitches }},
+	{"nonvoluntary_ctxt_switches", "nonvoluntary_ctxt_switches", func(s procfs.ProcStatus) uint64 { return s.NonVoluntaryCtxtSwitches }},
+}
+
+// ProcMetrics are the metrics of a process collected by a ProcCollector,
+// the nil ones are not collected.
+type ProcMetrics struct {
+	OpenFDs    prometheus.Gauge
+	Threads    prometheus.Gauge
+	NetDev     *prometheus.GaugeVec
+	ProcStat   *prometheus.GaugeVec
+	ProcStatus *prometheus.GaugeVec
+	ProcIO     *prometheus.GaugeVec
+}
+
+// ProcCollector collects the metrics of the processes from procfs. The
+// fields of the status files are probed once, from the status of the
+// current process, so the fields missing on the running kernel are not
+// exported rather than reported as zero.
+type ProcCollector struct {
+	fs         procfs.FS
+	mountPoint string
+
+	probeOnce     sync.Once
+	kernel        string
+	missingFields map[string]bool
+}
+
+// NewProcCollector returns a collector of the procfs mounted at mountPoint,
+// procfs.DefaultMountPoint on the host.
+func NewProcCollector(mountPoint string) (*ProcCollector, error) {
+	fs, err := procfs.NewFS(mountPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ProcCollector{
+		fs:         fs,
+		mountPoint: mountPoint,
+	}, nil
+}
+
+var (
+	hostProcCollector     *ProcCollector
+	hostProcCollectorErr  error
+	hostProcCollectorOnce sync.Once
+)
+
+// HostProcCollector returns the collector of the host procfs, shared by
+// the callers so it is probed once.
+func HostProcCollector() (*ProcCollector, error) {
+	hostProcCollectorOnce.Do(func() {
+		hostProcCollector, hostProcCollectorErr = NewProcCollector(procfs.DefaultMountPoint)
+	})
+	return hostProcCollector, hostProcCollectorErr
+}
+
+// probe reads the kernel release and the fields of the status file of the
+// current process. All the fields are assumed to be present if the status
+// file can't be read.
+func (c *ProcCollector) probe() {
+	c.probeOnce.Do(func() {
+		c.missingFields = make(map[string]bool)
+
+		if release, err := ioutil.ReadFile(filepath.Join(c.mountPoint, "sys/kernel/osrelease")); err == nil {
+			c.kernel = strings.TrimSpace(string(release))
+		}
+
+		data, err := ioutil.ReadFile(filepath.Join(c.mountPoint, "self/status"))
+		if err != nil {
+			return
+		}
+
+		present := make(map[string]bool)
+		for _, line := range strings.Split(string(data), "\n") {
+			if i := strings.Index(line, ":"); i > 0 {
+				present[strings.TrimSpace(line[:i])] = true
+			}
+		}
+
+		for _, f := range procStatusFields {
+			if !present[f.name] {
+				c.missingFields[f.label] = true
+			}
+		}
+	})
+}
+
+// Kernel returns the release of the running kernel, empty if unknown.
+func (c *ProcCollector) Kernel() string {
+	c.probe()
+	return c.kernel
+}
+
+// MissingStatusFields returns the labels of the proc_status items that are
+// not collected on the running kernel.
+func (c *ProcCollector) MissingStatusFields() []string {
+	c.probe()
+
+	var labels []string
+	for _, f := range procStatusFields {
+		if c.missingFields[f.label] {
+			labels = append(labels, f.label)
+		}
+	}
+	return labels
+}
+
+// CollectSelf collects the metrics of the current process.
+func (c *ProcCollector) CollectSelf(m ProcMetrics) error {
+	proc, err := c.fs.Self()
+	if err != nil {
+		return err
+	}
+
+	c.collect(proc, m)
+	return nil
+}
+
+// Collect collects the metrics of the process pid.
+func (c *ProcCollector) Collect(pid int, m ProcMetrics) error {
+	proc, err := c.fs.Proc(pid)
+	if err != nil {
+		return err
+	}
+
+	c.collect(proc, m)
+	return nil
+}
+
+// collect sets the metrics of a process. The process files which can't be
+// read are skipped, they are not readable for all the processes.
+func (c *ProcCollector) collect(proc procfs.Proc, m ProcMetrics) {
+	if m.OpenFDs != nil {
+		if fds, err := proc.FileDescriptorsLen(); err == nil {
+			m.OpenFDs.Set(float64(fds))
+		}
+	}
+
+	if m.NetDev != nil {
+		if netdev, err := proc.NetDev(); err == nil {
+			for _, v := range netdev {
+				SetGaugeVecNetDev(m.NetDev, v)
+			}
+		}
+	}
+
+	if m.Threads != nil || m.ProcStat != nil {
+		if procStat, err := proc.Stat(); err == nil {
+			if m.Threads != nil {
+				m.Threads.Set(float64(procStat.NumThreads))
+			}
+			if m.ProcStat != nil {
+				SetGaugeVecProcStat(m.ProcStat, procStat)
+			}
+		}
+	}
+
+	if m.ProcStatus != nil {
+		if procStatus, err := proc.NewStatus(); err == nil {
+			c.setProcStatus(m.ProcStatus, procStatus)
+		}
+	}
+
+	if m.ProcIO != nil {
+		if ioStat, err := proc.IO(); err == nil {
+			SetGaugeVecProcIO(m.ProcIO, ioStat)
+		}
+	}
+}
+
+// setProcStatus sets the gauge of the status fields present on the
+// running kernel.
+func (c *ProcCollector) setProcStatus(gv *prometheus.GaugeVec, procStatus procfs.ProcStatus) {
+	c.probe()
+
+	for _, f := range procStatusFields {
+		if c.missingFields[f.label] {
+			gv.DeleteLabelValues(f.label)
+			continue
+		}
+		gv.WithLabelValues(f.label).Set(float64(f.value(procStatus)))
+	}
+}
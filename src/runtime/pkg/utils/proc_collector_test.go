@@ -0,0 +1,98 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package utils
+
+import (
+	"bytes"
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/expfmt"
+	"github.com/stretchr/testify/assert"
+)
+
+var update = flag.Bool("update", false, "update the golden files of the tests")
+
+func newTestProcMetrics() (*prometheus.Registry, ProcMetrics) {
+	m := ProcMetrics{
+		OpenFDs:    prometheus.NewGauge(prometheus.GaugeOpts{Name: "test_fds", Help: "Open FDs."}),
+		Threads:    prometheus.NewGauge(prometheus.GaugeOpts{Name: "test_threads", Help: "Threads."}),
+		NetDev:     prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "test_netdev", Help: "Network devices statistics."}, []string{"interface", "item"}),
+		ProcStat:   prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "test_proc_stat", Help: "Process statistics."}, []string{"item"}),
+		ProcStatus: prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "test_proc_status", Help: "Process status."}, []string{"item"}),
+		ProcIO:     prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "test_io_stat", Help: "Process IO statistics."}, []string{"item"}),
+	}
+
+	r := prometheus.NewRegistry()
+	r.MustRegister(m.OpenFDs, m.Threads, m.NetDev, m.ProcStat, m.ProcStatus, m.ProcIO)
+	return r, m
+}
+
+func TestProcCollector(t *testing.T) {
+	for _, tc := range []struct {
+		kernel  string
+		missing []string
+	}{
+		{"4.14.0", nil},
+		// VmPMD was removed in 4.15
+		{"5.10.0", []string{"vmpmd"}},
+	} {
+		t.Run(tc.kernel, func(t *testing.T) {
+			assert := assert.New(t)
+
+			c, err := NewProcCollector(filepath.Join("testdata", "proc-"+tc.kernel))
+			assert.NoError(err)
+			assert.Equal(tc.kernel, c.Kernel())
+			assert.Equal(tc.missing, c.MissingStatusFields())
+
+			r, m := newTestProcMetrics()
+			assert.NoError(c.Collect(42, m))
+
+			mfs, err := r.Gather()
+			assert.NoError(err)
+
+			var out bytes.Buffer
+			for _, mf := range mfs {
+				_, err := expfmt.MetricFamilyToText(&out, mf)
+				assert.NoError(err)
+			}
+
+			golden := filepath.Join("testdata", "proc-"+tc.kernel+".golden")
+			if *update {
+				assert.NoError(ioutil.WriteFile(golden, out.Bytes(), 0644))
+			}
+
+			expected, err := ioutil.ReadFile(golden)
+			assert.NoError(err)
+			assert.Equal(string(expected), out.String())
+		})
+	}
+}
+
+func TestProcCollectorMissingProcess(t *testing.T) {
+	assert := assert.New(t)
+
+	c, err := NewProcCollector(filepath.Join("testdata", "proc-5.10.0"))
+	assert.NoError(err)
+
+	_, m := newTestProcMetrics()
+	assert.Error(c.Collect(1, m))
+	assert.NoError(c.CollectSelf(m))
+}
+
+func TestHostProcCollector(t *testing.T) {
+	assert := assert.New(t)
+
+	c, err := HostProcCollector()
+	assert.NoError(err)
+	assert.NotEmpty(c.Kernel())
+
+	_, m := newTestProcMetrics()
+	assert.NoError(c.CollectSelf(m))
+}
@@ -60,6 +60,17 @@ func RunCommand(args []string) (string, error) {
 	return RunCommandFull(args, false)
 }
 
+// RunHostCommand runs a host command and returns its space-trimmed combined
+// output, which is part of the error if the command fails. It is replaced by
+// the tests.
+var RunHostCommand = func(name string, args ...string) (string, error) {
+	out, err := exec.Command(name, args...).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("%s %s failed: %v: %s", name, strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
 // EnsureDir check if a directory exist, if not then create it
 func EnsureDir(path string, mode os.FileMode) error {
 	if !filepath.IsAbs(path) {
@@ -0,0 +1,217 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	"github.com/prometheus/procfs"
+	"github.com/sirupsen/logrus"
+
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/persist"
+	vcTypes "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/types"
+)
+
+// admission modes of the sandboxes not fitting in the host free memory
+const (
+	// AdmissionSoft launches them anyway, and counts them.
+	AdmissionSoft = "soft"
+
+	// AdmissionHard rejects them, and counts them.
+	AdmissionHard = "hard"
+)
+
+// admissionFile keeps the counts of the sandboxes not fitting in the host
+// free memory, next to the sandboxes storage: the shims of the rejected
+// sandboxes exit right away.
+const admissionFile = "admission.json"
+
+// AdmissionConfig is the configuration of the check of the host free
+// memory done before launching the sandbox VM.
+type AdmissionConfig struct {
+	// Mode is how a sandbox not fitting in the host free memory is
+	// handled, the memory is not checked when empty.
+	Mode string
+
+	// MemoryOverheadMiB is the host memory used by the sandbox on top
+	// of the VM memory: the hypervisor, virtiofsd and the shim.
+	MemoryOverheadMiB uint32
+}
+
+// Validate checks the admission configuration.
+func (c AdmissionConfig) Validate() error {
+	switch c.Mode {
+	case "", AdmissionSoft, AdmissionHard:
+		return nil
+	}
+
+	return fmt.Errorf("unknown admission mode %q", c.Mode)
+}
+
+// hostAvailableMemory returns the host memory available to a VM, in MiB:
+// the free huge pages if the VM memory is backed by huge pages.
+var hostAvailableMemory = func(hugePages bool) (uint64, error) {
+	fs, err := procfs.NewDefaultFS()
+	if err != nil {
+		return 0, err
+	}
+
+	mi, err := fs.Meminfo()
+	if err != nil {
+		return 0, err
+	}
+
+	if hugePages {
+		if mi.HugePagesFree == nil || mi.Hugepagesize == nil {
+			return 0, fmt.Errorf("no huge pages information in %s", procMemInfo)
+		}
+		return *mi.HugePagesFree * *mi.Hugepagesize >> 10, nil
+	}
+
+	if mi.MemAvailable == nil {
+		return 0, fmt.Errorf("no available memory information in %s", procMemInfo)
+	}
+	return *mi.MemAvailable >> 10, nil
+}
+
+// checkAdmission checks that the VM memory and the sandbox overhead fit in
+// the host free memory, before launching the VM.
+func (s *Sandbox) checkAdmission() error {
+	c := s.config.AdmissionConfig
+	if c.Mode == "" {
+		return nil
+	}
+
+	available, err := hostAvailableMemory(s.config.HypervisorConfig.HugePages)
+	if err != nil {
+		return fmt.Errorf("Could not get the host available memory: %v", err)
+	}
+
+	required := uint64(s.config.HypervisorConfig.MemorySize) + uint64(c.MemoryOverheadMiB)
+	if required <= available {
+		return nil
+	}
+
+	if err := countAdmissionRejection(c.Mode); err != nil {
+		s.Logger().WithError(err).Warn("Could not count the admission rejection")
+	}
+
+	logger := s.Logger().WithFields(logrus.Fields{
+		"required-mib":  required,
+		"available-mib": available,
+		"mode":          c.Mode,
+	})
+
+	if c.Mode == AdmissionSoft {
+		logger.Warn("Sandbox does not fit in the host free memory, launching it anyway")
+		return nil
+	}
+
+	logger.Error("Sandbox does not fit in the host free memory, rejecting it")
+	return &vcTypes.InsufficientMemoryError{
+		RequiredMiB:  required,
+		AvailableMiB: available,
+	}
+}
+
+// AdmissionRejections are the counts of the sandboxes not fitting in the
+// host free memory since the host boot, by admission mode.
+type AdmissionRejections map[string]uint64
+
+func admissionFilePath() (string, error) {
+	driver, err := persist.GetDriver()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(filepath.Dir(driver.RunStoragePath()), admissionFile), nil
+}
+
+func readAdmissionRejections(f *os.File) (AdmissionRejections, error) {
+	rejections := make(AdmissionRejections)
+
+	data, err := ioutil.ReadAll(f)
+	if err != nil || len(data) == 0 {
+		return rejections, err
+	}
+
+	if err := json.Unmarshal(data, &rejections); err != nil {
+		return nil, err
+	}
+
+	return rejections, nil
+}
+
+// countAdmissionRejection increments the count of the sandboxes not fitting
+// in the host free memory of an admission mode, shared by all the runtimes.
+func countAdmissionRejection(mode string) error {
+	path, err := admissionFilePath()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), DirMode); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX); err != nil {
+		return err
+	}
+	defer syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+
+	rejections, err := readAdmissionRejections(f)
+	if err != nil {
+		return err
+	}
+	rejections[mode]++
+
+	data, err := json.Marshal(rejections)
+	if err != nil {
+		return err
+	}
+
+	if err := f.Truncate(0); err != nil {
+		return err
+	}
+	_, err = f.WriteAt(data, 0)
+	return err
+}
+
+// GetAdmissionRejections returns the counts of the sandboxes not fitting in
+// the host free memory, by admission mode.
+func GetAdmissionRejections() (AdmissionRejections, error) {
+	path, err := admissionFilePath()
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return AdmissionRejections{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_SH); err != nil {
+		return nil, err
+	}
+	defer syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+
+	return readAdmissionRejections(f)
+}
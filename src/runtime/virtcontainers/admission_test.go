@@ -0,0 +1,65 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	vcTypes "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/types"
+)
+
+func TestAdmissionConfigValidate(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.NoError(AdmissionConfig{}.Validate())
+	assert.NoError(AdmissionConfig{Mode: AdmissionSoft}.Validate())
+	assert.NoError(AdmissionConfig{Mode: AdmissionHard, MemoryOverheadMiB: 128}.Validate())
+	assert.Error(AdmissionConfig{Mode: "strict"}.Validate())
+}
+
+func TestCheckAdmission(t *testing.T) {
+	assert := assert.New(t)
+
+	savedHostAvailableMemory := hostAvailableMemory
+	defer func() {
+		hostAvailableMemory = savedHostAvailableMemory
+	}()
+	hostAvailableMemory = func(bool) (uint64, error) {
+		return 2048, nil
+	}
+
+	path, err := admissionFilePath()
+	assert.NoError(err)
+	defer os.Remove(path)
+
+	s := &Sandbox{
+		id: testSandboxID,
+		config: &SandboxConfig{
+			HypervisorConfig: HypervisorConfig{MemorySize: 2048},
+		},
+	}
+
+	// not checked
+	assert.NoError(s.checkAdmission())
+
+	s.config.AdmissionConfig = AdmissionConfig{Mode: AdmissionHard}
+	assert.NoError(s.checkAdmission())
+
+	s.config.AdmissionConfig.MemoryOverheadMiB = 128
+	err = s.checkAdmission()
+	assert.Equal(&vcTypes.InsufficientMemoryError{RequiredMiB: 2176, AvailableMiB: 2048}, err)
+
+	s.config.AdmissionConfig.Mode = AdmissionSoft
+	assert.NoError(s.checkAdmission())
+	assert.NoError(s.checkAdmission())
+
+	rejections, err := GetAdmissionRejections()
+	assert.NoError(err)
+	assert.Equal(AdmissionRejections{AdmissionHard: 1, AdmissionSoft: 2}, rejections)
+}
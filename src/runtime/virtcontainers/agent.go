@@ -53,6 +53,19 @@ type ProcessListOptions struct {
 // ProcessList represents the list of running processes inside the container
 type ProcessList []byte
 
+// MemoryEvent is a container reaching its memory limits in the guest.
+type MemoryEvent struct {
+	ContainerID string
+
+	// Event is the counter of the container cgroup memory.events that
+	// increased, "high" or "max", or "pressure" for the memory pressure
+	// notifications of cgroup v1.
+	Event string
+
+	// Count is the value of the counter.
+	Count uint64
+}
+
 const (
 	// SocketTypeVSOCK is a VSOCK socket type for talking to an agent.
 	SocketTypeVSOCK = "vsock"
@@ -135,8 +148,9 @@ type agent interface {
 	// updateContainer will update the resources of a running container
 	updateContainer(ctx context.Context, sandbox *Sandbox, c Container, resources specs.LinuxResources) error
 
-	// waitProcess will wait for the exit code of a process
-	waitProcess(ctx context.Context, c *Container, processID string) (int32, error)
+	// waitProcess will wait for the exit code of a process, and returns
+	// the record of the session of the exec processes if available
+	waitProcess(ctx context.Context, c *Container, processID string) (int32, *ExecSession, error)
 
 	// onlineCPUMem will online CPUs and Memory inside the Sandbox.
 	// This function should be called after hot adding vCPUs or Memory.
@@ -205,6 +219,24 @@ type agent interface {
 	// Will return the ID of the container where the event occurred.
 	getOOMEvent(ctx context.Context) (string, error)
 
+	// getMemoryEvent will wait on the memory events of the containers
+	// of the sandbox, e.g. a container above its memory.high limit.
+	getMemoryEvent(ctx context.Context) (MemoryEvent, error)
+
 	// getAgentMetrics get metrics of agent and guest through agent
 	getAgentMetrics(context.Context, *grpc.GetMetricsRequest) (*grpc.Metrics, error)
+
+	// getGuestStatus returns the boot time, uptime and clock of the guest,
+	// and the version of the agent.
+	getGuestStatus(ctx context.Context) (*grpc.GuestStatus, error)
+
+	// setNetworkPolicy replaces the nftables ruleset enforcing the
+	// network policy in the guest, an empty ruleset removes it.
+	setNetworkPolicy(ctx context.Context, ruleset string) error
+
+	// setLogLevel changes the log level of the agent.
+	setLogLevel(ctx context.Context, level string) error
+
+	// setDebugConsole starts or stops the debug console of the guest.
+	setDebugConsole(ctx context.Context, enable bool) error
 }
@@ -0,0 +1,102 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"context"
+	"fmt"
+)
+
+// log levels of the agent
+var agentLogLevels = []string{"trace", "debug", "info", "warn", "error", "critical"}
+
+// ValidAgentLogLevel returns true if level is a log level of the agent.
+func ValidAgentLogLevel(level string) bool {
+	for _, l := range agentLogLevels {
+		if l == level {
+			return true
+		}
+	}
+	return false
+}
+
+// SetAgentLogLevel changes the log level of the agent of the running
+// sandbox, e.g. to debug an issue without recreating the pod with the
+// agent.log kernel parameter.
+func (s *Sandbox) SetAgentLogLevel(ctx context.Context, level string) error {
+	if !ValidAgentLogLevel(level) {
+		return fmt.Errorf("invalid agent log level %q", level)
+	}
+
+	s.agentDebugLock.Lock()
+	defer s.agentDebugLock.Unlock()
+
+	err := s.agent.setLogLevel(ctx, level)
+	s.audit.record(ctx, AuditAgentDebug, map[string]interface{}{
+		"action": "set_log_level",
+		"level":  level,
+	}, err)
+	if err != nil {
+		return err
+	}
+
+	s.agentLogLevel = level
+
+	return nil
+}
+
+// GetAgentLogLevel returns the log level of the agent.
+func (s *Sandbox) GetAgentLogLevel() string {
+	s.agentDebugLock.Lock()
+	defer s.agentDebugLock.Unlock()
+
+	if s.agentLogLevel != "" {
+		return s.agentLogLevel
+	}
+	if s.config != nil && s.config.AgentConfig.Debug {
+		return "debug"
+	}
+	return "info"
+}
+
+// SetDebugConsole starts or stops the debug console of the guest, the one
+// kata-runtime exec connects to, without the agent.debug_console kernel
+// parameter. The agent only starts it with the agent.debug_console_control
+// kernel parameter, and it is never started in the confidential guests.
+// The sessions already opened are not closed when the console is stopped.
+func (s *Sandbox) SetDebugConsole(ctx context.Context, enable bool) error {
+	s.agentDebugLock.Lock()
+	defer s.agentDebugLock.Unlock()
+
+	if enable && s.config != nil && s.config.HypervisorConfig.ConfidentialGuest {
+		return fmt.Errorf("the debug console cannot be started in a confidential guest")
+	}
+
+	err := s.agent.setDebugConsole(ctx, enable)
+	s.audit.record(ctx, AuditAgentDebug, map[string]interface{}{
+		"action": "set_debug_console",
+		"enable": enable,
+	}, err)
+	if err != nil {
+		return err
+	}
+
+	s.debugConsole = &enable
+
+	return nil
+}
+
+// DebugConsoleEnabled returns true if the debug console of the guest is
+// running.
+func (s *Sandbox) DebugConsoleEnabled() bool {
+	s.agentDebugLock.Lock()
+	defer s.agentDebugLock.Unlock()
+
+	if s.debugConsole != nil {
+		return *s.debugConsole
+	}
+	return s.config != nil && s.config.AgentConfig.EnableDebugConsole
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetAgentLogLevel(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "agent-debug")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	s := &Sandbox{
+		agent:  &mockAgent{},
+		config: &SandboxConfig{AgentConfig: KataAgentConfig{Debug: true}},
+		audit:  newAuditLog("sb", dir, AuditConfig{Enable: true}),
+	}
+	assert.Equal("debug", s.GetAgentLogLevel())
+
+	assert.Error(s.SetAgentLogLevel(context.Background(), "verbose"))
+	assert.NoError(s.SetAgentLogLevel(context.Background(), "warn"))
+	assert.Equal("warn", s.GetAgentLogLevel())
+
+	records, err := s.audit.records()
+	assert.NoError(err)
+	assert.Len(records, 1)
+	assert.Equal(AuditAgentDebug, records[0].Operation)
+	assert.Equal("set_log_level", records[0].Params["action"])
+	assert.Equal("warn", records[0].Params["level"])
+}
+
+func TestSetDebugConsole(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "agent-debug")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	s := &Sandbox{
+		agent:  &mockAgent{},
+		config: &SandboxConfig{},
+		audit:  newAuditLog("sb", dir, AuditConfig{Enable: true}),
+	}
+	assert.False(s.DebugConsoleEnabled())
+
+	assert.NoError(s.SetDebugConsole(context.Background(), true))
+	assert.True(s.DebugConsoleEnabled())
+
+	assert.NoError(s.SetDebugConsole(context.Background(), false))
+	assert.False(s.DebugConsoleEnabled())
+
+	records, err := s.audit.records()
+	assert.NoError(err)
+	assert.Len(records, 2)
+	assert.Equal("set_debug_console", records[0].Params["action"])
+	assert.Equal(true, records[0].Params["enable"])
+
+	// never in a confidential guest
+	s.config.HypervisorConfig.ConfidentialGuest = true
+	assert.Error(s.SetDebugConsole(context.Background(), true))
+	assert.False(s.DebugConsoleEnabled())
+	assert.NoError(s.SetDebugConsole(context.Background(), false))
+}
@@ -0,0 +1,169 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"reflect"
+
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/agent/protocols/grpc"
+)
+
+const (
+	agentPolicyAllow = "allow"
+	agentPolicyDeny  = "deny"
+
+	// agentPolicyAnyRequest matches all the agent requests
+	agentPolicyAnyRequest = "*"
+)
+
+// agentPolicy is evaluated before sending each request to the agent,
+// a non nil error denies the request.
+type agentPolicy interface {
+	evaluate(msgName string, req interface{}) error
+}
+
+// agentPolicyRule matches agent requests by name (e.g. grpc.ExecProcessRequest),
+// and optionally by container and executed command.
+type agentPolicyRule struct {
+	Request string `json:"request"`
+	Action  string `json:"action"`
+
+	// Containers restricts the rule to the requests targeting these containers.
+	Containers []string `json:"containers,omitempty"`
+
+	// Commands restricts the rule to the processes executing these
+	// binaries (ExecProcessRequest only), as given by the first argument of
+	// the process. The match is advisory: the binary is not resolved in
+	// the guest, e.g. "sh" or a link to /bin/sh executes /bin/sh too, and
+	// a shell or an interpreter runs any command. The rules denying some
+	// commands are thus easily bypassed, allow the known commands instead
+	// and deny the others.
+	Commands []string `json:"commands,omitempty"`
+}
+
+// filePolicy is an agentPolicy loaded from a local JSON file. The first
+// rule matching a request decides, DefaultAction applies otherwise.
+type filePolicy struct {
+	path string
+
+	Rules         []agentPolicyRule `json:"rules"`
+	DefaultAction string            `json:"default_action"`
+}
+
+func loadFilePolicy(path string) (*filePolicy, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &filePolicy{
+		path:          path,
+		DefaultAction: agentPolicyAllow,
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return nil, fmt.Errorf("invalid agent policy %s: %v", path, err)
+	}
+
+	if err := validPolicyAction(p.DefaultAction); err != nil {
+		return nil, fmt.Errorf("invalid agent policy %s: %v", path, err)
+	}
+
+	for i, r := range p.Rules {
+		if r.Request == "" {
+			return nil, fmt.Errorf("invalid agent policy %s: rule %d has no request", path, i)
+		}
+		if err := validPolicyAction(r.Action); err != nil {
+			return nil, fmt.Errorf("invalid agent policy %s: rule %d: %v", path, i, err)
+		}
+	}
+
+	return p, nil
+}
+
+func validPolicyAction(action string) error {
+	if action != agentPolicyAllow && action != agentPolicyDeny {
+		return fmt.Errorf("unknown action %q", action)
+	}
+	return nil
+}
+
+func (p *filePolicy) evaluate(msgName string, req interface{}) error {
+	// the requests of a batch are evaluated as if they were sent one by one
+	if batch, ok := req.(*grpc.CreateContainerBatchRequest); ok {
+		for _, f := range batch.Files {
+			if err := p.evaluate(grpcCopyFileRequest, f); err != nil {
+				return err
+			}
+		}
+		if err := p.evaluate(grpcCreateContainerRequest, batch.Container); err != nil {
+			return err
+		}
+	}
+
+	action := p.DefaultAction
+	for _, r := range p.Rules {
+		if r.match(msgName, req) {
+			action = r.Action
+			break
+		}
+	}
+
+	if action == agentPolicyDeny {
+		return fmt.Errorf("agent request %s denied by policy %s", msgName, p.path)
+	}
+
+	return nil
+}
+
+func (r agentPolicyRule) match(msgName string, req interface{}) bool {
+	if r.Request != agentPolicyAnyRequest && r.Request != msgName {
+		return false
+	}
+
+	if len(r.Containers) > 0 && !containsString(r.Containers, requestContainerID(req)) {
+		return false
+	}
+
+	if len(r.Commands) > 0 {
+		exec, ok := req.(*grpc.ExecProcessRequest)
+		if !ok || exec.Process == nil || len(exec.Process.Args) == 0 {
+			return false
+		}
+		if !containsString(r.Commands, path.Clean(exec.Process.Args[0])) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// requestContainerID returns the ContainerId of the request, if any.
+func requestContainerID(req interface{}) string {
+	v := reflect.Indirect(reflect.ValueOf(req))
+	if v.Kind() != reflect.Struct {
+		return ""
+	}
+
+	f := v.FieldByName("ContainerId")
+	if !f.IsValid() || f.Kind() != reflect.String {
+		return ""
+	}
+
+	return f.String()
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/agent/protocols/grpc"
+	"github.com/stretchr/testify/assert"
+)
+
+func writePolicy(t *testing.T, dir, content string) string {
+	path := filepath.Join(dir, "policy.json")
+	assert.NoError(t, ioutil.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func TestLoadFilePolicy(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "policy")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	_, err = loadFilePolicy(filepath.Join(dir, "missing.json"))
+	assert.Error(err)
+
+	for _, content := range []string{
+		`not json`,
+		`{"default_action": "maybe"}`,
+		`{"rules": [{"action": "deny"}]}`,
+		`{"rules": [{"request": "grpc.ExecProcessRequest", "action": "drop"}]}`,
+	} {
+		_, err = loadFilePolicy(writePolicy(t, dir, content))
+		assert.Error(err, content)
+	}
+
+	p, err := loadFilePolicy(writePolicy(t, dir, `{"rules": [{"request": "*", "action": "deny"}]}`))
+	assert.NoError(err)
+	assert.Equal(agentPolicyAllow, p.DefaultAction)
+	assert.Len(p.Rules, 1)
+}
+
+func TestFilePolicyEvaluate(t *testing.T) {
+	assert := assert.New(t)
+
+	p := &filePolicy{
+		DefaultAction: agentPolicyAllow,
+		Rules: []agentPolicyRule{
+			{Request: "grpc.ExecProcessRequest", Containers: []string{"trusted"}, Action: agentPolicyAllow},
+			{Request: "grpc.ExecProcessRequest", Commands: []string{"/bin/sh"}, Action: agentPolicyDeny},
+			{Request: "grpc.UpdateRoutesRequest", Action: agentPolicyDeny},
+		},
+	}
+
+	exec := func(containerID string, args ...string) *grpc.ExecProcessRequest {
+		return &grpc.ExecProcessRequest{
+			ContainerId: containerID,
+			Process:     &grpc.Process{Args: args},
+		}
+	}
+
+	assert.NoError(p.evaluate("grpc.ExecProcessRequest", exec("trusted", "/bin/sh")))
+	assert.Error(p.evaluate("grpc.ExecProcessRequest", exec("c1", "/bin/sh")))
+	assert.Error(p.evaluate("grpc.ExecProcessRequest", exec("c1", "//bin/./sh")))
+	assert.NoError(p.evaluate("grpc.ExecProcessRequest", exec("c1", "/bin/ls")))
+	assert.NoError(p.evaluate("grpc.ExecProcessRequest", &grpc.ExecProcessRequest{ContainerId: "c1"}))
+	assert.Error(p.evaluate("grpc.UpdateRoutesRequest", &grpc.UpdateRoutesRequest{}))
+	assert.NoError(p.evaluate("grpc.CheckRequest", &grpc.CheckRequest{}))
+
+	// the requests of a batch are evaluated one by one
+	p.Rules = append(p.Rules, agentPolicyRule{Request: "grpc.CreateContainerRequest", Containers: []string{"c2"}, Action: agentPolicyDeny})
+	batch := func(containerID string) *grpc.CreateContainerBatchRequest {
+		return &grpc.CreateContainerBatchRequest{
+			Files:     []*grpc.CopyFileRequest{{Path: "/run/kata-containers/shared/containers/f"}},
+			Container: &grpc.CreateContainerRequest{ContainerId: containerID},
+		}
+	}
+	assert.NoError(p.evaluate("grpc.CreateContainerBatchRequest", batch("c1")))
+	assert.Error(p.evaluate("grpc.CreateContainerBatchRequest", batch("c2")))
+
+	p.DefaultAction = agentPolicyDeny
+	assert.Error(p.evaluate("grpc.CheckRequest", &grpc.CheckRequest{}))
+}
+
+func TestRequestContainerID(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("c1", requestContainerID(&grpc.SignalProcessRequest{ContainerId: "c1"}))
+	assert.Empty(requestContainerID(&grpc.CheckRequest{}))
+	assert.Empty(requestContainerID(nil))
+}
@@ -63,6 +63,11 @@ func createSandboxFromConfig(ctx context.Context, sandboxConfig SandboxConfig, f
 		return nil, err
 	}
 
+	// Nothing is launched for a dry run.
+	if sandboxConfig.DryRun {
+		return s, nil
+	}
+
 	// cleanup sandbox resources in case of any failure
 	defer func() {
 		if err != nil {
@@ -0,0 +1,238 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/device/api"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/device/config"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/cloudevent"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	auditLogFile = "audit.log"
+
+	// type of the CloudEvents posted to the audit sink
+	auditEventType = "io.katacontainers.audit"
+
+	auditSinkTimeout = 5 * time.Second
+)
+
+// privileged operations recorded in the audit log
+const (
+	AuditDeviceHotplug   = "device_hotplug"
+	AuditDeviceHotunplug = "device_hotunplug"
+	AuditMount           = "mount"
+	AuditExec            = "exec"
+	AuditExecSession     = "exec_session"
+	AuditNetworkUpdate   = "network_update"
+	AuditAgentDebug      = "agent_debug"
+)
+
+// AuditConfig is the audit log configuration of a sandbox.
+type AuditConfig struct {
+	// Enable records the privileged operations of the sandbox.
+	Enable bool
+
+	// Dir is the directory holding the audit log of the sandbox, as
+	// <Dir>/<sandbox id>/audit.log. The sandbox run storage path is
+	// used when empty, in which case the log is removed along with
+	// the sandbox.
+	Dir string
+
+	// Sink is the URL where each record is posted as a CloudEvent,
+	// in addition to the audit log file.
+	Sink string
+}
+
+// AuditRecord is an entry of the sandbox audit log.
+type AuditRecord struct {
+	Time      time.Time              `json:"time"`
+	Sandbox   string                 `json:"sandbox"`
+	Actor     string                 `json:"actor"`
+	Operation string                 `json:"operation"`
+	Params    map[string]interface{} `json:"params,omitempty"`
+	Error     string                 `json:"error,omitempty"`
+}
+
+// ExecSession is the record of an exec process of a container, reported
+// by the agent when the process exits.
+type ExecSession struct {
+	// UID and GID are the user the process ran as in the container.
+	UID      uint32
+	GID      uint32
+	Args     []string
+	Terminal bool
+	Duration time.Duration
+}
+
+var auditActor = fmt.Sprintf("%s[%d]", filepath.Base(os.Args[0]), os.Getpid())
+
+// auditLog is the append-only log of the privileged
+// operations performed for a sandbox.
+type auditLog struct {
+	sync.Mutex
+	sandboxID string
+	path      string
+	sink      string
+	client    *http.Client
+}
+
+func newAuditLog(sandboxID, runStoragePath string, config AuditConfig) *auditLog {
+	if !config.Enable {
+		return nil
+	}
+
+	dir := config.Dir
+	if dir == "" {
+		dir = runStoragePath
+	}
+
+	return &auditLog{
+		sandboxID: sandboxID,
+		path:      filepath.Join(dir, sandboxID, auditLogFile),
+		sink:      config.Sink,
+		client:    &http.Client{Timeout: auditSinkTimeout},
+	}
+}
+
+func (a *auditLog) logger() *logrus.Entry {
+	return virtLog.WithFields(logrus.Fields{
+		"subsystem": "audit",
+		"sandbox":   a.sandboxID,
+	})
+}
+
+// record appends an operation and its result to the audit log, the posted
+// event carries the trace context of ctx. Failing to record is logged only,
+// it does not fail the operation itself.
+func (a *auditLog) record(ctx context.Context, operation string, params map[string]interface{}, opErr error) {
+	if a == nil {
+		return
+	}
+
+	r := AuditRecord{
+		Time:      time.Now().UTC(),
+		Sandbox:   a.sandboxID,
+		Actor:     auditActor,
+		Operation: operation,
+		Params:    params,
+	}
+	if opErr != nil {
+		r.Error = opErr.Error()
+	}
+
+	if err := a.write(r); err != nil {
+		a.logger().WithError(err).WithField("operation", operation).Error("failed to write audit record")
+	}
+
+	if a.sink != "" {
+		go a.post(ctx, r)
+	}
+}
+
+func (a *auditLog) write(r AuditRecord) error {
+	line, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	a.Lock()
+	defer a.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(a.path), DirMode); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(a.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
+
+func (a *auditLog) post(ctx context.Context, r AuditRecord) {
+	e, err := cloudevent.New(fmt.Sprintf("/kata-containers/sandbox/%s", a.sandboxID), auditEventType, r.Time, r)
+	if err != nil {
+		a.logger().WithError(err).Error("failed to generate audit event")
+		return
+	}
+	e.SetTraceContext(ctx)
+
+	if err := cloudevent.Deliver(a.client, a.sink, e, cloudevent.DefaultRetries); err != nil {
+		a.logger().WithError(err).WithField("sink", a.sink).Warn("failed to post audit event")
+	}
+}
+
+// records returns the records of the audit log.
+func (a *auditLog) records() ([]AuditRecord, error) {
+	a.Lock()
+	defer a.Unlock()
+
+	f, err := os.Open(a.path)
+	if os.IsNotExist(err) {
+		return []AuditRecord{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	records := []AuditRecord{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var r AuditRecord
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			return nil, err
+		}
+		records = append(records, r)
+	}
+
+	return records, scanner.Err()
+}
+
+// GetAuditLog returns the records of the sandbox audit log.
+func (s *Sandbox) GetAuditLog() ([]AuditRecord, error) {
+	if s.audit == nil {
+		return nil, fmt.Errorf("audit log is not enabled")
+	}
+
+	return s.audit.records()
+}
+
+func deviceAuditParams(device api.Device, devType config.DeviceType) map[string]interface{} {
+	return map[string]interface{}{
+		"device":    device.DeviceID(),
+		"type":      string(devType),
+		"host_path": device.GetHostPath(),
+	}
+}
+
+func execSessionAuditParams(containerID, execID string, exitCode int32, session *ExecSession) map[string]interface{} {
+	return map[string]interface{}{
+		"container":   containerID,
+		"exec_id":     execID,
+		"uid":         session.UID,
+		"gid":         session.GID,
+		"args":        session.Args,
+		"terminal":    session.Terminal,
+		"duration_ms": session.Duration.Milliseconds(),
+		"exit_code":   exitCode,
+	}
+}
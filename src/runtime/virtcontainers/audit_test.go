@@ -0,0 +1,167 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/cloudevent"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAuditLog(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Nil(newAuditLog("sb", "/run/vc/sbs", AuditConfig{}))
+
+	a := newAuditLog("sb", "/run/vc/sbs", AuditConfig{Enable: true})
+	assert.Equal("/run/vc/sbs/sb/audit.log", a.path)
+
+	a = newAuditLog("sb", "/run/vc/sbs", AuditConfig{Enable: true, Dir: "/var/log/audit"})
+	assert.Equal("/var/log/audit/sb/audit.log", a.path)
+
+	// recording to a disabled audit log is a no-op
+	var disabled *auditLog
+	disabled.record(context.Background(), AuditExec, nil, nil)
+}
+
+func TestAuditLogRecords(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "audit")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	a := newAuditLog("sb", dir, AuditConfig{Enable: true})
+
+	records, err := a.records()
+	assert.NoError(err)
+	assert.Empty(records)
+
+	a.record(context.Background(), AuditExec, map[string]interface{}{"container": "c1"}, nil)
+	a.record(context.Background(), AuditDeviceHotplug, nil, errors.New("hotplug failed"))
+
+	records, err = a.records()
+	assert.NoError(err)
+	assert.Len(records, 2)
+
+	assert.Equal("sb", records[0].Sandbox)
+	assert.Equal(auditActor, records[0].Actor)
+	assert.Equal(AuditExec, records[0].Operation)
+	assert.Equal("c1", records[0].Params["container"])
+	assert.Empty(records[0].Error)
+
+	assert.Equal(AuditDeviceHotplug, records[1].Operation)
+	assert.Equal("hotplug failed", records[1].Error)
+
+	s := &Sandbox{audit: a}
+	records, err = s.GetAuditLog()
+	assert.NoError(err)
+	assert.Len(records, 2)
+
+	s = &Sandbox{}
+	_, err = s.GetAuditLog()
+	assert.Error(err)
+}
+
+func TestAuditLogSink(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "audit")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	type auditEvent struct {
+		cloudevent.Event
+		Data AuditRecord `json:"data"`
+	}
+
+	events := make(chan auditEvent, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var e auditEvent
+		if r.Header.Get("Content-Type") == cloudevent.ContentType {
+			json.NewDecoder(r.Body).Decode(&e)
+		}
+		events <- e
+	}))
+	defer srv.Close()
+
+	a := newAuditLog("sb", dir, AuditConfig{Enable: true, Sink: srv.URL})
+	a.record(context.Background(), AuditMount, map[string]interface{}{"source": "/src"}, nil)
+
+	select {
+	case e := <-events:
+		assert.Equal(cloudevent.SpecVersion, e.SpecVersion)
+		assert.Equal(auditEventType, e.Type)
+		assert.NotEmpty(e.ID)
+		assert.Equal(AuditMount, e.Data.Operation)
+		assert.Equal("/src", e.Data.Params["source"])
+	case <-time.After(auditSinkTimeout):
+		assert.Fail("audit event not received")
+	}
+}
+
+type execSessionAgent struct {
+	mockAgent
+	session *ExecSession
+}
+
+func (a *execSessionAgent) waitProcess(ctx context.Context, c *Container, processID string) (int32, *ExecSession, error) {
+	return 3, a.session, nil
+}
+
+func TestAuditExecSession(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "audit")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	agent := &execSessionAgent{}
+	c := &Container{
+		id:    "c1",
+		state: types.ContainerState{State: types.StateRunning},
+		sandbox: &Sandbox{
+			agent: agent,
+			audit: newAuditLog("sb", dir, AuditConfig{Enable: true}),
+		},
+	}
+
+	// the init process has no session
+	status, err := c.wait(context.Background(), "c1")
+	assert.NoError(err)
+	assert.Equal(int32(3), status)
+
+	agent.session = &ExecSession{
+		UID:      1000,
+		GID:      100,
+		Args:     []string{"sh"},
+		Terminal: true,
+		Duration: 90 * time.Second,
+	}
+	_, err = c.wait(context.Background(), "e1")
+	assert.NoError(err)
+
+	records, err := c.sandbox.audit.records()
+	assert.NoError(err)
+	assert.Len(records, 1)
+	assert.Equal(AuditExecSession, records[0].Operation)
+	assert.Equal("e1", records[0].Params["exec_id"])
+	assert.Equal(float64(1000), records[0].Params["uid"])
+	assert.Equal([]interface{}{"sh"}, records[0].Params["args"])
+	assert.Equal(true, records[0].Params["terminal"])
+	assert.Equal(float64(90000), records[0].Params["duration_ms"])
+	assert.Equal(float64(3), records[0].Params["exit_code"])
+}
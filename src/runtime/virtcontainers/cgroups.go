@@ -15,6 +15,8 @@ import (
 
 	"github.com/containerd/cgroups"
 	specs "github.com/opencontainers/runtime-spec/specs-go"
+
+	vccgroups "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/cgroups"
 )
 
 type cgroupPather interface {
@@ -119,11 +121,33 @@ func cgroupV1MountPoint() (string, error) {
 }
 
 func cgroupNoConstraintsPath(path string) string {
+	// the no constraints cgroup is managed by the runtime, not by
+	// systemd: a systemd path is only used for its name
+	if fsPath, err := vccgroups.CgroupfsPath(path); err == nil {
+		path = fsPath
+	}
 	return filepath.Join(cgroupKataPath, path)
 }
 
+// staticCgroupPath returns the path of the given cgroup, a systemd path is
+// expanded to the path of its unit.
+func staticCgroupPath(path string) cgroups.Path {
+	fsPath, err := vccgroups.CgroupfsPath(path)
+	if err != nil {
+		return func(cgroups.Name) (string, error) {
+			return "", err
+		}
+	}
+	return cgroups.StaticPath(fsPath)
+}
+
 // return the parent cgroup for the given path
 func parentCgroup(hierarchy cgroups.Hierarchy, path string) (cgroups.Cgroup, error) {
+	path, err := vccgroups.CgroupfsPath(path)
+	if err != nil {
+		return nil, err
+	}
+
 	// append '/' just in case CgroupsPath doesn't start with it
 	parent := filepath.Dir("/" + path)
 
@@ -29,6 +29,7 @@ import (
 	"github.com/kata-containers/kata-containers/src/runtime/pkg/katautils/katatrace"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/device/config"
 	vcTypes "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/types"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/vmmsandbox"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/types"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/utils"
 )
@@ -71,6 +72,7 @@ const (
 	clhStopSandboxTimeout = 3
 	clhSocket             = "clh.sock"
 	clhAPISocket          = "clh-api.sock"
+	clhAPIPath            = "/api/v1"
 	virtioFsSocket        = "virtiofsd.sock"
 	defaultClhPath        = "/usr/local/bin/cloud-hypervisor"
 	virtioFsCacheAlways   = "always"
@@ -94,6 +96,8 @@ type clhClient interface {
 	BootVM(ctx context.Context) (*http.Response, error)
 	// Add/remove CPUs to/from the VM
 	VmResizePut(ctx context.Context, vmResize chclient.VmResize) (*http.Response, error)
+	// Add memory to a memory zone of the VM
+	VmResizeZonePut(ctx context.Context, vmResizeZone chclient.VmResizeZone) (*http.Response, error)
 	// Add VFIO PCI device to the VM
 	VmAddDevicePut(ctx context.Context, vmAddDevice chclient.VmAddDevice) (chclient.PciDeviceInfo, *http.Response, error)
 	// Add a new disk device to the VM
@@ -110,6 +114,9 @@ type CloudHypervisorState struct {
 	PID          int
 	VirtiofsdPID int
 	apiSocket    string
+	// invocation is the command line of cloud-hypervisor and the API
+	// calls creating and booting the VM
+	invocation HypervisorInvocation
 }
 
 func (s *CloudHypervisorState) reset() {
@@ -128,6 +135,10 @@ type cloudHypervisor struct {
 	virtiofsd Virtiofsd
 	store     persistapi.PersistDriver
 	console   console.Console
+
+	// extraFiles are the files inherited by cloud-hypervisor,
+	// e.g. the pre-opened tap fds of the network devices.
+	extraFiles []*os.File
 }
 
 var clhKernelParams = []Param{
@@ -164,6 +175,10 @@ func (clh *cloudHypervisor) createSandbox(ctx context.Context, id string, networ
 		return err
 	}
 
+	if err := checkClhCPUConfig(hypervisorConfig, procCPUInfo); err != nil {
+		return err
+	}
+
 	clh.id = id
 	clh.config = *hypervisorConfig
 	clh.state.state = clhNotReady
@@ -182,6 +197,8 @@ func (clh *cloudHypervisor) createSandbox(ctx context.Context, id string, networ
 			sourcePath: filepath.Join(getSharePath(clh.id)),
 			debug:      clh.config.Debug,
 			socketPath: virtiofsdSocketPath,
+			sandboxID:  clh.id,
+			ids:        clh.config.VirtioFSDaemonIDs,
 		}
 		return nil
 	}
@@ -209,6 +226,10 @@ func (clh *cloudHypervisor) createSandbox(ctx context.Context, id string, networ
 		MaxVcpus:  int32(clh.config.DefaultMaxVCPUs),
 	}
 
+	if len(clh.config.NUMANodes) > 0 {
+		clh.setNUMAConfig(utils.MemUnit(hostMemKb) * utils.KiB)
+	}
+
 	// Add the kernel path
 	kernelPath, err := clh.config.KernelAssetPath()
 	if err != nil {
@@ -288,6 +309,8 @@ func (clh *cloudHypervisor) createSandbox(ctx context.Context, id string, networ
 		extraArgs:  clh.config.VirtioFSExtraArgs,
 		debug:      clh.config.Debug,
 		cache:      clh.config.VirtioFSCache,
+		sandboxID:  clh.id,
+		ids:        clh.config.VirtioFSDaemonIDs,
 	}
 
 	if clh.config.SGXEPCSize > 0 {
@@ -303,7 +326,7 @@ func (clh *cloudHypervisor) createSandbox(ctx context.Context, id string, networ
 }
 
 // startSandbox will start the VMM and boot the virtual machine for the given sandbox.
-func (clh *cloudHypervisor) startSandbox(ctx context.Context, timeout int) error {
+func (clh *cloudHypervisor) startSandbox(ctx context.Context, timeout int) (err error) {
 	span, _ := katatrace.Trace(ctx, clh.Logger(), "startSandbox", clh.tracingTags())
 	defer span.End()
 
@@ -313,15 +336,28 @@ func (clh *cloudHypervisor) startSandbox(ctx context.Context, timeout int) error
 	clh.Logger().WithField("function", "startSandbox").Info("starting Sandbox")
 
 	vmPath := filepath.Join(clh.store.RunVMStoragePath(), clh.id)
-	err := os.MkdirAll(vmPath, DirMode)
+	err = os.MkdirAll(vmPath, DirMode)
 	if err != nil {
 		return err
 	}
+	if err = labelSandboxPath(&clh.config, vmPath); err != nil {
+		return err
+	}
 
 	if clh.virtiofsd == nil {
 		return errors.New("Missing virtiofsd configuration")
 	}
 
+	cred, err := clh.config.hypervisorCredential(clh.id, vmPath)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			clh.config.releaseHypervisorID(clh.id)
+		}
+	}()
+
 	// This needs to be done as late as possible, just before launching
 	// virtiofsd are executed by kata-runtime after this call, run with
 	// the SELinux label. If these processes require privileged, we do
@@ -344,7 +380,7 @@ func (clh *cloudHypervisor) startSandbox(ctx context.Context, timeout int) error
 		return errors.New("cloud-hypervisor only supports virtio based file sharing")
 	}
 
-	pid, err := clh.launchClh()
+	pid, err := clh.launchClh(cred)
 	if err != nil {
 		if shutdownErr := clh.virtiofsd.Stop(ctx); shutdownErr != nil {
 			clh.Logger().WithError(shutdownErr).Warn("error shutting down Virtiofsd")
@@ -390,6 +426,43 @@ func (clh *cloudHypervisor) getThreadIDs(ctx context.Context) (vcpuThreadIDs, er
 	return vcpuInfo, nil
 }
 
+// checkClhCPUConfig checks the guest cpu configuration can be honored:
+// cloud-hypervisor always exposes the host cpu model to the guest, so the
+// requested features must be available on the host.
+func checkClhCPUConfig(config *HypervisorConfig, cpuInfoPath string) error {
+	if config.CPUModel != "" && config.CPUModel != defaultCPUModel {
+		return fmt.Errorf("cloud-hypervisor does not support the %q cpu model, only %q is supported", config.CPUModel, defaultCPUModel)
+	}
+
+	enabled, disabled := parseCPUFeatures(config.CPUFeatures)
+	if len(disabled) > 0 {
+		return fmt.Errorf("cloud-hypervisor does not support disabling cpu features: %s", strings.Join(disabled, ","))
+	}
+
+	if len(enabled) == 0 {
+		return nil
+	}
+
+	flags, err := CPUFlags(cpuInfoPath)
+	if err != nil {
+		return fmt.Errorf("failed to get the host cpu features: %v", err)
+	}
+
+	var missing []string
+	for _, f := range enabled {
+		// QEMU feature names use dashes where cpuinfo uses underscores
+		if !flags[f] && !flags[strings.Replace(f, "-", "_", -1)] {
+			missing = append(missing, f)
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("cpu features not available on the host: %s", strings.Join(missing, ","))
+	}
+
+	return nil
+}
+
 func clhDriveIndexToID(i int) string {
 	return "clh_drive_" + strconv.Itoa(i)
 }
@@ -509,6 +582,86 @@ func (clh *cloudHypervisor) hotplugRemoveDevice(ctx context.Context, devInfo int
 	return nil, err
 }
 
+// clhMemoryZoneID returns the id of the memory zone of a guest NUMA node.
+func clhMemoryZoneID(node int) string {
+	return fmt.Sprintf("mem%d", node)
+}
+
+// setNUMAConfig splits the VM memory in a memory zone per guest NUMA node, the
+// memory hotplugged in a zone is local to its node. The zones are resized with
+// virtio-mem.
+func (clh *cloudHypervisor) setNUMAConfig(hotplugSize utils.MemUnit) {
+	zoneHotplugSize := hotplugSize / utils.MemUnit(len(clh.config.NUMANodes))
+
+	// the memory of the VM is the one of its zones
+	clh.vmconfig.Memory.Size = 0
+	clh.vmconfig.Memory.HotplugSize = 0
+	clh.vmconfig.Memory.HotplugMethod = "virtio-mem"
+	clh.vmconfig.Memory.Zones = nil
+	clh.vmconfig.Numa = nil
+
+	for i, node := range clh.config.NUMANodes {
+		id := clhMemoryZoneID(i)
+
+		// OpenAPI only supports int64 values
+		clh.vmconfig.Memory.Zones = append(clh.vmconfig.Memory.Zones, chclient.MemoryZoneConfig{
+			Id:          id,
+			Size:        int64((utils.MemUnit(node.MemorySize) * utils.MiB).ToBytes()),
+			Shared:      true,
+			HotplugSize: int64(zoneHotplugSize.ToBytes()),
+		})
+
+		cpus := make([]int32, len(node.VCPUs))
+		for j, vcpu := range node.VCPUs {
+			cpus[j] = int32(vcpu)
+		}
+		clh.vmconfig.Numa = append(clh.vmconfig.Numa, chclient.NumaConfig{
+			GuestNumaId: int32(i),
+			Cpus:        cpus,
+			MemoryZones: []string{id},
+		})
+	}
+}
+
+// resizeMemoryZones hotplugs memory in the zones of the guest NUMA nodes,
+// block by block in the zone with the least memory.
+func (clh *cloudHypervisor) resizeMemoryZones(ctx context.Context, zones []chclient.MemoryZoneConfig, hotplugSize utils.MemUnit, blockSize utils.MemUnit) error {
+	if blockSize == 0 {
+		blockSize = hotplugSize
+	}
+
+	sizes := make([]utils.MemUnit, len(zones))
+	for i, zone := range zones {
+		sizes[i] = utils.MemUnit(zone.Size+zone.HotpluggedSize) * utils.Byte
+	}
+	desired := append([]utils.MemUnit{}, sizes...)
+
+	for added := utils.MemUnit(0); added < hotplugSize; added += blockSize {
+		smallest := 0
+		for i := range desired {
+			if desired[i] < desired[smallest] {
+				smallest = i
+			}
+		}
+		desired[smallest] += blockSize
+	}
+
+	cl := clh.client()
+	for i, zone := range zones {
+		if desired[i] == sizes[i] {
+			continue
+		}
+
+		clh.Logger().WithFields(log.Fields{"zone": zone.Id, "current-memory": sizes[i], "new-memory": desired[i]}).Debug("updating VM memory zone")
+		// OpenApi does not support uint64, convert to int64
+		if _, err := cl.VmResizeZonePut(ctx, chclient.VmResizeZone{Id: zone.Id, DesiredRam: int64(desired[i].ToBytes())}); err != nil {
+			return fmt.Errorf("Failed to resize memory zone %s from %d to %d: %s", zone.Id, sizes[i], desired[i], openAPIClientError(err))
+		}
+	}
+
+	return nil
+}
+
 func (clh *cloudHypervisor) hypervisorConfig() HypervisorConfig {
 	return clh.config
 }
@@ -532,6 +685,9 @@ func (clh *cloudHypervisor) resizeMemory(ctx context.Context, reqMemMB uint32, m
 	}
 
 	currentMem := utils.MemUnit(info.Config.Memory.Size) * utils.Byte
+	for _, zone := range info.Config.Memory.Zones {
+		currentMem += utils.MemUnit(zone.Size+zone.HotpluggedSize) * utils.Byte
+	}
 	newMem := utils.MemUnit(reqMemMB) * utils.MiB
 
 	// Early check to verify if boot memory is the same as requested
@@ -566,6 +722,15 @@ func (clh *cloudHypervisor) resizeMemory(ctx context.Context, reqMemMB uint32, m
 	ctx, cancelResize := context.WithTimeout(ctx, clhAPITimeout*time.Second)
 	defer cancelResize()
 
+	// the memory is hotplugged in the zones of the guest NUMA nodes
+	if len(info.Config.Memory.Zones) > 0 {
+		if err := clh.resizeMemoryZones(ctx, info.Config.Memory.Zones, hotplugSize, blockSize); err != nil {
+			clh.Logger().WithError(err).WithFields(log.Fields{"current-memory": currentMem, "new-memory": newMem}).Warn("failed to update memory")
+			return uint32(currentMem.ToMiB()), memoryDevice{}, err
+		}
+		return uint32(newMem.ToMiB()), memoryDevice{sizeMB: int(hotplugSize.ToMiB())}, nil
+	}
+
 	// OpenApi does not support uint64, convert to int64
 	resize := chclient.VmResize{DesiredRam: int64(newMem.ToBytes())}
 	clh.Logger().WithFields(log.Fields{"current-memory": currentMem, "new-memory": newMem}).Debug("updating VM memory")
@@ -659,6 +824,7 @@ func (clh *cloudHypervisor) save() (s persistapi.HypervisorState) {
 	s.Type = string(ClhHypervisor)
 	s.VirtiofsdPid = clh.state.VirtiofsdPID
 	s.APISocket = clh.state.apiSocket
+	s.Invocation = clh.state.invocation.save()
 	return
 }
 
@@ -666,6 +832,7 @@ func (clh *cloudHypervisor) load(s persistapi.HypervisorState) {
 	clh.state.PID = s.Pid
 	clh.state.VirtiofsdPID = s.VirtiofsdPid
 	clh.state.apiSocket = s.APISocket
+	clh.state.invocation = loadHypervisorInvocation(s.Invocation)
 }
 
 func (clh *cloudHypervisor) check() error {
@@ -731,6 +898,9 @@ func (clh *cloudHypervisor) capabilities(ctx context.Context) types.Capabilities
 	var caps types.Capabilities
 	caps.SetFsSharingSupport()
 	caps.SetBlockDeviceHotplugSupport()
+	caps.SetGuestNUMASupport()
+	// the tap queues are opened by the runtime and passed as fds
+	caps.SetMultiQueueSupport()
 	return caps
 }
 
@@ -839,13 +1009,23 @@ func (clh *cloudHypervisor) clhPath() (string, error) {
 	return p, err
 }
 
-func (clh *cloudHypervisor) launchClh() (int, error) {
+func (clh *cloudHypervisor) launchClh(cred *vmmsandbox.Credential) (int, error) {
 
 	clhPath, err := clh.clhPath()
 	if err != nil {
 		return -1, err
 	}
 
+	if clh.config.SharedFS == config.VirtioFS {
+		socketPath, err := clh.virtioFsSocketPath(clh.id)
+		if err != nil {
+			return -1, err
+		}
+		if err := shareSocketWithHypervisor(socketPath, cred); err != nil {
+			return -1, err
+		}
+	}
+
 	args := []string{cscAPIsocket, clh.state.apiSocket}
 	if clh.config.Debug {
 		// Cloud hypervisor log levels
@@ -872,7 +1052,17 @@ func (clh *cloudHypervisor) launchClh() (int, error) {
 	clh.Logger().WithField("path", clhPath).Info()
 	clh.Logger().WithField("args", strings.Join(args, " ")).Info()
 
-	cmdHypervisor := exec.Command(clhPath, args...)
+	launcher, err := clh.config.vmmLauncher(filepath.Join(clh.store.RunVMStoragePath(), clh.id), clhPath, cred)
+	if err != nil {
+		return -1, err
+	}
+
+	clh.state.invocation = HypervisorInvocation{
+		Path: launcher,
+		Args: args,
+	}
+
+	cmdHypervisor := exec.Command(launcher, args...)
 	if clh.config.Debug {
 		cmdHypervisor.Env = os.Environ()
 		cmdHypervisor.Env = append(cmdHypervisor.Env, "RUST_BACKTRACE=full")
@@ -883,6 +1073,7 @@ func (clh *cloudHypervisor) launchClh() (int, error) {
 	}
 
 	cmdHypervisor.Stderr = cmdHypervisor.Stdout
+	cmdHypervisor.ExtraFiles = clh.extraFiles
 
 	err = utils.StartCmd(cmdHypervisor)
 	if err != nil {
@@ -1010,14 +1201,19 @@ func (clh *cloudHypervisor) bootVM(ctx context.Context) error {
 
 	cl := clh.client()
 
+	bodyBuf, err := json.Marshal(clh.vmconfig)
+	if err != nil {
+		return err
+	}
 	if clh.config.Debug {
-		bodyBuf, err := json.Marshal(clh.vmconfig)
-		if err != nil {
-			return err
-		}
 		clh.Logger().WithField("body", string(bodyBuf)).Debug("VM config")
 	}
-	_, err := cl.CreateVM(ctx, clh.vmconfig)
+	clh.state.invocation.APICalls = []HypervisorAPICall{
+		{Method: http.MethodPut, Endpoint: clhAPIPath + "/vm.create", Body: bodyBuf},
+		{Method: http.MethodPut, Endpoint: clhAPIPath + "/vm.boot"},
+	}
+
+	_, err = cl.CreateVM(ctx, clh.vmconfig)
 	if err != nil {
 		return openAPIClientError(err)
 	}
@@ -1081,10 +1277,30 @@ func (clh *cloudHypervisor) addNet(e Endpoint) error {
 		"tap": tapPath,
 	}).Info("Adding Net")
 
+	// Pass the tap fds opened by the runtime when there are some, so
+	// that cloud-hypervisor doesn't need the privileges to open the tap.
+	if len(netPair.VMFds) > 0 {
+		var fds []int32
+		for _, f := range netPair.VMFds {
+			fds = append(fds, clh.addExtraFile(f))
+		}
+		// each tap fd is a queue pair
+		clh.vmconfig.Net = append(clh.vmconfig.Net, chclient.NetConfig{Mac: mac, Fd: fds, NumQueues: int32(2 * len(fds))})
+		return nil
+	}
+
 	clh.vmconfig.Net = append(clh.vmconfig.Net, chclient.NetConfig{Mac: mac, Tap: tapPath})
 	return nil
 }
 
+// addExtraFile adds f to the files inherited by cloud-hypervisor,
+// and returns its fd number in the cloud-hypervisor process.
+func (clh *cloudHypervisor) addExtraFile(f *os.File) int32 {
+	clh.extraFiles = append(clh.extraFiles, f)
+	// the inherited files follow stdin, stdout and stderr
+	return int32(2 + len(clh.extraFiles))
+}
+
 // Add shared Volume using virtiofs
 func (clh *cloudHypervisor) addVolume(volume types.Volume) error {
 	if clh.config.SharedFS != config.VirtioFS {
@@ -1126,6 +1342,8 @@ func (clh *cloudHypervisor) cleanupVM(force bool) error {
 		return errors.New("Hypervisor ID is empty")
 	}
 
+	clh.config.releaseHypervisorID(clh.id)
+
 	clh.Logger().Debug("removing vm sockets")
 
 	path, err := clh.vsockSocketPath(clh.id)
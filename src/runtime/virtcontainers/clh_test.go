@@ -7,6 +7,8 @@ package virtcontainers
 
 import (
 	"context"
+	"encoding/json"
+	"io/ioutil"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -93,6 +95,16 @@ func (c *clhClientMock) VmResizePut(ctx context.Context, vmResize chclient.VmRes
 	return nil, nil
 }
 
+//nolint:golint
+func (c *clhClientMock) VmResizeZonePut(ctx context.Context, vmResizeZone chclient.VmResizeZone) (*http.Response, error) {
+	for i, zone := range c.vmInfo.Config.Memory.Zones {
+		if zone.Id == vmResizeZone.Id {
+			c.vmInfo.Config.Memory.Zones[i].HotpluggedSize = vmResizeZone.DesiredRam - zone.Size
+		}
+	}
+	return nil, nil
+}
+
 //nolint:golint
 func (c *clhClientMock) VmAddDevicePut(ctx context.Context, vmAddDevice chclient.VmAddDevice) (chclient.PciDeviceInfo, *http.Response, error) {
 	return chclient.PciDeviceInfo{}, nil, nil
@@ -150,6 +162,45 @@ func TestCloudHypervisorAddNetCheckNetConfigListValues(t *testing.T) {
 	}
 }
 
+func TestCloudHypervisorAddNetTapFds(t *testing.T) {
+	assert := assert.New(t)
+
+	tap1, err := ioutil.TempFile("", "tap")
+	assert.NoError(err)
+	defer os.Remove(tap1.Name())
+	defer tap1.Close()
+
+	tap2, err := ioutil.TempFile("", "tap")
+	assert.NoError(err)
+	defer os.Remove(tap2.Name())
+	defer tap2.Close()
+
+	clh := cloudHypervisor{}
+
+	e := &VethEndpoint{}
+	e.NetPair.TAPIface.HardAddr = "00:00:00:00:00"
+	e.NetPair.TapInterface.TAPIface.Name = "tap0"
+	e.NetPair.VMFds = []*os.File{tap1, tap2}
+
+	assert.NoError(clh.addNet(e))
+	assert.Len(clh.vmconfig.Net, 1)
+	assert.Empty(clh.vmconfig.Net[0].Tap)
+	assert.Equal([]int32{3, 4}, clh.vmconfig.Net[0].Fd)
+	assert.Equal(int32(4), clh.vmconfig.Net[0].NumQueues)
+	assert.Equal([]*os.File{tap1, tap2}, clh.extraFiles)
+}
+
+func TestCloudHypervisorCapabilities(t *testing.T) {
+	assert := assert.New(t)
+
+	clh := cloudHypervisor{}
+
+	caps := clh.capabilities(context.Background())
+	assert.True(caps.IsFsSharingSupported())
+	assert.True(caps.IsBlockDeviceHotplugSupported())
+	assert.True(caps.IsMultiQueueSupported())
+}
+
 // Check addNet with valid values, and fail with invalid values
 // For Cloud Hypervisor only tap is be required
 func TestCloudHypervisorAddNetCheckEnpointTypes(t *testing.T) {
@@ -194,6 +245,31 @@ func TestCloudHypervisorBootVM(t *testing.T) {
 	}
 }
 
+func TestCloudHypervisorInvocation(t *testing.T) {
+	assert := assert.New(t)
+
+	clh := &cloudHypervisor{}
+	clh.APIClient = &clhClientMock{}
+	clh.state.invocation = HypervisorInvocation{Path: "/usr/bin/cloud-hypervisor", Args: []string{cscAPIsocket, "clh-api.sock"}}
+	clh.vmconfig.Cpus.BootVcpus = 2
+
+	assert.NoError(clh.bootVM(context.Background()))
+
+	// the invocation is persisted
+	loaded := &cloudHypervisor{}
+	loaded.load(clh.save())
+
+	invocation := loaded.state.invocation
+	assert.Equal("/usr/bin/cloud-hypervisor", invocation.Path)
+	assert.Equal([]string{cscAPIsocket, "clh-api.sock"}, invocation.Args)
+	assert.Len(invocation.APICalls, 2)
+	assert.Equal("/api/v1/vm.create", invocation.APICalls[0].Endpoint)
+	var vmconfig chclient.VmConfig
+	assert.NoError(json.Unmarshal(invocation.APICalls[0].Body, &vmconfig))
+	assert.Equal(int32(2), vmconfig.Cpus.BootVcpus)
+	assert.Equal(HypervisorAPICall{Method: http.MethodPut, Endpoint: "/api/v1/vm.boot"}, invocation.APICalls[1])
+}
+
 func TestCloudHypervisorCleanupVM(t *testing.T) {
 	assert := assert.New(t)
 	store, err := persist.GetDriver()
@@ -323,6 +399,79 @@ func TestCloudHypervisorResizeMemory(t *testing.T) {
 	}
 }
 
+func TestCloudHypervisorNUMAConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	clhConfig, err := newClhConfig()
+	assert.NoError(err)
+	clhConfig.NumVCPUs = 2
+	clhConfig.DefaultMaxVCPUs = 4
+	clhConfig.MemorySize = 2048
+	clhConfig.NUMANodes = []NUMANode{
+		{VCPUs: []uint32{0, 2}, MemorySize: 1536},
+		{VCPUs: []uint32{1, 3}, MemorySize: 512},
+	}
+
+	store, err := persist.GetDriver()
+	assert.NoError(err)
+
+	clh := &cloudHypervisor{
+		config: clhConfig,
+		store:  store,
+	}
+
+	err = clh.createSandbox(context.Background(), "testSandbox", NetworkNamespace{}, &clhConfig)
+	assert.NoError(err)
+
+	assert.Equal(int64(0), clh.vmconfig.Memory.Size)
+	assert.Equal(int64(0), clh.vmconfig.Memory.HotplugSize)
+	assert.Len(clh.vmconfig.Memory.Zones, 2)
+	assert.Equal(int64(1536*utils.MiB), clh.vmconfig.Memory.Zones[0].Size)
+	assert.Equal(int64(512*utils.MiB), clh.vmconfig.Memory.Zones[1].Size)
+	assert.NotZero(clh.vmconfig.Memory.Zones[0].HotplugSize)
+
+	assert.Equal([]chclient.NumaConfig{
+		{GuestNumaId: 0, Cpus: []int32{0, 2}, MemoryZones: []string{"mem0"}},
+		{GuestNumaId: 1, Cpus: []int32{1, 3}, MemoryZones: []string{"mem1"}},
+	}, clh.vmconfig.Numa)
+	assert.Equal(int32(4), clh.vmconfig.Cpus.MaxVcpus)
+}
+
+func TestCloudHypervisorResizeMemoryZones(t *testing.T) {
+	assert := assert.New(t)
+
+	clhConfig, err := newClhConfig()
+	assert.NoError(err)
+
+	mockClient := &clhClientMock{}
+	mockClient.vmInfo.Config.Memory.Zones = []chclient.MemoryZoneConfig{
+		{Id: "mem0", Size: int64(1024 * utils.MiB), HotplugSize: int64(utils.GiB)},
+		{Id: "mem1", Size: int64(512 * utils.MiB), HotplugSize: int64(utils.GiB)},
+	}
+
+	clh := cloudHypervisor{
+		APIClient: mockClient,
+		config:    clhConfig,
+	}
+
+	// the memory goes to the node with the least memory first
+	newMem, memDev, err := clh.resizeMemory(context.Background(), 2048, 128, false)
+	assert.NoError(err)
+	assert.Equal(uint32(2048), newMem)
+	assert.Equal(512, memDev.sizeMB)
+	zones := mockClient.vmInfo.Config.Memory.Zones
+	assert.Equal(int64(0), zones[0].HotpluggedSize)
+	assert.Equal(int64(512*utils.MiB), zones[1].HotpluggedSize)
+
+	// then it is spread across the nodes
+	newMem, memDev, err = clh.resizeMemory(context.Background(), 2560, 128, false)
+	assert.NoError(err)
+	assert.Equal(uint32(2560), newMem)
+	assert.Equal(512, memDev.sizeMB)
+	assert.Equal(int64(256*utils.MiB), zones[0].HotpluggedSize)
+	assert.Equal(int64(768*utils.MiB), zones[1].HotpluggedSize)
+}
+
 func TestCloudHypervisorHotplugAddBlockDevice(t *testing.T) {
 	assert := assert.New(t)
 
@@ -364,3 +513,40 @@ func TestCloudHypervisorHotplugRemoveDevice(t *testing.T) {
 	_, err = clh.hotplugRemoveDevice(context.Background(), nil, netDev)
 	assert.Error(err, "Hotplug remove pmem block device expected error")
 }
+
+func TestCheckClhCPUConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	f, err := ioutil.TempFile("", "cpuinfo")
+	assert.NoError(err)
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	_, err = f.WriteString("flags\t\t: fpu vmx avx512f sse4_2\n")
+	assert.NoError(err)
+
+	testCases := []struct {
+		model    string
+		features string
+		valid    bool
+	}{
+		{"", "", true},
+		{defaultCPUModel, "avx512f,+vmx,sse4-2", true},
+		{"", "avx512f,pdpe1gb", false},
+		{"", "pmu=off", false},
+		{"Skylake-Server", "", false},
+	}
+
+	for _, tc := range testCases {
+		config := &HypervisorConfig{CPUModel: tc.model, CPUFeatures: tc.features}
+		err := checkClhCPUConfig(config, f.Name())
+		if tc.valid {
+			assert.NoError(err, tc)
+		} else {
+			assert.Error(err, tc)
+		}
+	}
+
+	err = checkClhCPUConfig(&HypervisorConfig{CPUFeatures: "vmx"}, filepath.Join(os.TempDir(), "no-such-cpuinfo"))
+	assert.Error(err)
+}
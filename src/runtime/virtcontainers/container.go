@@ -20,7 +20,10 @@ import (
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/device/config"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/device/manager"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/agent/protocols/grpc"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/annotations"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/blockdedup"
 	vccgroups "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/cgroups"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/cpuset"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/rootless"
 	vcTypes "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/types"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/types"
@@ -279,6 +282,12 @@ func (c *ContainerConfig) valid() bool {
 	return true
 }
 
+// isSandbox returns true for the container holding the sandbox, i.e. the
+// pause container in Kubernetes, which the other containers depend on.
+func (c *ContainerConfig) isSandbox() bool {
+	return c.Annotations[annotations.ContainerTypeKey] == string(PodSandbox)
+}
+
 // SystemMountsInfo describes additional information for system mounts that the agent
 // needs to handle
 type SystemMountsInfo struct {
@@ -346,9 +355,28 @@ type Container struct {
 
 	systemMountsInfo SystemMountsInfo
 
+	// filesToCopy are the files shared by copy, when the hypervisor
+	// doesn't support filesystem sharing. They are copied by the
+	// agent when it creates the container.
+	filesToCopy []fileCopy
+
+	// pendingResources are the guest resources updated while the
+	// container creation is deferred.
+	pendingResources *specs.LinuxResources
+
+	// remoteSnapshot is the mount info of a rootfs
+	// provided by a remote snapshotter.
+	remoteSnapshot *RemoteSnapshot
+
 	ctx context.Context
 }
 
+// fileCopy is a host file copied in the guest
+type fileCopy struct {
+	src string
+	dst string
+}
+
 // ID returns the container identifier string.
 func (c *Container) ID() string {
 	return c.id
@@ -462,9 +490,7 @@ func (c *Container) shareFiles(ctx context.Context, m Mount, idx int) (string, b
 			return "", true, nil
 		}
 
-		if err := c.sandbox.agent.copyFile(ctx, m.Source, guestDest); err != nil {
-			return "", false, err
-		}
+		c.filesToCopy = append(c.filesToCopy, fileCopy{src: m.Source, dst: guestDest})
 	} else {
 		// These mounts are created in the shared dir
 		mountDest := filepath.Join(getMountPath(c.sandboxID), filename)
@@ -498,6 +524,13 @@ func (c *Container) shareFiles(ctx context.Context, m Mount, idx int) (string, b
 		}
 		// Save HostPath mount value into the mount list of the container.
 		c.mounts[idx].HostPath = mountDest
+
+		c.sandbox.audit.record(ctx, AuditMount, map[string]interface{}{
+			"container":   c.id,
+			"source":      m.Source,
+			"destination": m.Destination,
+			"readonly":    m.ReadOnly,
+		}, nil)
 	}
 
 	return guestDest, false, nil
@@ -510,6 +543,7 @@ func (c *Container) shareFiles(ctx context.Context, m Mount, idx int) (string, b
 // available when we will need to unmount those mounts.
 func (c *Container) mountSharedDirMounts(ctx context.Context, sharedDirMounts, ignoredMounts map[string]Mount) (storages []*grpc.Storage, err error) {
 	var devicesToDetach []string
+	c.filesToCopy = nil
 	defer func() {
 		if err != nil {
 			for _, id := range devicesToDetach {
@@ -779,13 +813,29 @@ func newContainer(ctx context.Context, sandbox *Sandbox, contConfig *ContainerCo
 		return nil, err
 	}
 
+	if c.remoteSnapshot, err = ParseRemoteSnapshot(c.rootFs.Options); err != nil {
+		return nil, err
+	}
+	if c.remoteSnapshot != nil {
+		// the agent mounts the rootfs in place of the rootfs directory
+		c.rootfsSuffix = ""
+	}
+
 	// If mounts are block devices, add to devmanager
 	if err := c.createMounts(ctx); err != nil {
 		return nil, err
 	}
 
+	// Prepare the devices of the device plugins, added to the container's devices
+	if err := c.preparePluginDevices(ctx); err != nil {
+		return nil, err
+	}
+
 	// Add container's devices to sandbox's device-manager
 	if err := c.createDevices(contConfig); err != nil {
+		if err := c.releasePluginDevices(ctx); err != nil {
+			c.Logger().WithError(err).Warn("failed to release the plugin devices")
+		}
 		return nil, err
 	}
 
@@ -863,10 +913,45 @@ func (c *Container) create(ctx context.Context) (err error) {
 		}
 	}()
 
+	if c.deferCreation(ctx) {
+		c.Logger().Info("container creation in the guest deferred until it starts")
+		c.state.CreationDeferred = true
+		var process *Process
+		if process, err = buildProcessFromExecID(c.id); err != nil {
+			return
+		}
+		c.process = *process
+	} else if err = c.createInGuest(ctx); err != nil {
+		return
+	}
+
+	if !rootless.IsRootless() && !c.sandbox.config.SandboxCgroupOnly {
+		if err = c.cgroupsCreate(); err != nil {
+			return
+		}
+	}
+
+	if err = c.setContainerState(types.StateReady); err != nil {
+		return
+	}
+
+	return nil
+}
+
+// deferCreation returns true if the creation of the container in the guest,
+// and the hotplug of its rootfs block device, are deferred until it starts.
+func (c *Container) deferCreation(ctx context.Context) bool {
+	return c.sandbox.config.HypervisorConfig.LazyBlockDeviceAttach &&
+		!c.config.isSandbox() && c.checkBlockDeviceSupport(ctx)
+}
+
+// createInGuest hotplugs the rootfs and the devices of the container to the
+// guest, and asks the agent to create it.
+func (c *Container) createInGuest(ctx context.Context) (err error) {
 	if c.checkBlockDeviceSupport(ctx) {
 		// If the rootfs is backed by a block device, go ahead and hotplug it to the guest
 		if err = c.hotplugDrive(ctx); err != nil {
-			return
+			return err
 		}
 	}
 
@@ -882,7 +967,7 @@ func (c *Container) create(ctx context.Context) (err error) {
 			var isLargeBarSpace bool
 			isLargeBarSpace, err = manager.IsVFIOLargeBarSpaceDevice(device.ContainerPath)
 			if err != nil {
-				return
+				return err
 			}
 			if isLargeBarSpace {
 				delayAttachedDevs = append(delayAttachedDevs, device)
@@ -900,7 +985,7 @@ func (c *Container) create(ctx context.Context) (err error) {
 	}).Info("normal attach devices")
 	if len(normalAttachedDevs) > 0 {
 		if err = c.attachDevices(ctx, normalAttachedDevs); err != nil {
-			return
+			return err
 		}
 	}
 
@@ -921,20 +1006,10 @@ func (c *Container) create(ctx context.Context) (err error) {
 			"devices":      delayAttachedDevs,
 		}).Info("lazy attach devices")
 		if err = c.attachDevices(ctx, delayAttachedDevs); err != nil {
-			return
-		}
-	}
-
-	if !rootless.IsRootless() && !c.sandbox.config.SandboxCgroupOnly {
-		if err = c.cgroupsCreate(); err != nil {
-			return
+			return err
 		}
 	}
 
-	if err = c.setContainerState(types.StateReady); err != nil {
-		return
-	}
-
 	return nil
 }
 
@@ -949,6 +1024,11 @@ func (c *Container) delete(ctx context.Context) error {
 		return err
 	}
 
+	// The devices were detached when the container was stopped
+	if err := c.releasePluginDevices(ctx); err != nil {
+		c.Logger().WithError(err).Warn("failed to release the plugin devices")
+	}
+
 	// If running rootless, there are no cgroups to remove
 	if !c.sandbox.config.SandboxCgroupOnly || !rootless.IsRootless() {
 		if err := c.cgroupsDelete(); err != nil {
@@ -1003,7 +1083,15 @@ func (c *Container) start(ctx context.Context) error {
 		return err
 	}
 
-	if err := c.sandbox.agent.startContainer(ctx, c.sandbox, c); err != nil {
+	if c.state.CreationDeferred {
+		if err := c.createDeferred(ctx); err != nil {
+			return err
+		}
+	}
+
+	if err := c.sandbox.agentCall(ctx, func() error {
+		return c.sandbox.agent.startContainer(ctx, c.sandbox, c)
+	}); err != nil {
 		c.Logger().WithError(err).Error("Failed to start container")
 
 		if err := c.stop(ctx, true); err != nil {
@@ -1015,6 +1103,31 @@ func (c *Container) start(ctx context.Context) error {
 	return c.setContainerState(types.StateRunning)
 }
 
+// createDeferred creates in the guest a container whose creation was
+// deferred until it starts, see deferCreation.
+func (c *Container) createDeferred(ctx context.Context) (err error) {
+	defer func() {
+		if err != nil {
+			c.Logger().WithError(err).Error("deferred container create failed")
+			c.rollbackFailingContainerCreation(ctx)
+		}
+	}()
+
+	if err = c.createInGuest(ctx); err != nil {
+		return
+	}
+	c.state.CreationDeferred = false
+
+	if c.pendingResources != nil {
+		if err = c.sandbox.agent.updateContainer(ctx, c.sandbox, *c, *c.pendingResources); err != nil {
+			return
+		}
+		c.pendingResources = nil
+	}
+
+	return nil
+}
+
 func (c *Container) stop(ctx context.Context, force bool) error {
 	span, ctx := katatrace.Trace(ctx, c.Logger(), "stop", c.tracingTags())
 	defer span.End()
@@ -1035,17 +1148,6 @@ func (c *Container) stop(ctx context.Context, force bool) error {
 		return err
 	}
 
-	// Force the container to be killed. For most of the cases, this
-	// should not matter and it should return an error that will be
-	// ignored.
-	c.kill(ctx, syscall.SIGKILL, true)
-
-	// Since the agent has supported the MultiWaitProcess, it's better to
-	// wait the process here to make sure the process has exited before to
-	// issue stopContainer, otherwise the RemoveContainerRequest in it will
-	// get failed if the process hasn't exited.
-	c.sandbox.agent.waitProcess(ctx, c, c.id)
-
 	defer func() {
 		// Save device and drive data.
 		// TODO: can we merge this saving with setContainerState()?
@@ -1054,8 +1156,23 @@ func (c *Container) stop(ctx context.Context, force bool) error {
 		}
 	}()
 
-	if err := c.sandbox.agent.stopContainer(ctx, c.sandbox, *c); err != nil && !force {
-		return err
+	// A container whose creation is still deferred has nothing running
+	// in the guest.
+	if !c.state.CreationDeferred {
+		// Force the container to be killed. For most of the cases, this
+		// should not matter and it should return an error that will be
+		// ignored.
+		c.kill(ctx, syscall.SIGKILL, true)
+
+		// Since the agent has supported the MultiWaitProcess, it's better to
+		// wait the process here to make sure the process has exited before to
+		// issue stopContainer, otherwise the RemoveContainerRequest in it will
+		// get failed if the process hasn't exited.
+		c.sandbox.agent.waitProcess(ctx, c, c.id)
+
+		if err := c.sandbox.agent.stopContainer(ctx, c.sandbox, *c); err != nil && !force {
+			return err
+		}
 	}
 
 	if err := c.unmountHostMounts(ctx); err != nil && !force {
@@ -1074,11 +1191,17 @@ func (c *Container) stop(ctx context.Context, force bool) error {
 		return err
 	}
 
+	c.detachReleasedDevices(ctx)
+
 	shareDir := filepath.Join(getMountPath(c.sandbox.id), c.id)
 	if err := syscall.Rmdir(shareDir); err != nil {
 		c.Logger().WithError(err).WithField("share-dir", shareDir).Warn("Could not remove container share dir")
 	}
 
+	// The rootfs drive is detached now, a lazily attached container is
+	// created again in the guest if it restarts.
+	c.state.CreationDeferred = c.deferCreation(ctx)
+
 	// container was killed by force, container MUST change its state
 	// as soon as possible just in case one of below operations fail leaving
 	// the containers in a bad state.
@@ -1115,7 +1238,12 @@ func (c *Container) wait(ctx context.Context, processID string) (int32, error) {
 			"impossible to wait")
 	}
 
-	return c.sandbox.agent.waitProcess(ctx, c, processID)
+	status, session, err := c.sandbox.agent.waitProcess(ctx, c, processID)
+	if err == nil && session != nil {
+		c.sandbox.audit.record(ctx, AuditExecSession, execSessionAuditParams(c.id, processID, status, session), nil)
+	}
+
+	return status, err
 }
 
 func (c *Container) kill(ctx context.Context, signal syscall.Signal, all bool) error {
@@ -1172,6 +1300,35 @@ func (c *Container) update(ctx context.Context, resources specs.LinuxResources)
 		c.config.Resources.CPU = &specs.LinuxCPU{}
 	}
 
+	if cpu := resources.CPU; cpu != nil {
+		if cpu.Cpus != "" {
+			if _, err := cpuset.Parse(cpu.Cpus); err != nil {
+				return fmt.Errorf("invalid cpuset.cpus %q: %v", cpu.Cpus, err)
+			}
+		}
+		if cpu.Mems != "" {
+			if _, err := cpuset.Parse(cpu.Mems); err != nil {
+				return fmt.Errorf("invalid cpuset.mems %q: %v", cpu.Mems, err)
+			}
+		}
+	}
+
+	if c.config.Resources.Memory == nil {
+		c.config.Resources.Memory = &specs.LinuxMemory{}
+	}
+
+	var shrink bool
+	if mem := resources.Memory; mem != nil && mem.Limit != nil {
+		shrink = isMemoryShrink(c.config.Resources.Memory.Limit, mem.Limit)
+	}
+
+	// Check before updating anything, the guest memory limit would not
+	// match the VM memory otherwise.
+	caps := c.sandbox.hypervisor.capabilities(ctx)
+	if shrink && !caps.IsMemoryHotUnplugSupported() {
+		return fmt.Errorf("cannot decrease the memory limit of container %s: the hypervisor does not support memory hot unplug (virtio-mem is required)", c.id)
+	}
+
 	if cpu := resources.CPU; cpu != nil {
 		if p := cpu.Period; p != nil && *p != 0 {
 			c.config.Resources.CPU.Period = p
@@ -1187,14 +1344,41 @@ func (c *Container) update(ctx context.Context, resources specs.LinuxResources)
 		}
 	}
 
-	if c.config.Resources.Memory == nil {
-		c.config.Resources.Memory = &specs.LinuxMemory{}
-	}
-
 	if mem := resources.Memory; mem != nil && mem.Limit != nil {
 		c.config.Resources.Memory.Limit = mem.Limit
 	}
 
+	if len(resources.HugepageLimits) > 0 {
+		c.config.Resources.HugepageLimits = resources.HugepageLimits
+	}
+
+	// There currently isn't a notion of cpusets.cpus or mems being tracked
+	// inside of the guest: the host cpuset constrains the vCPU threads of
+	// the VM, through the sandbox cgroup. Make sure we clear these before
+	// asking agent to update the container's cgroups.
+	guestResources := resources
+	if resources.CPU != nil {
+		cpu := *resources.CPU
+		cpu.Mems = ""
+		cpu.Cpus = ""
+		guestResources.CPU = c.sandbox.config.CPUQuotaPolicy.guestCPU(&cpu)
+	}
+
+	// The guest resources of a container whose creation is deferred are
+	// updated once it is created.
+	if c.state.CreationDeferred {
+		c.pendingResources = &guestResources
+		shrink = false
+	}
+
+	// When the memory decreases, the guest cgroup is constrained first so
+	// that the guest can give the memory back before it is unplugged.
+	if shrink {
+		if err := c.sandbox.agent.updateContainer(ctx, c.sandbox, *c, guestResources); err != nil {
+			return err
+		}
+	}
+
 	if err := c.sandbox.updateResources(ctx); err != nil {
 		return err
 	}
@@ -1205,15 +1389,21 @@ func (c *Container) update(ctx context.Context, resources specs.LinuxResources)
 		}
 	}
 
-	// There currently isn't a notion of cpusets.cpus or mems being tracked
-	// inside of the guest. Make sure we clear these before asking agent to update
-	// the container's cgroups.
-	if resources.CPU != nil {
-		resources.CPU.Mems = ""
-		resources.CPU.Cpus = ""
+	if shrink || c.state.CreationDeferred {
+		return nil
+	}
+
+	return c.sandbox.agent.updateContainer(ctx, c.sandbox, *c, guestResources)
+}
+
+// isMemoryShrink tells if a memory limit update decreases the memory of
+// the container, a nil or non positive limit meaning no limit.
+func isMemoryShrink(current, requested *int64) bool {
+	if current == nil || requested == nil {
+		return false
 	}
 
-	return c.sandbox.agent.updateContainer(ctx, c.sandbox, *c, resources)
+	return *current > 0 && *requested > 0 && *requested < *current
 }
 
 func (c *Container) pause(ctx context.Context) error {
@@ -1254,6 +1444,11 @@ func (c *Container) hotplugDrive(ctx context.Context) error {
 	var dev device
 	var err error
 
+	// the rootfs is mounted in the guest
+	if c.remoteSnapshot != nil {
+		return nil
+	}
+
 	// Check to see if the rootfs is an umounted block device (source) or if the
 	// mount (target) is backed by a block device:
 	if !c.rootFs.Mounted {
@@ -1310,7 +1505,21 @@ func (c *Container) hotplugDrive(ctx context.Context) error {
 		"fs-type":     fsType,
 	}).Info("Block device detected")
 
+	if c.sandbox.config.RootfsDedup && c.checkBlockDeviceSupport(ctx) {
+		// share the base device with the other sandboxes,
+		// the container writes to its own snapshot
+		if devicePath, err = blockdedup.Acquire(devicePath, c.rootfsDedupDir(), c.id); err != nil {
+			return err
+		}
+		c.Logger().WithField("device-path", devicePath).Info("Block device snapshot created")
+	}
+
 	if err = c.plugDevice(ctx, devicePath); err != nil {
+		if c.sandbox.config.RootfsDedup {
+			if err := blockdedup.Release(c.rootfsDedupDir(), c.id); err != nil {
+				c.Logger().WithError(err).Error("failed to release block device snapshot")
+			}
+		}
 		return err
 	}
 
@@ -1355,13 +1564,12 @@ func (c *Container) removeDrive(ctx context.Context) (err error) {
 	if c.isDriveUsed() {
 		c.Logger().Info("unplugging block device")
 
-		devID := c.state.BlockDeviceID
-		err := c.sandbox.devManager.DetachDevice(ctx, devID, c.sandbox)
-		if err != nil && err != manager.ErrDeviceNotAttached {
-			return err
-		}
+		// the snapshot of a deduplicated rootfs is removed below, it
+		// can't be kept attached
+		keep := c.reuseDevices() && !c.sandbox.config.RootfsDedup
 
-		if err = c.sandbox.devManager.RemoveDevice(devID); err != nil {
+		devID := c.state.BlockDeviceID
+		if err := c.sandbox.devManager.ReleaseDevice(ctx, devID, c.sandbox, keep); err != nil {
 			c.Logger().WithFields(logrus.Fields{
 				"container": c.id,
 				"device-id": devID,
@@ -1372,11 +1580,22 @@ func (c *Container) removeDrive(ctx context.Context) (err error) {
 				return err
 			}
 		}
+
+		if c.sandbox.config.RootfsDedup {
+			if err := blockdedup.Release(c.rootfsDedupDir(), c.id); err != nil {
+				return err
+			}
+		}
 	}
 
 	return nil
 }
 
+// rootfsDedupDir is the directory of the rootfs snapshots of the sandbox.
+func (c *Container) rootfsDedupDir() string {
+	return filepath.Join(c.sandbox.config.RootfsDedupDir, c.sandbox.id)
+}
+
 func (c *Container) attachDevices(ctx context.Context, devices []ContainerDevice) error {
 	// there's no need to do rollback when error happens,
 	// because if attachDevices fails, container creation will fail too,
@@ -1395,12 +1614,7 @@ func (c *Container) attachDevices(ctx context.Context, devices []ContainerDevice
 
 func (c *Container) detachDevices(ctx context.Context) error {
 	for _, dev := range c.devices {
-		err := c.sandbox.devManager.DetachDevice(ctx, dev.ID, c.sandbox)
-		if err != nil && err != manager.ErrDeviceNotAttached {
-			return err
-		}
-
-		if err = c.sandbox.devManager.RemoveDevice(dev.ID); err != nil {
+		if err := c.sandbox.devManager.ReleaseDevice(ctx, dev.ID, c.sandbox, c.reuseDevices()); err != nil {
 			c.Logger().WithFields(logrus.Fields{
 				"container": c.id,
 				"device-id": dev.ID,
@@ -1415,6 +1629,20 @@ func (c *Container) detachDevices(ctx context.Context) error {
 	return nil
 }
 
+// reuseDevices returns whether the devices of the container are kept
+// attached when it stops, for a container restarting with them.
+func (c *Container) reuseDevices() bool {
+	return c.sandbox.config.DeviceReuseTimeout > 0
+}
+
+// detachReleasedDevices detaches the devices kept attached for reuse which
+// were not reused in time.
+func (c *Container) detachReleasedDevices(ctx context.Context) {
+	if err := c.sandbox.devManager.DetachReleasedDevices(ctx, c.sandbox, c.sandbox.config.DeviceReuseTimeout); err != nil {
+		c.Logger().WithError(err).Warn("Could not detach the released devices")
+	}
+}
+
 // cgroupsCreate creates cgroups on the host for the associated container
 func (c *Container) cgroupsCreate() (err error) {
 	spec := c.GetPatchedOCISpec()
@@ -1437,7 +1665,7 @@ func (c *Container) cgroupsCreate() (err error) {
 	}
 
 	cgroup, err := cgroupsNewFunc(cgroups.V1,
-		cgroups.StaticPath(c.state.CgroupPath), &resources)
+		staticCgroupPath(c.state.CgroupPath), &resources)
 	if err != nil {
 		return fmt.Errorf("Could not create cgroup for %v: %v", c.state.CgroupPath, err)
 	}
@@ -1461,7 +1689,7 @@ func (c *Container) cgroupsDelete() error {
 	}
 
 	cgroup, err := cgroupsLoadFunc(cgroups.V1,
-		cgroups.StaticPath(c.state.CgroupPath))
+		staticCgroupPath(c.state.CgroupPath))
 
 	if err == cgroups.ErrCgroupDeleted {
 		// cgroup already deleted
@@ -1501,7 +1729,7 @@ func (c *Container) cgroupsUpdate(resources specs.LinuxResources) error {
 		return nil
 	}
 	cgroup, err := cgroupsLoadFunc(cgroups.V1,
-		cgroups.StaticPath(c.state.CgroupPath))
+		staticCgroupPath(c.state.CgroupPath))
 	if err != nil {
 		return fmt.Errorf("Could not load cgroup %v: %v", c.state.CgroupPath, err)
 	}
@@ -1516,8 +1744,9 @@ func (c *Container) cgroupsUpdate(resources specs.LinuxResources) error {
 		return fmt.Errorf("Could not update container cgroup path='%v': error='%v'", c.state.CgroupPath, err)
 	}
 
-	// store new resources
-	c.config.Resources = r
+	// store new resources, keeping the memory and hugepage limits
+	// which are not applied to the host cgroup
+	c.config.Resources.CPU = r.CPU
 	if err := c.storeContainer(); err != nil {
 		return err
 	}
@@ -22,6 +22,7 @@ import (
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/device/manager"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/persist"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/types"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -636,3 +637,73 @@ func TestMountSharedDirMounts(t *testing.T) {
 	assert.Equal(updatedMounts[mountDestination].Source, expectedStorageDest)
 	assert.Equal(updatedMounts[mountDestination].Destination, mountDestination)
 }
+
+func TestIsMemoryShrink(t *testing.T) {
+	assert := assert.New(t)
+
+	limit := func(l int64) *int64 { return &l }
+
+	assert.True(isMemoryShrink(limit(2048), limit(1024)))
+	assert.False(isMemoryShrink(limit(1024), limit(2048)))
+	assert.False(isMemoryShrink(limit(1024), limit(1024)))
+	assert.False(isMemoryShrink(nil, limit(1024)))
+	assert.False(isMemoryShrink(limit(1024), nil))
+	assert.False(isMemoryShrink(limit(-1), limit(1024)))
+	assert.False(isMemoryShrink(limit(1024), limit(-1)))
+}
+
+func TestContainerUpdateMemoryShrinkUnsupported(t *testing.T) {
+	assert := assert.New(t)
+
+	current := int64(2048)
+	requested := int64(1024)
+
+	c := &Container{
+		id: "c1",
+		sandbox: &Sandbox{
+			hypervisor: &mockHypervisor{},
+			state: types.SandboxState{
+				State: types.StateRunning,
+			},
+		},
+		config: &ContainerConfig{
+			Resources: specs.LinuxResources{
+				Memory: &specs.LinuxMemory{Limit: &current},
+			},
+		},
+		state: types.ContainerState{
+			State: types.StateRunning,
+		},
+	}
+
+	err := c.update(context.Background(), specs.LinuxResources{
+		Memory: &specs.LinuxMemory{Limit: &requested},
+	})
+	assert.Error(err)
+	assert.Contains(err.Error(), "memory hot unplug")
+
+	// nothing is updated when the shrink cannot be honored
+	assert.Equal(current, *c.config.Resources.Memory.Limit)
+
+	err = c.update(context.Background(), specs.LinuxResources{
+		CPU: &specs.LinuxCPU{Cpus: "foo"},
+	})
+	assert.Error(err)
+}
+
+func TestContainerRootfsDedupDir(t *testing.T) {
+	assert := assert.New(t)
+
+	c := &Container{
+		sandbox: &Sandbox{
+			id: "sid",
+			config: &SandboxConfig{
+				RootfsDedup:    true,
+				RootfsDedupDir: "/var/lib/kata-containers/rootfs-dedup",
+			},
+		},
+	}
+
+	// the snapshots are kept on disk, not in the run storage
+	assert.Equal("/var/lib/kata-containers/rootfs-dedup/sid", c.rootfsDedupDir())
+}
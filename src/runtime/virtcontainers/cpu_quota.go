@@ -0,0 +1,109 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"fmt"
+
+	"github.com/containerd/cgroups"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+// CPUQuotaPolicy tells where the CPU quota of the containers is enforced: in
+// the host sandbox cgroup of the vCPU threads, in the guest cgroups of the
+// containers, or both.
+type CPUQuotaPolicy string
+
+const (
+	// CPUQuotaBoth enforces the quota of each container in the guest, and
+	// the sum of the quotas on the vCPU threads in the host.
+	CPUQuotaBoth CPUQuotaPolicy = "both"
+
+	// CPUQuotaHost only enforces the sum of the quotas on the vCPU threads
+	// in the host. The containers are not throttled in the guest, they
+	// share the vCPUs of the sandbox.
+	CPUQuotaHost CPUQuotaPolicy = "host"
+
+	// CPUQuotaGuest only enforces the quota of each container in the
+	// guest. The vCPU threads are throttled in the host only above the sum
+	// of the quotas rounded up to whole CPUs, i.e. the vCPUs hotplugged for
+	// the containers, so that the guest doesn't see the vCPUs stall in the
+	// middle of a period.
+	CPUQuotaGuest CPUQuotaPolicy = "guest"
+)
+
+// Valid checks the CPU quota policy is known, the empty policy being both.
+func (p CPUQuotaPolicy) Valid() error {
+	switch p {
+	case "", CPUQuotaBoth, CPUQuotaHost, CPUQuotaGuest:
+		return nil
+	}
+
+	return fmt.Errorf("invalid CPU quota policy %q, must be one of %q, %q or %q", p, CPUQuotaBoth, CPUQuotaHost, CPUQuotaGuest)
+}
+
+// inGuest returns true if the quota of the containers is enforced in the guest.
+func (p CPUQuotaPolicy) inGuest() bool {
+	return p != CPUQuotaHost
+}
+
+// hostQuota returns the quota of the vCPU threads in the host, from the sum
+// of the quotas of the containers.
+func (p CPUQuotaPolicy) hostQuota(quota int64, period uint64) int64 {
+	if p != CPUQuotaGuest || quota <= 0 || period == 0 {
+		return quota
+	}
+
+	// round up to the period, i.e. to whole CPUs
+	return (quota + int64(period) - 1) / int64(period) * int64(period)
+}
+
+// guestCPU returns the CPU resources of a container applied in the guest.
+func (p CPUQuotaPolicy) guestCPU(cpu *specs.LinuxCPU) *specs.LinuxCPU {
+	if cpu == nil || p.inGuest() {
+		return cpu
+	}
+
+	guestCPU := *cpu
+	guestCPU.Quota = nil
+	guestCPU.Period = nil
+
+	return &guestCPU
+}
+
+// hostCPUThrottling returns the throttling of the vCPU threads by the host
+// sandbox cgroup.
+func (s *Sandbox) hostCPUThrottling() (ThrottlingData, error) {
+	if s.state.CgroupPath == "" {
+		return ThrottlingData{}, fmt.Errorf("sandbox cgroup path is empty")
+	}
+
+	// the vCPU threads are in the constrained sandbox cgroup
+	hierarchy := V1Constraints
+	if s.config.SandboxCgroupOnly {
+		hierarchy = cgroups.V1
+	}
+
+	cgroup, err := cgroupsLoadFunc(hierarchy, staticCgroupPath(s.state.CgroupPath))
+	if err != nil {
+		return ThrottlingData{}, fmt.Errorf("Could not load sandbox cgroup in %v: %v", s.state.CgroupPath, err)
+	}
+
+	metrics, err := cgroup.Stat(cgroups.ErrorHandler(cgroups.IgnoreNotExist))
+	if err != nil {
+		return ThrottlingData{}, err
+	}
+
+	if metrics.CPU == nil || metrics.CPU.Throttling == nil {
+		return ThrottlingData{}, nil
+	}
+
+	return ThrottlingData{
+		Periods:          metrics.CPU.Throttling.Periods,
+		ThrottledPeriods: metrics.CPU.Throttling.ThrottledPeriods,
+		ThrottledTime:    metrics.CPU.Throttling.ThrottledTime,
+	}, nil
+}
@@ -0,0 +1,87 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"testing"
+
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCPUQuotaPolicyValid(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, p := range []CPUQuotaPolicy{"", CPUQuotaBoth, CPUQuotaHost, CPUQuotaGuest} {
+		assert.NoError(p.Valid())
+	}
+	assert.Error(CPUQuotaPolicy("vcpu").Valid())
+}
+
+func TestCPUQuotaPolicyGuestCPU(t *testing.T) {
+	assert := assert.New(t)
+
+	quota := int64(150000)
+	period := uint64(100000)
+	shares := uint64(1024)
+	cpu := &specs.LinuxCPU{
+		Quota:  &quota,
+		Period: &period,
+		Shares: &shares,
+	}
+
+	assert.Nil(CPUQuotaHost.guestCPU(nil))
+	assert.Equal(cpu, CPUQuotaBoth.guestCPU(cpu))
+	assert.Equal(cpu, CPUQuotaGuest.guestCPU(cpu))
+
+	// the quota is only enforced in the host
+	guestCPU := CPUQuotaHost.guestCPU(cpu)
+	assert.Nil(guestCPU.Quota)
+	assert.Nil(guestCPU.Period)
+	assert.Equal(&shares, guestCPU.Shares)
+	assert.NotNil(cpu.Quota)
+}
+
+func TestSandboxCPUResourcesQuotaPolicy(t *testing.T) {
+	assert := assert.New(t)
+
+	newContainer := func(quota int64, period uint64) *Container {
+		return &Container{
+			config: &ContainerConfig{
+				Resources: specs.LinuxResources{
+					CPU: &specs.LinuxCPU{
+						Quota:  &quota,
+						Period: &period,
+					},
+				},
+			},
+		}
+	}
+
+	s := &Sandbox{
+		config: &SandboxConfig{},
+		containers: map[string]*Container{
+			"foo": newContainer(50000, 100000),
+			"bar": newContainer(100000, 100000),
+		},
+	}
+
+	for _, tc := range []struct {
+		policy CPUQuotaPolicy
+		quota  int64
+	}{
+		{"", 150000},
+		{CPUQuotaBoth, 150000},
+		{CPUQuotaHost, 150000},
+		// rounded up to the vCPUs
+		{CPUQuotaGuest, 200000},
+	} {
+		s.config.CPUQuotaPolicy = tc.policy
+		cpu := s.cpuResources()
+		assert.Equal(tc.quota, *cpu.Quota, "policy %q", tc.policy)
+		assert.Equal(uint64(100000), *cpu.Period, "policy %q", tc.policy)
+	}
+}
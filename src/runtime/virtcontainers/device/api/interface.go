@@ -8,6 +8,7 @@ package api
 
 import (
 	"context"
+	"time"
 
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/device/config"
 	persistapi "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/persist/api"
@@ -91,7 +92,15 @@ type DeviceManager interface {
 	RemoveDevice(string) error
 	AttachDevice(context.Context, string, DeviceReceiver) error
 	DetachDevice(context.Context, string, DeviceReceiver) error
+	// ReleaseDevice detaches and removes a device, or keeps it attached
+	// for reuse if asked and it has no other user
+	ReleaseDevice(context.Context, string, DeviceReceiver, bool) error
+	// DetachReleasedDevices detaches the devices kept attached for longer
+	// than the timeout
+	DetachReleasedDevices(context.Context, DeviceReceiver, time.Duration) error
 	IsDeviceAttached(string) bool
+	// IsDeviceReleased returns whether a device is kept attached for reuse
+	IsDeviceReleased(string) bool
 	GetDeviceByID(string) Device
 	GetAllDevices() []Device
 	LoadDevices([]persistapi.DeviceState)
@@ -11,6 +11,7 @@ import (
 	"encoding/hex"
 	"errors"
 	"sync"
+	"time"
 
 	"github.com/sirupsen/logrus"
 
@@ -53,6 +54,9 @@ type deviceManager struct {
 	vhostUserStorePath    string
 
 	devices map[string]api.Device
+	// released are the devices kept attached after their last user
+	// released them, by release time
+	released map[string]time.Time
 	sync.RWMutex
 }
 
@@ -66,6 +70,7 @@ func NewDeviceManager(blockDriver string, vhostUserStoreEnabled bool, vhostUserS
 		vhostUserStoreEnabled: vhostUserStoreEnabled,
 		vhostUserStorePath:    vhostUserStorePath,
 		devices:               make(map[string]api.Device),
+		released:              make(map[string]time.Time),
 	}
 	if blockDriver == VirtioMmio {
 		dm.blockDriver = VirtioMmio
@@ -165,6 +170,10 @@ func (dm *deviceManager) RemoveDevice(id string) error {
 	}
 
 	if dev.Dereference() == 0 {
+		if _, ok := dm.released[id]; ok {
+			// the device is waiting to be reused
+			return nil
+		}
 		if dev.GetAttachCount() > 0 {
 			return ErrRemoveAttachedDevice
 		}
@@ -173,6 +182,71 @@ func (dm *deviceManager) RemoveDevice(id string) error {
 	return nil
 }
 
+// ReleaseDevice detaches the device from its user and removes it from the
+// list. If keep is true and the device has no other user, it is kept
+// attached instead, so that a container restarting with the same device
+// reuses it without being hotplugged again.
+func (dm *deviceManager) ReleaseDevice(ctx context.Context, id string, dr api.DeviceReceiver, keep bool) error {
+	dm.Lock()
+	defer dm.Unlock()
+
+	d, ok := dm.devices[id]
+	if !ok {
+		return ErrDeviceNotExist
+	}
+
+	refs := d.Dereference()
+	if keep && refs == 0 && d.GetAttachCount() == 1 {
+		deviceLogger().WithField("device", d.GetHostPath()).Info("Device released, kept attached for reuse")
+		dm.released[id] = time.Now()
+		return nil
+	}
+
+	if d.GetAttachCount() > 0 {
+		if err := d.Detach(ctx, dr); err != nil {
+			d.Reference()
+			return err
+		}
+	}
+
+	if refs == 0 {
+		if d.GetAttachCount() > 0 {
+			return ErrRemoveAttachedDevice
+		}
+		delete(dm.released, id)
+		delete(dm.devices, id)
+	}
+	return nil
+}
+
+// DetachReleasedDevices detaches and removes the devices released for more
+// than timeout, and not reused since.
+func (dm *deviceManager) DetachReleasedDevices(ctx context.Context, dr api.DeviceReceiver, timeout time.Duration) error {
+	dm.Lock()
+	defer dm.Unlock()
+
+	for id, released := range dm.released {
+		if time.Since(released) < timeout {
+			continue
+		}
+
+		d, ok := dm.devices[id]
+		if !ok {
+			delete(dm.released, id)
+			continue
+		}
+
+		if d.GetAttachCount() > 0 {
+			if err := d.Detach(ctx, dr); err != nil {
+				return err
+			}
+		}
+		delete(dm.released, id)
+		delete(dm.devices, id)
+	}
+	return nil
+}
+
 func (dm *deviceManager) newDeviceID() (string, error) {
 	for i := 0; i < 5; i++ {
 		// generate an random ID
@@ -199,6 +273,13 @@ func (dm *deviceManager) AttachDevice(ctx context.Context, id string, dr api.Dev
 		return ErrDeviceNotExist
 	}
 
+	if _, ok := dm.released[id]; ok {
+		// the device is still attached, its new user takes over the
+		// attachment of the previous one
+		delete(dm.released, id)
+		return nil
+	}
+
 	if err := d.Attach(ctx, dr); err != nil {
 		return err
 	}
@@ -252,6 +333,13 @@ func (dm *deviceManager) IsDeviceAttached(id string) bool {
 	return d.GetAttachCount() > 0
 }
 
+func (dm *deviceManager) IsDeviceReleased(id string) bool {
+	dm.RLock()
+	defer dm.RUnlock()
+	_, ok := dm.released[id]
+	return ok
+}
+
 // NewDevice creates a device based on specified DeviceInfo
 func (dm *deviceManager) LoadDevices(devStates []persistapi.DeviceState) {
 	dm.Lock()
@@ -281,5 +369,10 @@ func (dm *deviceManager) LoadDevices(devStates []persistapi.DeviceState) {
 
 		dev.Load(ds)
 		dm.devices[dev.DeviceID()] = dev
+
+		// a device attached without user was released for reuse
+		if ds.RefCount == 0 && ds.AttachCount > 0 {
+			dm.released[dev.DeviceID()] = time.Now()
+		}
 	}
 }
@@ -14,11 +14,13 @@ import (
 	"path/filepath"
 	"strconv"
 	"testing"
+	"time"
 
 	ktu "github.com/kata-containers/kata-containers/src/runtime/pkg/katatestutils"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/device/api"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/device/config"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/device/drivers"
+	persistapi "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/persist/api"
 	"github.com/stretchr/testify/assert"
 
 	"golang.org/x/sys/unix"
@@ -345,3 +347,108 @@ func TestAttachDetachDevice(t *testing.T) {
 	err = dm.RemoveDevice(device.DeviceID())
 	assert.Nil(t, err)
 }
+
+// hotplugCounter counts the hotplugs of the devices
+type hotplugCounter struct {
+	api.MockDeviceReceiver
+	added, removed int
+}
+
+func (h *hotplugCounter) HotplugAddDevice(context.Context, api.Device, config.DeviceType) error {
+	h.added++
+	return nil
+}
+
+func (h *hotplugCounter) HotplugRemoveDevice(context.Context, api.Device, config.DeviceType) error {
+	h.removed++
+	return nil
+}
+
+func TestReleaseDevice(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+	dm := NewDeviceManager(VirtioBlock, false, "", nil)
+	dr := &hotplugCounter{}
+
+	deviceInfo := config.DeviceInfo{
+		HostPath:      "/dev/hda",
+		ContainerPath: "/dev/hda",
+		DevType:       "b",
+		Major:         3,
+		Minor:         0,
+	}
+
+	device, err := dm.NewDevice(deviceInfo)
+	assert.NoError(err)
+	assert.NoError(dm.AttachDevice(ctx, device.DeviceID(), dr))
+	assert.Equal(1, dr.added)
+
+	// the device is kept attached for reuse
+	assert.NoError(dm.ReleaseDevice(ctx, device.DeviceID(), dr, true))
+	assert.Equal(0, dr.removed)
+	assert.True(dm.IsDeviceAttached(device.DeviceID()))
+	assert.True(dm.IsDeviceReleased(device.DeviceID()))
+
+	// a restarting container reuses it without hotplug
+	reused, err := dm.NewDevice(deviceInfo)
+	assert.NoError(err)
+	assert.Equal(device.DeviceID(), reused.DeviceID())
+	assert.NoError(dm.AttachDevice(ctx, reused.DeviceID(), dr))
+	assert.Equal(1, dr.added)
+	assert.Equal(uint(1), reused.GetAttachCount())
+	assert.False(dm.IsDeviceReleased(device.DeviceID()))
+
+	// the reused device is not detached with the released ones
+	assert.NoError(dm.DetachReleasedDevices(ctx, dr, 0))
+	assert.Equal(0, dr.removed)
+
+	assert.NoError(dm.ReleaseDevice(ctx, device.DeviceID(), dr, true))
+	assert.NoError(dm.DetachReleasedDevices(ctx, dr, time.Hour))
+	assert.Equal(0, dr.removed)
+	assert.NoError(dm.DetachReleasedDevices(ctx, dr, 0))
+	assert.Equal(1, dr.removed)
+	assert.Nil(dm.GetDeviceByID(device.DeviceID()))
+
+	// a device used by other containers is not kept for reuse
+	device, err = dm.NewDevice(deviceInfo)
+	assert.NoError(err)
+	assert.NoError(dm.AttachDevice(ctx, device.DeviceID(), dr))
+	_, err = dm.NewDevice(deviceInfo)
+	assert.NoError(err)
+	assert.NoError(dm.AttachDevice(ctx, device.DeviceID(), dr))
+	assert.Equal(2, dr.added)
+
+	assert.NoError(dm.ReleaseDevice(ctx, device.DeviceID(), dr, true))
+	assert.Equal(uint(1), device.GetAttachCount())
+	assert.NoError(dm.ReleaseDevice(ctx, device.DeviceID(), dr, false))
+	assert.Equal(2, dr.removed)
+	assert.Nil(dm.GetDeviceByID(device.DeviceID()))
+
+	assert.Equal(ErrDeviceNotExist, dm.ReleaseDevice(ctx, device.DeviceID(), dr, false))
+}
+
+func TestLoadReleasedDevices(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+	dm := NewDeviceManager(VirtioBlock, false, "", nil)
+	dr := &hotplugCounter{}
+
+	device, err := dm.NewDevice(config.DeviceInfo{
+		HostPath:      "/dev/hda",
+		ContainerPath: "/dev/hda",
+		DevType:       "b",
+		Major:         3,
+		Minor:         0,
+	})
+	assert.NoError(err)
+	assert.NoError(dm.AttachDevice(ctx, device.DeviceID(), dr))
+	assert.NoError(dm.ReleaseDevice(ctx, device.DeviceID(), dr, true))
+
+	// the released devices are still released once restored
+	restored := NewDeviceManager(VirtioBlock, false, "", nil)
+	restored.LoadDevices([]persistapi.DeviceState{device.Save()})
+	assert.True(restored.IsDeviceAttached(device.DeviceID()))
+	assert.NoError(restored.DetachReleasedDevices(ctx, dr, 0))
+	assert.Equal(1, dr.removed)
+	assert.Nil(restored.GetDeviceByID(device.DeviceID()))
+}
@@ -0,0 +1,229 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+// Package plugin calls the external device plugins, host daemons preparing
+// the devices of a class, e.g. FPGAs or smart NICs, asked by the containers
+// with the io.katacontainers.device_plugins annotation, so that vendor
+// hardware doesn't need changes in the runtime.
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	pb "github.com/kata-containers/kata-containers/src/runtime/protocols/deviceplugin"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/device/api"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/device/config"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
+	"google.golang.org/grpc"
+)
+
+const (
+	// the timeout of a call to a plugin, including the connection
+	callTimeout = 30 * time.Second
+
+	// the permission bits of the devices of the plugins not setting them
+	defaultFileMode = os.FileMode(0666)
+)
+
+var (
+	// Dir is the directory of the sockets of the plugins, <class>.sock, a
+	// variable to be changed in the tests.
+	Dir = "/run/kata-containers/device-plugins"
+
+	classRegexp = regexp.MustCompile(`^[a-z0-9]([a-z0-9.-]{0,251}[a-z0-9])?$`)
+)
+
+func pluginLogger() *logrus.Entry {
+	return api.DeviceLogger().WithField("subsystem", "device-plugin")
+}
+
+// Request is the request of a container for the devices of a class.
+type Request struct {
+	// Class is the class of the devices, which is the name of the plugin.
+	Class string
+
+	// IDs are the IDs of the devices, meaningful to the plugin only.
+	IDs []string
+}
+
+// ParseRequests parses the device_plugins annotation of a container, a
+// semicolon separated list of <class>=<id>[,<id>...], e.g.
+// "fpga.example.com=fpga0;smartnic.example.com=vf1,vf2".
+func ParseRequests(annotation string) ([]Request, error) {
+	var requests []Request
+	classes := make(map[string]bool)
+
+	for _, r := range strings.Split(annotation, ";") {
+		r = strings.TrimSpace(r)
+		if r == "" {
+			continue
+		}
+
+		kv := strings.SplitN(r, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid device plugin request %q, expecting <class>=<id>[,<id>...]", r)
+		}
+
+		class := strings.TrimSpace(kv[0])
+		if !classRegexp.MatchString(class) {
+			return nil, fmt.Errorf("invalid device plugin class %q", class)
+		}
+		if classes[class] {
+			return nil, fmt.Errorf("duplicate device plugin class %q", class)
+		}
+		classes[class] = true
+
+		var ids []string
+		for _, id := range strings.Split(kv[1], ",") {
+			if id = strings.TrimSpace(id); id == "" {
+				return nil, fmt.Errorf("empty device ID for device plugin class %q", class)
+			}
+			ids = append(ids, id)
+		}
+
+		requests = append(requests, Request{Class: class, IDs: ids})
+	}
+
+	return requests, nil
+}
+
+// call connects to the plugin of a class and calls it.
+func call(ctx context.Context, class string, f func(context.Context, pb.DevicePluginClient) error) error {
+	socket := filepath.Join(Dir, class+".sock")
+	if _, err := os.Stat(socket); err != nil {
+		return errors.Wrapf(err, "no device plugin for class %q", class)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, callTimeout)
+	defer cancel()
+
+	conn, err := grpc.DialContext(ctx, fmt.Sprintf("unix://%s", socket), grpc.WithInsecure(), grpc.WithBlock())
+	if err != nil {
+		return errors.Wrapf(err, "failed to connect to device plugin %q", class)
+	}
+	defer conn.Close()
+
+	return f(ctx, pb.NewDevicePluginClient(conn))
+}
+
+// deviceInfo returns the info of a device returned by a plugin, to be added
+// to the device manager.
+func deviceInfo(d *pb.Device) (config.DeviceInfo, error) {
+	var st unix.Stat_t
+	if err := unix.Stat(d.HostPath, &st); err != nil {
+		return config.DeviceInfo{}, errors.Wrapf(err, "invalid device %q", d.HostPath)
+	}
+
+	var devType string
+	switch st.Mode & unix.S_IFMT {
+	case unix.S_IFCHR:
+		devType = "c"
+	case unix.S_IFBLK:
+		devType = "b"
+	default:
+		return config.DeviceInfo{}, fmt.Errorf("%q is not a device", d.HostPath)
+	}
+
+	info := config.DeviceInfo{
+		HostPath:      d.HostPath,
+		ContainerPath: d.ContainerPath,
+		DevType:       devType,
+		Major:         int64(unix.Major(uint64(st.Rdev))),
+		Minor:         int64(unix.Minor(uint64(st.Rdev))),
+		FileMode:      os.FileMode(d.FileMode).Perm(),
+		UID:           d.Uid,
+		GID:           d.Gid,
+	}
+	if info.ContainerPath == "" {
+		info.ContainerPath = d.HostPath
+	}
+	if info.FileMode == 0 {
+		info.FileMode = defaultFileMode
+	}
+
+	return info, nil
+}
+
+// Prepare asks the plugins to prepare the devices of a container, returning
+// the devices to add to the container and the environment variables of its
+// process. The devices already prepared are released on failure.
+func Prepare(ctx context.Context, sandboxID, containerID string, requests []Request, annotations map[string]string) (devices []config.DeviceInfo, envs []string, err error) {
+	var prepared []Request
+	defer func() {
+		if err != nil && len(prepared) > 0 {
+			if err := Release(ctx, sandboxID, containerID, prepared); err != nil {
+				pluginLogger().WithError(err).Warn("failed to release the devices")
+			}
+		}
+	}()
+
+	for _, r := range requests {
+		pluginLogger().WithFields(logrus.Fields{
+			"class":     r.Class,
+			"ids":       r.IDs,
+			"container": containerID,
+		}).Info("Preparing plugin devices")
+
+		var resp *pb.PrepareResponse
+		err = call(ctx, r.Class, func(ctx context.Context, c pb.DevicePluginClient) error {
+			var err error
+			resp, err = c.Prepare(ctx, &pb.PrepareRequest{
+				SandboxId:   sandboxID,
+				ContainerId: containerID,
+				DeviceIds:   r.IDs,
+				Annotations: annotations,
+			})
+			return err
+		})
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to prepare the devices of class %q", r.Class)
+		}
+		prepared = append(prepared, r)
+
+		for _, d := range resp.Devices {
+			info, err := deviceInfo(d)
+			if err != nil {
+				return nil, nil, errors.Wrapf(err, "device plugin %q", r.Class)
+			}
+			devices = append(devices, info)
+		}
+		envs = append(envs, resp.Envs...)
+	}
+
+	return devices, envs, nil
+}
+
+// Release asks the plugins to release the devices of a deleted container,
+// returning the first error, if any.
+func Release(ctx context.Context, sandboxID, containerID string, requests []Request) error {
+	var firstErr error
+
+	for _, r := range requests {
+		err := call(ctx, r.Class, func(ctx context.Context, c pb.DevicePluginClient) error {
+			_, err := c.Release(ctx, &pb.ReleaseRequest{
+				SandboxId:   sandboxID,
+				ContainerId: containerID,
+				DeviceIds:   r.IDs,
+			})
+			return err
+		})
+		if err != nil {
+			pluginLogger().WithError(err).WithField("class", r.Class).Error("failed to release the devices")
+			if firstErr == nil {
+				firstErr = errors.Wrapf(err, "failed to release the devices of class %q", r.Class)
+			}
+		}
+	}
+
+	return firstErr
+}
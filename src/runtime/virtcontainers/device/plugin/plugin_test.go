@@ -0,0 +1,124 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package plugin
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gogo/protobuf/types"
+	pb "github.com/kata-containers/kata-containers/src/runtime/protocols/deviceplugin"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+)
+
+type fakePlugin struct {
+	pb.UnimplementedDevicePluginServer
+	devices  []*pb.Device
+	prepared map[string][]string
+}
+
+func (p *fakePlugin) Prepare(ctx context.Context, req *pb.PrepareRequest) (*pb.PrepareResponse, error) {
+	p.prepared[req.ContainerId] = req.DeviceIds
+	return &pb.PrepareResponse{
+		Devices: p.devices,
+		Envs:    []string{"FPGA_IDS=" + req.DeviceIds[0]},
+	}, nil
+}
+
+func (p *fakePlugin) Release(ctx context.Context, req *pb.ReleaseRequest) (*types.Empty, error) {
+	delete(p.prepared, req.ContainerId)
+	return &types.Empty{}, nil
+}
+
+func startFakePlugin(t *testing.T, class string, p *fakePlugin) func() {
+	l, err := net.Listen("unix", filepath.Join(Dir, class+".sock"))
+	assert.NoError(t, err)
+
+	s := grpc.NewServer()
+	pb.RegisterDevicePluginServer(s, p)
+	go s.Serve(l)
+
+	return s.Stop
+}
+
+func TestParseRequests(t *testing.T) {
+	assert := assert.New(t)
+
+	requests, err := ParseRequests("")
+	assert.NoError(err)
+	assert.Empty(requests)
+
+	requests, err = ParseRequests("fpga.example.com=fpga0; smartnic.example.com=vf1,vf2")
+	assert.NoError(err)
+	assert.Equal([]Request{
+		{Class: "fpga.example.com", IDs: []string{"fpga0"}},
+		{Class: "smartnic.example.com", IDs: []string{"vf1", "vf2"}},
+	}, requests)
+
+	for _, a := range []string{
+		"fpga.example.com",
+		"fpga.example.com=",
+		"fpga.example.com=fpga0,",
+		"../fpga=fpga0",
+		"FPGA=fpga0",
+		"fpga=fpga0;fpga=fpga1",
+	} {
+		_, err := ParseRequests(a)
+		assert.Error(err, a)
+	}
+}
+
+func TestPrepareRelease(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "device-plugins")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	savedDir := Dir
+	Dir = dir
+	defer func() {
+		Dir = savedDir
+	}()
+
+	p := &fakePlugin{
+		devices:  []*pb.Device{{HostPath: "/dev/null", ContainerPath: "/dev/fpga0"}},
+		prepared: make(map[string][]string),
+	}
+	defer startFakePlugin(t, "fpga.example.com", p)()
+
+	requests := []Request{{Class: "fpga.example.com", IDs: []string{"fpga0"}}}
+	devices, envs, err := Prepare(context.Background(), "sandbox", "container", requests, nil)
+	assert.NoError(err)
+	assert.Equal([]string{"fpga0"}, p.prepared["container"])
+	assert.Equal([]string{"FPGA_IDS=fpga0"}, envs)
+	assert.Len(devices, 1)
+	assert.Equal("/dev/fpga0", devices[0].ContainerPath)
+	assert.Equal("c", devices[0].DevType)
+	assert.Equal(int64(1), devices[0].Major)
+	assert.Equal(int64(3), devices[0].Minor)
+	assert.Equal(defaultFileMode, devices[0].FileMode)
+
+	assert.NoError(Release(context.Background(), "sandbox", "container", requests))
+	assert.Empty(p.prepared)
+
+	// the prepared devices are released when a plugin fails
+	requests = append(requests, Request{Class: "missing.example.com", IDs: []string{"nic0"}})
+	_, _, err = Prepare(context.Background(), "sandbox", "container", requests, nil)
+	assert.Error(err)
+	assert.Empty(p.prepared)
+
+	// not a device
+	p.devices = []*pb.Device{{HostPath: dir}}
+	_, _, err = Prepare(context.Background(), "sandbox", "container", requests[:1], nil)
+	assert.Error(err)
+	assert.Empty(p.prepared)
+}
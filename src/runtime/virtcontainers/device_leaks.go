@@ -0,0 +1,145 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"context"
+	"sort"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/types"
+)
+
+// DeviceLeak is a device whose attachments to the VM don't match its users,
+// e.g. after a crash between the attachment of a device and the save of the
+// sandbox state.
+type DeviceLeak struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	HostPath string `json:"host_path"`
+	// AttachCount is how many times the device is attached to the VM.
+	AttachCount uint `json:"attach_count"`
+	// Users is how many times it is used by the sandbox and its containers.
+	Users uint `json:"users"`
+}
+
+// Leaked returns whether the device is attached more than it is used, the
+// only case which can be cleaned up. A device attached less than it is used
+// is missing in the VM.
+func (l DeviceLeak) Leaked() bool {
+	return l.AttachCount > l.Users
+}
+
+// deviceUsers returns the number of users of the devices: the sandbox
+// itself, the containers created in the guest and the devices kept attached
+// for reuse.
+func (s *Sandbox) deviceUsers() map[string]uint {
+	users := make(map[string]uint)
+
+	for _, id := range s.state.SandboxDevices {
+		users[id]++
+	}
+
+	for _, c := range s.containers {
+		// the devices of a container are attached when it is created in
+		// the guest and detached when it stops
+		if c.state.State == types.StateStopped || c.state.CreationDeferred {
+			continue
+		}
+
+		for _, dev := range c.devices {
+			users[dev.ID]++
+		}
+		if c.isDriveUsed() && c.state.BlockDeviceID != "" {
+			users[c.state.BlockDeviceID]++
+		}
+		for _, m := range c.mounts {
+			if m.BlockDeviceID != "" && !isSystemMount(m.Source) {
+				users[m.BlockDeviceID]++
+			}
+		}
+	}
+
+	for _, dev := range s.devManager.GetAllDevices() {
+		if s.devManager.IsDeviceReleased(dev.DeviceID()) {
+			users[dev.DeviceID()]++
+		}
+	}
+
+	return users
+}
+
+// DeviceLeaks returns the devices whose attachments to the VM don't match
+// their users.
+func (s *Sandbox) DeviceLeaks() []DeviceLeak {
+	if s.devManager == nil {
+		return nil
+	}
+
+	var leaks []DeviceLeak
+	users := s.deviceUsers()
+	for _, dev := range s.devManager.GetAllDevices() {
+		id := dev.DeviceID()
+		if dev.GetAttachCount() == users[id] {
+			continue
+		}
+
+		leaks = append(leaks, DeviceLeak{
+			ID:          id,
+			Type:        string(dev.DeviceType()),
+			HostPath:    dev.GetHostPath(),
+			AttachCount: dev.GetAttachCount(),
+			Users:       users[id],
+		})
+	}
+
+	sort.Slice(leaks, func(i, j int) bool {
+		return leaks[i].ID < leaks[j].ID
+	})
+	return leaks
+}
+
+// CleanupDeviceLeaks detaches the devices attached more than they are used,
+// and removes the ones not used anymore. It returns the leaks cleaned up.
+func (s *Sandbox) CleanupDeviceLeaks(ctx context.Context) ([]DeviceLeak, error) {
+	var cleaned []DeviceLeak
+	for _, leak := range s.DeviceLeaks() {
+		if !leak.Leaked() {
+			continue
+		}
+
+		s.Logger().WithFields(logrus.Fields{
+			"device":       leak.HostPath,
+			"device-id":    leak.ID,
+			"attach-count": leak.AttachCount,
+			"users":        leak.Users,
+		}).Warn("Cleaning up leaked device")
+
+		for i := leak.Users; i < leak.AttachCount; i++ {
+			if err := s.devManager.DetachDevice(ctx, leak.ID, s); err != nil {
+				return cleaned, err
+			}
+		}
+
+		// drop the stale references of a device not used anymore
+		for leak.Users == 0 && s.devManager.GetDeviceByID(leak.ID) != nil {
+			if err := s.devManager.RemoveDevice(leak.ID); err != nil {
+				return cleaned, err
+			}
+		}
+
+		cleaned = append(cleaned, leak)
+	}
+
+	if len(cleaned) > 0 {
+		if err := s.Save(); err != nil {
+			return cleaned, err
+		}
+	}
+
+	return cleaned, nil
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/device/config"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/device/manager"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/persist"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/types"
+)
+
+func TestDeviceLeaks(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	s := &Sandbox{
+		id:         "test-device-leaks",
+		ctx:        ctx,
+		devManager: manager.NewDeviceManager(manager.VirtioSCSI, false, "", nil),
+		hypervisor: &mockHypervisor{},
+		agent:      &mockAgent{},
+		config:     &SandboxConfig{},
+		containers: make(map[string]*Container),
+		state:      types.SandboxState{BlockIndexMap: make(map[int]struct{})},
+	}
+	var err error
+	s.store, err = persist.GetDriver()
+	assert.NoError(err)
+	defer s.store.Destroy(s.id)
+
+	newDevice := func(path string, minor int64) string {
+		dev, err := s.devManager.NewDevice(config.DeviceInfo{
+			HostPath:      path,
+			ContainerPath: path,
+			DevType:       "b",
+			Major:         8,
+			Minor:         minor,
+		})
+		assert.NoError(err)
+		assert.NoError(s.devManager.AttachDevice(ctx, dev.DeviceID(), s))
+		return dev.DeviceID()
+	}
+
+	used := newDevice("/dev/sda", 0)
+	s.containers["running"] = &Container{
+		id:      "running",
+		sandbox: s,
+		devices: []ContainerDevice{{ID: used}},
+		state:   types.ContainerState{State: types.StateRunning},
+	}
+
+	disk := newDevice("/dev/sdb", 16)
+	s.state.SandboxDevices = []string{disk}
+
+	// the device of a stopped container is not detached
+	orphan := newDevice("/dev/sdc", 32)
+	s.containers["stopped"] = &Container{
+		id:      "stopped",
+		sandbox: s,
+		devices: []ContainerDevice{{ID: orphan}},
+		state:   types.ContainerState{State: types.StateStopped},
+	}
+
+	leaks := s.DeviceLeaks()
+	assert.Len(leaks, 1)
+	assert.Equal(DeviceLeak{ID: orphan, Type: string(config.DeviceBlock), HostPath: "/dev/sdc", AttachCount: 1, Users: 0}, leaks[0])
+	assert.True(leaks[0].Leaked())
+
+	// attached again after a crash
+	assert.NoError(s.devManager.AttachDevice(ctx, used, s))
+	assert.Len(s.DeviceLeaks(), 2)
+
+	cleaned, err := s.CleanupDeviceLeaks(ctx)
+	assert.NoError(err)
+	assert.Len(cleaned, 2)
+	assert.Empty(s.DeviceLeaks())
+	assert.True(s.devManager.IsDeviceAttached(used))
+	assert.True(s.devManager.IsDeviceAttached(disk))
+	assert.Nil(s.devManager.GetDeviceByID(orphan))
+
+	// a device missing in the VM is reported, not cleaned up
+	assert.NoError(s.devManager.DetachDevice(ctx, disk, s))
+	leaks = s.DeviceLeaks()
+	assert.Len(leaks, 1)
+	assert.False(leaks[0].Leaked())
+
+	cleaned, err = s.CleanupDeviceLeaks(ctx)
+	assert.NoError(err)
+	assert.Empty(cleaned)
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/device/plugin"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/annotations"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+// devicePluginsAnnotationName is the name enabling the restricted device
+// plugins annotation in enable_annotations.
+const devicePluginsAnnotationName = "device_plugins"
+
+// devicePluginsAnnotationEnabled returns whether the device plugins
+// annotation is enabled by enable_annotations. The annotations of the
+// sandbox are checked by the oci package, not the ones of its containers.
+func devicePluginsAnnotationEnabled(enabled []string) bool {
+	for _, e := range enabled {
+		if matched, _ := regexp.MatchString(e, devicePluginsAnnotationName); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// pluginRequests returns the requests of the container to the device plugins,
+// of the classes enabled in the configuration only.
+func (c *Container) pluginRequests() ([]plugin.Request, error) {
+	value, ok := c.config.Annotations[annotations.DevicePlugins]
+	if !ok {
+		return nil, nil
+	}
+
+	if !devicePluginsAnnotationEnabled(c.sandbox.config.HypervisorConfig.EnableAnnotations) {
+		return nil, fmt.Errorf("annotation %s is not enabled", annotations.DevicePlugins)
+	}
+
+	requests, err := plugin.ParseRequests(value)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, r := range requests {
+		if !containsString(c.sandbox.config.DevicePluginClasses, r.Class) {
+			return nil, fmt.Errorf("device plugin class %q is not enabled, see device_plugin_classes", r.Class)
+		}
+	}
+
+	return requests, nil
+}
+
+// preparePluginDevices asks the device plugins to prepare the devices of the
+// container, and adds them to its configuration, before its devices are
+// created.
+func (c *Container) preparePluginDevices(ctx context.Context) error {
+	requests, err := c.pluginRequests()
+	if err != nil || len(requests) == 0 {
+		return err
+	}
+
+	devices, envs, err := plugin.Prepare(ctx, c.sandboxID, c.id, requests, c.config.Annotations)
+	if err != nil {
+		return err
+	}
+
+	c.config.DeviceInfos = append(c.config.DeviceInfos, devices...)
+
+	// the agent creates the devices of the spec in the container
+	if spec := c.config.CustomSpec; spec != nil {
+		if spec.Linux != nil {
+			for _, d := range devices {
+				mode, uid, gid := d.FileMode, d.UID, d.GID
+				spec.Linux.Devices = append(spec.Linux.Devices, specs.LinuxDevice{
+					Path:     d.ContainerPath,
+					Type:     d.DevType,
+					Major:    d.Major,
+					Minor:    d.Minor,
+					FileMode: &mode,
+					UID:      &uid,
+					GID:      &gid,
+				})
+			}
+		}
+		if spec.Process != nil {
+			spec.Process.Env = append(spec.Process.Env, envs...)
+		}
+	}
+
+	return nil
+}
+
+// releasePluginDevices asks the device plugins to release the devices of the
+// deleted container.
+func (c *Container) releasePluginDevices(ctx context.Context) error {
+	requests, err := c.pluginRequests()
+	if err != nil || len(requests) == 0 {
+		return err
+	}
+
+	return plugin.Release(ctx, c.sandboxID, c.id, requests)
+}
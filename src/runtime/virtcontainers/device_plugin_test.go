@@ -0,0 +1,48 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"testing"
+
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/device/plugin"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/annotations"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContainerPluginRequests(t *testing.T) {
+	assert := assert.New(t)
+
+	c := &Container{
+		sandbox: &Sandbox{config: &SandboxConfig{}},
+		config:  &ContainerConfig{Annotations: map[string]string{}},
+	}
+
+	requests, err := c.pluginRequests()
+	assert.NoError(err)
+	assert.Empty(requests)
+
+	// the annotation must be enabled
+	c.config.Annotations[annotations.DevicePlugins] = "fpga.example.com=fpga0"
+	c.sandbox.config.DevicePluginClasses = []string{"fpga.example.com"}
+	_, err = c.pluginRequests()
+	assert.Error(err)
+
+	// the classes must be enabled in the configuration
+	c.sandbox.config.HypervisorConfig.EnableAnnotations = []string{"device_plugins"}
+	c.sandbox.config.DevicePluginClasses = nil
+	_, err = c.pluginRequests()
+	assert.Error(err)
+
+	c.sandbox.config.DevicePluginClasses = []string{"fpga.example.com"}
+	requests, err = c.pluginRequests()
+	assert.NoError(err)
+	assert.Equal([]plugin.Request{{Class: "fpga.example.com", IDs: []string{"fpga0"}}}, requests)
+
+	c.config.Annotations[annotations.DevicePlugins] = "fpga.example.com=fpga0;smartnic.example.com=vf1"
+	_, err = c.pluginRequests()
+	assert.Error(err)
+}
@@ -0,0 +1,209 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// SandboxPlan is what would be launched for a sandbox, resolved by a dry
+// run of its creation without launching anything, e.g. for debugging the
+// configuration or for the admission tools.
+type SandboxPlan struct {
+	ID         string          `json:"id"`
+	Hypervisor HypervisorPlan  `json:"hypervisor"`
+	Network    NetworkPlan     `json:"network"`
+	Containers []ContainerPlan `json:"containers,omitempty"`
+
+	// the host disks created for the sandbox
+	GuestSwapMB     uint32 `json:"guest_swap_mb,omitempty"`
+	EphemeralDisk   string `json:"ephemeral_disk,omitempty"`
+	EphemeralDiskMB uint32 `json:"ephemeral_disk_mb,omitempty"`
+}
+
+// HypervisorPlan is the VM of a sandbox plan.
+type HypervisorPlan struct {
+	Type         HypervisorType `json:"type"`
+	Path         string         `json:"path"`
+	Kernel       string         `json:"kernel"`
+	KernelParams string         `json:"kernel_params,omitempty"`
+	Image        string         `json:"image,omitempty"`
+	Initrd       string         `json:"initrd,omitempty"`
+	VCPUs        uint32         `json:"vcpus"`
+	MaxVCPUs     uint32         `json:"max_vcpus"`
+	MemoryMB     uint32         `json:"memory_mb"`
+	// DeviceArgs are the command line arguments of the devices of the
+	// VM, for the hypervisors able to generate them.
+	DeviceArgs []string `json:"device_args,omitempty"`
+}
+
+// NetworkPlan is the network of a sandbox plan.
+type NetworkPlan struct {
+	NetNSPath         string         `json:"netns,omitempty"`
+	InterworkingModel string         `json:"interworking_model"`
+	Endpoints         []EndpointPlan `json:"endpoints,omitempty"`
+}
+
+// EndpointPlan is a network endpoint found in the network namespace of the
+// sandbox.
+type EndpointPlan struct {
+	Name         string       `json:"name"`
+	Type         EndpointType `json:"type"`
+	HardwareAddr string       `json:"hw_addr"`
+	Addresses    []string     `json:"addresses,omitempty"`
+}
+
+// ContainerPlan is a container of a sandbox plan.
+type ContainerPlan struct {
+	ID      string   `json:"id"`
+	Rootfs  string   `json:"rootfs"`
+	Mounts  []string `json:"mounts,omitempty"`
+	Devices []string `json:"devices,omitempty"`
+}
+
+// deviceArgsPlanner is implemented by the hypervisors able to generate the
+// command line arguments of the devices of the VM without launching it.
+type deviceArgsPlanner interface {
+	deviceArgs() []string
+}
+
+// vmCleaner is implemented by the hypervisors creating host files when the
+// VM is configured, before it is launched, e.g. the rootfs disk overlay.
+type vmCleaner interface {
+	cleanupVM() error
+}
+
+// dryRun resolves the plan of a new sandbox. Nothing is launched nor
+// stored, what was created for the VM configuration being removed.
+func (s *Sandbox) dryRun(ctx context.Context) error {
+	if s.state.State != "" {
+		return fmt.Errorf("sandbox %s already exists", s.id)
+	}
+	defer s.dryRunCleanup(ctx)
+
+	conf := s.config.HypervisorConfig
+	plan := &SandboxPlan{
+		ID: s.id,
+		Hypervisor: HypervisorPlan{
+			Type:         s.config.HypervisorType,
+			KernelParams: strings.Join(SerializeParams(conf.KernelParams, "="), " "),
+			VCPUs:        conf.NumVCPUs,
+			MaxVCPUs:     conf.DefaultMaxVCPUs,
+			MemoryMB:     conf.MemorySize,
+		},
+	}
+
+	var err error
+	if plan.Hypervisor.Path, err = conf.HypervisorAssetPath(); err != nil {
+		return err
+	}
+	if plan.Hypervisor.Kernel, err = conf.KernelAssetPath(); err != nil {
+		return err
+	}
+	if plan.Hypervisor.Image, err = conf.ImageAssetPath(); err != nil {
+		return err
+	}
+	if plan.Hypervisor.Initrd, err = conf.InitrdAssetPath(); err != nil {
+		return err
+	}
+	if h, ok := s.hypervisor.(deviceArgsPlanner); ok {
+		plan.Hypervisor.DeviceArgs = h.deviceArgs()
+	}
+
+	if plan.Network, err = s.networkPlan(); err != nil {
+		return err
+	}
+
+	for _, c := range s.config.Containers {
+		cp := ContainerPlan{
+			ID:     c.ID,
+			Rootfs: c.RootFs.Source,
+		}
+		for _, m := range c.Mounts {
+			cp.Mounts = append(cp.Mounts, fmt.Sprintf("%s:%s", m.Source, m.Destination))
+		}
+		for _, d := range c.DeviceInfos {
+			cp.Devices = append(cp.Devices, d.ContainerPath)
+		}
+		plan.Containers = append(plan.Containers, cp)
+	}
+
+	plan.GuestSwapMB = s.config.GuestSwapConfig.SizeMB
+	if s.config.EphemeralDiskConfig.enabled() {
+		plan.EphemeralDisk = s.config.EphemeralDiskConfig.Backend
+		plan.EphemeralDiskMB = s.config.EphemeralDiskConfig.SizeMB
+	}
+
+	s.plan = plan
+	s.Logger().WithField("plan", plan).Info("sandbox dry run")
+
+	return nil
+}
+
+// dryRunCleanup removes the host files of the hypervisor and the run
+// storage of a dry run, and releases the SELinux categories of the VM.
+func (s *Sandbox) dryRunCleanup(ctx context.Context) {
+	if err := s.hypervisor.cleanup(ctx); err != nil {
+		s.Logger().WithError(err).Warn("failed to clean up the hypervisor")
+	}
+	if h, ok := s.hypervisor.(vmCleaner); ok {
+		if err := h.cleanupVM(); err != nil {
+			s.Logger().WithError(err).Warn("failed to remove the VM files")
+		}
+	}
+
+	releaseSELinuxLabels(&s.config.HypervisorConfig)
+
+	if err := s.store.Destroy(s.id); err != nil {
+		s.Logger().WithError(err).Warn("failed to remove the run storage")
+	}
+}
+
+// networkPlan returns the endpoints which would be attached to the VM,
+// found in the network namespace without creating them.
+func (s *Sandbox) networkPlan() (NetworkPlan, error) {
+	config := s.config.NetworkConfig
+	plan := NetworkPlan{
+		NetNSPath:         config.NetNSPath,
+		InterworkingModel: config.InterworkingModel.GetModel(),
+	}
+
+	if config.DisableNewNetNs || config.NetNSPath == "" {
+		return plan, nil
+	}
+
+	var endpoints []Endpoint
+	var err error
+	if config.StaticConfig != nil {
+		endpoints, err = createEndpointsFromStaticConfig(config.NetNSPath, &config)
+	} else {
+		endpoints, err = createEndpointsFromScan(config.NetNSPath, &config)
+	}
+	if err != nil {
+		return plan, err
+	}
+
+	for _, e := range endpoints {
+		ep := EndpointPlan{
+			Name:         e.Name(),
+			Type:         e.Type(),
+			HardwareAddr: e.HardwareAddr(),
+		}
+		for _, addr := range e.Properties().Addrs {
+			ep.Addresses = append(ep.Addresses, addr.IPNet.String())
+		}
+		plan.Endpoints = append(plan.Endpoints, ep)
+	}
+
+	return plan, nil
+}
+
+// GetDryRunPlan returns the plan of a sandbox created in dry run mode.
+func (s *Sandbox) GetDryRunPlan() *SandboxPlan {
+	return s.plan
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateSandboxDryRun(t *testing.T) {
+	defer cleanUp()
+	assert := assert.New(t)
+
+	config := newTestSandboxConfigNoop()
+	config.DryRun = true
+	config.GuestSwapConfig = GuestSwapConfig{SizeMB: 512}
+
+	ctx := WithNewAgentFunc(context.Background(), newMockAgent)
+	p, err := CreateSandbox(ctx, config, nil)
+	assert.NoError(err)
+
+	plan := p.GetDryRunPlan()
+	assert.NotNil(plan)
+	assert.Equal(testSandboxID, plan.ID)
+	assert.Equal(MockHypervisor, plan.Hypervisor.Type)
+	assert.Equal(filepath.Join(testDir, testHypervisor), plan.Hypervisor.Path)
+	assert.Equal(filepath.Join(testDir, testKernel), plan.Hypervisor.Kernel)
+	assert.Equal(filepath.Join(testDir, testImage), plan.Hypervisor.Image)
+	assert.Equal(uint32(512), plan.GuestSwapMB)
+	assert.Len(plan.Containers, 1)
+	assert.Equal(containerID, plan.Containers[0].ID)
+
+	// nothing is stored
+	s := p.(*Sandbox)
+	_, err = os.Stat(filepath.Join(s.store.RunStoragePath(), p.ID()))
+	assert.True(os.IsNotExist(err))
+
+	// not for an existing sandbox
+	config.DryRun = false
+	config.GuestSwapConfig = GuestSwapConfig{}
+	_, err = CreateSandbox(ctx, config, nil)
+	assert.NoError(err)
+
+	config.DryRun = true
+	_, err = CreateSandbox(ctx, config, nil)
+	assert.Error(err)
+}
+
+type cleanedVMHypervisor struct {
+	mockHypervisor
+	cleaned bool
+}
+
+func (h *cleanedVMHypervisor) cleanupVM() error {
+	h.cleaned = true
+	return nil
+}
+
+func TestDryRunCleanup(t *testing.T) {
+	defer cleanUp()
+	assert := assert.New(t)
+
+	config := newTestSandboxConfigNoop()
+	config.DryRun = true
+
+	ctx := WithNewAgentFunc(context.Background(), newMockAgent)
+	p, err := CreateSandbox(ctx, config, nil)
+	assert.NoError(err)
+
+	// the files created for the VM configuration are removed
+	s := p.(*Sandbox)
+	h := &cleanedVMHypervisor{}
+	s.hypervisor = h
+	s.dryRunCleanup(ctx)
+	assert.True(h.cleaned)
+}
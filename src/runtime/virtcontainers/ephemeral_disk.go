@@ -0,0 +1,305 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	pkgUtils "github.com/kata-containers/kata-containers/src/runtime/pkg/utils"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/device/config"
+	"golang.org/x/sys/unix"
+)
+
+// backends of the sandbox ephemeral disk
+const (
+	// EphemeralDiskFile backs the disk with a sparse file
+	// of the ephemeral disk directory, through a loop device.
+	EphemeralDiskFile = "file"
+
+	// EphemeralDiskLVM backs the disk with a logical volume.
+	EphemeralDiskLVM = "lvm"
+)
+
+const (
+	ephemeralDiskFsType   = "ext4"
+	ephemeralDiskImage    = "ephemeral-disk.img"
+	ephemeralDiskLVPrefix = "kata-ephemeral-"
+	ephemeralDiskDir      = "ephemeral-disk"
+
+	// driver option of the storages the agent encrypts
+	// with a key generated in the guest
+	ephemeralDiskEncryptionOption = "encryption=ephemeral"
+)
+
+// EphemeralDiskConfig is the configuration of the disk backing the local
+// (e.g. Kubernetes emptyDir) volumes of a sandbox. The volumes share the
+// host root filesystem when it is not enabled.
+type EphemeralDiskConfig struct {
+	// Backend is the kind of host disk created for the sandbox,
+	// the disk is not used when empty.
+	Backend string
+
+	// VolumeGroup is the LVM volume group the
+	// logical volumes of the lvm backend are created in.
+	VolumeGroup string
+
+	// Dir is the host directory the sparse files of the file backend are
+	// created in. It must be on a disk, not in the tmpfs of the run
+	// storage, the files taking the memory of the host otherwise.
+	Dir string
+
+	// SizeMB is the size of the disk, usually the
+	// ephemeral storage limit of the pod.
+	SizeMB uint32
+
+	// MaxSizeMB is the maximum size of the disk the pods can ask for,
+	// the default size when zero.
+	MaxSizeMB uint32
+
+	// Encrypt has the agent encrypt the disk with a key generated in
+	// the guest, the host only sees the encrypted data.
+	Encrypt bool
+}
+
+// EphemeralDiskStatus is the status of the ephemeral disk of a sandbox.
+type EphemeralDiskStatus struct {
+	Backend   string `json:"backend"`
+	SizeMB    uint32 `json:"size_mb"`
+	Encrypted bool   `json:"encrypted"`
+	Attached  bool   `json:"attached"`
+	Device    string `json:"device,omitempty"`
+}
+
+func (c EphemeralDiskConfig) enabled() bool {
+	return c.Backend != ""
+}
+
+// Validate checks the ephemeral disk configuration.
+func (c EphemeralDiskConfig) Validate() error {
+	switch c.Backend {
+	case "":
+		return nil
+	case EphemeralDiskFile:
+		if c.Dir == "" {
+			return fmt.Errorf("ephemeral disk backend %q requires a directory", c.Backend)
+		}
+	case EphemeralDiskLVM:
+		if c.VolumeGroup == "" {
+			return fmt.Errorf("ephemeral disk backend %q requires a volume group", c.Backend)
+		}
+	default:
+		return fmt.Errorf("unknown ephemeral disk backend %q", c.Backend)
+	}
+
+	if c.SizeMB == 0 {
+		return fmt.Errorf("ephemeral disk size must be set")
+	}
+
+	if c.SizeMB > c.maxSizeMB() {
+		return fmt.Errorf("ephemeral disk size %d MiB exceeds the maximum size %d MiB", c.SizeMB, c.MaxSizeMB)
+	}
+
+	return nil
+}
+
+// maxSizeMB returns the maximum size of the disk the pods can ask for.
+func (c EphemeralDiskConfig) maxSizeMB() uint32 {
+	if c.MaxSizeMB == 0 {
+		return c.SizeMB
+	}
+	return c.MaxSizeMB
+}
+
+// CheckSize checks the size of the disk a pod asks for, if the disk is enabled.
+func (c EphemeralDiskConfig) CheckSize(sizeMB uint64) error {
+	if !c.enabled() {
+		return nil
+	}
+	if sizeMB == 0 || sizeMB > uint64(c.maxSizeMB()) {
+		return fmt.Errorf("ephemeral disk size %d MiB is not between 1 and %d MiB", sizeMB, c.maxSizeMB())
+	}
+	return nil
+}
+
+// ephemeralDiskGuestPath is the mount point of the ephemeral disk in the VM.
+func ephemeralDiskGuestPath() string {
+	return filepath.Join(kataGuestSandboxDir(), ephemeralDiskDir)
+}
+
+func ephemeralDiskImagePath(dir, sandboxID string) string {
+	return filepath.Join(dir, sandboxID, ephemeralDiskImage)
+}
+
+func ephemeralDiskLVName(sandboxID string) string {
+	return ephemeralDiskLVPrefix + sandboxID
+}
+
+// createEphemeralDisk creates and formats the ephemeral disk of a sandbox,
+// and returns the path of its host block device.
+func createEphemeralDisk(c EphemeralDiskConfig, sandboxID string) (path string, err error) {
+	switch c.Backend {
+	case EphemeralDiskFile:
+		image := ephemeralDiskImagePath(c.Dir, sandboxID)
+		if err = os.MkdirAll(filepath.Dir(image), DirMode); err != nil {
+			return "", err
+		}
+
+		f, err := os.OpenFile(image, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0600)
+		if err != nil {
+			return "", err
+		}
+		// a sparse file, the host disk space is used on demand
+		err = f.Truncate(int64(c.SizeMB) << 20)
+		f.Close()
+		if err != nil {
+			os.Remove(image)
+			return "", err
+		}
+
+		if path, err = pkgUtils.RunHostCommand("losetup", "--find", "--show", image); err != nil {
+			os.Remove(image)
+			return "", err
+		}
+	case EphemeralDiskLVM:
+		lv := ephemeralDiskLVName(sandboxID)
+		if _, err = pkgUtils.RunHostCommand("lvcreate", "--yes", "--name", lv, "--size", fmt.Sprintf("%dm", c.SizeMB), c.VolumeGroup); err != nil {
+			return "", err
+		}
+		path = filepath.Join("/dev", c.VolumeGroup, lv)
+	default:
+		return "", fmt.Errorf("unknown ephemeral disk backend %q", c.Backend)
+	}
+
+	// the disk is formatted by the agent once encrypted
+	if c.Encrypt {
+		return path, nil
+	}
+
+	if _, err = pkgUtils.RunHostCommand("mkfs."+ephemeralDiskFsType, "-q", "-F", path); err != nil {
+		removeEphemeralDisk(c, sandboxID)
+		return "", err
+	}
+
+	return path, nil
+}
+
+// removeEphemeralDisk removes the ephemeral disk of a sandbox, if any.
+func removeEphemeralDisk(c EphemeralDiskConfig, sandboxID string) error {
+	switch c.Backend {
+	case EphemeralDiskFile:
+		image := ephemeralDiskImagePath(c.Dir, sandboxID)
+		if _, err := os.Stat(image); os.IsNotExist(err) {
+			return nil
+		}
+
+		out, err := pkgUtils.RunHostCommand("losetup", "--noheadings", "--output", "NAME", "--associated", image)
+		if err != nil {
+			return err
+		}
+		for _, loop := range strings.Fields(out) {
+			if _, err := pkgUtils.RunHostCommand("losetup", "--detach", loop); err != nil {
+				return err
+			}
+		}
+
+		if err := os.Remove(image); err != nil {
+			return err
+		}
+		return os.Remove(filepath.Dir(image))
+	case EphemeralDiskLVM:
+		lv := filepath.Join(c.VolumeGroup, ephemeralDiskLVName(sandboxID))
+		if _, err := pkgUtils.RunHostCommand("lvs", lv); err != nil {
+			// no such logical volume
+			return nil
+		}
+
+		_, err := pkgUtils.RunHostCommand("lvremove", "--yes", lv)
+		return err
+	}
+
+	return nil
+}
+
+// setupEphemeralDisk creates the ephemeral disk of the sandbox and attaches
+// it to the VM, the agent mounts it when the sandbox is started.
+func (s *Sandbox) setupEphemeralDisk(ctx context.Context) error {
+	c := s.config.EphemeralDiskConfig
+	if !c.enabled() {
+		return nil
+	}
+
+	if s.config.HypervisorConfig.DisableBlockDeviceUse {
+		return fmt.Errorf("ephemeral disk requires block device support")
+	}
+
+	path, err := createEphemeralDisk(c, s.id)
+	if err != nil {
+		return err
+	}
+
+	var stat unix.Stat_t
+	if err := unix.Stat(path, &stat); err != nil {
+		return fmt.Errorf("stat %q failed: %v", path, err)
+	}
+
+	b, err := s.devManager.NewDevice(config.DeviceInfo{
+		HostPath:      path,
+		ContainerPath: ephemeralDiskGuestPath(),
+		DevType:       "b",
+		Major:         int64(unix.Major(stat.Rdev)),
+		Minor:         int64(unix.Minor(stat.Rdev)),
+	})
+	if err != nil {
+		return fmt.Errorf("device manager failed to create ephemeral disk device for %q: %v", path, err)
+	}
+
+	if err := s.devManager.AttachDevice(ctx, b.DeviceID(), s); err != nil {
+		return err
+	}
+	s.state.SandboxDevices = append(s.state.SandboxDevices, b.DeviceID())
+
+	drive, ok := b.GetDeviceInfo().(*config.BlockDrive)
+	if !ok || drive == nil {
+		return fmt.Errorf("ephemeral disk device %q is not a block drive", b.DeviceID())
+	}
+
+	storage, err := newBlockDriveStorage(s.config.HypervisorConfig.BlockDeviceDriver, drive)
+	if err != nil {
+		return err
+	}
+	storage.Fstype = ephemeralDiskFsType
+	storage.MountPoint = ephemeralDiskGuestPath()
+	if c.Encrypt {
+		storage.DriverOptions = []string{ephemeralDiskEncryptionOption}
+	}
+
+	s.ephemeralDisk = storage
+	s.ephemeralDiskPath = path
+
+	s.Logger().WithField("device", path).Info("ephemeral disk attached")
+
+	return nil
+}
+
+// GetEphemeralDiskStatus returns the status of the sandbox ephemeral disk.
+func (s *Sandbox) GetEphemeralDiskStatus() (EphemeralDiskStatus, error) {
+	c := s.config.EphemeralDiskConfig
+	if !c.enabled() {
+		return EphemeralDiskStatus{}, fmt.Errorf("ephemeral disk is not enabled")
+	}
+
+	return EphemeralDiskStatus{
+		Backend:   c.Backend,
+		SizeMB:    c.SizeMB,
+		Encrypted: c.Encrypt,
+		Attached:  s.ephemeralDisk != nil,
+		Device:    s.ephemeralDiskPath,
+	}, nil
+}
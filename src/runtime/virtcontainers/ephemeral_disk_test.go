@@ -0,0 +1,180 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	pkgUtils "github.com/kata-containers/kata-containers/src/runtime/pkg/utils"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/agent/protocols/grpc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEphemeralDiskConfigValidate(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.NoError(EphemeralDiskConfig{}.Validate())
+	assert.NoError(EphemeralDiskConfig{Backend: EphemeralDiskFile, Dir: "/var/lib/disks", SizeMB: 64}.Validate())
+	assert.NoError(EphemeralDiskConfig{Backend: EphemeralDiskLVM, VolumeGroup: "vg", SizeMB: 64, MaxSizeMB: 128}.Validate())
+
+	assert.Error(EphemeralDiskConfig{Backend: "tmpfs", SizeMB: 64}.Validate())
+	assert.Error(EphemeralDiskConfig{Backend: EphemeralDiskFile, Dir: "/var/lib/disks"}.Validate())
+	assert.Error(EphemeralDiskConfig{Backend: EphemeralDiskFile, SizeMB: 64}.Validate())
+	assert.Error(EphemeralDiskConfig{Backend: EphemeralDiskLVM, SizeMB: 64}.Validate())
+	assert.Error(EphemeralDiskConfig{Backend: EphemeralDiskLVM, VolumeGroup: "vg", SizeMB: 64, MaxSizeMB: 32}.Validate())
+}
+
+func TestEphemeralDiskConfigCheckSize(t *testing.T) {
+	assert := assert.New(t)
+
+	// not used
+	assert.NoError(EphemeralDiskConfig{}.CheckSize(1 << 40))
+
+	// up to the default size
+	c := EphemeralDiskConfig{Backend: EphemeralDiskLVM, VolumeGroup: "vg", SizeMB: 64}
+	assert.NoError(c.CheckSize(64))
+	assert.Error(c.CheckSize(65))
+	assert.Error(c.CheckSize(0))
+
+	// up to the maximum size, not truncated
+	c.MaxSizeMB = 1024
+	assert.NoError(c.CheckSize(1024))
+	assert.Error(c.CheckSize(1<<32 + 64))
+}
+
+// mockHostCommands records the host commands run by the runtime and runs
+// the run function instead, when set.
+func mockHostCommands(run func(name string, args ...string) (string, error)) (*[]string, func()) {
+	var commands []string
+
+	saved := pkgUtils.RunHostCommand
+	pkgUtils.RunHostCommand = func(name string, args ...string) (string, error) {
+		commands = append(commands, strings.Join(append([]string{name}, args...), " "))
+		if run == nil {
+			return "", nil
+		}
+		return run(name, args...)
+	}
+
+	return &commands, func() {
+		pkgUtils.RunHostCommand = saved
+	}
+}
+
+// hostCommandOutputs returns the outputs of the host commands by name.
+func hostCommandOutputs(outputs map[string]string) func(name string, args ...string) (string, error) {
+	return func(name string, args ...string) (string, error) {
+		return outputs[name], nil
+	}
+}
+
+func TestEphemeralDiskFile(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "ephemeral-disk")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	commands, restore := mockHostCommands(hostCommandOutputs(map[string]string{"losetup": "/dev/loop7"}))
+	defer restore()
+
+	c := EphemeralDiskConfig{Backend: EphemeralDiskFile, Dir: dir, SizeMB: 64}
+	image := filepath.Join(dir, "sid", ephemeralDiskImage)
+
+	path, err := createEphemeralDisk(c, "sid")
+	assert.NoError(err)
+	assert.Equal("/dev/loop7", path)
+	assert.Equal([]string{
+		"losetup --find --show " + image,
+		"mkfs.ext4 -q -F /dev/loop7",
+	}, *commands)
+
+	info, err := os.Stat(image)
+	assert.NoError(err)
+	assert.Equal(int64(64<<20), info.Size())
+
+	// the disk exists already
+	_, err = createEphemeralDisk(c, "sid")
+	assert.Error(err)
+
+	*commands = nil
+	assert.NoError(removeEphemeralDisk(c, "sid"))
+	assert.Equal([]string{
+		"losetup --noheadings --output NAME --associated " + image,
+		"losetup --detach /dev/loop7",
+	}, *commands)
+	_, err = os.Stat(filepath.Dir(image))
+	assert.True(os.IsNotExist(err))
+
+	// nothing to remove
+	*commands = nil
+	assert.NoError(removeEphemeralDisk(c, "sid"))
+	assert.Empty(*commands)
+}
+
+func TestEphemeralDiskLVM(t *testing.T) {
+	assert := assert.New(t)
+
+	commands, restore := mockHostCommands(nil)
+	defer restore()
+
+	c := EphemeralDiskConfig{Backend: EphemeralDiskLVM, VolumeGroup: "vg", SizeMB: 64}
+
+	path, err := createEphemeralDisk(c, "sid")
+	assert.NoError(err)
+	assert.Equal("/dev/vg/kata-ephemeral-sid", path)
+	assert.Equal([]string{
+		"lvcreate --yes --name kata-ephemeral-sid --size 64m vg",
+		"mkfs.ext4 -q -F /dev/vg/kata-ephemeral-sid",
+	}, *commands)
+
+	*commands = nil
+	assert.NoError(removeEphemeralDisk(c, "sid"))
+	assert.Equal([]string{
+		"lvs vg/kata-ephemeral-sid",
+		"lvremove --yes vg/kata-ephemeral-sid",
+	}, *commands)
+}
+
+func TestEphemeralDiskEncrypted(t *testing.T) {
+	assert := assert.New(t)
+
+	commands, restore := mockHostCommands(nil)
+	defer restore()
+
+	c := EphemeralDiskConfig{Backend: EphemeralDiskLVM, VolumeGroup: "vg", SizeMB: 64, Encrypt: true}
+
+	// the agent formats the disk
+	_, err := createEphemeralDisk(c, "sid")
+	assert.NoError(err)
+	assert.Equal([]string{
+		"lvcreate --yes --name kata-ephemeral-sid --size 64m vg",
+	}, *commands)
+}
+
+func TestGetEphemeralDiskStatus(t *testing.T) {
+	assert := assert.New(t)
+
+	s := &Sandbox{config: &SandboxConfig{}}
+	_, err := s.GetEphemeralDiskStatus()
+	assert.Error(err)
+
+	s.config.EphemeralDiskConfig = EphemeralDiskConfig{Backend: EphemeralDiskFile, SizeMB: 64, Encrypt: true}
+	status, err := s.GetEphemeralDiskStatus()
+	assert.NoError(err)
+	assert.Equal(EphemeralDiskStatus{Backend: EphemeralDiskFile, SizeMB: 64, Encrypted: true}, status)
+
+	s.ephemeralDisk = &grpc.Storage{}
+	s.ephemeralDiskPath = "/dev/loop7"
+	status, err = s.GetEphemeralDiskStatus()
+	assert.NoError(err)
+	assert.True(status.Attached)
+	assert.Equal("/dev/loop7", status.Device)
+}
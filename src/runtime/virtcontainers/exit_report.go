@@ -0,0 +1,261 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/containerd/cgroups"
+	"github.com/prometheus/procfs"
+)
+
+// exitReportFile is the file of the exit report in the persist directory of
+// the sandbox.
+const exitReportFile = "exit-report.json"
+
+// maxSandboxEvents is the number of abnormal events kept for the exit
+// report, the oldest ones being dropped.
+const maxSandboxEvents = 32
+
+// types of the abnormal events of a sandbox
+const (
+	// the agent or the hypervisor failed to answer the monitor
+	sandboxEventFailure = "failure"
+	// a process of the sandbox, e.g. virtiofsd, was restarted
+	sandboxEventComponentRestart = "component_restart"
+)
+
+// SandboxEvent is an abnormal event of the life of a sandbox.
+type SandboxEvent struct {
+	Time    time.Time `json:"time"`
+	Type    string    `json:"type"`
+	Message string    `json:"message"`
+}
+
+// ExitReport is the final report of the resources used by a sandbox,
+// written when it stops, for the billing and efficiency systems which need
+// the whole lifecycle rather than the scraped metrics.
+type ExitReport struct {
+	SandboxID string `json:"sandbox_id"`
+	// StartTime is the start time of the hypervisor process
+	StartTime time.Time `json:"start_time,omitempty"`
+	StopTime  time.Time `json:"stop_time"`
+
+	// PeakMemoryBytes is the peak memory usage of the sandbox cgroup, or
+	// the sum of the peak RSS of the sandbox processes without cgroup.
+	PeakMemoryBytes uint64 `json:"peak_memory_bytes"`
+	// MemoryLimitHits is the number of times the sandbox cgroup memory
+	// usage hit its limit.
+	MemoryLimitHits uint64 `json:"memory_limit_hits,omitempty"`
+	// CPUSeconds is the CPU time of the sandbox cgroup, or of the sandbox
+	// processes without cgroup.
+	CPUSeconds          float64 `json:"cpu_seconds"`
+	CPUThrottledSeconds float64 `json:"cpu_throttled_seconds,omitempty"`
+
+	// the storage I/O of the sandbox processes, e.g. of the block devices
+	// of the VM
+	DiskReadBytes  uint64 `json:"disk_read_bytes"`
+	DiskWriteBytes uint64 `json:"disk_write_bytes"`
+	// the traffic of the sandbox network interfaces
+	NetworkRxBytes uint64 `json:"network_rx_bytes"`
+	NetworkTxBytes uint64 `json:"network_tx_bytes"`
+
+	// BootPhases are the durations of the boot phases, in seconds, when
+	// the VM was started by this shim.
+	BootPhases map[string]float64 `json:"boot_phases_seconds,omitempty"`
+
+	// Events are the last abnormal events of the sandbox.
+	Events []SandboxEvent `json:"abnormal_events,omitempty"`
+
+	// Errors are the resources which could not be read.
+	Errors []string `json:"errors,omitempty"`
+}
+
+// recordEvent records an abnormal event of the sandbox for its exit report.
+func (s *Sandbox) recordEvent(eventType, message string) {
+	s.reportLock.Lock()
+	defer s.reportLock.Unlock()
+
+	s.events = append(s.events, SandboxEvent{
+		Time:    time.Now().UTC(),
+		Type:    eventType,
+		Message: message,
+	})
+	if len(s.events) > maxSandboxEvents {
+		s.events = s.events[len(s.events)-maxSandboxEvents:]
+	}
+}
+
+// recordBootPhase records the duration of a boot phase started at start.
+func (s *Sandbox) recordBootPhase(phase string, start time.Time) {
+	d := time.Since(start)
+	s.metrics().bootPhaseDuration.WithLabelValues(phase).Set(d.Seconds())
+
+	s.reportLock.Lock()
+	defer s.reportLock.Unlock()
+
+	if s.bootPhases == nil {
+		s.bootPhases = make(map[string]float64)
+	}
+	s.bootPhases[phase] = d.Seconds()
+}
+
+// newExitReport reads the resources used by the sandbox. It is called before
+// the VM is stopped, while its processes and cgroups are still there.
+func (s *Sandbox) newExitReport() *ExitReport {
+	report := &ExitReport{
+		SandboxID: s.id,
+		StopTime:  time.Now().UTC(),
+	}
+
+	s.reportLock.Lock()
+	if len(s.bootPhases) > 0 {
+		report.BootPhases = make(map[string]float64, len(s.bootPhases))
+		for phase, d := range s.bootPhases {
+			report.BootPhases[phase] = d
+		}
+	}
+	report.Events = append([]SandboxEvent(nil), s.events...)
+	s.reportLock.Unlock()
+
+	addError := func(err error) {
+		report.Errors = append(report.Errors, err.Error())
+	}
+
+	var peakRSS uint64
+	var cpuSeconds float64
+	for _, c := range s.components() {
+		if c.pid <= 0 {
+			continue
+		}
+
+		proc, err := procfs.NewProc(c.pid)
+		if err != nil {
+			addError(fmt.Errorf("%s: %v", c.name, err))
+			continue
+		}
+
+		if stat, err := proc.Stat(); err == nil {
+			cpuSeconds += stat.CPUTime()
+			if c.component == componentHypervisor {
+				if start, err := stat.StartTime(); err == nil {
+					report.StartTime = time.Unix(0, int64(start*float64(time.Second))).UTC()
+				}
+			}
+		} else {
+			addError(fmt.Errorf("%s: %v", c.name, err))
+		}
+
+		if status, err := proc.NewStatus(); err == nil {
+			peakRSS += status.VmHWM
+		}
+
+		if io, err := proc.IO(); err == nil {
+			report.DiskReadBytes += io.ReadBytes
+			report.DiskWriteBytes += io.WriteBytes
+		} else {
+			addError(fmt.Errorf("%s: %v", c.name, err))
+		}
+	}
+
+	// the sandbox cgroups, when available, also account the kernel
+	// threads of the VM, e.g. vhost
+	report.PeakMemoryBytes = peakRSS
+	report.CPUSeconds = cpuSeconds
+	if err := s.cgroupUsage(report); err != nil {
+		addError(err)
+	}
+
+	if stats, err := s.networkStats(); err == nil {
+		for _, st := range stats {
+			report.NetworkRxBytes += st.RxBytes
+			report.NetworkTxBytes += st.TxBytes
+		}
+	} else {
+		addError(err)
+	}
+
+	return report
+}
+
+// cgroupUsage sets the memory and CPU usage of the report from the sandbox
+// cgroups.
+func (s *Sandbox) cgroupUsage(report *ExitReport) error {
+	if s.state.CgroupPath == "" {
+		return nil
+	}
+
+	// the memory of the hypervisor is accounted in the unconstrained
+	// cgroup, its vCPU threads are in the constrained one
+	memoryHierarchy, memoryPath := V1NoConstraints, cgroupNoConstraintsPath(s.state.CgroupPath)
+	cpuHierarchy := V1Constraints
+	if s.config.SandboxCgroupOnly {
+		memoryHierarchy, memoryPath = cgroups.V1, s.state.CgroupPath
+		cpuHierarchy = cgroups.V1
+	}
+
+	cgroup, err := cgroupsLoadFunc(memoryHierarchy, staticCgroupPath(memoryPath))
+	if err != nil {
+		return fmt.Errorf("Could not load sandbox cgroup in %v: %v", memoryPath, err)
+	}
+	metrics, err := cgroup.Stat(cgroups.ErrorHandler(cgroups.IgnoreNotExist))
+	if err != nil {
+		return err
+	}
+	if metrics.Memory != nil && metrics.Memory.Usage != nil && metrics.Memory.Usage.Max != 0 {
+		report.PeakMemoryBytes = metrics.Memory.Usage.Max
+		report.MemoryLimitHits = metrics.Memory.Usage.Failcnt
+	}
+
+	cgroup, err = cgroupsLoadFunc(cpuHierarchy, staticCgroupPath(s.state.CgroupPath))
+	if err != nil {
+		return fmt.Errorf("Could not load sandbox cgroup in %v: %v", s.state.CgroupPath, err)
+	}
+	metrics, err = cgroup.Stat(cgroups.ErrorHandler(cgroups.IgnoreNotExist))
+	if err != nil {
+		return err
+	}
+	if metrics.CPU != nil {
+		if metrics.CPU.Usage != nil && metrics.CPU.Usage.Total != 0 {
+			report.CPUSeconds = time.Duration(metrics.CPU.Usage.Total).Seconds()
+		}
+		if metrics.CPU.Throttling != nil {
+			report.CPUThrottledSeconds = time.Duration(metrics.CPU.Throttling.ThrottledTime).Seconds()
+		}
+	}
+
+	return nil
+}
+
+// writeExitReport writes the exit report in the persist directory of the
+// sandbox, where it is kept until the sandbox is deleted.
+func (s *Sandbox) writeExitReport(report *ExitReport) error {
+	data, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Join(s.store.RunStoragePath(), s.id)
+	if err := os.MkdirAll(dir, DirMode); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(filepath.Join(dir, exitReportFile), data, 0640)
+}
+
+// GetExitReport returns the exit report of the sandbox, nil until it is
+// stopped.
+func (s *Sandbox) GetExitReport() *ExitReport {
+	s.reportLock.Lock()
+	defer s.reportLock.Unlock()
+
+	return s.exitReport
+}
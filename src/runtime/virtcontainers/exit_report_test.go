@@ -0,0 +1,73 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/persist"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSandboxRecordEvent(t *testing.T) {
+	assert := assert.New(t)
+
+	s := &Sandbox{}
+	for i := 0; i < maxSandboxEvents+2; i++ {
+		s.recordEvent(sandboxEventFailure, fmt.Sprintf("failure %d", i))
+	}
+
+	assert.Len(s.events, maxSandboxEvents)
+	assert.Equal("failure 2", s.events[0].Message)
+	assert.Equal(fmt.Sprintf("failure %d", maxSandboxEvents+1), s.events[maxSandboxEvents-1].Message)
+}
+
+func TestSandboxExitReport(t *testing.T) {
+	assert := assert.New(t)
+
+	store, err := persist.GetDriver()
+	assert.NoError(err)
+
+	s := &Sandbox{
+		id:         testSandboxID,
+		hypervisor: &mockHypervisor{mockPid: os.Getpid()},
+		config:     &SandboxConfig{HypervisorType: MockHypervisor},
+		store:      store,
+	}
+	assert.Nil(s.GetExitReport())
+
+	s.recordBootPhase(bootPhaseVMStart, time.Now().Add(-time.Second))
+	s.recordEvent(sandboxEventComponentRestart, "virtiofsd restarted")
+
+	report := s.newExitReport()
+	assert.Equal(testSandboxID, report.SandboxID)
+	assert.False(report.StartTime.IsZero())
+	assert.True(report.StartTime.Before(report.StopTime))
+	assert.NotZero(report.PeakMemoryBytes)
+	assert.True(report.BootPhases[bootPhaseVMStart] >= 1)
+	assert.Len(report.Events, 1)
+	assert.Equal(sandboxEventComponentRestart, report.Events[0].Type)
+
+	// the report doesn't change with the later events
+	s.recordEvent(sandboxEventFailure, "agent is not alive")
+	assert.Len(report.Events, 1)
+
+	assert.NoError(s.writeExitReport(report))
+	defer os.RemoveAll(filepath.Join(store.RunStoragePath(), testSandboxID))
+
+	data, err := ioutil.ReadFile(filepath.Join(store.RunStoragePath(), testSandboxID, exitReportFile))
+	assert.NoError(err)
+	var written ExitReport
+	assert.NoError(json.Unmarshal(data, &written))
+	assert.Equal(report.PeakMemoryBytes, written.PeakMemoryBytes)
+	assert.Equal(report.BootPhases, written.BootPhases)
+}
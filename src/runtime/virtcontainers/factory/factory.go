@@ -8,6 +8,7 @@ package factory
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/kata-containers/kata-containers/src/runtime/pkg/katautils/katatrace"
 	pb "github.com/kata-containers/kata-containers/src/runtime/protocols/cache"
@@ -150,6 +151,7 @@ func (f *factory) GetVM(ctx context.Context, config vc.VMConfig) (*vc.VM, error)
 	err := f.checkConfig(config)
 	if err != nil {
 		f.log().WithError(err).Info("fallback to direct factory vm")
+		factoryGetVM.WithLabelValues(getVMMiss).Inc()
 		return direct.New(ctx, config).GetBaseVM(ctx, config)
 	}
 
@@ -157,8 +159,10 @@ func (f *factory) GetVM(ctx context.Context, config vc.VMConfig) (*vc.VM, error)
 	vm, err := f.base.GetBaseVM(ctx, config)
 	if err != nil {
 		f.log().WithError(err).Error("failed to get base VM")
+		factoryGetVM.WithLabelValues(getVMError).Inc()
 		return nil, err
 	}
+	factoryGetVM.WithLabelValues(getVMHit).Inc()
 
 	// cleanup upon error
 	defer func() {
@@ -213,6 +217,67 @@ func (f *factory) GetVM(ctx context.Context, config vc.VMConfig) (*vc.VM, error)
 	return vm, nil
 }
 
+// Status describes the template and the VMCache server of a factory config.
+type Status struct {
+	Template    bool
+	TemplateAge time.Duration
+	VMCache     bool
+	CachedVMs   int
+}
+
+// GetStatus returns the status of the template and
+// the VMCache server of the factory config.
+func GetStatus(ctx context.Context, config Config) (Status, error) {
+	status := Status{
+		Template: config.Template,
+		VMCache:  config.VMCache,
+	}
+
+	if config.Template {
+		age, err := template.Age(config.TemplatePath)
+		if err != nil {
+			return Status{}, err
+		}
+		status.TemplateAge = age
+	}
+
+	if config.VMCache {
+		s, err := grpccache.Status(ctx, config.VMCacheEndpoint)
+		if err != nil {
+			return Status{}, err
+		}
+		status.CachedVMs = len(s.Vmstatus)
+	}
+
+	return status, nil
+}
+
+// FlushTemplate destroys the VM template of the factory config.
+func FlushTemplate(ctx context.Context, config Config) error {
+	if !config.Template {
+		return fmt.Errorf("vm template is not enabled")
+	}
+
+	f, err := NewFactory(ctx, config, true)
+	if err != nil {
+		return err
+	}
+	f.CloseFactory(ctx)
+
+	return nil
+}
+
+// RebuildTemplate destroys the VM template of the factory config,
+// if any, and creates a new one.
+func RebuildTemplate(ctx context.Context, config Config) error {
+	if err := FlushTemplate(ctx, config); err != nil {
+		factoryLogger.WithError(err).Warn("failed to flush vm template")
+	}
+
+	_, err := NewFactory(ctx, config, false)
+	return err
+}
+
 // Config returns base factory config.
 func (f *factory) Config() vc.VMConfig {
 	return f.base.Config()
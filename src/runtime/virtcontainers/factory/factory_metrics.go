@@ -0,0 +1,69 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package factory
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const namespaceFactory = "kata_factory"
+
+// results of getting a VM from the factory
+const (
+	getVMHit   = "hit"
+	getVMMiss  = "miss"
+	getVMError = "error"
+)
+
+var (
+	factoryGetVM = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespaceFactory,
+		Name:      "get_vm_total",
+		Help:      "VMs requested to the factory, by result(hit, miss or error).",
+	},
+		[]string{"result"},
+	)
+
+	factoryCachedVMs = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespaceFactory,
+		Name:      "cached_vms",
+		Help:      "VMs ready in the VMCache server.",
+	})
+
+	factoryTemplateAge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespaceFactory,
+		Name:      "template_age_seconds",
+		Help:      "Age of the VM template(seconds).",
+	})
+)
+
+// RegisterMetrics registers the VM factory metrics.
+func RegisterMetrics(r prometheus.Registerer) {
+	r.MustRegister(factoryGetVM)
+	r.MustRegister(factoryCachedVMs)
+	r.MustRegister(factoryTemplateAge)
+}
+
+// UpdateMetrics updates the metrics about the template and
+// the VMCache server of the factory config.
+func UpdateMetrics(ctx context.Context, config Config) error {
+	status, err := GetStatus(ctx, config)
+	if err != nil {
+		return err
+	}
+
+	if status.Template {
+		factoryTemplateAge.Set(status.TemplateAge.Seconds())
+	}
+
+	if status.VMCache {
+		factoryCachedVMs.Set(float64(status.CachedVMs))
+	}
+
+	return nil
+}
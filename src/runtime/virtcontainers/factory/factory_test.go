@@ -335,3 +335,33 @@ func TestDeepCompare(t *testing.T) {
 	assert.Nil(err)
 	assert.False(utils.DeepCompare(f1, f2))
 }
+
+func TestGetStatus(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx := context.Background()
+
+	status, err := GetStatus(ctx, Config{})
+	assert.NoError(err)
+	assert.False(status.Template)
+	assert.False(status.VMCache)
+
+	// no template in the template path
+	templatePath := fs.MockStorageRootPath()
+	defer fs.MockStorageDestroy()
+
+	_, err = GetStatus(ctx, Config{Template: true, TemplatePath: templatePath})
+	assert.Error(err)
+}
+
+func TestFlushTemplate(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx := context.Background()
+
+	err := FlushTemplate(ctx, Config{})
+	assert.Error(err)
+
+	err = RebuildTemplate(ctx, Config{})
+	assert.Error(err)
+}
@@ -43,6 +43,22 @@ func New(ctx context.Context, endpoint string) (base.FactoryBase, error) {
 	return &grpccache{conn: conn, config: config}, nil
 }
 
+// Status returns the status of the VMCache server listening on endpoint.
+func Status(ctx context.Context, endpoint string) (*pb.GrpcStatus, error) {
+	conn, err := grpc.Dial(fmt.Sprintf("unix://%s", endpoint), grpc.WithInsecure())
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to connect %q", endpoint)
+	}
+	defer conn.Close()
+
+	status, err := pb.NewCacheServiceClient(conn).Status(ctx, &types.Empty{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to call gRPC Status")
+	}
+
+	return status, nil
+}
+
 // Config returns the direct factory's configuration.
 func (g *grpccache) Config() vc.VMConfig {
 	return *g.config
@@ -166,6 +166,16 @@ func (t *template) createFromTemplateVM(ctx context.Context, c vc.VMConfig) (*vc
 	return vc.NewVM(ctx, config)
 }
 
+// Age returns how long ago the VM template in templatePath was created.
+func Age(templatePath string) (time.Duration, error) {
+	fi, err := os.Stat(templatePath + "/state")
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Since(fi.ModTime()), nil
+}
+
 func (t *template) checkTemplateVM() error {
 	_, err := os.Stat(t.statePath + "/memory")
 	if err != nil {
@@ -8,6 +8,7 @@ package template
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"testing"
 	"time"
@@ -132,3 +133,25 @@ func TestTemplateFactory(t *testing.T) {
 	assert.Error(err)
 	assert.True(os.IsNotExist(err))
 }
+
+func TestTemplateAge(t *testing.T) {
+	assert := assert.New(t)
+
+	templatePath, err := ioutil.TempDir("", "template")
+	assert.NoError(err)
+	defer os.RemoveAll(templatePath)
+
+	_, err = Age(templatePath)
+	assert.Error(err)
+
+	f, err := os.Create(templatePath + "/state")
+	assert.NoError(err)
+	f.Close()
+
+	created := time.Now().Add(-time.Hour)
+	assert.NoError(os.Chtimes(templatePath+"/state", created, created))
+
+	age, err := Age(templatePath)
+	assert.NoError(err)
+	assert.True(age >= time.Hour)
+}
@@ -38,6 +38,7 @@ import (
 	"github.com/go-openapi/strfmt"
 	"github.com/opencontainers/selinux/go-selinux/label"
 	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sirupsen/logrus"
 )
 
@@ -165,6 +166,9 @@ type firecracker struct {
 	fcConfig     *types.FcConfig // Parameters configured before VM starts
 
 	console console.Console
+
+	// metrics are the metrics read from the firecracker metrics fifo
+	metrics *firecrackerCollectors
 }
 
 type firecrackerDevice struct {
@@ -203,6 +207,7 @@ func (fc *firecracker) createSandbox(ctx context.Context, id string, networkNS N
 	fc.id = fc.truncateID(id)
 	fc.state.set(notReady)
 	fc.config = *hypervisorConfig
+	fc.metrics = newFirecrackerCollectors()
 
 	// When running with jailer all resources need to be under
 	// a specific location and that location needs to have
@@ -608,7 +613,14 @@ func (fc *firecracker) updateMetrics(line string) {
 		fc.Logger().WithError(err).WithField("data", line).Error("failed to unmarshal fc metrics")
 		return
 	}
-	updateFirecrackerMetrics(&fm)
+	fc.metrics.update(&fm)
+}
+
+// registerMetrics registers the metrics of the VM read from the metrics fifo.
+func (fc *firecracker) registerMetrics(r prometheus.Registerer) {
+	if fc.metrics != nil {
+		fc.metrics.register(r)
+	}
 }
 
 type fifoConsumer func(string)
@@ -731,9 +743,6 @@ func (fc *firecracker) fcInitConfiguration(ctx context.Context) error {
 		}
 	}
 
-	// register firecracker specificed metrics
-	registerFirecrackerMetrics()
-
 	return nil
 }
 
@@ -11,348 +11,371 @@ import (
 
 const fcMetricsNS = "kata_firecracker"
 
-// prometheus metrics Firecracker exposed.
-var (
-	apiServerMetrics = prometheus.NewGaugeVec(prometheus.GaugeOpts{
-		Namespace: fcMetricsNS,
-		Name:      "api_server",
-		Help:      "Metrics related to the internal API server.",
-	},
-		[]string{"item"},
-	)
-
-	blockDeviceMetrics = prometheus.NewGaugeVec(prometheus.GaugeOpts{
-		Namespace: fcMetricsNS,
-		Name:      "block",
-		Help:      "Block Device associated metrics.",
-	},
-		[]string{"item"},
-	)
-
-	getRequestsMetrics = prometheus.NewGaugeVec(prometheus.GaugeOpts{
-		Namespace: fcMetricsNS,
-		Name:      "get_api_requests",
-		Help:      "Metrics specific to GET API Requests for counting user triggered actions and/or failures.",
-	},
-		[]string{"item"},
-	)
-
-	i8042DeviceMetrics = prometheus.NewGaugeVec(prometheus.GaugeOpts{
-		Namespace: fcMetricsNS,
-		Name:      "i8042",
-		Help:      "Metrics specific to the i8042 device.",
-	},
-		[]string{"item"},
-	)
-
-	performanceMetrics = prometheus.NewGaugeVec(prometheus.GaugeOpts{
-		Namespace: fcMetricsNS,
-		Name:      "latencies_us",
-		Help:      "Performance metrics related for the moment only to snapshots.",
-	},
-		[]string{"item"},
-	)
-
-	loggerSystemMetrics = prometheus.NewGaugeVec(prometheus.GaugeOpts{
-		Namespace: fcMetricsNS,
-		Name:      "logger",
-		Help:      "Metrics for the logging subsystem.",
-	},
-		[]string{"item"},
-	)
-
-	mmdsMetrics = prometheus.NewGaugeVec(prometheus.GaugeOpts{
-		Namespace: fcMetricsNS,
-		Name:      "mmds",
-		Help:      "Metrics for the MMDS functionality.",
-	},
-		[]string{"item"},
-	)
-
-	netDeviceMetrics = prometheus.NewGaugeVec(prometheus.GaugeOpts{
-		Namespace: fcMetricsNS,
-		Name:      "net",
-		Help:      "Network-related metrics.",
-	},
-		[]string{"item"},
-	)
-
-	patchRequestsMetrics = prometheus.NewGaugeVec(prometheus.GaugeOpts{
-		Namespace: fcMetricsNS,
-		Name:      "patch_api_requests",
-		Help:      "Metrics specific to PATCH API Requests for counting user triggered actions and/or failures.",
-	},
-		[]string{"item"},
-	)
-
-	putRequestsMetrics = prometheus.NewGaugeVec(prometheus.GaugeOpts{
-		Namespace: fcMetricsNS,
-		Name:      "put_api_requests",
-		Help:      "Metrics specific to PUT API Requests for counting user triggered actions and/or failures.",
-	},
-		[]string{"item"},
-	)
-
-	rTCDeviceMetrics = prometheus.NewGaugeVec(prometheus.GaugeOpts{
-		Namespace: fcMetricsNS,
-		Name:      "rtc",
-		Help:      "Metrics specific to the RTC device.",
-	},
-		[]string{"item"},
-	)
-
-	seccompMetrics = prometheus.NewGaugeVec(prometheus.GaugeOpts{
-		Namespace: fcMetricsNS,
-		Name:      "seccomp",
-		Help:      "Metrics for the seccomp filtering.",
-	},
-		[]string{"item"},
-	)
-
-	vcpuMetrics = prometheus.NewGaugeVec(prometheus.GaugeOpts{
-		Namespace: fcMetricsNS,
-		Name:      "vcpu",
-		Help:      "Metrics specific to VCPUs' mode of functioning.",
-	},
-		[]string{"item"},
-	)
-
-	vmmMetrics = prometheus.NewGaugeVec(prometheus.GaugeOpts{
-		Namespace: fcMetricsNS,
-		Name:      "vmm",
-		Help:      "Metrics specific to the machine manager as a whole.",
-	},
-		[]string{"item"},
-	)
-
-	serialDeviceMetrics = prometheus.NewGaugeVec(prometheus.GaugeOpts{
-		Namespace: fcMetricsNS,
-		Name:      "uart",
-		Help:      "Metrics specific to the UART device.",
-	},
-		[]string{"item"},
-	)
-
-	signalMetrics = prometheus.NewGaugeVec(prometheus.GaugeOpts{
-		Namespace: fcMetricsNS,
-		Name:      "signals",
-		Help:      "Metrics related to signals.",
-	},
-		[]string{"item"},
-	)
-
-	vsockDeviceMetrics = prometheus.NewGaugeVec(prometheus.GaugeOpts{
-		Namespace: fcMetricsNS,
-		Name:      "vsock",
-		Help:      "Vsock-related metrics.",
-	},
-		[]string{"item"},
-	)
-)
+// firecrackerCollectors are the prometheus metrics Firecracker exposed,
+// each VM has its own ones.
+type firecrackerCollectors struct {
+	apiServerMetrics     *prometheus.GaugeVec
+	blockDeviceMetrics   *prometheus.GaugeVec
+	getRequestsMetrics   *prometheus.GaugeVec
+	i8042DeviceMetrics   *prometheus.GaugeVec
+	performanceMetrics   *prometheus.GaugeVec
+	loggerSystemMetrics  *prometheus.GaugeVec
+	mmdsMetrics          *prometheus.GaugeVec
+	netDeviceMetrics     *prometheus.GaugeVec
+	patchRequestsMetrics *prometheus.GaugeVec
+	putRequestsMetrics   *prometheus.GaugeVec
+	rTCDeviceMetrics     *prometheus.GaugeVec
+	seccompMetrics       *prometheus.GaugeVec
+	vcpuMetrics          *prometheus.GaugeVec
+	vmmMetrics           *prometheus.GaugeVec
+	serialDeviceMetrics  *prometheus.GaugeVec
+	signalMetrics        *prometheus.GaugeVec
+	vsockDeviceMetrics   *prometheus.GaugeVec
+}
 
-// registerFirecrackerMetrics register all metrics to prometheus.
-func registerFirecrackerMetrics() {
-	prometheus.MustRegister(apiServerMetrics)
-	prometheus.MustRegister(blockDeviceMetrics)
-	prometheus.MustRegister(getRequestsMetrics)
-	prometheus.MustRegister(i8042DeviceMetrics)
-	prometheus.MustRegister(performanceMetrics)
-	prometheus.MustRegister(loggerSystemMetrics)
-	prometheus.MustRegister(mmdsMetrics)
-	prometheus.MustRegister(netDeviceMetrics)
-	prometheus.MustRegister(patchRequestsMetrics)
-	prometheus.MustRegister(putRequestsMetrics)
-	prometheus.MustRegister(rTCDeviceMetrics)
-	prometheus.MustRegister(seccompMetrics)
-	prometheus.MustRegister(vcpuMetrics)
-	prometheus.MustRegister(vmmMetrics)
-	prometheus.MustRegister(serialDeviceMetrics)
-	prometheus.MustRegister(signalMetrics)
-	prometheus.MustRegister(vsockDeviceMetrics)
+// newFirecrackerCollectors creates the prometheus metrics Firecracker exposed.
+func newFirecrackerCollectors() *firecrackerCollectors {
+	return &firecrackerCollectors{
+		apiServerMetrics: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: fcMetricsNS,
+			Name:      "api_server",
+			Help:      "Metrics related to the internal API server.",
+		},
+			[]string{"item"},
+		),
+
+		blockDeviceMetrics: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: fcMetricsNS,
+			Name:      "block",
+			Help:      "Block Device associated metrics.",
+		},
+			[]string{"item"},
+		),
+
+		getRequestsMetrics: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: fcMetricsNS,
+			Name:      "get_api_requests",
+			Help:      "Metrics specific to GET API Requests for counting user triggered actions and/or failures.",
+		},
+			[]string{"item"},
+		),
+
+		i8042DeviceMetrics: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: fcMetricsNS,
+			Name:      "i8042",
+			Help:      "Metrics specific to the i8042 device.",
+		},
+			[]string{"item"},
+		),
+
+		performanceMetrics: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: fcMetricsNS,
+			Name:      "latencies_us",
+			Help:      "Performance metrics related for the moment only to snapshots.",
+		},
+			[]string{"item"},
+		),
+
+		loggerSystemMetrics: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: fcMetricsNS,
+			Name:      "logger",
+			Help:      "Metrics for the logging subsystem.",
+		},
+			[]string{"item"},
+		),
+
+		mmdsMetrics: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: fcMetricsNS,
+			Name:      "mmds",
+			Help:      "Metrics for the MMDS functionality.",
+		},
+			[]string{"item"},
+		),
+
+		netDeviceMetrics: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: fcMetricsNS,
+			Name:      "net",
+			Help:      "Network-related metrics.",
+		},
+			[]string{"item"},
+		),
+
+		patchRequestsMetrics: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: fcMetricsNS,
+			Name:      "patch_api_requests",
+			Help:      "Metrics specific to PATCH API Requests for counting user triggered actions and/or failures.",
+		},
+			[]string{"item"},
+		),
+
+		putRequestsMetrics: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: fcMetricsNS,
+			Name:      "put_api_requests",
+			Help:      "Metrics specific to PUT API Requests for counting user triggered actions and/or failures.",
+		},
+			[]string{"item"},
+		),
+
+		rTCDeviceMetrics: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: fcMetricsNS,
+			Name:      "rtc",
+			Help:      "Metrics specific to the RTC device.",
+		},
+			[]string{"item"},
+		),
+
+		seccompMetrics: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: fcMetricsNS,
+			Name:      "seccomp",
+			Help:      "Metrics for the seccomp filtering.",
+		},
+			[]string{"item"},
+		),
+
+		vcpuMetrics: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: fcMetricsNS,
+			Name:      "vcpu",
+			Help:      "Metrics specific to VCPUs' mode of functioning.",
+		},
+			[]string{"item"},
+		),
+
+		vmmMetrics: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: fcMetricsNS,
+			Name:      "vmm",
+			Help:      "Metrics specific to the machine manager as a whole.",
+		},
+			[]string{"item"},
+		),
+
+		serialDeviceMetrics: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: fcMetricsNS,
+			Name:      "uart",
+			Help:      "Metrics specific to the UART device.",
+		},
+			[]string{"item"},
+		),
+
+		signalMetrics: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: fcMetricsNS,
+			Name:      "signals",
+			Help:      "Metrics related to signals.",
+		},
+			[]string{"item"},
+		),
+
+		vsockDeviceMetrics: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: fcMetricsNS,
+			Name:      "vsock",
+			Help:      "Vsock-related metrics.",
+		},
+			[]string{"item"},
+		),
+	}
+}
 
+// register registers all the metrics to prometheus.
+func (m *firecrackerCollectors) register(r prometheus.Registerer) {
+	r.MustRegister(m.apiServerMetrics)
+	r.MustRegister(m.blockDeviceMetrics)
+	r.MustRegister(m.getRequestsMetrics)
+	r.MustRegister(m.i8042DeviceMetrics)
+	r.MustRegister(m.performanceMetrics)
+	r.MustRegister(m.loggerSystemMetrics)
+	r.MustRegister(m.mmdsMetrics)
+	r.MustRegister(m.netDeviceMetrics)
+	r.MustRegister(m.patchRequestsMetrics)
+	r.MustRegister(m.putRequestsMetrics)
+	r.MustRegister(m.rTCDeviceMetrics)
+	r.MustRegister(m.seccompMetrics)
+	r.MustRegister(m.vcpuMetrics)
+	r.MustRegister(m.vmmMetrics)
+	r.MustRegister(m.serialDeviceMetrics)
+	r.MustRegister(m.signalMetrics)
+	r.MustRegister(m.vsockDeviceMetrics)
 }
 
-// updateFirecrackerMetrics update all metrics to the latest values.
-func updateFirecrackerMetrics(fm *FirecrackerMetrics) {
+// update updates all the metrics to the latest values.
+func (m *firecrackerCollectors) update(fm *FirecrackerMetrics) {
 	// set metrics for APIServerMetrics
-	apiServerMetrics.WithLabelValues("process_startup_time_us").Set(float64(fm.APIServer.ProcessStartupTimeUs))
-	apiServerMetrics.WithLabelValues("process_startup_time_cpu_us").Set(float64(fm.APIServer.ProcessStartupTimeCPUUs))
-	apiServerMetrics.WithLabelValues("sync_response_fails").Set(float64(fm.APIServer.SyncResponseFails))
-	apiServerMetrics.WithLabelValues("sync_vmm_send_timeout_count").Set(float64(fm.APIServer.SyncVmmSendTimeoutCount))
+	m.apiServerMetrics.WithLabelValues("process_startup_time_us").Set(float64(fm.APIServer.ProcessStartupTimeUs))
+	m.apiServerMetrics.WithLabelValues("process_startup_time_cpu_us").Set(float64(fm.APIServer.ProcessStartupTimeCPUUs))
+	m.apiServerMetrics.WithLabelValues("sync_response_fails").Set(float64(fm.APIServer.SyncResponseFails))
+	m.apiServerMetrics.WithLabelValues("sync_vmm_send_timeout_count").Set(float64(fm.APIServer.SyncVmmSendTimeoutCount))
 
 	// set metrics for BlockDeviceMetrics
-	blockDeviceMetrics.WithLabelValues("activate_fails").Set(float64(fm.Block.ActivateFails))
-	blockDeviceMetrics.WithLabelValues("cfg_fails").Set(float64(fm.Block.CfgFails))
-	blockDeviceMetrics.WithLabelValues("no_avail_buffer").Set(float64(fm.Block.NoAvailBuffer))
-	blockDeviceMetrics.WithLabelValues("event_fails").Set(float64(fm.Block.EventFails))
-	blockDeviceMetrics.WithLabelValues("execute_fails").Set(float64(fm.Block.ExecuteFails))
-	blockDeviceMetrics.WithLabelValues("invalid_reqs_count").Set(float64(fm.Block.InvalidReqsCount))
-	blockDeviceMetrics.WithLabelValues("flush_count").Set(float64(fm.Block.FlushCount))
-	blockDeviceMetrics.WithLabelValues("queue_event_count").Set(float64(fm.Block.QueueEventCount))
-	blockDeviceMetrics.WithLabelValues("rate_limiter_event_count").Set(float64(fm.Block.RateLimiterEventCount))
-	blockDeviceMetrics.WithLabelValues("update_count").Set(float64(fm.Block.UpdateCount))
-	blockDeviceMetrics.WithLabelValues("update_fails").Set(float64(fm.Block.UpdateFails))
-	blockDeviceMetrics.WithLabelValues("read_bytes").Set(float64(fm.Block.ReadBytes))
-	blockDeviceMetrics.WithLabelValues("write_bytes").Set(float64(fm.Block.WriteBytes))
-	blockDeviceMetrics.WithLabelValues("read_count").Set(float64(fm.Block.ReadCount))
-	blockDeviceMetrics.WithLabelValues("write_count").Set(float64(fm.Block.WriteCount))
-	blockDeviceMetrics.WithLabelValues("rate_limiter_throttled_events").Set(float64(fm.Block.RateLimiterThrottledEvents))
+	m.blockDeviceMetrics.WithLabelValues("activate_fails").Set(float64(fm.Block.ActivateFails))
+	m.blockDeviceMetrics.WithLabelValues("cfg_fails").Set(float64(fm.Block.CfgFails))
+	m.blockDeviceMetrics.WithLabelValues("no_avail_buffer").Set(float64(fm.Block.NoAvailBuffer))
+	m.blockDeviceMetrics.WithLabelValues("event_fails").Set(float64(fm.Block.EventFails))
+	m.blockDeviceMetrics.WithLabelValues("execute_fails").Set(float64(fm.Block.ExecuteFails))
+	m.blockDeviceMetrics.WithLabelValues("invalid_reqs_count").Set(float64(fm.Block.InvalidReqsCount))
+	m.blockDeviceMetrics.WithLabelValues("flush_count").Set(float64(fm.Block.FlushCount))
+	m.blockDeviceMetrics.WithLabelValues("queue_event_count").Set(float64(fm.Block.QueueEventCount))
+	m.blockDeviceMetrics.WithLabelValues("rate_limiter_event_count").Set(float64(fm.Block.RateLimiterEventCount))
+	m.blockDeviceMetrics.WithLabelValues("update_count").Set(float64(fm.Block.UpdateCount))
+	m.blockDeviceMetrics.WithLabelValues("update_fails").Set(float64(fm.Block.UpdateFails))
+	m.blockDeviceMetrics.WithLabelValues("read_bytes").Set(float64(fm.Block.ReadBytes))
+	m.blockDeviceMetrics.WithLabelValues("write_bytes").Set(float64(fm.Block.WriteBytes))
+	m.blockDeviceMetrics.WithLabelValues("read_count").Set(float64(fm.Block.ReadCount))
+	m.blockDeviceMetrics.WithLabelValues("write_count").Set(float64(fm.Block.WriteCount))
+	m.blockDeviceMetrics.WithLabelValues("rate_limiter_throttled_events").Set(float64(fm.Block.RateLimiterThrottledEvents))
 
 	// set metrics for GetRequestsMetrics
-	getRequestsMetrics.WithLabelValues("instance_info_count").Set(float64(fm.GetAPIRequests.InstanceInfoCount))
-	getRequestsMetrics.WithLabelValues("instance_info_fails").Set(float64(fm.GetAPIRequests.InstanceInfoFails))
-	getRequestsMetrics.WithLabelValues("machine_cfg_count").Set(float64(fm.GetAPIRequests.MachineCfgCount))
-	getRequestsMetrics.WithLabelValues("machine_cfg_fails").Set(float64(fm.GetAPIRequests.MachineCfgFails))
+	m.getRequestsMetrics.WithLabelValues("instance_info_count").Set(float64(fm.GetAPIRequests.InstanceInfoCount))
+	m.getRequestsMetrics.WithLabelValues("instance_info_fails").Set(float64(fm.GetAPIRequests.InstanceInfoFails))
+	m.getRequestsMetrics.WithLabelValues("machine_cfg_count").Set(float64(fm.GetAPIRequests.MachineCfgCount))
+	m.getRequestsMetrics.WithLabelValues("machine_cfg_fails").Set(float64(fm.GetAPIRequests.MachineCfgFails))
 
 	// set metrics for I8042DeviceMetrics
-	i8042DeviceMetrics.WithLabelValues("error_count").Set(float64(fm.I8042.ErrorCount))
-	i8042DeviceMetrics.WithLabelValues("missed_read_count").Set(float64(fm.I8042.MissedReadCount))
-	i8042DeviceMetrics.WithLabelValues("missed_write_count").Set(float64(fm.I8042.MissedWriteCount))
-	i8042DeviceMetrics.WithLabelValues("read_count").Set(float64(fm.I8042.ReadCount))
-	i8042DeviceMetrics.WithLabelValues("reset_count").Set(float64(fm.I8042.ResetCount))
-	i8042DeviceMetrics.WithLabelValues("write_count").Set(float64(fm.I8042.WriteCount))
+	m.i8042DeviceMetrics.WithLabelValues("error_count").Set(float64(fm.I8042.ErrorCount))
+	m.i8042DeviceMetrics.WithLabelValues("missed_read_count").Set(float64(fm.I8042.MissedReadCount))
+	m.i8042DeviceMetrics.WithLabelValues("missed_write_count").Set(float64(fm.I8042.MissedWriteCount))
+	m.i8042DeviceMetrics.WithLabelValues("read_count").Set(float64(fm.I8042.ReadCount))
+	m.i8042DeviceMetrics.WithLabelValues("reset_count").Set(float64(fm.I8042.ResetCount))
+	m.i8042DeviceMetrics.WithLabelValues("write_count").Set(float64(fm.I8042.WriteCount))
 
 	// set metrics for PerformanceMetrics
-	performanceMetrics.WithLabelValues("full_create_snapshot").Set(float64(fm.LatenciesUs.FullCreateSnapshot))
-	performanceMetrics.WithLabelValues("diff_create_snapshot").Set(float64(fm.LatenciesUs.DiffCreateSnapshot))
-	performanceMetrics.WithLabelValues("load_snapshot").Set(float64(fm.LatenciesUs.LoadSnapshot))
-	performanceMetrics.WithLabelValues("pause_vm").Set(float64(fm.LatenciesUs.PauseVM))
-	performanceMetrics.WithLabelValues("resume_vm").Set(float64(fm.LatenciesUs.ResumeVM))
-	performanceMetrics.WithLabelValues("vmm_full_create_snapshot").Set(float64(fm.LatenciesUs.VmmFullCreateSnapshot))
-	performanceMetrics.WithLabelValues("vmm_diff_create_snapshot").Set(float64(fm.LatenciesUs.VmmDiffCreateSnapshot))
-	performanceMetrics.WithLabelValues("vmm_load_snapshot").Set(float64(fm.LatenciesUs.VmmLoadSnapshot))
-	performanceMetrics.WithLabelValues("vmm_pause_vm").Set(float64(fm.LatenciesUs.VmmPauseVM))
-	performanceMetrics.WithLabelValues("vmm_resume_vm").Set(float64(fm.LatenciesUs.VmmResumeVM))
+	m.performanceMetrics.WithLabelValues("full_create_snapshot").Set(float64(fm.LatenciesUs.FullCreateSnapshot))
+	m.performanceMetrics.WithLabelValues("diff_create_snapshot").Set(float64(fm.LatenciesUs.DiffCreateSnapshot))
+	m.performanceMetrics.WithLabelValues("load_snapshot").Set(float64(fm.LatenciesUs.LoadSnapshot))
+	m.performanceMetrics.WithLabelValues("pause_vm").Set(float64(fm.LatenciesUs.PauseVM))
+	m.performanceMetrics.WithLabelValues("resume_vm").Set(float64(fm.LatenciesUs.ResumeVM))
+	m.performanceMetrics.WithLabelValues("vmm_full_create_snapshot").Set(float64(fm.LatenciesUs.VmmFullCreateSnapshot))
+	m.performanceMetrics.WithLabelValues("vmm_diff_create_snapshot").Set(float64(fm.LatenciesUs.VmmDiffCreateSnapshot))
+	m.performanceMetrics.WithLabelValues("vmm_load_snapshot").Set(float64(fm.LatenciesUs.VmmLoadSnapshot))
+	m.performanceMetrics.WithLabelValues("vmm_pause_vm").Set(float64(fm.LatenciesUs.VmmPauseVM))
+	m.performanceMetrics.WithLabelValues("vmm_resume_vm").Set(float64(fm.LatenciesUs.VmmResumeVM))
 
 	// set metrics for LoggerSystemMetrics
-	loggerSystemMetrics.WithLabelValues("missed_metrics_count").Set(float64(fm.Logger.MissedMetricsCount))
-	loggerSystemMetrics.WithLabelValues("metrics_fails").Set(float64(fm.Logger.MetricsFails))
-	loggerSystemMetrics.WithLabelValues("missed_log_count").Set(float64(fm.Logger.MissedLogCount))
-	loggerSystemMetrics.WithLabelValues("log_fails").Set(float64(fm.Logger.LogFails))
+	m.loggerSystemMetrics.WithLabelValues("missed_metrics_count").Set(float64(fm.Logger.MissedMetricsCount))
+	m.loggerSystemMetrics.WithLabelValues("metrics_fails").Set(float64(fm.Logger.MetricsFails))
+	m.loggerSystemMetrics.WithLabelValues("missed_log_count").Set(float64(fm.Logger.MissedLogCount))
+	m.loggerSystemMetrics.WithLabelValues("log_fails").Set(float64(fm.Logger.LogFails))
 
 	// set metrics for MmdsMetrics
-	mmdsMetrics.WithLabelValues("rx_accepted").Set(float64(fm.Mmds.RxAccepted))
-	mmdsMetrics.WithLabelValues("rx_accepted_err").Set(float64(fm.Mmds.RxAcceptedErr))
-	mmdsMetrics.WithLabelValues("rx_accepted_unusual").Set(float64(fm.Mmds.RxAcceptedUnusual))
-	mmdsMetrics.WithLabelValues("rx_bad_eth").Set(float64(fm.Mmds.RxBadEth))
-	mmdsMetrics.WithLabelValues("rx_count").Set(float64(fm.Mmds.RxCount))
-	mmdsMetrics.WithLabelValues("tx_bytes").Set(float64(fm.Mmds.TxBytes))
-	mmdsMetrics.WithLabelValues("tx_count").Set(float64(fm.Mmds.TxCount))
-	mmdsMetrics.WithLabelValues("tx_errors").Set(float64(fm.Mmds.TxErrors))
-	mmdsMetrics.WithLabelValues("tx_frames").Set(float64(fm.Mmds.TxFrames))
-	mmdsMetrics.WithLabelValues("connections_created").Set(float64(fm.Mmds.ConnectionsCreated))
-	mmdsMetrics.WithLabelValues("connections_destroyed").Set(float64(fm.Mmds.ConnectionsDestroyed))
+	m.mmdsMetrics.WithLabelValues("rx_accepted").Set(float64(fm.Mmds.RxAccepted))
+	m.mmdsMetrics.WithLabelValues("rx_accepted_err").Set(float64(fm.Mmds.RxAcceptedErr))
+	m.mmdsMetrics.WithLabelValues("rx_accepted_unusual").Set(float64(fm.Mmds.RxAcceptedUnusual))
+	m.mmdsMetrics.WithLabelValues("rx_bad_eth").Set(float64(fm.Mmds.RxBadEth))
+	m.mmdsMetrics.WithLabelValues("rx_count").Set(float64(fm.Mmds.RxCount))
+	m.mmdsMetrics.WithLabelValues("tx_bytes").Set(float64(fm.Mmds.TxBytes))
+	m.mmdsMetrics.WithLabelValues("tx_count").Set(float64(fm.Mmds.TxCount))
+	m.mmdsMetrics.WithLabelValues("tx_errors").Set(float64(fm.Mmds.TxErrors))
+	m.mmdsMetrics.WithLabelValues("tx_frames").Set(float64(fm.Mmds.TxFrames))
+	m.mmdsMetrics.WithLabelValues("connections_created").Set(float64(fm.Mmds.ConnectionsCreated))
+	m.mmdsMetrics.WithLabelValues("connections_destroyed").Set(float64(fm.Mmds.ConnectionsDestroyed))
 
 	// set metrics for NetDeviceMetrics
-	netDeviceMetrics.WithLabelValues("activate_fails").Set(float64(fm.Net.ActivateFails))
-	netDeviceMetrics.WithLabelValues("cfg_fails").Set(float64(fm.Net.CfgFails))
-	netDeviceMetrics.WithLabelValues("mac_address_updates").Set(float64(fm.Net.MacAddressUpdates))
-	netDeviceMetrics.WithLabelValues("no_rx_avail_buffer").Set(float64(fm.Net.NoRxAvailBuffer))
-	netDeviceMetrics.WithLabelValues("no_tx_avail_buffer").Set(float64(fm.Net.NoTxAvailBuffer))
-	netDeviceMetrics.WithLabelValues("event_fails").Set(float64(fm.Net.EventFails))
-	netDeviceMetrics.WithLabelValues("rx_queue_event_count").Set(float64(fm.Net.RxQueueEventCount))
-	netDeviceMetrics.WithLabelValues("rx_event_rate_limiter_count").Set(float64(fm.Net.RxEventRateLimiterCount))
-	netDeviceMetrics.WithLabelValues("rx_partial_writes").Set(float64(fm.Net.RxPartialWrites))
-	netDeviceMetrics.WithLabelValues("rx_rate_limiter_throttled").Set(float64(fm.Net.RxRateLimiterThrottled))
-	netDeviceMetrics.WithLabelValues("rx_tap_event_count").Set(float64(fm.Net.RxTapEventCount))
-	netDeviceMetrics.WithLabelValues("rx_bytes_count").Set(float64(fm.Net.RxBytesCount))
-	netDeviceMetrics.WithLabelValues("rx_packets_count").Set(float64(fm.Net.RxPacketsCount))
-	netDeviceMetrics.WithLabelValues("rx_fails").Set(float64(fm.Net.RxFails))
-	netDeviceMetrics.WithLabelValues("rx_count").Set(float64(fm.Net.RxCount))
-	netDeviceMetrics.WithLabelValues("tap_read_fails").Set(float64(fm.Net.TapReadFails))
-	netDeviceMetrics.WithLabelValues("tap_write_fails").Set(float64(fm.Net.TapWriteFails))
-	netDeviceMetrics.WithLabelValues("tx_bytes_count").Set(float64(fm.Net.TxBytesCount))
-	netDeviceMetrics.WithLabelValues("tx_malformed_frames").Set(float64(fm.Net.TxMalformedFrames))
-	netDeviceMetrics.WithLabelValues("tx_fails").Set(float64(fm.Net.TxFails))
-	netDeviceMetrics.WithLabelValues("tx_count").Set(float64(fm.Net.TxCount))
-	netDeviceMetrics.WithLabelValues("tx_packets_count").Set(float64(fm.Net.TxPacketsCount))
-	netDeviceMetrics.WithLabelValues("tx_partial_reads").Set(float64(fm.Net.TxPartialReads))
-	netDeviceMetrics.WithLabelValues("tx_queue_event_count").Set(float64(fm.Net.TxQueueEventCount))
-	netDeviceMetrics.WithLabelValues("tx_rate_limiter_event_count").Set(float64(fm.Net.TxRateLimiterEventCount))
-	netDeviceMetrics.WithLabelValues("tx_rate_limiter_throttled").Set(float64(fm.Net.TxRateLimiterThrottled))
-	netDeviceMetrics.WithLabelValues("tx_spoofed_mac_count").Set(float64(fm.Net.TxSpoofedMacCount))
+	m.netDeviceMetrics.WithLabelValues("activate_fails").Set(float64(fm.Net.ActivateFails))
+	m.netDeviceMetrics.WithLabelValues("cfg_fails").Set(float64(fm.Net.CfgFails))
+	m.netDeviceMetrics.WithLabelValues("mac_address_updates").Set(float64(fm.Net.MacAddressUpdates))
+	m.netDeviceMetrics.WithLabelValues("no_rx_avail_buffer").Set(float64(fm.Net.NoRxAvailBuffer))
+	m.netDeviceMetrics.WithLabelValues("no_tx_avail_buffer").Set(float64(fm.Net.NoTxAvailBuffer))
+	m.netDeviceMetrics.WithLabelValues("event_fails").Set(float64(fm.Net.EventFails))
+	m.netDeviceMetrics.WithLabelValues("rx_queue_event_count").Set(float64(fm.Net.RxQueueEventCount))
+	m.netDeviceMetrics.WithLabelValues("rx_event_rate_limiter_count").Set(float64(fm.Net.RxEventRateLimiterCount))
+	m.netDeviceMetrics.WithLabelValues("rx_partial_writes").Set(float64(fm.Net.RxPartialWrites))
+	m.netDeviceMetrics.WithLabelValues("rx_rate_limiter_throttled").Set(float64(fm.Net.RxRateLimiterThrottled))
+	m.netDeviceMetrics.WithLabelValues("rx_tap_event_count").Set(float64(fm.Net.RxTapEventCount))
+	m.netDeviceMetrics.WithLabelValues("rx_bytes_count").Set(float64(fm.Net.RxBytesCount))
+	m.netDeviceMetrics.WithLabelValues("rx_packets_count").Set(float64(fm.Net.RxPacketsCount))
+	m.netDeviceMetrics.WithLabelValues("rx_fails").Set(float64(fm.Net.RxFails))
+	m.netDeviceMetrics.WithLabelValues("rx_count").Set(float64(fm.Net.RxCount))
+	m.netDeviceMetrics.WithLabelValues("tap_read_fails").Set(float64(fm.Net.TapReadFails))
+	m.netDeviceMetrics.WithLabelValues("tap_write_fails").Set(float64(fm.Net.TapWriteFails))
+	m.netDeviceMetrics.WithLabelValues("tx_bytes_count").Set(float64(fm.Net.TxBytesCount))
+	m.netDeviceMetrics.WithLabelValues("tx_malformed_frames").Set(float64(fm.Net.TxMalformedFrames))
+	m.netDeviceMetrics.WithLabelValues("tx_fails").Set(float64(fm.Net.TxFails))
+	m.netDeviceMetrics.WithLabelValues("tx_count").Set(float64(fm.Net.TxCount))
+	m.netDeviceMetrics.WithLabelValues("tx_packets_count").Set(float64(fm.Net.TxPacketsCount))
+	m.netDeviceMetrics.WithLabelValues("tx_partial_reads").Set(float64(fm.Net.TxPartialReads))
+	m.netDeviceMetrics.WithLabelValues("tx_queue_event_count").Set(float64(fm.Net.TxQueueEventCount))
+	m.netDeviceMetrics.WithLabelValues("tx_rate_limiter_event_count").Set(float64(fm.Net.TxRateLimiterEventCount))
+	m.netDeviceMetrics.WithLabelValues("tx_rate_limiter_throttled").Set(float64(fm.Net.TxRateLimiterThrottled))
+	m.netDeviceMetrics.WithLabelValues("tx_spoofed_mac_count").Set(float64(fm.Net.TxSpoofedMacCount))
 
 	// set metrics for PatchRequestsMetrics
-	patchRequestsMetrics.WithLabelValues("drive_count").Set(float64(fm.PatchAPIRequests.DriveCount))
-	patchRequestsMetrics.WithLabelValues("drive_fails").Set(float64(fm.PatchAPIRequests.DriveFails))
-	patchRequestsMetrics.WithLabelValues("network_count").Set(float64(fm.PatchAPIRequests.NetworkCount))
-	patchRequestsMetrics.WithLabelValues("network_fails").Set(float64(fm.PatchAPIRequests.NetworkFails))
-	patchRequestsMetrics.WithLabelValues("machine_cfg_count").Set(float64(fm.PatchAPIRequests.MachineCfgCount))
-	patchRequestsMetrics.WithLabelValues("machine_cfg_fails").Set(float64(fm.PatchAPIRequests.MachineCfgFails))
+	m.patchRequestsMetrics.WithLabelValues("drive_count").Set(float64(fm.PatchAPIRequests.DriveCount))
+	m.patchRequestsMetrics.WithLabelValues("drive_fails").Set(float64(fm.PatchAPIRequests.DriveFails))
+	m.patchRequestsMetrics.WithLabelValues("network_count").Set(float64(fm.PatchAPIRequests.NetworkCount))
+	m.patchRequestsMetrics.WithLabelValues("network_fails").Set(float64(fm.PatchAPIRequests.NetworkFails))
+	m.patchRequestsMetrics.WithLabelValues("machine_cfg_count").Set(float64(fm.PatchAPIRequests.MachineCfgCount))
+	m.patchRequestsMetrics.WithLabelValues("machine_cfg_fails").Set(float64(fm.PatchAPIRequests.MachineCfgFails))
 
 	// set metrics for PutRequestsMetrics
-	putRequestsMetrics.WithLabelValues("actions_count").Set(float64(fm.PutAPIRequests.ActionsCount))
-	putRequestsMetrics.WithLabelValues("actions_fails").Set(float64(fm.PutAPIRequests.ActionsFails))
-	putRequestsMetrics.WithLabelValues("boot_source_count").Set(float64(fm.PutAPIRequests.BootSourceCount))
-	putRequestsMetrics.WithLabelValues("boot_source_fails").Set(float64(fm.PutAPIRequests.BootSourceFails))
-	putRequestsMetrics.WithLabelValues("drive_count").Set(float64(fm.PutAPIRequests.DriveCount))
-	putRequestsMetrics.WithLabelValues("drive_fails").Set(float64(fm.PutAPIRequests.DriveFails))
-	putRequestsMetrics.WithLabelValues("logger_count").Set(float64(fm.PutAPIRequests.LoggerCount))
-	putRequestsMetrics.WithLabelValues("logger_fails").Set(float64(fm.PutAPIRequests.LoggerFails))
-	putRequestsMetrics.WithLabelValues("machine_cfg_count").Set(float64(fm.PutAPIRequests.MachineCfgCount))
-	putRequestsMetrics.WithLabelValues("machine_cfg_fails").Set(float64(fm.PutAPIRequests.MachineCfgFails))
-	putRequestsMetrics.WithLabelValues("metrics_count").Set(float64(fm.PutAPIRequests.MetricsCount))
-	putRequestsMetrics.WithLabelValues("metrics_fails").Set(float64(fm.PutAPIRequests.MetricsFails))
-	putRequestsMetrics.WithLabelValues("network_count").Set(float64(fm.PutAPIRequests.NetworkCount))
-	putRequestsMetrics.WithLabelValues("network_fails").Set(float64(fm.PutAPIRequests.NetworkFails))
+	m.putRequestsMetrics.WithLabelValues("actions_count").Set(float64(fm.PutAPIRequests.ActionsCount))
+	m.putRequestsMetrics.WithLabelValues("actions_fails").Set(float64(fm.PutAPIRequests.ActionsFails))
+	m.putRequestsMetrics.WithLabelValues("boot_source_count").Set(float64(fm.PutAPIRequests.BootSourceCount))
+	m.putRequestsMetrics.WithLabelValues("boot_source_fails").Set(float64(fm.PutAPIRequests.BootSourceFails))
+	m.putRequestsMetrics.WithLabelValues("drive_count").Set(float64(fm.PutAPIRequests.DriveCount))
+	m.putRequestsMetrics.WithLabelValues("drive_fails").Set(float64(fm.PutAPIRequests.DriveFails))
+	m.putRequestsMetrics.WithLabelValues("logger_count").Set(float64(fm.PutAPIRequests.LoggerCount))
+	m.putRequestsMetrics.WithLabelValues("logger_fails").Set(float64(fm.PutAPIRequests.LoggerFails))
+	m.putRequestsMetrics.WithLabelValues("machine_cfg_count").Set(float64(fm.PutAPIRequests.MachineCfgCount))
+	m.putRequestsMetrics.WithLabelValues("machine_cfg_fails").Set(float64(fm.PutAPIRequests.MachineCfgFails))
+	m.putRequestsMetrics.WithLabelValues("metrics_count").Set(float64(fm.PutAPIRequests.MetricsCount))
+	m.putRequestsMetrics.WithLabelValues("metrics_fails").Set(float64(fm.PutAPIRequests.MetricsFails))
+	m.putRequestsMetrics.WithLabelValues("network_count").Set(float64(fm.PutAPIRequests.NetworkCount))
+	m.putRequestsMetrics.WithLabelValues("network_fails").Set(float64(fm.PutAPIRequests.NetworkFails))
 
 	// set metrics for RTCDeviceMetrics
-	rTCDeviceMetrics.WithLabelValues("error_count").Set(float64(fm.Rtc.ErrorCount))
-	rTCDeviceMetrics.WithLabelValues("missed_read_count").Set(float64(fm.Rtc.MissedReadCount))
-	rTCDeviceMetrics.WithLabelValues("missed_write_count").Set(float64(fm.Rtc.MissedWriteCount))
+	m.rTCDeviceMetrics.WithLabelValues("error_count").Set(float64(fm.Rtc.ErrorCount))
+	m.rTCDeviceMetrics.WithLabelValues("missed_read_count").Set(float64(fm.Rtc.MissedReadCount))
+	m.rTCDeviceMetrics.WithLabelValues("missed_write_count").Set(float64(fm.Rtc.MissedWriteCount))
 
 	// set metrics for SeccompMetrics
-	seccompMetrics.WithLabelValues("num_faults").Set(float64(fm.Seccomp.NumFaults))
+	m.seccompMetrics.WithLabelValues("num_faults").Set(float64(fm.Seccomp.NumFaults))
 
 	// set metrics for VcpuMetrics
-	vcpuMetrics.WithLabelValues("exit_io_in").Set(float64(fm.Vcpu.ExitIoIn))
-	vcpuMetrics.WithLabelValues("exit_io_out").Set(float64(fm.Vcpu.ExitIoOut))
-	vcpuMetrics.WithLabelValues("exit_mmio_read").Set(float64(fm.Vcpu.ExitMmioRead))
-	vcpuMetrics.WithLabelValues("exit_mmio_write").Set(float64(fm.Vcpu.ExitMmioWrite))
-	vcpuMetrics.WithLabelValues("failures").Set(float64(fm.Vcpu.Failures))
-	vcpuMetrics.WithLabelValues("filter_cpuid").Set(float64(fm.Vcpu.FilterCPUid))
+	m.vcpuMetrics.WithLabelValues("exit_io_in").Set(float64(fm.Vcpu.ExitIoIn))
+	m.vcpuMetrics.WithLabelValues("exit_io_out").Set(float64(fm.Vcpu.ExitIoOut))
+	m.vcpuMetrics.WithLabelValues("exit_mmio_read").Set(float64(fm.Vcpu.ExitMmioRead))
+	m.vcpuMetrics.WithLabelValues("exit_mmio_write").Set(float64(fm.Vcpu.ExitMmioWrite))
+	m.vcpuMetrics.WithLabelValues("failures").Set(float64(fm.Vcpu.Failures))
+	m.vcpuMetrics.WithLabelValues("filter_cpuid").Set(float64(fm.Vcpu.FilterCPUid))
 
 	// set metrics for VmmMetrics
-	vmmMetrics.WithLabelValues("device_events").Set(float64(fm.Vmm.DeviceEvents))
-	vmmMetrics.WithLabelValues("panic_count").Set(float64(fm.Vmm.PanicCount))
+	m.vmmMetrics.WithLabelValues("device_events").Set(float64(fm.Vmm.DeviceEvents))
+	m.vmmMetrics.WithLabelValues("panic_count").Set(float64(fm.Vmm.PanicCount))
 
 	// set metrics for SerialDeviceMetrics
-	serialDeviceMetrics.WithLabelValues("error_count").Set(float64(fm.Uart.ErrorCount))
-	serialDeviceMetrics.WithLabelValues("flush_count").Set(float64(fm.Uart.FlushCount))
-	serialDeviceMetrics.WithLabelValues("missed_read_count").Set(float64(fm.Uart.MissedReadCount))
-	serialDeviceMetrics.WithLabelValues("missed_write_count").Set(float64(fm.Uart.MissedWriteCount))
-	serialDeviceMetrics.WithLabelValues("read_count").Set(float64(fm.Uart.ReadCount))
-	serialDeviceMetrics.WithLabelValues("write_count").Set(float64(fm.Uart.WriteCount))
+	m.serialDeviceMetrics.WithLabelValues("error_count").Set(float64(fm.Uart.ErrorCount))
+	m.serialDeviceMetrics.WithLabelValues("flush_count").Set(float64(fm.Uart.FlushCount))
+	m.serialDeviceMetrics.WithLabelValues("missed_read_count").Set(float64(fm.Uart.MissedReadCount))
+	m.serialDeviceMetrics.WithLabelValues("missed_write_count").Set(float64(fm.Uart.MissedWriteCount))
+	m.serialDeviceMetrics.WithLabelValues("read_count").Set(float64(fm.Uart.ReadCount))
+	m.serialDeviceMetrics.WithLabelValues("write_count").Set(float64(fm.Uart.WriteCount))
 
 	// set metrics for SignalMetrics
-	signalMetrics.WithLabelValues("sigbus").Set(float64(fm.Signals.Sigbus))
-	signalMetrics.WithLabelValues("sigsegv").Set(float64(fm.Signals.Sigsegv))
+	m.signalMetrics.WithLabelValues("sigbus").Set(float64(fm.Signals.Sigbus))
+	m.signalMetrics.WithLabelValues("sigsegv").Set(float64(fm.Signals.Sigsegv))
 
 	// set metrics for VsockDeviceMetrics
-	vsockDeviceMetrics.WithLabelValues("activate_fails").Set(float64(fm.Vsock.ActivateFails))
-	vsockDeviceMetrics.WithLabelValues("cfg_fails").Set(float64(fm.Vsock.CfgFails))
-	vsockDeviceMetrics.WithLabelValues("rx_queue_event_fails").Set(float64(fm.Vsock.RxQueueEventFails))
-	vsockDeviceMetrics.WithLabelValues("tx_queue_event_fails").Set(float64(fm.Vsock.TxQueueEventFails))
-	vsockDeviceMetrics.WithLabelValues("ev_queue_event_fails").Set(float64(fm.Vsock.EvQueueEventFails))
-	vsockDeviceMetrics.WithLabelValues("muxer_event_fails").Set(float64(fm.Vsock.MuxerEventFails))
-	vsockDeviceMetrics.WithLabelValues("conn_event_fails").Set(float64(fm.Vsock.ConnEventFails))
-	vsockDeviceMetrics.WithLabelValues("rx_queue_event_count").Set(float64(fm.Vsock.RxQueueEventCount))
-	vsockDeviceMetrics.WithLabelValues("tx_queue_event_count").Set(float64(fm.Vsock.TxQueueEventCount))
-	vsockDeviceMetrics.WithLabelValues("rx_bytes_count").Set(float64(fm.Vsock.RxBytesCount))
-	vsockDeviceMetrics.WithLabelValues("tx_bytes_count").Set(float64(fm.Vsock.TxBytesCount))
-	vsockDeviceMetrics.WithLabelValues("rx_packets_count").Set(float64(fm.Vsock.RxPacketsCount))
-	vsockDeviceMetrics.WithLabelValues("tx_packets_count").Set(float64(fm.Vsock.TxPacketsCount))
-	vsockDeviceMetrics.WithLabelValues("conns_added").Set(float64(fm.Vsock.ConnsAdded))
-	vsockDeviceMetrics.WithLabelValues("conns_killed").Set(float64(fm.Vsock.ConnsKilled))
-	vsockDeviceMetrics.WithLabelValues("conns_removed").Set(float64(fm.Vsock.ConnsRemoved))
-	vsockDeviceMetrics.WithLabelValues("killq_resync").Set(float64(fm.Vsock.KillqResync))
-	vsockDeviceMetrics.WithLabelValues("tx_flush_fails").Set(float64(fm.Vsock.TxFlushFails))
-	vsockDeviceMetrics.WithLabelValues("tx_write_fails").Set(float64(fm.Vsock.TxWriteFails))
-	vsockDeviceMetrics.WithLabelValues("rx_read_fails").Set(float64(fm.Vsock.RxReadFails))
+	m.vsockDeviceMetrics.WithLabelValues("activate_fails").Set(float64(fm.Vsock.ActivateFails))
+	m.vsockDeviceMetrics.WithLabelValues("cfg_fails").Set(float64(fm.Vsock.CfgFails))
+	m.vsockDeviceMetrics.WithLabelValues("rx_queue_event_fails").Set(float64(fm.Vsock.RxQueueEventFails))
+	m.vsockDeviceMetrics.WithLabelValues("tx_queue_event_fails").Set(float64(fm.Vsock.TxQueueEventFails))
+	m.vsockDeviceMetrics.WithLabelValues("ev_queue_event_fails").Set(float64(fm.Vsock.EvQueueEventFails))
+	m.vsockDeviceMetrics.WithLabelValues("muxer_event_fails").Set(float64(fm.Vsock.MuxerEventFails))
+	m.vsockDeviceMetrics.WithLabelValues("conn_event_fails").Set(float64(fm.Vsock.ConnEventFails))
+	m.vsockDeviceMetrics.WithLabelValues("rx_queue_event_count").Set(float64(fm.Vsock.RxQueueEventCount))
+	m.vsockDeviceMetrics.WithLabelValues("tx_queue_event_count").Set(float64(fm.Vsock.TxQueueEventCount))
+	m.vsockDeviceMetrics.WithLabelValues("rx_bytes_count").Set(float64(fm.Vsock.RxBytesCount))
+	m.vsockDeviceMetrics.WithLabelValues("tx_bytes_count").Set(float64(fm.Vsock.TxBytesCount))
+	m.vsockDeviceMetrics.WithLabelValues("rx_packets_count").Set(float64(fm.Vsock.RxPacketsCount))
+	m.vsockDeviceMetrics.WithLabelValues("tx_packets_count").Set(float64(fm.Vsock.TxPacketsCount))
+	m.vsockDeviceMetrics.WithLabelValues("conns_added").Set(float64(fm.Vsock.ConnsAdded))
+	m.vsockDeviceMetrics.WithLabelValues("conns_killed").Set(float64(fm.Vsock.ConnsKilled))
+	m.vsockDeviceMetrics.WithLabelValues("conns_removed").Set(float64(fm.Vsock.ConnsRemoved))
+	m.vsockDeviceMetrics.WithLabelValues("killq_resync").Set(float64(fm.Vsock.KillqResync))
+	m.vsockDeviceMetrics.WithLabelValues("tx_flush_fails").Set(float64(fm.Vsock.TxFlushFails))
+	m.vsockDeviceMetrics.WithLabelValues("tx_write_fails").Set(float64(fm.Vsock.TxWriteFails))
+	m.vsockDeviceMetrics.WithLabelValues("rx_read_fails").Set(float64(fm.Vsock.RxReadFails))
 
 }
 
-//  Structure storing all metrics while enforcing serialization support on them.
+// Structure storing all metrics while enforcing serialization support on them.
 type FirecrackerMetrics struct {
 	// API Server related metrics.
 	APIServer APIServerMetrics `json:"api_server"`
@@ -390,7 +413,7 @@ type FirecrackerMetrics struct {
 	Vsock VsockDeviceMetrics `json:"vsock"`
 }
 
-//  API Server related metrics.
+// API Server related metrics.
 type APIServerMetrics struct {
 	// Measures the process's startup time in microseconds.
 	ProcessStartupTimeUs uint64 `json:"process_startup_time_us"`
@@ -402,7 +425,7 @@ type APIServerMetrics struct {
 	SyncVmmSendTimeoutCount uint64 `json:"sync_vmm_send_timeout_count"`
 }
 
-//  A block device's related metrics.
+// A block device's related metrics.
 type BlockDeviceMetrics struct {
 	// Number of times when activate failed on a block device.
 	ActivateFails uint64 `json:"activate_fails"`
@@ -438,7 +461,7 @@ type BlockDeviceMetrics struct {
 	RateLimiterThrottledEvents uint64 `json:"rate_limiter_throttled_events"`
 }
 
-//  Metrics related to API GET requests.
+// Metrics related to API GET requests.
 type GetRequestsMetrics struct {
 	// Number of GETs for getting information on the instance.
 	InstanceInfoCount uint64 `json:"instance_info_count"`
@@ -450,7 +473,7 @@ type GetRequestsMetrics struct {
 	MachineCfgFails uint64 `json:"machine_cfg_fails"`
 }
 
-//  Metrics related to the i8042 device.
+// Metrics related to the i8042 device.
 type I8042DeviceMetrics struct {
 	// Errors triggered while using the i8042 device.
 	ErrorCount uint64 `json:"error_count"`
@@ -466,7 +489,7 @@ type I8042DeviceMetrics struct {
 	WriteCount uint64 `json:"write_count"`
 }
 
-//  Metrics related to performance measurements.
+// Metrics related to performance measurements.
 type PerformanceMetrics struct {
 	// Measures the snapshot full create time, at the API (user) level, in microseconds.
 	FullCreateSnapshot uint64 `json:"full_create_snapshot"`
@@ -490,7 +513,7 @@ type PerformanceMetrics struct {
 	VmmResumeVM uint64 `json:"vmm_resume_vm"`
 }
 
-//  Logging related metrics.
+// Logging related metrics.
 type LoggerSystemMetrics struct {
 	// Number of misses on flushing metrics.
 	MissedMetricsCount uint64 `json:"missed_metrics_count"`
@@ -502,7 +525,7 @@ type LoggerSystemMetrics struct {
 	LogFails uint64 `json:"log_fails"`
 }
 
-//  Metrics specific to MMDS functionality.
+// Metrics specific to MMDS functionality.
 type MmdsMetrics struct {
 	// Number of frames rerouted to MMDS.
 	RxAccepted uint64 `json:"rx_accepted"`
@@ -528,7 +551,7 @@ type MmdsMetrics struct {
 	ConnectionsDestroyed uint64 `json:"connections_destroyed"`
 }
 
-//  A network device's related metrics.
+// A network device's related metrics.
 type NetDeviceMetrics struct {
 	// Number of times when activate failed on a network device.
 	ActivateFails uint64 `json:"activate_fails"`
@@ -585,7 +608,7 @@ type NetDeviceMetrics struct {
 	TxSpoofedMacCount uint64 `json:"tx_spoofed_mac_count"`
 }
 
-//  Metrics related to API PATCH requests.
+// Metrics related to API PATCH requests.
 type PatchRequestsMetrics struct {
 	// Number of tries to PATCH a block device.
 	DriveCount uint64 `json:"drive_count"`
@@ -601,7 +624,7 @@ type PatchRequestsMetrics struct {
 	MachineCfgFails uint64 `json:"machine_cfg_fails"`
 }
 
-//  Metrics related to API PUT requests.
+// Metrics related to API PUT requests.
 type PutRequestsMetrics struct {
 	// Number of PUTs triggering an action on the VM.
 	ActionsCount uint64 `json:"actions_count"`
@@ -633,7 +656,7 @@ type PutRequestsMetrics struct {
 	NetworkFails uint64 `json:"network_fails"`
 }
 
-//  Metrics related to the RTC device.
+// Metrics related to the RTC device.
 type RTCDeviceMetrics struct {
 	// Errors triggered while using the RTC device.
 	ErrorCount uint64 `json:"error_count"`
@@ -643,13 +666,13 @@ type RTCDeviceMetrics struct {
 	MissedWriteCount uint64 `json:"missed_write_count"`
 }
 
-//  Metrics related to seccomp filtering.
+// Metrics related to seccomp filtering.
 type SeccompMetrics struct {
 	// Number of errors inside the seccomp filtering.
 	NumFaults uint64 `json:"num_faults"`
 }
 
-//  Metrics related to a vcpu's functioning.
+// Metrics related to a vcpu's functioning.
 type VcpuMetrics struct {
 	// Number of KVM exits for handling input IO.
 	ExitIoIn uint64 `json:"exit_io_in"`
@@ -665,7 +688,7 @@ type VcpuMetrics struct {
 	FilterCPUid uint64 `json:"filter_cpuid"`
 }
 
-//  Metrics related to the virtual machine manager.
+// Metrics related to the virtual machine manager.
 type VmmMetrics struct {
 	// Number of device related events received for a VM.
 	DeviceEvents uint64 `json:"device_events"`
@@ -673,7 +696,7 @@ type VmmMetrics struct {
 	PanicCount uint64 `json:"panic_count"`
 }
 
-//  Metrics related to the UART device.
+// Metrics related to the UART device.
 type SerialDeviceMetrics struct {
 	// Errors triggered while using the UART device.
 	ErrorCount uint64 `json:"error_count"`
@@ -689,7 +712,7 @@ type SerialDeviceMetrics struct {
 	WriteCount uint64 `json:"write_count"`
 }
 
-//  Metrics related to signals.
+// Metrics related to signals.
 type SignalMetrics struct {
 	// Number of times that SIGBUS was handled.
 	Sigbus uint64 `json:"sigbus"`
@@ -697,7 +720,7 @@ type SignalMetrics struct {
 	Sigsegv uint64 `json:"sigsegv"`
 }
 
-//  Metrics related to virtio-vsockets.
+// Metrics related to virtio-vsockets.
 type VsockDeviceMetrics struct {
 	// Number of times when activate failed on a vsock device.
 	ActivateFails uint64 `json:"activate_fails"`
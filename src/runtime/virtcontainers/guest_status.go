@@ -0,0 +1,55 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"context"
+	"time"
+)
+
+// GuestStatus is the liveness of the guest of a sandbox, as reported by its
+// agent. The guest times are read from the guest clocks, so the status does
+// not rely on NTP running in the guest.
+type GuestStatus struct {
+	// BootTime is the boot time of the guest, from its realtime clock.
+	BootTime time.Time `json:"boot_time"`
+	// Uptime is the time elapsed since the boot of the guest.
+	Uptime time.Duration `json:"uptime"`
+	// ClockOffset is the offset of the guest realtime clock from the host
+	// one, positive when the guest is ahead.
+	ClockOffset  time.Duration `json:"clock_offset"`
+	AgentVersion string        `json:"agent_version"`
+	// LastHeartbeat is the time of the last successful check of the agent
+	// by the sandbox monitor, zero when the sandbox is not monitored.
+	LastHeartbeat time.Time `json:"last_heartbeat"`
+}
+
+// GetGuestStatus returns the liveness of the guest. The last heartbeat is
+// returned even when the agent fails to answer.
+func (s *Sandbox) GetGuestStatus(ctx context.Context) (GuestStatus, error) {
+	var status GuestStatus
+	if s.monitor != nil {
+		status.LastHeartbeat = s.monitor.heartbeat()
+	}
+
+	sent := time.Now()
+	resp, err := s.agent.getGuestStatus(ctx)
+	if err != nil {
+		return status, err
+	}
+	received := time.Now()
+
+	status.BootTime = time.Unix(resp.BootTime, 0)
+	status.Uptime = time.Duration(resp.UptimeMs) * time.Millisecond
+	status.AgentVersion = resp.AgentVersion
+	if resp.TimeNs != 0 {
+		// the guest clock is compared to the host one at the middle of
+		// the request
+		status.ClockOffset = time.Unix(0, resp.TimeNs).Sub(sent.Add(received.Sub(sent) / 2))
+	}
+
+	return status, nil
+}
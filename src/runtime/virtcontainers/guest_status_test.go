@@ -0,0 +1,36 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetGuestStatus(t *testing.T) {
+	assert := assert.New(t)
+
+	s := &Sandbox{
+		ctx:   context.Background(),
+		agent: &mockAgent{},
+	}
+
+	status, err := s.GetGuestStatus(s.ctx)
+	assert.NoError(err)
+	assert.True(status.LastHeartbeat.IsZero())
+	assert.Zero(status.ClockOffset)
+
+	heartbeat := time.Now()
+	s.monitor = newMonitor(s)
+	s.monitor.lastHeartbeat = heartbeat
+
+	status, err = s.GetGuestStatus(s.ctx)
+	assert.NoError(err)
+	assert.Equal(heartbeat, status.LastHeartbeat)
+}
@@ -0,0 +1,212 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	pkgUtils "github.com/kata-containers/kata-containers/src/runtime/pkg/utils"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/device/config"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// DefaultGuestSwapDir is the host directory of the guest swap files,
+	// the run storage is usually a tmpfs, i.e. host memory.
+	DefaultGuestSwapDir = "/var/lib/kata-containers/swap"
+
+	guestSwapImageSuffix = "-swap.img"
+
+	// file system type of the storages the agent enables as swap
+	guestSwapFsType = "swap"
+
+	// swappiness range of the Linux kernel
+	maxGuestSwappiness = 200
+)
+
+// GuestSwapConfig is the configuration of the swap device of the guest,
+// which lets the memory overcommitted pods survive their memory spikes.
+type GuestSwapConfig struct {
+	// SizeMB is the size of the swap device, the guest
+	// has no swap when zero.
+	SizeMB uint32
+
+	// MaxSizeMB is the maximum size of the swap device the pods can ask
+	// for, the default size when zero: the pods have no swap unless the
+	// host enables it.
+	MaxSizeMB uint32
+
+	// Swappiness is the vm.swappiness of the guest,
+	// the guest kernel default when zero.
+	Swappiness uint32
+
+	// Dir is the host directory the swap files are created in.
+	Dir string
+}
+
+func (c GuestSwapConfig) enabled() bool {
+	return c.SizeMB != 0
+}
+
+// Validate checks the guest swap configuration.
+func (c GuestSwapConfig) Validate() error {
+	if c.Swappiness > maxGuestSwappiness {
+		return fmt.Errorf("guest swappiness %d out of [0, %d]", c.Swappiness, maxGuestSwappiness)
+	}
+
+	if c.Dir != "" && !filepath.IsAbs(c.Dir) {
+		return fmt.Errorf("guest swap directory %q is not an absolute path", c.Dir)
+	}
+
+	if c.SizeMB > c.maxSizeMB() {
+		return fmt.Errorf("guest swap size %d MiB exceeds the maximum size %d MiB", c.SizeMB, c.MaxSizeMB)
+	}
+
+	return nil
+}
+
+// maxSizeMB returns the maximum size of the swap device the pods can ask for.
+func (c GuestSwapConfig) maxSizeMB() uint32 {
+	if c.MaxSizeMB == 0 {
+		return c.SizeMB
+	}
+	return c.MaxSizeMB
+}
+
+// CheckSize checks the size of the swap device a pod asks for, zero
+// disabling the swap.
+func (c GuestSwapConfig) CheckSize(sizeMB uint64) error {
+	if sizeMB > uint64(c.maxSizeMB()) {
+		return fmt.Errorf("guest swap size %d MiB exceeds the maximum size %d MiB", sizeMB, c.maxSizeMB())
+	}
+	return nil
+}
+
+func guestSwapImagePath(c GuestSwapConfig, sandboxID string) string {
+	dir := c.Dir
+	if dir == "" {
+		dir = DefaultGuestSwapDir
+	}
+
+	return filepath.Join(dir, sandboxID+guestSwapImageSuffix)
+}
+
+// createGuestSwap creates the swap file of a sandbox, fully allocated so
+// that the guest can't run out of host disk space while swapping, and
+// returns the path of its loop device.
+func createGuestSwap(c GuestSwapConfig, sandboxID string) (string, error) {
+	image := guestSwapImagePath(c, sandboxID)
+	if err := os.MkdirAll(filepath.Dir(image), DirMode); err != nil {
+		return "", err
+	}
+
+	f, err := os.OpenFile(image, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return "", err
+	}
+	err = unix.Fallocate(int(f.Fd()), 0, 0, int64(c.SizeMB)<<20)
+	f.Close()
+	if err != nil {
+		os.Remove(image)
+		return "", fmt.Errorf("could not allocate guest swap file %q: %v", image, err)
+	}
+
+	if _, err := pkgUtils.RunHostCommand("mkswap", image); err != nil {
+		os.Remove(image)
+		return "", err
+	}
+
+	path, err := pkgUtils.RunHostCommand("losetup", "--find", "--show", image)
+	if err != nil {
+		os.Remove(image)
+		return "", err
+	}
+
+	return path, nil
+}
+
+// removeGuestSwap removes the swap file of a sandbox, if any.
+func removeGuestSwap(c GuestSwapConfig, sandboxID string) error {
+	image := guestSwapImagePath(c, sandboxID)
+	if _, err := os.Stat(image); os.IsNotExist(err) {
+		return nil
+	}
+
+	out, err := pkgUtils.RunHostCommand("losetup", "--noheadings", "--output", "NAME", "--associated", image)
+	if err != nil {
+		return err
+	}
+	for _, loop := range strings.Fields(out) {
+		if _, err := pkgUtils.RunHostCommand("losetup", "--detach", loop); err != nil {
+			return err
+		}
+	}
+
+	return os.Remove(image)
+}
+
+// setupGuestSwap creates the swap device of the sandbox and attaches it to
+// the VM, the agent enables it when the sandbox is started.
+func (s *Sandbox) setupGuestSwap(ctx context.Context) error {
+	c := s.config.GuestSwapConfig
+	if !c.enabled() {
+		return nil
+	}
+
+	if s.config.HypervisorConfig.DisableBlockDeviceUse {
+		return fmt.Errorf("guest swap requires block device support")
+	}
+
+	path, err := createGuestSwap(c, s.id)
+	if err != nil {
+		return err
+	}
+
+	var stat unix.Stat_t
+	if err := unix.Stat(path, &stat); err != nil {
+		return fmt.Errorf("stat %q failed: %v", path, err)
+	}
+
+	b, err := s.devManager.NewDevice(config.DeviceInfo{
+		HostPath: path,
+		DevType:  "b",
+		Major:    int64(unix.Major(stat.Rdev)),
+		Minor:    int64(unix.Minor(stat.Rdev)),
+	})
+	if err != nil {
+		return fmt.Errorf("device manager failed to create guest swap device for %q: %v", path, err)
+	}
+
+	if err := s.devManager.AttachDevice(ctx, b.DeviceID(), s); err != nil {
+		return err
+	}
+	s.state.SandboxDevices = append(s.state.SandboxDevices, b.DeviceID())
+
+	drive, ok := b.GetDeviceInfo().(*config.BlockDrive)
+	if !ok || drive == nil {
+		return fmt.Errorf("guest swap device %q is not a block drive", b.DeviceID())
+	}
+
+	storage, err := newBlockDriveStorage(s.config.HypervisorConfig.BlockDeviceDriver, drive)
+	if err != nil {
+		return err
+	}
+	// the device is not mounted, the agent enables it with swapon
+	storage.Fstype = guestSwapFsType
+	if c.Swappiness != 0 {
+		storage.DriverOptions = []string{fmt.Sprintf("swappiness=%d", c.Swappiness)}
+	}
+
+	s.guestSwap = storage
+
+	s.Logger().WithField("device", path).WithField("size-mb", c.SizeMB).Info("guest swap attached")
+
+	return nil
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGuestSwapConfigValidate(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.NoError(GuestSwapConfig{}.Validate())
+	assert.NoError(GuestSwapConfig{SizeMB: 1024, Swappiness: 200, Dir: "/var/lib/swap"}.Validate())
+
+	assert.Error(GuestSwapConfig{SizeMB: 1024, Swappiness: 201}.Validate())
+	assert.Error(GuestSwapConfig{SizeMB: 1024, Dir: "swap"}.Validate())
+	assert.Error(GuestSwapConfig{SizeMB: 1024, MaxSizeMB: 512}.Validate())
+}
+
+func TestGuestSwapConfigCheckSize(t *testing.T) {
+	assert := assert.New(t)
+
+	// the pods have no swap unless the host enables it
+	assert.NoError(GuestSwapConfig{}.CheckSize(0))
+	assert.Error(GuestSwapConfig{}.CheckSize(1024))
+
+	c := GuestSwapConfig{SizeMB: 1024}
+	assert.NoError(c.CheckSize(0))
+	assert.NoError(c.CheckSize(1024))
+	assert.Error(c.CheckSize(2048))
+
+	c.MaxSizeMB = 4096
+	assert.NoError(c.CheckSize(4096))
+	assert.Error(c.CheckSize(4097))
+	assert.Error(c.CheckSize(1<<32 + 1024))
+}
+
+func TestGuestSwapImagePath(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(filepath.Join(DefaultGuestSwapDir, "sid-swap.img"), guestSwapImagePath(GuestSwapConfig{}, "sid"))
+	assert.Equal("/var/lib/swap/sid-swap.img", guestSwapImagePath(GuestSwapConfig{Dir: "/var/lib/swap"}, "sid"))
+}
+
+func TestGuestSwap(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "guest-swap")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	commands, restore := mockHostCommands(hostCommandOutputs(map[string]string{"losetup": "/dev/loop7"}))
+	defer restore()
+
+	c := GuestSwapConfig{SizeMB: 16, Dir: dir}
+	image := filepath.Join(dir, "sid"+guestSwapImageSuffix)
+
+	path, err := createGuestSwap(c, "sid")
+	assert.NoError(err)
+	assert.Equal("/dev/loop7", path)
+	assert.Equal([]string{
+		"mkswap " + image,
+		"losetup --find --show " + image,
+	}, *commands)
+
+	info, err := os.Stat(image)
+	assert.NoError(err)
+	assert.Equal(int64(16<<20), info.Size())
+
+	// the swap file exists already
+	_, err = createGuestSwap(c, "sid")
+	assert.Error(err)
+
+	*commands = nil
+	assert.NoError(removeGuestSwap(c, "sid"))
+	assert.Equal([]string{
+		"losetup --noheadings --output NAME --associated " + image,
+		"losetup --detach /dev/loop7",
+	}, *commands)
+	_, err = os.Stat(image)
+	assert.True(os.IsNotExist(err))
+
+	// nothing to remove
+	*commands = nil
+	assert.NoError(removeGuestSwap(c, "sid"))
+	assert.Empty(*commands)
+}
@@ -0,0 +1,178 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"fmt"
+	"hash/fnv"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// the lock file serializing the allocation of the IDs of a directory
+const hostIDsLockFile = ".lock"
+
+// The host directories of the user and group IDs allocated to the sandbox
+// processes, one file per ID holding the sandbox ID, variables to be
+// changed in the tests.
+var (
+	// virtiofsdIDsDir is the directory of the IDs of the virtiofsd daemons.
+	virtiofsdIDsDir = "/run/kata-containers/virtiofsd-ids"
+
+	// hypervisorIDsDir is the directory of the IDs of the hypervisors.
+	hypervisorIDsDir = "/run/kata-containers/hypervisor-ids"
+)
+
+// idRange is a range of user and group IDs.
+type idRange struct {
+	first uint32
+	last  uint32
+}
+
+// parseIDRange parses a range of IDs, <first>-<last>, excluding root.
+func parseIDRange(s string) (idRange, error) {
+	bounds := strings.SplitN(s, "-", 2)
+	if len(bounds) != 2 {
+		return idRange{}, fmt.Errorf("invalid ID range %q, expecting <first>-<last>", s)
+	}
+
+	first, err := strconv.ParseUint(strings.TrimSpace(bounds[0]), 10, 32)
+	if err != nil {
+		return idRange{}, fmt.Errorf("invalid ID range %q: %v", s, err)
+	}
+	last, err := strconv.ParseUint(strings.TrimSpace(bounds[1]), 10, 32)
+	if err != nil {
+		return idRange{}, fmt.Errorf("invalid ID range %q: %v", s, err)
+	}
+
+	// the last ID, -1, is the invalid uid_t
+	if first == 0 || last < first || last == math.MaxUint32 {
+		return idRange{}, fmt.Errorf("invalid ID range %q", s)
+	}
+
+	return idRange{first: uint32(first), last: uint32(last)}, nil
+}
+
+// lockHostIDs takes the lock of the IDs allocated in dir, to be released by
+// closing the returned file.
+func lockHostIDs(dir string) (*os.File, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+
+	lock, err := os.OpenFile(filepath.Join(dir, hostIDsLockFile), os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := unix.Flock(int(lock.Fd()), unix.LOCK_EX); err != nil {
+		lock.Close()
+		return nil, err
+	}
+
+	return lock, nil
+}
+
+// allocatedHostIDs returns the IDs allocated in dir and their sandboxes.
+func allocatedHostIDs(dir string) (map[uint32]string, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make(map[uint32]string)
+	for _, f := range files {
+		id, err := strconv.ParseUint(f.Name(), 10, 32)
+		if err != nil {
+			continue
+		}
+
+		sandboxID, err := ioutil.ReadFile(filepath.Join(dir, f.Name()))
+		if err != nil {
+			return nil, err
+		}
+		ids[uint32(id)] = string(sandboxID)
+	}
+
+	return ids, nil
+}
+
+// allocateHostID allocates in dir the user and group ID of a process of a
+// sandbox in a range, returning the ID already allocated to the sandbox, if
+// any, e.g. when the shim is restarted.
+func allocateHostID(dir string, r idRange, sandboxID string) (uint32, error) {
+	lock, err := lockHostIDs(dir)
+	if err != nil {
+		return 0, err
+	}
+	defer lock.Close()
+
+	allocated, err := allocatedHostIDs(dir)
+	if err != nil {
+		return 0, err
+	}
+
+	for id, s := range allocated {
+		if s == sandboxID {
+			return id, nil
+		}
+	}
+
+	// start from a hash of the sandbox ID, to find a free ID quickly
+	size := uint64(r.last-r.first) + 1
+	h := fnv.New64a()
+	h.Write([]byte(sandboxID))
+	start := h.Sum64() % size
+
+	for i := uint64(0); i < size; i++ {
+		id := r.first + uint32((start+i)%size)
+		if _, ok := allocated[id]; ok {
+			continue
+		}
+
+		f, err := os.OpenFile(filepath.Join(dir, strconv.FormatUint(uint64(id), 10)), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if err != nil {
+			return 0, err
+		}
+		_, err = f.WriteString(sandboxID)
+		f.Close()
+		if err != nil {
+			return 0, err
+		}
+
+		return id, nil
+	}
+
+	return 0, fmt.Errorf("no free ID in %d-%d", r.first, r.last)
+}
+
+// releaseHostID releases the ID allocated in dir to a sandbox.
+func releaseHostID(dir string, sandboxID string) error {
+	lock, err := lockHostIDs(dir)
+	if err != nil {
+		return err
+	}
+	defer lock.Close()
+
+	allocated, err := allocatedHostIDs(dir)
+	if err != nil {
+		return err
+	}
+
+	for id, s := range allocated {
+		if s == sandboxID {
+			return os.Remove(filepath.Join(dir, strconv.FormatUint(uint64(id), 10)))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseIDRange(t *testing.T) {
+	assert := assert.New(t)
+
+	r, err := parseIDRange("3000000000-3000065535")
+	assert.NoError(err)
+	assert.Equal(idRange{first: 3000000000, last: 3000065535}, r)
+
+	r, err = parseIDRange("1000-1000")
+	assert.NoError(err)
+	assert.Equal(idRange{first: 1000, last: 1000}, r)
+
+	for _, s := range []string{
+		"",
+		"1000",
+		"0-1000",
+		"2000-1000",
+		"1000-4294967295",
+		"a-1000",
+		"1000-4294967296",
+	} {
+		_, err := parseIDRange(s)
+		assert.Error(err, s)
+	}
+}
+
+func TestAllocateHostID(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "host-ids")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	r := idRange{first: 1000, last: 1001}
+
+	id1, err := allocateHostID(dir, r, "sandbox1")
+	assert.NoError(err)
+	assert.True(id1 >= r.first && id1 <= r.last)
+
+	// the ID of a sandbox is reused
+	id, err := allocateHostID(dir, r, "sandbox1")
+	assert.NoError(err)
+	assert.Equal(id1, id)
+
+	id2, err := allocateHostID(dir, r, "sandbox2")
+	assert.NoError(err)
+	assert.NotEqual(id1, id2)
+	assert.True(id2 >= r.first && id2 <= r.last)
+
+	_, err = allocateHostID(dir, r, "sandbox3")
+	assert.Error(err)
+
+	assert.NoError(releaseHostID(dir, "sandbox1"))
+	id, err = allocateHostID(dir, r, "sandbox3")
+	assert.NoError(err)
+	assert.Equal(id1, id)
+
+	// releasing a sandbox without ID is fine
+	assert.NoError(releaseHostID(dir, "sandbox1"))
+}
@@ -0,0 +1,135 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/containerd/cgroups"
+	vccgroups "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/cgroups"
+	"github.com/prometheus/procfs"
+)
+
+// the limit of a hugetlb cgroup without limit is close to MaxInt64
+const hugetlbUnlimited = 1 << 62
+
+// hugetlbCgroupRoot returns the root of the hugetlb cgroup hierarchy and the
+// suffix of the limit files, hugetlb.<page size>.<suffix>.
+var hugetlbCgroupRoot = func() (string, string) {
+	if cgroups.Mode() == cgroups.Unified {
+		return "/sys/fs/cgroup", "max"
+	}
+	return "/sys/fs/cgroup/hugetlb", "limit_in_bytes"
+}
+
+// podHugepages returns the hugepages of a pod, in bytes by page size, from
+// the hugetlb limits set by the kubelet on the pod cgroup, the parent of the
+// sandbox cgroup. The page sizes without limit are left out.
+func podHugepages(cgroupsPath string) (map[uint64]uint64, error) {
+	path, err := vccgroups.CgroupfsPath(cgroupsPath)
+	if err != nil {
+		return nil, err
+	}
+	// a relative path is not under a pod cgroup
+	if !filepath.IsAbs(path) {
+		return nil, nil
+	}
+
+	root, suffix := hugetlbCgroupRoot()
+	files, err := filepath.Glob(filepath.Join(root, filepath.Dir(path), "hugetlb.*."+suffix))
+	if err != nil {
+		return nil, err
+	}
+
+	hugepages := make(map[uint64]uint64)
+	for _, file := range files {
+		pageSize, err := parseHugepageSize(strings.Split(filepath.Base(file), ".")[1])
+		if err != nil {
+			return nil, err
+		}
+
+		content, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		value := strings.TrimSpace(string(content))
+		if value == "max" {
+			continue
+		}
+		limit, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid hugetlb limit in %s: %v", file, err)
+		}
+		if limit == 0 || limit >= hugetlbUnlimited {
+			continue
+		}
+
+		hugepages[pageSize] = limit
+	}
+
+	if len(hugepages) == 0 {
+		return nil, nil
+	}
+	return hugepages, nil
+}
+
+// hostHugetlbfsMount returns a hugetlbfs mount point of the host for the
+// page size, in bytes.
+var hostHugetlbfsMount = func(pageSize uint64) (string, error) {
+	mounts, err := procfs.GetMounts()
+	if err != nil {
+		return "", err
+	}
+
+	fs, err := procfs.NewDefaultFS()
+	if err != nil {
+		return "", err
+	}
+	mi, err := fs.Meminfo()
+	if err != nil {
+		return "", err
+	}
+
+	for _, m := range mounts {
+		if m.FSType != hugetlbfsType {
+			continue
+		}
+
+		// the mounts of the default page size may not have the option
+		size := uint64(0)
+		if mi.Hugepagesize != nil {
+			size = *mi.Hugepagesize << 10
+		}
+		if opt, ok := m.SuperOptions["pagesize"]; ok {
+			if size, err = parseHugepageSize(opt); err != nil {
+				continue
+			}
+		}
+
+		if size == pageSize {
+			return m.MountPoint, nil
+		}
+	}
+
+	return "", fmt.Errorf("no hugetlbfs mounted for the %d bytes pages", pageSize)
+}
+
+// hugepageSizeParam returns the size of a hugepage as a kernel parameter
+// value, e.g. 2M or 1G.
+func hugepageSizeParam(pageSize uint64) string {
+	if pageSize >= 1<<30 && pageSize%(1<<30) == 0 {
+		return fmt.Sprintf("%dG", pageSize>>30)
+	}
+	if pageSize >= 1<<20 && pageSize%(1<<20) == 0 {
+		return fmt.Sprintf("%dM", pageSize>>20)
+	}
+	return fmt.Sprintf("%dK", pageSize>>10)
+}
@@ -0,0 +1,105 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPodHugepages(t *testing.T) {
+	assert := assert.New(t)
+
+	root, err := ioutil.TempDir("", "hugetlb")
+	assert.NoError(err)
+	defer os.RemoveAll(root)
+
+	saved := hugetlbCgroupRoot
+	hugetlbCgroupRoot = func() (string, string) {
+		return root, "limit_in_bytes"
+	}
+	defer func() {
+		hugetlbCgroupRoot = saved
+	}()
+
+	pod := filepath.Join(root, "kubepods", "podabc")
+	assert.NoError(os.MkdirAll(pod, 0755))
+	for name, value := range map[string]string{
+		"hugetlb.2MB.limit_in_bytes":  "536870912\n",
+		"hugetlb.1GB.limit_in_bytes":  "0\n",
+		"hugetlb.64KB.limit_in_bytes": "9223372036854710272\n",
+	} {
+		assert.NoError(ioutil.WriteFile(filepath.Join(pod, name), []byte(value), 0644))
+	}
+
+	hugepages, err := podHugepages("/kubepods/podabc/sandbox")
+	assert.NoError(err)
+	assert.Equal(map[uint64]uint64{2 << 20: 512 << 20}, hugepages)
+
+	// no pod cgroup
+	hugepages, err = podHugepages("/kubepods/poddef/sandbox")
+	assert.NoError(err)
+	assert.Nil(hugepages)
+
+	hugepages, err = podHugepages("sandbox")
+	assert.NoError(err)
+	assert.Nil(hugepages)
+
+	assert.NoError(ioutil.WriteFile(filepath.Join(pod, "hugetlb.2MB.limit_in_bytes"), []byte("foo"), 0644))
+	_, err = podHugepages("/kubepods/podabc/sandbox")
+	assert.Error(err)
+}
+
+func TestHugepageSizeParam(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("2M", hugepageSizeParam(2<<20))
+	assert.Equal("1G", hugepageSizeParam(1<<30))
+	assert.Equal("64K", hugepageSizeParam(64<<10))
+	assert.Equal("512M", hugepageSizeParam(512<<20))
+}
+
+func TestQemuGuestHugepages(t *testing.T) {
+	assert := assert.New(t)
+
+	saved := hostHugetlbfsMount
+	hostHugetlbfsMount = func(pageSize uint64) (string, error) {
+		return "/dev/hugepages-" + hugepageSizeParam(pageSize), nil
+	}
+	defer func() {
+		hostHugetlbfsMount = saved
+	}()
+
+	q := &qemu{
+		config: HypervisorConfig{
+			GuestHugepages: map[uint64]uint64{1 << 30: 2 << 30, 2 << 20: 512 << 20},
+		},
+	}
+
+	assert.Equal([]Param{{"hugepagesz", "2M"}, {"hugepagesz", "1G"}}, q.hugepagesKernelParameters())
+
+	devices, err := q.appendGuestHugepages(nil)
+	assert.NoError(err)
+	assert.Len(devices, 2)
+	assert.Equal([]string{
+		"-object", "memory-backend-file,id=hugemem-2M,mem-path=/dev/hugepages-2M,size=536870912,prealloc=on,share=on",
+		"-device", "pc-dimm,id=hugedimm-2M,memdev=hugemem-2M",
+	}, devices[0].QemuParams(nil))
+	assert.Equal([]string{
+		"-object", "memory-backend-file,id=hugemem-1G,mem-path=/dev/hugepages-1G,size=2147483648,prealloc=on,share=on",
+		"-device", "pc-dimm,id=hugedimm-1G,memdev=hugemem-1G",
+	}, devices[1].QemuParams(nil))
+
+	q.config.GuestHugepages = nil
+	assert.Empty(q.hugepagesKernelParameters())
+	devices, err = q.appendGuestHugepages(nil)
+	assert.NoError(err)
+	assert.Empty(devices)
+}
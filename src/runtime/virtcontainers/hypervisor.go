@@ -17,6 +17,7 @@ import (
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/device/config"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/persist"
 	persistapi "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/persist/api"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/vmmsandbox"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/types"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/utils"
 )
@@ -135,6 +136,13 @@ const (
 	// hybridVirtioVsockDev is a hybrid virtio-vsock device supported
 	// only on certain hypervisors, like firecracker.
 	hybridVirtioVsockDev
+
+	// fwCfgDev is a firmware configuration blob, supported only by qemu.
+	fwCfgDev
+
+	// sharedMemoryDev is a memory region shared with other VMs,
+	// supported only by qemu.
+	sharedMemoryDev
 )
 
 type memoryDevice struct {
@@ -264,6 +272,21 @@ type HypervisorConfig struct {
 	// ImagePath and InitrdPath cannot be set at the same time.
 	InitrdPath string
 
+	// RootfsDiskPath is the host path of a pre-built guest rootfs disk.
+	// The guest boots from it rather than from the image or the initrd,
+	// its writes go to a transient overlay discarded with the VM.
+	RootfsDiskPath string
+
+	// RootfsDiskFormat is the format of the rootfs disk, raw or qcow2.
+	RootfsDiskFormat string
+
+	// RootfsDiskOverlayDir is the host directory the overlays of the
+	// rootfs disk are created in. It must be on a disk, not in a tmpfs.
+	RootfsDiskOverlayDir string
+
+	// RootfsDiskList is the list of rootfs disks allowed in annotations.
+	RootfsDiskList []string
+
 	// FirmwarePath is the bios host path
 	FirmwarePath string
 
@@ -273,6 +296,9 @@ type HypervisorConfig struct {
 	// CPUFeatures are cpu specific features
 	CPUFeatures string
 
+	// CPUModel is the guest cpu model, the host cpu model is used when empty
+	CPUModel string
+
 	// HypervisorPath is the hypervisor executable host path.
 	HypervisorPath string
 
@@ -334,6 +360,11 @@ type HypervisorConfig struct {
 	// VirtioFSExtraArgs passes options to virtiofsd daemon
 	VirtioFSExtraArgs []string
 
+	// VirtioFSDaemonIDs is the range of the user and group IDs allocated to
+	// the virtiofsd daemons, <first>-<last>, each daemon being run with its
+	// own IDs. The daemons are run as root if empty.
+	VirtioFSDaemonIDs string
+
 	// Enable annotations by name
 	EnableAnnotations []string
 
@@ -346,6 +377,10 @@ type HypervisorConfig struct {
 	// VhostUserStorePathList is the list of valid values for vhost-user paths
 	VhostUserStorePathList []string
 
+	// VhostUserSocketList is the list of valid values for the vhost-user
+	// sockets of the static network interfaces
+	VhostUserSocketList []string
+
 	// customAssets is a map of assets.
 	// Each value in that map takes precedence over the configured assets.
 	// For example, if there is a value for the "kernel" key in this map,
@@ -366,6 +401,10 @@ type HypervisorConfig struct {
 	// DisableBlockDeviceUse disallows a block device from being used.
 	DisableBlockDeviceUse bool
 
+	// LazyBlockDeviceAttach defers the hotplug of the containers rootfs
+	// block device, and their creation in the guest, until they start.
+	LazyBlockDeviceAttach bool
+
 	// EnableIOThreads enables IO to be processed in a separate thread.
 	// Supported currently for virtio-scsi driver.
 	EnableIOThreads bool
@@ -404,6 +443,26 @@ type HypervisorConfig struct {
 	// DisableImageNvdimm is used to disable guest rootfs image nvdimm devices
 	DisableImageNvdimm bool
 
+	// GICVersion is the interrupt controller version of the
+	// arm64 virt machine type, the host one when empty.
+	GICVersion string
+
+	// DisableITS disables the interrupt translation service
+	// of the arm64 virt machine type.
+	DisableITS bool
+
+	// SMTMode is the number of threads per core of the
+	// ppc64le pseries machine type, 1 when unset.
+	SMTMode uint32
+
+	// MMUMode is the MMU mode of the ppc64le guests, radix or hash,
+	// the guest kernel chooses it when empty.
+	MMUMode string
+
+	// MemoryBlockSizeMB is the size, in MiB, of the memory blocks
+	// hotplugged in the ppc64le guests, a multiple of 256.
+	MemoryBlockSizeMB uint32
+
 	// HotplugVFIOOnRootBus is used to indicate if devices need to be hotplugged on the
 	// root bus instead of a bridge.
 	HotplugVFIOOnRootBus bool
@@ -417,6 +476,11 @@ type HypervisorConfig struct {
 	// from memory encryption to both memory and CPU-state encryption and integrity.
 	ConfidentialGuest bool
 
+	// SEHostKeyDocuments are the host key documents the Secure Execution
+	// boot image of the confidential guests is built for, on s390x. The
+	// kernel is expected to be a pre-built boot image when empty.
+	SEHostKeyDocuments []string
+
 	// BootToBeTemplate used to indicate if the VM is created to be a template VM
 	BootToBeTemplate bool
 
@@ -446,10 +510,60 @@ type HypervisorConfig struct {
 	// SELinux label for the VM
 	SELinuxProcessLabel string
 
+	// SELinuxFileLabel is the SELinux label of the sandbox files
+	// the hypervisor accesses, set when SELinuxCategories is enabled.
+	SELinuxFileLabel string
+
+	// SELinuxCategories gives the hypervisor process and the sandbox
+	// files an MCS category pair unique to the VM (sVirt).
+	SELinuxCategories bool
+
+	// VMMSandboxing launches the hypervisor in new namespaces, with
+	// no new privileges, no capabilities and a seccomp allow-list of
+	// syscalls.
+	VMMSandboxing bool
+
+	// VMMSandboxingSyscalls are allowed to the hypervisor on top of
+	// the default VMM sandboxing allow-list.
+	VMMSandboxingSyscalls []string
+
+	// VMMSandboxingCapabilities are kept by the sandboxed hypervisor,
+	// e.g. CAP_IPC_LOCK.
+	VMMSandboxingCapabilities []string
+
+	// HypervisorIDs is the range of the user and group IDs allocated to
+	// the hypervisors, <first>-<last>, each hypervisor being run with its
+	// own IDs. The hypervisors are run as root if empty.
+	HypervisorIDs string
+
+	// VMMOOMScoreAdj is the oom_score_adj of the hypervisor processes,
+	// they keep the one of the runtime when 0.
+	VMMOOMScoreAdj int
+
+	// VMMNice is the nice value of the hypervisor processes.
+	VMMNice int
+
+	// VMMIOClass and VMMIOPriority are the I/O scheduling class and
+	// priority of the hypervisor processes, as set by ionice.
+	VMMIOClass    string
+	VMMIOPriority uint32
+
+	// VCPURealtimePriority is the SCHED_FIFO priority of the vCPU
+	// threads, they are not realtime when 0.
+	VCPURealtimePriority uint32
+
 	// SGXEPCSize specifies the size in bytes for the EPC Section.
 	// Enable SGX. Hardware-based isolation and memory encryption.
 	SGXEPCSize int64
 
+	// NUMANodes is the guest NUMA topology, the VM has a single node when
+	// empty.
+	NUMANodes []NUMANode
+
+	// GuestHugepages are the hugepages of the pod, in bytes by page size,
+	// backed by host hugepages of the same size and allocated in the guest.
+	GuestHugepages map[uint64]uint64
+
 	// RxRateLimiterMaxRate is used to control network I/O inbound bandwidth on VM level.
 	RxRateLimiterMaxRate uint64
 
@@ -460,11 +574,72 @@ type HypervisorConfig struct {
 	MemOffset uint64
 }
 
+// NUMANode is a node of the guest NUMA topology.
+type NUMANode struct {
+	// VCPUs are the vCPUs of the node, the hotplugged ones included.
+	VCPUs []uint32
+
+	// MemorySize is the boot memory of the node in MiB.
+	MemorySize uint32
+}
+
 // vcpu mapping from vcpu number to thread number
 type vcpuThreadIDs struct {
 	vcpus map[int]int
 }
 
+// checkNUMAConfig checks the guest NUMA topology: the boot memory is split
+// between the nodes and the vCPUs are numbered from 0. The vCPUs of the nodes
+// are the ones that can be hotplugged, so that they are placed in a node.
+func (conf *HypervisorConfig) checkNUMAConfig() error {
+	if len(conf.NUMANodes) == 0 {
+		return nil
+	}
+
+	var memory uint32
+	vcpus := make(map[uint32]int)
+
+	for i, node := range conf.NUMANodes {
+		if node.MemorySize == 0 {
+			return fmt.Errorf("NUMA node %d has no memory", i)
+		}
+		memory += node.MemorySize
+
+		if len(node.VCPUs) == 0 {
+			return fmt.Errorf("NUMA node %d has no vCPU", i)
+		}
+		for _, vcpu := range node.VCPUs {
+			if n, found := vcpus[vcpu]; found {
+				return fmt.Errorf("vCPU %d is in NUMA nodes %d and %d", vcpu, n, i)
+			}
+			vcpus[vcpu] = i
+		}
+	}
+
+	if memory != conf.MemorySize {
+		return fmt.Errorf("memory of the NUMA nodes (%d MiB) differs from the VM memory (%d MiB)", memory, conf.MemorySize)
+	}
+
+	maxVCPUs := uint32(len(vcpus))
+	for vcpu := uint32(0); vcpu < maxVCPUs; vcpu++ {
+		if _, found := vcpus[vcpu]; !found {
+			return fmt.Errorf("vCPU %d is not in a NUMA node", vcpu)
+		}
+	}
+
+	if maxVCPUs < conf.NumVCPUs {
+		return fmt.Errorf("%d vCPUs in the NUMA nodes, less than the %d vCPUs of the VM", maxVCPUs, conf.NumVCPUs)
+	}
+	if maxVCPUs > conf.DefaultMaxVCPUs {
+		return fmt.Errorf("%d vCPUs in the NUMA nodes, more than the maximum of %d vCPUs", maxVCPUs, conf.DefaultMaxVCPUs)
+	}
+
+	// the vCPUs out of the nodes are not hotplugged
+	conf.DefaultMaxVCPUs = maxVCPUs
+
+	return nil
+}
+
 func (conf *HypervisorConfig) checkTemplateConfig() error {
 	if conf.BootToBeTemplate && conf.BootFromTemplate {
 		return fmt.Errorf("Cannot set both 'to be' and 'from' vm tempate")
@@ -488,14 +663,45 @@ func (conf *HypervisorConfig) valid() error {
 		return fmt.Errorf("Missing kernel path")
 	}
 
-	if conf.ImagePath == "" && conf.InitrdPath == "" {
+	if conf.ImagePath == "" && conf.InitrdPath == "" && conf.RootfsDiskPath == "" {
 		return fmt.Errorf("Missing image and initrd path")
 	}
 
+	switch conf.RootfsDiskFormat {
+	case "", rootfsDiskFormatRaw, rootfsDiskFormatQcow2:
+	default:
+		return fmt.Errorf("Unsupported rootfs disk format %q", conf.RootfsDiskFormat)
+	}
+
+	if conf.RootfsDiskPath != "" && conf.RootfsDiskOverlayDir == "" {
+		return fmt.Errorf("Missing rootfs disk overlay directory")
+	}
+
 	if err := conf.checkTemplateConfig(); err != nil {
 		return err
 	}
 
+	if conf.VirtioFSDaemonIDs != "" {
+		if _, err := parseIDRange(conf.VirtioFSDaemonIDs); err != nil {
+			return err
+		}
+	}
+
+	if conf.HypervisorIDs != "" {
+		if _, err := parseIDRange(conf.HypervisorIDs); err != nil {
+			return err
+		}
+
+		// the hypervisor can't access the files of the containers
+		// nor the templates of the VM factory
+		if conf.SharedFS == config.Virtio9P {
+			return fmt.Errorf("virtio-9p is not supported with hypervisor IDs")
+		}
+		if conf.BootToBeTemplate || conf.BootFromTemplate {
+			return fmt.Errorf("VM templating is not supported with hypervisor IDs")
+		}
+	}
+
 	if conf.NumVCPUs == 0 {
 		conf.NumVCPUs = defaultVCPUs
 	}
@@ -522,6 +728,14 @@ func (conf *HypervisorConfig) valid() error {
 		conf.Msize9p = defaultMsize9p
 	}
 
+	if err := conf.checkNUMAConfig(); err != nil {
+		return err
+	}
+
+	if err := conf.checkVMMPriority(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -646,6 +860,43 @@ func (conf *HypervisorConfig) FirmwareAssetPath() (string, error) {
 	return conf.assetPath(types.FirmwareAsset)
 }
 
+// vmmLauncher returns the path to execute in place of the hypervisor
+// binary path, which launches it in the sandboxing profile if enabled and
+// with the credential cred if not nil. The profile is stored in the VM
+// directory vmPath.
+func (conf *HypervisorConfig) vmmLauncher(vmPath, path string, cred *vmmsandbox.Credential) (string, error) {
+	if !conf.VMMSandboxing && cred == nil {
+		return path, nil
+	}
+
+	p := vmmsandbox.Profile{Path: path}
+	if conf.VMMSandboxing {
+		p = vmmsandbox.NewProfile(path, conf.VMMSandboxingSyscalls, conf.VMMSandboxingCapabilities)
+	}
+
+	if cred != nil {
+		p.Credential = cred
+		p.NoNewPrivileges = true
+
+		// the hypervisor can't create its memory files in the
+		// hugetlbfs of the host, owned by root
+		if conf.HugePages {
+			p.HugePagesDir = hugePagesDir
+			if !p.HasNamespace("mount") {
+				p.Namespaces = append(p.Namespaces, "mount")
+			}
+		}
+	}
+
+	return vmmsandbox.Prepare(vmPath, p)
+}
+
+// CheckVMMSandboxing verifies that the hypervisor sandboxing
+// profile of the configuration can be applied on the host.
+func CheckVMMSandboxing(conf HypervisorConfig) error {
+	return vmmsandbox.Check(vmmsandbox.NewProfile("", conf.VMMSandboxingSyscalls, conf.VMMSandboxingCapabilities))
+}
+
 func appendParam(params []Param, parameter string, value string) []Param {
 	return append(params, Param{parameter, value})
 }
@@ -805,6 +1056,30 @@ func CPUFlags(cpuInfoPath string) (map[string]bool, error) {
 	return map[string]bool{}, fmt.Errorf("Couldn't find %q from %q output", flagsField, cpuInfoPath)
 }
 
+// parseCPUFeatures splits a comma-separated list of cpu features into the
+// features to enable ("f", "+f" or "f=on") and to disable ("-f" or "f=off").
+// Other properties (e.g. "hv-spinlocks=0x1fff") are ignored.
+func parseCPUFeatures(features string) (enabled, disabled []string) {
+	for _, f := range strings.Split(features, ",") {
+		f = strings.TrimSpace(f)
+		switch {
+		case f == "":
+		case strings.HasPrefix(f, "+"):
+			enabled = append(enabled, f[1:])
+		case strings.HasPrefix(f, "-"):
+			disabled = append(disabled, f[1:])
+		case strings.HasSuffix(f, "=on"):
+			enabled = append(enabled, strings.TrimSuffix(f, "=on"))
+		case strings.HasSuffix(f, "=off"):
+			disabled = append(disabled, strings.TrimSuffix(f, "=off"))
+		case !strings.Contains(f, "="):
+			enabled = append(enabled, f)
+		}
+	}
+
+	return enabled, disabled
+}
+
 // RunningOnVMM checks if the system is running inside a VM.
 func RunningOnVMM(cpuInfoPath string) (bool, error) {
 	if runtime.GOARCH == "amd64" {
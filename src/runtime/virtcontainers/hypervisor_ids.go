@@ -0,0 +1,104 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/vmmsandbox"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// the hypervisor needs the group of the device to create the VM
+	kvmDevice = "/dev/kvm"
+
+	// the hugetlbfs mount point of the hypervisor memory files
+	hugePagesDir = "/dev/hugepages"
+)
+
+// hypervisorCredential allocates the user and group ID of the hypervisor of
+// a sandbox, returning nil when the hypervisor is run as root. The
+// hypervisor is given the group of the KVM device and the VM directory
+// vmPath, where it creates its sockets and files.
+func (conf *HypervisorConfig) hypervisorCredential(sandboxID, vmPath string) (cred *vmmsandbox.Credential, err error) {
+	if conf.HypervisorIDs == "" {
+		return nil, nil
+	}
+
+	r, err := parseIDRange(conf.HypervisorIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := allocateHostID(hypervisorIDsDir, r, sandboxID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			conf.releaseHypervisorID(sandboxID)
+		}
+	}()
+
+	cred = &vmmsandbox.Credential{UID: id, GID: id}
+
+	var st unix.Stat_t
+	if err := unix.Stat(kvmDevice, &st); err != nil {
+		return nil, err
+	}
+	if st.Gid != 0 {
+		cred.Groups = append(cred.Groups, st.Gid)
+	}
+
+	// the parents of the VM directory, e.g. /run/vc/vm and /run/vc, only
+	// need to be traversed, they can't be listed
+	parent := filepath.Dir(vmPath)
+	for _, dir := range []string{parent, filepath.Dir(parent)} {
+		fi, err := os.Stat(dir)
+		if err != nil {
+			return nil, err
+		}
+		if mode := fi.Mode().Perm(); mode&0001 == 0 {
+			if err := os.Chmod(dir, mode|0001); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := os.Chown(vmPath, int(id), int(id)); err != nil {
+		return nil, err
+	}
+
+	return cred, nil
+}
+
+// releaseHypervisorID releases the user and group ID of the hypervisor of a
+// sandbox, if any.
+func (conf *HypervisorConfig) releaseHypervisorID(sandboxID string) {
+	if conf.HypervisorIDs == "" {
+		return
+	}
+
+	if err := releaseHostID(hypervisorIDsDir, sandboxID); err != nil {
+		virtLog.WithError(err).WithField("sandbox", sandboxID).Warn("releasing hypervisor ID failed")
+	}
+}
+
+// shareSocketWithHypervisor gives the hypervisor run with cred the access to
+// a socket it connects to, e.g. the one of virtiofsd.
+func shareSocketWithHypervisor(path string, cred *vmmsandbox.Credential) error {
+	if cred == nil {
+		return nil
+	}
+
+	if err := os.Chown(path, -1, int(cred.GID)); err != nil {
+		return err
+	}
+
+	return os.Chmod(path, 0660)
+}
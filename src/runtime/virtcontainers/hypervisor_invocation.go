@@ -0,0 +1,96 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	persistapi "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/persist/api"
+)
+
+// HypervisorAPICall is a call to the API of the hypervisor, e.g. to the
+// REST API of Cloud Hypervisor.
+type HypervisorAPICall struct {
+	Method   string          `json:"method"`
+	Endpoint string          `json:"endpoint"`
+	Body     json.RawMessage `json:"body,omitempty"`
+}
+
+// HypervisorInvocation is how the hypervisor of a sandbox was launched and
+// the VM configured, so that the VM can be reproduced outside of Kata
+// Containers. It is kept in the persist store of the sandbox.
+type HypervisorInvocation struct {
+	// Path and Args are the command line of the hypervisor. Path is the
+	// launcher of the hypervisor when one is configured, except for QEMU
+	// whose command line is read from its process.
+	Path string   `json:"path"`
+	Args []string `json:"args,omitempty"`
+	// APICalls are the calls to the API of the hypervisor creating and
+	// booting the VM, in order.
+	APICalls []HypervisorAPICall `json:"api_calls,omitempty"`
+}
+
+func (i HypervisorInvocation) save() persistapi.HypervisorInvocation {
+	s := persistapi.HypervisorInvocation{
+		Path: i.Path,
+		Args: i.Args,
+	}
+	for _, call := range i.APICalls {
+		s.APICalls = append(s.APICalls, persistapi.HypervisorAPICall{
+			Method:   call.Method,
+			Endpoint: call.Endpoint,
+			Body:     string(call.Body),
+		})
+	}
+	return s
+}
+
+func loadHypervisorInvocation(s persistapi.HypervisorInvocation) HypervisorInvocation {
+	i := HypervisorInvocation{
+		Path: s.Path,
+		Args: s.Args,
+	}
+	for _, call := range s.APICalls {
+		apiCall := HypervisorAPICall{
+			Method:   call.Method,
+			Endpoint: call.Endpoint,
+		}
+		if call.Body != "" {
+			apiCall.Body = json.RawMessage(call.Body)
+		}
+		i.APICalls = append(i.APICalls, apiCall)
+	}
+	return i
+}
+
+// processInvocation returns the command line of the process pid.
+func processInvocation(pid int) (HypervisorInvocation, error) {
+	data, err := ioutil.ReadFile(fmt.Sprintf("/proc/%d/cmdline", pid))
+	if err != nil {
+		return HypervisorInvocation{}, err
+	}
+
+	args := strings.Split(strings.TrimSuffix(string(data), "\x00"), "\x00")
+	if args[0] == "" {
+		return HypervisorInvocation{}, fmt.Errorf("empty command line for process %d", pid)
+	}
+
+	return HypervisorInvocation{Path: args[0], Args: args[1:]}, nil
+}
+
+// GetHypervisorInvocation returns how the hypervisor of the sandbox was
+// launched, for the hypervisors recording it.
+func (s *Sandbox) GetHypervisorInvocation() (HypervisorInvocation, error) {
+	invocation := loadHypervisorInvocation(s.hypervisor.save().Invocation)
+	if invocation.Path == "" {
+		return invocation, fmt.Errorf("hypervisor invocation not recorded by %s", s.config.HypervisorType)
+	}
+
+	return invocation, nil
+}
@@ -6,6 +6,7 @@
 package virtcontainers
 
 import (
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -13,6 +14,8 @@ import (
 	"testing"
 
 	ktu "github.com/kata-containers/kata-containers/src/runtime/pkg/katatestutils"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/device/config"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/vmmsandbox"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/types"
 	"github.com/stretchr/testify/assert"
 )
@@ -158,6 +161,105 @@ func TestHypervisorConfigValidTemplateConfig(t *testing.T) {
 	testHypervisorConfigValid(t, hypervisorConfig, false)
 }
 
+func TestHypervisorConfigValidHypervisorIDs(t *testing.T) {
+	hypervisorConfig := &HypervisorConfig{
+		KernelPath:     fmt.Sprintf("%s/%s", testDir, testKernel),
+		ImagePath:      fmt.Sprintf("%s/%s", testDir, testImage),
+		HypervisorPath: fmt.Sprintf("%s/%s", testDir, testHypervisor),
+		HypervisorIDs:  "3000100000-3000165535",
+		SharedFS:       config.VirtioFS,
+	}
+	testHypervisorConfigValid(t, hypervisorConfig, true)
+
+	hypervisorConfig.HypervisorIDs = "0-1000"
+	testHypervisorConfigValid(t, hypervisorConfig, false)
+
+	hypervisorConfig.HypervisorIDs = "3000100000-3000165535"
+	hypervisorConfig.SharedFS = config.Virtio9P
+	testHypervisorConfigValid(t, hypervisorConfig, false)
+
+	hypervisorConfig.SharedFS = config.VirtioFS
+	hypervisorConfig.BootToBeTemplate = true
+	hypervisorConfig.MemoryPath = "foobar"
+	testHypervisorConfigValid(t, hypervisorConfig, false)
+}
+
+func TestVMMLauncherCredential(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "vmm-launcher")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	conf := &HypervisorConfig{HugePages: true}
+
+	path, err := conf.vmmLauncher(dir, "/usr/bin/qemu", nil)
+	assert.NoError(err)
+	assert.Equal("/usr/bin/qemu", path)
+
+	cred := &vmmsandbox.Credential{UID: 3000100000, GID: 3000100000, Groups: []uint32{36}}
+	path, err = conf.vmmLauncher(dir, "/usr/bin/qemu", cred)
+	assert.NoError(err)
+	assert.Equal(filepath.Join(dir, vmmsandbox.LauncherName), path)
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, "vmm-sandbox.json"))
+	assert.NoError(err)
+	var p vmmsandbox.Profile
+	assert.NoError(json.Unmarshal(data, &p))
+	assert.Equal(vmmsandbox.Profile{
+		Path:            "/usr/bin/qemu",
+		Namespaces:      []string{"mount"},
+		NoNewPrivileges: true,
+		Credential:      cred,
+		HugePagesDir:    hugePagesDir,
+	}, p)
+}
+
+func TestHypervisorConfigValidNUMAConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	newConfig := func() *HypervisorConfig {
+		return &HypervisorConfig{
+			KernelPath:      fmt.Sprintf("%s/%s", testDir, testKernel),
+			ImagePath:       fmt.Sprintf("%s/%s", testDir, testImage),
+			HypervisorPath:  fmt.Sprintf("%s/%s", testDir, testHypervisor),
+			NumVCPUs:        2,
+			DefaultMaxVCPUs: 8,
+			MemorySize:      2048,
+			NUMANodes: []NUMANode{
+				{VCPUs: []uint32{0, 2}, MemorySize: 1024},
+				{VCPUs: []uint32{1, 3}, MemorySize: 1024},
+			},
+		}
+	}
+
+	// the vCPUs out of the nodes are not hotplugged
+	hypervisorConfig := newConfig()
+	testHypervisorConfigValid(t, hypervisorConfig, true)
+	assert.Equal(uint32(4), hypervisorConfig.DefaultMaxVCPUs)
+
+	for _, update := range []func(*HypervisorConfig){
+		// no memory
+		func(c *HypervisorConfig) { c.NUMANodes[1].MemorySize = 0 },
+		// memory of the nodes differs from the VM memory
+		func(c *HypervisorConfig) { c.MemorySize = 4096 },
+		// no vCPU
+		func(c *HypervisorConfig) { c.NUMANodes[1].VCPUs = nil },
+		// vCPU in two nodes
+		func(c *HypervisorConfig) { c.NUMANodes[1].VCPUs = []uint32{1, 2} },
+		// vCPU 3 missing
+		func(c *HypervisorConfig) { c.NUMANodes[1].VCPUs = []uint32{1, 4} },
+		// less vCPUs than the VM
+		func(c *HypervisorConfig) { c.NumVCPUs = 6 },
+		// more vCPUs than the maximum
+		func(c *HypervisorConfig) { c.DefaultMaxVCPUs = 3 },
+	} {
+		hypervisorConfig = newConfig()
+		update(hypervisorConfig)
+		testHypervisorConfigValid(t, hypervisorConfig, false)
+	}
+}
+
 func TestHypervisorConfigDefaults(t *testing.T) {
 	assert := assert.New(t)
 	hypervisorConfig := &HypervisorConfig{
@@ -500,3 +602,15 @@ func TestAssetPath(t *testing.T) {
 		assert.Equal(expected, p, msg)
 	}
 }
+
+func TestParseCPUFeatures(t *testing.T) {
+	assert := assert.New(t)
+
+	enabled, disabled := parseCPUFeatures("")
+	assert.Empty(enabled)
+	assert.Empty(disabled)
+
+	enabled, disabled = parseCPUFeatures("avx512f, +vmx,-pmu,pdpe1gb=on,x2apic=off,hv-spinlocks=0x1fff,,")
+	assert.Equal([]string{"avx512f", "vmx", "pdpe1gb"}, enabled)
+	assert.Equal([]string{"pmu", "x2apic"}, disabled)
+}
@@ -0,0 +1,247 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/device/config"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/types"
+)
+
+// methods passing the initdata to the guest
+const (
+	// InitDataFwCfg passes the initdata as a QEMU fw_cfg blob.
+	InitDataFwCfg = "fw_cfg"
+
+	// InitDataDisk passes the initdata as a small read-only disk.
+	InitDataDisk = "disk"
+)
+
+const (
+	initDataImage = "initdata.img"
+	// initDataDriveID is also the serial of the initdata disk, the
+	// agent only reads the disk with this serial.
+	initDataDriveID = "initdata"
+	// initDataKernelParam names the initdata method, the agent only
+	// reads the initdata when it is set.
+	initDataKernelParam = "agent.initdata"
+	// initDataFwCfgName is the fw_cfg entry the agent reads from
+	// /sys/firmware/qemu_fw_cfg/by_name
+	initDataFwCfgName = "opt/io.katacontainers/initdata"
+
+	// the initdata image is a header sector followed by the JSON
+	// encoded InitData, padded to a whole number of sectors. The
+	// header is the magic, the version and the length of the data,
+	// little endian.
+	initDataMagic      = "KATAINIT"
+	initDataVersion    = 1
+	initDataSectorSize = 512
+)
+
+// InitDataConfig is the configuration of the initdata of a sandbox: the
+// sandbox metadata passed to the guest at boot, rather than by the agent
+// RPCs, so that the agent can apply it before the vsock is up.
+type InitDataConfig struct {
+	// Method is how the initdata is passed to the guest,
+	// the initdata is not used when empty.
+	Method string
+
+	// Certificates are the host paths of the PEM certificates
+	// installed in the guest trust store.
+	Certificates []string
+
+	// HTTPProxy, HTTPSProxy and NoProxy are the proxies set in the
+	// environment of the agent.
+	HTTPProxy  string
+	HTTPSProxy string
+	NoProxy    string
+}
+
+func (c InitDataConfig) enabled() bool {
+	return c.Method != ""
+}
+
+// kernelParam returns the kernel parameter telling the agent how the
+// initdata is passed.
+func (c InitDataConfig) kernelParam() Param {
+	return Param{Key: initDataKernelParam, Value: c.Method}
+}
+
+// Validate checks the initdata configuration.
+func (c InitDataConfig) Validate(hypervisorType HypervisorType) error {
+	switch c.Method {
+	case "":
+		return nil
+	case InitDataFwCfg, InitDataDisk:
+	default:
+		return fmt.Errorf("unknown initdata method %q", c.Method)
+	}
+
+	// the other hypervisors don't support fw_cfg, and name the
+	// disks by their index
+	if hypervisorType != QemuHypervisor {
+		return fmt.Errorf("initdata is not supported by %s", hypervisorType)
+	}
+
+	return nil
+}
+
+// InitData is the sandbox metadata passed to the guest at boot.
+type InitData struct {
+	SandboxID     string            `json:"sandbox_id"`
+	Hostname      string            `json:"hostname,omitempty"`
+	DNS           []string          `json:"dns,omitempty"`
+	Proxies       map[string]string `json:"proxies,omitempty"`
+	Certificates  []string          `json:"certificates,omitempty"`
+	AllowedAPIs   []string          `json:"allowed_apis,omitempty"`
+	KernelModules []string          `json:"kernel_modules,omitempty"`
+	GuestHookPath string            `json:"guest_hook_path,omitempty"`
+}
+
+// initDataHeader is the header sector of the initdata image.
+type initDataHeader struct {
+	Magic   [8]byte
+	Version uint32
+	Length  uint32
+}
+
+// encodeInitData returns the initdata image.
+func encodeInitData(data *InitData) ([]byte, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	header := initDataHeader{
+		Version: initDataVersion,
+		Length:  uint32(len(payload)),
+	}
+	copy(header.Magic[:], initDataMagic)
+
+	var buf bytes.Buffer
+	if err := binary.Write(&buf, binary.LittleEndian, header); err != nil {
+		return nil, err
+	}
+	buf.Write(make([]byte, initDataSectorSize-buf.Len()))
+	buf.Write(payload)
+	if pad := buf.Len() % initDataSectorSize; pad != 0 {
+		buf.Write(make([]byte, initDataSectorSize-pad))
+	}
+
+	return buf.Bytes(), nil
+}
+
+// initDataPassed returns whether the sandbox metadata is passed to the
+// guest by the initdata. It can't be with a VM factory, the VMs are booted
+// before the sandbox is known.
+func (s *Sandbox) initDataPassed() bool {
+	return s.config.InitDataConfig.enabled() && s.factory == nil
+}
+
+// newInitData assembles the initdata of the sandbox.
+func (s *Sandbox) newInitData() (*InitData, error) {
+	c := s.config.InitDataConfig
+
+	hostname := s.config.Hostname
+	if len(hostname) > maxHostnameLen {
+		hostname = hostname[:maxHostnameLen]
+	}
+
+	dns, err := getDNS(s)
+	if err != nil {
+		return nil, err
+	}
+
+	data := &InitData{
+		SandboxID:     s.id,
+		Hostname:      hostname,
+		DNS:           dns,
+		AllowedAPIs:   agentAllowedAPIs(s.config.AgentConfig),
+		KernelModules: s.config.AgentConfig.KernelModules,
+		GuestHookPath: s.config.HypervisorConfig.GuestHookPath,
+	}
+
+	proxies := map[string]string{
+		"HTTP_PROXY":  c.HTTPProxy,
+		"HTTPS_PROXY": c.HTTPSProxy,
+		"NO_PROXY":    c.NoProxy,
+	}
+	for name, value := range proxies {
+		if value == "" {
+			continue
+		}
+		if data.Proxies == nil {
+			data.Proxies = make(map[string]string)
+		}
+		data.Proxies[name] = value
+	}
+
+	for _, path := range c.Certificates {
+		cert, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("Could not read certificate %s: %v", path, err)
+		}
+		data.Certificates = append(data.Certificates, string(cert))
+	}
+
+	return data, nil
+}
+
+// setupInitData writes the initdata image of the sandbox and adds it to
+// the VM, before it is started.
+func (s *Sandbox) setupInitData(ctx context.Context) error {
+	if !s.config.InitDataConfig.enabled() {
+		return nil
+	}
+
+	if s.factory != nil {
+		s.Logger().Warn("initdata not supported with the VM factory, the sandbox metadata is sent to the agent")
+		return nil
+	}
+
+	data, err := s.newInitData()
+	if err != nil {
+		return err
+	}
+
+	image, err := encodeInitData(data)
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(s.store.RunStoragePath(), s.id, initDataImage)
+	if err := os.MkdirAll(filepath.Dir(path), DirMode); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(path, image, 0600); err != nil {
+		return err
+	}
+
+	s.Logger().WithField("method", s.config.InitDataConfig.Method).Info("Passing the sandbox metadata by initdata")
+
+	switch s.config.InitDataConfig.Method {
+	case InitDataFwCfg:
+		return s.hypervisor.addDevice(ctx, types.FwCfg{Name: initDataFwCfgName, File: path}, fwCfgDev)
+	case InitDataDisk:
+		drive := config.BlockDrive{
+			File:     path,
+			Format:   "raw",
+			ID:       initDataDriveID,
+			ReadOnly: true,
+		}
+		return s.hypervisor.addDevice(ctx, drive, blockDev)
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInitDataConfigValidate(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.NoError(InitDataConfig{}.Validate(FirecrackerHypervisor))
+	assert.NoError(InitDataConfig{Method: InitDataFwCfg}.Validate(QemuHypervisor))
+	assert.NoError(InitDataConfig{Method: InitDataDisk}.Validate(QemuHypervisor))
+	assert.Error(InitDataConfig{Method: "floppy"}.Validate(QemuHypervisor))
+	assert.Error(InitDataConfig{Method: InitDataDisk}.Validate(FirecrackerHypervisor))
+
+	assert.Equal(Param{Key: "agent.initdata", Value: "disk"}, InitDataConfig{Method: InitDataDisk}.kernelParam())
+}
+
+func TestEncodeInitData(t *testing.T) {
+	assert := assert.New(t)
+
+	data := &InitData{
+		SandboxID: "sid",
+		Hostname:  "pod",
+		DNS:       []string{"nameserver 10.0.0.10"},
+	}
+
+	image, err := encodeInitData(data)
+	assert.NoError(err)
+	assert.Zero(len(image) % initDataSectorSize)
+	assert.Equal(initDataMagic, string(image[:8]))
+	assert.Equal(uint32(initDataVersion), binary.LittleEndian.Uint32(image[8:12]))
+
+	length := binary.LittleEndian.Uint32(image[12:16])
+	payload := image[initDataSectorSize : initDataSectorSize+length]
+
+	var decoded InitData
+	assert.NoError(json.Unmarshal(payload, &decoded))
+	assert.Equal(*data, decoded)
+}
+
+func TestSetupInitData(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "initdata")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	cert := filepath.Join(dir, "ca.pem")
+	assert.NoError(ioutil.WriteFile(cert, []byte("-----BEGIN CERTIFICATE-----\n"), 0600))
+
+	s, err := testCreateSandbox(t, testSandboxID, MockHypervisor, newHypervisorConfig(nil, nil), NetworkConfig{}, nil, nil)
+	assert.NoError(err)
+	defer cleanUp()
+
+	s.config.Hostname = "pod"
+	s.config.InitDataConfig = InitDataConfig{
+		Method:       InitDataFwCfg,
+		Certificates: []string{cert},
+		HTTPSProxy:   "http://proxy:3128",
+	}
+	assert.True(s.initDataPassed())
+
+	data, err := s.newInitData()
+	assert.NoError(err)
+	assert.Equal(testSandboxID, data.SandboxID)
+	assert.Equal("pod", data.Hostname)
+	assert.Equal(map[string]string{"HTTPS_PROXY": "http://proxy:3128"}, data.Proxies)
+	assert.Equal([]string{"-----BEGIN CERTIFICATE-----\n"}, data.Certificates)
+
+	assert.NoError(s.setupInitData(context.Background()))
+	_, err = os.Stat(filepath.Join(s.store.RunStoragePath(), s.id, initDataImage))
+	assert.NoError(err)
+
+	s.config.InitDataConfig.Certificates = []string{filepath.Join(dir, "missing.pem")}
+	assert.Error(s.setupInitData(context.Background()))
+}
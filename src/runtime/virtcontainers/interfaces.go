@@ -15,6 +15,7 @@ import (
 	pbTypes "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/agent/protocols"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/types"
 	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sirupsen/logrus"
 )
 
@@ -39,6 +40,7 @@ type VCSandbox interface {
 	SetAnnotations(annotations map[string]string) error
 
 	Stats(ctx context.Context) (SandboxStats, error)
+	StatsSandbox(ctx context.Context) (PodStats, error)
 
 	Start(ctx context.Context) error
 	Stop(ctx context.Context, force bool) error
@@ -71,11 +73,32 @@ type VCSandbox interface {
 	ListRoutes(ctx context.Context) ([]*pbTypes.Route, error)
 
 	GetOOMEvent(ctx context.Context) (string, error)
+	GetMemoryEvent(ctx context.Context) (MemoryEvent, error)
 	GetHypervisorPid() (int, error)
 
+	RegisterMetrics(r prometheus.Registerer)
+	RegisterProcessMetrics(r prometheus.Registerer)
+	RegisterStorageMetrics(r prometheus.Registerer)
 	UpdateRuntimeMetrics() error
+	UpdateStorageMetrics() error
 	GetAgentMetrics(ctx context.Context) (string, error)
 	GetAgentURL() (string, error)
+	GetAgentAllowedAPIs() []string
+	GetAuditLog() ([]AuditRecord, error)
+	GetEphemeralDiskStatus() (EphemeralDiskStatus, error)
+	GetGuestProtectionStatus() (GuestProtectionStatus, error)
+	GetGuestStatus(ctx context.Context) (GuestStatus, error)
+	SetNetworkPolicy(ctx context.Context, ruleset string) error
+	GetNetworkPolicy() string
+	SetAgentLogLevel(ctx context.Context, level string) error
+	GetAgentLogLevel() string
+	SetDebugConsole(ctx context.Context, enable bool) error
+	DebugConsoleEnabled() bool
+	GetDryRunPlan() *SandboxPlan
+	GetHypervisorInvocation() (HypervisorInvocation, error)
+	GetExitReport() *ExitReport
+	DeviceLeaks() []DeviceLeak
+	CleanupDeviceLeaks(ctx context.Context) ([]DeviceLeak, error)
 }
 
 // VCContainer is the Container interface
@@ -31,6 +31,7 @@ import (
 	vcTypes "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/types"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/uuid"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/types"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/utils"
 
 	"github.com/gogo/protobuf/proto"
 	"github.com/opencontainers/runtime-spec/specs-go"
@@ -64,10 +65,18 @@ const (
 
 	sandboxMountsDir = "sandbox-mounts"
 
+	// the sandbox bind mounts flags
+	sandboxBindMountRO = "ro"
+	sandboxBindMountRW = "rw"
+
 	// enable debug console
 	kernelParamDebugConsole           = "agent.debug_console"
 	kernelParamDebugConsoleVPort      = "agent.debug_console_vport"
 	kernelParamDebugConsoleVPortValue = "1026"
+
+	// vsock port of the debug console started at runtime, the same as
+	// the one set at boot for kata-runtime exec to connect to it
+	debugConsoleVPort = 1026
 )
 
 var (
@@ -95,7 +104,9 @@ var (
 	shmDir                      = "shm"
 	kataEphemeralDevType        = "ephemeral"
 	defaultEphemeralPath        = filepath.Join(defaultKataGuestSandboxDir, kataEphemeralDevType)
+	hugetlbfsType               = "hugetlbfs"
 	grpcMaxDataSize             = int64(1024 * 1024)
+	grpcMaxBatchDataSize        = int64(2 * 1024 * 1024)
 	localDirOptions             = []string{"mode=0777"}
 	maxHostnameLen              = 64
 	GuestDNSFile                = "/etc/resolv.conf"
@@ -112,37 +123,44 @@ const (
 )
 
 const (
-	grpcCheckRequest             = "grpc.CheckRequest"
-	grpcExecProcessRequest       = "grpc.ExecProcessRequest"
-	grpcCreateSandboxRequest     = "grpc.CreateSandboxRequest"
-	grpcDestroySandboxRequest    = "grpc.DestroySandboxRequest"
-	grpcCreateContainerRequest   = "grpc.CreateContainerRequest"
-	grpcStartContainerRequest    = "grpc.StartContainerRequest"
-	grpcRemoveContainerRequest   = "grpc.RemoveContainerRequest"
-	grpcSignalProcessRequest     = "grpc.SignalProcessRequest"
-	grpcUpdateRoutesRequest      = "grpc.UpdateRoutesRequest"
-	grpcUpdateInterfaceRequest   = "grpc.UpdateInterfaceRequest"
-	grpcListInterfacesRequest    = "grpc.ListInterfacesRequest"
-	grpcListRoutesRequest        = "grpc.ListRoutesRequest"
-	grpcAddARPNeighborsRequest   = "grpc.AddARPNeighborsRequest"
-	grpcOnlineCPUMemRequest      = "grpc.OnlineCPUMemRequest"
-	grpcUpdateContainerRequest   = "grpc.UpdateContainerRequest"
-	grpcWaitProcessRequest       = "grpc.WaitProcessRequest"
-	grpcTtyWinResizeRequest      = "grpc.TtyWinResizeRequest"
-	grpcWriteStreamRequest       = "grpc.WriteStreamRequest"
-	grpcCloseStdinRequest        = "grpc.CloseStdinRequest"
-	grpcStatsContainerRequest    = "grpc.StatsContainerRequest"
-	grpcPauseContainerRequest    = "grpc.PauseContainerRequest"
-	grpcResumeContainerRequest   = "grpc.ResumeContainerRequest"
-	grpcReseedRandomDevRequest   = "grpc.ReseedRandomDevRequest"
-	grpcGuestDetailsRequest      = "grpc.GuestDetailsRequest"
-	grpcMemHotplugByProbeRequest = "grpc.MemHotplugByProbeRequest"
-	grpcCopyFileRequest          = "grpc.CopyFileRequest"
-	grpcSetGuestDateTimeRequest  = "grpc.SetGuestDateTimeRequest"
-	grpcStartTracingRequest      = "grpc.StartTracingRequest"
-	grpcStopTracingRequest       = "grpc.StopTracingRequest"
-	grpcGetOOMEventRequest       = "grpc.GetOOMEventRequest"
-	grpcGetMetricsRequest        = "grpc.GetMetricsRequest"
+	grpcCheckRequest                = "grpc.CheckRequest"
+	grpcExecProcessRequest          = "grpc.ExecProcessRequest"
+	grpcCreateSandboxRequest        = "grpc.CreateSandboxRequest"
+	grpcDestroySandboxRequest       = "grpc.DestroySandboxRequest"
+	grpcCreateContainerRequest      = "grpc.CreateContainerRequest"
+	grpcCreateContainerBatchRequest = "grpc.CreateContainerBatchRequest"
+	grpcStartContainerRequest       = "grpc.StartContainerRequest"
+	grpcRemoveContainerRequest      = "grpc.RemoveContainerRequest"
+	grpcSignalProcessRequest        = "grpc.SignalProcessRequest"
+	grpcUpdateRoutesRequest         = "grpc.UpdateRoutesRequest"
+	grpcUpdateInterfaceRequest      = "grpc.UpdateInterfaceRequest"
+	grpcListInterfacesRequest       = "grpc.ListInterfacesRequest"
+	grpcListRoutesRequest           = "grpc.ListRoutesRequest"
+	grpcAddARPNeighborsRequest      = "grpc.AddARPNeighborsRequest"
+	grpcOnlineCPUMemRequest         = "grpc.OnlineCPUMemRequest"
+	grpcUpdateContainerRequest      = "grpc.UpdateContainerRequest"
+	grpcWaitProcessRequest          = "grpc.WaitProcessRequest"
+	grpcTtyWinResizeRequest         = "grpc.TtyWinResizeRequest"
+	grpcWriteStreamRequest          = "grpc.WriteStreamRequest"
+	grpcCloseStdinRequest           = "grpc.CloseStdinRequest"
+	grpcStatsContainerRequest       = "grpc.StatsContainerRequest"
+	grpcPauseContainerRequest       = "grpc.PauseContainerRequest"
+	grpcResumeContainerRequest      = "grpc.ResumeContainerRequest"
+	grpcReseedRandomDevRequest      = "grpc.ReseedRandomDevRequest"
+	grpcGuestDetailsRequest         = "grpc.GuestDetailsRequest"
+	grpcMemHotplugByProbeRequest    = "grpc.MemHotplugByProbeRequest"
+	grpcCopyFileRequest             = "grpc.CopyFileRequest"
+	grpcSetGuestDateTimeRequest     = "grpc.SetGuestDateTimeRequest"
+	grpcStartTracingRequest         = "grpc.StartTracingRequest"
+	grpcStopTracingRequest          = "grpc.StopTracingRequest"
+	grpcGetOOMEventRequest          = "grpc.GetOOMEventRequest"
+	grpcGetMemoryEventRequest       = "grpc.GetMemoryEventRequest"
+	grpcGetMetricsRequest           = "grpc.GetMetricsRequest"
+	grpcGetGuestStatusRequest       = "grpc.GetGuestStatusRequest"
+	grpcSetNetworkPolicyRequest     = "grpc.SetNetworkPolicyRequest"
+	grpcSetLogLevelRequest          = "grpc.SetLogLevelRequest"
+	grpcSetDebugConsoleRequest      = "grpc.SetDebugConsoleRequest"
+	grpcReadStreamRequest           = "grpc.ReadStreamRequest"
 )
 
 // newKataAgent returns an agent from an agent type.
@@ -213,6 +231,10 @@ func ephemeralPath() string {
 	return defaultEphemeralPath
 }
 
+// AgentMetricsGroups are the groups of metrics the agent can collect, see
+// metrics_groups in the configuration.
+var AgentMetricsGroups = []string{"proc", "meminfo", "netdev", "filesystem"}
+
 // KataAgentConfig is a structure storing information needed
 // to reach the Kata Containers agent.
 type KataAgentConfig struct {
@@ -224,7 +246,21 @@ type KataAgentConfig struct {
 	TraceMode          string
 	TraceType          string
 	DialTimeout        uint32
+	// DialBackoffInitial and DialBackoffMax bound the delay between the
+	// attempts to dial the agent, in milliseconds. The client defaults are
+	// used when zero.
+	DialBackoffInitial uint32
+	DialBackoffMax     uint32
 	KernelModules      []string
+	// MetricsGroups selects the groups of metrics collected by the agent,
+	// all of them are collected when empty.
+	MetricsGroups []string
+	// PolicyFile is a local policy evaluated before sending each
+	// request to the agent, all the requests are allowed when empty.
+	PolicyFile string
+	// AllowedAPIs restricts the requests served by the agent, in addition
+	// to the ones the runtime needs. All of them are served when nil.
+	AllowedAPIs []string
 }
 
 // KataAgentState is the structure describing the data stored from this
@@ -233,8 +269,79 @@ type KataAgentState struct {
 	URL string
 }
 
+// agentMandatoryAPIs are the agent requests the runtime issues on its own to
+// run a sandbox, they are always part of the API allow-list sent to the
+// agent. The requests issued on behalf of the users, e.g. the exec of a
+// process or the changes of the management socket, are not.
+var agentMandatoryAPIs = []string{
+	grpcCheckRequest,
+	grpcCreateSandboxRequest,
+	grpcDestroySandboxRequest,
+	grpcCreateContainerRequest,
+	grpcCreateContainerBatchRequest,
+	grpcStartContainerRequest,
+	grpcRemoveContainerRequest,
+	grpcUpdateContainerRequest,
+	grpcSignalProcessRequest,
+	grpcWaitProcessRequest,
+	grpcWriteStreamRequest,
+	grpcReadStreamRequest,
+	grpcCloseStdinRequest,
+	grpcTtyWinResizeRequest,
+	grpcStatsContainerRequest,
+	grpcPauseContainerRequest,
+	grpcResumeContainerRequest,
+	grpcUpdateInterfaceRequest,
+	grpcUpdateRoutesRequest,
+	grpcListInterfacesRequest,
+	grpcListRoutesRequest,
+	grpcAddARPNeighborsRequest,
+	grpcOnlineCPUMemRequest,
+	grpcMemHotplugByProbeRequest,
+	grpcReseedRandomDevRequest,
+	grpcSetGuestDateTimeRequest,
+	grpcGuestDetailsRequest,
+	grpcGetGuestStatusRequest,
+	grpcCopyFileRequest,
+	grpcGetOOMEventRequest,
+	grpcGetMemoryEventRequest,
+	grpcGetMetricsRequest,
+	grpcStartTracingRequest,
+	grpcStopTracingRequest,
+}
+
+// agentDialConfig returns the configuration of the dial of the agent, the
+// failed attempts are reported while the guest is booting.
+func (k *kataAgent) agentDialConfig(config KataAgentConfig) kataclient.DialConfig {
+	return kataclient.DialConfig{
+		Timeout:        time.Duration(config.DialTimeout) * time.Second,
+		BackoffInitial: time.Duration(config.DialBackoffInitial) * time.Millisecond,
+		BackoffMax:     time.Duration(config.DialBackoffMax) * time.Millisecond,
+		OnFailure: func(attempt int, err error) {
+			k.metrics().agentDialAttempts.Set(float64(attempt))
+		},
+	}
+}
+
+// agentAllowedAPIs returns the API allow-list sent to the agent,
+// empty when all the requests are allowed.
+func agentAllowedAPIs(config KataAgentConfig) []string {
+	if config.AllowedAPIs == nil {
+		return nil
+	}
+
+	apis := append([]string{}, agentMandatoryAPIs...)
+	for _, api := range config.AllowedAPIs {
+		if !containsString(apis, api) {
+			apis = append(apis, api)
+		}
+	}
+
+	return apis
+}
+
 type kataAgent struct {
-	// lock protects the client pointer
+	// lock protects the client pointer and noBatchCreate
 	sync.Mutex
 	client *kataclient.AgentClient
 
@@ -243,8 +350,18 @@ type kataAgent struct {
 	keepConn       bool
 	dynamicTracing bool
 	dead           bool
-	dialTimout     uint32
+	dialConfig     kataclient.DialConfig
 	kmodules       []string
+	allowedAPIs    []string
+	policy         agentPolicy
+
+	// noBatchCreate is set when the agent doesn't support
+	// CreateContainerBatchRequest, the files needed by the
+	// containers are then copied one by one.
+	noBatchCreate bool
+
+	// sandboxMetrics are the metrics of the sandbox of the agent, see metrics.
+	sandboxMetrics *sandboxMetrics
 
 	vmSocket interface{}
 	ctx      context.Context
@@ -254,6 +371,18 @@ func (k *kataAgent) Logger() *logrus.Entry {
 	return virtLog.WithField("subsystem", "kata_agent")
 }
 
+// unboundAgentMetrics are the metrics of the agents not bound to a sandbox,
+// e.g. the agents of the VM factory, which are never exposed.
+var unboundAgentMetrics = newSandboxMetrics()
+
+// metrics returns the metrics of the sandbox of the agent.
+func (k *kataAgent) metrics() *sandboxMetrics {
+	if k.sandboxMetrics == nil {
+		return unboundAgentMetrics
+	}
+	return k.sandboxMetrics
+}
+
 func (k *kataAgent) longLiveConn() bool {
 	return k.keepConn
 }
@@ -339,7 +468,15 @@ func (k *kataAgent) init(ctx context.Context, sandbox *Sandbox, config KataAgent
 	disableVMShutdown = k.handleTraceSettings(config)
 	k.keepConn = config.LongLiveConn
 	k.kmodules = config.KernelModules
-	k.dialTimout = config.DialTimeout
+	k.sandboxMetrics = sandbox.metrics()
+	k.dialConfig = k.agentDialConfig(config)
+	k.allowedAPIs = agentAllowedAPIs(config)
+
+	if config.PolicyFile != "" {
+		if k.policy, err = loadFilePolicy(config.PolicyFile); err != nil {
+			return false, err
+		}
+	}
 
 	return disableVMShutdown, nil
 }
@@ -381,6 +518,21 @@ func (k *kataAgent) internalConfigure(ctx context.Context, h hypervisor, id stri
 	return nil
 }
 
+// ParseSandboxBindMount splits a sandbox bind mount, "<path>[:ro|:rw]",
+// into its host path and whether it is read-only, the default.
+func ParseSandboxBindMount(m string) (string, bool) {
+	if i := strings.LastIndex(m, ":"); i >= 0 {
+		switch m[i+1:] {
+		case sandboxBindMountRO:
+			return m[:i], true
+		case sandboxBindMountRW:
+			return m[:i], false
+		}
+	}
+
+	return m, true
+}
+
 func (k *kataAgent) setupSandboxBindMounts(ctx context.Context, sandbox *Sandbox) (err error) {
 	span, ctx := katatrace.Trace(ctx, k.Logger(), "setupSandboxBindMounts", kataAgentTracingTags)
 	defer span.End()
@@ -389,6 +541,12 @@ func (k *kataAgent) setupSandboxBindMounts(ctx context.Context, sandbox *Sandbox
 		return nil
 	}
 
+	defer func() {
+		if err != nil {
+			sandbox.metrics().sandboxBindMountFailures.WithLabelValues("setup").Inc()
+		}
+	}()
+
 	// Create subdirectory in host shared path for sandbox mounts
 	sandboxMountDir := filepath.Join(getMountPath(sandbox.id), sandboxMountsDir)
 	sandboxShareDir := filepath.Join(getSharePath(sandbox.id), sandboxMountsDir)
@@ -411,18 +569,31 @@ func (k *kataAgent) setupSandboxBindMounts(ctx context.Context, sandbox *Sandbox
 	}()
 
 	for _, m := range sandbox.config.SandboxBindMounts {
-		mountDest := filepath.Join(sandboxMountDir, filepath.Base(m))
+		path, readOnly := ParseSandboxBindMount(m)
+		mountDest := filepath.Join(sandboxMountDir, filepath.Base(path))
 		// bind-mount each sandbox mount that's defined into the sandbox mounts dir
-		if err := bindMount(ctx, m, mountDest, true, "private"); err != nil {
-			return fmt.Errorf("Mounting sandbox directory: %v to %v: %w", m, mountDest, err)
+		if err := bindMount(ctx, path, mountDest, true, "private"); err != nil {
+			return fmt.Errorf("Mounting sandbox directory: %v to %v: %w", path, mountDest, err)
 		}
 		mountedList = append(mountedList, mountDest)
 
-		mountDest = filepath.Join(sandboxShareDir, filepath.Base(m))
+		if !readOnly {
+			continue
+		}
+
+		mountDest = filepath.Join(sandboxShareDir, filepath.Base(path))
 		if err := remountRo(ctx, mountDest); err != nil {
-			return fmt.Errorf("remount sandbox directory: %v to %v: %w", m, mountDest, err)
+			return fmt.Errorf("remount sandbox directory: %v to %v: %w", path, mountDest, err)
 		}
 
+		// the guest must never be able to write to a read-only sandbox mount
+		var st unix.Statfs_t
+		if err := unix.Statfs(mountDest, &st); err != nil {
+			return fmt.Errorf("check sandbox directory %v: %w", mountDest, err)
+		}
+		if st.Flags&unix.ST_RDONLY == 0 {
+			return fmt.Errorf("sandbox directory %v is not read-only", mountDest)
+		}
 	}
 
 	return nil
@@ -434,9 +605,16 @@ func (k *kataAgent) cleanupSandboxBindMounts(sandbox *Sandbox) error {
 	}
 
 	var retErr error
+	defer func() {
+		if retErr != nil {
+			sandbox.metrics().sandboxBindMountFailures.WithLabelValues("cleanup").Inc()
+		}
+	}()
+
 	bindmountShareDir := filepath.Join(getMountPath(sandbox.id), sandboxMountsDir)
 	for _, m := range sandbox.config.SandboxBindMounts {
-		mountPath := filepath.Join(bindmountShareDir, filepath.Base(m))
+		path, _ := ParseSandboxBindMount(m)
+		mountPath := filepath.Join(bindmountShareDir, filepath.Base(path))
 		if err := syscall.Unmount(mountPath, syscall.MNT_DETACH|UmountNoFollow); err != nil {
 			if retErr == nil {
 				retErr = err
@@ -516,6 +694,11 @@ func (k *kataAgent) setupSharedPath(ctx context.Context, sandbox *Sandbox) (err
 	if err := os.MkdirAll(mountPath, DirMode); err != nil {
 		return err
 	}
+	for _, path := range []string{getSandboxPath(sandbox.id), sharePath, mountPath} {
+		if err := labelSandboxPath(&sandbox.config.HypervisorConfig, path); err != nil {
+			return err
+		}
+	}
 
 	// slave mount so that future mountpoints under mountPath are shown in sharePath as well
 	if err := bindMount(ctx, mountPath, sharePath, true, "slave"); err != nil {
@@ -769,13 +952,15 @@ func (k *kataAgent) reuseAgent(agent agent) error {
 
 	k.installReqFunc(a.client)
 	k.client = a.client
+	// the RPCs of the client are accounted to the sandbox from now on
+	a.sandboxMetrics = k.sandboxMetrics
 	return nil
 }
 
-func (k *kataAgent) getDNS(sandbox *Sandbox) ([]string, error) {
+func getDNS(sandbox *Sandbox) ([]string, error) {
 	ociSpec := sandbox.GetPatchedOCISpec()
 	if ociSpec == nil {
-		k.Logger().Debug("Sandbox OCI spec not found. Sandbox DNS will not be set.")
+		sandbox.Logger().Debug("Sandbox OCI spec not found. Sandbox DNS will not be set.")
 		return nil, nil
 	}
 
@@ -792,7 +977,7 @@ func (k *kataAgent) getDNS(sandbox *Sandbox) ([]string, error) {
 
 		}
 	}
-	k.Logger().Debug("DNS file not present in ociMounts. Sandbox DNS will not be set.")
+	sandbox.Logger().Debug("DNS file not present in ociMounts. Sandbox DNS will not be set.")
 	return nil, nil
 }
 
@@ -804,18 +989,8 @@ func (k *kataAgent) startSandbox(ctx context.Context, sandbox *Sandbox) error {
 		return err
 	}
 
-	hostname := sandbox.config.Hostname
-	if len(hostname) > maxHostnameLen {
-		hostname = hostname[:maxHostnameLen]
-	}
-
-	dns, err := k.getDNS(sandbox)
-	if err != nil {
-		return err
-	}
-
 	// check grpc server is serving
-	if err = k.check(ctx); err != nil {
+	if err := k.check(ctx); err != nil {
 		return err
 	}
 
@@ -835,21 +1010,38 @@ func (k *kataAgent) startSandbox(ctx context.Context, sandbox *Sandbox) error {
 	}
 
 	storages := setupStorages(ctx, sandbox)
-
-	kmodules := setupKernelModules(k.kmodules)
+	if sandbox.ephemeralDisk != nil {
+		storages = append(storages, sandbox.ephemeralDisk)
+	}
+	if sandbox.guestSwap != nil {
+		storages = append(storages, sandbox.guestSwap)
+	}
 
 	req := &grpc.CreateSandboxRequest{
-		Hostname:      hostname,
-		Dns:           dns,
-		Storages:      storages,
-		SandboxPidns:  sandbox.sharePidNs,
-		SandboxId:     sandbox.id,
-		GuestHookPath: sandbox.config.HypervisorConfig.GuestHookPath,
-		KernelModules: kmodules,
+		Storages:     storages,
+		SandboxPidns: sandbox.sharePidNs,
+		SandboxId:    sandbox.id,
+		// the allow-list is enforced by the agent from the initdata
+		// too, it is still sent in case the agent ignores it
+		AllowedApis: k.allowedAPIs,
+	}
+
+	// the agent applied the rest of the sandbox metadata at boot
+	if !sandbox.initDataPassed() {
+		req.Hostname = sandbox.config.Hostname
+		if len(req.Hostname) > maxHostnameLen {
+			req.Hostname = req.Hostname[:maxHostnameLen]
+		}
+
+		if req.Dns, err = getDNS(sandbox); err != nil {
+			return err
+		}
+
+		req.GuestHookPath = sandbox.config.HypervisorConfig.GuestHookPath
+		req.KernelModules = setupKernelModules(k.kmodules)
 	}
 
-	_, err = k.sendReq(ctx, req)
-	if err != nil {
+	if _, err = k.sendReq(ctx, req); err != nil {
 		return err
 	}
 
@@ -1028,11 +1220,21 @@ func (k *kataAgent) replaceOCIMountsForStorages(spec *specs.Spec, volumeStorages
 	return nil
 }
 
-func (k *kataAgent) constraintGRPCSpec(grpcSpec *grpc.Spec, passSeccomp bool) {
+func (k *kataAgent) constraintGRPCSpec(grpcSpec *grpc.Spec, passSeccomp, guestHooks bool, hugepageSizes []uint64) {
 	// Disable Hooks since they have been handled on the host and there is
 	// no reason to send them to the agent. It would make no sense to try
-	// to apply them on the guest.
-	grpcSpec.Hooks = nil
+	// to apply them on the guest, except for the hooks run in the container
+	// namespaces, which only exist in the guest, and the poststop hooks
+	// then run in the guest too.
+	if guestHooks && grpcSpec.Hooks != nil {
+		grpcSpec.Hooks = &grpc.Hooks{
+			CreateContainer: grpcSpec.Hooks.CreateContainer,
+			StartContainer:  grpcSpec.Hooks.StartContainer,
+			Poststop:        grpcSpec.Hooks.Poststop,
+		}
+	} else {
+		grpcSpec.Hooks = nil
+	}
 
 	// Pass seccomp only if disable_guest_seccomp is set to false in
 	// configuration.toml and guest image is seccomp capable.
@@ -1053,8 +1255,10 @@ func (k *kataAgent) constraintGRPCSpec(grpcSpec *grpc.Spec, passSeccomp bool) {
 	grpcSpec.Linux.Resources.Devices = nil
 	grpcSpec.Linux.Resources.Pids = nil
 	grpcSpec.Linux.Resources.BlockIO = nil
-	grpcSpec.Linux.Resources.HugepageLimits = nil
 	grpcSpec.Linux.Resources.Network = nil
+	// Only the hugepages of the sizes allocated in the guest can be
+	// limited, the guest kernel may not support the others.
+	grpcSpec.Linux.Resources.HugepageLimits = filterHugepageLimits(grpcSpec.Linux.Resources.HugepageLimits, hugepageSizes)
 	if grpcSpec.Linux.Resources.CPU != nil {
 		grpcSpec.Linux.Resources.CPU.Cpus = ""
 		grpcSpec.Linux.Resources.CPU.Mems = ""
@@ -1240,6 +1444,15 @@ func (k *kataAgent) rollbackFailingContainerCreation(ctx context.Context, c *Con
 }
 
 func (k *kataAgent) buildContainerRootfs(ctx context.Context, sandbox *Sandbox, c *Container, rootPathParent string) (*grpc.Storage, error) {
+	if c.remoteSnapshot != nil {
+		// The rootfs is provided by a remote snapshotter, and mounted
+		// by the agent in the guest where the image is lazily pulled.
+		if err := os.MkdirAll(filepath.Join(getMountPath(c.sandbox.id), c.id), DirMode); err != nil {
+			return nil, err
+		}
+		return c.remoteSnapshot.storage(rootPathParent), nil
+	}
+
 	if c.state.Fstype != "" && c.state.BlockDeviceID != "" {
 		// The rootfs storage volume represents the container rootfs
 		// mount point inside the guest.
@@ -1359,6 +1572,21 @@ func (k *kataAgent) createContainer(ctx context.Context, sandbox *Sandbox, c *Co
 
 	k.handleShm(ociSpec.Mounts, sandbox)
 
+	var hugepageLimits []specs.LinuxHugepageLimit
+	if ociSpec.Linux != nil && ociSpec.Linux.Resources != nil {
+		hugepageLimits = ociSpec.Linux.Resources.HugepageLimits
+	}
+
+	hugepagesStorages, hugepageSizes, err := k.handleHugepages(ociSpec.Mounts, hugepageLimits)
+	if err != nil {
+		return nil, err
+	}
+	for size := range sandbox.config.HypervisorConfig.GuestHugepages {
+		hugepageSizes = append(hugepageSizes, size)
+	}
+
+	ctrStorages = append(ctrStorages, hugepagesStorages...)
+
 	epheStorages, err := k.handleEphemeralStorage(ociSpec.Mounts)
 	if err != nil {
 		return nil, err
@@ -1366,7 +1594,13 @@ func (k *kataAgent) createContainer(ctx context.Context, sandbox *Sandbox, c *Co
 
 	ctrStorages = append(ctrStorages, epheStorages...)
 
-	localStorages, err := k.handleLocalStorage(ociSpec.Mounts, sandbox.id, c.rootfsSuffix)
+	var localStorages []*grpc.Storage
+	if sandbox.config.EphemeralDiskConfig.enabled() {
+		// the local directories are created in the ephemeral disk
+		localStorages, err = k.handleLocalStorageIn(ociSpec.Mounts, ephemeralDiskGuestPath())
+	} else {
+		localStorages, err = k.handleLocalStorage(ociSpec.Mounts, sandbox.id, c.rootfsSuffix)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -1416,7 +1650,13 @@ func (k *kataAgent) createContainer(ctx context.Context, sandbox *Sandbox, c *Co
 
 	// We need to constraint the spec to make sure we're not passing
 	// irrelevant information to the agent.
-	k.constraintGRPCSpec(grpcSpec, passSeccomp)
+	k.constraintGRPCSpec(grpcSpec, passSeccomp, sandbox.config.GuestOCIHooks, hugepageSizes)
+
+	// The CPU quota of the container is only enforced in the host
+	if cpu := grpcSpec.Linux.Resources.CPU; cpu != nil && !sandbox.config.CPUQuotaPolicy.inGuest() {
+		cpu.Quota = 0
+		cpu.Period = 0
+	}
 
 	req := &grpc.CreateContainerRequest{
 		ContainerId:  c.id,
@@ -1427,13 +1667,70 @@ func (k *kataAgent) createContainer(ctx context.Context, sandbox *Sandbox, c *Co
 		SandboxPidns: sharedPidNs,
 	}
 
-	if _, err = k.sendReq(ctx, req); err != nil {
+	if err = sandbox.agentCall(ctx, func() error {
+		return k.sendCreateContainer(ctx, req, c.filesToCopy)
+	}); err != nil {
 		return nil, err
 	}
 
 	return buildProcessFromExecID(req.ExecId)
 }
 
+func (k *kataAgent) batchCreateSupported() bool {
+	k.Lock()
+	defer k.Unlock()
+	return !k.noBatchCreate
+}
+
+// sendCreateContainer creates the container after copying the files it
+// needs in the guest. The files are sent along with the container in a
+// single request when the agent supports it, the bigger ones excepted.
+func (k *kataAgent) sendCreateContainer(ctx context.Context, req *grpc.CreateContainerRequest, files []fileCopy) error {
+	var batch []*grpc.CopyFileRequest
+	var batchSize int64
+
+	for _, f := range files {
+		cpReqs, err := copyFileRequests(f.src, f.dst)
+		if err != nil {
+			return err
+		}
+
+		size := cpReqs[0].FileSize
+		if !k.batchCreateSupported() || batchSize+size > grpcMaxBatchDataSize {
+			if err := k.sendCopyFileRequests(ctx, cpReqs); err != nil {
+				return err
+			}
+			continue
+		}
+
+		batch = append(batch, cpReqs...)
+		batchSize += size
+	}
+
+	if len(batch) > 0 {
+		_, err := k.sendReq(ctx, &grpc.CreateContainerBatchRequest{
+			Files:     batch,
+			Container: req,
+		})
+		code := grpcStatus.Convert(err).Code()
+		if err == nil || (code != codes.Unimplemented && code != codes.NotFound) {
+			return err
+		}
+
+		k.Logger().WithError(err).Warn("create container batch request failed due to old agent, please upgrade Kata Containers image version")
+		k.Lock()
+		k.noBatchCreate = true
+		k.Unlock()
+
+		if err := k.sendCopyFileRequests(ctx, batch); err != nil {
+			return err
+		}
+	}
+
+	_, err := k.sendReq(ctx, req)
+	return err
+}
+
 func buildProcessFromExecID(token string) (*Process, error) {
 	return &Process{
 		Token:     token,
@@ -1442,6 +1739,121 @@ func buildProcessFromExecID(token string) (*Process, error) {
 	}, nil
 }
 
+// handleHugepages handles the ephemeral volumes backed by hugepages on the
+// host, by creating a hugetlbfs storage inside the VM. The size option of
+// the storage is the container hugepage limit for the page size of the
+// volume: the agent allocates the hugepages of each container once per page
+// size, whatever the number of its volumes, and releases them when the
+// container is removed. The page sizes of the volumes are returned along
+// with the storages.
+func (k *kataAgent) handleHugepages(mounts []specs.Mount, hugepageLimits []specs.LinuxHugepageLimit) ([]*grpc.Storage, []uint64, error) {
+	var storages []*grpc.Storage
+	var pageSizes []uint64
+	for idx, mnt := range mounts {
+		if mnt.Type != KataEphemeralDevType {
+			continue
+		}
+
+		_, fsType, fsOptions, err := utils.GetDevicePathAndFsTypeOptions(mnt.Source)
+		if err != nil || fsType != hugetlbfsType {
+			continue
+		}
+
+		pageSize, err := hugetlbfsPageSize(fsOptions)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid hugepages volume %s: %v", mnt.Source, err)
+		}
+
+		var size uint64
+		for _, l := range hugepageLimits {
+			limitPageSize, err := parseHugepageSize(l.Pagesize)
+			if err != nil {
+				return nil, nil, err
+			}
+			if limitPageSize == pageSize {
+				size = l.Limit
+			}
+		}
+
+		if size == 0 {
+			return nil, nil, fmt.Errorf("no hugepage limit set for the %d bytes pages of volume %s", pageSize, mnt.Source)
+		}
+
+		// Set the mount source path to a path that resides inside the VM
+		mounts[idx].Source = filepath.Join(ephemeralPath(), filepath.Base(mnt.Source))
+		// Set the mount type to "bind"
+		mounts[idx].Type = "bind"
+
+		storages = append(storages, &grpc.Storage{
+			Driver:     KataEphemeralDevType,
+			Source:     "nodev",
+			Fstype:     hugetlbfsType,
+			MountPoint: mounts[idx].Source,
+			Options: []string{
+				fmt.Sprintf("pagesize=%d", pageSize),
+				fmt.Sprintf("size=%d", size),
+			},
+		})
+		pageSizes = append(pageSizes, pageSize)
+	}
+	return storages, pageSizes, nil
+}
+
+// hugetlbfsPageSize returns the page size in bytes of a hugetlbfs mount.
+func hugetlbfsPageSize(fsOptions []string) (uint64, error) {
+	for _, opt := range fsOptions {
+		if strings.HasPrefix(opt, "pagesize=") {
+			return parseHugepageSize(strings.TrimPrefix(opt, "pagesize="))
+		}
+	}
+	return 0, fmt.Errorf("no pagesize option")
+}
+
+// parseHugepageSize parses a hugepage size as used by the OCI hugepage
+// limits (e.g. 2MB) or by the hugetlbfs mounts (e.g. 2M), in bytes.
+func parseHugepageSize(s string) (uint64, error) {
+	units := map[string]uint64{
+		"K": 1 << 10,
+		"M": 1 << 20,
+		"G": 1 << 30,
+	}
+
+	size := strings.TrimSuffix(strings.ToUpper(s), "B")
+	if len(size) < 2 {
+		return 0, fmt.Errorf("invalid hugepage size %q", s)
+	}
+
+	unit, ok := units[size[len(size)-1:]]
+	if !ok {
+		return 0, fmt.Errorf("invalid hugepage size %q", s)
+	}
+
+	value, err := strconv.ParseUint(size[:len(size)-1], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid hugepage size %q: %v", s, err)
+	}
+
+	return value * unit, nil
+}
+
+// filterHugepageLimits returns the hugepage limits of the page sizes.
+func filterHugepageLimits(limits []grpc.LinuxHugepageLimit, pageSizes []uint64) []grpc.LinuxHugepageLimit {
+	var filtered []grpc.LinuxHugepageLimit
+	for _, l := range limits {
+		size, err := parseHugepageSize(l.Pagesize)
+		if err != nil {
+			continue
+		}
+		for _, s := range pageSizes {
+			if size == s {
+				filtered = append(filtered, l)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
 // handleEphemeralStorage handles ephemeral storages by
 // creating a Storage from corresponding source of the mount point
 func (k *kataAgent) handleEphemeralStorage(mounts []specs.Mount) ([]*grpc.Storage, error) {
@@ -1488,6 +1900,16 @@ func (k *kataAgent) handleEphemeralStorage(mounts []specs.Mount) ([]*grpc.Storag
 // handleLocalStorage handles local storage within the VM
 // by creating a directory in the VM from the source of the mount point.
 func (k *kataAgent) handleLocalStorage(mounts []specs.Mount, sandboxID string, rootfsSuffix string) ([]*grpc.Storage, error) {
+	// The directories are located in the sandbox directory.
+	// We rely on the fact that the first container in the VM has the same ID as the sandbox ID.
+	// In Kubernetes, this is usually the pause container and we depend on it existing for
+	// local directories to work.
+	return k.handleLocalStorageIn(mounts, filepath.Join(kataGuestSharedDir(), sandboxID, rootfsSuffix, KataLocalDevType))
+}
+
+// handleLocalStorageIn handles local storage within the VM by creating
+// a directory in the VM directory localDir from the source of the mount point.
+func (k *kataAgent) handleLocalStorageIn(mounts []specs.Mount, localDir string) ([]*grpc.Storage, error) {
 	var localStorages []*grpc.Storage
 	for idx, mnt := range mounts {
 		if mnt.Type == KataLocalDevType {
@@ -1509,11 +1931,7 @@ func (k *kataAgent) handleLocalStorage(mounts []specs.Mount, sandboxID string, r
 			}
 
 			// Set the mount source path to a the desired directory point in the VM.
-			// In this case it is located in the sandbox directory.
-			// We rely on the fact that the first container in the VM has the same ID as the sandbox ID.
-			// In Kubernetes, this is usually the pause container and we depend on it existing for
-			// local directories to work.
-			mounts[idx].Source = filepath.Join(kataGuestSharedDir(), sandboxID, rootfsSuffix, KataLocalDevType, filepath.Base(mnt.Source))
+			mounts[idx].Source = filepath.Join(localDir, filepath.Base(mnt.Source))
 
 			// Create a storage struct so that the kata agent is able to create the
 			// directory inside the VM.
@@ -1540,27 +1958,17 @@ func (k *kataAgent) handleDeviceBlockVolume(c *Container, m Mount, device api.De
 		k.Logger().Error("malformed block drive")
 		return nil, fmt.Errorf("malformed block drive")
 	}
-	switch {
 	// pmem volumes case
-	case blockDrive.Pmem:
+	if blockDrive.Pmem {
 		vol.Driver = kataNvdimmDevType
 		vol.Source = fmt.Sprintf("/dev/pmem%s", blockDrive.NvdimmID)
 		vol.Fstype = blockDrive.Format
 		vol.Options = []string{"dax"}
-	case c.sandbox.config.HypervisorConfig.BlockDeviceDriver == config.VirtioBlockCCW:
-		vol.Driver = kataBlkCCWDevType
-		vol.Source = blockDrive.DevNo
-	case c.sandbox.config.HypervisorConfig.BlockDeviceDriver == config.VirtioBlock:
-		vol.Driver = kataBlkDevType
-		vol.Source = blockDrive.PCIPath.String()
-	case c.sandbox.config.HypervisorConfig.BlockDeviceDriver == config.VirtioMmio:
-		vol.Driver = kataMmioBlkDevType
-		vol.Source = blockDrive.VirtPath
-	case c.sandbox.config.HypervisorConfig.BlockDeviceDriver == config.VirtioSCSI:
-		vol.Driver = kataSCSIDevType
-		vol.Source = blockDrive.SCSIAddr
-	default:
-		return nil, fmt.Errorf("Unknown block device driver: %s", c.sandbox.config.HypervisorConfig.BlockDeviceDriver)
+	} else {
+		var err error
+		if vol, err = newBlockDriveStorage(c.sandbox.config.HypervisorConfig.BlockDeviceDriver, blockDrive); err != nil {
+			return nil, err
+		}
 	}
 
 	vol.MountPoint = m.Destination
@@ -1576,6 +1984,31 @@ func (k *kataAgent) handleDeviceBlockVolume(c *Container, m Mount, device api.De
 	return vol, nil
 }
 
+// newBlockDriveStorage returns the storage of a block drive
+// attached to the VM with the block device driver.
+func newBlockDriveStorage(blockDeviceDriver string, blockDrive *config.BlockDrive) (*grpc.Storage, error) {
+	vol := &grpc.Storage{}
+
+	switch blockDeviceDriver {
+	case config.VirtioBlockCCW:
+		vol.Driver = kataBlkCCWDevType
+		vol.Source = blockDrive.DevNo
+	case config.VirtioBlock:
+		vol.Driver = kataBlkDevType
+		vol.Source = blockDrive.PCIPath.String()
+	case config.VirtioMmio:
+		vol.Driver = kataMmioBlkDevType
+		vol.Source = blockDrive.VirtPath
+	case config.VirtioSCSI:
+		vol.Driver = kataSCSIDevType
+		vol.Source = blockDrive.SCSIAddr
+	default:
+		return nil, fmt.Errorf("Unknown block device driver: %s", blockDeviceDriver)
+	}
+
+	return vol, nil
+}
+
 // handleVhostUserBlkVolume handles volume that is block device file
 // and VhostUserBlk type.
 func (k *kataAgent) handleVhostUserBlkVolume(c *Container, m Mount, device api.Device) (*grpc.Storage, error) {
@@ -1643,10 +2076,11 @@ func (k *kataAgent) handleBlockVolumes(c *Container) ([]*grpc.Storage, error) {
 }
 
 // handlePidNamespace checks if Pid namespace for a container needs to be shared with its sandbox
-// pid namespace. This function also modifies the grpc spec to remove the pid namespace
-// from the list of namespaces passed to the agent.
+// pid namespace, which is always the case when the sandbox is configured to share it. This
+// function also modifies the grpc spec to remove the pid namespace from the list of namespaces
+// passed to the agent.
 func (k *kataAgent) handlePidNamespace(grpcSpec *grpc.Spec, sandbox *Sandbox) bool {
-	sharedPidNs := false
+	sharedPidNs := sandbox.sharePidNs
 	pidIndex := -1
 
 	for i, ns := range grpcSpec.Linux.Namespaces {
@@ -1839,11 +2273,14 @@ func (k *kataAgent) connect(ctx context.Context) error {
 	}
 
 	k.Logger().WithField("url", k.state.URL).Info("New client")
-	client, err := kataclient.NewAgentClient(k.ctx, k.state.URL, k.dialTimout)
+	k.metrics().agentDialAttempts.Set(0)
+	start := time.Now()
+	client, err := kataclient.NewAgentClient(k.ctx, k.state.URL, k.dialConfig, agentRPCMetrics{agent: k})
 	if err != nil {
 		k.dead = true
 		return err
 	}
+	k.metrics().bootPhaseDuration.WithLabelValues(bootPhaseAgentDial).Set(time.Since(start).Seconds())
 
 	k.installReqFunc(client)
 	k.client = client
@@ -1881,7 +2318,7 @@ func (k *kataAgent) check(ctx context.Context) error {
 	return err
 }
 
-func (k *kataAgent) waitProcess(ctx context.Context, c *Container, processID string) (int32, error) {
+func (k *kataAgent) waitProcess(ctx context.Context, c *Container, processID string) (int32, *ExecSession, error) {
 	span, ctx := katatrace.Trace(ctx, k.Logger(), "waitProcess", kataAgentTracingTags)
 	defer span.End()
 
@@ -1890,10 +2327,23 @@ func (k *kataAgent) waitProcess(ctx context.Context, c *Container, processID str
 		ExecId:      processID,
 	})
 	if err != nil {
-		return 0, err
+		return 0, nil, err
+	}
+
+	// the session is only reported for the exec processes,
+	// and not by the older agents
+	waitResp := resp.(*grpc.WaitProcessResponse)
+	if waitResp.Session == nil {
+		return waitResp.Status, nil, nil
 	}
 
-	return resp.(*grpc.WaitProcessResponse).Status, nil
+	return waitResp.Status, &ExecSession{
+		UID:      waitResp.Session.Uid,
+		GID:      waitResp.Session.Gid,
+		Args:     waitResp.Session.Args,
+		Terminal: waitResp.Session.Terminal,
+		Duration: time.Duration(waitResp.Session.DurationMs) * time.Millisecond,
+	}, nil
 }
 
 func (k *kataAgent) writeProcessStdin(ctx context.Context, c *Container, ProcessID string, data []byte) (int, error) {
@@ -1946,6 +2396,9 @@ func (k *kataAgent) installReqFunc(c *kataclient.AgentClient) {
 	k.reqHandlers[grpcCreateContainerRequest] = func(ctx context.Context, req interface{}) (interface{}, error) {
 		return k.client.AgentServiceClient.CreateContainer(ctx, req.(*grpc.CreateContainerRequest))
 	}
+	k.reqHandlers[grpcCreateContainerBatchRequest] = func(ctx context.Context, req interface{}) (interface{}, error) {
+		return k.client.AgentServiceClient.CreateContainerBatch(ctx, req.(*grpc.CreateContainerBatchRequest))
+	}
 	k.reqHandlers[grpcStartContainerRequest] = func(ctx context.Context, req interface{}) (interface{}, error) {
 		return k.client.AgentServiceClient.StartContainer(ctx, req.(*grpc.StartContainerRequest))
 	}
@@ -2021,16 +2474,31 @@ func (k *kataAgent) installReqFunc(c *kataclient.AgentClient) {
 	k.reqHandlers[grpcGetOOMEventRequest] = func(ctx context.Context, req interface{}) (interface{}, error) {
 		return k.client.AgentServiceClient.GetOOMEvent(ctx, req.(*grpc.GetOOMEventRequest))
 	}
+	k.reqHandlers[grpcGetMemoryEventRequest] = func(ctx context.Context, req interface{}) (interface{}, error) {
+		return k.client.AgentServiceClient.GetMemoryEvent(ctx, req.(*grpc.GetMemoryEventRequest))
+	}
 	k.reqHandlers[grpcGetMetricsRequest] = func(ctx context.Context, req interface{}) (interface{}, error) {
 		return k.client.AgentServiceClient.GetMetrics(ctx, req.(*grpc.GetMetricsRequest))
 	}
+	k.reqHandlers[grpcGetGuestStatusRequest] = func(ctx context.Context, req interface{}) (interface{}, error) {
+		return k.client.AgentServiceClient.GetGuestStatus(ctx, req.(*grpc.GetGuestStatusRequest))
+	}
+	k.reqHandlers[grpcSetNetworkPolicyRequest] = func(ctx context.Context, req interface{}) (interface{}, error) {
+		return k.client.AgentServiceClient.SetNetworkPolicy(ctx, req.(*grpc.SetNetworkPolicyRequest))
+	}
+	k.reqHandlers[grpcSetLogLevelRequest] = func(ctx context.Context, req interface{}) (interface{}, error) {
+		return k.client.AgentServiceClient.SetLogLevel(ctx, req.(*grpc.SetLogLevelRequest))
+	}
+	k.reqHandlers[grpcSetDebugConsoleRequest] = func(ctx context.Context, req interface{}) (interface{}, error) {
+		return k.client.AgentServiceClient.SetDebugConsole(ctx, req.(*grpc.SetDebugConsoleRequest))
+	}
 }
 
 func (k *kataAgent) getReqContext(ctx context.Context, reqName string) (newCtx context.Context, cancel context.CancelFunc) {
 	newCtx = ctx
 	switch reqName {
-	case grpcWaitProcessRequest, grpcGetOOMEventRequest:
-		// Wait and GetOOMEvent have no timeout
+	case grpcWaitProcessRequest, grpcGetOOMEventRequest, grpcGetMemoryEventRequest:
+		// Wait, GetOOMEvent and GetMemoryEvent have no timeout
 	case grpcCheckRequest:
 		newCtx, cancel = context.WithTimeout(ctx, checkRequestTimeout)
 	default:
@@ -2055,6 +2523,14 @@ func (k *kataAgent) sendReq(spanCtx context.Context, request interface{}) (inter
 	if msgName == "" || handler == nil {
 		return nil, errors.New("Invalid request type")
 	}
+
+	if k.policy != nil {
+		if err := k.policy.evaluate(msgName, request); err != nil {
+			k.metrics().agentPolicyDenials.WithLabelValues(msgName).Inc()
+			k.Logger().WithError(err).WithField("name", msgName).Warn("request denied by agent policy")
+			return nil, err
+		}
+	}
 	message := request.(proto.Message)
 	ctx, cancel := k.getReqContext(spanCtx, msgName)
 	if cancel != nil {
@@ -2063,7 +2539,7 @@ func (k *kataAgent) sendReq(spanCtx context.Context, request interface{}) (inter
 	k.Logger().WithField("name", msgName).WithField("req", message.String()).Trace("sending request")
 
 	defer func() {
-		agentRPCDurationsHistogram.WithLabelValues(msgName).Observe(float64(time.Since(start).Nanoseconds() / int64(time.Millisecond)))
+		k.metrics().agentRPCDurationsHistogram.WithLabelValues(msgName).Observe(float64(time.Since(start).Nanoseconds() / int64(time.Millisecond)))
 	}()
 	return handler(ctx, request)
 }
@@ -2126,62 +2602,80 @@ func (k *kataAgent) setGuestDateTime(ctx context.Context, tv time.Time) error {
 }
 
 func (k *kataAgent) copyFile(ctx context.Context, src, dst string) error {
+	cpReqs, err := copyFileRequests(src, dst)
+	if err != nil {
+		return err
+	}
+
+	k.Logger().WithFields(logrus.Fields{
+		"source": src,
+		"dest":   dst,
+	}).Debugf("Copying file from host to guest")
+
+	return k.sendCopyFileRequests(ctx, cpReqs)
+}
+
+func (k *kataAgent) sendCopyFileRequests(ctx context.Context, cpReqs []*grpc.CopyFileRequest) error {
+	for _, cpReq := range cpReqs {
+		if _, err := k.sendReq(ctx, cpReq); err != nil {
+			return fmt.Errorf("Could not send CopyFile request: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// copyFileRequests returns the requests copying the src file from the host
+// to dst in the guest, the file is copied by parts if it's needed.
+func copyFileRequests(src, dst string) ([]*grpc.CopyFileRequest, error) {
 	var st unix.Stat_t
 
 	err := unix.Stat(src, &st)
 	if err != nil {
-		return fmt.Errorf("Could not get file %s information: %v", src, err)
+		return nil, fmt.Errorf("Could not get file %s information: %v", src, err)
 	}
 
 	b, err := ioutil.ReadFile(src)
 	if err != nil {
-		return fmt.Errorf("Could not read file %s: %v", src, err)
+		return nil, fmt.Errorf("Could not read file %s: %v", src, err)
 	}
 
 	fileSize := int64(len(b))
 
-	k.Logger().WithFields(logrus.Fields{
-		"source": src,
-		"dest":   dst,
-	}).Debugf("Copying file from host to guest")
-
-	cpReq := &grpc.CopyFileRequest{
-		Path:     dst,
-		DirMode:  uint32(DirMode),
-		FileMode: st.Mode,
-		FileSize: fileSize,
-		Uid:      int32(st.Uid),
-		Gid:      int32(st.Gid),
+	newReq := func() *grpc.CopyFileRequest {
+		return &grpc.CopyFileRequest{
+			Path:     dst,
+			DirMode:  uint32(DirMode),
+			FileMode: st.Mode,
+			FileSize: fileSize,
+			Uid:      int32(st.Uid),
+			Gid:      int32(st.Gid),
+		}
 	}
 
 	// Handle the special case where the file is empty
 	if fileSize == 0 {
-		_, err = k.sendReq(ctx, cpReq)
-		return err
+		return []*grpc.CopyFileRequest{newReq()}, nil
 	}
 
-	// Copy file by parts if it's needed
-	remainingBytes := fileSize
+	var cpReqs []*grpc.CopyFileRequest
 	offset := int64(0)
-	for remainingBytes > 0 {
+	for len(b) > 0 {
 		bytesToCopy := int64(len(b))
 		if bytesToCopy > grpcMaxDataSize {
 			bytesToCopy = grpcMaxDataSize
 		}
 
+		cpReq := newReq()
 		cpReq.Data = b[:bytesToCopy]
 		cpReq.Offset = offset
-
-		if _, err = k.sendReq(ctx, cpReq); err != nil {
-			return fmt.Errorf("Could not send CopyFile request: %v", err)
-		}
+		cpReqs = append(cpReqs, cpReq)
 
 		b = b[bytesToCopy:]
-		remainingBytes -= bytesToCopy
-		offset += grpcMaxDataSize
+		offset += bytesToCopy
 	}
 
-	return nil
+	return cpReqs, nil
 }
 
 func (k *kataAgent) markDead(ctx context.Context) {
@@ -2234,6 +2728,21 @@ func (k *kataAgent) getOOMEvent(ctx context.Context) (string, error) {
 	return "", err
 }
 
+func (k *kataAgent) getMemoryEvent(ctx context.Context) (MemoryEvent, error) {
+	req := &grpc.GetMemoryEventRequest{}
+	result, err := k.sendReq(ctx, req)
+	if err != nil {
+		return MemoryEvent{}, err
+	}
+
+	event := result.(*grpc.MemoryEvent)
+	return MemoryEvent{
+		ContainerID: event.ContainerId,
+		Event:       event.Event,
+		Count:       event.Count,
+	}, nil
+}
+
 func (k *kataAgent) getAgentMetrics(ctx context.Context, req *grpc.GetMetricsRequest) (*grpc.Metrics, error) {
 	resp, err := k.sendReq(ctx, req)
 	if err != nil {
@@ -2242,3 +2751,34 @@ func (k *kataAgent) getAgentMetrics(ctx context.Context, req *grpc.GetMetricsReq
 
 	return resp.(*grpc.Metrics), nil
 }
+
+func (k *kataAgent) getGuestStatus(ctx context.Context) (*grpc.GuestStatus, error) {
+	resp, err := k.sendReq(ctx, &grpc.GetGuestStatusRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.(*grpc.GuestStatus), nil
+}
+
+func (k *kataAgent) setNetworkPolicy(ctx context.Context, ruleset string) error {
+	_, err := k.sendReq(ctx, &grpc.SetNetworkPolicyRequest{
+		Ruleset: ruleset,
+	})
+	return err
+}
+
+func (k *kataAgent) setLogLevel(ctx context.Context, level string) error {
+	_, err := k.sendReq(ctx, &grpc.SetLogLevelRequest{
+		Level: level,
+	})
+	return err
+}
+
+func (k *kataAgent) setDebugConsole(ctx context.Context, enable bool) error {
+	_, err := k.sendReq(ctx, &grpc.SetDebugConsoleRequest{
+		Enable: enable,
+		Vport:  debugConsoleVPort,
+	})
+	return err
+}
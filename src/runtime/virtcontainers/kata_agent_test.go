@@ -20,6 +20,7 @@ import (
 
 	specs "github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/sys/unix"
 
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/device/api"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/device/config"
@@ -161,7 +162,7 @@ func TestKataAgentSendReq(t *testing.T) {
 	err = k.check(ctx)
 	assert.Nil(err)
 
-	_, err = k.waitProcess(ctx, container, execid)
+	_, _, err = k.waitProcess(ctx, container, execid)
 	assert.Nil(err)
 
 	_, err = k.writeProcessStdin(ctx, container, execid, []byte{'c'})
@@ -201,6 +202,34 @@ func TestHandleEphemeralStorage(t *testing.T) {
 		"Ephemeral mount point didn't match: got %s, expecting %s", epheMountPoint, expected)
 }
 
+func TestParseHugepageSize(t *testing.T) {
+	assert := assert.New(t)
+
+	for s, expected := range map[string]uint64{
+		"2MB":  2 << 20,
+		"2M":   2 << 20,
+		"1GB":  1 << 30,
+		"64KB": 64 << 10,
+		"64k":  64 << 10,
+	} {
+		size, err := parseHugepageSize(s)
+		assert.NoError(err, s)
+		assert.Equal(expected, size, s)
+	}
+
+	for _, s := range []string{"", "M", "2", "2TB", "xMB"} {
+		_, err := parseHugepageSize(s)
+		assert.Error(err, s)
+	}
+
+	size, err := hugetlbfsPageSize([]string{"rw", "relatime", "pagesize=1024M"})
+	assert.NoError(err)
+	assert.Equal(uint64(1<<30), size)
+
+	_, err = hugetlbfsPageSize([]string{"rw"})
+	assert.Error(err)
+}
+
 func TestHandleLocalStorage(t *testing.T) {
 	k := kataAgent{}
 	var ociMounts []specs.Mount
@@ -567,7 +596,7 @@ func TestConstraintGRPCSpec(t *testing.T) {
 				CPU:            &pb.LinuxCPU{},
 				Pids:           &pb.LinuxPids{},
 				BlockIO:        &pb.LinuxBlockIO{},
-				HugepageLimits: []pb.LinuxHugepageLimit{},
+				HugepageLimits: []pb.LinuxHugepageLimit{{Pagesize: "2MB", Limit: 2 << 20}, {Pagesize: "1GB", Limit: 1 << 30}},
 				Network:        &pb.LinuxNetwork{},
 			},
 			CgroupsPath: "system.slice:foo:bar",
@@ -588,7 +617,7 @@ func TestConstraintGRPCSpec(t *testing.T) {
 	}
 
 	k := kataAgent{}
-	k.constraintGRPCSpec(g, true)
+	k.constraintGRPCSpec(g, true, false, []uint64{2 << 20})
 
 	// check nil fields
 	assert.Nil(g.Hooks)
@@ -597,7 +626,7 @@ func TestConstraintGRPCSpec(t *testing.T) {
 	assert.NotNil(g.Linux.Resources.Memory)
 	assert.Nil(g.Linux.Resources.Pids)
 	assert.Nil(g.Linux.Resources.BlockIO)
-	assert.Nil(g.Linux.Resources.HugepageLimits)
+	assert.Equal([]pb.LinuxHugepageLimit{{Pagesize: "2MB", Limit: 2 << 20}}, g.Linux.Resources.HugepageLimits)
 	assert.Nil(g.Linux.Resources.Network)
 	assert.NotNil(g.Linux.Resources.CPU)
 	assert.Equal(g.Process.SelinuxLabel, "")
@@ -616,6 +645,41 @@ func TestConstraintGRPCSpec(t *testing.T) {
 	assert.Empty(g.Linux.Devices)
 }
 
+func TestConstraintGRPCSpecGuestHooks(t *testing.T) {
+	assert := assert.New(t)
+
+	hook := pb.Hook{Path: "/usr/bin/hook"}
+	g := &pb.Spec{
+		Hooks: &pb.Hooks{
+			Prestart:        []pb.Hook{hook},
+			Poststart:       []pb.Hook{hook},
+			Poststop:        []pb.Hook{hook},
+			CreateContainer: []pb.Hook{hook},
+			StartContainer:  []pb.Hook{hook},
+		},
+		Linux: &pb.Linux{
+			Resources: &pb.LinuxResources{
+				HugepageLimits: []pb.LinuxHugepageLimit{{Pagesize: "2MB", Limit: 2 << 20}},
+			},
+		},
+		Process: &pb.Process{},
+	}
+
+	k := kataAgent{}
+	k.constraintGRPCSpec(g, true, true, nil)
+
+	// no hugepages allocated in the guest
+	assert.Nil(g.Linux.Resources.HugepageLimits)
+
+	// the host runs the other hooks
+	assert.NotNil(g.Hooks)
+	assert.Empty(g.Hooks.Prestart)
+	assert.Empty(g.Hooks.Poststart)
+	assert.Len(g.Hooks.Poststop, 1)
+	assert.Len(g.Hooks.CreateContainer, 1)
+	assert.Len(g.Hooks.StartContainer, 1)
+}
+
 func TestHandleShm(t *testing.T) {
 	assert := assert.New(t)
 	k := kataAgent{}
@@ -734,6 +798,11 @@ func TestHandlePidNamespace(t *testing.T) {
 	sharedPid = k.handlePidNamespace(g, sandbox)
 	assert.True(sharedPid)
 	assert.False(testIsPidNamespacePresent(g))
+
+	// the sandbox pid namespace is shared with all the containers
+	sandbox.sharePidNs = true
+	sharedPid = k.handlePidNamespace(g, sandbox)
+	assert.True(sharedPid)
 }
 
 func TestAgentConfigure(t *testing.T) {
@@ -960,6 +1029,91 @@ func TestKataCopyFile(t *testing.T) {
 	assert.NoError(err)
 }
 
+func TestCopyFileRequests(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := copyFileRequests("/abc/xyz/123", "/tmp")
+	assert.Error(err)
+
+	src, err := ioutil.TempFile("", "src")
+	assert.NoError(err)
+	defer os.Remove(src.Name())
+	assert.NoError(src.Close())
+
+	// an empty file is copied with a single request
+	cpReqs, err := copyFileRequests(src.Name(), "/run/kata-containers/shared/containers/dst")
+	assert.NoError(err)
+	assert.Len(cpReqs, 1)
+	assert.Zero(cpReqs[0].FileSize)
+
+	assert.NoError(ioutil.WriteFile(src.Name(), []byte("abcdefghi"), 0644))
+
+	orgGrpcMaxDataSize := grpcMaxDataSize
+	grpcMaxDataSize = 4
+	defer func() {
+		grpcMaxDataSize = orgGrpcMaxDataSize
+	}()
+
+	cpReqs, err = copyFileRequests(src.Name(), "/run/kata-containers/shared/containers/dst")
+	assert.NoError(err)
+	assert.Len(cpReqs, 3)
+	for i, data := range []string{"abcd", "efgh", "i"} {
+		assert.Equal([]byte(data), cpReqs[i].Data)
+		assert.Equal(int64(i*4), cpReqs[i].Offset)
+		assert.Equal(int64(9), cpReqs[i].FileSize)
+	}
+}
+
+func TestKataSendCreateContainer(t *testing.T) {
+	assert := assert.New(t)
+
+	url, err := mock.GenerateKataMockHybridVSock()
+	assert.NoError(err)
+
+	hybridVSockTTRPCMock := mock.HybridVSockTTRPCMock{}
+	err = hybridVSockTTRPCMock.Start(url)
+	assert.NoError(err)
+	defer hybridVSockTTRPCMock.Stop()
+
+	k := &kataAgent{
+		ctx: context.Background(),
+		state: KataAgentState{
+			URL: url,
+		},
+	}
+
+	small, err := ioutil.TempFile("", "small")
+	assert.NoError(err)
+	defer os.Remove(small.Name())
+	_, err = small.Write([]byte("abc"))
+	assert.NoError(err)
+	assert.NoError(small.Close())
+
+	big, err := ioutil.TempFile("", "big")
+	assert.NoError(err)
+	defer os.Remove(big.Name())
+	_, err = big.Write([]byte("abcdefghi123456789"))
+	assert.NoError(err)
+	assert.NoError(big.Close())
+
+	orgGrpcMaxBatchDataSize := grpcMaxBatchDataSize
+	grpcMaxBatchDataSize = 8
+	defer func() {
+		grpcMaxBatchDataSize = orgGrpcMaxBatchDataSize
+	}()
+
+	req := &pb.CreateContainerRequest{ContainerId: "foo", ExecId: "foo"}
+	files := []fileCopy{
+		{src: small.Name(), dst: "/run/kata-containers/shared/containers/small"},
+		{src: big.Name(), dst: "/run/kata-containers/shared/containers/big"},
+	}
+
+	assert.NoError(k.sendCreateContainer(context.Background(), req, files))
+	assert.False(k.noBatchCreate)
+
+	assert.Error(k.sendCreateContainer(context.Background(), req, []fileCopy{{src: "/abc/xyz/123", dst: "/tmp"}}))
+}
+
 func TestKataCleanupSandbox(t *testing.T) {
 	assert := assert.New(t)
 
@@ -1254,7 +1408,7 @@ func TestSandboxBindMount(t *testing.T) {
 		ctx: context.Background(),
 		id:  "foobar",
 		config: &SandboxConfig{
-			SandboxBindMounts: []string{m1Path, m2Path},
+			SandboxBindMounts: []string{m1Path, m2Path + ":rw"},
 		},
 	}
 	k := &kataAgent{ctx: context.Background()}
@@ -1282,6 +1436,13 @@ func TestSandboxBindMount(t *testing.T) {
 	err = k.setupSandboxBindMounts(context.Background(), sandbox)
 	assert.NoError(err)
 
+	// only the read-only mounts are read-only in the shared directory
+	var st unix.Statfs_t
+	assert.NoError(unix.Statfs(filepath.Join(sharePath, sandboxMountsDir, filepath.Base(m1Path)), &st))
+	assert.NotZero(st.Flags & unix.ST_RDONLY)
+	assert.NoError(unix.Statfs(filepath.Join(sharePath, sandboxMountsDir, filepath.Base(m2Path)), &st))
+	assert.Zero(st.Flags & unix.ST_RDONLY)
+
 	// Test the cleanup function. We expect it to succeed for the mount to be removed.
 	err = k.cleanupSandboxBindMounts(sandbox)
 	assert.NoError(err)
@@ -1329,3 +1490,47 @@ func TestSandboxBindMount(t *testing.T) {
 	assert.True(os.IsNotExist(err))
 
 }
+
+func TestParseSandboxBindMount(t *testing.T) {
+	assert := assert.New(t)
+
+	for m, expected := range map[string]struct {
+		path     string
+		readOnly bool
+	}{
+		"/etc/pki":          {"/etc/pki", true},
+		"/etc/pki:ro":       {"/etc/pki", true},
+		"/var/lib/data:rw":  {"/var/lib/data", false},
+		"/var/lib/data:foo": {"/var/lib/data:foo", true},
+	} {
+		path, readOnly := ParseSandboxBindMount(m)
+		assert.Equal(expected.path, path, m)
+		assert.Equal(expected.readOnly, readOnly, m)
+	}
+}
+
+func TestAgentAllowedAPIs(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Nil(agentAllowedAPIs(KataAgentConfig{}))
+
+	// an empty allow-list only allows the APIs needed by the runtime
+	apis := agentAllowedAPIs(KataAgentConfig{AllowedAPIs: []string{}})
+	assert.Equal(agentMandatoryAPIs, apis)
+	assert.NotContains(apis, grpcExecProcessRequest)
+
+	// including the ones the runtime issues outside of the containers lifecycle
+	for _, api := range []string{grpcStatsContainerRequest, grpcPauseContainerRequest, grpcResumeContainerRequest,
+		grpcGetMetricsRequest, grpcStartTracingRequest, grpcStopTracingRequest, grpcListInterfacesRequest,
+		grpcListRoutesRequest, grpcSetGuestDateTimeRequest} {
+		assert.Contains(apis, api)
+	}
+
+	apis = agentAllowedAPIs(KataAgentConfig{AllowedAPIs: []string{grpcExecProcessRequest, grpcCreateContainerRequest}})
+	assert.Len(apis, len(agentMandatoryAPIs)+1)
+	assert.Contains(apis, grpcExecProcessRequest)
+	assert.Contains(apis, grpcCreateContainerRequest)
+
+	s := &Sandbox{config: &SandboxConfig{AgentConfig: KataAgentConfig{AllowedAPIs: []string{grpcGetMetricsRequest}}}}
+	assert.Contains(s.GetAgentAllowedAPIs(), grpcGetMetricsRequest)
+}
@@ -132,8 +132,8 @@ func (n *mockAgent) statsContainer(ctx context.Context, sandbox *Sandbox, c Cont
 }
 
 // waitProcess is the Noop agent process waiter. It does nothing.
-func (n *mockAgent) waitProcess(ctx context.Context, c *Container, processID string) (int32, error) {
-	return 0, nil
+func (n *mockAgent) waitProcess(ctx context.Context, c *Container, processID string) (int32, *ExecSession, error) {
+	return 0, nil, nil
 }
 
 // winsizeProcess is the Noop agent process tty resizer. It does nothing.
@@ -233,6 +233,26 @@ func (n *mockAgent) getOOMEvent(ctx context.Context) (string, error) {
 	return "", nil
 }
 
+func (n *mockAgent) getMemoryEvent(ctx context.Context) (MemoryEvent, error) {
+	return MemoryEvent{}, nil
+}
+
 func (n *mockAgent) getAgentMetrics(ctx context.Context, req *grpc.GetMetricsRequest) (*grpc.Metrics, error) {
 	return nil, nil
 }
+
+func (n *mockAgent) getGuestStatus(ctx context.Context) (*grpc.GuestStatus, error) {
+	return &grpc.GuestStatus{}, nil
+}
+
+func (n *mockAgent) setNetworkPolicy(ctx context.Context, ruleset string) error {
+	return nil
+}
+
+func (n *mockAgent) setLogLevel(ctx context.Context, level string) error {
+	return nil
+}
+
+func (n *mockAgent) setDebugConsole(ctx context.Context, enable bool) error {
+	return nil
+}
@@ -18,6 +18,9 @@ var MockHybridVSockPath = "/tmp/kata-mock-hybrid-vsock.socket"
 
 type mockHypervisor struct {
 	mockPid int
+	// mockAuxPids are the pids of the processes of the driver other
+	// than the VMM
+	mockAuxPids []int
 }
 
 func (m *mockHypervisor) capabilities(ctx context.Context) types.Capabilities {
@@ -108,7 +111,7 @@ func (m *mockHypervisor) cleanup(ctx context.Context) error {
 }
 
 func (m *mockHypervisor) getPids() []int {
-	return []int{m.mockPid}
+	return append([]int{m.mockPid}, m.mockAuxPids...)
 }
 
 func (m *mockHypervisor) getVirtioFsPid() *int {
@@ -7,6 +7,7 @@ package virtcontainers
 
 import (
 	"context"
+	"fmt"
 	"sync"
 	"time"
 
@@ -27,6 +28,13 @@ type monitor struct {
 	wg            sync.WaitGroup
 	running       bool
 	stopCh        chan bool
+
+	// componentPids holds the last known pid of the sandbox components,
+	// used to detect the restarts of a component.
+	componentPids map[string]int
+
+	// lastHeartbeat is the time of the last successful check of the agent.
+	lastHeartbeat time.Time
 }
 
 func newMonitor(s *Sandbox) *monitor {
@@ -34,6 +42,7 @@ func newMonitor(s *Sandbox) *monitor {
 		sandbox:       s,
 		checkInterval: defaultCheckInterval,
 		stopCh:        make(chan bool, 1),
+		componentPids: make(map[string]int),
 	}
 }
 
@@ -60,6 +69,7 @@ func (m *monitor) newWatcher(ctx context.Context) (chan error, error) {
 				case <-tick.C:
 					m.watchHypervisor(ctx)
 					m.watchAgent(ctx)
+					m.watchComponents()
 				}
 			}
 		}()
@@ -70,6 +80,7 @@ func (m *monitor) newWatcher(ctx context.Context) (chan error, error) {
 
 func (m *monitor) notify(ctx context.Context, err error) {
 	m.sandbox.agent.markDead(ctx)
+	m.sandbox.recordEvent(sandboxEventFailure, err.Error())
 
 	m.Lock()
 	defer m.Unlock()
@@ -133,7 +144,21 @@ func (m *monitor) watchAgent(ctx context.Context) {
 	if err != nil {
 		// TODO: define and export error types
 		m.notify(ctx, errors.Wrapf(err, "failed to ping agent"))
+		return
 	}
+
+	m.Lock()
+	m.lastHeartbeat = time.Now()
+	m.Unlock()
+}
+
+// heartbeat returns the time of the last successful check of the agent,
+// zero if the agent was never checked.
+func (m *monitor) heartbeat() time.Time {
+	m.Lock()
+	defer m.Unlock()
+
+	return m.lastHeartbeat
 }
 
 func (m *monitor) watchHypervisor(ctx context.Context) error {
@@ -143,3 +168,27 @@ func (m *monitor) watchHypervisor(ctx context.Context) error {
 	}
 	return nil
 }
+
+// watchComponents updates the liveness metrics of the processes the sandbox
+// relies on. It does not notify the watchers, as the hypervisor and agent
+// checks already do it when the sandbox is not usable anymore.
+func (m *monitor) watchComponents() {
+	for _, c := range m.sandbox.components() {
+		up := 0.0
+		if c.alive() {
+			up = 1
+		}
+		m.sandbox.metrics().componentUp.WithLabelValues(c.component, c.name).Set(up)
+
+		if c.pid <= 0 {
+			continue
+		}
+
+		key := c.component + "/" + c.name
+		if lastPid, found := m.componentPids[key]; found && lastPid != c.pid {
+			m.sandbox.metrics().componentRestarts.WithLabelValues(c.component, c.name).Inc()
+			m.sandbox.recordEvent(sandboxEventComponentRestart, fmt.Sprintf("%s restarted, pid %d", key, c.pid))
+		}
+		m.componentPids[key] = c.pid
+	}
+}
@@ -8,8 +8,11 @@ package virtcontainers
 import (
 	"context"
 	"errors"
+	"os"
 	"testing"
 
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -59,3 +62,47 @@ func TestMonitorClosedChannel(t *testing.T) {
 
 	m.stop()
 }
+
+func gaugeValue(g prometheus.Gauge) float64 {
+	m := &dto.Metric{}
+	g.Write(m)
+	return m.GetGauge().GetValue()
+}
+
+func counterValue(c prometheus.Counter) float64 {
+	m := &dto.Metric{}
+	c.Write(m)
+	return m.GetCounter().GetValue()
+}
+
+func TestMonitorWatchComponents(t *testing.T) {
+	contID := "505"
+	contConfig := newTestContainerConfigNoop(contID)
+	hConfig := newHypervisorConfig(nil, nil)
+	assert := assert.New(t)
+
+	// create a sandbox
+	s, err := testCreateSandbox(t, testSandboxID, MockHypervisor, hConfig, NetworkConfig{}, []ContainerConfig{contConfig}, nil)
+	assert.NoError(err)
+	defer cleanUp()
+
+	m := newMonitor(s)
+	h := s.hypervisor.(*mockHypervisor)
+	name := string(MockHypervisor)
+	metrics := s.metrics()
+
+	h.mockPid = os.Getpid()
+	m.watchComponents()
+	assert.Equal(1.0, gaugeValue(metrics.componentUp.WithLabelValues(componentHypervisor, name)))
+	restarts := counterValue(metrics.componentRestarts.WithLabelValues(componentHypervisor, name))
+
+	// a new pid means the component has been restarted
+	h.mockPid = os.Getppid()
+	m.watchComponents()
+	assert.Equal(restarts+1, counterValue(metrics.componentRestarts.WithLabelValues(componentHypervisor, name)))
+
+	h.mockPid = 0
+	m.watchComponents()
+	assert.Equal(0.0, gaugeValue(metrics.componentUp.WithLabelValues(componentHypervisor, name)))
+	assert.Equal(restarts+1, counterValue(metrics.componentRestarts.WithLabelValues(componentHypervisor, name)))
+}
@@ -426,7 +426,8 @@ const (
 )
 
 // IsEphemeralStorage returns true if the given path
-// to the storage belongs to kubernetes ephemeral storage
+// to the storage belongs to kubernetes ephemeral storage,
+// backed by memory or by hugepages
 //
 // This method depends on a specific path used by k8s
 // to detect if it's of type ephemeral. As of now,
@@ -439,7 +440,7 @@ func IsEphemeralStorage(path string) bool {
 		return false
 	}
 
-	if _, fsType, _ := utils.GetDevicePathAndFsType(path); fsType == "tmpfs" {
+	if _, fsType, _ := utils.GetDevicePathAndFsType(path); fsType == "tmpfs" || fsType == hugetlbfsType {
 		return true
 	}
 
@@ -454,7 +455,7 @@ func Isk8sHostEmptyDir(path string) bool {
 		return false
 	}
 
-	if _, fsType, _ := utils.GetDevicePathAndFsType(path); fsType != "tmpfs" {
+	if _, fsType, _ := utils.GetDevicePathAndFsType(path); fsType != "tmpfs" && fsType != hugetlbfsType {
 		return true
 	}
 	return false
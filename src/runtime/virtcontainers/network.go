@@ -183,6 +183,10 @@ type NetworkConfig struct {
 	DisableNewNetNs   bool
 	NetmonConfig      NetmonConfig
 	InterworkingModel NetInterworkingModel
+
+	// StaticConfig is the network configuration applied without scanning
+	// the network namespace, when set.
+	StaticConfig *StaticNetworkConfig
 }
 
 func networkLogger() *logrus.Entry {
@@ -1335,7 +1339,13 @@ func (n *Network) Add(ctx context.Context, config *NetworkConfig, s *Sandbox, ho
 	katatrace.AddTag(span, "type", config.InterworkingModel.GetModel())
 	defer span.End()
 
-	endpoints, err := createEndpointsFromScan(config.NetNSPath, config)
+	var endpoints []Endpoint
+	var err error
+	if config.StaticConfig != nil {
+		endpoints, err = createEndpointsFromStaticConfig(config.NetNSPath, config)
+	} else {
+		endpoints, err = createEndpointsFromScan(config.NetNSPath, config)
+	}
 	if err != nil {
 		return endpoints, err
 	}
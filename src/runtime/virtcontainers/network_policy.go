@@ -0,0 +1,43 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+)
+
+// SetNetworkPolicy replaces the network policy enforced by the agent in the
+// guest, on top of the host enforcement. The ruleset is the body of an
+// nftables table of the guest, i.e. its sets and chains, e.g. derived from
+// the Kubernetes NetworkPolicy objects of the pod by an external controller.
+// An empty ruleset removes the policy.
+func (s *Sandbox) SetNetworkPolicy(ctx context.Context, ruleset string) error {
+	s.networkPolicyLock.Lock()
+	defer s.networkPolicyLock.Unlock()
+
+	err := s.agent.setNetworkPolicy(ctx, ruleset)
+	s.audit.record(ctx, AuditNetworkUpdate, map[string]interface{}{
+		"action": "set_network_policy",
+		"digest": fmt.Sprintf("sha256:%x", sha256.Sum256([]byte(ruleset))),
+	}, err)
+	if err != nil {
+		return err
+	}
+
+	s.networkPolicy = ruleset
+
+	return nil
+}
+
+// GetNetworkPolicy returns the network policy enforced in the guest.
+func (s *Sandbox) GetNetworkPolicy() string {
+	s.networkPolicyLock.Lock()
+	defer s.networkPolicyLock.Unlock()
+
+	return s.networkPolicy
+}
@@ -0,0 +1,43 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetNetworkPolicy(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "network-policy")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	s := &Sandbox{
+		agent: &mockAgent{},
+		audit: newAuditLog("sb", dir, AuditConfig{Enable: true}),
+	}
+	assert.Empty(s.GetNetworkPolicy())
+
+	ruleset := "chain input { type filter hook input priority 0; policy drop; }"
+	assert.NoError(s.SetNetworkPolicy(context.Background(), ruleset))
+	assert.Equal(ruleset, s.GetNetworkPolicy())
+
+	assert.NoError(s.SetNetworkPolicy(context.Background(), ""))
+	assert.Empty(s.GetNetworkPolicy())
+
+	records, err := s.audit.records()
+	assert.NoError(err)
+	assert.Len(records, 2)
+	assert.Equal(AuditNetworkUpdate, records[0].Operation)
+	assert.Equal("set_network_policy", records[0].Params["action"])
+	assert.Contains(records[0].Params["digest"], "sha256:")
+}
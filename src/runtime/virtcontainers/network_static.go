@@ -0,0 +1,269 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/containernetworking/plugins/pkg/ns"
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netns"
+)
+
+// StaticNetworkInterface is a guest interface of the static network
+// configuration, connected to a link of the network namespace.
+type StaticNetworkInterface struct {
+	// Name is the name of the link in the network namespace, and of the
+	// guest interface.
+	Name string `json:"name"`
+
+	// HwAddr is the MAC address of the guest interface, set on the link
+	// when it differs. The MAC address of the link is used when empty.
+	HwAddr string `json:"hw_addr,omitempty"`
+
+	// IPAddresses are the addresses of the interface, in CIDR notation.
+	IPAddresses []string `json:"ip_addresses"`
+
+	// MTU is set on the link when not zero.
+	MTU int `json:"mtu,omitempty"`
+
+	// VhostUserSocket is the path of the vhost-user socket of the interface,
+	// e.g. the OVS-DPDK or VPP port of the CNI result. The interface has no
+	// link in the network namespace, its MAC address must be set.
+	VhostUserSocket string `json:"vhost_user_socket,omitempty"`
+}
+
+// StaticNetworkRoute is a guest route of the static network configuration.
+type StaticNetworkRoute struct {
+	// Dest is the destination in CIDR notation, the default route when empty.
+	Dest    string `json:"dest,omitempty"`
+	Gateway string `json:"gateway,omitempty"`
+	Source  string `json:"source,omitempty"`
+
+	// Device is the name of the interface of the route.
+	Device string `json:"device"`
+}
+
+// StaticNetworkConfig is the network configuration of a sandbox computed by
+// the caller, e.g. a VM first orchestrator or a CNI plugin. It is applied as
+// is, the network namespace is not scanned for the interfaces and the
+// addresses.
+type StaticNetworkConfig struct {
+	Interfaces []StaticNetworkInterface `json:"interfaces"`
+	Routes     []StaticNetworkRoute     `json:"routes,omitempty"`
+}
+
+// Validate checks the static network configuration is consistent.
+func (c *StaticNetworkConfig) Validate() error {
+	_, err := c.networkInfos()
+	return err
+}
+
+func parseStaticRoute(r StaticNetworkRoute) (netlink.Route, error) {
+	var route netlink.Route
+
+	if r.Dest != "" {
+		_, dst, err := net.ParseCIDR(r.Dest)
+		if err != nil {
+			return route, fmt.Errorf("invalid destination of static route: %v", err)
+		}
+		route.Dst = dst
+	}
+
+	if r.Gateway != "" {
+		if route.Gw = net.ParseIP(r.Gateway); route.Gw == nil {
+			return route, fmt.Errorf("invalid gateway %q of static route", r.Gateway)
+		}
+	}
+
+	if r.Source != "" {
+		if route.Src = net.ParseIP(r.Source); route.Src == nil {
+			return route, fmt.Errorf("invalid source %q of static route", r.Source)
+		}
+	}
+
+	// the routes without gateway reach their destination directly
+	route.Scope = netlink.SCOPE_UNIVERSE
+	if route.Gw == nil {
+		if route.Dst == nil {
+			return route, fmt.Errorf("static default route of %s without gateway", r.Device)
+		}
+		route.Scope = netlink.SCOPE_LINK
+	}
+
+	return route, nil
+}
+
+// networkInfos returns the network information of the interfaces, in the
+// order of the configuration, without their link attributes.
+func (c *StaticNetworkConfig) networkInfos() ([]NetworkInfo, error) {
+	if len(c.Interfaces) == 0 {
+		return nil, fmt.Errorf("no interface in the static network configuration")
+	}
+
+	infos := make([]NetworkInfo, len(c.Interfaces))
+	index := make(map[string]int)
+
+	for i, iface := range c.Interfaces {
+		if iface.Name == "" {
+			return nil, fmt.Errorf("static network interface %d has no name", i)
+		}
+		if _, found := index[iface.Name]; found {
+			return nil, fmt.Errorf("duplicate static network interface %s", iface.Name)
+		}
+		index[iface.Name] = i
+
+		if iface.HwAddr != "" {
+			hwAddr, err := net.ParseMAC(iface.HwAddr)
+			if err != nil {
+				return nil, fmt.Errorf("invalid MAC address of static network interface %s: %v", iface.Name, err)
+			}
+			infos[i].Iface.HardwareAddr = hwAddr
+		}
+
+		if iface.VhostUserSocket != "" {
+			if !filepath.IsAbs(iface.VhostUserSocket) {
+				return nil, fmt.Errorf("vhost-user socket %s of static network interface %s is not an absolute path", iface.VhostUserSocket, iface.Name)
+			}
+			if iface.HwAddr == "" {
+				return nil, fmt.Errorf("vhost-user static network interface %s has no MAC address", iface.Name)
+			}
+		}
+
+		if iface.MTU < 0 {
+			return nil, fmt.Errorf("invalid MTU %d of static network interface %s", iface.MTU, iface.Name)
+		}
+
+		if len(iface.IPAddresses) == 0 {
+			return nil, fmt.Errorf("static network interface %s has no IP address", iface.Name)
+		}
+
+		for _, a := range iface.IPAddresses {
+			addr, err := netlink.ParseAddr(a)
+			if err != nil {
+				return nil, fmt.Errorf("invalid IP address of static network interface %s: %v", iface.Name, err)
+			}
+			infos[i].Addrs = append(infos[i].Addrs, *addr)
+		}
+	}
+
+	for _, r := range c.Routes {
+		i, found := index[r.Device]
+		if !found {
+			return nil, fmt.Errorf("unknown device %q of static route", r.Device)
+		}
+
+		route, err := parseStaticRoute(r)
+		if err != nil {
+			return nil, err
+		}
+
+		infos[i].Routes = append(infos[i].Routes, route)
+	}
+
+	return infos, nil
+}
+
+// createEndpointsFromStaticConfig creates the endpoints of the links of the
+// static network configuration, the addresses and routes of the guest
+// interfaces are the ones of the configuration.
+func createEndpointsFromStaticConfig(networkNSPath string, config *NetworkConfig) ([]Endpoint, error) {
+	netInfos, err := config.StaticConfig.networkInfos()
+	if err != nil {
+		return []Endpoint{}, err
+	}
+
+	netnsHandle, err := netns.GetFromPath(networkNSPath)
+	if err != nil {
+		return []Endpoint{}, err
+	}
+	defer netnsHandle.Close()
+
+	netlinkHandle, err := netlink.NewHandleAt(netnsHandle)
+	if err != nil {
+		return []Endpoint{}, err
+	}
+	defer netlinkHandle.Delete()
+
+	var endpoints []Endpoint
+
+	for idx, iface := range config.StaticConfig.Interfaces {
+		var (
+			endpoint  Endpoint
+			errCreate error
+		)
+
+		netInfo := netInfos[idx]
+
+		// the vhost-user ports have no link in the network namespace
+		if iface.VhostUserSocket != "" {
+			if fi, err := os.Stat(iface.VhostUserSocket); err != nil || fi.Mode()&os.ModeSocket == 0 {
+				return []Endpoint{}, fmt.Errorf("invalid vhost-user socket %s of static network interface %s", iface.VhostUserSocket, iface.Name)
+			}
+
+			netInfo.Iface.Name = iface.Name
+			netInfo.Iface.MTU = iface.MTU
+			vhostUserEndpoint, err := createVhostUserEndpoint(netInfo, iface.VhostUserSocket)
+			if err != nil {
+				return []Endpoint{}, err
+			}
+
+			vhostUserEndpoint.SetProperties(netInfo)
+			endpoints = append(endpoints, vhostUserEndpoint)
+			continue
+		}
+
+		link, err := netlinkHandle.LinkByName(iface.Name)
+		if err != nil {
+			return []Endpoint{}, fmt.Errorf("static network interface %s: %v", iface.Name, err)
+		}
+
+		// the guest interface takes the MAC address of the link
+		if hwAddr := netInfo.Iface.HardwareAddr; hwAddr != nil && !bytes.Equal(hwAddr, link.Attrs().HardwareAddr) {
+			if err := netlinkHandle.LinkSetHardwareAddr(link, hwAddr); err != nil {
+				return []Endpoint{}, fmt.Errorf("could not set MAC address %s of static network interface %s: %v", hwAddr, iface.Name, err)
+			}
+		}
+
+		if iface.MTU != 0 && iface.MTU != link.Attrs().MTU {
+			if err := netlinkHandle.LinkSetMTU(link, iface.MTU); err != nil {
+				return []Endpoint{}, fmt.Errorf("could not set MTU %d of static network interface %s: %v", iface.MTU, iface.Name, err)
+			}
+		}
+
+		// read the link again for its updated attributes
+		if link, err = netlinkHandle.LinkByName(iface.Name); err != nil {
+			return []Endpoint{}, err
+		}
+		netInfo.Iface = NetlinkIface{
+			LinkAttrs: *(link.Attrs()),
+			Type:      link.Type(),
+		}
+
+		if err := doNetNS(networkNSPath, func(_ ns.NetNS) error {
+			endpoint, errCreate = createEndpoint(netInfo, idx, config.InterworkingModel, link)
+			return errCreate
+		}); err != nil {
+			return []Endpoint{}, err
+		}
+
+		endpoint.SetProperties(netInfo)
+		endpoints = append(endpoints, endpoint)
+	}
+
+	sort.Slice(endpoints, func(i, j int) bool {
+		return endpoints[i].Name() < endpoints[j].Name()
+	})
+
+	networkLogger().WithField("endpoints", endpoints).Info("Endpoints created from the static configuration")
+
+	return endpoints, nil
+}
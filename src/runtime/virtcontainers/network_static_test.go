@@ -0,0 +1,182 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/containernetworking/plugins/pkg/testutils"
+	ktu "github.com/kata-containers/kata-containers/src/runtime/pkg/katatestutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netns"
+)
+
+func testStaticNetworkConfig() *StaticNetworkConfig {
+	return &StaticNetworkConfig{
+		Interfaces: []StaticNetworkInterface{
+			{
+				Name:        "eth0",
+				HwAddr:      "02:00:00:00:00:01",
+				IPAddresses: []string{"10.0.0.2/24", "fd00::2/64"},
+				MTU:         1450,
+			},
+		},
+		Routes: []StaticNetworkRoute{
+			{Dest: "192.168.0.0/16", Device: "eth0"},
+			{Gateway: "10.0.0.1", Device: "eth0"},
+		},
+	}
+}
+
+func TestStaticNetworkConfigValidate(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.NoError(testStaticNetworkConfig().Validate())
+
+	invalid := []func(c *StaticNetworkConfig){
+		func(c *StaticNetworkConfig) { c.Interfaces = nil },
+		func(c *StaticNetworkConfig) { c.Interfaces[0].Name = "" },
+		func(c *StaticNetworkConfig) { c.Interfaces = append(c.Interfaces, c.Interfaces[0]) },
+		func(c *StaticNetworkConfig) { c.Interfaces[0].HwAddr = "foo" },
+		func(c *StaticNetworkConfig) { c.Interfaces[0].MTU = -1 },
+		func(c *StaticNetworkConfig) { c.Interfaces[0].IPAddresses = nil },
+		func(c *StaticNetworkConfig) { c.Interfaces[0].IPAddresses = []string{"10.0.0.2"} },
+		func(c *StaticNetworkConfig) { c.Routes[0].Device = "eth1" },
+		func(c *StaticNetworkConfig) { c.Routes[0].Dest = "192.168.0.0" },
+		func(c *StaticNetworkConfig) { c.Routes[1].Gateway = "foo" },
+		func(c *StaticNetworkConfig) { c.Routes[1].Source = "foo" },
+		// default route without gateway
+		func(c *StaticNetworkConfig) { c.Routes[1].Gateway = "" },
+		func(c *StaticNetworkConfig) { c.Interfaces[0].VhostUserSocket = "vhu.sock" },
+		func(c *StaticNetworkConfig) {
+			c.Interfaces[0].VhostUserSocket = "/run/vhu.sock"
+			c.Interfaces[0].HwAddr = ""
+		},
+	}
+
+	for i, update := range invalid {
+		c := testStaticNetworkConfig()
+		update(c)
+		assert.Error(c.Validate(), "test %d", i)
+	}
+}
+
+func TestStaticNetworkInfos(t *testing.T) {
+	assert := assert.New(t)
+
+	infos, err := testStaticNetworkConfig().networkInfos()
+	assert.NoError(err)
+	assert.Len(infos, 1)
+
+	info := infos[0]
+	assert.Equal("02:00:00:00:00:01", info.Iface.HardwareAddr.String())
+	assert.Len(info.Addrs, 2)
+	assert.Equal("10.0.0.2/24", info.Addrs[0].IPNet.String())
+
+	assert.Len(info.Routes, 2)
+	assert.Equal("192.168.0.0/16", info.Routes[0].Dst.String())
+	assert.Equal(netlink.SCOPE_LINK, info.Routes[0].Scope)
+	assert.Nil(info.Routes[1].Dst)
+	assert.Equal("10.0.0.1", info.Routes[1].Gw.String())
+	assert.Equal(netlink.SCOPE_UNIVERSE, info.Routes[1].Scope)
+}
+
+func TestCreateEndpointsFromStaticConfig(t *testing.T) {
+	if tc.NotValid(ktu.NeedRoot()) {
+		t.Skip(testDisabledAsNonRoot)
+	}
+
+	assert := assert.New(t)
+
+	n, err := testutils.NewNS()
+	assert.NoError(err)
+	defer n.Close()
+
+	netnsHandle, err := netns.GetFromPath(n.Path())
+	assert.NoError(err)
+	defer netnsHandle.Close()
+
+	netlinkHandle, err := netlink.NewHandleAt(netnsHandle)
+	assert.NoError(err)
+	defer netlinkHandle.Delete()
+
+	// the addresses of the link are not the ones of the guest interface
+	veth := &netlink.Veth{LinkAttrs: netlink.LinkAttrs{Name: "eth0", MTU: 1500}, PeerName: "eth0-peer"}
+	assert.NoError(netlinkHandle.LinkAdd(veth))
+
+	config := &NetworkConfig{
+		NetNSPath:         n.Path(),
+		InterworkingModel: NetXConnectTCFilterModel,
+		StaticConfig:      testStaticNetworkConfig(),
+	}
+
+	endpoints, err := createEndpointsFromStaticConfig(n.Path(), config)
+	assert.NoError(err)
+	assert.Len(endpoints, 1)
+	assert.Equal(VethEndpointType, endpoints[0].Type())
+
+	// the MAC address and the MTU are set on the link
+	link, err := netlinkHandle.LinkByName("eth0")
+	assert.NoError(err)
+	assert.Equal("02:00:00:00:00:01", link.Attrs().HardwareAddr.String())
+	assert.Equal(1450, link.Attrs().MTU)
+
+	ifaces, routes, _, err := generateVCNetworkStructures(context.Background(), NetworkNamespace{
+		NetNsPath: n.Path(),
+		Endpoints: endpoints,
+	})
+	assert.NoError(err)
+	assert.Len(ifaces, 1)
+	assert.Equal(uint64(1450), ifaces[0].Mtu)
+	assert.Len(ifaces[0].IPAddresses, 2)
+	assert.Len(routes, 2)
+	assert.Equal(net.ParseIP("10.0.0.1").String(), routes[1].Gateway)
+
+	// the vhost-user ports have no link
+	dir, err := ioutil.TempDir("", "vhost-user")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	socket := filepath.Join(dir, "vhu.sock")
+	l, err := net.Listen("unix", socket)
+	assert.NoError(err)
+	defer l.Close()
+
+	config.StaticConfig.Interfaces = append(config.StaticConfig.Interfaces, StaticNetworkInterface{
+		Name:            "dpdk0",
+		HwAddr:          "02:00:00:00:00:02",
+		IPAddresses:     []string{"10.0.1.2/24"},
+		VhostUserSocket: socket,
+	})
+	endpoints, err = createEndpointsFromStaticConfig(n.Path(), config)
+	assert.NoError(err)
+	assert.Len(endpoints, 2)
+	assert.Equal(VhostUserEndpointType, endpoints[0].Type())
+	assert.Equal("dpdk0", endpoints[0].Name())
+	assert.Equal("02:00:00:00:00:02", endpoints[0].HardwareAddr())
+	assert.Equal(socket, endpoints[0].(*VhostUserEndpoint).SocketPath)
+
+	config.StaticConfig.Interfaces[1].VhostUserSocket = filepath.Join(dir, "missing.sock")
+	_, err = createEndpointsFromStaticConfig(n.Path(), config)
+	assert.Error(err)
+	config.StaticConfig.Interfaces = config.StaticConfig.Interfaces[:1]
+
+	// the links are not scanned
+	config.StaticConfig.Interfaces[0].Name = "eth0-peer"
+	config.StaticConfig.Routes = nil
+	config.StaticConfig.Interfaces = append(config.StaticConfig.Interfaces, StaticNetworkInterface{
+		Name:        "missing",
+		IPAddresses: []string{"10.0.1.2/24"},
+	})
+	_, err = createEndpointsFromStaticConfig(n.Path(), config)
+	assert.Error(err)
+}
@@ -35,6 +35,7 @@ func (s *Sandbox) dumpState(ss *persistapi.SandboxState, cs map[string]persistap
 	ss.State = string(s.state.State)
 	ss.CgroupPath = s.state.CgroupPath
 	ss.CgroupPaths = s.state.CgroupPaths
+	ss.SandboxDevices = s.state.SandboxDevices
 
 	for id, cont := range s.containers {
 		state := persistapi.ContainerState{}
@@ -47,6 +48,7 @@ func (s *Sandbox) dumpState(ss *persistapi.SandboxState, cs map[string]persistap
 			FsType:        cont.state.Fstype,
 		}
 		state.CgroupPath = cont.state.CgroupPath
+		state.CreationDeferred = cont.state.CreationDeferred
 		cs[id] = state
 	}
 
@@ -172,6 +174,45 @@ func (s *Sandbox) dumpNetwork(ss *persistapi.SandboxState) {
 	}
 }
 
+func dumpStaticNetworkConfig(c *StaticNetworkConfig) *persistapi.StaticNetworkConfig {
+	if c == nil {
+		return nil
+	}
+
+	saved := &persistapi.StaticNetworkConfig{}
+	for _, i := range c.Interfaces {
+		saved.Interfaces = append(saved.Interfaces, persistapi.StaticNetworkInterface{
+			Name:            i.Name,
+			HwAddr:          i.HwAddr,
+			IPAddresses:     i.IPAddresses,
+			MTU:             i.MTU,
+			VhostUserSocket: i.VhostUserSocket,
+		})
+	}
+	for _, r := range c.Routes {
+		saved.Routes = append(saved.Routes, persistapi.StaticNetworkRoute{
+			Dest:    r.Dest,
+			Gateway: r.Gateway,
+			Source:  r.Source,
+			Device:  r.Device,
+		})
+	}
+
+	return saved
+}
+
+func dumpNUMANodes(nodes []NUMANode) []persistapi.NUMANode {
+	var saved []persistapi.NUMANode
+	for _, n := range nodes {
+		saved = append(saved, persistapi.NUMANode{
+			VCPUs:      n.VCPUs,
+			MemorySize: n.MemorySize,
+		})
+	}
+
+	return saved
+}
+
 func (s *Sandbox) dumpConfig(ss *persistapi.SandboxState) {
 	sconfig := s.config
 	ss.Config = persistapi.SandboxConfig{
@@ -181,87 +222,161 @@ func (s *Sandbox) dumpConfig(ss *persistapi.SandboxState) {
 			NetNsCreated:      sconfig.NetworkConfig.NetNsCreated,
 			DisableNewNetNs:   sconfig.NetworkConfig.DisableNewNetNs,
 			InterworkingModel: int(sconfig.NetworkConfig.InterworkingModel),
+			StaticConfig:      dumpStaticNetworkConfig(sconfig.NetworkConfig.StaticConfig),
 		},
 
-		ShmSize:             sconfig.ShmSize,
-		SharePidNs:          sconfig.SharePidNs,
-		SystemdCgroup:       sconfig.SystemdCgroup,
-		SandboxCgroupOnly:   sconfig.SandboxCgroupOnly,
-		DisableGuestSeccomp: sconfig.DisableGuestSeccomp,
-		Cgroups:             sconfig.Cgroups,
+		ShmSize:              sconfig.ShmSize,
+		SharePidNs:           sconfig.SharePidNs,
+		ContainerParallelism: sconfig.ContainerParallelism,
+		SystemdCgroup:        sconfig.SystemdCgroup,
+		SandboxCgroupOnly:    sconfig.SandboxCgroupOnly,
+		CPUQuotaPolicy:       string(sconfig.CPUQuotaPolicy),
+		DisableGuestSeccomp:  sconfig.DisableGuestSeccomp,
+		GuestOCIHooks:        sconfig.GuestOCIHooks,
+		Cgroups:              sconfig.Cgroups,
+		AuditConfig: persistapi.AuditConfig{
+			Enable: sconfig.AuditConfig.Enable,
+			Dir:    sconfig.AuditConfig.Dir,
+			Sink:   sconfig.AuditConfig.Sink,
+		},
+		EphemeralDiskConfig: persistapi.EphemeralDiskConfig{
+			Backend:     sconfig.EphemeralDiskConfig.Backend,
+			VolumeGroup: sconfig.EphemeralDiskConfig.VolumeGroup,
+			Dir:         sconfig.EphemeralDiskConfig.Dir,
+			SizeMB:      sconfig.EphemeralDiskConfig.SizeMB,
+			MaxSizeMB:   sconfig.EphemeralDiskConfig.MaxSizeMB,
+			Encrypt:     sconfig.EphemeralDiskConfig.Encrypt,
+		},
+		RootfsDedup:         sconfig.RootfsDedup,
+		RootfsDedupDir:      sconfig.RootfsDedupDir,
+		DeviceReuseTimeout:  sconfig.DeviceReuseTimeout,
+		DevicePluginClasses: sconfig.DevicePluginClasses,
+		InitDataConfig: persistapi.InitDataConfig{
+			Method:       sconfig.InitDataConfig.Method,
+			Certificates: sconfig.InitDataConfig.Certificates,
+			HTTPProxy:    sconfig.InitDataConfig.HTTPProxy,
+			HTTPSProxy:   sconfig.InitDataConfig.HTTPSProxy,
+			NoProxy:      sconfig.InitDataConfig.NoProxy,
+		},
+		GuestSwapConfig: persistapi.GuestSwapConfig{
+			SizeMB:     sconfig.GuestSwapConfig.SizeMB,
+			MaxSizeMB:  sconfig.GuestSwapConfig.MaxSizeMB,
+			Swappiness: sconfig.GuestSwapConfig.Swappiness,
+			Dir:        sconfig.GuestSwapConfig.Dir,
+		},
 	}
 
 	ss.Config.SandboxBindMounts = append(ss.Config.SandboxBindMounts, sconfig.SandboxBindMounts...)
 
+	for _, r := range sconfig.SharedMemoryConfig.Regions {
+		ss.Config.SharedMemoryConfig.Regions = append(ss.Config.SharedMemoryConfig.Regions, persistapi.SharedMemoryRegion{
+			Name: r.Name,
+			Size: r.Size,
+		})
+	}
+	ss.Config.SharedMemoryConfig.ValidRegions = append(ss.Config.SharedMemoryConfig.ValidRegions, sconfig.SharedMemoryConfig.ValidRegions...)
+	ss.Config.SharedMemoryConfig.MaxRegionSizeMB = sconfig.SharedMemoryConfig.MaxRegionSizeMB
+	ss.Config.SharedMemoryConfig.MaxSizeMB = sconfig.SharedMemoryConfig.MaxSizeMB
+
 	for _, e := range sconfig.Experimental {
 		ss.Config.Experimental = append(ss.Config.Experimental, e.Name)
 	}
 
 	ss.Config.HypervisorConfig = persistapi.HypervisorConfig{
-		NumVCPUs:                sconfig.HypervisorConfig.NumVCPUs,
-		DefaultMaxVCPUs:         sconfig.HypervisorConfig.DefaultMaxVCPUs,
-		MemorySize:              sconfig.HypervisorConfig.MemorySize,
-		DefaultBridges:          sconfig.HypervisorConfig.DefaultBridges,
-		Msize9p:                 sconfig.HypervisorConfig.Msize9p,
-		MemSlots:                sconfig.HypervisorConfig.MemSlots,
-		MemOffset:               sconfig.HypervisorConfig.MemOffset,
-		VirtioMem:               sconfig.HypervisorConfig.VirtioMem,
-		VirtioFSCacheSize:       sconfig.HypervisorConfig.VirtioFSCacheSize,
-		KernelPath:              sconfig.HypervisorConfig.KernelPath,
-		ImagePath:               sconfig.HypervisorConfig.ImagePath,
-		InitrdPath:              sconfig.HypervisorConfig.InitrdPath,
-		FirmwarePath:            sconfig.HypervisorConfig.FirmwarePath,
-		MachineAccelerators:     sconfig.HypervisorConfig.MachineAccelerators,
-		CPUFeatures:             sconfig.HypervisorConfig.CPUFeatures,
-		HypervisorPath:          sconfig.HypervisorConfig.HypervisorPath,
-		HypervisorPathList:      sconfig.HypervisorConfig.HypervisorPathList,
-		HypervisorCtlPath:       sconfig.HypervisorConfig.HypervisorCtlPath,
-		HypervisorCtlPathList:   sconfig.HypervisorConfig.HypervisorCtlPathList,
-		JailerPath:              sconfig.HypervisorConfig.JailerPath,
-		JailerPathList:          sconfig.HypervisorConfig.JailerPathList,
-		BlockDeviceDriver:       sconfig.HypervisorConfig.BlockDeviceDriver,
-		HypervisorMachineType:   sconfig.HypervisorConfig.HypervisorMachineType,
-		MemoryPath:              sconfig.HypervisorConfig.MemoryPath,
-		DevicesStatePath:        sconfig.HypervisorConfig.DevicesStatePath,
-		EntropySource:           sconfig.HypervisorConfig.EntropySource,
-		EntropySourceList:       sconfig.HypervisorConfig.EntropySourceList,
-		SharedFS:                sconfig.HypervisorConfig.SharedFS,
-		VirtioFSDaemon:          sconfig.HypervisorConfig.VirtioFSDaemon,
-		VirtioFSDaemonList:      sconfig.HypervisorConfig.VirtioFSDaemonList,
-		VirtioFSCache:           sconfig.HypervisorConfig.VirtioFSCache,
-		VirtioFSExtraArgs:       sconfig.HypervisorConfig.VirtioFSExtraArgs[:],
-		BlockDeviceCacheSet:     sconfig.HypervisorConfig.BlockDeviceCacheSet,
-		BlockDeviceCacheDirect:  sconfig.HypervisorConfig.BlockDeviceCacheDirect,
-		BlockDeviceCacheNoflush: sconfig.HypervisorConfig.BlockDeviceCacheNoflush,
-		DisableBlockDeviceUse:   sconfig.HypervisorConfig.DisableBlockDeviceUse,
-		EnableIOThreads:         sconfig.HypervisorConfig.EnableIOThreads,
-		Debug:                   sconfig.HypervisorConfig.Debug,
-		MemPrealloc:             sconfig.HypervisorConfig.MemPrealloc,
-		HugePages:               sconfig.HypervisorConfig.HugePages,
-		FileBackedMemRootDir:    sconfig.HypervisorConfig.FileBackedMemRootDir,
-		FileBackedMemRootList:   sconfig.HypervisorConfig.FileBackedMemRootList,
-		Realtime:                sconfig.HypervisorConfig.Realtime,
-		Mlock:                   sconfig.HypervisorConfig.Mlock,
-		DisableNestingChecks:    sconfig.HypervisorConfig.DisableNestingChecks,
-		DisableImageNvdimm:      sconfig.HypervisorConfig.DisableImageNvdimm,
-		HotplugVFIOOnRootBus:    sconfig.HypervisorConfig.HotplugVFIOOnRootBus,
-		PCIeRootPort:            sconfig.HypervisorConfig.PCIeRootPort,
-		BootToBeTemplate:        sconfig.HypervisorConfig.BootToBeTemplate,
-		BootFromTemplate:        sconfig.HypervisorConfig.BootFromTemplate,
-		DisableVhostNet:         sconfig.HypervisorConfig.DisableVhostNet,
-		EnableVhostUserStore:    sconfig.HypervisorConfig.EnableVhostUserStore,
-		VhostUserStorePath:      sconfig.HypervisorConfig.VhostUserStorePath,
-		VhostUserStorePathList:  sconfig.HypervisorConfig.VhostUserStorePathList,
-		GuestHookPath:           sconfig.HypervisorConfig.GuestHookPath,
-		VMid:                    sconfig.HypervisorConfig.VMid,
-		RxRateLimiterMaxRate:    sconfig.HypervisorConfig.RxRateLimiterMaxRate,
-		TxRateLimiterMaxRate:    sconfig.HypervisorConfig.TxRateLimiterMaxRate,
-		SGXEPCSize:              sconfig.HypervisorConfig.SGXEPCSize,
-		EnableAnnotations:       sconfig.HypervisorConfig.EnableAnnotations,
+		NumVCPUs:                  sconfig.HypervisorConfig.NumVCPUs,
+		DefaultMaxVCPUs:           sconfig.HypervisorConfig.DefaultMaxVCPUs,
+		MemorySize:                sconfig.HypervisorConfig.MemorySize,
+		DefaultBridges:            sconfig.HypervisorConfig.DefaultBridges,
+		Msize9p:                   sconfig.HypervisorConfig.Msize9p,
+		MemSlots:                  sconfig.HypervisorConfig.MemSlots,
+		MemOffset:                 sconfig.HypervisorConfig.MemOffset,
+		VirtioMem:                 sconfig.HypervisorConfig.VirtioMem,
+		VirtioFSCacheSize:         sconfig.HypervisorConfig.VirtioFSCacheSize,
+		KernelPath:                sconfig.HypervisorConfig.KernelPath,
+		ImagePath:                 sconfig.HypervisorConfig.ImagePath,
+		InitrdPath:                sconfig.HypervisorConfig.InitrdPath,
+		FirmwarePath:              sconfig.HypervisorConfig.FirmwarePath,
+		MachineAccelerators:       sconfig.HypervisorConfig.MachineAccelerators,
+		CPUFeatures:               sconfig.HypervisorConfig.CPUFeatures,
+		CPUModel:                  sconfig.HypervisorConfig.CPUModel,
+		HypervisorPath:            sconfig.HypervisorConfig.HypervisorPath,
+		HypervisorPathList:        sconfig.HypervisorConfig.HypervisorPathList,
+		HypervisorCtlPath:         sconfig.HypervisorConfig.HypervisorCtlPath,
+		HypervisorCtlPathList:     sconfig.HypervisorConfig.HypervisorCtlPathList,
+		JailerPath:                sconfig.HypervisorConfig.JailerPath,
+		JailerPathList:            sconfig.HypervisorConfig.JailerPathList,
+		BlockDeviceDriver:         sconfig.HypervisorConfig.BlockDeviceDriver,
+		HypervisorMachineType:     sconfig.HypervisorConfig.HypervisorMachineType,
+		MemoryPath:                sconfig.HypervisorConfig.MemoryPath,
+		DevicesStatePath:          sconfig.HypervisorConfig.DevicesStatePath,
+		EntropySource:             sconfig.HypervisorConfig.EntropySource,
+		EntropySourceList:         sconfig.HypervisorConfig.EntropySourceList,
+		RootfsDiskPath:            sconfig.HypervisorConfig.RootfsDiskPath,
+		RootfsDiskFormat:          sconfig.HypervisorConfig.RootfsDiskFormat,
+		RootfsDiskOverlayDir:      sconfig.HypervisorConfig.RootfsDiskOverlayDir,
+		RootfsDiskList:            sconfig.HypervisorConfig.RootfsDiskList,
+		SharedFS:                  sconfig.HypervisorConfig.SharedFS,
+		VirtioFSDaemon:            sconfig.HypervisorConfig.VirtioFSDaemon,
+		VirtioFSDaemonList:        sconfig.HypervisorConfig.VirtioFSDaemonList,
+		VirtioFSCache:             sconfig.HypervisorConfig.VirtioFSCache,
+		VirtioFSExtraArgs:         sconfig.HypervisorConfig.VirtioFSExtraArgs[:],
+		VirtioFSDaemonIDs:         sconfig.HypervisorConfig.VirtioFSDaemonIDs,
+		BlockDeviceCacheSet:       sconfig.HypervisorConfig.BlockDeviceCacheSet,
+		BlockDeviceCacheDirect:    sconfig.HypervisorConfig.BlockDeviceCacheDirect,
+		BlockDeviceCacheNoflush:   sconfig.HypervisorConfig.BlockDeviceCacheNoflush,
+		DisableBlockDeviceUse:     sconfig.HypervisorConfig.DisableBlockDeviceUse,
+		LazyBlockDeviceAttach:     sconfig.HypervisorConfig.LazyBlockDeviceAttach,
+		EnableIOThreads:           sconfig.HypervisorConfig.EnableIOThreads,
+		Debug:                     sconfig.HypervisorConfig.Debug,
+		MemPrealloc:               sconfig.HypervisorConfig.MemPrealloc,
+		HugePages:                 sconfig.HypervisorConfig.HugePages,
+		FileBackedMemRootDir:      sconfig.HypervisorConfig.FileBackedMemRootDir,
+		FileBackedMemRootList:     sconfig.HypervisorConfig.FileBackedMemRootList,
+		Realtime:                  sconfig.HypervisorConfig.Realtime,
+		Mlock:                     sconfig.HypervisorConfig.Mlock,
+		DisableNestingChecks:      sconfig.HypervisorConfig.DisableNestingChecks,
+		DisableImageNvdimm:        sconfig.HypervisorConfig.DisableImageNvdimm,
+		GICVersion:                sconfig.HypervisorConfig.GICVersion,
+		DisableITS:                sconfig.HypervisorConfig.DisableITS,
+		SMTMode:                   sconfig.HypervisorConfig.SMTMode,
+		MMUMode:                   sconfig.HypervisorConfig.MMUMode,
+		MemoryBlockSizeMB:         sconfig.HypervisorConfig.MemoryBlockSizeMB,
+		SEHostKeyDocuments:        sconfig.HypervisorConfig.SEHostKeyDocuments,
+		HotplugVFIOOnRootBus:      sconfig.HypervisorConfig.HotplugVFIOOnRootBus,
+		PCIeRootPort:              sconfig.HypervisorConfig.PCIeRootPort,
+		BootToBeTemplate:          sconfig.HypervisorConfig.BootToBeTemplate,
+		BootFromTemplate:          sconfig.HypervisorConfig.BootFromTemplate,
+		DisableVhostNet:           sconfig.HypervisorConfig.DisableVhostNet,
+		EnableVhostUserStore:      sconfig.HypervisorConfig.EnableVhostUserStore,
+		VhostUserStorePath:        sconfig.HypervisorConfig.VhostUserStorePath,
+		VhostUserStorePathList:    sconfig.HypervisorConfig.VhostUserStorePathList,
+		VhostUserSocketList:       sconfig.HypervisorConfig.VhostUserSocketList,
+		GuestHookPath:             sconfig.HypervisorConfig.GuestHookPath,
+		VMid:                      sconfig.HypervisorConfig.VMid,
+		RxRateLimiterMaxRate:      sconfig.HypervisorConfig.RxRateLimiterMaxRate,
+		TxRateLimiterMaxRate:      sconfig.HypervisorConfig.TxRateLimiterMaxRate,
+		SGXEPCSize:                sconfig.HypervisorConfig.SGXEPCSize,
+		NUMANodes:                 dumpNUMANodes(sconfig.HypervisorConfig.NUMANodes),
+		GuestHugepages:            sconfig.HypervisorConfig.GuestHugepages,
+		EnableAnnotations:         sconfig.HypervisorConfig.EnableAnnotations,
+		VMMSandboxing:             sconfig.HypervisorConfig.VMMSandboxing,
+		VMMSandboxingSyscalls:     sconfig.HypervisorConfig.VMMSandboxingSyscalls,
+		VMMSandboxingCapabilities: sconfig.HypervisorConfig.VMMSandboxingCapabilities,
+		HypervisorIDs:             sconfig.HypervisorConfig.HypervisorIDs,
+		VMMOOMScoreAdj:            sconfig.HypervisorConfig.VMMOOMScoreAdj,
+		VMMNice:                   sconfig.HypervisorConfig.VMMNice,
+		VMMIOClass:                sconfig.HypervisorConfig.VMMIOClass,
+		VMMIOPriority:             sconfig.HypervisorConfig.VMMIOPriority,
+		VCPURealtimePriority:      sconfig.HypervisorConfig.VCPURealtimePriority,
 	}
 
 	ss.Config.KataAgentConfig = &persistapi.KataAgentConfig{
-		LongLiveConn: sconfig.AgentConfig.LongLiveConn,
+		LongLiveConn:       sconfig.AgentConfig.LongLiveConn,
+		PolicyFile:         sconfig.AgentConfig.PolicyFile,
+		AllowedAPIs:        sconfig.AgentConfig.AllowedAPIs,
+		DialTimeout:        sconfig.AgentConfig.DialTimeout,
+		DialBackoffInitial: sconfig.AgentConfig.DialBackoffInitial,
+		DialBackoffMax:     sconfig.AgentConfig.DialBackoffMax,
 	}
 
 	for _, contConf := range sconfig.Containers {
@@ -305,14 +420,16 @@ func (s *Sandbox) loadState(ss persistapi.SandboxState) {
 	s.state.CgroupPath = ss.CgroupPath
 	s.state.CgroupPaths = ss.CgroupPaths
 	s.state.GuestMemoryHotplugProbe = ss.GuestMemoryHotplugProbe
+	s.state.SandboxDevices = ss.SandboxDevices
 }
 
 func (c *Container) loadContState(cs persistapi.ContainerState) {
 	c.state = types.ContainerState{
-		State:         types.StateString(cs.State),
-		BlockDeviceID: cs.Rootfs.BlockDeviceID,
-		Fstype:        cs.Rootfs.FsType,
-		CgroupPath:    cs.CgroupPath,
+		State:            types.StateString(cs.State),
+		BlockDeviceID:    cs.Rootfs.BlockDeviceID,
+		Fstype:           cs.Rootfs.FsType,
+		CgroupPath:       cs.CgroupPath,
+		CreationDeferred: cs.CreationDeferred,
 	}
 }
 
@@ -432,6 +549,45 @@ func (c *Container) Restore() error {
 	return nil
 }
 
+func loadNUMANodes(saved []persistapi.NUMANode) []NUMANode {
+	var nodes []NUMANode
+	for _, n := range saved {
+		nodes = append(nodes, NUMANode{
+			VCPUs:      n.VCPUs,
+			MemorySize: n.MemorySize,
+		})
+	}
+
+	return nodes
+}
+
+func loadStaticNetworkConfig(saved *persistapi.StaticNetworkConfig) *StaticNetworkConfig {
+	if saved == nil {
+		return nil
+	}
+
+	c := &StaticNetworkConfig{}
+	for _, i := range saved.Interfaces {
+		c.Interfaces = append(c.Interfaces, StaticNetworkInterface{
+			Name:            i.Name,
+			HwAddr:          i.HwAddr,
+			IPAddresses:     i.IPAddresses,
+			MTU:             i.MTU,
+			VhostUserSocket: i.VhostUserSocket,
+		})
+	}
+	for _, r := range saved.Routes {
+		c.Routes = append(c.Routes, StaticNetworkRoute{
+			Dest:    r.Dest,
+			Gateway: r.Gateway,
+			Source:  r.Source,
+			Device:  r.Device,
+		})
+	}
+
+	return c
+}
+
 func loadSandboxConfig(id string) (*SandboxConfig, error) {
 	store, err := persist.GetDriver()
 	if err != nil || store == nil {
@@ -452,87 +608,161 @@ func loadSandboxConfig(id string) (*SandboxConfig, error) {
 			NetNsCreated:      savedConf.NetworkConfig.NetNsCreated,
 			DisableNewNetNs:   savedConf.NetworkConfig.DisableNewNetNs,
 			InterworkingModel: NetInterworkingModel(savedConf.NetworkConfig.InterworkingModel),
+			StaticConfig:      loadStaticNetworkConfig(savedConf.NetworkConfig.StaticConfig),
 		},
 
-		ShmSize:             savedConf.ShmSize,
-		SharePidNs:          savedConf.SharePidNs,
-		SystemdCgroup:       savedConf.SystemdCgroup,
-		SandboxCgroupOnly:   savedConf.SandboxCgroupOnly,
-		DisableGuestSeccomp: savedConf.DisableGuestSeccomp,
-		Cgroups:             savedConf.Cgroups,
+		ShmSize:              savedConf.ShmSize,
+		SharePidNs:           savedConf.SharePidNs,
+		ContainerParallelism: savedConf.ContainerParallelism,
+		SystemdCgroup:        savedConf.SystemdCgroup,
+		SandboxCgroupOnly:    savedConf.SandboxCgroupOnly,
+		CPUQuotaPolicy:       CPUQuotaPolicy(savedConf.CPUQuotaPolicy),
+		DisableGuestSeccomp:  savedConf.DisableGuestSeccomp,
+		GuestOCIHooks:        savedConf.GuestOCIHooks,
+		Cgroups:              savedConf.Cgroups,
+		AuditConfig: AuditConfig{
+			Enable: savedConf.AuditConfig.Enable,
+			Dir:    savedConf.AuditConfig.Dir,
+			Sink:   savedConf.AuditConfig.Sink,
+		},
+		EphemeralDiskConfig: EphemeralDiskConfig{
+			Backend:     savedConf.EphemeralDiskConfig.Backend,
+			VolumeGroup: savedConf.EphemeralDiskConfig.VolumeGroup,
+			Dir:         savedConf.EphemeralDiskConfig.Dir,
+			SizeMB:      savedConf.EphemeralDiskConfig.SizeMB,
+			MaxSizeMB:   savedConf.EphemeralDiskConfig.MaxSizeMB,
+			Encrypt:     savedConf.EphemeralDiskConfig.Encrypt,
+		},
+		RootfsDedup:         savedConf.RootfsDedup,
+		RootfsDedupDir:      savedConf.RootfsDedupDir,
+		DeviceReuseTimeout:  savedConf.DeviceReuseTimeout,
+		DevicePluginClasses: savedConf.DevicePluginClasses,
+		InitDataConfig: InitDataConfig{
+			Method:       savedConf.InitDataConfig.Method,
+			Certificates: savedConf.InitDataConfig.Certificates,
+			HTTPProxy:    savedConf.InitDataConfig.HTTPProxy,
+			HTTPSProxy:   savedConf.InitDataConfig.HTTPSProxy,
+			NoProxy:      savedConf.InitDataConfig.NoProxy,
+		},
+		GuestSwapConfig: GuestSwapConfig{
+			SizeMB:     savedConf.GuestSwapConfig.SizeMB,
+			MaxSizeMB:  savedConf.GuestSwapConfig.MaxSizeMB,
+			Swappiness: savedConf.GuestSwapConfig.Swappiness,
+			Dir:        savedConf.GuestSwapConfig.Dir,
+		},
 	}
 	sconfig.SandboxBindMounts = append(sconfig.SandboxBindMounts, savedConf.SandboxBindMounts...)
 
+	for _, r := range savedConf.SharedMemoryConfig.Regions {
+		sconfig.SharedMemoryConfig.Regions = append(sconfig.SharedMemoryConfig.Regions, SharedMemoryRegion{
+			Name: r.Name,
+			Size: r.Size,
+		})
+	}
+	sconfig.SharedMemoryConfig.ValidRegions = append(sconfig.SharedMemoryConfig.ValidRegions, savedConf.SharedMemoryConfig.ValidRegions...)
+	sconfig.SharedMemoryConfig.MaxRegionSizeMB = savedConf.SharedMemoryConfig.MaxRegionSizeMB
+	sconfig.SharedMemoryConfig.MaxSizeMB = savedConf.SharedMemoryConfig.MaxSizeMB
+
 	for _, name := range savedConf.Experimental {
 		sconfig.Experimental = append(sconfig.Experimental, *exp.Get(name))
 	}
 
 	hconf := savedConf.HypervisorConfig
 	sconfig.HypervisorConfig = HypervisorConfig{
-		NumVCPUs:                hconf.NumVCPUs,
-		DefaultMaxVCPUs:         hconf.DefaultMaxVCPUs,
-		MemorySize:              hconf.MemorySize,
-		DefaultBridges:          hconf.DefaultBridges,
-		Msize9p:                 hconf.Msize9p,
-		MemSlots:                hconf.MemSlots,
-		MemOffset:               hconf.MemOffset,
-		VirtioMem:               hconf.VirtioMem,
-		VirtioFSCacheSize:       hconf.VirtioFSCacheSize,
-		KernelPath:              hconf.KernelPath,
-		ImagePath:               hconf.ImagePath,
-		InitrdPath:              hconf.InitrdPath,
-		FirmwarePath:            hconf.FirmwarePath,
-		MachineAccelerators:     hconf.MachineAccelerators,
-		CPUFeatures:             hconf.CPUFeatures,
-		HypervisorPath:          hconf.HypervisorPath,
-		HypervisorPathList:      hconf.HypervisorPathList,
-		HypervisorCtlPath:       hconf.HypervisorCtlPath,
-		HypervisorCtlPathList:   hconf.HypervisorCtlPathList,
-		JailerPath:              hconf.JailerPath,
-		JailerPathList:          hconf.JailerPathList,
-		BlockDeviceDriver:       hconf.BlockDeviceDriver,
-		HypervisorMachineType:   hconf.HypervisorMachineType,
-		MemoryPath:              hconf.MemoryPath,
-		DevicesStatePath:        hconf.DevicesStatePath,
-		EntropySource:           hconf.EntropySource,
-		EntropySourceList:       hconf.EntropySourceList,
-		SharedFS:                hconf.SharedFS,
-		VirtioFSDaemon:          hconf.VirtioFSDaemon,
-		VirtioFSDaemonList:      hconf.VirtioFSDaemonList,
-		VirtioFSCache:           hconf.VirtioFSCache,
-		VirtioFSExtraArgs:       hconf.VirtioFSExtraArgs[:],
-		BlockDeviceCacheSet:     hconf.BlockDeviceCacheSet,
-		BlockDeviceCacheDirect:  hconf.BlockDeviceCacheDirect,
-		BlockDeviceCacheNoflush: hconf.BlockDeviceCacheNoflush,
-		DisableBlockDeviceUse:   hconf.DisableBlockDeviceUse,
-		EnableIOThreads:         hconf.EnableIOThreads,
-		Debug:                   hconf.Debug,
-		MemPrealloc:             hconf.MemPrealloc,
-		HugePages:               hconf.HugePages,
-		FileBackedMemRootDir:    hconf.FileBackedMemRootDir,
-		FileBackedMemRootList:   hconf.FileBackedMemRootList,
-		Realtime:                hconf.Realtime,
-		Mlock:                   hconf.Mlock,
-		DisableNestingChecks:    hconf.DisableNestingChecks,
-		DisableImageNvdimm:      hconf.DisableImageNvdimm,
-		HotplugVFIOOnRootBus:    hconf.HotplugVFIOOnRootBus,
-		PCIeRootPort:            hconf.PCIeRootPort,
-		BootToBeTemplate:        hconf.BootToBeTemplate,
-		BootFromTemplate:        hconf.BootFromTemplate,
-		DisableVhostNet:         hconf.DisableVhostNet,
-		EnableVhostUserStore:    hconf.EnableVhostUserStore,
-		VhostUserStorePath:      hconf.VhostUserStorePath,
-		VhostUserStorePathList:  hconf.VhostUserStorePathList,
-		GuestHookPath:           hconf.GuestHookPath,
-		VMid:                    hconf.VMid,
-		RxRateLimiterMaxRate:    hconf.RxRateLimiterMaxRate,
-		TxRateLimiterMaxRate:    hconf.TxRateLimiterMaxRate,
-		SGXEPCSize:              hconf.SGXEPCSize,
-		EnableAnnotations:       hconf.EnableAnnotations,
+		NumVCPUs:                  hconf.NumVCPUs,
+		DefaultMaxVCPUs:           hconf.DefaultMaxVCPUs,
+		MemorySize:                hconf.MemorySize,
+		DefaultBridges:            hconf.DefaultBridges,
+		Msize9p:                   hconf.Msize9p,
+		MemSlots:                  hconf.MemSlots,
+		MemOffset:                 hconf.MemOffset,
+		VirtioMem:                 hconf.VirtioMem,
+		VirtioFSCacheSize:         hconf.VirtioFSCacheSize,
+		KernelPath:                hconf.KernelPath,
+		ImagePath:                 hconf.ImagePath,
+		InitrdPath:                hconf.InitrdPath,
+		FirmwarePath:              hconf.FirmwarePath,
+		MachineAccelerators:       hconf.MachineAccelerators,
+		CPUFeatures:               hconf.CPUFeatures,
+		CPUModel:                  hconf.CPUModel,
+		HypervisorPath:            hconf.HypervisorPath,
+		HypervisorPathList:        hconf.HypervisorPathList,
+		HypervisorCtlPath:         hconf.HypervisorCtlPath,
+		HypervisorCtlPathList:     hconf.HypervisorCtlPathList,
+		JailerPath:                hconf.JailerPath,
+		JailerPathList:            hconf.JailerPathList,
+		BlockDeviceDriver:         hconf.BlockDeviceDriver,
+		HypervisorMachineType:     hconf.HypervisorMachineType,
+		MemoryPath:                hconf.MemoryPath,
+		DevicesStatePath:          hconf.DevicesStatePath,
+		EntropySource:             hconf.EntropySource,
+		EntropySourceList:         hconf.EntropySourceList,
+		RootfsDiskPath:            hconf.RootfsDiskPath,
+		RootfsDiskFormat:          hconf.RootfsDiskFormat,
+		RootfsDiskOverlayDir:      hconf.RootfsDiskOverlayDir,
+		RootfsDiskList:            hconf.RootfsDiskList,
+		SharedFS:                  hconf.SharedFS,
+		VirtioFSDaemon:            hconf.VirtioFSDaemon,
+		VirtioFSDaemonList:        hconf.VirtioFSDaemonList,
+		VirtioFSCache:             hconf.VirtioFSCache,
+		VirtioFSExtraArgs:         hconf.VirtioFSExtraArgs[:],
+		VirtioFSDaemonIDs:         hconf.VirtioFSDaemonIDs,
+		BlockDeviceCacheSet:       hconf.BlockDeviceCacheSet,
+		BlockDeviceCacheDirect:    hconf.BlockDeviceCacheDirect,
+		BlockDeviceCacheNoflush:   hconf.BlockDeviceCacheNoflush,
+		DisableBlockDeviceUse:     hconf.DisableBlockDeviceUse,
+		LazyBlockDeviceAttach:     hconf.LazyBlockDeviceAttach,
+		EnableIOThreads:           hconf.EnableIOThreads,
+		Debug:                     hconf.Debug,
+		MemPrealloc:               hconf.MemPrealloc,
+		HugePages:                 hconf.HugePages,
+		FileBackedMemRootDir:      hconf.FileBackedMemRootDir,
+		FileBackedMemRootList:     hconf.FileBackedMemRootList,
+		Realtime:                  hconf.Realtime,
+		Mlock:                     hconf.Mlock,
+		DisableNestingChecks:      hconf.DisableNestingChecks,
+		DisableImageNvdimm:        hconf.DisableImageNvdimm,
+		GICVersion:                hconf.GICVersion,
+		DisableITS:                hconf.DisableITS,
+		SMTMode:                   hconf.SMTMode,
+		MMUMode:                   hconf.MMUMode,
+		MemoryBlockSizeMB:         hconf.MemoryBlockSizeMB,
+		SEHostKeyDocuments:        hconf.SEHostKeyDocuments,
+		HotplugVFIOOnRootBus:      hconf.HotplugVFIOOnRootBus,
+		PCIeRootPort:              hconf.PCIeRootPort,
+		BootToBeTemplate:          hconf.BootToBeTemplate,
+		BootFromTemplate:          hconf.BootFromTemplate,
+		DisableVhostNet:           hconf.DisableVhostNet,
+		EnableVhostUserStore:      hconf.EnableVhostUserStore,
+		VhostUserStorePath:        hconf.VhostUserStorePath,
+		VhostUserStorePathList:    hconf.VhostUserStorePathList,
+		VhostUserSocketList:       hconf.VhostUserSocketList,
+		GuestHookPath:             hconf.GuestHookPath,
+		VMid:                      hconf.VMid,
+		RxRateLimiterMaxRate:      hconf.RxRateLimiterMaxRate,
+		TxRateLimiterMaxRate:      hconf.TxRateLimiterMaxRate,
+		SGXEPCSize:                hconf.SGXEPCSize,
+		NUMANodes:                 loadNUMANodes(hconf.NUMANodes),
+		GuestHugepages:            hconf.GuestHugepages,
+		EnableAnnotations:         hconf.EnableAnnotations,
+		VMMSandboxing:             hconf.VMMSandboxing,
+		VMMSandboxingSyscalls:     hconf.VMMSandboxingSyscalls,
+		VMMSandboxingCapabilities: hconf.VMMSandboxingCapabilities,
+		HypervisorIDs:             hconf.HypervisorIDs,
+		VMMOOMScoreAdj:            hconf.VMMOOMScoreAdj,
+		VMMNice:                   hconf.VMMNice,
+		VMMIOClass:                hconf.VMMIOClass,
+		VMMIOPriority:             hconf.VMMIOPriority,
+		VCPURealtimePriority:      hconf.VCPURealtimePriority,
 	}
 
 	sconfig.AgentConfig = KataAgentConfig{
-		LongLiveConn: savedConf.KataAgentConfig.LongLiveConn,
+		LongLiveConn:       savedConf.KataAgentConfig.LongLiveConn,
+		PolicyFile:         savedConf.KataAgentConfig.PolicyFile,
+		AllowedAPIs:        savedConf.KataAgentConfig.AllowedAPIs,
+		DialTimeout:        savedConf.KataAgentConfig.DialTimeout,
+		DialBackoffInitial: savedConf.KataAgentConfig.DialBackoffInitial,
+		DialBackoffMax:     savedConf.KataAgentConfig.DialBackoffMax,
 	}
 
 	for _, contConf := range savedConf.ContainerConfigs {
@@ -7,6 +7,8 @@
 package persistapi
 
 import (
+	"time"
+
 	"github.com/opencontainers/runc/libcontainer/configs"
 	specs "github.com/opencontainers/runtime-spec/specs-go"
 )
@@ -57,6 +59,9 @@ type HypervisorConfig struct {
 	// CPUFeatures are cpu specific features
 	CPUFeatures string
 
+	// CPUModel is the guest cpu model, the host cpu model is used when empty
+	CPUModel string
+
 	// HypervisorPath is the hypervisor executable host path.
 	HypervisorPath string
 
@@ -99,6 +104,19 @@ type HypervisorConfig struct {
 	// EntropySourceList is the list of valid entropy sources
 	EntropySourceList []string
 
+	// RootfsDiskPath is the host path of a pre-built guest rootfs disk
+	// the guest boots from, rather than from the image or the initrd.
+	RootfsDiskPath string
+
+	// RootfsDiskFormat is the format of the rootfs disk, raw or qcow2.
+	RootfsDiskFormat string
+
+	// RootfsDiskOverlayDir is the host directory of the rootfs disk overlays.
+	RootfsDiskOverlayDir string
+
+	// RootfsDiskList is the list of rootfs disks allowed in annotations.
+	RootfsDiskList []string
+
 	// Shared file system type:
 	//   - virtio-9p (default)
 	//   - virtio-fs
@@ -116,6 +134,10 @@ type HypervisorConfig struct {
 	// VirtioFSExtraArgs passes options to virtiofsd daemon
 	VirtioFSExtraArgs []string
 
+	// VirtioFSDaemonIDs is the range of the user and group IDs allocated to
+	// the virtiofsd daemons
+	VirtioFSDaemonIDs string
+
 	// File based memory backend root directory
 	FileBackedMemRootDir string
 
@@ -136,6 +158,10 @@ type HypervisorConfig struct {
 	// DisableBlockDeviceUse disallows a block device from being used.
 	DisableBlockDeviceUse bool
 
+	// LazyBlockDeviceAttach defers the hotplug of the containers rootfs
+	// block device until they start.
+	LazyBlockDeviceAttach bool
+
 	// EnableIOThreads enables IO to be processed in a separate thread.
 	// Supported currently for virtio-scsi driver.
 	EnableIOThreads bool
@@ -168,6 +194,30 @@ type HypervisorConfig struct {
 	// DisableImageNvdimm disables nvdimm for guest rootfs image
 	DisableImageNvdimm bool
 
+	// GICVersion is the interrupt controller version of the
+	// arm64 virt machine type, the host one when empty.
+	GICVersion string
+
+	// DisableITS disables the interrupt translation service
+	// of the arm64 virt machine type.
+	DisableITS bool
+
+	// SMTMode is the number of threads per core of the
+	// ppc64le pseries machine type, 1 when unset.
+	SMTMode uint32
+
+	// MMUMode is the MMU mode of the ppc64le guests, radix or hash,
+	// the guest kernel chooses it when empty.
+	MMUMode string
+
+	// MemoryBlockSizeMB is the size, in MiB, of the memory blocks
+	// hotplugged in the ppc64le guests, a multiple of 256.
+	MemoryBlockSizeMB uint32
+
+	// SEHostKeyDocuments are the host key documents the Secure
+	// Execution boot image of the confidential guests is built for.
+	SEHostKeyDocuments []string
+
 	// HotplugVFIOOnRootBus is used to indicate if devices need to be hotplugged on the
 	// root bus instead of a bridge.
 	HotplugVFIOOnRootBus bool
@@ -195,6 +245,10 @@ type HypervisorConfig struct {
 	// VhostUserStorePathList is the list of valid values for vhost-user paths
 	VhostUserStorePathList []string
 
+	// VhostUserSocketList is the list of valid values for the vhost-user
+	// sockets of the static network interfaces
+	VhostUserSocketList []string
+
 	// GuestHookPath is the path within the VM that will be used for 'drop-in' hooks
 	GuestHookPath string
 
@@ -212,14 +266,54 @@ type HypervisorConfig struct {
 	// Enable SGX. Hardware-based isolation and memory encryption.
 	SGXEPCSize int64
 
+	// NUMANodes is the guest NUMA topology
+	NUMANodes []NUMANode
+
+	// GuestHugepages are the hugepages of the pod by page size
+	GuestHugepages map[uint64]uint64
+
 	// Enable annotations by name
 	EnableAnnotations []string
+
+	// VMMSandboxing launches the hypervisor in a sandboxing profile.
+	VMMSandboxing bool
+
+	// VMMSandboxingSyscalls are allowed on top of the default profile.
+	VMMSandboxingSyscalls []string
+
+	// VMMSandboxingCapabilities are kept by the sandboxed hypervisor.
+	VMMSandboxingCapabilities []string
+
+	// HypervisorIDs is the range of the user and group IDs allocated to
+	// the hypervisors
+	HypervisorIDs string
+
+	// VMMOOMScoreAdj, VMMNice, VMMIOClass and VMMIOPriority are the
+	// priorities of the hypervisor processes.
+	VMMOOMScoreAdj int
+	VMMNice        int
+	VMMIOClass     string
+	VMMIOPriority  uint32
+
+	// VCPURealtimePriority is the SCHED_FIFO priority of the vCPU threads.
+	VCPURealtimePriority uint32
+}
+
+// NUMANode is a node of the guest NUMA topology
+type NUMANode struct {
+	VCPUs      []uint32
+	MemorySize uint32
 }
 
 // KataAgentConfig is a structure storing information needed
 // to reach the Kata Containers agent.
 type KataAgentConfig struct {
-	LongLiveConn bool
+	PolicyFile         string
+	AllowedAPIs        []string
+	LongLiveConn       bool
+	DialTimeout        uint32
+	DialBackoffInitial uint32
+	DialBackoffMax     uint32
 }
 
 // ShimConfig is the structure providing specific configuration
@@ -229,12 +323,37 @@ type ShimConfig struct {
 	Debug bool
 }
 
+// StaticNetworkInterface is a guest interface of the static network configuration.
+type StaticNetworkInterface struct {
+	Name            string
+	HwAddr          string
+	IPAddresses     []string
+	MTU             int
+	VhostUserSocket string `json:",omitempty"`
+}
+
+// StaticNetworkRoute is a guest route of the static network configuration.
+type StaticNetworkRoute struct {
+	Dest    string
+	Gateway string
+	Source  string
+	Device  string
+}
+
+// StaticNetworkConfig is the network configuration applied without scanning
+// the network namespace.
+type StaticNetworkConfig struct {
+	Interfaces []StaticNetworkInterface
+	Routes     []StaticNetworkRoute
+}
+
 // NetworkConfig is the network configuration related to a network.
 type NetworkConfig struct {
 	NetNSPath         string
 	NetNsCreated      bool
 	DisableNewNetNs   bool
 	InterworkingModel int
+	StaticConfig      *StaticNetworkConfig `json:",omitempty"`
 }
 
 type ContainerConfig struct {
@@ -245,6 +364,60 @@ type ContainerConfig struct {
 	Resources specs.LinuxResources
 }
 
+// AuditConfig is the audit log configuration of a sandbox.
+// Refs: virtcontainers/audit.go:AuditConfig
+type AuditConfig struct {
+	Enable bool
+	Dir    string
+	Sink   string
+}
+
+// EphemeralDiskConfig is the ephemeral disk configuration of a sandbox.
+// Refs: virtcontainers/ephemeral_disk.go:EphemeralDiskConfig
+type EphemeralDiskConfig struct {
+	Backend     string
+	VolumeGroup string
+	Dir         string
+	SizeMB      uint32
+	MaxSizeMB   uint32
+	Encrypt     bool
+}
+
+// InitDataConfig is the initdata configuration of a sandbox.
+// Refs: virtcontainers/initdata.go:InitDataConfig
+type InitDataConfig struct {
+	Method       string
+	Certificates []string
+	HTTPProxy    string
+	HTTPSProxy   string
+	NoProxy      string
+}
+
+// GuestSwapConfig is the guest swap configuration of a sandbox.
+// Refs: virtcontainers/guest_swap.go:GuestSwapConfig
+type GuestSwapConfig struct {
+	SizeMB     uint32
+	MaxSizeMB  uint32
+	Swappiness uint32
+	Dir        string
+}
+
+// SharedMemoryRegion is a memory region shared between sandboxes.
+// Refs: virtcontainers/shared_memory.go:SharedMemoryRegion
+type SharedMemoryRegion struct {
+	Name string
+	Size uint64
+}
+
+// SharedMemoryConfig is the shared memory configuration of a sandbox.
+// Refs: virtcontainers/shared_memory.go:SharedMemoryConfig
+type SharedMemoryConfig struct {
+	Regions         []SharedMemoryRegion
+	ValidRegions    []string
+	MaxRegionSizeMB uint32
+	MaxSizeMB       uint32
+}
+
 // SandboxConfig is a sandbox configuration.
 // Refs: virtcontainers/sandbox.go:SandboxConfig
 type SandboxConfig struct {
@@ -263,6 +436,10 @@ type SandboxConfig struct {
 	// SharePidNs sets all containers to share the same sandbox level pid namespace.
 	SharePidNs bool
 
+	// ContainerParallelism is the maximum number of containers created or
+	// started at the same time, with the sandbox or through CreateContainer.
+	ContainerParallelism uint32
+
 	// Stateful keeps sandbox resources in memory across APIs. Users will be responsible
 	// for calling Release() to release the memory resources.
 	Stateful bool
@@ -273,14 +450,50 @@ type SandboxConfig struct {
 	// SandboxCgroupOnly enables cgroup only at podlevel in the host
 	SandboxCgroupOnly bool
 
+	// CPUQuotaPolicy tells where the CPU quota of the containers is enforced
+	CPUQuotaPolicy string
+
 	DisableGuestSeccomp bool
 
+	// GuestOCIHooks runs the OCI hooks of the containers in the guest
+	GuestOCIHooks bool
+
 	// SandboxBindMounts - list of paths to mount into guest
 	SandboxBindMounts []string
 
 	// Experimental enables experimental features
 	Experimental []string
 
+	// AuditConfig configures the audit log of the privileged operations
+	AuditConfig AuditConfig
+
+	// EphemeralDiskConfig configures the disk backing the local volumes
+	EphemeralDiskConfig EphemeralDiskConfig
+
+	// RootfsDedup shares the rootfs block devices across the sandboxes
+	RootfsDedup bool
+
+	// RootfsDedupDir is the directory of the rootfs snapshots
+	RootfsDedupDir string
+
+	// DeviceReuseTimeout is how long the devices released by the
+	// containers are kept attached for reuse
+	DeviceReuseTimeout time.Duration
+
+	// DevicePluginClasses are the classes of the device plugins
+	// the containers can ask for devices
+	DevicePluginClasses []string
+
+	// InitDataConfig configures the sandbox metadata passed to the guest
+	InitDataConfig InitDataConfig
+
+	// GuestSwapConfig configures the swap device of the guest
+	GuestSwapConfig GuestSwapConfig
+
+	// SharedMemoryConfig configures the memory regions shared with the
+	// other sandboxes of the host
+	SharedMemoryConfig SharedMemoryConfig
+
 	// Information for fields not saved:
 	// * Annotation: this is kind of casual data, we don't need casual data in persist file,
 	// 				if you know this data needs to persist, please gives it
@@ -108,4 +108,8 @@ type ContainerState struct {
 	// BundlePath saves container OCI config.json, which can be unmarshaled
 	// and translated to "CompatOCISpec"
 	BundlePath string
+
+	// CreationDeferred is true while the container is not yet created
+	// in the guest, because its rootfs is attached when it starts.
+	CreationDeferred bool
 }
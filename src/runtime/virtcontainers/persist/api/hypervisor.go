@@ -26,6 +26,20 @@ type CPUDevice struct {
 	ID string
 }
 
+// HypervisorAPICall is a call to the API of the hypervisor
+type HypervisorAPICall struct {
+	Method   string
+	Endpoint string
+	Body     string
+}
+
+// HypervisorInvocation is how the hypervisor was launched and configured
+type HypervisorInvocation struct {
+	Path     string
+	Args     []string
+	APICalls []HypervisorAPICall
+}
+
 type HypervisorState struct {
 	Pid int
 	// Type of hypervisor, E.g. qemu/firecracker/acrn.
@@ -42,7 +56,12 @@ type HypervisorState struct {
 	VirtiofsdPid         int
 	HotplugVFIOOnRootBus bool
 	PCIeRootPort         int
+	SEBootImage          string
 
 	// clh sepcific: refer to 'virtcontainers/clh.go:CloudHypervisorState'
 	APISocket string
+
+	// Invocation is how the VM was launched, for the hypervisors
+	// recording it
+	Invocation HypervisorInvocation
 }
@@ -43,6 +43,10 @@ type SandboxState struct {
 	// Devices plugged to sandbox(hypervisor)
 	Devices []DeviceState
 
+	// SandboxDevices are the IDs of the devices used by the sandbox itself
+	// rather than by one of its containers
+	SandboxDevices []string
+
 	// HypervisorState saves hypervisor specific data
 	HypervisorState HypervisorState
 
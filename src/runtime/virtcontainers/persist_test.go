@@ -79,3 +79,13 @@ func TestSandboxRestore(t *testing.T) {
 	assert.Equal(len(sandbox.state.BlockIndexMap), 1)
 	assert.Equal(sandbox.state.BlockIndexMap[2], struct{}{})
 }
+
+func TestStaticNetworkConfigPersist(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Nil(dumpStaticNetworkConfig(nil))
+	assert.Nil(loadStaticNetworkConfig(nil))
+
+	config := testStaticNetworkConfig()
+	assert.Equal(config, loadStaticNetworkConfig(dumpStaticNetworkConfig(config)))
+}
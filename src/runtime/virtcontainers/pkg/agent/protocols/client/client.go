@@ -39,6 +39,13 @@ const (
 
 var defaultDialTimeout = 30 * time.Second
 
+// the default backoff between the dial attempts, the agent is not listening
+// until the guest has booted
+var (
+	defaultDialBackoffInitial = 10 * time.Millisecond
+	defaultDialBackoffMax     = 200 * time.Millisecond
+)
+
 var hybridVSockPort uint32
 
 var agentClientFields = logrus.Fields{
@@ -56,7 +63,47 @@ type AgentClient struct {
 	conn               *ttrpc.Client
 }
 
-type dialer func(string, time.Duration) (net.Conn, error)
+// DialConfig configures the dial of the agent. The dial is retried until
+// the timeout, the delay between the attempts is doubled after each failed
+// attempt, from BackoffInitial up to BackoffMax. The defaults are used for
+// the zero values.
+type DialConfig struct {
+	Timeout        time.Duration
+	BackoffInitial time.Duration
+	BackoffMax     time.Duration
+	// OnFailure is called after each failed attempt when not nil, e.g. to
+	// report the progress of the boot of the guest.
+	OnFailure func(attempt int, err error)
+}
+
+// withDefaults returns the dial configuration with the defaults set.
+func (c DialConfig) withDefaults() DialConfig {
+	if c.Timeout == 0 {
+		c.Timeout = defaultDialTimeout
+	}
+	if c.BackoffInitial == 0 {
+		c.BackoffInitial = defaultDialBackoffInitial
+	}
+	if c.BackoffMax == 0 {
+		c.BackoffMax = defaultDialBackoffMax
+	}
+	if c.BackoffMax < c.BackoffInitial {
+		c.BackoffMax = c.BackoffInitial
+	}
+	return c
+}
+
+type dialer func(string, DialConfig) (net.Conn, error)
+
+// RPCObserver observes the RPCs sent on the agent connection, e.g. to
+// account the vsock traffic of each method.
+type RPCObserver interface {
+	// Started is called when the RPC is sent, with the payload size.
+	Started(method string, sent int)
+	// Finished is called when the RPC returns, with the payload size
+	// of the response.
+	Finished(method string, received int, err error)
+}
 
 // NewAgentClient creates a new agent gRPC client and handles both unix and vsock addresses.
 //
@@ -66,26 +113,34 @@ type dialer func(string, time.Duration) (net.Conn, error)
 //     model, and mediates communication between AF_UNIX sockets (on the host end)
 //     and AF_VSOCK sockets (on the guest end).
 //   - mock://<path>. just for test use.
-func NewAgentClient(ctx context.Context, sock string, timeout uint32) (*AgentClient, error) {
+//
+// The RPCs are observed by observer when not nil.
+func NewAgentClient(ctx context.Context, sock string, dial DialConfig, observer RPCObserver) (*AgentClient, error) {
 	grpcAddr, parsedAddr, err := parse(sock)
 	if err != nil {
 		return nil, err
 	}
 
-	dialTimeout := defaultDialTimeout
-	if timeout > 0 {
-		dialTimeout = time.Duration(timeout) * time.Second
-		agentClientLog.WithField("timeout", timeout).Debug("custom dialing timeout has been set")
-	}
+	dial = dial.withDefaults()
+	agentClientLog.WithFields(logrus.Fields{
+		"timeout":         dial.Timeout,
+		"backoff-initial": dial.BackoffInitial,
+		"backoff-max":     dial.BackoffMax,
+	}).Debug("dialing the agent")
 
 	var conn net.Conn
 	var d = agentDialer(parsedAddr)
-	conn, err = d(grpcAddr, dialTimeout)
+	conn, err = d(grpcAddr, dial)
 	if err != nil {
 		return nil, err
 	}
 
-	client := ttrpc.NewClient(conn, ttrpc.WithUnaryClientInterceptor(TraceUnaryClientInterceptor()))
+	interceptor := TraceUnaryClientInterceptor()
+	if observer != nil {
+		interceptor = chainUnaryClientInterceptors(interceptor, ObserverUnaryClientInterceptor(observer))
+	}
+
+	client := ttrpc.NewClient(conn, ttrpc.WithUnaryClientInterceptor(interceptor))
 
 	return &AgentClient{
 		AgentServiceClient: agentgrpc.NewAgentServiceClient(client),
@@ -137,6 +192,47 @@ func TraceUnaryClientInterceptor() ttrpc.UnaryClientInterceptor {
 	}
 }
 
+// ObserverUnaryClientInterceptor reports the RPCs to observer, with the
+// size of their request and response payloads.
+func ObserverUnaryClientInterceptor(observer RPCObserver) ttrpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		req *ttrpc.Request,
+		resp *ttrpc.Response,
+		ci *ttrpc.UnaryClientInfo,
+		invoker ttrpc.Invoker,
+	) error {
+		observer.Started(req.Method, len(req.Payload))
+
+		err := invoker(ctx, req, resp)
+
+		observer.Finished(req.Method, len(resp.Payload), err)
+
+		return err
+	}
+}
+
+// chainUnaryClientInterceptors returns an interceptor calling the
+// interceptors in order, ttrpc only taking one.
+func chainUnaryClientInterceptors(interceptors ...ttrpc.UnaryClientInterceptor) ttrpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		req *ttrpc.Request,
+		resp *ttrpc.Response,
+		ci *ttrpc.UnaryClientInfo,
+		invoker ttrpc.Invoker,
+	) error {
+		chained := invoker
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, next := interceptors[i], chained
+			chained = func(ctx context.Context, req *ttrpc.Request, resp *ttrpc.Response) error {
+				return interceptor(ctx, req, resp, ci, next)
+			}
+		}
+		return chained(ctx, req, resp)
+	}
+}
+
 type metadataSupplier struct {
 	metadata *ttrpc.MD
 }
@@ -243,11 +339,11 @@ func parse(sock string) (string, *url.URL, error) {
 func agentDialer(addr *url.URL) dialer {
 	switch addr.Scheme {
 	case VSockSocketScheme:
-		return VsockDialer
+		return vsockDialer
 	case HybridVSockScheme:
-		return HybridVSockDialer
+		return hybridVSockDialer
 	case MockHybridVSockScheme:
-		return MockHybridVSockDialer
+		return mockHybridVSockDialer
 	default:
 		return nil
 	}
@@ -301,12 +397,17 @@ func parseGrpcHybridVSockAddr(sock string) (string, uint32, error) {
 // it is not reasonable to have such aggressive backoffs which would kill kata
 // containers boot up speed. For more information, see
 // https://github.com/grpc/grpc/blob/master/doc/connection-backoff.md
-func commonDialer(timeout time.Duration, dialFunc func() (net.Conn, error), timeoutErrMsg error) (net.Conn, error) {
-	t := time.NewTimer(timeout)
+//
+// The dial is retried with an exponential backoff, so that a guest slow to
+// boot, e.g. nested or emulated, is not dialed in a busy loop.
+func commonDialer(cfg DialConfig, dialFunc func() (net.Conn, error), timeoutErrMsg error) (net.Conn, error) {
+	cfg = cfg.withDefaults()
+	t := time.NewTimer(cfg.Timeout)
 	cancel := make(chan bool)
 	ch := make(chan net.Conn)
 	go func() {
-		for {
+		delay := cfg.BackoffInitial
+		for attempt := 1; ; attempt++ {
 			select {
 			case <-cancel:
 				// canceled or channel closed
@@ -325,6 +426,20 @@ func commonDialer(timeout time.Duration, dialFunc func() (net.Conn, error), time
 				}
 				return
 			}
+
+			if cfg.OnFailure != nil {
+				cfg.OnFailure(attempt, err)
+			}
+
+			select {
+			case <-cancel:
+				return
+			case <-time.After(delay):
+			}
+
+			if delay *= 2; delay > cfg.BackoffMax {
+				delay = cfg.BackoffMax
+			}
 		}
 	}()
 
@@ -343,7 +458,12 @@ func commonDialer(timeout time.Duration, dialFunc func() (net.Conn, error), time
 	return conn, nil
 }
 
+// VsockDialer dials to a vsock
 func VsockDialer(sock string, timeout time.Duration) (net.Conn, error) {
+	return vsockDialer(sock, DialConfig{Timeout: timeout})
+}
+
+func vsockDialer(sock string, cfg DialConfig) (net.Conn, error) {
 	cid, port, err := parseGrpcVsockAddr(sock)
 	if err != nil {
 		return nil, err
@@ -355,11 +475,16 @@ func VsockDialer(sock string, timeout time.Duration) (net.Conn, error) {
 
 	timeoutErr := grpcStatus.Errorf(codes.DeadlineExceeded, "timed out connecting to vsock %d:%d", cid, port)
 
-	return commonDialer(timeout, dialFunc, timeoutErr)
+	return commonDialer(cfg, dialFunc, timeoutErr)
 }
 
 // HybridVSockDialer dials to a hybrid virtio socket
 func HybridVSockDialer(sock string, timeout time.Duration) (net.Conn, error) {
+	return hybridVSockDialer(sock, DialConfig{Timeout: timeout})
+}
+
+func hybridVSockDialer(sock string, cfg DialConfig) (net.Conn, error) {
+	cfg = cfg.withDefaults()
 	udsPath, port, err := parseGrpcHybridVSockAddr(sock)
 	if err != nil {
 		return nil, err
@@ -367,7 +492,7 @@ func HybridVSockDialer(sock string, timeout time.Duration) (net.Conn, error) {
 
 	dialFunc := func() (net.Conn, error) {
 		handshakeTimeout := 10 * time.Second
-		conn, err := net.DialTimeout("unix", udsPath, timeout)
+		conn, err := net.DialTimeout("unix", udsPath, cfg.Timeout)
 		if err != nil {
 			return nil, err
 		}
@@ -423,17 +548,22 @@ func HybridVSockDialer(sock string, timeout time.Duration) (net.Conn, error) {
 	}
 
 	timeoutErr := grpcStatus.Errorf(codes.DeadlineExceeded, "timed out connecting to hybrid vsocket %s", sock)
-	return commonDialer(timeout, dialFunc, timeoutErr)
+	return commonDialer(cfg, dialFunc, timeoutErr)
 }
 
 // just for tests use.
 func MockHybridVSockDialer(sock string, timeout time.Duration) (net.Conn, error) {
+	return mockHybridVSockDialer(sock, DialConfig{Timeout: timeout})
+}
+
+func mockHybridVSockDialer(sock string, cfg DialConfig) (net.Conn, error) {
+	cfg = cfg.withDefaults()
 	sock = strings.TrimPrefix(sock, "mock:")
 
 	dialFunc := func() (net.Conn, error) {
-		return net.DialTimeout("unix", sock, timeout)
+		return net.DialTimeout("unix", sock, cfg.Timeout)
 	}
 
 	timeoutErr := grpcStatus.Errorf(codes.DeadlineExceeded, "timed out connecting to mock hybrid vsocket %s", sock)
-	return commonDialer(timeout, dialFunc, timeoutErr)
+	return commonDialer(cfg, dialFunc, timeoutErr)
 }
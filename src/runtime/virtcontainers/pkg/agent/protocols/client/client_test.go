@@ -0,0 +1,140 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/containerd/ttrpc"
+	"github.com/stretchr/testify/assert"
+)
+
+type testObserver struct {
+	name  string
+	calls *[]string
+	err   error
+}
+
+func (o *testObserver) Started(method string, sent int) {
+	*o.calls = append(*o.calls, fmt.Sprintf("%s started %s %d", o.name, method, sent))
+}
+
+func (o *testObserver) Finished(method string, received int, err error) {
+	*o.calls = append(*o.calls, fmt.Sprintf("%s finished %s %d", o.name, method, received))
+	o.err = err
+}
+
+func TestObserverUnaryClientInterceptor(t *testing.T) {
+	assert := assert.New(t)
+
+	var calls []string
+	observer := &testObserver{name: "observer", calls: &calls}
+
+	invoker := func(ctx context.Context, req *ttrpc.Request, resp *ttrpc.Response) error {
+		resp.Payload = make([]byte, 32)
+		return errors.New("failed")
+	}
+
+	interceptor := ObserverUnaryClientInterceptor(observer)
+	err := interceptor(context.Background(), &ttrpc.Request{Method: "ReadStdout", Payload: make([]byte, 8)}, &ttrpc.Response{}, &ttrpc.UnaryClientInfo{}, invoker)
+	assert.Error(err)
+	assert.Equal(err, observer.err)
+	assert.Equal([]string{"observer started ReadStdout 8", "observer finished ReadStdout 32"}, calls)
+}
+
+func TestChainUnaryClientInterceptors(t *testing.T) {
+	assert := assert.New(t)
+
+	var calls []string
+	first := &testObserver{name: "first", calls: &calls}
+	second := &testObserver{name: "second", calls: &calls}
+
+	invoker := func(ctx context.Context, req *ttrpc.Request, resp *ttrpc.Response) error {
+		calls = append(calls, "invoked")
+		return nil
+	}
+
+	interceptor := chainUnaryClientInterceptors(ObserverUnaryClientInterceptor(first), ObserverUnaryClientInterceptor(second))
+	err := interceptor(context.Background(), &ttrpc.Request{Method: "Check"}, &ttrpc.Response{}, &ttrpc.UnaryClientInfo{}, invoker)
+	assert.NoError(err)
+	assert.Equal([]string{
+		"first started Check 0",
+		"second started Check 0",
+		"invoked",
+		"second finished Check 0",
+		"first finished Check 0",
+	}, calls)
+}
+
+func TestDialConfigDefaults(t *testing.T) {
+	assert := assert.New(t)
+
+	cfg := DialConfig{}.withDefaults()
+	assert.Equal(defaultDialTimeout, cfg.Timeout)
+	assert.Equal(defaultDialBackoffInitial, cfg.BackoffInitial)
+	assert.Equal(defaultDialBackoffMax, cfg.BackoffMax)
+
+	// the maximum backoff can't be lower than the initial one
+	cfg = DialConfig{BackoffInitial: time.Second, BackoffMax: time.Millisecond}.withDefaults()
+	assert.Equal(time.Second, cfg.BackoffMax)
+}
+
+func TestCommonDialerBackoff(t *testing.T) {
+	assert := assert.New(t)
+
+	var attempts []int
+	var dials []time.Time
+	cfg := DialConfig{
+		Timeout:        time.Second,
+		BackoffInitial: 10 * time.Millisecond,
+		BackoffMax:     40 * time.Millisecond,
+		OnFailure: func(attempt int, err error) {
+			attempts = append(attempts, attempt)
+		},
+	}
+
+	server, client := net.Pipe()
+	defer server.Close()
+
+	dialFunc := func() (net.Conn, error) {
+		dials = append(dials, time.Now())
+		if len(dials) < 5 {
+			return nil, errors.New("not listening")
+		}
+		return client, nil
+	}
+
+	conn, err := commonDialer(cfg, dialFunc, errors.New("timed out"))
+	assert.NoError(err)
+	assert.Equal(client, conn)
+	assert.Equal([]int{1, 2, 3, 4}, attempts)
+
+	// the delay is doubled after each failed attempt, up to the maximum
+	for i, min := range []time.Duration{10, 20, 40, 40} {
+		assert.True(dials[i+1].Sub(dials[i]) >= min*time.Millisecond)
+	}
+}
+
+func TestCommonDialerTimeout(t *testing.T) {
+	assert := assert.New(t)
+
+	cfg := DialConfig{
+		Timeout:        50 * time.Millisecond,
+		BackoffInitial: 10 * time.Millisecond,
+	}
+	dialFunc := func() (net.Conn, error) {
+		return nil, errors.New("not listening")
+	}
+
+	timeoutErr := errors.New("timed out")
+	_, err := commonDialer(cfg, dialFunc, timeoutErr)
+	assert.Equal(timeoutErr, err)
+}
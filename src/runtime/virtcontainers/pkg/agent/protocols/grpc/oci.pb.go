@@ -423,7 +423,13 @@ type Hooks struct {
 	// Poststart is a list of hooks to be run after the container process is started.
 	Poststart []Hook `protobuf:"bytes,2,rep,name=Poststart,json=poststart,proto3" json:"Poststart"`
 	// Poststop is a list of hooks to be run after the container process exits.
-	Poststop             []Hook   `protobuf:"bytes,3,rep,name=Poststop,json=poststop,proto3" json:"Poststop"`
+	Poststop []Hook `protobuf:"bytes,3,rep,name=Poststop,json=poststop,proto3" json:"Poststop"`
+	// CreateContainer is a list of hooks to be run in the container namespaces
+	// after the container is created, before pivot_root.
+	CreateContainer []Hook `protobuf:"bytes,4,rep,name=CreateContainer,json=createContainer,proto3" json:"CreateContainer"`
+	// StartContainer is a list of hooks to be run in the container namespaces
+	// before the container process is executed.
+	StartContainer       []Hook   `protobuf:"bytes,5,rep,name=StartContainer,json=startContainer,proto3" json:"StartContainer"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -1546,150 +1552,152 @@ func init() {
 }
 
 var fileDescriptor_e42fef2823778fc8 = []byte{
-	// 2282 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x58, 0xcd, 0x73, 0x23, 0x47,
-	0xd9, 0xdf, 0xd1, 0x8c, 0xa4, 0x51, 0xcb, 0x1f, 0xbb, 0x9d, 0xc4, 0xd1, 0xbb, 0x6f, 0x4a, 0x71,
-	0x86, 0x14, 0x18, 0x58, 0xec, 0x62, 0xc3, 0x47, 0x08, 0x1f, 0x55, 0xb2, 0xbd, 0x1b, 0xab, 0xb2,
-	0x5e, 0x8b, 0x96, 0x9d, 0x85, 0x1c, 0x52, 0xd5, 0x9e, 0x69, 0x49, 0x13, 0x8f, 0xa6, 0x87, 0xee,
-	0x96, 0xb5, 0xce, 0x09, 0x6e, 0xdc, 0x39, 0x70, 0xe6, 0xc2, 0xc7, 0x7f, 0x40, 0x71, 0xe2, 0xc6,
-	0x16, 0x27, 0x8e, 0x54, 0x51, 0x05, 0xac, 0xef, 0xdc, 0x39, 0x52, 0x4f, 0x77, 0xcf, 0xa8, 0x65,
-	0x7b, 0x21, 0x0b, 0x27, 0xcd, 0xf3, 0x7b, 0x9e, 0x7e, 0xba, 0xfb, 0xf9, 0x6e, 0xa1, 0xa3, 0x71,
-	0xaa, 0x26, 0xb3, 0xd3, 0xed, 0x98, 0x4f, 0x77, 0xce, 0xa8, 0xa2, 0x5f, 0x89, 0x79, 0xae, 0x68,
-	0x9a, 0x33, 0x21, 0xaf, 0xd1, 0x52, 0xc4, 0x3b, 0x74, 0xcc, 0x72, 0xb5, 0x53, 0x08, 0xae, 0x78,
-	0xcc, 0x33, 0x69, 0xbe, 0xe4, 0x0e, 0x8f, 0xd3, 0x6d, 0xfd, 0x89, 0x83, 0xb1, 0x28, 0xe2, 0xbb,
-	0xd1, 0x98, 0x8f, 0xb9, 0x61, 0x9e, 0xce, 0x46, 0x3b, 0x40, 0x69, 0x42, 0x7f, 0x19, 0xc9, 0xe8,
-	0x0f, 0x3e, 0x0a, 0x86, 0x05, 0x8b, 0x71, 0x07, 0x35, 0x3f, 0x64, 0x42, 0xa6, 0x3c, 0xef, 0x78,
-	0x9b, 0xde, 0x56, 0x8b, 0x34, 0xcf, 0x0d, 0x89, 0xbf, 0x80, 0x9a, 0x03, 0xc1, 0x63, 0x26, 0x65,
-	0xa7, 0xb6, 0xe9, 0x6d, 0xb5, 0xef, 0xaf, 0x6e, 0x83, 0xfa, 0x6d, 0x0b, 0x92, 0x66, 0x61, 0x3e,
-	0x70, 0x17, 0x05, 0x84, 0x73, 0xd5, 0xf1, 0xb5, 0x14, 0x32, 0x52, 0x80, 0x90, 0x40, 0x70, 0xae,
-	0xf0, 0x5d, 0x14, 0x1e, 0x70, 0xa9, 0x72, 0x3a, 0x65, 0x9d, 0x40, 0xef, 0x11, 0x4e, 0x2c, 0x8d,
-	0xbf, 0x88, 0x1a, 0x87, 0x7c, 0x96, 0x2b, 0xd9, 0xa9, 0x6f, 0xfa, 0x5b, 0xed, 0xfb, 0x6d, 0xb3,
-	0x5a, 0x63, 0xbb, 0xc1, 0xb3, 0xbf, 0xbe, 0x79, 0x8b, 0x34, 0xa6, 0x5a, 0x00, 0xbf, 0x85, 0xea,
-	0x07, 0x9c, 0x9f, 0xc9, 0x4e, 0x43, 0xef, 0x63, 0x25, 0x35, 0x44, 0xea, 0x13, 0xf8, 0xc1, 0xdf,
-	0x45, 0xed, 0x5e, 0x9e, 0x73, 0x45, 0x55, 0xca, 0x73, 0xd9, 0x69, 0x6a, 0x95, 0xff, 0x6f, 0x04,
-	0xe1, 0xb6, 0xdb, 0x0e, 0xf7, 0x41, 0xae, 0xc4, 0x05, 0x69, 0xd3, 0x05, 0x02, 0x3b, 0x3c, 0x4a,
-	0xf3, 0xd9, 0xd3, 0x4e, 0xe8, 0xee, 0xa0, 0x21, 0x52, 0xcf, 0xe0, 0x07, 0x8c, 0x32, 0xe4, 0x19,
-	0x15, 0xa9, 0xec, 0xb4, 0x5c, 0xa3, 0x58, 0x90, 0x34, 0xa5, 0xf9, 0x00, 0xc1, 0x27, 0x69, 0x9e,
-	0xf0, 0xb9, 0xec, 0x20, 0x57, 0xd0, 0x82, 0xa4, 0x39, 0x37, 0x1f, 0x77, 0xbf, 0x87, 0x6e, 0x5f,
-	0x3d, 0x15, 0xbe, 0x8d, 0xfc, 0x33, 0x76, 0x61, 0x1d, 0x02, 0x9f, 0xf8, 0x55, 0x54, 0x3f, 0xa7,
-	0xd9, 0x8c, 0x69, 0x57, 0xb4, 0x88, 0x21, 0xde, 0xab, 0xbd, 0xeb, 0x45, 0xbf, 0xf3, 0x2b, 0x3f,
-	0x81, 0xa5, 0x8f, 0x99, 0x98, 0xa6, 0x39, 0xcd, 0xf4, 0xe2, 0x90, 0x84, 0xca, 0xd2, 0xf8, 0xcb,
-	0xa8, 0xbd, 0xc7, 0x73, 0xc9, 0x33, 0x36, 0x4c, 0x3f, 0x65, 0xd6, 0xa5, 0x2d, 0x73, 0xa8, 0x5d,
-	0xfe, 0x94, 0xb4, 0xe3, 0x05, 0x17, 0xbf, 0x8d, 0x82, 0x13, 0xc9, 0xc4, 0xb2, 0x4b, 0x01, 0xb1,
-	0x3e, 0x09, 0x66, 0x92, 0x09, 0x8c, 0x51, 0xd0, 0x13, 0x63, 0xd9, 0x09, 0x36, 0xfd, 0xad, 0x16,
-	0x09, 0xa8, 0x18, 0x4b, 0x38, 0xfa, 0x83, 0xfc, 0x5c, 0x7b, 0xb3, 0x45, 0x7c, 0x96, 0x9f, 0x03,
-	0xb2, 0x37, 0x4f, 0xb4, 0xd7, 0x5a, 0xc4, 0x8f, 0xe7, 0x09, 0xfe, 0x36, 0x5a, 0xd9, 0xa3, 0x05,
-	0x3d, 0x4d, 0xb3, 0x54, 0xa5, 0x0c, 0xfc, 0x04, 0xbb, 0xbc, 0xee, 0x98, 0xdb, 0x65, 0x93, 0x95,
-	0xd8, 0xa1, 0xf0, 0x57, 0x51, 0x93, 0x64, 0xe9, 0x34, 0x55, 0xb2, 0x13, 0x6a, 0xff, 0xde, 0xb1,
-	0x61, 0x79, 0x34, 0xec, 0xff, 0xc0, 0x70, 0xec, 0x21, 0x9b, 0xc2, 0xc8, 0xe1, 0x2d, 0xb4, 0xfe,
-	0x98, 0x3f, 0x66, 0xf3, 0x81, 0x48, 0xcf, 0xd3, 0x8c, 0x8d, 0x99, 0x71, 0x5e, 0x48, 0xd6, 0xf3,
-	0x65, 0x18, 0x24, 0x7b, 0x45, 0x41, 0xc5, 0x94, 0x8b, 0x81, 0xe0, 0xa3, 0x34, 0x63, 0xda, 0x7b,
-	0x2d, 0xb2, 0x4e, 0x97, 0x61, 0xbc, 0x89, 0xda, 0x47, 0x47, 0x87, 0xc3, 0x98, 0x0b, 0xd6, 0x4b,
-	0x3e, 0xe9, 0xb4, 0x37, 0xbd, 0x2d, 0x9f, 0xb4, 0xf9, 0x02, 0xc2, 0x11, 0x5a, 0x19, 0x32, 0x1d,
-	0x35, 0x8f, 0xe8, 0x29, 0xcb, 0x3a, 0x2b, 0x5a, 0xd1, 0x8a, 0x74, 0xb0, 0xe8, 0x1d, 0xe4, 0xef,
-	0xf2, 0xa7, 0x78, 0x03, 0x35, 0x0e, 0x58, 0x3a, 0x9e, 0x28, 0xed, 0xb5, 0x55, 0xd2, 0x98, 0x68,
-	0x0a, 0xbc, 0xfe, 0x24, 0x4d, 0xd4, 0x44, 0x7b, 0x6b, 0x95, 0xd4, 0xe7, 0x40, 0x44, 0xb9, 0x71,
-	0x0e, 0x18, 0xf6, 0xa4, 0xbf, 0x6f, 0x97, 0xf8, 0xb3, 0xfe, 0x3e, 0x20, 0xef, 0xf7, 0xf7, 0xad,
-	0xb4, 0x3f, 0xee, 0xef, 0xe3, 0xcf, 0xa3, 0xb5, 0x5e, 0x92, 0xa4, 0x10, 0x5b, 0x34, 0x7b, 0x3f,
-	0x4d, 0x64, 0xc7, 0xdf, 0xf4, 0xb7, 0x56, 0xc9, 0x1a, 0x5d, 0x42, 0x21, 0x72, 0x40, 0xa7, 0x9b,
-	0xa3, 0x33, 0x4b, 0x47, 0xbf, 0xf2, 0xd0, 0x9d, 0x6b, 0x5e, 0x81, 0x15, 0xbb, 0x7c, 0x96, 0x27,
-	0x69, 0x3e, 0xee, 0x78, 0xda, 0xdb, 0xe1, 0xa9, 0xa5, 0xf1, 0x1b, 0xa8, 0xf5, 0x60, 0x34, 0x62,
-	0xb1, 0x4a, 0xcf, 0x21, 0xd2, 0x80, 0xd9, 0x62, 0x25, 0x00, 0xa6, 0xeb, 0xe7, 0x13, 0x26, 0x52,
-	0x45, 0x4f, 0x33, 0xa6, 0x0f, 0xd4, 0x22, 0xed, 0x74, 0x01, 0xc1, 0xfa, 0x01, 0xc4, 0xad, 0x52,
-	0x2c, 0xb1, 0xd1, 0xd5, 0x2a, 0x4a, 0x00, 0x4a, 0x56, 0x6f, 0x7a, 0x9a, 0xb2, 0x5c, 0xd9, 0x30,
-	0x6b, 0x52, 0x43, 0x46, 0x7d, 0xd4, 0x76, 0xc2, 0x00, 0xe2, 0xf3, 0xf8, 0xa2, 0x60, 0x36, 0x8f,
-	0x02, 0x75, 0x51, 0x30, 0xc0, 0x0e, 0xa8, 0x48, 0xb4, 0x8d, 0x02, 0x12, 0x4c, 0xa8, 0x48, 0x00,
-	0x1b, 0xf2, 0x91, 0x29, 0x60, 0x01, 0x09, 0x24, 0x1f, 0xa9, 0x88, 0xa3, 0xba, 0x2e, 0x42, 0x70,
-	0xda, 0x84, 0x49, 0x95, 0xe6, 0x3a, 0x41, 0xad, 0x2e, 0x17, 0x02, 0xef, 0x49, 0x3e, 0x13, 0x71,
-	0x99, 0x9c, 0x96, 0x02, 0xb5, 0xb0, 0xa5, 0x56, 0x5b, 0x6e, 0xdf, 0x41, 0x4d, 0x5e, 0x98, 0xea,
-	0x64, 0xee, 0x55, 0x92, 0xd1, 0x37, 0x4c, 0x15, 0x85, 0x55, 0x03, 0xaa, 0x26, 0xe5, 0xa1, 0x0b,
-	0xaa, 0x26, 0x60, 0x6b, 0xc2, 0x68, 0xc2, 0xf3, 0xec, 0x42, 0xef, 0x11, 0x92, 0x50, 0x58, 0x3a,
-	0xfa, 0x99, 0x67, 0xeb, 0x22, 0xbe, 0x87, 0xc2, 0x81, 0x60, 0x52, 0x51, 0xa1, 0xb4, 0x47, 0xaa,
-	0xc4, 0x05, 0xb6, 0xcd, 0x89, 0xb0, 0xb0, 0x12, 0x78, 0x1b, 0xb5, 0x06, 0x5c, 0x2a, 0x23, 0x5e,
-	0x7b, 0x81, 0x78, 0xab, 0x28, 0x45, 0xb4, 0x76, 0x4d, 0xf0, 0x42, 0xbb, 0xec, 0x66, 0xed, 0x56,
-	0x22, 0xfa, 0x08, 0x05, 0x80, 0xdf, 0x78, 0x9b, 0xb2, 0x6c, 0xd4, 0xae, 0x97, 0x0d, 0x7f, 0x51,
-	0x36, 0x3a, 0xa8, 0x79, 0x9c, 0x4e, 0x19, 0x9f, 0x29, 0x1d, 0x90, 0x3e, 0x69, 0x2a, 0x43, 0x46,
-	0xbf, 0xa9, 0xdb, 0x3a, 0x8d, 0xbf, 0x83, 0xda, 0x27, 0xfd, 0xfd, 0x43, 0x5a, 0x14, 0x69, 0x3e,
-	0x96, 0xf6, 0xd2, 0xaf, 0x3a, 0x75, 0xa4, 0x62, 0xda, 0x03, 0xb6, 0x67, 0x0b, 0x71, 0x58, 0xfd,
-	0xbe, 0xb3, 0xba, 0xf6, 0x9f, 0x57, 0x8f, 0x9d, 0xd5, 0x3b, 0xa8, 0x31, 0xbc, 0x90, 0xb1, 0xca,
-	0xac, 0x35, 0xdc, 0xf2, 0xb5, 0x6d, 0x38, 0xa6, 0xc5, 0x34, 0xa4, 0x26, 0xf0, 0x7d, 0xd4, 0x22,
-	0xcc, 0x84, 0x86, 0xd4, 0x57, 0x5a, 0xde, 0xac, 0xe2, 0x91, 0x96, 0x28, 0x3f, 0x21, 0xf8, 0xf6,
-	0xc6, 0x82, 0xcf, 0x0a, 0xa9, 0xad, 0x58, 0x37, 0xc1, 0x17, 0x2f, 0x20, 0xfc, 0x1e, 0x42, 0x8f,
-	0xe9, 0x94, 0xc9, 0x82, 0x82, 0xda, 0xc6, 0xb5, 0x3b, 0x54, 0x4c, 0x7b, 0x07, 0x94, 0x57, 0xd2,
-	0x50, 0x4a, 0xf7, 0xd9, 0x79, 0x1a, 0xb3, 0xb2, 0x55, 0xde, 0x71, 0x16, 0x1a, 0x4e, 0x59, 0x4a,
-	0x13, 0x23, 0x87, 0xef, 0xa1, 0xe6, 0x90, 0xc5, 0x31, 0x9f, 0x16, 0xb6, 0x49, 0x62, 0x67, 0x89,
-	0xe5, 0x90, 0xa6, 0x34, 0x1f, 0xf8, 0x1e, 0xba, 0x03, 0x31, 0x3d, 0x92, 0x03, 0xc1, 0x0b, 0x3a,
-	0x36, 0x19, 0xd4, 0xd2, 0x97, 0xb8, 0x23, 0xae, 0x32, 0xe0, 0xb2, 0x87, 0x54, 0x9e, 0xb1, 0x04,
-	0x2e, 0x06, 0x6d, 0x53, 0xd7, 0x85, 0xe9, 0x02, 0xc2, 0x6f, 0xa3, 0xd5, 0x32, 0x0f, 0x8c, 0x4c,
-	0x5b, 0xcb, 0xac, 0x0a, 0x17, 0xc4, 0x5d, 0x84, 0x74, 0xea, 0xba, 0x65, 0x17, 0x4d, 0x2b, 0x04,
-	0xef, 0xa0, 0xb0, 0x9f, 0x2b, 0x96, 0x91, 0x44, 0x75, 0x56, 0xf5, 0x25, 0x5e, 0x71, 0x9d, 0x6e,
-	0x59, 0x24, 0x4c, 0xed, 0xd7, 0xdd, 0x6f, 0xa1, 0xb6, 0xe3, 0xd0, 0x97, 0xea, 0xce, 0x6f, 0x56,
-	0x63, 0x00, 0x08, 0x25, 0xb3, 0xe9, 0xb4, 0x5c, 0x68, 0x08, 0x10, 0xb0, 0xb3, 0xc3, 0x0b, 0x04,
-	0x3e, 0x46, 0x6b, 0xcb, 0xc1, 0xa8, 0xbb, 0x05, 0x97, 0xaa, 0x2a, 0xfd, 0x8d, 0x89, 0xa6, 0x74,
-	0xb0, 0x94, 0x03, 0x63, 0xd5, 0x05, 0xda, 0xf1, 0x02, 0xd2, 0x85, 0x0e, 0x9a, 0xbf, 0xaf, 0x59,
-	0x81, 0x4c, 0x3f, 0x65, 0xd1, 0xbb, 0x56, 0x7f, 0x15, 0x28, 0x2f, 0x2a, 0x9b, 0x3a, 0x02, 0x6b,
-	0x8b, 0x3c, 0x8e, 0x7e, 0xe1, 0xa1, 0xb6, 0x13, 0x2a, 0x2f, 0xca, 0x75, 0xad, 0xab, 0xe6, 0xe8,
-	0x7a, 0x15, 0xd5, 0x0f, 0xe9, 0x27, 0xdc, 0x4c, 0x17, 0x3e, 0xa9, 0x4f, 0x81, 0xd0, 0x68, 0x9a,
-	0x73, 0x61, 0xb3, 0xbd, 0x3e, 0x05, 0x02, 0x2a, 0xdf, 0xc3, 0x34, 0x63, 0x87, 0x3c, 0x61, 0x3a,
-	0xfa, 0x57, 0x49, 0x38, 0xb2, 0x74, 0xd9, 0xff, 0x1a, 0xd7, 0xfa, 0x5f, 0xb3, 0xea, 0x7f, 0xd1,
-	0xdf, 0x6a, 0xf6, 0x7a, 0x55, 0x7a, 0xe1, 0x6f, 0x2e, 0xa2, 0xde, 0xbb, 0x96, 0xb9, 0x86, 0x63,
-	0x72, 0xee, 0x6a, 0xec, 0xc3, 0xac, 0xca, 0xa6, 0x5c, 0x5c, 0xd8, 0xe1, 0xc9, 0xcd, 0x16, 0xc3,
-	0x20, 0x8d, 0xa9, 0xfe, 0xc5, 0x9b, 0xc8, 0xdf, 0x1b, 0x9c, 0xd8, 0xf1, 0x69, 0xcd, 0x1d, 0x6c,
-	0x06, 0x27, 0xc4, 0x8f, 0x07, 0x27, 0xf8, 0x73, 0x28, 0x18, 0x40, 0x3b, 0x36, 0x85, 0x60, 0xdd,
-	0x11, 0x01, 0x98, 0x04, 0x05, 0x74, 0xe5, 0x7b, 0xa8, 0xb9, 0x9b, 0xf1, 0xf8, 0xac, 0x7f, 0xa4,
-	0x2f, 0xbf, 0x9c, 0x6d, 0x96, 0x43, 0x9a, 0xa7, 0xe6, 0x03, 0x3f, 0x44, 0x6b, 0x07, 0xb3, 0x31,
-	0x2b, 0xe8, 0x98, 0x3d, 0x32, 0x03, 0x92, 0x29, 0x07, 0x1d, 0x67, 0xd1, 0x92, 0x80, 0xbd, 0xe0,
-	0xda, 0x64, 0x69, 0x15, 0xec, 0xfa, 0x98, 0xa9, 0x39, 0x17, 0x67, 0x76, 0x32, 0x73, 0x77, 0xb5,
-	0x1c, 0xd2, 0xcc, 0xcd, 0x47, 0xf4, 0x97, 0x32, 0x0a, 0x8c, 0x09, 0xc0, 0x8f, 0x5a, 0x8f, 0x0e,
-	0x03, 0x1f, 0xe6, 0x66, 0x68, 0xc5, 0x9b, 0xa8, 0x4d, 0x98, 0x64, 0xe2, 0xdc, 0xd4, 0x80, 0x9a,
-	0x19, 0x97, 0xc4, 0x02, 0xd2, 0xb1, 0x39, 0xa7, 0x85, 0x0d, 0x8a, 0x40, 0xce, 0x69, 0x01, 0x91,
-	0xfe, 0x01, 0x13, 0x39, 0xcb, 0x6c, 0x50, 0x34, 0xce, 0x34, 0x05, 0xf3, 0x81, 0xc1, 0x8f, 0xf7,
-	0x06, 0xda, 0x32, 0x3e, 0x69, 0x9d, 0x95, 0x00, 0xe4, 0x3f, 0x68, 0x2a, 0xd2, 0x1c, 0xde, 0x2e,
-	0x0d, 0xdd, 0xd4, 0x91, 0xac, 0x10, 0xfc, 0x25, 0x74, 0x7b, 0x3f, 0x95, 0x30, 0x68, 0x1c, 0x1d,
-	0x1d, 0x7e, 0x90, 0x66, 0x19, 0x13, 0xfa, 0xa2, 0x21, 0xb9, 0x9d, 0x5c, 0xc1, 0xa3, 0x3f, 0x7a,
-	0x28, 0x2c, 0x1d, 0x07, 0xc7, 0x19, 0x4e, 0xa8, 0xd0, 0x81, 0x03, 0x4a, 0x1b, 0x52, 0x53, 0x70,
-	0xe5, 0xef, 0xcf, 0xb8, 0xa2, 0xf6, 0x5a, 0xf5, 0x1f, 0x01, 0x01, 0xd2, 0x03, 0x26, 0x52, 0x9e,
-	0xd8, 0xb9, 0xa2, 0x51, 0x68, 0x0a, 0x66, 0x4c, 0xc2, 0x68, 0x06, 0xdd, 0x8c, 0xcc, 0x72, 0xf8,
-	0xb1, 0xb7, 0x5b, 0x17, 0xcb, 0x30, 0x0c, 0x6f, 0xa5, 0xa4, 0xd5, 0x54, 0xd7, 0x9a, 0xd6, 0xc4,
-	0x12, 0x0a, 0xa6, 0xdb, 0x2b, 0x66, 0xd2, 0x8e, 0xd8, 0x41, 0x5c, 0xcc, 0x24, 0x60, 0x87, 0x6c,
-	0x6a, 0x66, 0xeb, 0x16, 0x09, 0xa6, 0x6c, 0x2a, 0xa3, 0xb9, 0x9d, 0xe3, 0x9e, 0xe8, 0xe9, 0xd2,
-	0x66, 0x6d, 0x95, 0x8d, 0xde, 0x8d, 0xd9, 0x58, 0x73, 0xb3, 0x71, 0x03, 0x35, 0xcc, 0x5a, 0x5b,
-	0x41, 0x1a, 0x73, 0x33, 0xa7, 0x76, 0x11, 0x7a, 0xc4, 0xe8, 0xc8, 0xf2, 0x02, 0xcd, 0x43, 0x59,
-	0x85, 0x44, 0x27, 0xe8, 0x15, 0xbd, 0xf1, 0xf1, 0x44, 0x70, 0xa5, 0x32, 0xf6, 0x5f, 0x6c, 0x8d,
-	0x51, 0x40, 0xa8, 0x62, 0xe5, 0x8c, 0x26, 0xa8, 0x62, 0xd1, 0x3f, 0x7c, 0xb4, 0xe2, 0xa6, 0x82,
-	0x73, 0x3e, 0xef, 0xdf, 0x9c, 0xaf, 0x76, 0xf5, 0x7c, 0xb8, 0x87, 0x56, 0x5c, 0x9b, 0xdc, 0xd0,
-	0xd1, 0x5d, 0xb6, 0x4d, 0x9b, 0x95, 0xb9, 0x6b, 0xc6, 0x13, 0xf4, 0x5a, 0x79, 0x3b, 0x68, 0x51,
-	0xbb, 0x85, 0xb4, 0xba, 0x02, 0xad, 0xeb, 0xff, 0x1c, 0x5d, 0xcb, 0x56, 0xb0, 0xda, 0x5e, 0x53,
-	0x37, 0xad, 0xc6, 0x4f, 0xd0, 0x46, 0x29, 0xfe, 0x44, 0xa4, 0x8a, 0x2d, 0xf4, 0xd6, 0x3f, 0x9b,
-	0xde, 0x0d, 0x75, 0xe3, 0x72, 0x57, 0x31, 0xec, 0xd8, 0x3f, 0x1a, 0x0c, 0xad, 0xe2, 0xc6, 0x4b,
-	0x2a, 0x5e, 0x5e, 0x8e, 0x7f, 0x88, 0x5e, 0x5f, 0x3a, 0xb1, 0xa3, 0xb9, 0xf9, 0xd9, 0x34, 0xbf,
-	0xae, 0x6e, 0x5e, 0x1f, 0xbd, 0x85, 0x5a, 0x55, 0x85, 0xbc, 0xb9, 0xce, 0x44, 0x3f, 0x29, 0xdf,
-	0x2a, 0x6e, 0x21, 0x07, 0xd9, 0x5e, 0x96, 0xf1, 0xb9, 0x7d, 0x14, 0xd7, 0x29, 0x10, 0xff, 0x73,
-	0x6f, 0xda, 0x40, 0x8d, 0x5e, 0xac, 0xff, 0x1f, 0x31, 0x73, 0x59, 0x83, 0x6a, 0x2a, 0xca, 0x6c,
-	0x54, 0xda, 0x52, 0x09, 0x93, 0xec, 0x5e, 0x46, 0xa5, 0xac, 0x1a, 0x76, 0x33, 0x36, 0x24, 0xde,
-	0x45, 0x68, 0x20, 0x52, 0x2e, 0xcc, 0x33, 0xd8, 0x0c, 0xa0, 0x6f, 0x5c, 0x99, 0x45, 0xc4, 0x88,
-	0xc6, 0xcc, 0x4a, 0x5d, 0x94, 0x43, 0x5c, 0x51, 0xad, 0x8a, 0x1e, 0x22, 0x7c, 0xbd, 0xb2, 0x43,
-	0xdf, 0x1c, 0xd0, 0x31, 0x83, 0x0e, 0x6f, 0xfb, 0x71, 0x58, 0x58, 0x7a, 0x61, 0x39, 0xf3, 0x06,
-	0xb2, 0x96, 0x3b, 0x40, 0x1b, 0x37, 0xef, 0x09, 0x76, 0x82, 0xe1, 0xa0, 0xec, 0xeb, 0xfa, 0x7f,
-	0x1b, 0xd0, 0x6f, 0xf9, 0x36, 0x9f, 0x42, 0x7b, 0xa6, 0x8b, 0xe8, 0x97, 0x9e, 0x35, 0x80, 0x9d,
-	0x07, 0x61, 0x6c, 0xdb, 0x67, 0x23, 0x3a, 0xcb, 0x54, 0x2f, 0x76, 0x1e, 0x51, 0xab, 0x89, 0x0b,
-	0x82, 0x54, 0x4f, 0xc4, 0x93, 0x54, 0xb1, 0x58, 0xcd, 0x04, 0x2b, 0xdf, 0x07, 0xab, 0xd4, 0x05,
-	0xe1, 0xf0, 0x0f, 0x33, 0x3a, 0x96, 0xf6, 0xa9, 0x50, 0x1f, 0x01, 0x81, 0xbf, 0x86, 0x42, 0x98,
-	0xd0, 0x68, 0x96, 0x49, 0x9b, 0x70, 0x4b, 0x73, 0xa9, 0x61, 0x95, 0x8f, 0x14, 0x69, 0x25, 0xa3,
-	0x14, 0xad, 0xbb, 0xe7, 0xec, 0x89, 0x31, 0xa8, 0xef, 0xe7, 0x09, 0x7b, 0x6a, 0x2b, 0x7c, 0x3d,
-	0x05, 0x02, 0xd0, 0x0f, 0xab, 0xf9, 0x2e, 0xb0, 0xf3, 0x1d, 0xd8, 0x40, 0xa3, 0xc7, 0x73, 0x6e,
-	0xcb, 0x52, 0x78, 0x6e, 0x69, 0xbc, 0x86, 0x6a, 0x47, 0x85, 0x7d, 0x49, 0xd7, 0x78, 0x11, 0xfd,
-	0xbc, 0xb2, 0x89, 0xd9, 0x1c, 0x54, 0xea, 0x89, 0xcb, 0xbe, 0x9d, 0xeb, 0x7a, 0x2c, 0x37, 0x21,
-	0x55, 0x75, 0x48, 0x1d, 0x52, 0xda, 0x36, 0x6f, 0xa0, 0x90, 0x09, 0x91, 0x73, 0xc1, 0x6c, 0xe9,
-	0x3d, 0xb8, 0x45, 0x2a, 0x04, 0xef, 0x38, 0xff, 0xc3, 0xb4, 0xef, 0xbf, 0x76, 0x7d, 0x22, 0xef,
-	0x89, 0xf2, 0x09, 0xa3, 0x5f, 0x5b, 0xbb, 0x08, 0x85, 0x0f, 0x60, 0x31, 0x61, 0x2a, 0xfa, 0x3a,
-	0x5a, 0x5d, 0x9a, 0x7b, 0xc1, 0x0f, 0x8f, 0xde, 0xd9, 0xa3, 0xf1, 0x84, 0x0d, 0xe3, 0x09, 0x9b,
-	0xd2, 0xd2, 0x5b, 0x99, 0x0b, 0xee, 0xfe, 0xd4, 0x7b, 0xf6, 0xbc, 0x7b, 0xeb, 0xcf, 0xcf, 0xbb,
-	0xb7, 0xfe, 0xf9, 0xbc, 0xeb, 0xfd, 0xf8, 0xb2, 0xeb, 0xfd, 0xfa, 0xb2, 0xeb, 0xfd, 0xf6, 0xb2,
-	0xeb, 0xfd, 0xfe, 0xb2, 0xeb, 0x3d, 0xbb, 0xec, 0x7a, 0x7f, 0xba, 0xec, 0x7a, 0x7f, 0xbf, 0xec,
-	0x7a, 0x1f, 0x7d, 0xfc, 0x92, 0x7f, 0x73, 0x0a, 0xd3, 0xfe, 0x76, 0xce, 0x53, 0xa1, 0x1c, 0x56,
-	0x71, 0x36, 0xbe, 0xf6, 0x0f, 0x28, 0xdc, 0xf4, 0xb4, 0xa1, 0xe9, 0x77, 0xfe, 0x15, 0x00, 0x00,
-	0xff, 0xff, 0x63, 0x45, 0x7b, 0x17, 0x4f, 0x15, 0x00, 0x00,
+	// 2308 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x58, 0xcb, 0x73, 0x1b, 0x49,
+	0x19, 0x8f, 0x34, 0x23, 0x69, 0xd4, 0xb2, 0xec, 0xa4, 0x37, 0x71, 0x86, 0x90, 0xd2, 0x7a, 0x87,
+	0x2d, 0x30, 0x10, 0xec, 0x22, 0xe1, 0x11, 0xc2, 0xa3, 0x4a, 0xb6, 0x93, 0x58, 0xb5, 0x71, 0x2c,
+	0x5a, 0xf6, 0x06, 0xf6, 0xb0, 0x55, 0xed, 0x51, 0x4b, 0x9a, 0xf5, 0x68, 0x7a, 0xe8, 0x6e, 0x59,
+	0x71, 0x4e, 0x70, 0x83, 0xbf, 0x80, 0x33, 0x17, 0x1e, 0xff, 0x01, 0xc5, 0x89, 0x1b, 0x29, 0x4e,
+	0x1c, 0xa9, 0xa2, 0x0a, 0x88, 0xef, 0xdc, 0x39, 0x52, 0x5f, 0x77, 0x8f, 0xd4, 0xf2, 0x03, 0x36,
+	0xec, 0x49, 0xf3, 0xfd, 0xbe, 0x47, 0x3f, 0xbe, 0x67, 0x0b, 0xed, 0x0f, 0x13, 0x35, 0x9a, 0x1c,
+	0x6d, 0xc4, 0x7c, 0xbc, 0x79, 0x4c, 0x15, 0xfd, 0x5a, 0xcc, 0x33, 0x45, 0x93, 0x8c, 0x09, 0x79,
+	0x81, 0x96, 0x22, 0xde, 0xa4, 0x43, 0x96, 0xa9, 0xcd, 0x5c, 0x70, 0xc5, 0x63, 0x9e, 0x4a, 0xf3,
+	0x25, 0x37, 0x79, 0x9c, 0x6c, 0xe8, 0x4f, 0xec, 0x0f, 0x45, 0x1e, 0xdf, 0x89, 0x86, 0x7c, 0xc8,
+	0x0d, 0xf3, 0x68, 0x32, 0xd8, 0x04, 0x4a, 0x13, 0xfa, 0xcb, 0x48, 0x46, 0x7f, 0xf2, 0x90, 0xdf,
+	0xcb, 0x59, 0x8c, 0x43, 0x54, 0xfb, 0x90, 0x09, 0x99, 0xf0, 0x2c, 0x2c, 0xad, 0x95, 0xd6, 0xeb,
+	0xa4, 0x76, 0x62, 0x48, 0xfc, 0x25, 0x54, 0xeb, 0x0a, 0x1e, 0x33, 0x29, 0xc3, 0xf2, 0x5a, 0x69,
+	0xbd, 0x71, 0xbf, 0xb9, 0x01, 0xe6, 0x37, 0x2c, 0x48, 0x6a, 0xb9, 0xf9, 0xc0, 0x2d, 0xe4, 0x13,
+	0xce, 0x55, 0xe8, 0x69, 0x29, 0x64, 0xa4, 0x00, 0x21, 0xbe, 0xe0, 0x5c, 0xe1, 0x3b, 0x28, 0xd8,
+	0xe5, 0x52, 0x65, 0x74, 0xcc, 0x42, 0x5f, 0xaf, 0x11, 0x8c, 0x2c, 0x8d, 0xbf, 0x8c, 0xaa, 0x7b,
+	0x7c, 0x92, 0x29, 0x19, 0x56, 0xd6, 0xbc, 0xf5, 0xc6, 0xfd, 0x86, 0xd1, 0xd6, 0xd8, 0x96, 0xff,
+	0xfa, 0xef, 0xef, 0x5e, 0x23, 0xd5, 0xb1, 0x16, 0xc0, 0xef, 0xa1, 0xca, 0x2e, 0xe7, 0xc7, 0x32,
+	0xac, 0xae, 0x95, 0xe6, 0x92, 0x1a, 0x22, 0x95, 0x11, 0xfc, 0xe0, 0xef, 0xa3, 0x46, 0x3b, 0xcb,
+	0xb8, 0xa2, 0x2a, 0xe1, 0x99, 0x0c, 0x6b, 0xda, 0xe4, 0xe7, 0x8d, 0x20, 0x9c, 0x76, 0xc3, 0xe1,
+	0x3e, 0xce, 0x94, 0x38, 0x25, 0x0d, 0x3a, 0x47, 0x60, 0x85, 0x67, 0x49, 0x36, 0x79, 0x19, 0x06,
+	0xee, 0x0a, 0x1a, 0x22, 0x95, 0x14, 0x7e, 0xe0, 0x52, 0x7a, 0x3c, 0xa5, 0x22, 0x91, 0x61, 0xdd,
+	0xbd, 0x14, 0x0b, 0x92, 0x9a, 0x34, 0x1f, 0x20, 0xf8, 0x22, 0xc9, 0xfa, 0x7c, 0x2a, 0x43, 0xe4,
+	0x0a, 0x5a, 0x90, 0xd4, 0xa6, 0xe6, 0xe3, 0xce, 0x0f, 0xd0, 0xf5, 0xf3, 0xbb, 0xc2, 0xd7, 0x91,
+	0x77, 0xcc, 0x4e, 0xad, 0x43, 0xe0, 0x13, 0xdf, 0x44, 0x95, 0x13, 0x9a, 0x4e, 0x98, 0x76, 0x45,
+	0x9d, 0x18, 0xe2, 0x51, 0xf9, 0x61, 0x29, 0xfa, 0x83, 0x37, 0xf3, 0x13, 0xdc, 0xf4, 0x01, 0x13,
+	0xe3, 0x24, 0xa3, 0xa9, 0x56, 0x0e, 0x48, 0xa0, 0x2c, 0x8d, 0xbf, 0x8a, 0x1a, 0xdb, 0x3c, 0x93,
+	0x3c, 0x65, 0xbd, 0xe4, 0x15, 0xb3, 0x2e, 0xad, 0x9b, 0x4d, 0x6d, 0xf1, 0x97, 0xa4, 0x11, 0xcf,
+	0xb9, 0xf8, 0x7d, 0xe4, 0x1f, 0x4a, 0x26, 0x16, 0x5d, 0x0a, 0x88, 0xf5, 0x89, 0x3f, 0x91, 0x4c,
+	0x60, 0x8c, 0xfc, 0xb6, 0x18, 0xca, 0xd0, 0x5f, 0xf3, 0xd6, 0xeb, 0xc4, 0xa7, 0x62, 0x28, 0x61,
+	0xeb, 0x8f, 0xb3, 0x13, 0xed, 0xcd, 0x3a, 0xf1, 0x58, 0x76, 0x02, 0xc8, 0xf6, 0xb4, 0xaf, 0xbd,
+	0x56, 0x27, 0x5e, 0x3c, 0xed, 0xe3, 0xef, 0xa2, 0xa5, 0x6d, 0x9a, 0xd3, 0xa3, 0x24, 0x4d, 0x54,
+	0xc2, 0xc0, 0x4f, 0xb0, 0xca, 0x6d, 0xe7, 0xba, 0x5d, 0x36, 0x59, 0x8a, 0x1d, 0x0a, 0x7f, 0x1d,
+	0xd5, 0x48, 0x9a, 0x8c, 0x13, 0x25, 0xc3, 0x40, 0xfb, 0xf7, 0x86, 0x0d, 0xcb, 0xfd, 0x5e, 0xe7,
+	0x47, 0x86, 0x63, 0x37, 0x59, 0x13, 0x46, 0x0e, 0xaf, 0xa3, 0x95, 0xe7, 0xfc, 0x39, 0x9b, 0x76,
+	0x45, 0x72, 0x92, 0xa4, 0x6c, 0xc8, 0x8c, 0xf3, 0x02, 0xb2, 0x92, 0x2d, 0xc2, 0x20, 0xd9, 0xce,
+	0x73, 0x2a, 0xc6, 0x5c, 0x74, 0x05, 0x1f, 0x24, 0x29, 0xd3, 0xde, 0xab, 0x93, 0x15, 0xba, 0x08,
+	0xe3, 0x35, 0xd4, 0xd8, 0xdf, 0xdf, 0xeb, 0xc5, 0x5c, 0xb0, 0x76, 0xff, 0x93, 0xb0, 0xb1, 0x56,
+	0x5a, 0xf7, 0x48, 0x83, 0xcf, 0x21, 0x1c, 0xa1, 0xa5, 0x1e, 0xd3, 0x51, 0xf3, 0x8c, 0x1e, 0xb1,
+	0x34, 0x5c, 0xd2, 0x86, 0x96, 0xa4, 0x83, 0x45, 0x0f, 0x90, 0xb7, 0xc5, 0x5f, 0xe2, 0x55, 0x54,
+	0xdd, 0x65, 0xc9, 0x70, 0xa4, 0xb4, 0xd7, 0x9a, 0xa4, 0x3a, 0xd2, 0x14, 0x78, 0xfd, 0x45, 0xd2,
+	0x57, 0x23, 0xed, 0xad, 0x26, 0xa9, 0x4c, 0x81, 0x88, 0x32, 0xe3, 0x1c, 0xb8, 0xd8, 0xc3, 0xce,
+	0x8e, 0x55, 0xf1, 0x26, 0x9d, 0x1d, 0x40, 0x9e, 0x76, 0x76, 0xac, 0xb4, 0x37, 0xec, 0xec, 0xe0,
+	0x2f, 0xa2, 0xe5, 0x76, 0xbf, 0x9f, 0x40, 0x6c, 0xd1, 0xf4, 0x69, 0xd2, 0x97, 0xa1, 0xb7, 0xe6,
+	0xad, 0x37, 0xc9, 0x32, 0x5d, 0x40, 0x21, 0x72, 0xc0, 0xa6, 0x9b, 0xa3, 0x13, 0x4b, 0x47, 0xbf,
+	0x29, 0xa1, 0x1b, 0x17, 0xbc, 0x02, 0x1a, 0x5b, 0x7c, 0x92, 0xf5, 0x93, 0x6c, 0x18, 0x96, 0xb4,
+	0xb7, 0x83, 0x23, 0x4b, 0xe3, 0xbb, 0xa8, 0xfe, 0x78, 0x30, 0x60, 0xb1, 0x4a, 0x4e, 0x20, 0xd2,
+	0x80, 0x59, 0x67, 0x05, 0x00, 0x57, 0xd7, 0xc9, 0x46, 0x4c, 0x24, 0x8a, 0x1e, 0xa5, 0x4c, 0x6f,
+	0xa8, 0x4e, 0x1a, 0xc9, 0x1c, 0x02, 0xfd, 0x2e, 0xc4, 0xad, 0x52, 0xac, 0x6f, 0xa3, 0xab, 0x9e,
+	0x17, 0x00, 0x94, 0xac, 0xf6, 0xf8, 0x28, 0x61, 0x99, 0xb2, 0x61, 0x56, 0xa3, 0x86, 0x8c, 0x3a,
+	0xa8, 0xe1, 0x84, 0x01, 0xc4, 0xe7, 0xc1, 0x69, 0xce, 0x6c, 0x1e, 0xf9, 0xea, 0x34, 0x67, 0x80,
+	0xed, 0x52, 0xd1, 0xd7, 0x77, 0xe4, 0x13, 0x7f, 0x44, 0x45, 0x1f, 0xb0, 0x1e, 0x1f, 0x98, 0x02,
+	0xe6, 0x13, 0x5f, 0xf2, 0x81, 0x8a, 0x38, 0xaa, 0xe8, 0x22, 0x04, 0xbb, 0xed, 0x33, 0xa9, 0x92,
+	0x4c, 0x27, 0xa8, 0xb5, 0xe5, 0x42, 0xe0, 0x3d, 0xc9, 0x27, 0x22, 0x2e, 0x92, 0xd3, 0x52, 0x60,
+	0x16, 0x96, 0x0c, 0x3d, 0x67, 0xf9, 0x10, 0xd5, 0x78, 0x6e, 0xaa, 0x93, 0x39, 0x57, 0x41, 0x46,
+	0xdf, 0x32, 0x55, 0x14, 0xb4, 0xba, 0x54, 0x8d, 0x8a, 0x4d, 0xe7, 0x54, 0x8d, 0xe0, 0xae, 0x09,
+	0xa3, 0x7d, 0x9e, 0xa5, 0xa7, 0x7a, 0x8d, 0x80, 0x04, 0xc2, 0xd2, 0xd1, 0x2f, 0xca, 0xb6, 0x2e,
+	0xe2, 0x7b, 0x28, 0xe8, 0x0a, 0x26, 0x15, 0x15, 0x4a, 0x7b, 0x64, 0x96, 0xb8, 0xc0, 0xb6, 0x39,
+	0x11, 0xe4, 0x56, 0x02, 0x6f, 0xa0, 0x7a, 0x97, 0x4b, 0x65, 0xc4, 0xcb, 0x57, 0x88, 0xd7, 0xf3,
+	0x42, 0x44, 0x5b, 0xd7, 0x04, 0xcf, 0x43, 0xef, 0x0a, 0xf1, 0x20, 0xb7, 0x12, 0xf8, 0x11, 0x5a,
+	0xd9, 0x16, 0x8c, 0x2a, 0xb6, 0x5d, 0xb4, 0xb0, 0xd0, 0xbf, 0x42, 0x69, 0x25, 0x5e, 0x14, 0xc4,
+	0x0f, 0xd1, 0x72, 0x0f, 0x96, 0x9c, 0xab, 0x56, 0xae, 0x50, 0x5d, 0x96, 0x0b, 0x72, 0xd1, 0x47,
+	0xc8, 0x07, 0xee, 0xa5, 0x77, 0x58, 0x14, 0xab, 0xf2, 0xc5, 0x62, 0xe5, 0xcd, 0x8b, 0x55, 0x88,
+	0x6a, 0x07, 0xc9, 0x98, 0xf1, 0x89, 0xd2, 0x69, 0xe0, 0x91, 0x9a, 0x32, 0x64, 0xf4, 0xbb, 0x8a,
+	0xed, 0x0e, 0xf8, 0x7b, 0xa8, 0x71, 0xd8, 0xd9, 0xd9, 0xa3, 0x79, 0x9e, 0x64, 0x43, 0x69, 0xaf,
+	0xfa, 0xa6, 0x53, 0xbd, 0x66, 0x4c, 0xbb, 0xcd, 0xc6, 0x64, 0x2e, 0x0e, 0xda, 0x4f, 0x1d, 0xed,
+	0xf2, 0xff, 0xd6, 0x1e, 0x3a, 0xda, 0x9b, 0xa8, 0xda, 0x3b, 0x95, 0xb1, 0x4a, 0xad, 0x0f, 0xdc,
+	0xa2, 0xb9, 0x61, 0x38, 0xa6, 0xb1, 0x55, 0xa5, 0x26, 0xf0, 0x7d, 0x54, 0x27, 0xcc, 0x04, 0xa4,
+	0xd4, 0x47, 0x5a, 0x5c, 0x6c, 0xc6, 0x23, 0x75, 0x51, 0x7c, 0x42, 0xc8, 0x6f, 0x0f, 0x05, 0x9f,
+	0xe4, 0x52, 0xdf, 0x62, 0xc5, 0x84, 0x7c, 0x3c, 0x87, 0xf0, 0x23, 0x84, 0x9e, 0xd3, 0x31, 0x93,
+	0x39, 0x05, 0xb3, 0xd5, 0x0b, 0x67, 0x98, 0x31, 0xed, 0x19, 0x50, 0x36, 0x93, 0x86, 0x02, 0xbe,
+	0xc3, 0x4e, 0x92, 0x98, 0x15, 0x0d, 0xfa, 0x86, 0xa3, 0x68, 0x38, 0x45, 0x01, 0xef, 0x1b, 0x39,
+	0x7c, 0x0f, 0xd5, 0x7a, 0x2c, 0x8e, 0xf9, 0x38, 0xb7, 0xad, 0x19, 0x3b, 0x2a, 0x96, 0x43, 0x6a,
+	0xd2, 0x7c, 0xe0, 0x7b, 0xe8, 0x06, 0x64, 0xd2, 0x40, 0x76, 0x05, 0xcf, 0xe9, 0xd0, 0xe4, 0x6d,
+	0x5d, 0x1f, 0xe2, 0x86, 0x38, 0xcf, 0x80, 0xc3, 0xee, 0x51, 0x79, 0xcc, 0xfa, 0x70, 0x30, 0x68,
+	0xd6, 0xba, 0x1a, 0x8d, 0xe7, 0x10, 0x7e, 0x1f, 0x35, 0x8b, 0xec, 0x33, 0x32, 0x0d, 0x2d, 0xd3,
+	0x14, 0x2e, 0x88, 0x5b, 0x08, 0xe9, 0x82, 0xe1, 0x16, 0x7b, 0x34, 0x9e, 0x21, 0x78, 0x13, 0x05,
+	0x9d, 0x4c, 0xb1, 0x94, 0xf4, 0x55, 0xd8, 0xd4, 0x87, 0x78, 0xc7, 0x75, 0xba, 0x65, 0x91, 0x20,
+	0xb1, 0x5f, 0x77, 0xbe, 0x83, 0x1a, 0x8e, 0x43, 0xdf, 0x6a, 0x26, 0x78, 0x77, 0x36, 0x7c, 0x80,
+	0x50, 0x7f, 0x32, 0x1e, 0x17, 0x8a, 0x86, 0x00, 0x01, 0x3b, 0xb1, 0x5c, 0x21, 0xf0, 0x31, 0x5a,
+	0x5e, 0x0c, 0x46, 0xdd, 0xa3, 0xb8, 0x54, 0xb3, 0x86, 0x53, 0x1d, 0x69, 0x4a, 0x07, 0x4b, 0x91,
+	0x80, 0xb3, 0xde, 0xd3, 0x88, 0xe7, 0x90, 0x2e, 0xaf, 0xc9, 0x2b, 0x53, 0x07, 0x9b, 0xc4, 0x97,
+	0xc9, 0x2b, 0x16, 0x3d, 0x44, 0xcb, 0x8b, 0x81, 0x72, 0x55, 0xb1, 0xd6, 0x11, 0x58, 0x9e, 0xe7,
+	0x71, 0xf4, 0xab, 0x12, 0x6a, 0x38, 0xa1, 0x72, 0x55, 0xae, 0x6b, 0x5b, 0x65, 0xc7, 0xd6, 0x4d,
+	0x54, 0xd9, 0xa3, 0x9f, 0x70, 0x33, 0xd3, 0x78, 0xa4, 0x32, 0x06, 0x42, 0xa3, 0x49, 0xc6, 0x85,
+	0xcd, 0xf6, 0xca, 0x18, 0x08, 0xa8, 0xb7, 0x4f, 0x92, 0x94, 0xed, 0xf1, 0x3e, 0xd3, 0xd1, 0xdf,
+	0x24, 0xc1, 0xc0, 0xd2, 0x45, 0xd7, 0xad, 0x5e, 0xe8, 0xba, 0xb5, 0x59, 0xd7, 0x8d, 0xfe, 0x51,
+	0x46, 0xcb, 0x8b, 0xe9, 0x85, 0xbf, 0x3d, 0x8f, 0xfa, 0xd2, 0x85, 0xcc, 0x35, 0x1c, 0x93, 0x73,
+	0xe7, 0x63, 0x1f, 0x26, 0x64, 0x36, 0xe6, 0xe2, 0xd4, 0x8e, 0x6c, 0x6e, 0xb6, 0x18, 0x06, 0xa9,
+	0x8e, 0xf5, 0x2f, 0x5e, 0x43, 0xde, 0x76, 0xf7, 0xd0, 0x0e, 0x6d, 0xcb, 0xee, 0x38, 0xd5, 0x3d,
+	0x24, 0x5e, 0xdc, 0x3d, 0xc4, 0x5f, 0x40, 0x7e, 0x17, 0x86, 0x00, 0x53, 0x08, 0x56, 0x1c, 0x11,
+	0x80, 0x89, 0x9f, 0xc3, 0x2c, 0x70, 0x0f, 0xd5, 0xb6, 0x52, 0x1e, 0x1f, 0x77, 0xf6, 0xc3, 0xca,
+	0x85, 0x6c, 0xb3, 0x1c, 0x52, 0x3b, 0x32, 0x1f, 0xf8, 0x09, 0x5a, 0xde, 0x9d, 0x0c, 0x59, 0x4e,
+	0x87, 0xec, 0x99, 0x19, 0xcb, 0x4c, 0x39, 0x08, 0x1d, 0xa5, 0x05, 0x81, 0xa2, 0x76, 0x8f, 0x16,
+	0xb4, 0x60, 0xd5, 0xe7, 0x4c, 0x4d, 0xb9, 0x38, 0x0e, 0x6b, 0x17, 0x56, 0xb5, 0x1c, 0x52, 0xcb,
+	0xcc, 0x47, 0xf4, 0xb7, 0x22, 0x0a, 0xcc, 0x15, 0x80, 0x1f, 0xb5, 0x1d, 0x1d, 0x06, 0x1e, 0xa9,
+	0x98, 0x01, 0x60, 0x0d, 0x35, 0x08, 0x93, 0x4c, 0x9c, 0x98, 0x1a, 0x50, 0xd6, 0xbc, 0x86, 0x98,
+	0x43, 0x3a, 0x36, 0xa7, 0x34, 0xb7, 0x41, 0xe1, 0xcb, 0x29, 0xcd, 0x21, 0xd2, 0x3f, 0x60, 0x22,
+	0x63, 0xa9, 0x0d, 0x8a, 0xea, 0xb1, 0xa6, 0x60, 0x2a, 0x31, 0xf8, 0xc1, 0x76, 0x57, 0xdf, 0x8c,
+	0x47, 0xea, 0xc7, 0x05, 0x00, 0xf9, 0x0f, 0x96, 0xf2, 0x24, 0x83, 0x17, 0x53, 0x55, 0x8f, 0x12,
+	0x48, 0xce, 0x10, 0xfc, 0x15, 0x74, 0x7d, 0x27, 0x91, 0x30, 0xde, 0xec, 0xef, 0xef, 0x7d, 0x90,
+	0xa4, 0x29, 0x13, 0xfa, 0xa0, 0x01, 0xb9, 0xde, 0x3f, 0x87, 0x47, 0x7f, 0x2e, 0xa1, 0xa0, 0x70,
+	0x1c, 0x6c, 0xa7, 0x37, 0xa2, 0x42, 0x07, 0x0e, 0x18, 0xad, 0x4a, 0x4d, 0xc1, 0x91, 0x7f, 0x38,
+	0xe1, 0x8a, 0xda, 0x63, 0x55, 0x7e, 0x02, 0x04, 0x48, 0x77, 0x99, 0x48, 0x78, 0xdf, 0x4e, 0x33,
+	0xd5, 0x5c, 0x53, 0x30, 0xd9, 0x12, 0x46, 0x53, 0xe8, 0x66, 0x64, 0x92, 0xc1, 0x8f, 0x3d, 0xdd,
+	0x8a, 0x58, 0x84, 0x61, 0x64, 0x2c, 0x24, 0xad, 0xa5, 0x8a, 0xb6, 0xb4, 0x2c, 0x16, 0x50, 0xb8,
+	0xba, 0xed, 0x7c, 0x22, 0xed, 0x60, 0xef, 0xc7, 0xf9, 0x44, 0x02, 0xb6, 0xc7, 0xc6, 0x66, 0xa2,
+	0xaf, 0x13, 0x7f, 0xcc, 0xc6, 0x32, 0x9a, 0xda, 0xe9, 0xf1, 0x85, 0x9e, 0x69, 0x6d, 0xd6, 0xce,
+	0xb2, 0xb1, 0x74, 0x69, 0x36, 0x96, 0xdd, 0x6c, 0x5c, 0x45, 0x55, 0xa3, 0x6b, 0x2b, 0x48, 0x75,
+	0xaa, 0x29, 0xb8, 0xf1, 0x67, 0x8c, 0x0e, 0x2c, 0xcf, 0xd7, 0x3c, 0x94, 0xce, 0x90, 0xe8, 0x10,
+	0xbd, 0xa3, 0x17, 0x3e, 0x18, 0x09, 0xae, 0x54, 0xca, 0xfe, 0x8f, 0xa5, 0x31, 0xf2, 0x09, 0x55,
+	0xac, 0x98, 0x0c, 0x05, 0x55, 0x2c, 0xfa, 0x97, 0x87, 0x96, 0xdc, 0x54, 0x70, 0xf6, 0x57, 0xfa,
+	0x2f, 0xfb, 0x2b, 0x9f, 0xdf, 0x1f, 0x6e, 0xa3, 0x25, 0xf7, 0x4e, 0x2e, 0xe9, 0xe8, 0x2e, 0xdb,
+	0xa6, 0xcd, 0xd2, 0xd4, 0xbd, 0xc6, 0x43, 0x74, 0xab, 0x38, 0x1d, 0xb4, 0xa8, 0xad, 0x5c, 0x5a,
+	0x5b, 0x66, 0xd8, 0xfa, 0x9c, 0x63, 0x6b, 0xf1, 0x16, 0xac, 0xb5, 0x5b, 0xea, 0x32, 0x6d, 0xfc,
+	0x02, 0xad, 0x16, 0xe2, 0x2f, 0x44, 0xa2, 0xd8, 0xdc, 0x6e, 0xe5, 0xd3, 0xd9, 0x5d, 0x55, 0x97,
+	0xaa, 0xbb, 0x86, 0x61, 0xc5, 0xce, 0x7e, 0xb7, 0x67, 0x0d, 0x57, 0xdf, 0xd2, 0xf0, 0xa2, 0x3a,
+	0xfe, 0x31, 0xba, 0xbd, 0xb0, 0x63, 0xc7, 0x72, 0xed, 0xd3, 0x59, 0xbe, 0xad, 0x2e, 0xd7, 0x8f,
+	0xde, 0x43, 0xf5, 0x59, 0x85, 0xbc, 0xbc, 0xce, 0x44, 0x3f, 0x2b, 0x5e, 0x48, 0x6e, 0x21, 0x07,
+	0xd9, 0x76, 0x9a, 0xf2, 0xa9, 0x7d, 0x8a, 0x57, 0x28, 0x10, 0x9f, 0xb9, 0x37, 0xad, 0xa2, 0x6a,
+	0x3b, 0xd6, 0xff, 0xca, 0x98, 0xb9, 0xac, 0x4a, 0x35, 0x15, 0xa5, 0x68, 0xc9, 0x2d, 0x95, 0x30,
+	0xc9, 0x6e, 0xa7, 0x54, 0xca, 0x59, 0xc3, 0xae, 0xc5, 0x86, 0xc4, 0x5b, 0x08, 0x75, 0x45, 0xc2,
+	0x85, 0x79, 0x7c, 0x9b, 0x01, 0xf4, 0xee, 0xb9, 0x59, 0x44, 0x0c, 0x68, 0xcc, 0xac, 0xd4, 0x69,
+	0x31, 0xc4, 0xe5, 0x33, 0xad, 0xe8, 0x09, 0xc2, 0x17, 0x2b, 0x3b, 0xf4, 0xcd, 0x2e, 0x1d, 0x32,
+	0xe8, 0xf0, 0xb6, 0x1f, 0x07, 0xb9, 0xa5, 0xe7, 0x37, 0x67, 0x5e, 0x5e, 0xf6, 0xe6, 0x76, 0xd1,
+	0xea, 0xe5, 0x6b, 0xc2, 0x3d, 0xc1, 0x70, 0x50, 0xf4, 0x75, 0xfd, 0x6f, 0x11, 0xd8, 0xb7, 0x7c,
+	0x9b, 0x4f, 0x81, 0xdd, 0xd3, 0x69, 0xf4, 0xeb, 0x12, 0x5a, 0x72, 0xe7, 0x41, 0x18, 0xdb, 0x76,
+	0xd8, 0x80, 0x4e, 0x52, 0xd5, 0x8e, 0x9d, 0xa7, 0x5b, 0xb3, 0xef, 0x82, 0x20, 0xd5, 0x16, 0xf1,
+	0x28, 0x51, 0x2c, 0x56, 0x13, 0xc1, 0x8a, 0xf7, 0x41, 0x93, 0xba, 0x20, 0x6c, 0xfe, 0x49, 0x4a,
+	0x87, 0xd2, 0x3e, 0x15, 0x2a, 0x03, 0x20, 0xf0, 0x37, 0x50, 0x00, 0x13, 0x1a, 0x4d, 0x53, 0x69,
+	0x13, 0x6e, 0x61, 0x2e, 0x35, 0xac, 0xe2, 0x69, 0x24, 0xad, 0x64, 0x94, 0xa0, 0x15, 0x77, 0x9f,
+	0x6d, 0x31, 0x04, 0xf3, 0x9d, 0xac, 0xcf, 0x5e, 0xda, 0x0a, 0x5f, 0x49, 0x80, 0x00, 0xf4, 0xc3,
+	0xd9, 0x7c, 0xe7, 0xdb, 0xf9, 0x0e, 0xee, 0x40, 0xa3, 0x07, 0x53, 0x6e, 0xcb, 0x52, 0x70, 0x62,
+	0x69, 0xbc, 0x8c, 0xca, 0xfb, 0xb9, 0x7d, 0xbf, 0x97, 0x79, 0x1e, 0xfd, 0x72, 0x76, 0x27, 0x66,
+	0x71, 0x30, 0xa9, 0x27, 0x2e, 0xfb, 0x62, 0xaf, 0xe8, 0xb1, 0xdc, 0x84, 0xd4, 0xac, 0x43, 0xea,
+	0x90, 0x02, 0x0a, 0xdf, 0x45, 0x01, 0x13, 0x22, 0xe3, 0x82, 0xd9, 0xd2, 0xbb, 0x7b, 0x8d, 0xcc,
+	0x10, 0xbc, 0xe9, 0xfc, 0xfb, 0xd3, 0xb8, 0x7f, 0xeb, 0xe2, 0x44, 0xde, 0x16, 0xc5, 0x13, 0x46,
+	0xbf, 0xb6, 0xb6, 0x10, 0x0a, 0x1e, 0x83, 0x32, 0x61, 0x2a, 0xfa, 0x26, 0x6a, 0x2e, 0xcc, 0xbd,
+	0xe0, 0x87, 0x67, 0x0f, 0xb6, 0x69, 0x3c, 0x62, 0xbd, 0x78, 0xc4, 0xc6, 0xb4, 0xf0, 0x56, 0xea,
+	0x82, 0x5b, 0x3f, 0x2f, 0xbd, 0x7e, 0xd3, 0xba, 0xf6, 0xd7, 0x37, 0xad, 0x6b, 0xff, 0x7e, 0xd3,
+	0x2a, 0xfd, 0xf4, 0xac, 0x55, 0xfa, 0xed, 0x59, 0xab, 0xf4, 0xfb, 0xb3, 0x56, 0xe9, 0x8f, 0x67,
+	0xad, 0xd2, 0xeb, 0xb3, 0x56, 0xe9, 0x2f, 0x67, 0xad, 0xd2, 0x3f, 0xcf, 0x5a, 0xa5, 0x8f, 0x3e,
+	0x7e, 0xcb, 0x3f, 0x57, 0x85, 0x69, 0x7f, 0x9b, 0x27, 0x89, 0x50, 0x0e, 0x2b, 0x3f, 0x1e, 0x5e,
+	0xf8, 0xdf, 0x15, 0x4e, 0x7a, 0x54, 0xd5, 0xf4, 0x83, 0xff, 0x0c, 0x00, 0x5a, 0x92, 0xad, 0x40,
+	0xc5, 0x15, 0x00, 0x00,
 }
 
 func (this *Spec) Equal(that interface{}) bool {
@@ -2113,6 +2121,22 @@ func (this *Hooks) Equal(that interface{}) bool {
 			return false
 		}
 	}
+	if len(this.CreateContainer) != len(that1.CreateContainer) {
+		return false
+	}
+	for i := range this.CreateContainer {
+		if !this.CreateContainer[i].Equal(&that1.CreateContainer[i]) {
+			return false
+		}
+	}
+	if len(this.StartContainer) != len(that1.StartContainer) {
+		return false
+	}
+	for i := range this.StartContainer {
+		if !this.StartContainer[i].Equal(&that1.StartContainer[i]) {
+			return false
+		}
+	}
 	if !bytes.Equal(this.XXX_unrecognized, that1.XXX_unrecognized) {
 		return false
 	}
@@ -3684,6 +3708,34 @@ func (m *Hooks) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if len(m.StartContainer) > 0 {
+		for iNdEx := len(m.StartContainer) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.StartContainer[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintOci(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x2a
+		}
+	}
+	if len(m.CreateContainer) > 0 {
+		for iNdEx := len(m.CreateContainer) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.CreateContainer[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintOci(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x22
+		}
+	}
 	if len(m.Poststop) > 0 {
 		for iNdEx := len(m.Poststop) - 1; iNdEx >= 0; iNdEx-- {
 			{
@@ -5287,8 +5339,24 @@ func NewPopulatedHooks(r randyOci, easy bool) *Hooks {
 			this.Poststop[i] = *v21
 		}
 	}
+	if r.Intn(5) != 0 {
+		v22 := r.Intn(5)
+		this.CreateContainer = make([]Hook, v22)
+		for i := 0; i < v22; i++ {
+			v23 := NewPopulatedHook(r, easy)
+			this.CreateContainer[i] = *v23
+		}
+	}
+	if r.Intn(5) != 0 {
+		v24 := r.Intn(5)
+		this.StartContainer = make([]Hook, v24)
+		for i := 0; i < v24; i++ {
+			v25 := NewPopulatedHook(r, easy)
+			this.StartContainer[i] = *v25
+		}
+	}
 	if !easy && r.Intn(10) != 0 {
-		this.XXX_unrecognized = randUnrecognizedOci(r, 4)
+		this.XXX_unrecognized = randUnrecognizedOci(r, 6)
 	}
 	return this
 }
@@ -5296,14 +5364,14 @@ func NewPopulatedHooks(r randyOci, easy bool) *Hooks {
 func NewPopulatedHook(r randyOci, easy bool) *Hook {
 	this := &Hook{}
 	this.Path = string(randStringOci(r))
-	v22 := r.Intn(10)
-	this.Args = make([]string, v22)
-	for i := 0; i < v22; i++ {
+	v26 := r.Intn(10)
+	this.Args = make([]string, v26)
+	for i := 0; i < v26; i++ {
 		this.Args[i] = string(randStringOci(r))
 	}
-	v23 := r.Intn(10)
-	this.Env = make([]string, v23)
-	for i := 0; i < v23; i++ {
+	v27 := r.Intn(10)
+	this.Env = make([]string, v27)
+	for i := 0; i < v27; i++ {
 		this.Env[i] = string(randStringOci(r))
 	}
 	this.Timeout = int64(r.Int63())
@@ -5319,25 +5387,25 @@ func NewPopulatedHook(r randyOci, easy bool) *Hook {
 func NewPopulatedLinux(r randyOci, easy bool) *Linux {
 	this := &Linux{}
 	if r.Intn(5) != 0 {
-		v24 := r.Intn(5)
-		this.UIDMappings = make([]LinuxIDMapping, v24)
-		for i := 0; i < v24; i++ {
-			v25 := NewPopulatedLinuxIDMapping(r, easy)
-			this.UIDMappings[i] = *v25
+		v28 := r.Intn(5)
+		this.UIDMappings = make([]LinuxIDMapping, v28)
+		for i := 0; i < v28; i++ {
+			v29 := NewPopulatedLinuxIDMapping(r, easy)
+			this.UIDMappings[i] = *v29
 		}
 	}
 	if r.Intn(5) != 0 {
-		v26 := r.Intn(5)
-		this.GIDMappings = make([]LinuxIDMapping, v26)
-		for i := 0; i < v26; i++ {
-			v27 := NewPopulatedLinuxIDMapping(r, easy)
-			this.GIDMappings[i] = *v27
+		v30 := r.Intn(5)
+		this.GIDMappings = make([]LinuxIDMapping, v30)
+		for i := 0; i < v30; i++ {
+			v31 := NewPopulatedLinuxIDMapping(r, easy)
+			this.GIDMappings[i] = *v31
 		}
 	}
 	if r.Intn(5) != 0 {
-		v28 := r.Intn(10)
+		v32 := r.Intn(10)
 		this.Sysctl = make(map[string]string)
-		for i := 0; i < v28; i++ {
+		for i := 0; i < v32; i++ {
 			this.Sysctl[randStringOci(r)] = randStringOci(r)
 		}
 	}
@@ -5346,33 +5414,33 @@ func NewPopulatedLinux(r randyOci, easy bool) *Linux {
 	}
 	this.CgroupsPath = string(randStringOci(r))
 	if r.Intn(5) != 0 {
-		v29 := r.Intn(5)
-		this.Namespaces = make([]LinuxNamespace, v29)
-		for i := 0; i < v29; i++ {
-			v30 := NewPopulatedLinuxNamespace(r, easy)
-			this.Namespaces[i] = *v30
+		v33 := r.Intn(5)
+		this.Namespaces = make([]LinuxNamespace, v33)
+		for i := 0; i < v33; i++ {
+			v34 := NewPopulatedLinuxNamespace(r, easy)
+			this.Namespaces[i] = *v34
 		}
 	}
 	if r.Intn(5) != 0 {
-		v31 := r.Intn(5)
-		this.Devices = make([]LinuxDevice, v31)
-		for i := 0; i < v31; i++ {
-			v32 := NewPopulatedLinuxDevice(r, easy)
-			this.Devices[i] = *v32
+		v35 := r.Intn(5)
+		this.Devices = make([]LinuxDevice, v35)
+		for i := 0; i < v35; i++ {
+			v36 := NewPopulatedLinuxDevice(r, easy)
+			this.Devices[i] = *v36
 		}
 	}
 	if r.Intn(5) != 0 {
 		this.Seccomp = NewPopulatedLinuxSeccomp(r, easy)
 	}
 	this.RootfsPropagation = string(randStringOci(r))
-	v33 := r.Intn(10)
-	this.MaskedPaths = make([]string, v33)
-	for i := 0; i < v33; i++ {
+	v37 := r.Intn(10)
+	this.MaskedPaths = make([]string, v37)
+	for i := 0; i < v37; i++ {
 		this.MaskedPaths[i] = string(randStringOci(r))
 	}
-	v34 := r.Intn(10)
-	this.ReadonlyPaths = make([]string, v34)
-	for i := 0; i < v34; i++ {
+	v38 := r.Intn(10)
+	this.ReadonlyPaths = make([]string, v38)
+	for i := 0; i < v38; i++ {
 		this.ReadonlyPaths[i] = string(randStringOci(r))
 	}
 	this.MountLabel = string(randStringOci(r))
@@ -5448,11 +5516,11 @@ func NewPopulatedLinuxDevice(r randyOci, easy bool) *LinuxDevice {
 func NewPopulatedLinuxResources(r randyOci, easy bool) *LinuxResources {
 	this := &LinuxResources{}
 	if r.Intn(5) != 0 {
-		v35 := r.Intn(5)
-		this.Devices = make([]LinuxDeviceCgroup, v35)
-		for i := 0; i < v35; i++ {
-			v36 := NewPopulatedLinuxDeviceCgroup(r, easy)
-			this.Devices[i] = *v36
+		v39 := r.Intn(5)
+		this.Devices = make([]LinuxDeviceCgroup, v39)
+		for i := 0; i < v39; i++ {
+			v40 := NewPopulatedLinuxDeviceCgroup(r, easy)
+			this.Devices[i] = *v40
 		}
 	}
 	if r.Intn(5) != 0 {
@@ -5468,11 +5536,11 @@ func NewPopulatedLinuxResources(r randyOci, easy bool) *LinuxResources {
 		this.BlockIO = NewPopulatedLinuxBlockIO(r, easy)
 	}
 	if r.Intn(5) != 0 {
-		v37 := r.Intn(5)
-		this.HugepageLimits = make([]LinuxHugepageLimit, v37)
-		for i := 0; i < v37; i++ {
-			v38 := NewPopulatedLinuxHugepageLimit(r, easy)
-			this.HugepageLimits[i] = *v38
+		v41 := r.Intn(5)
+		this.HugepageLimits = make([]LinuxHugepageLimit, v41)
+		for i := 0; i < v41; i++ {
+			v42 := NewPopulatedLinuxHugepageLimit(r, easy)
+			this.HugepageLimits[i] = *v42
 		}
 	}
 	if r.Intn(5) != 0 {
@@ -5574,44 +5642,44 @@ func NewPopulatedLinuxBlockIO(r randyOci, easy bool) *LinuxBlockIO {
 	this := &LinuxBlockIO{}
 	this.Weight = uint32(r.Uint32())
 	this.LeafWeight = uint32(r.Uint32())
-	if r.Intn(5) != 0 {
-		v39 := r.Intn(5)
-		this.WeightDevice = make([]LinuxWeightDevice, v39)
-		for i := 0; i < v39; i++ {
-			v40 := NewPopulatedLinuxWeightDevice(r, easy)
-			this.WeightDevice[i] = *v40
-		}
-	}
-	if r.Intn(5) != 0 {
-		v41 := r.Intn(5)
-		this.ThrottleReadBpsDevice = make([]LinuxThrottleDevice, v41)
-		for i := 0; i < v41; i++ {
-			v42 := NewPopulatedLinuxThrottleDevice(r, easy)
-			this.ThrottleReadBpsDevice[i] = *v42
-		}
-	}
 	if r.Intn(5) != 0 {
 		v43 := r.Intn(5)
-		this.ThrottleWriteBpsDevice = make([]LinuxThrottleDevice, v43)
+		this.WeightDevice = make([]LinuxWeightDevice, v43)
 		for i := 0; i < v43; i++ {
-			v44 := NewPopulatedLinuxThrottleDevice(r, easy)
-			this.ThrottleWriteBpsDevice[i] = *v44
+			v44 := NewPopulatedLinuxWeightDevice(r, easy)
+			this.WeightDevice[i] = *v44
 		}
 	}
 	if r.Intn(5) != 0 {
 		v45 := r.Intn(5)
-		this.ThrottleReadIOPSDevice = make([]LinuxThrottleDevice, v45)
+		this.ThrottleReadBpsDevice = make([]LinuxThrottleDevice, v45)
 		for i := 0; i < v45; i++ {
 			v46 := NewPopulatedLinuxThrottleDevice(r, easy)
-			this.ThrottleReadIOPSDevice[i] = *v46
+			this.ThrottleReadBpsDevice[i] = *v46
 		}
 	}
 	if r.Intn(5) != 0 {
 		v47 := r.Intn(5)
-		this.ThrottleWriteIOPSDevice = make([]LinuxThrottleDevice, v47)
+		this.ThrottleWriteBpsDevice = make([]LinuxThrottleDevice, v47)
 		for i := 0; i < v47; i++ {
 			v48 := NewPopulatedLinuxThrottleDevice(r, easy)
-			this.ThrottleWriteIOPSDevice[i] = *v48
+			this.ThrottleWriteBpsDevice[i] = *v48
+		}
+	}
+	if r.Intn(5) != 0 {
+		v49 := r.Intn(5)
+		this.ThrottleReadIOPSDevice = make([]LinuxThrottleDevice, v49)
+		for i := 0; i < v49; i++ {
+			v50 := NewPopulatedLinuxThrottleDevice(r, easy)
+			this.ThrottleReadIOPSDevice[i] = *v50
+		}
+	}
+	if r.Intn(5) != 0 {
+		v51 := r.Intn(5)
+		this.ThrottleWriteIOPSDevice = make([]LinuxThrottleDevice, v51)
+		for i := 0; i < v51; i++ {
+			v52 := NewPopulatedLinuxThrottleDevice(r, easy)
+			this.ThrottleWriteIOPSDevice[i] = *v52
 		}
 	}
 	if !easy && r.Intn(10) != 0 {
@@ -5655,11 +5723,11 @@ func NewPopulatedLinuxNetwork(r randyOci, easy bool) *LinuxNetwork {
 	this := &LinuxNetwork{}
 	this.ClassID = uint32(r.Uint32())
 	if r.Intn(5) != 0 {
-		v49 := r.Intn(5)
-		this.Priorities = make([]LinuxInterfacePriority, v49)
-		for i := 0; i < v49; i++ {
-			v50 := NewPopulatedLinuxInterfacePriority(r, easy)
-			this.Priorities[i] = *v50
+		v53 := r.Intn(5)
+		this.Priorities = make([]LinuxInterfacePriority, v53)
+		for i := 0; i < v53; i++ {
+			v54 := NewPopulatedLinuxInterfacePriority(r, easy)
+			this.Priorities[i] = *v54
 		}
 	}
 	if !easy && r.Intn(10) != 0 {
@@ -5691,22 +5759,22 @@ func NewPopulatedLinuxInterfacePriority(r randyOci, easy bool) *LinuxInterfacePr
 func NewPopulatedLinuxSeccomp(r randyOci, easy bool) *LinuxSeccomp {
 	this := &LinuxSeccomp{}
 	this.DefaultAction = string(randStringOci(r))
-	v51 := r.Intn(10)
-	this.Architectures = make([]string, v51)
-	for i := 0; i < v51; i++ {
+	v55 := r.Intn(10)
+	this.Architectures = make([]string, v55)
+	for i := 0; i < v55; i++ {
 		this.Architectures[i] = string(randStringOci(r))
 	}
-	v52 := r.Intn(10)
-	this.Flags = make([]string, v52)
-	for i := 0; i < v52; i++ {
+	v56 := r.Intn(10)
+	this.Flags = make([]string, v56)
+	for i := 0; i < v56; i++ {
 		this.Flags[i] = string(randStringOci(r))
 	}
 	if r.Intn(5) != 0 {
-		v53 := r.Intn(5)
-		this.Syscalls = make([]LinuxSyscall, v53)
-		for i := 0; i < v53; i++ {
-			v54 := NewPopulatedLinuxSyscall(r, easy)
-			this.Syscalls[i] = *v54
+		v57 := r.Intn(5)
+		this.Syscalls = make([]LinuxSyscall, v57)
+		for i := 0; i < v57; i++ {
+			v58 := NewPopulatedLinuxSyscall(r, easy)
+			this.Syscalls[i] = *v58
 		}
 	}
 	if !easy && r.Intn(10) != 0 {
@@ -5729,9 +5797,9 @@ func NewPopulatedLinuxSeccompArg(r randyOci, easy bool) *LinuxSeccompArg {
 
 func NewPopulatedLinuxSyscall(r randyOci, easy bool) *LinuxSyscall {
 	this := &LinuxSyscall{}
-	v55 := r.Intn(10)
-	this.Names = make([]string, v55)
-	for i := 0; i < v55; i++ {
+	v59 := r.Intn(10)
+	this.Names = make([]string, v59)
+	for i := 0; i < v59; i++ {
 		this.Names[i] = string(randStringOci(r))
 	}
 	this.Action = string(randStringOci(r))
@@ -5741,11 +5809,11 @@ func NewPopulatedLinuxSyscall(r randyOci, easy bool) *LinuxSyscall {
 		this.ErrnoRet = NewPopulatedLinuxSyscall_Errnoret(r, easy)
 	}
 	if r.Intn(5) != 0 {
-		v56 := r.Intn(5)
-		this.Args = make([]LinuxSeccompArg, v56)
-		for i := 0; i < v56; i++ {
-			v57 := NewPopulatedLinuxSeccompArg(r, easy)
-			this.Args[i] = *v57
+		v60 := r.Intn(5)
+		this.Args = make([]LinuxSeccompArg, v60)
+		for i := 0; i < v60; i++ {
+			v61 := NewPopulatedLinuxSeccompArg(r, easy)
+			this.Args[i] = *v61
 		}
 	}
 	if !easy && r.Intn(10) != 0 {
@@ -5787,9 +5855,9 @@ func randUTF8RuneOci(r randyOci) rune {
 	return rune(ru + 61)
 }
 func randStringOci(r randyOci) string {
-	v58 := r.Intn(100)
-	tmps := make([]rune, v58)
-	for i := 0; i < v58; i++ {
+	v62 := r.Intn(100)
+	tmps := make([]rune, v62)
+	for i := 0; i < v62; i++ {
 		tmps[i] = randUTF8RuneOci(r)
 	}
 	return string(tmps)
@@ -5811,11 +5879,11 @@ func randFieldOci(dAtA []byte, r randyOci, fieldNumber int, wire int) []byte {
 	switch wire {
 	case 0:
 		dAtA = encodeVarintPopulateOci(dAtA, uint64(key))
-		v59 := r.Int63()
+		v63 := r.Int63()
 		if r.Intn(2) == 0 {
-			v59 *= -1
+			v63 *= -1
 		}
-		dAtA = encodeVarintPopulateOci(dAtA, uint64(v59))
+		dAtA = encodeVarintPopulateOci(dAtA, uint64(v63))
 	case 1:
 		dAtA = encodeVarintPopulateOci(dAtA, uint64(key))
 		dAtA = append(dAtA, byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)), byte(r.Intn(256)))
@@ -6143,6 +6211,18 @@ func (m *Hooks) Size() (n int) {
 			n += 1 + l + sovOci(uint64(l))
 		}
 	}
+	if len(m.CreateContainer) > 0 {
+		for _, e := range m.CreateContainer {
+			l = e.Size()
+			n += 1 + l + sovOci(uint64(l))
+		}
+	}
+	if len(m.StartContainer) > 0 {
+		for _, e := range m.StartContainer {
+			l = e.Size()
+			n += 1 + l + sovOci(uint64(l))
+		}
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -6958,10 +7038,22 @@ func (this *Hooks) String() string {
 		repeatedStringForPoststop += strings.Replace(strings.Replace(f.String(), "Hook", "Hook", 1), `&`, ``, 1) + ","
 	}
 	repeatedStringForPoststop += "}"
+	repeatedStringForCreateContainer := "[]Hook{"
+	for _, f := range this.CreateContainer {
+		repeatedStringForCreateContainer += strings.Replace(strings.Replace(f.String(), "Hook", "Hook", 1), `&`, ``, 1) + ","
+	}
+	repeatedStringForCreateContainer += "}"
+	repeatedStringForStartContainer := "[]Hook{"
+	for _, f := range this.StartContainer {
+		repeatedStringForStartContainer += strings.Replace(strings.Replace(f.String(), "Hook", "Hook", 1), `&`, ``, 1) + ","
+	}
+	repeatedStringForStartContainer += "}"
 	s := strings.Join([]string{`&Hooks{`,
 		`Prestart:` + repeatedStringForPrestart + `,`,
 		`Poststart:` + repeatedStringForPoststart + `,`,
 		`Poststop:` + repeatedStringForPoststop + `,`,
+		`CreateContainer:` + repeatedStringForCreateContainer + `,`,
+		`StartContainer:` + repeatedStringForStartContainer + `,`,
 		`XXX_unrecognized:` + fmt.Sprintf("%v", this.XXX_unrecognized) + `,`,
 		`}`,
 	}, "")
@@ -9308,6 +9400,74 @@ func (m *Hooks) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CreateContainer", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOci
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthOci
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthOci
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.CreateContainer = append(m.CreateContainer, Hook{})
+			if err := m.CreateContainer[len(m.CreateContainer)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StartContainer", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowOci
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthOci
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthOci
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.StartContainer = append(m.StartContainer, Hook{})
+			if err := m.StartContainer[len(m.StartContainer)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipOci(dAtA[iNdEx:])
@@ -21,6 +21,10 @@ const (
 	ContainerTypeKey = kataAnnotationsPrefix + "pkg.oci.container_type"
 
 	SandboxConfigPathKey = kataAnnotationsPrefix + "config_path"
+
+	// DevicePlugins is a container annotation asking the device plugins for devices, as a
+	// semicolon separated list of <class>=<id>[,<id>...], e.g. "fpga.example.com=fpga0".
+	DevicePlugins = kataAnnotationsPrefix + "device_plugins"
 )
 
 // Annotations related to Hypervisor configuration
@@ -54,6 +58,13 @@ const (
 	// FirmwarePath is a sandbox annotation for passing a per container path pointing at the guest firmware that will run the container VM.
 	FirmwarePath = kataAnnotHypervisorPrefix + "firmware"
 
+	// RootfsDisk is a sandbox annotation for passing a per container path pointing at a pre-built guest rootfs disk
+	// the container VM boots from, rather than from the guest image or initrd.
+	RootfsDisk = kataAnnotHypervisorPrefix + "rootfs_disk"
+
+	// RootfsDiskFormat is a sandbox annotation to specify the format of the guest rootfs disk (raw or qcow2).
+	RootfsDiskFormat = kataAnnotHypervisorPrefix + "rootfs_disk_format"
+
 	// KernelHash is a sandbox annotation for passing a container kernel image SHA-512 hash value.
 	KernelHash = kataAnnotHypervisorPrefix + "kernel_hash"
 
@@ -94,6 +105,9 @@ const (
 	// CPUFeatures is a sandbox annotation to specify cpu specific features.
 	CPUFeatures = kataAnnotHypervisorPrefix + "cpu_features"
 
+	// CPUModel is a sandbox annotation to specify the guest cpu model.
+	CPUModel = kataAnnotHypervisorPrefix + "cpu_model"
+
 	// DisableVhostNet is a sandbox annotation to specify if vhost-net is not available on the host.
 	DisableVhostNet = kataAnnotHypervisorPrefix + "disable_vhost_net"
 
@@ -199,6 +213,10 @@ const (
 	// DisableBlockDeviceUse  is a sandbox annotation that disallows a block device from being used.
 	DisableBlockDeviceUse = kataAnnotHypervisorPrefix + "disable_block_device_use"
 
+	// LazyBlockDeviceAttach is a sandbox annotation that defers the hotplug of the
+	// containers rootfs block device until they start.
+	LazyBlockDeviceAttach = kataAnnotHypervisorPrefix + "lazy_block_device_attach"
+
 	// EnableIOThreads is a sandbox annotation to enable IO to be processed in a separate thread.
 	// Supported currently for virtio-scsi driver.
 	EnableIOThreads = kataAnnotHypervisorPrefix + "enable_iothreads"
@@ -231,6 +249,12 @@ const (
 	// SandboxCgroupOnly is a sandbox annotation that determines if kata processes are managed only in sandbox cgroup.
 	SandboxCgroupOnly = kataAnnotRuntimePrefix + "sandbox_cgroup_only"
 
+	// CPUQuotaPolicy is a sandbox annotation that determines where the CPU quota of the containers is enforced.
+	CPUQuotaPolicy = kataAnnotRuntimePrefix + "cpu_quota_policy"
+
+	// SharePidNs is a sandbox annotation that determines if all the containers share the sandbox pid namespace.
+	SharePidNs = kataAnnotRuntimePrefix + "share_pid_ns"
+
 	// EnablePprof is a sandbox annotation that determines if pprof enabled.
 	EnablePprof = kataAnnotRuntimePrefix + "enable_pprof"
 
@@ -241,8 +265,33 @@ const (
 	//the container network interface.
 	InterNetworkModel = kataAnnotRuntimePrefix + "internetworking_model"
 
+	// StaticNetworkConfig is a sandbox annotation that sets the network configuration of the sandbox,
+	// in JSON, applied without scanning the network namespace.
+	StaticNetworkConfig = kataAnnotRuntimePrefix + "static_network_config"
+
 	// DisableNewNetNs is a sandbox annotation that determines if create a netns for hypervisor process.
 	DisableNewNetNs = kataAnnotRuntimePrefix + "disable_new_netns"
+
+	// EphemeralDiskSizeMB is a sandbox annotation that sets the size of the disk backing the local
+	// volumes, usually the ephemeral storage limit of the pod.
+	EphemeralDiskSizeMB = kataAnnotRuntimePrefix + "ephemeral_disk_size_mb"
+
+	// EphemeralDiskEncryption is a sandbox annotation that determines if the disk backing the local
+	// volumes is encrypted by the agent with a key generated in the guest.
+	EphemeralDiskEncryption = kataAnnotRuntimePrefix + "ephemeral_disk_encryption"
+
+	// GuestSwapSizeMB is a sandbox annotation that sets the size of the swap device of the guest,
+	// the guest has no swap when zero.
+	GuestSwapSizeMB = kataAnnotRuntimePrefix + "guest_swap_size_mb"
+
+	// GuestSwappiness is a sandbox annotation that sets the vm.swappiness of the guest.
+	GuestSwappiness = kataAnnotRuntimePrefix + "guest_swappiness"
+
+	// SharedMemoryRegions is a sandbox annotation that sets the memory regions shared with the
+	// other sandboxes of the host using the same names, as a comma separated list of
+	// <name>=<size>, e.g. "accel=64Mi". It is experimental and needs the SharedMemoryChannel
+	// feature gate.
+	SharedMemoryRegions = kataAnnotRuntimePrefix + "shared_memory_regions"
 )
 
 // Agent related annotations
@@ -271,6 +320,27 @@ const (
 	// AgentTraceMode is a sandbox annotation to specify the trace type for the agent.
 	AgentTraceType = kataAnnotAgentPrefix + "trace_type"
 
+	// AgentMetricsGroups is a sandbox annotation to select the groups of metrics
+	// collected by the agent, as a comma separated list, e.g. "proc,meminfo".
+	AgentMetricsGroups = kataAnnotAgentPrefix + "metrics_groups"
+
+	// AgentAllowedAPIs is a sandbox annotation to restrict the requests served
+	// by the agent, as a comma separated list, e.g. "grpc.StatsContainerRequest".
+	// It can only narrow the allow-list of the configuration file.
+	AgentAllowedAPIs = kataAnnotAgentPrefix + "allowed_apis"
+
+	// AgentDialTimeout is a sandbox annotation to specify the timeout to dial
+	// the agent, in seconds.
+	AgentDialTimeout = kataAnnotAgentPrefix + "dial_timeout"
+
+	// AgentDialBackoffInitial is a sandbox annotation to specify the initial
+	// delay between the attempts to dial the agent, in milliseconds.
+	AgentDialBackoffInitial = kataAnnotAgentPrefix + "dial_backoff_initial_ms"
+
+	// AgentDialBackoffMax is a sandbox annotation to specify the maximum
+	// delay between the attempts to dial the agent, in milliseconds.
+	AgentDialBackoffMax = kataAnnotAgentPrefix + "dial_backoff_max_ms"
+
 	// AgentContainerPipeSize is an annotation to specify the size of the pipes created for containers
 	AgentContainerPipeSize       = kataAnnotAgentPrefix + ContainerPipeSizeOption
 	ContainerPipeSizeOption      = "container_pipe_size"
@@ -22,4 +22,7 @@ const (
 
 	// SandboxIDLabelKey is the sandbox ID annotation
 	SandboxIDLabelKey = "io.kubernetes.sandbox.id"
+
+	// ContainerNameLabelKey is the container name annotation
+	ContainerNameLabelKey = "io.kubernetes.container.name"
 )
@@ -0,0 +1,307 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+// Package blockdedup shares the block devices of the container images
+// across the sandboxes of a host.
+//
+// When the snapshotter provides the same image base block device to
+// several containers, the device is exposed once as a read-only base
+// device, and each container is given a copy on write device-mapper
+// snapshot of it. The base is reference counted across the runtime
+// processes, and removed along with its last snapshot.
+//
+// The bases are identified by the device number of the source devices, so
+// the devices are only shared when the snapshotter gives the same device to
+// the containers of an image, e.g. a read-only device per image. The
+// devmapper snapshotter of containerd creates a thin device per container,
+// whose bases are never shared.
+package blockdedup
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/utils"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	basePrefix     = "kata-base-"
+	snapshotPrefix = "kata-cow-"
+	cowSuffix      = "-cow.img"
+	lockFile       = "lock"
+
+	// the exception store of the snapshots is sparse, it is given the
+	// size of the base plus room for the snapshot metadata
+	cowMetadataSize = 64 << 20
+
+	// chunk size of the snapshots, in sectors
+	chunkSize = 8
+)
+
+// stateDir holds the reference counts of the base devices.
+var stateDir = "/run/kata-containers/block-dedup"
+
+// deviceKey identifies the base of a block device, by its device number
+// rather than its content, it is replaced by the tests.
+var deviceKey = func(path string) (string, error) {
+	var stat unix.Stat_t
+	if err := unix.Stat(path, &stat); err != nil {
+		return "", fmt.Errorf("stat %q failed: %v", path, err)
+	}
+
+	if stat.Mode&unix.S_IFMT != unix.S_IFBLK {
+		return "", fmt.Errorf("%q is not a block device", path)
+	}
+
+	return fmt.Sprintf("%d-%d", unix.Major(stat.Rdev), unix.Minor(stat.Rdev)), nil
+}
+
+// base is the reference count of a base device.
+type base struct {
+	Source string   `json:"source"`
+	Users  []string `json:"users"`
+}
+
+func basePath(key string) string {
+	return filepath.Join(stateDir, key+".json")
+}
+
+func baseDevice(key string) string {
+	return filepath.Join("/dev/mapper", basePrefix+key)
+}
+
+func snapshotName(containerID string) string {
+	return snapshotPrefix + containerID
+}
+
+func cowPath(cowDir, containerID string) string {
+	return filepath.Join(cowDir, containerID+cowSuffix)
+}
+
+// lock serializes the updates of the bases across the runtime processes.
+func lock() (*os.File, error) {
+	if err := os.MkdirAll(stateDir, 0700); err != nil {
+		return nil, err
+	}
+
+	f, err := os.OpenFile(filepath.Join(stateDir, lockFile), os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := unix.Flock(int(f.Fd()), unix.LOCK_EX); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return f, nil
+}
+
+func unlock(f *os.File) {
+	unix.Flock(int(f.Fd()), unix.LOCK_UN)
+	f.Close()
+}
+
+func loadBase(key string) (*base, error) {
+	data, err := ioutil.ReadFile(basePath(key))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var b base
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, err
+	}
+
+	return &b, nil
+}
+
+func storeBase(key string, b *base) error {
+	if len(b.Users) == 0 {
+		return os.Remove(basePath(key))
+	}
+
+	data, err := json.Marshal(b)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(basePath(key), data, 0600)
+}
+
+// sectors returns the size of a block device in 512 bytes sectors.
+func sectors(device string) (uint64, error) {
+	out, err := utils.RunHostCommand("blockdev", "--getsz", device)
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.ParseUint(out, 10, 64)
+}
+
+// Acquire returns the path of a copy on write snapshot of source for the
+// container, stored in cowDir. The base of source is created unless
+// another container uses it already.
+func Acquire(source, cowDir, containerID string) (device string, err error) {
+	key, err := deviceKey(source)
+	if err != nil {
+		return "", err
+	}
+
+	l, err := lock()
+	if err != nil {
+		return "", err
+	}
+	defer unlock(l)
+
+	b, err := loadBase(key)
+	if err != nil {
+		return "", err
+	}
+
+	size, err := sectors(source)
+	if err != nil {
+		return "", err
+	}
+
+	if b == nil {
+		table := fmt.Sprintf("0 %d linear %s 0", size, source)
+		if _, err := utils.RunHostCommand("dmsetup", "create", basePrefix+key, "--readonly", "--table", table); err != nil {
+			return "", err
+		}
+		b = &base{Source: source}
+
+		defer func() {
+			if err != nil {
+				utils.RunHostCommand("dmsetup", "remove", basePrefix+key)
+			}
+		}()
+	}
+
+	loop, err := createCow(cowPath(cowDir, containerID), int64(size)*512+cowMetadataSize)
+	if err != nil {
+		return "", err
+	}
+
+	table := fmt.Sprintf("0 %d snapshot %s %s N %d", size, baseDevice(key), loop, chunkSize)
+	if _, err = utils.RunHostCommand("dmsetup", "create", snapshotName(containerID), "--table", table); err != nil {
+		removeCow(cowPath(cowDir, containerID))
+		return "", err
+	}
+
+	b.Users = append(b.Users, containerID)
+	if err = storeBase(key, b); err != nil {
+		utils.RunHostCommand("dmsetup", "remove", snapshotName(containerID))
+		removeCow(cowPath(cowDir, containerID))
+		return "", err
+	}
+
+	return filepath.Join("/dev/mapper", snapshotName(containerID)), nil
+}
+
+// Release removes the snapshot of the container, and the base
+// it was created from unless other containers use it.
+func Release(cowDir, containerID string) error {
+	l, err := lock()
+	if err != nil {
+		return err
+	}
+	defer unlock(l)
+
+	files, err := filepath.Glob(filepath.Join(stateDir, "*.json"))
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		key := strings.TrimSuffix(filepath.Base(file), ".json")
+		b, err := loadBase(key)
+		if err != nil || b == nil {
+			continue
+		}
+
+		users := []string{}
+		for _, u := range b.Users {
+			if u != containerID {
+				users = append(users, u)
+			}
+		}
+		if len(users) == len(b.Users) {
+			continue
+		}
+
+		if _, err := utils.RunHostCommand("dmsetup", "remove", snapshotName(containerID)); err != nil {
+			return err
+		}
+		if err := removeCow(cowPath(cowDir, containerID)); err != nil {
+			return err
+		}
+
+		if len(users) == 0 {
+			if _, err := utils.RunHostCommand("dmsetup", "remove", basePrefix+key); err != nil {
+				return err
+			}
+		}
+
+		b.Users = users
+		return storeBase(key, b)
+	}
+
+	return nil
+}
+
+// createCow creates the sparse exception store of a
+// snapshot and returns the loop device exposing it.
+func createCow(path string, size int64) (string, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return "", err
+	}
+
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return "", err
+	}
+	err = f.Truncate(size)
+	f.Close()
+	if err != nil {
+		os.Remove(path)
+		return "", err
+	}
+
+	loop, err := utils.RunHostCommand("losetup", "--find", "--show", path)
+	if err != nil {
+		os.Remove(path)
+		return "", err
+	}
+
+	return loop, nil
+}
+
+func removeCow(path string) error {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil
+	}
+
+	out, err := utils.RunHostCommand("losetup", "--noheadings", "--output", "NAME", "--associated", path)
+	if err != nil {
+		return err
+	}
+	for _, loop := range strings.Fields(out) {
+		if _, err := utils.RunHostCommand("losetup", "--detach", loop); err != nil {
+			return err
+		}
+	}
+
+	return os.Remove(path)
+}
@@ -0,0 +1,106 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package blockdedup
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func mockHost(t *testing.T) (string, *[]string, func()) {
+	dir, err := ioutil.TempDir("", "block-dedup")
+	assert.NoError(t, err)
+
+	var commands []string
+
+	savedDir, savedRun, savedKey := stateDir, utils.RunHostCommand, deviceKey
+	stateDir = filepath.Join(dir, "state")
+	utils.RunHostCommand = func(name string, args ...string) (string, error) {
+		commands = append(commands, strings.Join(append([]string{name}, args...), " "))
+		switch name {
+		case "blockdev":
+			return "2048", nil
+		case "losetup":
+			return "/dev/loop3", nil
+		}
+		return "", nil
+	}
+	deviceKey = func(path string) (string, error) {
+		return "253-" + filepath.Base(path), nil
+	}
+
+	return dir, &commands, func() {
+		stateDir, utils.RunHostCommand, deviceKey = savedDir, savedRun, savedKey
+		os.RemoveAll(dir)
+	}
+}
+
+func TestAcquireRelease(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, commands, restore := mockHost(t)
+	defer restore()
+
+	cowDir := filepath.Join(dir, "sandbox")
+
+	// the first container creates the base
+	device, err := Acquire("/dev/dm-4", cowDir, "c1")
+	assert.NoError(err)
+	assert.Equal("/dev/mapper/kata-cow-c1", device)
+	assert.Equal([]string{
+		"blockdev --getsz /dev/dm-4",
+		"dmsetup create kata-base-253-dm-4 --readonly --table 0 2048 linear /dev/dm-4 0",
+		"losetup --find --show " + filepath.Join(cowDir, "c1-cow.img"),
+		"dmsetup create kata-cow-c1 --table 0 2048 snapshot /dev/mapper/kata-base-253-dm-4 /dev/loop3 N 8",
+	}, *commands)
+
+	info, err := os.Stat(filepath.Join(cowDir, "c1-cow.img"))
+	assert.NoError(err)
+	assert.Equal(int64(2048*512+cowMetadataSize), info.Size())
+
+	// the second one shares it
+	*commands = nil
+	device, err = Acquire("/dev/dm-4", cowDir, "c2")
+	assert.NoError(err)
+	assert.Equal("/dev/mapper/kata-cow-c2", device)
+	assert.Equal([]string{
+		"blockdev --getsz /dev/dm-4",
+		"losetup --find --show " + filepath.Join(cowDir, "c2-cow.img"),
+		"dmsetup create kata-cow-c2 --table 0 2048 snapshot /dev/mapper/kata-base-253-dm-4 /dev/loop3 N 8",
+	}, *commands)
+
+	b, err := loadBase("253-dm-4")
+	assert.NoError(err)
+	assert.Equal(&base{Source: "/dev/dm-4", Users: []string{"c1", "c2"}}, b)
+
+	// the base is kept while used
+	*commands = nil
+	assert.NoError(Release(cowDir, "c1"))
+	assert.Equal([]string{
+		"dmsetup remove kata-cow-c1",
+		"losetup --noheadings --output NAME --associated " + filepath.Join(cowDir, "c1-cow.img"),
+		"losetup --detach /dev/loop3",
+	}, *commands)
+
+	*commands = nil
+	assert.NoError(Release(cowDir, "c2"))
+	assert.Contains(*commands, "dmsetup remove kata-base-253-dm-4")
+
+	b, err = loadBase("253-dm-4")
+	assert.NoError(err)
+	assert.Nil(b)
+
+	// unknown container
+	*commands = nil
+	assert.NoError(Release(cowDir, "c3"))
+	assert.Empty(*commands)
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/opencontainers/runc/libcontainer"
 	libcontcgroups "github.com/opencontainers/runc/libcontainer/cgroups"
 	libcontcgroupsfs "github.com/opencontainers/runc/libcontainer/cgroups/fs"
+	libcontcgroupsfs2 "github.com/opencontainers/runc/libcontainer/cgroups/fs2"
 	"github.com/opencontainers/runc/libcontainer/configs"
 	"github.com/opencontainers/runc/libcontainer/specconv"
 	"github.com/opencontainers/runtime-spec/specs-go"
@@ -42,6 +43,10 @@ type Config struct {
 
 	// CgroupPath is the OCI spec cgroup path
 	CgroupPath string
+
+	// Systemd is whether the cgroups are managed by systemd, as the
+	// cgroup driver of the CRI
+	Systemd bool
 }
 
 type Manager struct {
@@ -111,8 +116,9 @@ func New(config *Config) (*Manager, error) {
 	cgroups := config.Cgroups
 	cgroupPaths := config.CgroupPaths
 
-	// determine if we are utilizing systemd managed cgroups based on the path provided
-	useSystemdCgroup := IsSystemdCgroup(config.CgroupPath)
+	// determine if we are utilizing systemd managed cgroups based on the
+	// cgroup driver or the path provided
+	useSystemdCgroup := config.Systemd || IsSystemdCgroup(config.CgroupPath)
 
 	// Create a new cgroup if the current one is nil
 	// this cgroups must be saved later
@@ -165,6 +171,17 @@ func New(config *Config) (*Manager, error) {
 		}, nil
 	}
 
+	if libcontcgroups.IsCgroup2UnifiedMode() {
+		mgr, err := libcontcgroupsfs2.NewManager(cgroups, cgroupPaths[""], rootless)
+		if err != nil {
+			return nil, fmt.Errorf("Could not create cgroup v2 manager: %v", err)
+		}
+
+		return &Manager{
+			mgr: mgr,
+		}, nil
+	}
+
 	return &Manager{
 		mgr: libcontcgroupsfs.NewManager(cgroups, cgroupPaths, rootless),
 	}, nil
@@ -10,6 +10,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/opencontainers/runc/libcontainer/cgroups/systemd"
 	"github.com/opencontainers/runc/libcontainer/devices"
 	"github.com/opencontainers/runtime-spec/specs-go"
 	"golang.org/x/sys/unix"
@@ -75,6 +76,30 @@ func IsSystemdCgroup(cgroupPath string) bool {
 	return false
 }
 
+// CgroupfsPath returns the path of a cgroup relative to the cgroups mount
+// point. A systemd path, slice:prefix:name, is expanded to the path of the
+// unit systemd creates for it, e.g. kubepods.slice:cri-containerd:abc is
+// /kubepods.slice/cri-containerd-abc.scope.
+func CgroupfsPath(path string) (string, error) {
+	if !IsSystemdCgroup(path) {
+		return path, nil
+	}
+
+	parts := strings.Split(path, ":")
+	slice, err := systemd.ExpandSlice(parts[0])
+	if err != nil {
+		return "", err
+	}
+
+	// systemd creates a scope unless a slice is explicitly asked for
+	unit := parts[2]
+	if !strings.HasSuffix(unit, ".slice") {
+		unit = parts[1] + "-" + unit + ".scope"
+	}
+
+	return filepath.Join(slice, unit), nil
+}
+
 func DeviceToCgroupDeviceRule(device string) (*devices.Rule, error) {
 	var st unix.Stat_t
 	deviceRule := devices.Rule{
@@ -105,6 +105,30 @@ func TestValidCgroupPath(t *testing.T) {
 
 }
 
+func TestCgroupfsPath(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, t := range []struct {
+		path     string
+		expected string
+		error    bool
+	}{
+		{"/kata/afhts2e5d4g5s", "/kata/afhts2e5d4g5s", false},
+		{"system.slice:kata:afhts2e5d4g5s", "/system.slice/kata-afhts2e5d4g5s.scope", false},
+		{"kubepods-besteffort-pod1.slice:cri-containerd:abc", "/kubepods.slice/kubepods-besteffort.slice/kubepods-besteffort-pod1.slice/cri-containerd-abc.scope", false},
+		{"system.slice:kata:kata.slice", "/system.slice/kata.slice", false},
+		{"a-.slice:kata:abc", "", true},
+	} {
+		path, err := CgroupfsPath(t.path)
+		if t.error {
+			assert.Error(err, t.path)
+			continue
+		}
+		assert.NoError(err, t.path)
+		assert.Equal(t.expected, path)
+	}
+}
+
 func TestDeviceToCgroupDeviceRule(t *testing.T) {
 	assert := assert.New(t)
 
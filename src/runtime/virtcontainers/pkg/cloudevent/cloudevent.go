@@ -0,0 +1,125 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+// Package cloudevent posts the events of the runtime to an HTTP sink as
+// CloudEvents, in the structured content mode.
+package cloudevent
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel/propagation"
+)
+
+const (
+	// ContentType is the content type of the events in the structured
+	// content mode.
+	ContentType = "application/cloudevents+json"
+
+	// SpecVersion is the version of the CloudEvents specification of
+	// the events.
+	SpecVersion = "1.0"
+)
+
+// Event is a CloudEvent whose data is a JSON value.
+type Event struct {
+	SpecVersion     string      `json:"specversion"`
+	ID              string      `json:"id"`
+	Source          string      `json:"source"`
+	Type            string      `json:"type"`
+	Time            time.Time   `json:"time"`
+	DataContentType string      `json:"datacontenttype"`
+	Data            interface{} `json:"data"`
+
+	// TraceParent and TraceState are the extension attributes of the
+	// distributed tracing extension, the W3C trace context of the event.
+	TraceParent string `json:"traceparent,omitempty"`
+	TraceState  string `json:"tracestate,omitempty"`
+}
+
+// New returns an event with a random ID.
+func New(source, eventType string, t time.Time, data interface{}) (Event, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return Event{}, err
+	}
+
+	return Event{
+		SpecVersion:     SpecVersion,
+		ID:              fmt.Sprintf("%x", id),
+		Source:          source,
+		Type:            eventType,
+		Time:            t,
+		DataContentType: "application/json",
+		Data:            data,
+	}, nil
+}
+
+// SetTraceContext sets the trace context of the event from the span of ctx,
+// it is left empty when ctx has no valid span, e.g. when tracing is disabled.
+func (e *Event) SetTraceContext(ctx context.Context) {
+	propagation.TraceContext{}.Inject(ctx, traceCarrier{e})
+}
+
+// traceCarrier sets the trace context attributes of an event.
+type traceCarrier struct {
+	e *Event
+}
+
+func (c traceCarrier) Get(key string) string {
+	switch key {
+	case "traceparent":
+		return c.e.TraceParent
+	case "tracestate":
+		return c.e.TraceState
+	}
+	return ""
+}
+
+func (c traceCarrier) Set(key, value string) {
+	switch key {
+	case "traceparent":
+		c.e.TraceParent = value
+	case "tracestate":
+		c.e.TraceState = value
+	}
+}
+
+// Post sends the event to the sink URL once, see Deliver for the retries.
+func Post(client *http.Client, sink string, e Event) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Post(sink, ContentType, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return &SinkError{Sink: sink, StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+
+	return nil
+}
+
+// SinkError is returned when the sink answers with an error status.
+type SinkError struct {
+	Sink       string
+	StatusCode int
+	Status     string
+}
+
+func (e *SinkError) Error() string {
+	return fmt.Sprintf("sink %s rejected event: %s", e.Sink, e.Status)
+}
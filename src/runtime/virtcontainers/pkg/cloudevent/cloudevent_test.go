@@ -0,0 +1,85 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package cloudevent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestPost(t *testing.T) {
+	assert := assert.New(t)
+
+	var received Event
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != ContentType {
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&received)
+	}))
+	defer srv.Close()
+
+	now := time.Now().UTC()
+	e, err := New("/test", "io.katacontainers.test", now, map[string]string{"foo": "bar"})
+	assert.NoError(err)
+	assert.Len(e.ID, 32)
+
+	assert.NoError(Post(srv.Client(), srv.URL, e))
+	assert.Equal(SpecVersion, received.SpecVersion)
+	assert.Equal(e.ID, received.ID)
+	assert.Equal("/test", received.Source)
+	assert.Equal("io.katacontainers.test", received.Type)
+	assert.True(now.Equal(received.Time))
+	assert.Equal(map[string]interface{}{"foo": "bar"}, received.Data)
+
+	e2, err := New("/test", "io.katacontainers.test", now, nil)
+	assert.NoError(err)
+	assert.NotEqual(e.ID, e2.ID)
+}
+
+func TestPostRejected(t *testing.T) {
+	assert := assert.New(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	e, err := New("/test", "io.katacontainers.test", time.Now(), nil)
+	assert.NoError(err)
+	assert.Error(Post(srv.Client(), srv.URL, e))
+}
+
+func TestSetTraceContext(t *testing.T) {
+	assert := assert.New(t)
+
+	e, err := New("/test", "io.katacontainers.test", time.Now(), nil)
+	assert.NoError(err)
+
+	// no span, e.g. tracing is disabled
+	e.SetTraceContext(context.Background())
+	assert.Empty(e.TraceParent)
+
+	body, err := json.Marshal(e)
+	assert.NoError(err)
+	assert.NotContains(string(body), "traceparent")
+
+	ctx, span := sdktrace.NewTracerProvider().Tracer("test").Start(context.Background(), "test")
+	defer span.End()
+
+	e.SetTraceContext(ctx)
+	sc := span.SpanContext()
+	assert.Equal(fmt.Sprintf("00-%s-%s-01", sc.TraceID, sc.SpanID), e.TraceParent)
+}
@@ -0,0 +1,187 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package cloudevent
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// DefaultRetries is the number of retries of the events whose
+	// delivery can be delayed.
+	DefaultRetries = 3
+
+	// the delay before the first retry of a failed post, doubled for
+	// each retry
+	retryBackoff = 200 * time.Millisecond
+
+	// the largest dead-letter file, the events are dropped once reached
+	maxDeadLetterSize = 16 << 20
+)
+
+// DeadLetterPath is the file where the events not delivered after their
+// retries are appended, one JSON DeadLetter per line, so that they can be
+// replayed. It is shared by all the shims of the host.
+var DeadLetterPath = "/run/kata-containers/cloudevents/dead-letter.jsonl"
+
+var deadLetterLock sync.Mutex
+
+var errDeadLetterFull = errors.New("dead-letter file full")
+
+var (
+	eventsPublished = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "kata_shim",
+		Name:      "cloudevents_published_total",
+		Help:      "CloudEvents delivered to the sinks.",
+	},
+		[]string{"sink", "type"},
+	)
+
+	eventsFailed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "kata_shim",
+		Name:      "cloudevents_failed_total",
+		Help:      "CloudEvents not delivered after their retries, appended to the dead-letter file.",
+	},
+		[]string{"sink", "type"},
+	)
+
+	eventsRetries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "kata_shim",
+		Name:      "cloudevents_retries_total",
+		Help:      "Posts of CloudEvents retried after a failure.",
+	},
+		[]string{"sink", "type"},
+	)
+
+	eventsDeliveryDurations = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "kata_shim",
+		Name:      "cloudevents_delivery_duration_seconds",
+		Help:      "Time to deliver the CloudEvents, retries included.",
+		Buckets:   prometheus.ExponentialBuckets(0.005, 4, 8),
+	},
+		[]string{"sink", "type", "result"},
+	)
+
+	eventsDeadLetterFailures = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "kata_shim",
+		Name:      "cloudevents_dead_letter_failures_total",
+		Help:      "CloudEvents lost as they could not be appended to the dead-letter file.",
+	})
+)
+
+// RegisterMetrics registers the metrics of the delivery of the events.
+func RegisterMetrics(r prometheus.Registerer) {
+	r.MustRegister(eventsPublished)
+	r.MustRegister(eventsFailed)
+	r.MustRegister(eventsRetries)
+	r.MustRegister(eventsDeliveryDurations)
+	r.MustRegister(eventsDeadLetterFailures)
+}
+
+// DeadLetter is an event not delivered to its sink.
+type DeadLetter struct {
+	Time  time.Time `json:"time"`
+	Sink  string    `json:"sink"`
+	Error string    `json:"error"`
+	Event Event     `json:"event"`
+}
+
+// Deliver posts the event to the sink, retrying up to retries times with an
+// exponential backoff when the sink is unreachable or fails. The event is
+// appended to the dead-letter file when it is still not delivered, or when
+// the sink rejects it.
+func Deliver(client *http.Client, sink string, e Event, retries int) error {
+	start := time.Now()
+	label := sinkLabel(sink)
+
+	backoff := retryBackoff
+	err := Post(client, sink, e)
+	for i := 0; i < retries && err != nil && retriable(err); i++ {
+		eventsRetries.WithLabelValues(label, e.Type).Inc()
+		time.Sleep(backoff)
+		backoff *= 2
+
+		err = Post(client, sink, e)
+	}
+
+	if err == nil {
+		eventsPublished.WithLabelValues(label, e.Type).Inc()
+		eventsDeliveryDurations.WithLabelValues(label, e.Type, "success").Observe(time.Since(start).Seconds())
+		return nil
+	}
+
+	eventsFailed.WithLabelValues(label, e.Type).Inc()
+	eventsDeliveryDurations.WithLabelValues(label, e.Type, "failure").Observe(time.Since(start).Seconds())
+
+	if dlErr := writeDeadLetter(DeadLetter{Time: time.Now().UTC(), Sink: sink, Error: err.Error(), Event: e}); dlErr != nil {
+		eventsDeadLetterFailures.Inc()
+	}
+
+	return err
+}
+
+// retriable returns true if the post may succeed later, i.e. the sink is
+// unreachable, fails or throttles the events.
+func retriable(err error) bool {
+	var sinkErr *SinkError
+	if errors.As(err, &sinkErr) {
+		return sinkErr.StatusCode >= http.StatusInternalServerError || sinkErr.StatusCode == http.StatusTooManyRequests
+	}
+	return true
+}
+
+// sinkLabel returns the host of a sink URL, as the label of its metrics,
+// without its path or credentials.
+func sinkLabel(sink string) string {
+	u, err := url.Parse(sink)
+	if err != nil || u.Host == "" {
+		return "unknown"
+	}
+	return u.Host
+}
+
+// writeDeadLetter appends a dead letter to the dead-letter file, unless it
+// reached maxDeadLetterSize.
+func writeDeadLetter(dl DeadLetter) error {
+	line, err := json.Marshal(dl)
+	if err != nil {
+		return err
+	}
+
+	deadLetterLock.Lock()
+	defer deadLetterLock.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(DeadLetterPath), 0700); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(DeadLetterPath, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if fi.Size()+int64(len(line))+1 > maxDeadLetterSize {
+		return errDeadLetterFull
+	}
+
+	// a single write, the shims appending to the same file
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
@@ -91,6 +91,10 @@ func (p *HybridVSockTTRPCMockImp) CreateContainer(ctx context.Context, req *pb.C
 	return emptyResp, nil
 }
 
+func (p *HybridVSockTTRPCMockImp) CreateContainerBatch(ctx context.Context, req *pb.CreateContainerBatchRequest) (*gpb.Empty, error) {
+	return emptyResp, nil
+}
+
 func (p *HybridVSockTTRPCMockImp) StartContainer(ctx context.Context, req *pb.StartContainerRequest) (*gpb.Empty, error) {
 	return emptyResp, nil
 }
@@ -219,6 +223,26 @@ func (p *HybridVSockTTRPCMockImp) GetOOMEvent(ctx context.Context, req *pb.GetOO
 	return &pb.OOMEvent{}, nil
 }
 
+func (p *HybridVSockTTRPCMockImp) GetMemoryEvent(ctx context.Context, req *pb.GetMemoryEventRequest) (*pb.MemoryEvent, error) {
+	return &pb.MemoryEvent{}, nil
+}
+
 func (p *HybridVSockTTRPCMockImp) GetMetrics(ctx context.Context, req *pb.GetMetricsRequest) (*pb.Metrics, error) {
 	return &pb.Metrics{}, nil
 }
+
+func (p *HybridVSockTTRPCMockImp) GetGuestStatus(ctx context.Context, req *pb.GetGuestStatusRequest) (*pb.GuestStatus, error) {
+	return &pb.GuestStatus{}, nil
+}
+
+func (p *HybridVSockTTRPCMockImp) SetNetworkPolicy(ctx context.Context, req *pb.SetNetworkPolicyRequest) (*gpb.Empty, error) {
+	return emptyResp, nil
+}
+
+func (p *HybridVSockTTRPCMockImp) SetLogLevel(ctx context.Context, req *pb.SetLogLevelRequest) (*gpb.Empty, error) {
+	return emptyResp, nil
+}
+
+func (p *HybridVSockTTRPCMockImp) SetDebugConsole(ctx context.Context, req *pb.SetDebugConsoleRequest) (*gpb.Empty, error) {
+	return emptyResp, nil
+}
@@ -16,6 +16,7 @@ import (
 	"strconv"
 	"strings"
 	"syscall"
+	"time"
 
 	criContainerdAnnotations "github.com/containerd/cri-containerd/pkg/annotations"
 	crioAnnotations "github.com/cri-o/cri-o/pkg/annotations"
@@ -31,6 +32,10 @@ import (
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/types"
 )
 
+// criContainerdContainerName is the container name annotation set by
+// containerd CRI plugin, not available in the vendored cri-containerd yet.
+const criContainerdContainerName = "io.kubernetes.cri.container-name"
+
 type annotationContainerType struct {
 	annotation    string
 	containerType vc.ContainerType
@@ -48,6 +53,10 @@ var (
 	// the sandbox ID (sandbox ID) from annotations in the config.json.
 	CRISandboxNameKeyList = []string{criContainerdAnnotations.SandboxID, crioAnnotations.SandboxID, dockershimAnnotations.SandboxIDLabelKey}
 
+	// CRIContainerNameKeyList lists all the CRI keys that could define
+	// the container name from annotations in the config.json.
+	CRIContainerNameKeyList = []string{criContainerdContainerName, crioAnnotations.ContainerName, dockershimAnnotations.ContainerNameLabelKey}
+
 	// CRIContainerTypeList lists all the maps from CRI ContainerTypes annotations
 	// to a virtcontainers ContainerType.
 	CRIContainerTypeList = []annotationContainerType{
@@ -117,20 +126,92 @@ type RuntimeConfig struct {
 	//Experimental features enabled
 	Experimental []exp.Feature
 
+	//State of the feature gates, set from the configuration and the environment
+	FeatureGates map[string]bool
+
 	Debug bool
 	Trace bool
 
 	//Determines if seccomp should be applied inside guest
 	DisableGuestSeccomp bool
 
+	// GuestOCIHooks runs the createContainer, startContainer and poststop
+	// OCI hooks of the containers in the guest
+	GuestOCIHooks bool
+
+	// HostContainerOCIHooks runs the createContainer and startContainer
+	// OCI hooks on the host when they are not run in the guest
+	HostContainerOCIHooks bool
+
 	//Determines if create a netns for hypervisor process
 	DisableNewNetNs bool
 
 	//Determines kata processes are managed only in sandbox cgroup
 	SandboxCgroupOnly bool
 
+	// CPUQuotaPolicy tells where the CPU quota of the containers is enforced
+	CPUQuotaPolicy vc.CPUQuotaPolicy
+
+	//Determines if all the containers share the sandbox pid namespace
+	SharePidNs bool
+
+	// SandboxesPerShim is the maximum number of sandboxes served by
+	// a shim process, each sandbox has its own shim when lower than 2
+	SandboxesPerShim uint32
+
+	// ContainerParallelism is the maximum number of containers created
+	// or started at the same time, with the sandbox or by the shim
+	ContainerParallelism uint32
+
 	// Determines if enable pprof
 	EnablePprof bool
+
+	// ShimMetricsGroups selects the groups of optional metrics exposed
+	// by the shim, all of them are exposed when it is empty
+	ShimMetricsGroups []string
+
+	// ShimLogFormat is the format of the shim log, text when empty
+	ShimLogFormat string
+
+	// MemoryEventsSink is the URL where the memory events of the
+	// containers are posted as CloudEvents
+	MemoryEventsSink string
+
+	// ExitReportSink is the URL where the exit reports of the sandboxes
+	// are posted as CloudEvents
+	ExitReportSink string
+
+	// ManagementTokenFile is the file holding the bearer token of the
+	// privileged requests of the shim management socket
+	ManagementTokenFile string
+
+	// Audit log of the privileged operations
+	AuditConfig vc.AuditConfig
+
+	// Disk backing the local volumes of the sandboxes
+	EphemeralDiskConfig vc.EphemeralDiskConfig
+
+	// Share the rootfs block devices across the sandboxes
+	RootfsDedup    bool
+	RootfsDedupDir string
+
+	// Keep the devices of the stopped containers attached for reuse
+	DeviceReuseTimeout time.Duration
+
+	// Classes of the device plugins the containers can ask for devices
+	DevicePluginClasses []string
+
+	// Sandbox metadata passed to the guest at boot
+	InitDataConfig vc.InitDataConfig
+
+	// Check of the host free memory before launching the VM
+	AdmissionConfig vc.AdmissionConfig
+
+	// Swap device of the guest
+	GuestSwapConfig vc.GuestSwapConfig
+
+	// Limits of the memory regions shared between sandboxes
+	SharedMemoryConfig vc.SharedMemoryConfig
 }
 
 // AddKernelParam allows the addition of new kernel parameters to an existing
@@ -239,6 +320,26 @@ func checkAnnotationNameIsValid(list []string, name string, prefix string) bool
 	return true
 }
 
+// restrictedAnnotations are the annotations outside of the hypervisor ones
+// which let a pod use host resources or bypass the checks of the host. Like
+// the hypervisor annotations, they are only accepted when their base name is
+// enabled by enable_annotations.
+var restrictedAnnotations = map[string]string{
+	vcAnnotations.StaticNetworkConfig:     "static_network_config",
+	vcAnnotations.CPUQuotaPolicy:          "cpu_quota_policy",
+	vcAnnotations.GuestSwapSizeMB:         "guest_swap_size_mb",
+	vcAnnotations.SharedMemoryRegions:     "shared_memory_regions",
+	vcAnnotations.DevicePlugins:           "device_plugins",
+	vcAnnotations.AgentDialTimeout:        "dial_timeout",
+	vcAnnotations.AgentDialBackoffInitial: "dial_backoff_initial_ms",
+	vcAnnotations.AgentDialBackoffMax:     "dial_backoff_max_ms",
+}
+
+func checkRestrictedAnnotationIsEnabled(list []string, name string) bool {
+	base, ok := restrictedAnnotations[name]
+	return !ok || regexpContains(list, base)
+}
+
 func newLinuxDeviceInfo(d specs.LinuxDevice) (*config.DeviceInfo, error) {
 	allowedDeviceTypes := []string{"c", "b", "u", "p"}
 
@@ -371,11 +472,26 @@ func SandboxID(spec specs.Spec) (string, error) {
 	return "", fmt.Errorf("Could not find sandbox ID")
 }
 
+// ContainerName returns the name given to the container by the CRI server,
+// or an empty string if the container was not created through CRI.
+func ContainerName(spec specs.Spec) string {
+	for _, key := range CRIContainerNameKeyList {
+		if name, ok := spec.Annotations[key]; ok {
+			return name
+		}
+	}
+
+	return ""
+}
+
 func addAnnotations(ocispec specs.Spec, config *vc.SandboxConfig, runtime RuntimeConfig) error {
 	for key := range ocispec.Annotations {
 		if !checkAnnotationNameIsValid(runtime.HypervisorConfig.EnableAnnotations, key, vcAnnotations.KataAnnotationHypervisorPrefix) {
 			return fmt.Errorf("annotation %v is not enabled", key)
 		}
+		if !checkRestrictedAnnotationIsEnabled(runtime.HypervisorConfig.EnableAnnotations, key) {
+			return fmt.Errorf("annotation %v is not enabled", key)
+		}
 	}
 
 	err := addAssetAnnotations(ocispec, config)
@@ -529,6 +645,17 @@ func addHypervisorPathOverrides(ocispec specs.Spec, config *vc.SandboxConfig, ru
 		config.HypervisorConfig.HypervisorCtlPath = value
 	}
 
+	if value, ok := ocispec.Annotations[vcAnnotations.RootfsDisk]; ok {
+		if !checkPathIsInGlobs(runtime.HypervisorConfig.RootfsDiskList, value) {
+			return fmt.Errorf("rootfs disk %v required from annotation is not valid", value)
+		}
+		config.HypervisorConfig.RootfsDiskPath = value
+	}
+
+	if value, ok := ocispec.Annotations[vcAnnotations.RootfsDiskFormat]; ok {
+		config.HypervisorConfig.RootfsDiskFormat = value
+	}
+
 	if value, ok := ocispec.Annotations[vcAnnotations.KernelParams]; ok {
 		if value != "" {
 			params := vc.DeserializeParams(strings.Fields(value))
@@ -670,6 +797,12 @@ func addHypervisorBlockOverrides(ocispec specs.Spec, sbConfig *vc.SandboxConfig)
 		return err
 	}
 
+	if err := newAnnotationConfiguration(ocispec, vcAnnotations.LazyBlockDeviceAttach).setBool(func(lazyBlockDeviceAttach bool) {
+		sbConfig.HypervisorConfig.LazyBlockDeviceAttach = lazyBlockDeviceAttach
+	}); err != nil {
+		return err
+	}
+
 	if err := newAnnotationConfiguration(ocispec, vcAnnotations.EnableIOThreads).setBool(func(enableIOThreads bool) {
 		sbConfig.HypervisorConfig.EnableIOThreads = enableIOThreads
 	}); err != nil {
@@ -755,6 +888,12 @@ func addHypervisporNetworkOverrides(ocispec specs.Spec, sbConfig *vc.SandboxConf
 		}
 	}
 
+	if value, ok := ocispec.Annotations[vcAnnotations.CPUModel]; ok {
+		if value != "" {
+			sbConfig.HypervisorConfig.CPUModel = value
+		}
+	}
+
 	if err := newAnnotationConfiguration(ocispec, vcAnnotations.DisableVhostNet).setBool(func(disableVhostNet bool) {
 		sbConfig.HypervisorConfig.DisableVhostNet = disableVhostNet
 	}); err != nil {
@@ -786,6 +925,20 @@ func addRuntimeConfigOverrides(ocispec specs.Spec, sbConfig *vc.SandboxConfig, r
 		return err
 	}
 
+	if value, ok := ocispec.Annotations[vcAnnotations.CPUQuotaPolicy]; ok {
+		policy := vc.CPUQuotaPolicy(value)
+		if err := policy.Valid(); err != nil {
+			return fmt.Errorf("Invalid CPU quota policy in annotation %s: %v", vcAnnotations.CPUQuotaPolicy, err)
+		}
+		sbConfig.CPUQuotaPolicy = policy
+	}
+
+	if err := newAnnotationConfiguration(ocispec, vcAnnotations.SharePidNs).setBool(func(sharePidNs bool) {
+		sbConfig.SharePidNs = sharePidNs
+	}); err != nil {
+		return err
+	}
+
 	if value, ok := ocispec.Annotations[vcAnnotations.Experimental]; ok {
 		features := strings.Split(value, " ")
 		sbConfig.Experimental = []exp.Feature{}
@@ -814,9 +967,135 @@ func addRuntimeConfigOverrides(ocispec specs.Spec, sbConfig *vc.SandboxConfig, r
 		sbConfig.NetworkConfig.InterworkingModel = runtimeConfig.InterNetworkModel
 	}
 
+	if value, ok := ocispec.Annotations[vcAnnotations.StaticNetworkConfig]; ok {
+		var staticConfig vc.StaticNetworkConfig
+		if err := json.Unmarshal([]byte(value), &staticConfig); err != nil {
+			return fmt.Errorf("Invalid static network configuration in annotation %s: %v", vcAnnotations.StaticNetworkConfig, err)
+		}
+		if err := staticConfig.Validate(); err != nil {
+			return fmt.Errorf("Invalid static network configuration in annotation %s: %v", vcAnnotations.StaticNetworkConfig, err)
+		}
+		for _, iface := range staticConfig.Interfaces {
+			if iface.VhostUserSocket != "" && !checkPathIsInGlobs(runtime.HypervisorConfig.VhostUserSocketList, iface.VhostUserSocket) {
+				return fmt.Errorf("vhost-user socket %v of static network interface %s is not valid", iface.VhostUserSocket, iface.Name)
+			}
+		}
+
+		sbConfig.NetworkConfig.StaticConfig = &staticConfig
+	}
+
+	if err := newAnnotationConfiguration(ocispec, vcAnnotations.EphemeralDiskSizeMB).setUintWithCheck(func(sizeMB uint64) error {
+		if err := runtime.EphemeralDiskConfig.CheckSize(sizeMB); err != nil {
+			return fmt.Errorf("Invalid annotation %s: %v", vcAnnotations.EphemeralDiskSizeMB, err)
+		}
+		sbConfig.EphemeralDiskConfig.SizeMB = uint32(sizeMB)
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if err := newAnnotationConfiguration(ocispec, vcAnnotations.EphemeralDiskEncryption).setBool(func(encrypt bool) {
+		sbConfig.EphemeralDiskConfig.Encrypt = encrypt
+	}); err != nil {
+		return err
+	}
+
+	if err := newAnnotationConfiguration(ocispec, vcAnnotations.GuestSwapSizeMB).setUintWithCheck(func(sizeMB uint64) error {
+		if err := runtime.GuestSwapConfig.CheckSize(sizeMB); err != nil {
+			return fmt.Errorf("Invalid annotation %s: %v", vcAnnotations.GuestSwapSizeMB, err)
+		}
+		sbConfig.GuestSwapConfig.SizeMB = uint32(sizeMB)
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if err := newAnnotationConfiguration(ocispec, vcAnnotations.GuestSwappiness).setUint(func(swappiness uint64) {
+		sbConfig.GuestSwapConfig.Swappiness = uint32(swappiness)
+	}); err != nil {
+		return err
+	}
+
+	if value, ok := ocispec.Annotations[vcAnnotations.SharedMemoryRegions]; ok {
+		regions, err := parseSharedMemoryRegions(value)
+		if err != nil {
+			return fmt.Errorf("Invalid shared memory regions in annotation %s: %v", vcAnnotations.SharedMemoryRegions, err)
+		}
+		sbConfig.SharedMemoryConfig.Regions = regions
+	}
+
 	return nil
 }
 
+// parseSharedMemoryRegions parses a list of shared memory regions in the
+// format "accel=64Mi,ring=1Mi", their sizes being binary quantities.
+func parseSharedMemoryRegions(value string) ([]vc.SharedMemoryRegion, error) {
+	var regions []vc.SharedMemoryRegion
+
+	for _, r := range strings.Split(value, ",") {
+		r = strings.TrimSpace(r)
+		if r == "" {
+			continue
+		}
+
+		fields := strings.SplitN(r, "=", 2)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("invalid shared memory region %q, expected <name>=<size>", r)
+		}
+
+		quantity, err := resource.ParseQuantity(strings.TrimSpace(fields[1]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid size of shared memory region %q: %v", fields[0], err)
+		}
+		if quantity.Format != resource.BinarySI {
+			return nil, fmt.Errorf("unsupported size of shared memory region %q: use Ki | Mi | Gi as suffix", fields[0])
+		}
+		size, _ := quantity.AsInt64()
+		if size <= 0 {
+			return nil, fmt.Errorf("invalid size of shared memory region %q", fields[0])
+		}
+
+		regions = append(regions, vc.SharedMemoryRegion{
+			Name: strings.TrimSpace(fields[0]),
+			Size: uint64(size),
+		})
+	}
+
+	return regions, nil
+}
+
+// restrictAllowedAPIs returns the APIs of requested also part of allowed,
+// a nil allowed list allowing all the APIs. An empty request, e.g. an empty
+// annotation, leaves allowed unchanged. Otherwise the result is never nil,
+// as an empty allow-list only allows the APIs needed by the runtime.
+func restrictAllowedAPIs(allowed, requested []string) []string {
+	var apis []string
+	for _, api := range requested {
+		if api = strings.TrimSpace(api); api != "" {
+			apis = append(apis, api)
+		}
+	}
+
+	if apis == nil {
+		return allowed
+	}
+	if allowed == nil {
+		return apis
+	}
+
+	restricted := []string{}
+	for _, api := range apis {
+		for _, a := range allowed {
+			if api == a {
+				restricted = append(restricted, api)
+				break
+			}
+		}
+	}
+
+	return restricted
+}
+
 func addAgentConfigOverrides(ocispec specs.Spec, config *vc.SandboxConfig) error {
 	c := config.AgentConfig
 
@@ -840,12 +1119,42 @@ func addAgentConfigOverrides(ocispec specs.Spec, config *vc.SandboxConfig) error
 		c.TraceType = value
 	}
 
+	if value, ok := ocispec.Annotations[vcAnnotations.AgentMetricsGroups]; ok {
+		groups, err := vc.ParseMetricsGroups(strings.Split(value, ","), vc.AgentMetricsGroups)
+		if err != nil {
+			return fmt.Errorf("invalid annotation %s: %v", vcAnnotations.AgentMetricsGroups, err)
+		}
+		c.MetricsGroups = groups
+	}
+
+	if value, ok := ocispec.Annotations[vcAnnotations.AgentAllowedAPIs]; ok {
+		c.AllowedAPIs = restrictAllowedAPIs(c.AllowedAPIs, strings.Split(value, ","))
+	}
+
 	if err := newAnnotationConfiguration(ocispec, vcAnnotations.AgentContainerPipeSize).setUint(func(containerPipeSize uint64) {
 		c.ContainerPipeSize = uint32(containerPipeSize)
 	}); err != nil {
 		return err
 	}
 
+	if err := newAnnotationConfiguration(ocispec, vcAnnotations.AgentDialTimeout).setUint(func(timeout uint64) {
+		c.DialTimeout = uint32(timeout)
+	}); err != nil {
+		return err
+	}
+
+	if err := newAnnotationConfiguration(ocispec, vcAnnotations.AgentDialBackoffInitial).setUint(func(backoff uint64) {
+		c.DialBackoffInitial = uint32(backoff)
+	}); err != nil {
+		return err
+	}
+
+	if err := newAnnotationConfiguration(ocispec, vcAnnotations.AgentDialBackoffMax).setUint(func(backoff uint64) {
+		c.DialBackoffMax = uint32(backoff)
+	}); err != nil {
+		return err
+	}
+
 	config.AgentConfig = c
 
 	return nil
@@ -892,14 +1201,40 @@ func SandboxConfig(ocispec specs.Spec, runtime RuntimeConfig, bundlePath, cid, c
 		SystemdCgroup: systemdCgroup,
 
 		SandboxCgroupOnly: runtime.SandboxCgroupOnly,
+		CPUQuotaPolicy:    runtime.CPUQuotaPolicy,
 		SandboxBindMounts: runtime.SandboxBindMounts,
 
+		SharePidNs: runtime.SharePidNs,
+
+		ContainerParallelism: runtime.ContainerParallelism,
+
 		DisableGuestSeccomp: runtime.DisableGuestSeccomp,
 
+		GuestOCIHooks: runtime.GuestOCIHooks,
+
 		// Q: Is this really necessary? @weizhang555
 		// Spec: &ocispec,
 
 		Experimental: runtime.Experimental,
+
+		AuditConfig: runtime.AuditConfig,
+
+		EphemeralDiskConfig: runtime.EphemeralDiskConfig,
+
+		RootfsDedup:    runtime.RootfsDedup,
+		RootfsDedupDir: runtime.RootfsDedupDir,
+
+		DeviceReuseTimeout: runtime.DeviceReuseTimeout,
+
+		DevicePluginClasses: runtime.DevicePluginClasses,
+
+		InitDataConfig: runtime.InitDataConfig,
+
+		AdmissionConfig: runtime.AdmissionConfig,
+
+		GuestSwapConfig: runtime.GuestSwapConfig,
+
+		SharedMemoryConfig: runtime.SharedMemoryConfig,
 	}
 
 	if err := addAnnotations(ocispec, &sandboxConfig, runtime); err != nil {
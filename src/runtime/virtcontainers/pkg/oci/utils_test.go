@@ -336,6 +336,20 @@ func TestSandboxIDFailure(t *testing.T) {
 	assert.Empty(sandboxID)
 }
 
+func TestContainerName(t *testing.T) {
+	var ociSpec specs.Spec
+	assert := assert.New(t)
+
+	assert.Empty(ContainerName(ociSpec))
+
+	for _, key := range CRIContainerNameKeyList {
+		ociSpec.Annotations = map[string]string{
+			key: "sidecar",
+		}
+		assert.Equal("sidecar", ContainerName(ociSpec))
+	}
+}
+
 func TestAddKernelParamValid(t *testing.T) {
 	var config RuntimeConfig
 	assert := assert.New(t)
@@ -595,18 +609,60 @@ func TestAddAgentAnnotations(t *testing.T) {
 			"e1000e InterruptThrottleRate=3000,3000,3000 EEE=1",
 			"i915 enable_ppgtt=0",
 		},
-		ContainerPipeSize: 1024,
+		ContainerPipeSize:  1024,
+		MetricsGroups:      []string{"proc", "meminfo"},
+		AllowedAPIs:        []string{"grpc.GetMetricsRequest"},
+		DialTimeout:        60,
+		DialBackoffInitial: 50,
+		DialBackoffMax:     1000,
 	}
 
 	runtimeConfig := RuntimeConfig{
 		HypervisorType: vc.QemuHypervisor,
 		Console:        consolePath,
 	}
+	runtimeConfig.HypervisorConfig.EnableAnnotations = []string{"dial_timeout", "dial_backoff_.*"}
 
 	ocispec.Annotations[vcAnnotations.KernelModules] = strings.Join(expectedAgentConfig.KernelModules, KernelModulesSeparator)
 	ocispec.Annotations[vcAnnotations.AgentContainerPipeSize] = "1024"
+	ocispec.Annotations[vcAnnotations.AgentMetricsGroups] = "proc, meminfo"
+	ocispec.Annotations[vcAnnotations.AgentAllowedAPIs] = "grpc.GetMetricsRequest"
+	ocispec.Annotations[vcAnnotations.AgentDialTimeout] = "60"
+	ocispec.Annotations[vcAnnotations.AgentDialBackoffInitial] = "50"
+	ocispec.Annotations[vcAnnotations.AgentDialBackoffMax] = "1000"
 	addAnnotations(ocispec, &config, runtimeConfig)
 	assert.Exactly(expectedAgentConfig, config.AgentConfig)
+
+	ocispec.Annotations[vcAnnotations.AgentMetricsGroups] = "proc,memory"
+	assert.Error(addAnnotations(ocispec, &config, runtimeConfig))
+
+	// the dial timeout and backoff can delay the detection of a dead
+	// guest, they are restricted
+	ocispec.Annotations[vcAnnotations.AgentMetricsGroups] = "proc"
+	runtimeConfig.HypervisorConfig.EnableAnnotations = []string{"dial_timeout"}
+	assert.Error(addAnnotations(ocispec, &config, runtimeConfig))
+}
+
+func TestRestrictAllowedAPIs(t *testing.T) {
+	assert := assert.New(t)
+
+	requested := []string{"grpc.ExecProcessRequest", "grpc.GetMetricsRequest"}
+
+	// no allow-list in the configuration
+	assert.Equal(requested, restrictAllowedAPIs(nil, requested))
+
+	// the annotation can only narrow the allow-list
+	allowed := []string{"grpc.GetMetricsRequest", "grpc.StatsContainerRequest"}
+	assert.Equal([]string{"grpc.GetMetricsRequest"}, restrictAllowedAPIs(allowed, requested))
+
+	apis := restrictAllowedAPIs(allowed, []string{"grpc.ExecProcessRequest"})
+	assert.NotNil(apis)
+	assert.Empty(apis)
+
+	// an empty annotation does not restrict anything
+	assert.Nil(restrictAllowedAPIs(nil, []string{""}))
+	assert.Equal(allowed, restrictAllowedAPIs(allowed, []string{" "}))
+	assert.Equal([]string{"grpc.GetMetricsRequest"}, restrictAllowedAPIs(nil, []string{" grpc.GetMetricsRequest", ""}))
 }
 
 func TestContainerPipeSizeAnnotation(t *testing.T) {
@@ -685,6 +741,7 @@ func TestAddHypervisorAnnotations(t *testing.T) {
 	ocispec.Annotations[vcAnnotations.IOMMU] = "true"
 	ocispec.Annotations[vcAnnotations.BlockDeviceDriver] = "virtio-scsi"
 	ocispec.Annotations[vcAnnotations.DisableBlockDeviceUse] = "true"
+	ocispec.Annotations[vcAnnotations.LazyBlockDeviceAttach] = "true"
 	ocispec.Annotations[vcAnnotations.EnableIOThreads] = "true"
 	ocispec.Annotations[vcAnnotations.BlockDeviceCacheSet] = "true"
 	ocispec.Annotations[vcAnnotations.BlockDeviceCacheDirect] = "true"
@@ -697,6 +754,7 @@ func TestAddHypervisorAnnotations(t *testing.T) {
 	ocispec.Annotations[vcAnnotations.MachineType] = "q35"
 	ocispec.Annotations[vcAnnotations.MachineAccelerators] = "nofw"
 	ocispec.Annotations[vcAnnotations.CPUFeatures] = "pmu=off"
+	ocispec.Annotations[vcAnnotations.CPUModel] = "Skylake-Server"
 	ocispec.Annotations[vcAnnotations.DisableVhostNet] = "true"
 	ocispec.Annotations[vcAnnotations.GuestHookPath] = "/usr/bin/"
 	ocispec.Annotations[vcAnnotations.DisableImageNvdimm] = "true"
@@ -722,6 +780,7 @@ func TestAddHypervisorAnnotations(t *testing.T) {
 	assert.Equal(config.HypervisorConfig.IOMMU, true)
 	assert.Equal(config.HypervisorConfig.BlockDeviceDriver, "virtio-scsi")
 	assert.Equal(config.HypervisorConfig.DisableBlockDeviceUse, true)
+	assert.Equal(config.HypervisorConfig.LazyBlockDeviceAttach, true)
 	assert.Equal(config.HypervisorConfig.EnableIOThreads, true)
 	assert.Equal(config.HypervisorConfig.BlockDeviceCacheSet, true)
 	assert.Equal(config.HypervisorConfig.BlockDeviceCacheDirect, true)
@@ -734,6 +793,7 @@ func TestAddHypervisorAnnotations(t *testing.T) {
 	assert.Equal(config.HypervisorConfig.HypervisorMachineType, "q35")
 	assert.Equal(config.HypervisorConfig.MachineAccelerators, "nofw")
 	assert.Equal(config.HypervisorConfig.CPUFeatures, "pmu=off")
+	assert.Equal(config.HypervisorConfig.CPUModel, "Skylake-Server")
 	assert.Equal(config.HypervisorConfig.DisableVhostNet, true)
 	assert.Equal(config.HypervisorConfig.GuestHookPath, "/usr/bin/")
 	assert.Equal(config.HypervisorConfig.DisableImageNvdimm, true)
@@ -789,26 +849,31 @@ func TestAddProtectedHypervisorAnnotations(t *testing.T) {
 	ocispec.Annotations[vcAnnotations.FileBackedMemRootDir] = "/dev/shm"
 	ocispec.Annotations[vcAnnotations.VirtioFSDaemon] = "/bin/false"
 	ocispec.Annotations[vcAnnotations.EntropySource] = "/dev/urandom"
+	ocispec.Annotations[vcAnnotations.RootfsDisk] = "/dev/zero"
 
 	config.HypervisorConfig.FileBackedMemRootDir = "do-not-touch"
 	config.HypervisorConfig.VirtioFSDaemon = "dangerous-daemon"
 	config.HypervisorConfig.EntropySource = "truly-random"
+	config.HypervisorConfig.RootfsDiskPath = "trusted-disk"
 
 	err = addAnnotations(ocispec, &config, runtimeConfig)
 	assert.Error(err)
 	assert.Equal(config.HypervisorConfig.FileBackedMemRootDir, "do-not-touch")
 	assert.Equal(config.HypervisorConfig.VirtioFSDaemon, "dangerous-daemon")
 	assert.Equal(config.HypervisorConfig.EntropySource, "truly-random")
+	assert.Equal(config.HypervisorConfig.RootfsDiskPath, "trusted-disk")
 
 	// Now enable them and check again
 	runtimeConfig.HypervisorConfig.FileBackedMemRootList = []string{"/dev/*m"}
 	runtimeConfig.HypervisorConfig.VirtioFSDaemonList = []string{"/bin/*ls*"}
 	runtimeConfig.HypervisorConfig.EntropySourceList = []string{"/dev/*random*"}
+	runtimeConfig.HypervisorConfig.RootfsDiskList = []string{"/dev/zer*"}
 	err = addAnnotations(ocispec, &config, runtimeConfig)
 	assert.NoError(err)
 	assert.Equal(config.HypervisorConfig.FileBackedMemRootDir, "/dev/shm")
 	assert.Equal(config.HypervisorConfig.VirtioFSDaemon, "/bin/false")
 	assert.Equal(config.HypervisorConfig.EntropySource, "/dev/urandom")
+	assert.Equal(config.HypervisorConfig.RootfsDiskPath, "/dev/zero")
 
 	// In case an absurd large value is provided, the config value if not over-ridden
 	ocispec.Annotations[vcAnnotations.DefaultVCPUs] = "655536"
@@ -844,17 +909,199 @@ func TestAddRuntimeAnnotations(t *testing.T) {
 		HypervisorType: vc.QemuHypervisor,
 		Console:        consolePath,
 	}
+	runtimeConfig.HypervisorConfig.EnableAnnotations = []string{"cpu_quota_policy"}
 
 	ocispec.Annotations[vcAnnotations.DisableGuestSeccomp] = "true"
 	ocispec.Annotations[vcAnnotations.SandboxCgroupOnly] = "true"
+	ocispec.Annotations[vcAnnotations.SharePidNs] = "true"
 	ocispec.Annotations[vcAnnotations.DisableNewNetNs] = "true"
 	ocispec.Annotations[vcAnnotations.InterNetworkModel] = "macvtap"
+	ocispec.Annotations[vcAnnotations.CPUQuotaPolicy] = "guest"
 
 	addAnnotations(ocispec, &config, runtimeConfig)
 	assert.Equal(config.DisableGuestSeccomp, true)
 	assert.Equal(config.SandboxCgroupOnly, true)
+	assert.Equal(config.SharePidNs, true)
 	assert.Equal(config.NetworkConfig.DisableNewNetNs, true)
 	assert.Equal(config.NetworkConfig.InterworkingModel, vc.NetXConnectMacVtapModel)
+	assert.Nil(config.NetworkConfig.StaticConfig)
+	assert.Equal(config.CPUQuotaPolicy, vc.CPUQuotaGuest)
+
+	ocispec.Annotations[vcAnnotations.CPUQuotaPolicy] = "vcpu"
+	err := addAnnotations(ocispec, &config, runtimeConfig)
+	assert.Error(err)
+
+	// the policy can move the quota out of the host cgroups
+	ocispec.Annotations[vcAnnotations.CPUQuotaPolicy] = "guest"
+	runtimeConfig.HypervisorConfig.EnableAnnotations = nil
+	assert.Error(addAnnotations(ocispec, &config, runtimeConfig))
+}
+
+func TestAddEphemeralDiskSizeAnnotation(t *testing.T) {
+	assert := assert.New(t)
+
+	config := vc.SandboxConfig{
+		Annotations: make(map[string]string),
+	}
+
+	ocispec := specs.Spec{
+		Annotations: make(map[string]string),
+	}
+
+	runtimeConfig := RuntimeConfig{
+		HypervisorType: vc.QemuHypervisor,
+		Console:        consolePath,
+		EphemeralDiskConfig: vc.EphemeralDiskConfig{
+			Backend:   vc.EphemeralDiskFile,
+			Dir:       "/var/lib/kata-containers/ephemeral-disk",
+			SizeMB:    1024,
+			MaxSizeMB: 4096,
+		},
+	}
+
+	ocispec.Annotations[vcAnnotations.EphemeralDiskSizeMB] = "2048"
+	assert.NoError(addAnnotations(ocispec, &config, runtimeConfig))
+	assert.Equal(uint32(2048), config.EphemeralDiskConfig.SizeMB)
+
+	// the size is bounded by the maximum size
+	for _, size := range []string{"0", "8192", "4294968320"} {
+		ocispec.Annotations[vcAnnotations.EphemeralDiskSizeMB] = size
+		assert.Error(addAnnotations(ocispec, &config, runtimeConfig), size)
+	}
+}
+
+func TestAddGuestSwapSizeAnnotation(t *testing.T) {
+	assert := assert.New(t)
+
+	config := vc.SandboxConfig{
+		Annotations: make(map[string]string),
+	}
+
+	ocispec := specs.Spec{
+		Annotations: make(map[string]string),
+	}
+
+	runtimeConfig := RuntimeConfig{
+		HypervisorType: vc.QemuHypervisor,
+		Console:        consolePath,
+		GuestSwapConfig: vc.GuestSwapConfig{
+			SizeMB:    1024,
+			MaxSizeMB: 4096,
+		},
+	}
+
+	ocispec.Annotations[vcAnnotations.GuestSwapSizeMB] = "2048"
+
+	// the pods cannot ask for swap unless enabled
+	assert.Error(addAnnotations(ocispec, &config, runtimeConfig))
+
+	runtimeConfig.HypervisorConfig.EnableAnnotations = []string{"guest_swap_size_mb"}
+	assert.NoError(addAnnotations(ocispec, &config, runtimeConfig))
+	assert.Equal(uint32(2048), config.GuestSwapConfig.SizeMB)
+
+	// the size is bounded by the maximum size
+	for _, size := range []string{"8192", "4294968320"} {
+		ocispec.Annotations[vcAnnotations.GuestSwapSizeMB] = size
+		assert.Error(addAnnotations(ocispec, &config, runtimeConfig), size)
+	}
+
+	// the host disabled the swap
+	runtimeConfig.GuestSwapConfig = vc.GuestSwapConfig{}
+	ocispec.Annotations[vcAnnotations.GuestSwapSizeMB] = "1024"
+	assert.Error(addAnnotations(ocispec, &config, runtimeConfig))
+}
+
+func TestAddStaticNetworkConfigAnnotation(t *testing.T) {
+	assert := assert.New(t)
+
+	config := vc.SandboxConfig{
+		Annotations: make(map[string]string),
+	}
+
+	ocispec := specs.Spec{
+		Annotations: make(map[string]string),
+	}
+
+	runtimeConfig := RuntimeConfig{
+		HypervisorType: vc.QemuHypervisor,
+		Console:        consolePath,
+	}
+
+	ocispec.Annotations[vcAnnotations.StaticNetworkConfig] = `{
+		"interfaces": [{"name": "eth0", "hw_addr": "02:00:00:00:00:01", "ip_addresses": ["10.0.0.2/24"]}],
+		"routes": [{"gateway": "10.0.0.1", "device": "eth0"}]
+	}`
+
+	// the pods cannot set their addresses unless enabled
+	assert.Error(addAnnotations(ocispec, &config, runtimeConfig))
+	runtimeConfig.HypervisorConfig.EnableAnnotations = []string{"kernel_params"}
+	assert.Error(addAnnotations(ocispec, &config, runtimeConfig))
+
+	runtimeConfig.HypervisorConfig.EnableAnnotations = []string{"static_network_config"}
+	assert.NoError(addAnnotations(ocispec, &config, runtimeConfig))
+	assert.Equal(&vc.StaticNetworkConfig{
+		Interfaces: []vc.StaticNetworkInterface{
+			{Name: "eth0", HwAddr: "02:00:00:00:00:01", IPAddresses: []string{"10.0.0.2/24"}},
+		},
+		Routes: []vc.StaticNetworkRoute{
+			{Gateway: "10.0.0.1", Device: "eth0"},
+		},
+	}, config.NetworkConfig.StaticConfig)
+
+	ocispec.Annotations[vcAnnotations.StaticNetworkConfig] = `{"interfaces": []}`
+	assert.Error(addAnnotations(ocispec, &config, runtimeConfig))
+
+	// the vhost-user sockets must be valid
+	dir, err := ioutil.TempDir("", "vhost-user")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+	socket := filepath.Join(dir, "port0")
+	assert.NoError(ioutil.WriteFile(socket, nil, 0600))
+
+	ocispec.Annotations[vcAnnotations.StaticNetworkConfig] = `{
+		"interfaces": [{"name": "eth0", "hw_addr": "02:00:00:00:00:01", "ip_addresses": ["10.0.0.2/24"], "vhost_user_socket": "` + socket + `"}]
+	}`
+	assert.Error(addAnnotations(ocispec, &config, runtimeConfig))
+	runtimeConfig.HypervisorConfig.VhostUserSocketList = []string{dir + "/*"}
+	assert.NoError(addAnnotations(ocispec, &config, runtimeConfig))
+	assert.Equal(socket, config.NetworkConfig.StaticConfig.Interfaces[0].VhostUserSocket)
+
+	ocispec.Annotations[vcAnnotations.StaticNetworkConfig] = `foo`
+	assert.Error(addAnnotations(ocispec, &config, runtimeConfig))
+}
+
+func TestAddSharedMemoryRegionsAnnotation(t *testing.T) {
+	assert := assert.New(t)
+
+	config := vc.SandboxConfig{
+		Annotations: make(map[string]string),
+	}
+
+	ocispec := specs.Spec{
+		Annotations: make(map[string]string),
+	}
+
+	runtimeConfig := RuntimeConfig{
+		HypervisorType: vc.QemuHypervisor,
+		Console:        consolePath,
+	}
+
+	ocispec.Annotations[vcAnnotations.SharedMemoryRegions] = "accel=64Mi, ring=4Ki"
+
+	// the pods cannot share memory unless enabled
+	assert.Error(addAnnotations(ocispec, &config, runtimeConfig))
+
+	runtimeConfig.HypervisorConfig.EnableAnnotations = []string{"shared_memory_regions"}
+	assert.NoError(addAnnotations(ocispec, &config, runtimeConfig))
+	assert.Equal([]vc.SharedMemoryRegion{
+		{Name: "accel", Size: 64 << 20},
+		{Name: "ring", Size: 4 << 10},
+	}, config.SharedMemoryConfig.Regions)
+
+	for _, value := range []string{"accel", "accel=64M", "accel=foo", "accel=0Mi"} {
+		ocispec.Annotations[vcAnnotations.SharedMemoryRegions] = value
+		assert.Error(addAnnotations(ocispec, &config, runtimeConfig), value)
+	}
 }
 
 func TestRegexpContains(t *testing.T) {
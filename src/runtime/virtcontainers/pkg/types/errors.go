@@ -7,6 +7,7 @@ package types
 
 import (
 	"errors"
+	"fmt"
 )
 
 // common error objects used for argument checking
@@ -18,3 +19,14 @@ var (
 	ErrNoSuchContainer   = errors.New("Container does not exist")
 	ErrInvalidConfigType = errors.New("Invalid config type")
 )
+
+// InsufficientMemoryError is returned when the host lacks the free memory
+// to launch a sandbox VM.
+type InsufficientMemoryError struct {
+	RequiredMiB  uint64
+	AvailableMiB uint64
+}
+
+func (e *InsufficientMemoryError) Error() string {
+	return fmt.Sprintf("insufficient memory on the host: %d MiB required, %d MiB available", e.RequiredMiB, e.AvailableMiB)
+}
@@ -17,6 +17,7 @@ import (
 	pbTypes "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/agent/protocols"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/types"
 	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 // ID implements the VCSandbox function of the same name.
@@ -105,6 +106,9 @@ func (s *Sandbox) CreateContainer(ctx context.Context, conf vc.ContainerConfig)
 
 // DeleteContainer implements the VCSandbox function of the same name.
 func (s *Sandbox) DeleteContainer(ctx context.Context, contID string) (vc.VCContainer, error) {
+	if s.DeleteContainerFunc != nil {
+		return s.DeleteContainerFunc(contID)
+	}
 	return &Container{}, nil
 }
 
@@ -153,6 +157,9 @@ func (s *Sandbox) Status() vc.SandboxStatus {
 
 // EnterContainer implements the VCSandbox function of the same name.
 func (s *Sandbox) EnterContainer(ctx context.Context, containerID string, cmd types.Cmd) (vc.VCContainer, *vc.Process, error) {
+	if s.EnterContainerFunc != nil {
+		return s.EnterContainerFunc(containerID, cmd)
+	}
 	return &Container{}, &vc.Process{}, nil
 }
 
@@ -168,31 +175,49 @@ func (s *Sandbox) UpdateContainer(ctx context.Context, containerID string, resou
 
 // WaitProcess implements the VCSandbox function of the same name.
 func (s *Sandbox) WaitProcess(ctx context.Context, containerID, processID string) (int32, error) {
+	if s.WaitProcessFunc != nil {
+		return s.WaitProcessFunc(containerID, processID)
+	}
 	return 0, nil
 }
 
 // SignalProcess implements the VCSandbox function of the same name.
 func (s *Sandbox) SignalProcess(ctx context.Context, containerID, processID string, signal syscall.Signal, all bool) error {
+	if s.SignalProcessFunc != nil {
+		return s.SignalProcessFunc(containerID, processID, signal, all)
+	}
 	return nil
 }
 
 // WinsizeProcess implements the VCSandbox function of the same name.
 func (s *Sandbox) WinsizeProcess(ctx context.Context, containerID, processID string, height, width uint32) error {
+	if s.WinsizeProcessFunc != nil {
+		return s.WinsizeProcessFunc(containerID, processID, height, width)
+	}
 	return nil
 }
 
 // IOStream implements the VCSandbox function of the same name.
 func (s *Sandbox) IOStream(containerID, processID string) (io.WriteCloser, io.Reader, io.Reader, error) {
+	if s.IOStreamFunc != nil {
+		return s.IOStreamFunc(containerID, processID)
+	}
 	return nil, nil, nil, nil
 }
 
 // AddDevice adds a device to sandbox
 func (s *Sandbox) AddDevice(ctx context.Context, info config.DeviceInfo) (api.Device, error) {
+	if s.AddDeviceFunc != nil {
+		return s.AddDeviceFunc(info)
+	}
 	return nil, nil
 }
 
 // AddInterface implements the VCSandbox function of the same name.
 func (s *Sandbox) AddInterface(ctx context.Context, inf *pbTypes.Interface) (*pbTypes.Interface, error) {
+	if s.AddInterfaceFunc != nil {
+		return s.AddInterfaceFunc(inf)
+	}
 	return nil, nil
 }
 
@@ -220,6 +245,26 @@ func (s *Sandbox) GetOOMEvent(ctx context.Context) (string, error) {
 	return "", nil
 }
 
+// GetMemoryEvent implements the VCSandbox function of the same name.
+func (s *Sandbox) GetMemoryEvent(ctx context.Context) (vc.MemoryEvent, error) {
+	if s.GetMemoryEventFunc != nil {
+		return s.GetMemoryEventFunc()
+	}
+	return vc.MemoryEvent{}, nil
+}
+
+// RegisterMetrics implements the VCSandbox function of the same name.
+func (s *Sandbox) RegisterMetrics(r prometheus.Registerer) {
+}
+
+// RegisterProcessMetrics implements the VCSandbox function of the same name.
+func (s *Sandbox) RegisterProcessMetrics(r prometheus.Registerer) {
+}
+
+// RegisterStorageMetrics implements the VCSandbox function of the same name.
+func (s *Sandbox) RegisterStorageMetrics(r prometheus.Registerer) {
+}
+
 // UpdateRuntimeMetrics implements the VCSandbox function of the same name.
 func (s *Sandbox) UpdateRuntimeMetrics() error {
 	if s.UpdateRuntimeMetricsFunc != nil {
@@ -228,6 +273,14 @@ func (s *Sandbox) UpdateRuntimeMetrics() error {
 	return nil
 }
 
+// UpdateStorageMetrics implements the VCSandbox function of the same name.
+func (s *Sandbox) UpdateStorageMetrics() error {
+	if s.UpdateStorageMetricsFunc != nil {
+		return s.UpdateStorageMetricsFunc()
+	}
+	return nil
+}
+
 // GetAgentMetrics implements the VCSandbox function of the same name.
 func (s *Sandbox) GetAgentMetrics(ctx context.Context) (string, error) {
 	if s.GetAgentMetricsFunc != nil {
@@ -244,6 +297,31 @@ func (s *Sandbox) Stats(ctx context.Context) (vc.SandboxStats, error) {
 	return vc.SandboxStats{}, nil
 }
 
+// StatsSandbox implements the VCSandbox function of the same name. The
+// stats are built from Stats and StatsContainer unless StatsSandboxFunc
+// is set.
+func (s *Sandbox) StatsSandbox(ctx context.Context) (vc.PodStats, error) {
+	if s.StatsSandboxFunc != nil {
+		return s.StatsSandboxFunc()
+	}
+
+	vm, err := s.Stats(ctx)
+	if err != nil {
+		return vc.PodStats{}, err
+	}
+
+	containers := make(map[string]vc.ContainerStats)
+	for _, c := range s.GetAllContainers() {
+		stats, err := s.StatsContainer(ctx, c.ID())
+		if err != nil {
+			return vc.PodStats{}, err
+		}
+		containers[c.ID()] = stats
+	}
+
+	return vc.NewPodStats(vm, containers), nil
+}
+
 func (s *Sandbox) GetAgentURL() (string, error) {
 	if s.GetAgentURLFunc != nil {
 		return s.GetAgentURLFunc()
@@ -251,6 +329,134 @@ func (s *Sandbox) GetAgentURL() (string, error) {
 	return "", nil
 }
 
+// GetAgentAllowedAPIs implements the VCSandbox function of the same name.
+func (s *Sandbox) GetAgentAllowedAPIs() []string {
+	if s.GetAgentAllowedAPIsFunc != nil {
+		return s.GetAgentAllowedAPIsFunc()
+	}
+	return nil
+}
+
 func (s *Sandbox) GetHypervisorPid() (int, error) {
 	return 0, nil
 }
+
+// GetAuditLog implements the VCSandbox function of the same name.
+func (s *Sandbox) GetAuditLog() ([]vc.AuditRecord, error) {
+	if s.GetAuditLogFunc != nil {
+		return s.GetAuditLogFunc()
+	}
+	return nil, nil
+}
+
+// GetEphemeralDiskStatus implements the VCSandbox function of the same name.
+func (s *Sandbox) GetEphemeralDiskStatus() (vc.EphemeralDiskStatus, error) {
+	if s.GetEphemeralDiskStatusFunc != nil {
+		return s.GetEphemeralDiskStatusFunc()
+	}
+	return vc.EphemeralDiskStatus{}, nil
+}
+
+// GetGuestProtectionStatus implements the VCSandbox function of the same name.
+func (s *Sandbox) GetGuestProtectionStatus() (vc.GuestProtectionStatus, error) {
+	if s.GetGuestProtectionStatusFunc != nil {
+		return s.GetGuestProtectionStatusFunc()
+	}
+	return vc.GuestProtectionStatus{}, nil
+}
+
+// GetGuestStatus implements the VCSandbox function of the same name.
+func (s *Sandbox) GetGuestStatus(ctx context.Context) (vc.GuestStatus, error) {
+	if s.GetGuestStatusFunc != nil {
+		return s.GetGuestStatusFunc()
+	}
+	return vc.GuestStatus{}, nil
+}
+
+// DeviceLeaks implements the VCSandbox function of the same name.
+func (s *Sandbox) DeviceLeaks() []vc.DeviceLeak {
+	if s.DeviceLeaksFunc != nil {
+		return s.DeviceLeaksFunc()
+	}
+	return nil
+}
+
+// CleanupDeviceLeaks implements the VCSandbox function of the same name.
+func (s *Sandbox) CleanupDeviceLeaks(ctx context.Context) ([]vc.DeviceLeak, error) {
+	if s.CleanupDeviceLeaksFunc != nil {
+		return s.CleanupDeviceLeaksFunc()
+	}
+	return nil, nil
+}
+
+// SetNetworkPolicy implements the VCSandbox function of the same name.
+func (s *Sandbox) SetNetworkPolicy(ctx context.Context, ruleset string) error {
+	if s.SetNetworkPolicyFunc != nil {
+		return s.SetNetworkPolicyFunc(ruleset)
+	}
+	return nil
+}
+
+// GetNetworkPolicy implements the VCSandbox function of the same name.
+func (s *Sandbox) GetNetworkPolicy() string {
+	if s.GetNetworkPolicyFunc != nil {
+		return s.GetNetworkPolicyFunc()
+	}
+	return ""
+}
+
+// GetDryRunPlan implements the VCSandbox function of the same name.
+func (s *Sandbox) GetDryRunPlan() *vc.SandboxPlan {
+	if s.GetDryRunPlanFunc != nil {
+		return s.GetDryRunPlanFunc()
+	}
+	return nil
+}
+
+// GetHypervisorInvocation implements the VCSandbox function of the same name.
+func (s *Sandbox) GetHypervisorInvocation() (vc.HypervisorInvocation, error) {
+	if s.GetHypervisorInvocationFunc != nil {
+		return s.GetHypervisorInvocationFunc()
+	}
+	return vc.HypervisorInvocation{}, nil
+}
+
+// GetExitReport implements the VCSandbox function of the same name.
+func (s *Sandbox) GetExitReport() *vc.ExitReport {
+	if s.GetExitReportFunc != nil {
+		return s.GetExitReportFunc()
+	}
+	return nil
+}
+
+// SetAgentLogLevel implements the VCSandbox function of the same name.
+func (s *Sandbox) SetAgentLogLevel(ctx context.Context, level string) error {
+	if s.SetAgentLogLevelFunc != nil {
+		return s.SetAgentLogLevelFunc(level)
+	}
+	return nil
+}
+
+// GetAgentLogLevel implements the VCSandbox function of the same name.
+func (s *Sandbox) GetAgentLogLevel() string {
+	if s.GetAgentLogLevelFunc != nil {
+		return s.GetAgentLogLevelFunc()
+	}
+	return ""
+}
+
+// SetDebugConsole implements the VCSandbox function of the same name.
+func (s *Sandbox) SetDebugConsole(ctx context.Context, enable bool) error {
+	if s.SetDebugConsoleFunc != nil {
+		return s.SetDebugConsoleFunc(enable)
+	}
+	return nil
+}
+
+// DebugConsoleEnabled implements the VCSandbox function of the same name.
+func (s *Sandbox) DebugConsoleEnabled() bool {
+	if s.DebugConsoleEnabledFunc != nil {
+		return s.DebugConsoleEnabledFunc()
+	}
+	return false
+}
@@ -64,9 +64,29 @@ type Sandbox struct {
 	UpdateRoutesFunc         func(routes []*pbTypes.Route) ([]*pbTypes.Route, error)
 	ListRoutesFunc           func() ([]*pbTypes.Route, error)
 	UpdateRuntimeMetricsFunc func() error
+	UpdateStorageMetricsFunc func() error
 	GetAgentMetricsFunc      func() (string, error)
 	StatsFunc                func() (vc.SandboxStats, error)
+	StatsSandboxFunc         func() (vc.PodStats, error)
 	GetAgentURLFunc          func() (string, error)
+	GetAgentAllowedAPIsFunc  func() []string
+	GetAuditLogFunc          func() ([]vc.AuditRecord, error)
+
+	GetEphemeralDiskStatusFunc   func() (vc.EphemeralDiskStatus, error)
+	GetGuestProtectionStatusFunc func() (vc.GuestProtectionStatus, error)
+	GetMemoryEventFunc           func() (vc.MemoryEvent, error)
+	GetGuestStatusFunc           func() (vc.GuestStatus, error)
+	DeviceLeaksFunc              func() []vc.DeviceLeak
+	CleanupDeviceLeaksFunc       func() ([]vc.DeviceLeak, error)
+	SetNetworkPolicyFunc         func(ruleset string) error
+	GetNetworkPolicyFunc         func() string
+	SetAgentLogLevelFunc         func(level string) error
+	GetAgentLogLevelFunc         func() string
+	SetDebugConsoleFunc          func(enable bool) error
+	DebugConsoleEnabledFunc      func() bool
+	GetDryRunPlanFunc            func() *vc.SandboxPlan
+	GetHypervisorInvocationFunc  func() (vc.HypervisorInvocation, error)
+	GetExitReportFunc            func() *vc.ExitReport
 }
 
 // Container is a fake Container type used for testing
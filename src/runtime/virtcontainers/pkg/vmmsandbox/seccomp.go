@@ -0,0 +1,185 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package vmmsandbox
+
+import (
+	"fmt"
+	"unsafe"
+
+	"golang.org/x/net/bpf"
+	"golang.org/x/sys/unix"
+)
+
+// from <linux/seccomp.h>
+const (
+	seccompRetKillProcess = 0x80000000
+	seccompRetErrno       = 0x00050000
+	seccompRetAllow       = 0x7fff0000
+
+	// offsets of the nr, arch and args fields of struct seccomp_data
+	seccompDataNrOffset   = 0
+	seccompDataArchOffset = 4
+	seccompDataArgsOffset = 16
+)
+
+// argCondition is met when a syscall argument masked with mask is value.
+type argCondition struct {
+	arg   int
+	mask  uint64
+	value uint64
+}
+
+// syscallRule allows a syscall when its arguments meet all the conditions,
+// it fails with errno otherwise, EPERM if zero. With deny, it always fails.
+type syscallRule struct {
+	nr         uint32
+	conditions []argCondition
+	deny       bool
+	errno      unix.Errno
+}
+
+// instructions returns the instructions of the rule, run for its syscall.
+// The 64-bit arguments are checked a word at a time, the low word first on
+// the little endian architectures supported.
+func (r syscallRule) instructions() []bpf.Instruction {
+	errno := r.errno
+	if errno == 0 {
+		errno = unix.EPERM
+	}
+	fail := bpf.RetConstant{Val: seccompRetErrno | uint32(errno)}
+
+	if r.deny {
+		return []bpf.Instruction{fail}
+	}
+
+	var insns []bpf.Instruction
+	for _, c := range r.conditions {
+		for word := uint32(0); word < 2; word++ {
+			mask := uint32(c.mask >> (32 * word))
+			if mask == 0 {
+				continue
+			}
+			insns = append(insns, bpf.LoadAbsolute{Off: seccompDataArgsOffset + 8*uint32(c.arg) + 4*word, Size: 4})
+			if mask != 0xffffffff {
+				insns = append(insns, bpf.ALUOpConstant{Op: bpf.ALUOpAnd, Val: mask})
+			}
+			insns = append(insns,
+				bpf.JumpIf{Cond: bpf.JumpEqual, Val: uint32(c.value>>(32*word)) & mask, SkipTrue: 1},
+				fail,
+			)
+		}
+	}
+
+	return append(insns, bpf.RetConstant{Val: seccompRetAllow})
+}
+
+// buildFilter returns a seccomp filter applying the rules, the other
+// syscalls fail with EPERM and the other architectures are killed.
+func buildFilter(rules []syscallRule) ([]bpf.RawInstruction, error) {
+	insns := []bpf.Instruction{
+		bpf.LoadAbsolute{Off: seccompDataArchOffset, Size: 4},
+		bpf.JumpIf{Cond: bpf.JumpEqual, Val: auditArch, SkipTrue: 1},
+		bpf.RetConstant{Val: seccompRetKillProcess},
+		bpf.LoadAbsolute{Off: seccompDataNrOffset, Size: 4},
+	}
+
+	// the instructions of a rule all return, the syscall number is
+	// still loaded when its rule is skipped
+	for _, r := range rules {
+		body := r.instructions()
+		insns = append(insns, bpf.JumpIf{Cond: bpf.JumpNotEqual, Val: r.nr, SkipTrue: uint8(len(body))})
+		insns = append(insns, body...)
+	}
+
+	insns = append(insns, bpf.RetConstant{Val: seccompRetErrno | uint32(unix.EPERM)})
+
+	return bpf.Assemble(insns)
+}
+
+func (p Profile) apply(filter []bpf.RawInstruction) error {
+	var flags int
+	for _, ns := range p.Namespaces {
+		flags |= namespaceFlags[ns]
+	}
+
+	if flags != 0 {
+		if err := unix.Unshare(flags); err != nil {
+			return fmt.Errorf("unshare namespaces %v: %v", p.Namespaces, err)
+		}
+	}
+
+	if flags&unix.CLONE_NEWNS != 0 {
+		// keep receiving the host mounts (e.g. the hotplugged
+		// devices), without propagating ours back to the host
+		if err := unix.Mount("", "/", "", unix.MS_REC|unix.MS_SLAVE, ""); err != nil {
+			return fmt.Errorf("make / a slave mount: %v", err)
+		}
+	}
+
+	if p.HugePagesDir != "" {
+		data := fmt.Sprintf("uid=%d,gid=%d,mode=0700", p.Credential.UID, p.Credential.GID)
+		if err := unix.Mount("hugetlbfs", p.HugePagesDir, "hugetlbfs", unix.MS_NOSUID|unix.MS_NODEV|unix.MS_NOEXEC, data); err != nil {
+			return fmt.Errorf("mount hugetlbfs on %s: %v", p.HugePagesDir, err)
+		}
+	}
+
+	caps, err := p.capabilityNumbers()
+	if err != nil {
+		return err
+	}
+
+	// the privileges are dropped after the namespaces and the mounts,
+	// which need them
+	if err := dropBoundingCapabilities(caps); err != nil {
+		return err
+	}
+
+	if c := p.Credential; c != nil {
+		if len(caps) > 0 {
+			if err := unix.Prctl(unix.PR_SET_KEEPCAPS, 1, 0, 0, 0); err != nil {
+				return fmt.Errorf("keep capabilities: %v", err)
+			}
+		}
+
+		groups := make([]int, len(c.Groups))
+		for i, g := range c.Groups {
+			groups[i] = int(g)
+		}
+		if err := unix.Setgroups(groups); err != nil {
+			return fmt.Errorf("set groups %v: %v", c.Groups, err)
+		}
+		if err := unix.Setresgid(int(c.GID), int(c.GID), int(c.GID)); err != nil {
+			return fmt.Errorf("set gid %d: %v", c.GID, err)
+		}
+		if err := unix.Setresuid(int(c.UID), int(c.UID), int(c.UID)); err != nil {
+			return fmt.Errorf("set uid %d: %v", c.UID, err)
+		}
+	}
+
+	if err := setCapabilities(caps); err != nil {
+		return err
+	}
+
+	if p.NoNewPrivileges {
+		if err := unix.Prctl(unix.PR_SET_NO_NEW_PRIVS, 1, 0, 0, 0); err != nil {
+			return fmt.Errorf("set no_new_privs: %v", err)
+		}
+	}
+
+	if len(filter) == 0 {
+		return nil
+	}
+
+	prog := unix.SockFprog{
+		Len:    uint16(len(filter)),
+		Filter: (*unix.SockFilter)(unsafe.Pointer(&filter[0])),
+	}
+	if err := unix.Prctl(unix.PR_SET_SECCOMP, unix.SECCOMP_MODE_FILTER, uintptr(unsafe.Pointer(&prog)), 0, 0); err != nil {
+		return fmt.Errorf("install seccomp filter: %v", err)
+	}
+
+	return nil
+}
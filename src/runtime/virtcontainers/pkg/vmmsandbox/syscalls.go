@@ -0,0 +1,226 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package vmmsandbox
+
+import "golang.org/x/sys/unix"
+
+// defaultSyscalls is the base allow-list of the hypervisor processes,
+// the syscalls that are not available on an architecture are ignored.
+// The privileges are dropped before the filter is installed, and io_uring
+// and userfaultfd are left out for their attack surface, the hypervisors
+// using them need them added to the profile. fork, vfork and tkill are left
+// out, clone, execve and the signals are restricted by conditionalSyscalls.
+var defaultSyscalls = []string{
+	"accept",
+	"accept4",
+	"access",
+	"arch_prctl",
+	"bind",
+	"brk",
+	"capget",
+	"chdir",
+	"clock_getres",
+	"clock_gettime",
+	"clock_nanosleep",
+	"close",
+	"connect",
+	"copy_file_range",
+	"dup",
+	"dup2",
+	"dup3",
+	"epoll_create",
+	"epoll_create1",
+	"epoll_ctl",
+	"epoll_pwait",
+	"epoll_wait",
+	"eventfd",
+	"eventfd2",
+	"exit",
+	"exit_group",
+	"faccessat",
+	"faccessat2",
+	"fadvise64",
+	"fallocate",
+	"fchdir",
+	"fchmod",
+	"fchmodat",
+	"fchown",
+	"fchownat",
+	"fcntl",
+	"fdatasync",
+	"fgetxattr",
+	"flock",
+	"fstat",
+	"fstatfs",
+	"fsync",
+	"ftruncate",
+	"futex",
+	"getcpu",
+	"getcwd",
+	"getdents",
+	"getdents64",
+	"getegid",
+	"geteuid",
+	"getgid",
+	"getgroups",
+	"getitimer",
+	"getpeername",
+	"getpgrp",
+	"getpid",
+	"getppid",
+	"getpriority",
+	"getrandom",
+	"getresgid",
+	"getresuid",
+	"getrlimit",
+	"getrusage",
+	"getsockname",
+	"getsockopt",
+	"gettid",
+	"gettimeofday",
+	"getuid",
+	"getxattr",
+	"inotify_add_watch",
+	"inotify_init1",
+	"inotify_rm_watch",
+	"io_cancel",
+	"io_destroy",
+	"io_getevents",
+	"io_setup",
+	"io_submit",
+	"ioctl",
+	"lgetxattr",
+	"listen",
+	"lseek",
+	"lstat",
+	"madvise",
+	"mbind",
+	"membarrier",
+	"memfd_create",
+	"mincore",
+	"mkdir",
+	"mkdirat",
+	"mlock",
+	"mlock2",
+	"mlockall",
+	"mmap",
+	"mprotect",
+	"mremap",
+	"msync",
+	"munlock",
+	"munlockall",
+	"munmap",
+	"nanosleep",
+	"newfstatat",
+	"open",
+	"openat",
+	"pipe",
+	"pipe2",
+	"poll",
+	"ppoll",
+	"prctl",
+	"pread64",
+	"preadv",
+	"preadv2",
+	"prlimit64",
+	"pselect6",
+	"pwrite64",
+	"pwritev",
+	"pwritev2",
+	"read",
+	"readahead",
+	"readlink",
+	"readlinkat",
+	"readv",
+	"recvfrom",
+	"recvmmsg",
+	"recvmsg",
+	"rename",
+	"renameat",
+	"renameat2",
+	"restart_syscall",
+	"rseq",
+	"rt_sigaction",
+	"rt_sigpending",
+	"rt_sigprocmask",
+	"rt_sigreturn",
+	"rt_sigsuspend",
+	"rt_sigtimedwait",
+	"sched_get_priority_max",
+	"sched_get_priority_min",
+	"sched_getaffinity",
+	"sched_getattr",
+	"sched_getparam",
+	"sched_getscheduler",
+	"sched_setaffinity",
+	"sched_setattr",
+	"sched_setscheduler",
+	"sched_yield",
+	"seccomp",
+	"select",
+	"sendfile",
+	"sendmmsg",
+	"sendmsg",
+	"sendto",
+	"set_robust_list",
+	"set_tid_address",
+	"setitimer",
+	"setpgid",
+	"setpriority",
+	"setsid",
+	"setsockopt",
+	"shmat",
+	"shmctl",
+	"shmdt",
+	"shmget",
+	"shutdown",
+	"sigaltstack",
+	"signalfd",
+	"signalfd4",
+	"socket",
+	"socketpair",
+	"stat",
+	"statfs",
+	"statx",
+	"sync_file_range",
+	"sysinfo",
+	"time",
+	"timer_create",
+	"timer_delete",
+	"timer_getoverrun",
+	"timer_gettime",
+	"timer_settime",
+	"timerfd_create",
+	"timerfd_gettime",
+	"timerfd_settime",
+	"truncate",
+	"umask",
+	"uname",
+	"unlink",
+	"unlinkat",
+	"utimensat",
+	"wait4",
+	"waitid",
+	"write",
+	"writev",
+}
+
+// conditionalSyscalls are only allowed with some arguments: clone without
+// creating namespaces, clone3 fails with ENOSYS, execve of the hypervisor
+// by the launcher and the signals sent to the hypervisor itself.
+var conditionalSyscalls = []string{
+	"clone",
+	"clone3",
+	"execve",
+	"kill",
+	"rt_sigqueueinfo",
+	"rt_tgsigqueueinfo",
+	"tgkill",
+}
+
+// namespaceCloneFlags are the clone flags creating namespaces.
+const namespaceCloneFlags = unix.CLONE_NEWCGROUP | unix.CLONE_NEWIPC | unix.CLONE_NEWNET |
+	unix.CLONE_NEWNS | unix.CLONE_NEWPID | unix.CLONE_NEWTIME | unix.CLONE_NEWUSER | unix.CLONE_NEWUTS
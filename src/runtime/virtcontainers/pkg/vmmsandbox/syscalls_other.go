@@ -0,0 +1,13 @@
+// +build linux,!amd64,!arm64
+
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package vmmsandbox
+
+// the seccomp filter is not supported on this architecture
+const auditArch = 0
+
+var syscallTable = map[string]uint32{}
@@ -0,0 +1,366 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+// Package vmmsandbox launches the hypervisor processes in a sandboxing
+// profile: new namespaces, no new privileges, a seccomp allow-list,
+// non-root user and group IDs and only the capabilities it needs.
+//
+// The runtime binaries act as the launcher when they are executed through
+// a symlink named LauncherName: the profile stored next to the symlink is
+// applied to the process, which then executes the hypervisor binary.
+package vmmsandbox
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/net/bpf"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// LauncherName is the name the runtime binaries are executed
+	// with to act as the hypervisor launcher.
+	LauncherName = "kata-vmm-launcher"
+
+	profileFile = "vmm-sandbox.json"
+)
+
+// defaultNamespaces are the namespaces the hypervisor does not share with
+// the host. The network namespace is kept since the hypervisor uses the
+// sandbox network interfaces.
+var defaultNamespaces = []string{"ipc", "mount", "uts"}
+
+var namespaceFlags = map[string]int{
+	"ipc":   unix.CLONE_NEWIPC,
+	"mount": unix.CLONE_NEWNS,
+	"uts":   unix.CLONE_NEWUTS,
+}
+
+// Profile describes the sandbox of a hypervisor process.
+type Profile struct {
+	// Path is the hypervisor binary executed by the launcher, the
+	// launcher only applies the profile and exits when it is empty.
+	Path string `json:"path"`
+
+	// Namespaces are unshared before executing the hypervisor.
+	Namespaces []string `json:"namespaces"`
+
+	// NoNewPrivileges sets the no_new_privs bit of the hypervisor.
+	NoNewPrivileges bool `json:"no_new_privileges"`
+
+	// Seccomp installs the seccomp allow-list.
+	Seccomp bool `json:"seccomp"`
+
+	// Syscalls are allowed on top of the default allow-list.
+	Syscalls []string `json:"syscalls,omitempty"`
+
+	// Capabilities are kept by the hypervisor, e.g. CAP_IPC_LOCK, the
+	// other ones are dropped, including from the bounding set.
+	Capabilities []string `json:"capabilities,omitempty"`
+
+	// Credential is the user and groups the hypervisor is run with, it is
+	// run as root when nil.
+	Credential *Credential `json:"credential,omitempty"`
+
+	// HugePagesDir is mounted with a hugetlbfs instance owned by the
+	// credential, e.g. /dev/hugepages, in the mount namespace of the
+	// hypervisor, so that it can create its memory files.
+	HugePagesDir string `json:"hugepages_dir,omitempty"`
+}
+
+// Credential is the user and groups of a hypervisor.
+type Credential struct {
+	UID uint32 `json:"uid"`
+	GID uint32 `json:"gid"`
+
+	// Groups are the supplementary groups, e.g. the group of /dev/kvm.
+	Groups []uint32 `json:"groups,omitempty"`
+}
+
+// NewProfile returns the default profile of the hypervisor binary path,
+// allowing the extra syscalls and keeping the capabilities.
+func NewProfile(path string, syscalls, capabilities []string) Profile {
+	return Profile{
+		Path:            path,
+		Namespaces:      defaultNamespaces,
+		NoNewPrivileges: true,
+		Seccomp:         true,
+		Syscalls:        syscalls,
+		Capabilities:    capabilities,
+	}
+}
+
+// HasNamespace returns true if the profile unshares the namespace ns.
+func (p Profile) HasNamespace(ns string) bool {
+	for _, n := range p.Namespaces {
+		if n == ns {
+			return true
+		}
+	}
+
+	return false
+}
+
+// syscallNumbers returns the sorted numbers of the syscalls allowed by the
+// profile. The default syscalls missing on this architecture are skipped,
+// the extra ones must exist.
+func (p Profile) syscallNumbers() ([]uint32, error) {
+	if auditArch == 0 {
+		return nil, fmt.Errorf("seccomp filter not supported on %s", runtime.GOARCH)
+	}
+
+	allowed := make(map[uint32]bool)
+	for _, name := range defaultSyscalls {
+		if nr, ok := syscallTable[name]; ok {
+			allowed[nr] = true
+		}
+	}
+
+	for _, name := range p.Syscalls {
+		nr, ok := syscallTable[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown syscall %q", name)
+		}
+		allowed[nr] = true
+	}
+
+	numbers := make([]uint32, 0, len(allowed))
+	for nr := range allowed {
+		numbers = append(numbers, nr)
+	}
+	sort.Slice(numbers, func(i, j int) bool { return numbers[i] < numbers[j] })
+
+	return numbers, nil
+}
+
+// syscallRules returns the seccomp rules of the profile: the allowed
+// syscalls, then the ones only allowed with some arguments, unless the
+// profile allows them explicitly. The launcher of process pid executes
+// the hypervisor with the path at address execPath, which is the only
+// execve allowed, none when zero.
+func (p Profile) syscallRules(pid int, execPath uintptr) ([]syscallRule, error) {
+	numbers, err := p.syscallNumbers()
+	if err != nil {
+		return nil, err
+	}
+
+	allowed := make(map[uint32]bool, len(numbers))
+	rules := make([]syscallRule, 0, len(numbers)+len(conditionalSyscalls))
+	for _, nr := range numbers {
+		allowed[nr] = true
+		rules = append(rules, syscallRule{nr: nr})
+	}
+
+	for _, name := range conditionalSyscalls {
+		nr, ok := syscallTable[name]
+		if !ok || allowed[nr] {
+			continue
+		}
+
+		r := syscallRule{nr: nr}
+		switch name {
+		case "clone":
+			r.conditions = []argCondition{{arg: 0, mask: namespaceCloneFlags, value: 0}}
+		case "clone3":
+			// its flags are in a structure the filter can't read,
+			// the C libraries fall back to clone on ENOSYS
+			r.deny = true
+			r.errno = unix.ENOSYS
+		case "execve":
+			// a compromised hypervisor could still map a path at
+			// this address, with no new privileges and the rest
+			// of the profile applied to what it executes
+			if execPath == 0 {
+				continue
+			}
+			r.conditions = []argCondition{{arg: 0, mask: ^uint64(0), value: uint64(execPath)}}
+		default:
+			// the signals are only sent to the hypervisor itself,
+			// which keeps the pid of the launcher
+			r.conditions = []argCondition{{arg: 0, mask: 0xffffffff, value: uint64(pid)}}
+		}
+		rules = append(rules, r)
+	}
+
+	return rules, nil
+}
+
+func (p Profile) validate() error {
+	for _, ns := range p.Namespaces {
+		if _, ok := namespaceFlags[ns]; !ok {
+			return fmt.Errorf("unknown namespace %q", ns)
+		}
+	}
+
+	if p.HugePagesDir != "" {
+		if p.Credential == nil {
+			return fmt.Errorf("hugepages directory without credential")
+		}
+		if !p.HasNamespace("mount") {
+			return fmt.Errorf("hugepages directory without mount namespace")
+		}
+	}
+
+	if _, err := p.capabilityNumbers(); err != nil {
+		return err
+	}
+
+	if !p.Seccomp {
+		return nil
+	}
+
+	// CAP_SYS_ADMIN is dropped before the filter is installed
+	if !p.NoNewPrivileges {
+		return fmt.Errorf("seccomp filter without no_new_privileges")
+	}
+
+	_, err := p.syscallNumbers()
+	return err
+}
+
+// Prepare stores the profile in dir and returns the path of the launcher
+// to execute in place of the hypervisor binary.
+func Prepare(dir string, p Profile) (string, error) {
+	if err := p.validate(); err != nil {
+		return "", fmt.Errorf("invalid hypervisor sandboxing profile: %v", err)
+	}
+
+	data, err := json.Marshal(p)
+	if err != nil {
+		return "", err
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(dir, profileFile), data, 0600); err != nil {
+		return "", err
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+
+	launcher := filepath.Join(dir, LauncherName)
+	if err := os.Remove(launcher); err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+	if err := os.Symlink(exe, launcher); err != nil {
+		return "", err
+	}
+
+	return launcher, nil
+}
+
+// Check verifies that the profile can be applied on this host, by running
+// the launcher without hypervisor.
+func Check(p Profile) error {
+	dir, err := ioutil.TempDir("", "kata-vmm-sandbox")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	p.Path = ""
+	launcher, err := Prepare(dir, p)
+	if err != nil {
+		return err
+	}
+
+	if out, err := exec.Command(launcher).CombinedOutput(); err != nil {
+		return fmt.Errorf("%v: %s", err, strings.TrimSpace(string(out)))
+	}
+
+	return nil
+}
+
+// Init must be called first thing in main. When the binary is executed as
+// the launcher, it applies the profile and executes the hypervisor, it
+// never returns.
+func Init() {
+	if filepath.Base(os.Args[0]) != LauncherName {
+		return
+	}
+
+	if err := launch(); err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", LauncherName, err)
+		os.Exit(1)
+	}
+
+	os.Exit(0)
+}
+
+func launch() error {
+	// the namespaces, no_new_privs and the seccomp filter
+	// apply to the current thread, which executes the hypervisor
+	runtime.LockOSThread()
+
+	data, err := ioutil.ReadFile(filepath.Join(filepath.Dir(os.Args[0]), profileFile))
+	if err != nil {
+		return err
+	}
+
+	var p Profile
+	if err := json.Unmarshal(data, &p); err != nil {
+		return err
+	}
+
+	if err := p.validate(); err != nil {
+		return err
+	}
+
+	// the seccomp filter only allows to execute this path
+	var path *byte
+	if p.Path != "" {
+		if path, err = unix.BytePtrFromString(p.Path); err != nil {
+			return err
+		}
+	}
+
+	var filter []bpf.RawInstruction
+	if p.Seccomp {
+		rules, err := p.syscallRules(os.Getpid(), uintptr(unsafe.Pointer(path)))
+		if err != nil {
+			return err
+		}
+
+		if filter, err = buildFilter(rules); err != nil {
+			return err
+		}
+	}
+
+	if err := p.apply(filter); err != nil {
+		return err
+	}
+
+	if path == nil {
+		return nil
+	}
+
+	argv, err := syscall.SlicePtrFromStrings(append([]string{p.Path}, os.Args[1:]...))
+	if err != nil {
+		return err
+	}
+	envv, err := syscall.SlicePtrFromStrings(os.Environ())
+	if err != nil {
+		return err
+	}
+
+	_, _, errno := unix.RawSyscall(unix.SYS_EXECVE,
+		uintptr(unsafe.Pointer(path)),
+		uintptr(unsafe.Pointer(&argv[0])),
+		uintptr(unsafe.Pointer(&envv[0])))
+	runtime.KeepAlive(path)
+
+	return fmt.Errorf("execute %s: %v", p.Path, errno)
+}
@@ -0,0 +1,225 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package vmmsandbox
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/bpf"
+	"golang.org/x/sys/unix"
+)
+
+func TestProfileSyscallNumbers(t *testing.T) {
+	if auditArch == 0 {
+		t.Skip("seccomp filter not supported")
+	}
+	assert := assert.New(t)
+
+	p := NewProfile("/usr/bin/qemu", nil, nil)
+	numbers, err := p.syscallNumbers()
+	assert.NoError(err)
+	assert.NotContains(numbers, uint32(unix.SYS_EXECVE))
+	assert.NotContains(numbers, uint32(unix.SYS_CLONE))
+	assert.NotContains(numbers, uint32(unix.SYS_KILL))
+	assert.NotContains(numbers, uint32(unix.SYS_PTRACE))
+	assert.NotContains(numbers, uint32(unix.SYS_SETUID))
+	assert.NotContains(numbers, uint32(unix.SYS_USERFAULTFD))
+	assert.NotContains(numbers, uint32(unix.SYS_IO_URING_SETUP))
+
+	p.Syscalls = []string{"ptrace"}
+	numbers, err = p.syscallNumbers()
+	assert.NoError(err)
+	assert.Contains(numbers, uint32(unix.SYS_PTRACE))
+
+	p.Syscalls = []string{"not_a_syscall"}
+	_, err = p.syscallNumbers()
+	assert.Error(err)
+
+	p = NewProfile("/usr/bin/qemu", nil, nil)
+	p.Namespaces = []string{"net"}
+	assert.Error(p.validate())
+}
+
+func TestProfileSyscallRules(t *testing.T) {
+	if auditArch == 0 {
+		t.Skip("seccomp filter not supported")
+	}
+	assert := assert.New(t)
+
+	rule := func(rules []syscallRule, nr uint32) *syscallRule {
+		for i := range rules {
+			if rules[i].nr == nr {
+				return &rules[i]
+			}
+		}
+		return nil
+	}
+
+	p := NewProfile("/usr/bin/qemu", nil, nil)
+	rules, err := p.syscallRules(42, 0x1000)
+	assert.NoError(err)
+
+	r := rule(rules, unix.SYS_READ)
+	if assert.NotNil(r) {
+		assert.Empty(r.conditions)
+	}
+	r = rule(rules, unix.SYS_CLONE)
+	if assert.NotNil(r) {
+		assert.Equal([]argCondition{{arg: 0, mask: namespaceCloneFlags}}, r.conditions)
+	}
+	r = rule(rules, unix.SYS_CLONE3)
+	if assert.NotNil(r) {
+		assert.True(r.deny)
+		assert.Equal(unix.ENOSYS, r.errno)
+	}
+	r = rule(rules, unix.SYS_EXECVE)
+	if assert.NotNil(r) {
+		assert.Equal([]argCondition{{arg: 0, mask: ^uint64(0), value: 0x1000}}, r.conditions)
+	}
+	r = rule(rules, unix.SYS_KILL)
+	if assert.NotNil(r) {
+		assert.Equal([]argCondition{{arg: 0, mask: 0xffffffff, value: 42}}, r.conditions)
+	}
+
+	// no execve without hypervisor path
+	rules, err = p.syscallRules(42, 0)
+	assert.NoError(err)
+	assert.Nil(rule(rules, unix.SYS_EXECVE))
+
+	// the syscalls allowed by the profile are not restricted
+	p.Syscalls = []string{"clone"}
+	rules, err = p.syscallRules(42, 0x1000)
+	assert.NoError(err)
+	r = rule(rules, unix.SYS_CLONE)
+	if assert.NotNil(r) {
+		assert.Empty(r.conditions)
+	}
+}
+
+func TestProfileValidate(t *testing.T) {
+	assert := assert.New(t)
+
+	// no seccomp filter to check
+	p := Profile{Path: "/usr/bin/qemu", Syscalls: []string{"not_a_syscall"}}
+	assert.NoError(p.validate())
+
+	p = Profile{Path: "/usr/bin/qemu", HugePagesDir: "/dev/hugepages"}
+	assert.Error(p.validate())
+
+	p.Credential = &Credential{UID: 1000, GID: 1000}
+	assert.Error(p.validate())
+
+	p.Namespaces = []string{"mount"}
+	assert.True(p.HasNamespace("mount"))
+	assert.False(p.HasNamespace("ipc"))
+	assert.NoError(p.validate())
+
+	p.Capabilities = []string{"CAP_IPC_LOCK"}
+	assert.NoError(p.validate())
+
+	p.Capabilities = []string{"CAP_NOT_A_CAPABILITY"}
+	assert.Error(p.validate())
+
+	// the seccomp filter is installed without CAP_SYS_ADMIN
+	p = NewProfile("/usr/bin/qemu", nil, nil)
+	p.NoNewPrivileges = false
+	assert.Error(p.validate())
+}
+
+func TestBuildFilter(t *testing.T) {
+	if auditArch == 0 {
+		t.Skip("seccomp filter not supported")
+	}
+	assert := assert.New(t)
+
+	filter, err := buildFilter([]syscallRule{
+		{nr: unix.SYS_READ},
+		{nr: unix.SYS_WRITE},
+		{nr: unix.SYS_CLONE, conditions: []argCondition{{arg: 0, mask: namespaceCloneFlags}}},
+		{nr: unix.SYS_EXECVE, conditions: []argCondition{{arg: 0, mask: ^uint64(0), value: 0x7f0000001000}}},
+		{nr: unix.SYS_CLONE3, deny: true, errno: unix.ENOSYS},
+	})
+	assert.NoError(err)
+
+	insns, ok := bpf.Disassemble(filter)
+	assert.True(ok)
+	vm, err := bpf.NewVM(insns)
+	assert.NoError(err)
+
+	// the bpf VM loads big endian words, the low word of the arguments
+	// comes first
+	run := func(arch, nr uint32, args ...uint64) int {
+		data := make([]byte, 64)
+		binary.BigEndian.PutUint32(data[seccompDataNrOffset:], nr)
+		binary.BigEndian.PutUint32(data[seccompDataArchOffset:], arch)
+		for i, arg := range args {
+			off := seccompDataArgsOffset + 8*i
+			binary.BigEndian.PutUint32(data[off:], uint32(arg))
+			binary.BigEndian.PutUint32(data[off+4:], uint32(arg>>32))
+		}
+		ret, err := vm.Run(data)
+		assert.NoError(err)
+		return ret
+	}
+
+	assert.Equal(seccompRetAllow, run(auditArch, unix.SYS_READ))
+	assert.Equal(seccompRetAllow, run(auditArch, unix.SYS_WRITE))
+	assert.Equal(seccompRetErrno|int(unix.EPERM), run(auditArch, unix.SYS_PTRACE))
+	assert.Equal(seccompRetKillProcess, run(auditArch+1, unix.SYS_READ))
+
+	assert.Equal(seccompRetAllow, run(auditArch, unix.SYS_CLONE, unix.CLONE_VM|unix.CLONE_THREAD))
+	assert.Equal(seccompRetErrno|int(unix.EPERM), run(auditArch, unix.SYS_CLONE, unix.CLONE_NEWUSER))
+	assert.Equal(seccompRetErrno|int(unix.EPERM), run(auditArch, unix.SYS_CLONE, unix.CLONE_VM|unix.CLONE_NEWNET))
+
+	assert.Equal(seccompRetAllow, run(auditArch, unix.SYS_EXECVE, 0x7f0000001000))
+	assert.Equal(seccompRetErrno|int(unix.EPERM), run(auditArch, unix.SYS_EXECVE, 0x7f0000002000))
+	assert.Equal(seccompRetErrno|int(unix.EPERM), run(auditArch, unix.SYS_EXECVE, 0x7e0000001000))
+
+	assert.Equal(seccompRetErrno|int(unix.ENOSYS), run(auditArch, unix.SYS_CLONE3))
+}
+
+func TestPrepare(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "vmm-sandbox")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	p := NewProfile("/usr/bin/qemu", []string{"not_a_syscall"}, nil)
+	_, err = Prepare(dir, p)
+	assert.Error(err)
+
+	if auditArch == 0 {
+		return
+	}
+
+	p.Syscalls = nil
+	launcher, err := Prepare(dir, p)
+	assert.NoError(err)
+	assert.Equal(filepath.Join(dir, LauncherName), launcher)
+
+	exe, err := os.Executable()
+	assert.NoError(err)
+	target, err := os.Readlink(launcher)
+	assert.NoError(err)
+	assert.Equal(exe, target)
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, profileFile))
+	assert.NoError(err)
+	var stored Profile
+	assert.NoError(json.Unmarshal(data, &stored))
+	assert.Equal(p, stored)
+
+	// preparing again replaces the launcher
+	_, err = Prepare(dir, p)
+	assert.NoError(err)
+}
@@ -0,0 +1,105 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"context"
+)
+
+// PodStats are the stats of a sandbox as a whole: the stats of its
+// containers in the guest, their sum, the stats of the VM on the host and
+// the host overhead of the VM.
+type PodStats struct {
+	// VM are the stats of the VM, as seen from the host.
+	VM SandboxStats `json:"vm"`
+	// Containers is the sum of the cgroup stats of the containers: their
+	// CPU usage and throttling, memory usage and pids.
+	Containers CgroupStats `json:"containers"`
+	// PerContainer are the stats of each container, by container ID.
+	PerContainer map[string]ContainerStats `json:"per_container"`
+	// Overhead is the part of the VM usage not accounted to the containers.
+	Overhead PodOverhead `json:"overhead"`
+}
+
+// PodOverhead is the host usage of a sandbox not accounted to its
+// containers: the guest kernel and agent, the hypervisor, virtiofsd...
+type PodOverhead struct {
+	// CPUTime is the CPU time, in nanoseconds.
+	CPUTime uint64 `json:"cpu_time"`
+	// Memory is the memory usage, in bytes.
+	Memory uint64 `json:"memory"`
+}
+
+// NewPodStats returns the stats of a sandbox from the stats of its VM and
+// of its containers.
+func NewPodStats(vm SandboxStats, containers map[string]ContainerStats) PodStats {
+	stats := PodStats{
+		VM:           vm,
+		PerContainer: containers,
+	}
+	if stats.PerContainer == nil {
+		stats.PerContainer = make(map[string]ContainerStats)
+	}
+
+	sum := &stats.Containers
+	for _, c := range containers {
+		if c.CgroupStats == nil {
+			continue
+		}
+
+		cpu := c.CgroupStats.CPUStats
+		sum.CPUStats.CPUUsage.TotalUsage += cpu.CPUUsage.TotalUsage
+		sum.CPUStats.CPUUsage.UsageInKernelmode += cpu.CPUUsage.UsageInKernelmode
+		sum.CPUStats.CPUUsage.UsageInUsermode += cpu.CPUUsage.UsageInUsermode
+		sum.CPUStats.ThrottlingData.Periods += cpu.ThrottlingData.Periods
+		sum.CPUStats.ThrottlingData.ThrottledPeriods += cpu.ThrottlingData.ThrottledPeriods
+		sum.CPUStats.ThrottlingData.ThrottledTime += cpu.ThrottlingData.ThrottledTime
+
+		memory := c.CgroupStats.MemoryStats
+		sum.MemoryStats.Cache += memory.Cache
+		sum.MemoryStats.Usage.Usage += memory.Usage.Usage
+		sum.MemoryStats.SwapUsage.Usage += memory.SwapUsage.Usage
+
+		sum.PidsStats.Current += c.CgroupStats.PidsStats.Current
+	}
+
+	stats.Overhead = PodOverhead{
+		CPUTime: saturatingSub(vm.CgroupStats.CPUStats.CPUUsage.TotalUsage, sum.CPUStats.CPUUsage.TotalUsage),
+		Memory:  saturatingSub(vm.CgroupStats.MemoryStats.Usage.Usage, sum.MemoryStats.Usage.Usage),
+	}
+
+	return stats
+}
+
+// saturatingSub returns a - b, or 0 if b is greater than a: the guest and
+// host stats are not read at the same time.
+func saturatingSub(a, b uint64) uint64 {
+	if b > a {
+		return 0
+	}
+	return a - b
+}
+
+// StatsSandbox returns the stats of the VM and of all the containers of
+// the sandbox, so that the consumers don't need to call StatsContainer for
+// each container.
+func (s *Sandbox) StatsSandbox(ctx context.Context) (PodStats, error) {
+	vm, err := s.Stats(ctx)
+	if err != nil {
+		return PodStats{}, err
+	}
+
+	containers := make(map[string]ContainerStats, len(s.containers))
+	for id := range s.containers {
+		stats, err := s.StatsContainer(ctx, id)
+		if err != nil {
+			return PodStats{}, err
+		}
+		containers[id] = stats
+	}
+
+	return NewPodStats(vm, containers), nil
+}
@@ -0,0 +1,50 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPodStats(t *testing.T) {
+	assert := assert.New(t)
+
+	var vm SandboxStats
+	vm.CgroupStats.CPUStats.CPUUsage.TotalUsage = 1000
+	vm.CgroupStats.MemoryStats.Usage.Usage = 4096
+
+	container := func(cpu, memory uint64) ContainerStats {
+		stats := &CgroupStats{}
+		stats.CPUStats.CPUUsage.TotalUsage = cpu
+		stats.CPUStats.ThrottlingData.ThrottledPeriods = 1
+		stats.MemoryStats.Usage.Usage = memory
+		stats.PidsStats.Current = 2
+		return ContainerStats{CgroupStats: stats}
+	}
+
+	stats := NewPodStats(vm, map[string]ContainerStats{
+		"foo": container(100, 1024),
+		"bar": container(200, 2048),
+		// not started yet
+		"baz": {},
+	})
+	assert.Len(stats.PerContainer, 3)
+	assert.Equal(uint64(300), stats.Containers.CPUStats.CPUUsage.TotalUsage)
+	assert.Equal(uint64(2), stats.Containers.CPUStats.ThrottlingData.ThrottledPeriods)
+	assert.Equal(uint64(3072), stats.Containers.MemoryStats.Usage.Usage)
+	assert.Equal(uint64(4), stats.Containers.PidsStats.Current)
+	assert.Equal(PodOverhead{CPUTime: 700, Memory: 1024}, stats.Overhead)
+
+	// the guest stats read after the host ones may exceed them
+	stats = NewPodStats(vm, map[string]ContainerStats{"foo": container(2000, 1024)})
+	assert.Equal(PodOverhead{CPUTime: 0, Memory: 3072}, stats.Overhead)
+
+	stats = NewPodStats(vm, nil)
+	assert.NotNil(stats.PerContainer)
+	assert.Equal(PodOverhead{CPUTime: 1000, Memory: 4096}, stats.Overhead)
+}
@@ -83,6 +83,10 @@ type QemuState struct {
 	HotplugVFIOOnRootBus bool
 	VirtiofsdPid         int
 	PCIeRootPort         int
+	// SEBootImage is the Secure Execution boot image built for the VM
+	SEBootImage string
+	// Invocation is the command line QEMU was launched with
+	Invocation HypervisorInvocation
 }
 
 // qemu is an Hypervisor interface implementation for the Linux qemu hypervisor.
@@ -183,6 +187,9 @@ func (q *qemu) kernelParameters() string {
 	// set the maximum number of vCPUs
 	params = append(params, Param{"nr_cpus", fmt.Sprintf("%d", q.config.DefaultMaxVCPUs)})
 
+	// register the page sizes of the guest hugepages
+	params = append(params, q.hugepagesKernelParameters()...)
+
 	// add the params specified by the provided config. As the kernel
 	// honours the last parameter value set and since the config-provided
 	// params are added here, they will take priority over the defaults.
@@ -198,13 +205,30 @@ func (q *qemu) capabilities(ctx context.Context) types.Capabilities {
 	span, _ := katatrace.Trace(ctx, q.Logger(), "capabilities", q.tracingTags())
 	defer span.End()
 
-	return q.arch.capabilities()
+	caps := q.arch.capabilities()
+	// only virtio-mem can give memory back to the host
+	if q.config.VirtioMem {
+		caps.SetMemoryHotUnplugSupport()
+	}
+
+	return caps
 }
 
 func (q *qemu) hypervisorConfig() HypervisorConfig {
 	return q.config
 }
 
+// deviceArgs returns the command line arguments of the devices of the VM.
+func (q *qemu) deviceArgs() []string {
+	var args []string
+	for _, d := range q.qemuConfig.Devices {
+		if d.Valid() {
+			args = append(args, d.QemuParams(&q.qemuConfig)...)
+		}
+	}
+	return args
+}
+
 // get the QEMU binary path
 func (q *qemu) qemuPath() (string, error) {
 	p, err := q.config.HypervisorAssetPath()
@@ -240,11 +264,7 @@ func (q *qemu) setup(ctx context.Context, id string, hypervisorConfig *Hyperviso
 		return err
 	}
 
-	initrdPath, err := q.config.InitrdAssetPath()
-	if err != nil {
-		return err
-	}
-	imagePath, err := q.config.ImageAssetPath()
+	initrdPath, imagePath, err := q.guestBootAssets()
 	if err != nil {
 		return err
 	}
@@ -305,6 +325,15 @@ func (q *qemu) cpuTopology() govmmQemu.SMP {
 	return q.arch.cpuTopology(q.config.NumVCPUs, q.config.DefaultMaxVCPUs)
 }
 
+// cpuThreads returns the number of vCPUs of the hotplugged CPU devices,
+// the threads of a core.
+func (q *qemu) cpuThreads() uint32 {
+	if threads := q.cpuTopology().Threads; threads > 1 {
+		return threads
+	}
+	return 1
+}
+
 func (q *qemu) hostMemMB() (uint64, error) {
 	hostMemKb, err := getHostMemorySizeKb(procMemInfo)
 	if err != nil {
@@ -347,6 +376,10 @@ func (q *qemu) getQemuMachine() (govmmQemu.Machine, error) {
 }
 
 func (q *qemu) appendImage(ctx context.Context, devices []govmmQemu.Device) ([]govmmQemu.Device, error) {
+	if q.config.RootfsDiskPath != "" {
+		return q.appendRootfsDisk(ctx, devices)
+	}
+
 	imagePath, err := q.config.ImageAssetPath()
 	if err != nil {
 		return nil, err
@@ -463,6 +496,25 @@ func (q *qemu) setupFileBackedMem(knobs *govmmQemu.Knobs, memory *govmmQemu.Memo
 	memory.Path = target
 }
 
+// cpuModel returns the -cpu option of the VM, built from the configured
+// model and features on top of the architecture specific default model.
+func (q *qemu) cpuModel() string {
+	cpuModel := q.arch.cpuModel()
+	if q.config.CPUModel != "" {
+		// keep the architecture specific properties of the default model
+		cpuModel = q.config.CPUModel + strings.TrimPrefix(cpuModel, defaultCPUModel)
+	}
+	cpuModel += "," + q.config.CPUFeatures
+
+	if enabled, _ := parseCPUFeatures(q.config.CPUFeatures); len(enabled) > 0 {
+		// fail to start rather than silently running
+		// without the requested features
+		cpuModel += ",enforce"
+	}
+
+	return cpuModel
+}
+
 // createSandbox is the Hypervisor sandbox creation implementation for govmmQemu.
 func (q *qemu) createSandbox(ctx context.Context, id string, networkNS NetworkNamespace, hypervisorConfig *HypervisorConfig) error {
 	// Save the tracing context
@@ -475,6 +527,14 @@ func (q *qemu) createSandbox(ctx context.Context, id string, networkNS NetworkNa
 		return err
 	}
 
+	// The guest hugepages are added with DIMMs, they are allocated from
+	// the VM memory otherwise.
+	if len(q.config.GuestHugepages) > 0 && !q.arch.supportGuestMemoryHotplug() {
+		q.Logger().Warn("guest hugepages not backed by host hugepages, memory hotplug is not supported")
+		q.config.GuestHugepages = nil
+		hypervisorConfig.GuestHugepages = nil
+	}
+
 	machine, err := q.getQemuMachine()
 	if err != nil {
 		return err
@@ -505,7 +565,7 @@ func (q *qemu) createSandbox(ctx context.Context, id string, networkNS NetworkNa
 		return err
 	}
 
-	initrdPath, err := q.config.InitrdAssetPath()
+	initrdPath, _, err := q.guestBootAssets()
 	if err != nil {
 		return err
 	}
@@ -516,6 +576,18 @@ func (q *qemu) createSandbox(ctx context.Context, id string, networkNS NetworkNa
 		Params:     q.kernelParameters(),
 	}
 
+	// The initrd and the kernel parameters of the Secure
+	// Execution guests are part of their protected boot image.
+	if q.config.ConfidentialGuest && len(q.config.SEHostKeyDocuments) > 0 {
+		image, err := createSEBootImage(kernelPath, initrdPath, kernel.Params, q.config.SEHostKeyDocuments)
+		if err != nil {
+			return err
+		}
+
+		kernel = govmmQemu.Kernel{Path: image}
+		q.state.SEBootImage = image
+	}
+
 	incoming := q.setupTemplate(&knobs, &memory)
 
 	// With the current implementations, VM templating will not work with file
@@ -563,8 +635,10 @@ func (q *qemu) createSandbox(ctx context.Context, id string, networkNS NetworkNa
 		return err
 	}
 
-	cpuModel := q.arch.cpuModel()
-	cpuModel += "," + q.config.CPUFeatures
+	devices, err = q.appendGuestHugepages(devices)
+	if err != nil {
+		return err
+	}
 
 	firmwarePath, err := q.config.FirmwareAssetPath()
 	if err != nil {
@@ -590,7 +664,7 @@ func (q *qemu) createSandbox(ctx context.Context, id string, networkNS NetworkNa
 		SMP:         smp,
 		Memory:      memory,
 		Devices:     devices,
-		CPUModel:    cpuModel,
+		CPUModel:    q.cpuModel(),
 		Kernel:      kernel,
 		RTC:         rtc,
 		QMPSockets:  qmpSockets,
@@ -642,6 +716,8 @@ func (q *qemu) createSandbox(ctx context.Context, id string, networkNS NetworkNa
 		extraArgs:  q.config.VirtioFSExtraArgs,
 		debug:      q.config.Debug,
 		cache:      q.config.VirtioFSCache,
+		sandboxID:  q.id,
+		ids:        q.config.VirtioFSDaemonIDs,
 	}
 
 	return nil
@@ -752,7 +828,7 @@ func (q *qemu) setupVirtioMem(ctx context.Context) error {
 }
 
 // startSandbox will start the Sandbox's VM.
-func (q *qemu) startSandbox(ctx context.Context, timeout int) error {
+func (q *qemu) startSandbox(ctx context.Context, timeout int) (err error) {
 	span, ctx := katatrace.Trace(ctx, q.Logger(), "startSandbox", q.tracingTags())
 	defer span.End()
 
@@ -778,10 +854,13 @@ func (q *qemu) startSandbox(ctx context.Context, timeout int) error {
 	}()
 
 	vmPath := filepath.Join(q.store.RunVMStoragePath(), q.id)
-	err := os.MkdirAll(vmPath, DirMode)
+	err = os.MkdirAll(vmPath, DirMode)
 	if err != nil {
 		return err
 	}
+	if err = labelSandboxPath(&q.config, vmPath); err != nil {
+		return err
+	}
 	// append logfile only on debug
 	if q.config.Debug {
 		q.qemuConfig.LogFile = filepath.Join(vmPath, "qemu.log")
@@ -795,6 +874,16 @@ func (q *qemu) startSandbox(ctx context.Context, timeout int) error {
 		}
 	}()
 
+	cred, err := q.config.hypervisorCredential(q.id, vmPath)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			q.config.releaseHypervisorID(q.id)
+		}
+	}()
+
 	// This needs to be done as late as possible, just before launching
 	// virtiofsd are executed by kata-runtime after this call, run with
 	// the SELinux label. If these processes require privileged, we do
@@ -817,10 +906,23 @@ func (q *qemu) startSandbox(ctx context.Context, timeout int) error {
 			}
 		}()
 
+		var socketPath string
+		if socketPath, err = q.vhostFSSocketPath(q.id); err != nil {
+			return err
+		}
+		if err = shareSocketWithHypervisor(socketPath, cred); err != nil {
+			return err
+		}
+	}
+
+	qemuConfig := q.qemuConfig
+	qemuConfig.Path, err = q.config.vmmLauncher(vmPath, qemuConfig.Path, cred)
+	if err != nil {
+		return err
 	}
 
 	var strErr string
-	strErr, err = govmmQemu.LaunchQemu(q.qemuConfig, newQMPLogger())
+	strErr, err = govmmQemu.LaunchQemu(qemuConfig, newQMPLogger())
 	if err != nil {
 		if q.config.Debug && q.qemuConfig.LogFile != "" {
 			b, err := ioutil.ReadFile(q.qemuConfig.LogFile)
@@ -837,6 +939,16 @@ func (q *qemu) startSandbox(ctx context.Context, timeout int) error {
 		return err
 	}
 
+	// govmm does not expose the command line it builds, it is taken
+	// from the QEMU process
+	if pid := q.getPids()[0]; pid != 0 {
+		invocation, invErr := processInvocation(pid)
+		if invErr != nil {
+			q.Logger().WithError(invErr).Warn("failed to record the qemu invocation")
+		}
+		q.state.Invocation = invocation
+	}
+
 	if q.config.BootFromTemplate {
 		if err = q.bootFromTemplate(); err != nil {
 			return err
@@ -981,6 +1093,7 @@ func (q *qemu) stopSandbox(ctx context.Context, waitOnly bool) error {
 }
 
 func (q *qemu) cleanupVM() error {
+	q.config.releaseHypervisorID(q.id)
 
 	// cleanup vm path
 	dir := filepath.Join(q.store.RunVMStoragePath(), q.id)
@@ -1003,6 +1116,10 @@ func (q *qemu) cleanupVM() error {
 		}
 	}
 
+	if err := q.removeRootfsDiskOverlay(); err != nil {
+		q.Logger().WithError(err).Warn("failed to remove rootfs disk overlay")
+	}
+
 	if q.config.VMid != "" {
 		dir = filepath.Join(q.store.RunStoragePath(), q.config.VMid)
 		if err := os.RemoveAll(dir); err != nil {
@@ -1659,7 +1776,8 @@ func (q *qemu) hotplugCPUs(vcpus uint32, op operation) (uint32, error) {
 
 // try to hot add an amount of vCPUs, returns the number of vCPUs added
 func (q *qemu) hotplugAddCPUs(amount uint32) (uint32, error) {
-	currentVCPUs := q.qemuConfig.SMP.CPUs + uint32(len(q.state.HotpluggedVCPUs))
+	threads := q.cpuThreads()
+	currentVCPUs := q.qemuConfig.SMP.CPUs + uint32(len(q.state.HotpluggedVCPUs))*threads
 
 	// Don't fail if the number of max vCPUs is exceeded, log a warning and hot add the vCPUs needed
 	// to reach out max vCPUs
@@ -1711,11 +1829,12 @@ func (q *qemu) hotplugAddCPUs(amount uint32) (uint32, error) {
 		}
 
 		// a new vCPU was added, update list of hotplugged vCPUs and check if all vCPUs were added
+		// the CPU devices are cores of several threads with SMT
 		q.state.HotpluggedVCPUs = append(q.state.HotpluggedVCPUs, CPUDevice{cpuID})
-		hotpluggedVCPUs++
-		if hotpluggedVCPUs == amount {
+		hotpluggedVCPUs += threads
+		if hotpluggedVCPUs >= amount {
 			// All vCPUs were hotplugged
-			return amount, nil
+			return hotpluggedVCPUs, nil
 		}
 	}
 
@@ -1724,14 +1843,18 @@ func (q *qemu) hotplugAddCPUs(amount uint32) (uint32, error) {
 
 // try to  hot remove an amount of vCPUs, returns the number of vCPUs removed
 func (q *qemu) hotplugRemoveCPUs(amount uint32) (uint32, error) {
-	hotpluggedVCPUs := uint32(len(q.state.HotpluggedVCPUs))
+	threads := q.cpuThreads()
+	hotpluggedVCPUs := uint32(len(q.state.HotpluggedVCPUs)) * threads
 
 	// we can only remove hotplugged vCPUs
 	if amount > hotpluggedVCPUs {
 		return 0, fmt.Errorf("Unable to remove %d CPUs, currently there are only %d hotplugged CPUs", amount, hotpluggedVCPUs)
 	}
 
-	for i := uint32(0); i < amount; i++ {
+	// only whole cores can be removed with SMT
+	amount -= amount % threads
+
+	for i := uint32(0); i < amount; i += threads {
 		// get the last vCPUs and try to remove it
 		cpu := q.state.HotpluggedVCPUs[len(q.state.HotpluggedVCPUs)-1]
 		if err := q.qmpMonitorCh.qmp.ExecuteDeviceDel(q.qmpMonitorCh.ctx, cpu.ID); err != nil {
@@ -1895,6 +2018,10 @@ func (q *qemu) addDevice(ctx context.Context, devInfo interface{}, devType devic
 		}
 	case types.Socket:
 		q.qemuConfig.Devices = q.arch.appendSocket(q.qemuConfig.Devices, v)
+	case types.FwCfg:
+		q.qemuConfig.FwCfg = append(q.qemuConfig.FwCfg, govmmQemu.FwCfg{Name: v.Name, File: v.File})
+	case types.SharedMemory:
+		q.qemuConfig.Devices, err = q.arch.appendSharedMemory(q.qemuConfig.Devices, v)
 	case types.VSock:
 		q.fds = append(q.fds, v.VhostFd)
 		q.qemuConfig.Devices, err = q.arch.appendVSock(ctx, q.qemuConfig.Devices, v)
@@ -1903,6 +2030,13 @@ func (q *qemu) addDevice(ctx context.Context, devInfo interface{}, devType devic
 	case config.BlockDrive:
 		q.qemuConfig.Devices, err = q.arch.appendBlockDevice(ctx, q.qemuConfig.Devices, v)
 	case config.VhostUserDeviceAttrs:
+		if v.Type == config.VhostUserNet {
+			if err = checkVhostUserNetHugePages(q.config, vhostUserMemInfo); err != nil {
+				return err
+			}
+			// the vhost-user backend maps the guest memory
+			q.qemuConfig.Knobs.MemShared = true
+		}
 		q.qemuConfig.Devices, err = q.arch.appendVhostUserDevice(ctx, q.qemuConfig.Devices, v)
 	case config.VFIODev:
 		q.qemuConfig.Devices = q.arch.appendVFIODevice(q.qemuConfig.Devices, v)
@@ -2021,6 +2155,10 @@ func (q *qemu) resizeMemory(ctx context.Context, reqMemMB uint32, memoryBlockSiz
 	case currentMemory < reqMemMB:
 		//hotplug
 		addMemMB := reqMemMB - currentMemory
+		// the configured memory blocks are larger than the guest ones
+		if q.config.MemoryBlockSizeMB > memoryBlockSizeMB {
+			memoryBlockSizeMB = q.config.MemoryBlockSizeMB
+		}
 		memHotplugMB, err := calcHotplugMemMiBSize(addMemMB, memoryBlockSizeMB)
 		if err != nil {
 			return currentMemory, memoryDevice{}, err
@@ -2218,7 +2356,7 @@ func calcHotplugMemMiBSize(mem uint32, memorySectionSizeMB uint32) (uint32, erro
 
 func (q *qemu) resizeVCPUs(ctx context.Context, reqVCPUs uint32) (currentVCPUs uint32, newVCPUs uint32, err error) {
 
-	currentVCPUs = q.config.NumVCPUs + uint32(len(q.state.HotpluggedVCPUs))
+	currentVCPUs = q.config.NumVCPUs + uint32(len(q.state.HotpluggedVCPUs))*q.cpuThreads()
 	newVCPUs = currentVCPUs
 	switch {
 	case currentVCPUs < reqVCPUs:
@@ -2361,6 +2499,8 @@ func (q *qemu) save() (s persistapi.HypervisorState) {
 	s.HotpluggedMemory = q.state.HotpluggedMemory
 	s.HotplugVFIOOnRootBus = q.state.HotplugVFIOOnRootBus
 	s.PCIeRootPort = q.state.PCIeRootPort
+	s.SEBootImage = q.state.SEBootImage
+	s.Invocation = q.state.Invocation.save()
 
 	for _, bridge := range q.arch.getBridges() {
 		s.Bridges = append(s.Bridges, persistapi.Bridge{
@@ -2385,6 +2525,8 @@ func (q *qemu) load(s persistapi.HypervisorState) {
 	q.state.HotplugVFIOOnRootBus = s.HotplugVFIOOnRootBus
 	q.state.VirtiofsdPid = s.VirtiofsdPid
 	q.state.PCIeRootPort = s.PCIeRootPort
+	q.state.SEBootImage = s.SEBootImage
+	q.state.Invocation = loadHypervisorInvocation(s.Invocation)
 
 	for _, bridge := range s.Bridges {
 		q.state.Bridges = append(q.state.Bridges, types.NewBridge(types.Type(bridge.Type), bridge.ID, bridge.DeviceAddr, bridge.Addr))
@@ -144,11 +144,17 @@ type qemuArch interface {
 	// append pvpanic device
 	appendPVPanicDevice(devices []govmmQemu.Device) ([]govmmQemu.Device, error)
 
+	// appendSharedMemory appends a memory region shared with other VMs
+	appendSharedMemory(devices []govmmQemu.Device, region types.SharedMemory) ([]govmmQemu.Device, error)
+
 	// append protection device.
 	// This implementation is architecture specific, some archs may need
 	// a firmware, returns a string containing the path to the firmware that should
 	// be used with the -bios option, ommit -bios option if the path is empty.
 	appendProtectionDevice(devices []govmmQemu.Device, firmware string) ([]govmmQemu.Device, string, error)
+
+	// guestProtection returns the protection enabled for the guest
+	guestProtection() guestProtection
 }
 
 // Kind of guest protection
@@ -174,6 +180,20 @@ const (
 	seProtection
 )
 
+func (p guestProtection) String() string {
+	switch p {
+	case tdxProtection:
+		return "tdx"
+	case sevProtection:
+		return "sev"
+	case pefProtection:
+		return "pef"
+	case seProtection:
+		return "se"
+	}
+	return "none"
+}
+
 type qemuArchBase struct {
 	memoryOffset         uint64
 	networkIndex         int
@@ -718,6 +738,14 @@ func (q *qemuArchBase) appendRNGDevice(_ context.Context, devices []govmmQemu.De
 }
 
 func (q *qemuArchBase) handleImagePath(config HypervisorConfig) {
+	if config.RootfsDiskPath != "" {
+		// the rootfs disk is the first virtio-blk device
+		q.kernelParams = append(q.kernelParams, commonVirtioblkKernelRootParams...)
+		q.kernelParamsNonDebug = append(q.kernelParamsNonDebug, kernelParamsSystemdNonDebug...)
+		q.kernelParamsDebug = append(q.kernelParamsDebug, kernelParamsSystemdDebug...)
+		return
+	}
+
 	if config.ImagePath != "" {
 		kernelRootParams := commonVirtioblkKernelRootParams
 		if !q.disableNvdimm {
@@ -834,6 +862,44 @@ func (q *qemuArchBase) appendPVPanicDevice(devices []govmmQemu.Device) ([]govmmQ
 	return devices, nil
 }
 
+// ivshmemDevice is an ivshmem-plain device, exposing a host file shared
+// with other VMs as the BAR 2 of a PCI device.
+type ivshmemDevice struct {
+	ID      string
+	MemPath string
+	Size    uint64
+}
+
+// Valid returns true if the ivshmem device is complete.
+func (dev ivshmemDevice) Valid() bool {
+	return dev.ID != "" && dev.MemPath != "" && dev.Size != 0
+}
+
+// QemuParams returns the qemu parameters of the ivshmem device and of its
+// memory backend, which must be shared for the other VMs to see the writes.
+func (dev ivshmemDevice) QemuParams(config *govmmQemu.Config) []string {
+	memID := "shmmem-" + dev.ID
+
+	return []string{
+		"-object", fmt.Sprintf("memory-backend-file,id=%s,mem-path=%s,size=%d,share=on", memID, dev.MemPath, dev.Size),
+		"-device", fmt.Sprintf("ivshmem-plain,id=shm-%s,memdev=%s", dev.ID, memID),
+	}
+}
+
+// appendSharedMemory appends an ivshmem device mapping the shared region
+func (q *qemuArchBase) appendSharedMemory(devices []govmmQemu.Device, region types.SharedMemory) ([]govmmQemu.Device, error) {
+	dev := ivshmemDevice{
+		ID:      region.ID,
+		MemPath: region.Path,
+		Size:    region.Size,
+	}
+	if !dev.Valid() {
+		return devices, fmt.Errorf("invalid shared memory region %+v", region)
+	}
+
+	return append(devices, dev), nil
+}
+
 func (q *qemuArchBase) getPFlash() ([]string, error) {
 	return q.PFlash, nil
 }
@@ -842,6 +908,10 @@ func (q *qemuArchBase) setPFlash(p []string) {
 	q.PFlash = p
 }
 
+func (q *qemuArchBase) guestProtection() guestProtection {
+	return q.protection
+}
+
 // append protection device
 func (q *qemuArchBase) appendProtectionDevice(devices []govmmQemu.Device, firmware string) ([]govmmQemu.Device, string, error) {
 	virtLog.WithField("arch", runtime.GOARCH).Warnf("Confidential Computing has not been implemented for this architecture")
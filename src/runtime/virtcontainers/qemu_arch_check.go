@@ -0,0 +1,246 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"bytes"
+	"debug/elf"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+)
+
+// qemuMachineArchs are the architectures supporting each machine type.
+var qemuMachineArchs = map[string][]string{
+	QemuPCLite:    {"amd64"},
+	QemuQ35:       {"amd64"},
+	QemuMicrovm:   {"amd64"},
+	QemuVirt:      {"amd64", "arm64"},
+	QemuPseries:   {"ppc64le"},
+	QemuCCWVirtio: {"s390x"},
+}
+
+// elfMachineArchs are the architectures of the ELF machines.
+var elfMachineArchs = map[elf.Machine]string{
+	elf.EM_X86_64:  "amd64",
+	elf.EM_AARCH64: "arm64",
+	elf.EM_PPC64:   "ppc64le",
+	elf.EM_S390:    "s390x",
+}
+
+// GIC versions of the arm64 virt machine type
+var qemuGICVersions = []string{"2", "3", "4", "host", "max"}
+
+// SMT modes of the ppc64le pseries machine type
+var qemuSMTModes = []uint32{1, 2, 4, 8}
+
+const (
+	// MMU modes of the ppc64le guests
+	ppc64leMMURadix = "radix"
+	ppc64leMMUHash  = "hash"
+
+	// the memory blocks of the pseries machine type are 256MiB,
+	// the hotplugged memory must be aligned to them
+	ppc64leMemoryBlockSizeMB = 256
+)
+
+const (
+	// offset and magic of the arm64 kernel Image header
+	arm64ImageMagicOffset = 0x38
+	arm64ImageMagic       = "ARM\x64"
+
+	// offset and magic of the x86 bzImage setup header
+	bzImageMagicOffset = 0x202
+	bzImageMagic       = "HdrS"
+)
+
+// binaryArch returns the architecture a kernel or a firmware is built for,
+// or an empty string if it cannot be told from the file headers.
+func binaryArch(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	header := make([]byte, bzImageMagicOffset+len(bzImageMagic))
+	n, err := io.ReadFull(f, header)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", err
+	}
+	header = header[:n]
+
+	hasMagic := func(offset int, magic string) bool {
+		return len(header) >= offset+len(magic) && bytes.Equal(header[offset:offset+len(magic)], []byte(magic))
+	}
+
+	switch {
+	case hasMagic(0, elf.ELFMAG):
+		e, err := elf.NewFile(f)
+		if err != nil {
+			return "", fmt.Errorf("invalid ELF file %v: %v", path, err)
+		}
+		if e.Machine == elf.EM_PPC64 && e.ByteOrder == binary.BigEndian {
+			return "ppc64", nil
+		}
+		return elfMachineArchs[e.Machine], nil
+	case hasMagic(arm64ImageMagicOffset, arm64ImageMagic):
+		return "arm64", nil
+	case hasMagic(bzImageMagicOffset, bzImageMagic):
+		return "amd64", nil
+	}
+
+	return "", nil
+}
+
+func archSupported(archs []string, arch string) bool {
+	for _, a := range archs {
+		if a == arch {
+			return true
+		}
+	}
+	return false
+}
+
+// checkSecureExecutionConfig checks the configuration of the
+// Secure Execution confidential guests of s390x.
+func checkSecureExecutionConfig(conf HypervisorConfig, arch string) error {
+	if arch != "s390x" {
+		if len(conf.SEHostKeyDocuments) > 0 {
+			return fmt.Errorf("Secure Execution host key documents are only supported on s390x")
+		}
+		return nil
+	}
+
+	if !conf.ConfidentialGuest {
+		if len(conf.SEHostKeyDocuments) > 0 {
+			return fmt.Errorf("Secure Execution host key documents require a confidential guest")
+		}
+		return nil
+	}
+
+	// the guest cannot share its memory with the emulated devices
+	if !conf.IOMMUPlatform {
+		return fmt.Errorf("Secure Execution guests require the IOMMU platform for their virtio devices")
+	}
+
+	if len(conf.SEHostKeyDocuments) == 0 {
+		return nil
+	}
+
+	if conf.InitrdPath == "" {
+		return fmt.Errorf("Secure Execution boot images require an initrd")
+	}
+
+	for _, hkd := range conf.SEHostKeyDocuments {
+		if _, err := os.Stat(hkd); err != nil {
+			return fmt.Errorf("invalid Secure Execution host key document: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// checkPPC64leConfig checks the tuning options of the ppc64le guests.
+func checkPPC64leConfig(conf HypervisorConfig, arch string) error {
+	if arch != "ppc64le" {
+		if conf.SMTMode != 0 {
+			return fmt.Errorf("SMT mode is only supported on ppc64le")
+		}
+		if conf.MMUMode != "" {
+			return fmt.Errorf("MMU mode is only supported on ppc64le")
+		}
+		if conf.MemoryBlockSizeMB != 0 {
+			return fmt.Errorf("memory block size is only supported on ppc64le")
+		}
+		return nil
+	}
+
+	if conf.SMTMode != 0 {
+		valid := false
+		for _, mode := range qemuSMTModes {
+			valid = valid || mode == conf.SMTMode
+		}
+		if !valid {
+			return fmt.Errorf("invalid SMT mode %v, valid modes are %v", conf.SMTMode, qemuSMTModes)
+		}
+
+		// the vCPUs are hotplugged by cores
+		if conf.NumVCPUs%conf.SMTMode != 0 || conf.DefaultMaxVCPUs%conf.SMTMode != 0 {
+			return fmt.Errorf("the number of vCPUs %v and the maximum number of vCPUs %v must be multiples of the SMT mode %v",
+				conf.NumVCPUs, conf.DefaultMaxVCPUs, conf.SMTMode)
+		}
+	}
+
+	if conf.MMUMode != "" && conf.MMUMode != ppc64leMMURadix && conf.MMUMode != ppc64leMMUHash {
+		return fmt.Errorf("invalid MMU mode %v, valid modes are %v and %v", conf.MMUMode, ppc64leMMURadix, ppc64leMMUHash)
+	}
+
+	if conf.MemoryBlockSizeMB%ppc64leMemoryBlockSizeMB != 0 {
+		return fmt.Errorf("memory block size %vMiB is not a multiple of %vMiB", conf.MemoryBlockSizeMB, ppc64leMemoryBlockSizeMB)
+	}
+
+	return nil
+}
+
+// CheckQemuArchConfig checks the qemu configuration is consistent with the
+// arch architecture: the machine type must be supported by it, the kernel
+// and the firmware must be built for it, and the tuning options of the
+// other architectures cannot be used. The machine types and the binaries
+// this does not know about are left to the architecture specific checks.
+func CheckQemuArchConfig(conf HypervisorConfig, arch string) error {
+	if archs, ok := qemuMachineArchs[conf.HypervisorMachineType]; ok && !archSupported(archs, arch) {
+		return fmt.Errorf("machine type %v is not supported on %v, only on %v", conf.HypervisorMachineType, arch, archs)
+	}
+
+	binaries := []struct {
+		kind string
+		path string
+	}{
+		{"kernel", conf.KernelPath},
+		{"firmware", conf.FirmwarePath},
+	}
+
+	for _, b := range binaries {
+		if b.path == "" {
+			continue
+		}
+
+		binArch, err := binaryArch(b.path)
+		if err != nil {
+			return err
+		}
+
+		if binArch != "" && binArch != arch {
+			return fmt.Errorf("%v %v is built for %v, it cannot run on %v", b.kind, b.path, binArch, arch)
+		}
+	}
+
+	if err := checkSecureExecutionConfig(conf, arch); err != nil {
+		return err
+	}
+
+	if err := checkPPC64leConfig(conf, arch); err != nil {
+		return err
+	}
+
+	if arch != "arm64" {
+		if conf.GICVersion != "" {
+			return fmt.Errorf("GIC version is only supported on arm64")
+		}
+		if conf.DisableITS {
+			return fmt.Errorf("disabling the ITS is only supported on arm64")
+		}
+		return nil
+	}
+
+	if conf.GICVersion != "" && !archSupported(qemuGICVersions, conf.GICVersion) {
+		return fmt.Errorf("invalid GIC version %v, valid versions are %v", conf.GICVersion, qemuGICVersions)
+	}
+
+	return nil
+}
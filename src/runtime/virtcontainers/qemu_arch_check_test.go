@@ -0,0 +1,134 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeKernelHeader(assert *assert.Assertions, path string, offset int, magic string) {
+	data := make([]byte, 1024)
+	copy(data[offset:], magic)
+	assert.NoError(ioutil.WriteFile(path, data, 0600))
+}
+
+func TestBinaryArch(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "qemu-arch")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	arm64Image := filepath.Join(dir, "Image")
+	writeKernelHeader(assert, arm64Image, arm64ImageMagicOffset, arm64ImageMagic)
+	arch, err := binaryArch(arm64Image)
+	assert.NoError(err)
+	assert.Equal("arm64", arch)
+
+	bzImage := filepath.Join(dir, "bzImage")
+	writeKernelHeader(assert, bzImage, bzImageMagicOffset, bzImageMagic)
+	arch, err = binaryArch(bzImage)
+	assert.NoError(err)
+	assert.Equal("amd64", arch)
+
+	// the test binary is an ELF file of the host architecture
+	self, err := os.Executable()
+	assert.NoError(err)
+	arch, err = binaryArch(self)
+	assert.NoError(err)
+	assert.Equal(runtime.GOARCH, arch)
+
+	unknown := filepath.Join(dir, "unknown")
+	assert.NoError(ioutil.WriteFile(unknown, []byte("foo"), 0600))
+	arch, err = binaryArch(unknown)
+	assert.NoError(err)
+	assert.Empty(arch)
+
+	_, err = binaryArch(filepath.Join(dir, "nonexistent"))
+	assert.Error(err)
+}
+
+func TestCheckQemuArchConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "qemu-arch")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	arm64Image := filepath.Join(dir, "Image")
+	writeKernelHeader(assert, arm64Image, arm64ImageMagicOffset, arm64ImageMagic)
+
+	// machine types
+	assert.NoError(CheckQemuArchConfig(HypervisorConfig{HypervisorMachineType: QemuQ35}, "amd64"))
+	assert.NoError(CheckQemuArchConfig(HypervisorConfig{HypervisorMachineType: QemuVirt}, "arm64"))
+	assert.Error(CheckQemuArchConfig(HypervisorConfig{HypervisorMachineType: QemuQ35}, "arm64"))
+	assert.Error(CheckQemuArchConfig(HypervisorConfig{HypervisorMachineType: QemuPseries}, "s390x"))
+	// left to the architecture checks
+	assert.NoError(CheckQemuArchConfig(HypervisorConfig{HypervisorMachineType: "foo"}, "amd64"))
+
+	// kernel
+	conf := HypervisorConfig{
+		HypervisorMachineType: QemuVirt,
+		KernelPath:            arm64Image,
+	}
+	assert.NoError(CheckQemuArchConfig(conf, "arm64"))
+	assert.Error(CheckQemuArchConfig(conf, "amd64"))
+
+	// firmware
+	conf = HypervisorConfig{FirmwarePath: arm64Image}
+	assert.Error(CheckQemuArchConfig(conf, "ppc64le"))
+
+	// arm64 virt machine type tuning
+	conf = HypervisorConfig{
+		HypervisorMachineType: QemuVirt,
+		GICVersion:            "3",
+		DisableITS:            true,
+	}
+	assert.NoError(CheckQemuArchConfig(conf, "arm64"))
+	assert.Error(CheckQemuArchConfig(conf, "amd64"))
+
+	conf.GICVersion = "5"
+	assert.Error(CheckQemuArchConfig(conf, "arm64"))
+}
+
+func TestCheckPPC64leConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	conf := HypervisorConfig{
+		HypervisorMachineType: QemuPseries,
+		NumVCPUs:              4,
+		DefaultMaxVCPUs:       16,
+		SMTMode:               4,
+		MMUMode:               ppc64leMMUHash,
+		MemoryBlockSizeMB:     512,
+	}
+	assert.NoError(CheckQemuArchConfig(conf, "ppc64le"))
+	assert.Error(CheckQemuArchConfig(HypervisorConfig{SMTMode: 4}, "amd64"))
+	assert.Error(CheckQemuArchConfig(HypervisorConfig{MMUMode: ppc64leMMURadix}, "arm64"))
+	assert.Error(CheckQemuArchConfig(HypervisorConfig{MemoryBlockSizeMB: 256}, "s390x"))
+
+	// the vCPUs are hotplugged by cores
+	conf.NumVCPUs = 2
+	assert.Error(CheckQemuArchConfig(conf, "ppc64le"))
+	conf.NumVCPUs = 4
+
+	conf.SMTMode = 3
+	assert.Error(CheckQemuArchConfig(conf, "ppc64le"))
+	conf.SMTMode = 4
+
+	conf.MMUMode = "foo"
+	assert.Error(CheckQemuArchConfig(conf, "ppc64le"))
+	conf.MMUMode = ppc64leMMURadix
+
+	conf.MemoryBlockSizeMB = 384
+	assert.Error(CheckQemuArchConfig(conf, "ppc64le"))
+}
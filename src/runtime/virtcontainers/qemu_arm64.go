@@ -8,6 +8,8 @@ package virtcontainers
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	govmmQemu "github.com/kata-containers/govmm/qemu"
@@ -66,6 +68,10 @@ func newQemuArch(config HypervisorConfig) (qemuArch, error) {
 		return nil, fmt.Errorf("unrecognised machinetype: %v", machineType)
 	}
 
+	if maxVCPUs, ok := gicList[gicVersion(config)]; ok && config.DefaultMaxVCPUs > maxVCPUs {
+		return nil, fmt.Errorf("GIC version %v supports at most %d vCPUs", config.GICVersion, maxVCPUs)
+	}
+
 	q := &qemuArm64{
 		qemuArchBase{
 			qemuMachine:          supportedQemuMachine,
@@ -79,11 +85,38 @@ func newQemuArch(config HypervisorConfig) (qemuArch, error) {
 		},
 	}
 
+	q.qemuMachine.Options = machineOptions(config)
+
 	q.handleImagePath(config)
 
 	return q, nil
 }
 
+// gicVersion returns the configured GIC version, 0 for the host one.
+func gicVersion(config HypervisorConfig) uint32 {
+	v, err := strconv.ParseUint(config.GICVersion, 10, 32)
+	if err != nil {
+		return 0
+	}
+	return uint32(v)
+}
+
+// machineOptions returns the options of the virt machine type,
+// tuned by the GIC version and the ITS configuration.
+func machineOptions(config HypervisorConfig) string {
+	options := defaultQemuMachineOptions
+
+	if config.GICVersion != "" {
+		options = strings.Replace(options, "gic-version=host", "gic-version="+config.GICVersion, 1)
+	}
+
+	if config.DisableITS {
+		options += ",its=off"
+	}
+
+	return options
+}
+
 func (q *qemuArm64) bridges(number uint32) {
 	q.Bridges = genericBridges(number, q.qemuMachine.Type)
 }
@@ -139,3 +139,23 @@ func TestQemuArm64WithInitrd(t *testing.T) {
 
 	assert.NotContains(m.machine().Options, qemuNvdimmOption)
 }
+
+func TestQemuArm64MachineOptions(t *testing.T) {
+	assert := assert.New(t)
+
+	arm64 := newTestQemu(assert, QemuVirt)
+	assert.Equal(defaultQemuMachineOptions, arm64.machine().Options)
+
+	config := qemuConfig(QemuVirt)
+	config.GICVersion = "3"
+	config.DisableITS = true
+	arch, err := newQemuArch(config)
+	assert.NoError(err)
+	assert.Equal("usb=off,accel=kvm,gic-version=3,its=off", arch.machine().Options)
+
+	// GICv2 supports up to 8 vCPUs
+	config.GICVersion = "2"
+	config.DefaultMaxVCPUs = 16
+	_, err = newQemuArch(config)
+	assert.Error(err)
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"fmt"
+	"sort"
+
+	govmmQemu "github.com/kata-containers/govmm/qemu"
+)
+
+// hugepagesDimm is a memory DIMM backed by the host hugepages mounted on
+// MemPath, preallocated when the VM starts.
+type hugepagesDimm struct {
+	ID      string
+	MemPath string
+	Size    uint64
+}
+
+// Valid returns true if the hugepages DIMM is complete.
+func (dev hugepagesDimm) Valid() bool {
+	return dev.ID != "" && dev.MemPath != "" && dev.Size != 0
+}
+
+// QemuParams returns the qemu parameters of the DIMM and of its memory
+// backend, shared like the rest of the guest memory can be.
+func (dev hugepagesDimm) QemuParams(config *govmmQemu.Config) []string {
+	memID := "hugemem-" + dev.ID
+
+	return []string{
+		"-object", fmt.Sprintf("memory-backend-file,id=%s,mem-path=%s,size=%d,prealloc=on,share=on", memID, dev.MemPath, dev.Size),
+		"-device", fmt.Sprintf("pc-dimm,id=hugedimm-%s,memdev=%s", dev.ID, memID),
+	}
+}
+
+// guestHugepageSizes returns the page sizes of the guest hugepages, sorted.
+func (q *qemu) guestHugepageSizes() []uint64 {
+	var sizes []uint64
+	for size := range q.config.GuestHugepages {
+		sizes = append(sizes, size)
+	}
+	sort.Slice(sizes, func(i, j int) bool { return sizes[i] < sizes[j] })
+	return sizes
+}
+
+// appendGuestHugepages appends a DIMM per page size of the guest hugepages,
+// backed by the host hugepages of the same size. The DIMMs take memory
+// hotplug slots.
+func (q *qemu) appendGuestHugepages(devices []govmmQemu.Device) ([]govmmQemu.Device, error) {
+	for _, size := range q.guestHugepageSizes() {
+		memPath, err := hostHugetlbfsMount(size)
+		if err != nil {
+			return nil, err
+		}
+
+		dev := hugepagesDimm{
+			ID:      hugepageSizeParam(size),
+			MemPath: memPath,
+			Size:    q.config.GuestHugepages[size],
+		}
+		if !dev.Valid() {
+			return nil, fmt.Errorf("invalid guest hugepages %+v", dev)
+		}
+
+		devices = append(devices, dev)
+	}
+
+	return devices, nil
+}
+
+// hugepagesKernelParameters returns the kernel parameters registering the
+// page sizes of the guest hugepages, which the agent allocates for the
+// containers.
+func (q *qemu) hugepagesKernelParameters() []Param {
+	var params []Param
+	for _, size := range q.guestHugepageSizes() {
+		params = append(params, Param{"hugepagesz", hugepageSizeParam(size)})
+	}
+	return params
+}
@@ -17,6 +17,12 @@ import (
 type qemuPPC64le struct {
 	// inherit from qemuArchBase, overwrite methods if needed
 	qemuArchBase
+
+	// threads per core
+	smtMode uint32
+
+	// size of the hotplugged memory blocks
+	memoryBlockSizeMB uint64
 }
 
 const defaultQemuPath = "/usr/bin/qemu-system-ppc64"
@@ -79,6 +85,13 @@ func newQemuArch(config HypervisorConfig) (qemuArch, error) {
 			kernelParams:         kernelParams,
 			protection:           noneProtection,
 		},
+		ppc64leSMTMode(config),
+		ppc64leMemoryBlockSize(config),
+	}
+
+	// radix is the default MMU mode of the POWER9 guests
+	if config.MMUMode == ppc64leMMUHash {
+		q.kernelParams = append(append([]Param{}, kernelParams...), Param{"disable_radix", ""})
 	}
 
 	if config.ConfidentialGuest {
@@ -94,6 +107,23 @@ func newQemuArch(config HypervisorConfig) (qemuArch, error) {
 	return q, nil
 }
 
+// ppc64leSMTMode returns the configured SMT mode, 1 when unset.
+func ppc64leSMTMode(config HypervisorConfig) uint32 {
+	if config.SMTMode == 0 {
+		return 1
+	}
+	return config.SMTMode
+}
+
+// ppc64leMemoryBlockSize returns the configured memory block size,
+// the one of the pseries machine type when unset.
+func ppc64leMemoryBlockSize(config HypervisorConfig) uint64 {
+	if config.MemoryBlockSizeMB == 0 {
+		return ppc64leMemoryBlockSizeMB
+	}
+	return uint64(config.MemoryBlockSizeMB)
+}
+
 func (q *qemuPPC64le) capabilities() types.Capabilities {
 	var caps types.Capabilities
 
@@ -116,10 +146,22 @@ func (q *qemuPPC64le) cpuModel() string {
 	return defaultCPUModel
 }
 
+// cpuTopology returns the vCPUs topology, with cores of smtMode threads.
+func (q *qemuPPC64le) cpuTopology(vcpus, maxvcpus uint32) govmmQemu.SMP {
+	smp := q.qemuArchBase.cpuTopology(vcpus, maxvcpus)
+
+	if q.smtMode > 1 {
+		smp.Sockets = maxvcpus / q.smtMode
+		smp.Threads = q.smtMode
+	}
+
+	return smp
+}
+
 func (q *qemuPPC64le) memoryTopology(memoryMb, hostMemoryMb uint64, slots uint8) govmmQemu.Memory {
 
-	q.Logger().Debug("Aligning maxmem to multiples of 256MB. Assumption: Kernel Version >= 4.11")
-	hostMemoryMb -= (hostMemoryMb % 256)
+	q.Logger().Debugf("Aligning maxmem to multiples of %dMB. Assumption: Kernel Version >= 4.11", q.memoryBlockSizeMB)
+	hostMemoryMb -= (hostMemoryMb % q.memoryBlockSizeMB)
 	return genericMemoryTopology(memoryMb, hostMemoryMb, slots, q.memoryOffset)
 }
 
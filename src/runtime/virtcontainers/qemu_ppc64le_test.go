@@ -100,3 +100,50 @@ func TestQemuPPC64leAppendProtectionDevice(t *testing.T) {
 	assert.Equal(expectedOut, devices)
 
 }
+
+func TestQemuPPC64leSMTMode(t *testing.T) {
+	assert := assert.New(t)
+
+	ppc64le, err := newQemuArch(HypervisorConfig{
+		HypervisorMachineType: QemuPseries,
+		SMTMode:               4,
+	})
+	assert.NoError(err)
+
+	smp := ppc64le.cpuTopology(4, 16)
+	assert.Equal(uint32(4), smp.Sockets)
+	assert.Equal(uint32(4), smp.Threads)
+	assert.Equal(uint32(16), smp.MaxCPUs)
+
+	ppc64le = newTestQemu(assert, QemuPseries)
+	smp = ppc64le.cpuTopology(4, 16)
+	assert.Equal(uint32(16), smp.Sockets)
+	assert.Equal(uint32(1), smp.Threads)
+}
+
+func TestQemuPPC64leMMUMode(t *testing.T) {
+	assert := assert.New(t)
+
+	ppc64le, err := newQemuArch(HypervisorConfig{
+		HypervisorMachineType: QemuPseries,
+		MMUMode:               ppc64leMMUHash,
+	})
+	assert.NoError(err)
+	assert.Contains(ppc64le.kernelParameters(false), Param{"disable_radix", ""})
+
+	ppc64le = newTestQemu(assert, QemuPseries)
+	assert.NotContains(ppc64le.kernelParameters(false), Param{"disable_radix", ""})
+}
+
+func TestQemuPPC64leMemoryBlockSize(t *testing.T) {
+	assert := assert.New(t)
+
+	ppc64le, err := newQemuArch(HypervisorConfig{
+		HypervisorMachineType: QemuPseries,
+		MemoryBlockSizeMB:     1024,
+	})
+	assert.NoError(err)
+
+	m := ppc64le.memoryTopology(1024, 3000, 10)
+	assert.Equal("3072M", m.MaxMem)
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	govmmQemu "github.com/kata-containers/govmm/qemu"
+	pkgUtils "github.com/kata-containers/kata-containers/src/runtime/pkg/utils"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/device/config"
+)
+
+// formats of the guest rootfs disks
+const (
+	rootfsDiskFormatRaw   = "raw"
+	rootfsDiskFormatQcow2 = "qcow2"
+)
+
+const (
+	rootfsDiskID      = "rootfs-disk"
+	rootfsDiskOverlay = "rootfs-overlay.qcow2"
+)
+
+// createRootfsDiskOverlay creates the transient overlay of the rootfs disk
+// in dir: the disk itself is only read, the guest writes go to the overlay,
+// which is removed along with the VM.
+func createRootfsDiskOverlay(disk, format, dir string) (string, error) {
+	if _, err := os.Stat(disk); err != nil {
+		return "", err
+	}
+
+	if format == "" {
+		format = rootfsDiskFormatRaw
+	}
+
+	if err := os.MkdirAll(dir, DirMode); err != nil {
+		return "", err
+	}
+
+	overlay := filepath.Join(dir, rootfsDiskOverlay)
+	if _, err := pkgUtils.RunHostCommand("qemu-img", "create", "-f", rootfsDiskFormatQcow2, "-F", format, "-b", disk, overlay); err != nil {
+		return "", err
+	}
+
+	return overlay, nil
+}
+
+// guestBootAssets returns the guest initrd and image paths, neither
+// of them is used when the guest boots from a rootfs disk.
+func (q *qemu) guestBootAssets() (initrdPath, imagePath string, err error) {
+	if q.config.RootfsDiskPath != "" {
+		return "", "", nil
+	}
+
+	if initrdPath, err = q.config.InitrdAssetPath(); err != nil {
+		return "", "", err
+	}
+
+	if imagePath, err = q.config.ImageAssetPath(); err != nil {
+		return "", "", err
+	}
+
+	return initrdPath, imagePath, nil
+}
+
+// rootfsDiskOverlayDir returns the directory of the rootfs disk overlay of
+// the VM, on a disk rather than in the VM storage, a tmpfs.
+func (q *qemu) rootfsDiskOverlayDir() string {
+	return filepath.Join(q.config.RootfsDiskOverlayDir, q.id)
+}
+
+// appendRootfsDisk attaches the overlay of the guest rootfs disk,
+// as the first block device of the VM.
+func (q *qemu) appendRootfsDisk(ctx context.Context, devices []govmmQemu.Device) ([]govmmQemu.Device, error) {
+	overlay, err := createRootfsDiskOverlay(q.config.RootfsDiskPath, q.config.RootfsDiskFormat,
+		q.rootfsDiskOverlayDir())
+	if err != nil {
+		return nil, err
+	}
+	if err = labelSandboxPath(&q.config, overlay); err != nil {
+		return nil, err
+	}
+
+	return q.arch.appendBlockDevice(ctx, devices, config.BlockDrive{
+		File:   overlay,
+		Format: rootfsDiskFormatQcow2,
+		ID:     rootfsDiskID,
+	})
+}
+
+// removeRootfsDiskOverlay removes the rootfs disk overlay of the VM, if any.
+func (q *qemu) removeRootfsDiskOverlay() error {
+	if q.config.RootfsDiskPath == "" || q.config.RootfsDiskOverlayDir == "" || q.id == "" {
+		return nil
+	}
+
+	return os.RemoveAll(q.rootfsDiskOverlayDir())
+}
@@ -0,0 +1,137 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	govmmQemu "github.com/kata-containers/govmm/qemu"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/persist"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateRootfsDiskOverlay(t *testing.T) {
+	assert := assert.New(t)
+
+	commands, restore := mockHostCommands(nil)
+	defer restore()
+
+	dir, err := ioutil.TempDir("", "rootfs-disk")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	disk := filepath.Join(dir, "rootfs.img")
+	assert.NoError(ioutil.WriteFile(disk, nil, 0600))
+	overlayDir := filepath.Join(dir, "overlay")
+
+	overlay, err := createRootfsDiskOverlay(disk, "", overlayDir)
+	assert.NoError(err)
+	assert.Equal(filepath.Join(overlayDir, rootfsDiskOverlay), overlay)
+	assert.Equal([]string{"qemu-img create -f qcow2 -F raw -b " + disk + " " + overlay}, *commands)
+
+	*commands = nil
+	_, err = createRootfsDiskOverlay(disk, rootfsDiskFormatQcow2, overlayDir)
+	assert.NoError(err)
+	assert.Equal([]string{"qemu-img create -f qcow2 -F qcow2 -b " + disk + " " + overlay}, *commands)
+
+	// missing disk
+	*commands = nil
+	_, err = createRootfsDiskOverlay(filepath.Join(dir, "nonexistent"), "", overlayDir)
+	assert.Error(err)
+	assert.Empty(*commands)
+}
+
+func TestQemuAppendRootfsDisk(t *testing.T) {
+	assert := assert.New(t)
+
+	commands, restore := mockHostCommands(nil)
+	defer restore()
+
+	dir, err := ioutil.TempDir("", "rootfs-disk")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	disk := filepath.Join(dir, "rootfs.qcow2")
+	assert.NoError(ioutil.WriteFile(disk, nil, 0600))
+
+	store, err := persist.GetDriver()
+	assert.NoError(err)
+
+	qemuConfig := newQemuConfig()
+	qemuConfig.RootfsDiskPath = disk
+	qemuConfig.RootfsDiskFormat = rootfsDiskFormatQcow2
+	qemuConfig.RootfsDiskOverlayDir = filepath.Join(dir, "overlay")
+
+	q := &qemu{
+		id:     "testRootfsDisk",
+		config: qemuConfig,
+		store:  store,
+		arch:   &qemuArchBase{},
+	}
+
+	// the image and the initrd are bypassed
+	initrdPath, imagePath, err := q.guestBootAssets()
+	assert.NoError(err)
+	assert.Empty(initrdPath)
+	assert.Empty(imagePath)
+
+	devices, err := q.appendImage(context.Background(), nil)
+	assert.NoError(err)
+	assert.Len(*commands, 1)
+
+	// on disk, not in the VM storage
+	overlay := filepath.Join(dir, "overlay", q.id, rootfsDiskOverlay)
+	assert.Equal([]govmmQemu.Device{
+		govmmQemu.BlockDevice{
+			Driver:    govmmQemu.VirtioBlock,
+			ID:        rootfsDiskID,
+			File:      overlay,
+			AIO:       govmmQemu.Threads,
+			Format:    govmmQemu.QCOW2,
+			Interface: "none",
+		},
+	}, devices)
+
+	// removed along with the VM
+	assert.NoError(os.MkdirAll(filepath.Dir(overlay), DirMode))
+	assert.NoError(q.cleanupVM())
+	_, err = os.Stat(filepath.Dir(overlay))
+	assert.True(os.IsNotExist(err))
+}
+
+func TestQemuArchBaseHandleRootfsDisk(t *testing.T) {
+	assert := assert.New(t)
+
+	q := &qemuArchBase{}
+	q.handleImagePath(HypervisorConfig{
+		ImagePath:      testQemuImagePath,
+		RootfsDiskPath: "/path/to/rootfs.img",
+	})
+
+	assert.Equal(commonVirtioblkKernelRootParams, q.kernelParams)
+	assert.NotContains(q.qemuMachine.Options, qemuNvdimmOption)
+}
+
+func TestHypervisorConfigRootfsDisk(t *testing.T) {
+	assert := assert.New(t)
+
+	conf := HypervisorConfig{
+		KernelPath:     "/path/to/kernel",
+		HypervisorPath: "/path/to/hypervisor",
+		RootfsDiskPath: "/path/to/rootfs.img",
+	}
+	assert.Error(conf.valid())
+
+	conf.RootfsDiskOverlayDir = "/var/lib/rootfs-disk"
+	assert.NoError(conf.valid())
+
+	conf.RootfsDiskFormat = "vmdk"
+	assert.Error(conf.valid())
+}
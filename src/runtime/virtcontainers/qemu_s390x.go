@@ -84,7 +84,7 @@ func newQemuArch(config HypervisorConfig) (qemuArch, error) {
 		}
 	}
 
-	if config.ImagePath != "" {
+	if config.ImagePath != "" || config.RootfsDiskPath != "" {
 		q.kernelParams = append(q.kernelParams, commonVirtioblkKernelRootParams...)
 		q.kernelParamsNonDebug = append(q.kernelParamsNonDebug, kernelParamsSystemdNonDebug...)
 		q.kernelParamsDebug = append(q.kernelParamsDebug, kernelParamsSystemdDebug...)
@@ -305,6 +305,10 @@ func (q *qemuS390x) appendIOMMU(devices []govmmQemu.Device) ([]govmmQemu.Device,
 	return devices, fmt.Errorf("S390x does not support appending a vIOMMU")
 }
 
+func (q *qemuS390x) appendSharedMemory(devices []govmmQemu.Device, region types.SharedMemory) ([]govmmQemu.Device, error) {
+	return devices, fmt.Errorf("S390x does not support ivshmem devices")
+}
+
 func (q *qemuS390x) addDeviceToBridge(ctx context.Context, ID string, t types.Type) (string, types.Bridge, error) {
 	addr, b, err := genericAddDeviceToBridge(ctx, q.Bridges, ID, types.CCW)
 	if err != nil {
@@ -0,0 +1,155 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	pkgUtils "github.com/kata-containers/kata-containers/src/runtime/pkg/utils"
+)
+
+// seImageDir holds the Secure Execution boot images built by the runtime.
+var seImageDir = "/run/kata-containers/secure-execution"
+
+// GuestProtectionStatus is the guest protection of a sandbox, for the
+// attestation workflows.
+type GuestProtectionStatus struct {
+	// Confidential is true when the VM is a confidential guest.
+	Confidential bool `json:"confidential"`
+
+	// Technology is the hardware technology protecting the guest.
+	Technology string `json:"technology"`
+
+	// HostKeyDocuments are the host key documents
+	// the Secure Execution boot image is built for.
+	HostKeyDocuments []string `json:"host_key_documents,omitempty"`
+
+	// BootImage is the Secure Execution boot image built by the runtime.
+	BootImage string `json:"boot_image,omitempty"`
+
+	// BootImageDigest is the SHA-256 digest of the boot image.
+	BootImageDigest string `json:"boot_image_digest,omitempty"`
+}
+
+// seBootImagePath returns the path of the Secure Execution boot image
+// of a kernel, an initrd and kernel parameters, built for the host key
+// documents. The images are shared by the sandboxes with the same inputs.
+func seBootImagePath(kernel, initrd, params string, hostKeyDocuments []string) (string, error) {
+	h := sha256.New()
+
+	for _, path := range append([]string{kernel, initrd}, hostKeyDocuments...) {
+		info, err := os.Stat(path)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(h, "%s %d %d\n", path, info.Size(), info.ModTime().UnixNano())
+	}
+	fmt.Fprintf(h, "%s\n", params)
+
+	return filepath.Join(seImageDir, hex.EncodeToString(h.Sum(nil))+".img"), nil
+}
+
+// createSEBootImage builds the Secure Execution boot image of a kernel, an
+// initrd and kernel parameters with genprotimg, unless it exists already.
+// The image is encrypted with the keys of the host key documents, only the
+// hosts they belong to can run it.
+func createSEBootImage(kernel, initrd, params string, hostKeyDocuments []string) (string, error) {
+	image, err := seBootImagePath(kernel, initrd, params, hostKeyDocuments)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := os.Stat(image); err == nil {
+		return image, nil
+	}
+
+	if err := os.MkdirAll(seImageDir, DirMode); err != nil {
+		return "", err
+	}
+
+	tmp, err := ioutil.TempDir(seImageDir, "build-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(tmp)
+
+	parmfile := filepath.Join(tmp, "parmfile")
+	if err := ioutil.WriteFile(parmfile, []byte(params), 0600); err != nil {
+		return "", err
+	}
+
+	output := filepath.Join(tmp, "se.img")
+	args := []string{"-i", kernel, "-r", initrd, "-p", parmfile, "-o", output}
+	for _, hkd := range hostKeyDocuments {
+		args = append(args, "-k", hkd)
+	}
+
+	if _, err := pkgUtils.RunHostCommand("genprotimg", args...); err != nil {
+		return "", err
+	}
+
+	// the sandboxes started concurrently may build the same image
+	if err := os.Rename(output, image); err != nil {
+		return "", err
+	}
+
+	return image, nil
+}
+
+func fileDigest(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// guestProtectionStatus returns the guest protection of the VM.
+func (q *qemu) guestProtectionStatus() (GuestProtectionStatus, error) {
+	status := GuestProtectionStatus{
+		Confidential: q.config.ConfidentialGuest,
+		Technology:   q.arch.guestProtection().String(),
+	}
+
+	if q.state.SEBootImage != "" {
+		digest, err := fileDigest(q.state.SEBootImage)
+		if err != nil {
+			return GuestProtectionStatus{}, err
+		}
+
+		status.HostKeyDocuments = q.config.SEHostKeyDocuments
+		status.BootImage = q.state.SEBootImage
+		status.BootImageDigest = digest
+	}
+
+	return status, nil
+}
+
+// GetGuestProtectionStatus returns the guest protection of the sandbox VM.
+func (s *Sandbox) GetGuestProtectionStatus() (GuestProtectionStatus, error) {
+	q, ok := s.hypervisor.(*qemu)
+	if !ok {
+		return GuestProtectionStatus{
+			Confidential: s.config.HypervisorConfig.ConfidentialGuest,
+			Technology:   noneProtection.String(),
+		}, nil
+	}
+
+	return q.guestProtectionStatus()
+}
@@ -0,0 +1,119 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mockGenprotimg(dir string) (*[]string, func()) {
+	savedDir := seImageDir
+	seImageDir = dir
+
+	commands, restore := mockHostCommands(func(name string, args ...string) (string, error) {
+		for i, arg := range args {
+			if arg == "-o" {
+				return "", ioutil.WriteFile(args[i+1], []byte("se image"), 0600)
+			}
+		}
+		return "", nil
+	})
+
+	return commands, func() {
+		seImageDir = savedDir
+		restore()
+	}
+}
+
+func TestCreateSEBootImage(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "secure-execution")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	commands, restore := mockGenprotimg(filepath.Join(dir, "images"))
+	defer restore()
+
+	kernel := filepath.Join(dir, "vmlinuz")
+	initrd := filepath.Join(dir, "initrd")
+	hkd := filepath.Join(dir, "hkd.crt")
+	for _, path := range []string{kernel, initrd, hkd} {
+		assert.NoError(ioutil.WriteFile(path, []byte(path), 0600))
+	}
+
+	image, err := createSEBootImage(kernel, initrd, "console=ttysclp0", []string{hkd})
+	assert.NoError(err)
+	assert.FileExists(image)
+	assert.Len(*commands, 1)
+
+	// the image is reused
+	cached, err := createSEBootImage(kernel, initrd, "console=ttysclp0", []string{hkd})
+	assert.NoError(err)
+	assert.Equal(image, cached)
+	assert.Len(*commands, 1)
+
+	// unless the parameters change
+	other, err := createSEBootImage(kernel, initrd, "console=ttysclp0 quiet", []string{hkd})
+	assert.NoError(err)
+	assert.NotEqual(image, other)
+	assert.Len(*commands, 2)
+
+	digest, err := fileDigest(image)
+	assert.NoError(err)
+	assert.Contains(digest, "sha256:")
+
+	_, err = createSEBootImage(kernel, initrd, "", []string{filepath.Join(dir, "nonexistent")})
+	assert.Error(err)
+}
+
+func TestCheckSecureExecutionConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "secure-execution")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	hkd := filepath.Join(dir, "hkd.crt")
+	assert.NoError(ioutil.WriteFile(hkd, []byte("hkd"), 0600))
+
+	conf := HypervisorConfig{
+		ConfidentialGuest:  true,
+		IOMMUPlatform:      true,
+		InitrdPath:         "/initrd",
+		SEHostKeyDocuments: []string{hkd},
+	}
+	assert.NoError(CheckQemuArchConfig(conf, "s390x"))
+	assert.Error(CheckQemuArchConfig(conf, "amd64"))
+
+	conf.SEHostKeyDocuments = []string{filepath.Join(dir, "nonexistent")}
+	assert.Error(CheckQemuArchConfig(conf, "s390x"))
+
+	conf.SEHostKeyDocuments = []string{hkd}
+	conf.InitrdPath = ""
+	assert.Error(CheckQemuArchConfig(conf, "s390x"))
+
+	conf.InitrdPath = "/initrd"
+	conf.IOMMUPlatform = false
+	assert.Error(CheckQemuArchConfig(conf, "s390x"))
+
+	conf.IOMMUPlatform = true
+	conf.ConfidentialGuest = false
+	assert.Error(CheckQemuArchConfig(conf, "s390x"))
+}
+
+func TestGuestProtectionString(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("none", noneProtection.String())
+	assert.Equal("se", seProtection.String())
+	assert.Equal("sev", sevProtection.String())
+}
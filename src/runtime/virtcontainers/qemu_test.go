@@ -261,6 +261,42 @@ func TestQemuAddDeviceVhostUserBlk(t *testing.T) {
 	testQemuAddDevice(t, vDevice, vhostuserDev, expectedOut)
 }
 
+func TestQemuAddDeviceVhostUserNet(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "vhost-user")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	restore, err := mockHugePagesMemInfo(dir, 1024)
+	assert.NoError(err)
+	defer restore()
+
+	vDevice := config.VhostUserDeviceAttrs{
+		DevID:      "testDevID",
+		SocketPath: "/test/socket/path",
+		MacAddress: "02:00:ca:fe:00:48",
+		Type:       config.VhostUserNet,
+	}
+
+	q := &qemu{
+		ctx:  context.Background(),
+		arch: &qemuArchBase{},
+		config: HypervisorConfig{
+			MemorySize: 1024,
+		},
+	}
+
+	// the guest memory must be backed by hugepages
+	assert.Error(q.addDevice(context.Background(), vDevice, vhostuserDev))
+	assert.Empty(q.qemuConfig.Devices)
+
+	q.config.HugePages = true
+	assert.NoError(q.addDevice(context.Background(), vDevice, vhostuserDev))
+	assert.Len(q.qemuConfig.Devices, 1)
+	assert.True(q.qemuConfig.Knobs.MemShared)
+}
+
 func TestQemuAddDeviceSerialPortDev(t *testing.T) {
 	deviceID := "channelTest"
 	id := "charchTest"
@@ -347,6 +383,11 @@ func TestQemuCapabilities(t *testing.T) {
 
 	caps := q.capabilities(q.ctx)
 	assert.True(caps.IsBlockDeviceHotplugSupported())
+	assert.False(caps.IsMemoryHotUnplugSupported())
+
+	q.config.VirtioMem = true
+	caps = q.capabilities(q.ctx)
+	assert.True(caps.IsMemoryHotUnplugSupported())
 }
 
 func TestQemuQemuPath(t *testing.T) {
@@ -585,3 +626,34 @@ func TestQemuGetpids(t *testing.T) {
 	assert.True(pids[0] == 100)
 	assert.True(pids[1] == 200)
 }
+
+func TestQemuProcessInvocation(t *testing.T) {
+	assert := assert.New(t)
+
+	invocation, err := processInvocation(os.Getpid())
+	assert.NoError(err)
+	assert.Equal(os.Args[0], invocation.Path)
+	assert.Equal(os.Args[1:], invocation.Args)
+
+	// the invocation is persisted
+	assert.Equal(invocation, loadHypervisorInvocation(invocation.save()))
+
+	_, err = processInvocation(-1)
+	assert.Error(err)
+}
+
+func TestQemuCPUModel(t *testing.T) {
+	assert := assert.New(t)
+
+	q := &qemu{
+		arch: &qemuArchBase{},
+	}
+	assert.Equal("host,", q.cpuModel())
+
+	q.config.CPUFeatures = "pmu=off"
+	assert.Equal("host,pmu=off", q.cpuModel())
+
+	q.config.CPUModel = "Skylake-Server"
+	q.config.CPUFeatures = "avx512f,pmu=off"
+	assert.Equal("Skylake-Server,avx512f,pmu=off,enforce", q.cpuModel())
+}
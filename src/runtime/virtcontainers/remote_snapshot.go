@@ -0,0 +1,93 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/agent/protocols/grpc"
+)
+
+const (
+	// rootfs mount options of the remote snapshotters: the base64
+	// encoded mount info, or the path of a file holding it
+	remoteSnapshotOption     = "io.katacontainers.remote-snapshot="
+	remoteSnapshotFileOption = "io.katacontainers.remote-snapshot-file="
+
+	kataRemoteSnapshotDevType = "remote-snapshot"
+)
+
+// RemoteSnapshot is the mount info of a container rootfs provided by a
+// remote snapshotter (e.g. nydus or stargz): rather than mounting it on
+// the host, the agent mounts it in the guest, where the image is pulled
+// lazily. The guest image must provide the mount helpers of the file
+// system type.
+type RemoteSnapshot struct {
+	// FsType is the type of the file system, e.g. fuse.nydus.
+	FsType string `json:"fstype"`
+
+	// Source is the source of the mount, e.g. the image reference.
+	Source string `json:"source"`
+
+	// Options are passed to the mount helper.
+	Options []string `json:"options,omitempty"`
+}
+
+// ParseRemoteSnapshot returns the remote snapshot info of a container rootfs,
+// given by its mount options, nil if the rootfs is not provided by a remote
+// snapshotter. The info is only taken from the mount of the snapshotter, not
+// from the container annotations, as the mount is done in the guest with the
+// source and options of the info.
+func ParseRemoteSnapshot(options []string) (*RemoteSnapshot, error) {
+	var data []byte
+
+	for _, o := range options {
+		var err error
+
+		switch {
+		case strings.HasPrefix(o, remoteSnapshotOption):
+			data, err = base64.StdEncoding.DecodeString(strings.TrimPrefix(o, remoteSnapshotOption))
+		case strings.HasPrefix(o, remoteSnapshotFileOption):
+			data, err = ioutil.ReadFile(strings.TrimPrefix(o, remoteSnapshotFileOption))
+		default:
+			continue
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("invalid remote snapshot mount option %q: %v", o, err)
+		}
+	}
+
+	if data == nil {
+		return nil, nil
+	}
+
+	var s RemoteSnapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("invalid remote snapshot mount info: %v", err)
+	}
+
+	if s.FsType == "" || s.Source == "" {
+		return nil, fmt.Errorf("remote snapshot mount info requires a file system type and a source")
+	}
+
+	return &s, nil
+}
+
+// storage returns the storage the agent mounts on mountPoint.
+func (s *RemoteSnapshot) storage(mountPoint string) *grpc.Storage {
+	return &grpc.Storage{
+		Driver:     kataRemoteSnapshotDevType,
+		Source:     s.Source,
+		Fstype:     s.FsType,
+		Options:    s.Options,
+		MountPoint: mountPoint,
+	}
+}
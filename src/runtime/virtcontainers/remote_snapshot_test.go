@@ -0,0 +1,62 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRemoteSnapshot(t *testing.T) {
+	assert := assert.New(t)
+
+	const info = `{"fstype": "fuse.nydus", "source": "busybox", "options": ["ro"]}`
+	expected := &RemoteSnapshot{FsType: "fuse.nydus", Source: "busybox", Options: []string{"ro"}}
+
+	// not a remote snapshot
+	s, err := ParseRemoteSnapshot([]string{"rw"})
+	assert.NoError(err)
+	assert.Nil(s)
+
+	// mount option
+	s, err = ParseRemoteSnapshot([]string{"rw", remoteSnapshotOption + base64.StdEncoding.EncodeToString([]byte(info))})
+	assert.NoError(err)
+	assert.Equal(expected, s)
+
+	_, err = ParseRemoteSnapshot([]string{remoteSnapshotOption + "!"})
+	assert.Error(err)
+
+	// mount info file
+	dir, err := ioutil.TempDir("", "remote-snapshot")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "mount-info.json")
+	assert.NoError(ioutil.WriteFile(file, []byte(info), 0600))
+
+	s, err = ParseRemoteSnapshot([]string{remoteSnapshotFileOption + file})
+	assert.NoError(err)
+	assert.Equal(expected, s)
+
+	_, err = ParseRemoteSnapshot([]string{remoteSnapshotFileOption + filepath.Join(dir, "nonexistent")})
+	assert.Error(err)
+
+	// incomplete mount info
+	_, err = ParseRemoteSnapshot([]string{remoteSnapshotOption + base64.StdEncoding.EncodeToString([]byte(`{"fstype": "fuse.nydus"}`))})
+	assert.Error(err)
+
+	storage := s.storage("/run/kata-containers/shared/containers/foo")
+	assert.Equal(kataRemoteSnapshotDevType, storage.Driver)
+	assert.Equal("fuse.nydus", storage.Fstype)
+	assert.Equal("busybox", storage.Source)
+	assert.Equal([]string{"ro"}, storage.Options)
+	assert.Equal("/run/kata-containers/shared/containers/foo", storage.MountPoint)
+}
@@ -14,9 +14,11 @@ import (
 	"math"
 	"net"
 	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 	"syscall"
+	"time"
 
 	"github.com/containerd/cgroups"
 	"github.com/containernetworking/plugins/pkg/ns"
@@ -79,10 +81,12 @@ type SandboxStatus struct {
 	Annotations map[string]string
 }
 
-// SandboxStats describes a sandbox's stats
+// SandboxStats describes a sandbox's stats, as seen from the host:
+// they account for the whole VM, not only for its containers.
 type SandboxStats struct {
-	CgroupStats CgroupStats
-	Cpus        int
+	CgroupStats  CgroupStats
+	NetworkStats []*NetworkStats
+	Cpus         int
 }
 
 // SandboxConfig is a Sandbox configuration.
@@ -113,23 +117,80 @@ type SandboxConfig struct {
 
 	ShmSize uint64
 
-	// SharePidNs sets all containers to share the same sandbox level pid namespace.
+	// SharePidNs sets all containers to share the same sandbox level pid
+	// namespace. The namespace is held by an init process managed by the
+	// agent, so that it outlives the containers of the sandbox.
 	SharePidNs bool
 
+	// ContainerParallelism is the maximum number of containers created or
+	// started at the same time, with the sandbox or through CreateContainer,
+	// they are handled one by one when lower than 2.
+	ContainerParallelism uint32
+
 	// SystemdCgroup enables systemd cgroup support
 	SystemdCgroup bool
 
 	// SandboxCgroupOnly enables cgroup only at podlevel in the host
 	SandboxCgroupOnly bool
 
+	// CPUQuotaPolicy tells where the CPU quota of the containers is enforced
+	CPUQuotaPolicy CPUQuotaPolicy
+
 	DisableGuestSeccomp bool
 
+	// GuestOCIHooks runs the createContainer and startContainer OCI hooks
+	// of the containers in their namespaces in the guest, and their
+	// poststop hooks in the guest rather than on the host.
+	GuestOCIHooks bool
+
 	// SandboxBindMounts - list of paths to mount into guest
 	SandboxBindMounts []string
 
 	// Experimental features enabled
 	Experimental []exp.Feature
 
+	// AuditConfig configures the audit log of the privileged operations
+	AuditConfig AuditConfig
+
+	// EphemeralDiskConfig configures the disk backing the local volumes
+	EphemeralDiskConfig EphemeralDiskConfig
+
+	// RootfsDedup shares the rootfs block devices across the sandboxes,
+	// with a copy on write snapshot per container
+	RootfsDedup bool
+
+	// RootfsDedupDir is the host directory of the copy on write
+	// snapshots, on a disk rather than in the tmpfs of the run storage
+	RootfsDedupDir string
+
+	// DeviceReuseTimeout is how long the devices released by a stopped
+	// container are kept attached to the VM, so that a restarting container
+	// reuses them instead of hotplugging them again. Zero disables it.
+	DeviceReuseTimeout time.Duration
+
+	// DevicePluginClasses are the classes of the device plugins the
+	// containers can ask for devices, none when empty
+	DevicePluginClasses []string
+
+	// InitDataConfig configures the sandbox metadata passed to the guest
+	// at boot
+	InitDataConfig InitDataConfig
+
+	// AdmissionConfig configures the check of the host free memory
+	// before launching the VM
+	AdmissionConfig AdmissionConfig
+
+	// GuestSwapConfig configures the swap device of the guest
+	GuestSwapConfig GuestSwapConfig
+
+	// SharedMemoryConfig configures the memory regions shared with the
+	// other sandboxes of the host
+	SharedMemoryConfig SharedMemoryConfig
+
+	// DryRun resolves the sandbox plan without launching nor storing
+	// anything, the plan being returned by GetDryRunPlan.
+	DryRun bool
+
 	// Cgroups specifies specific cgroup settings for the various subsystems that the container is
 	// placed into to limit the resources the container has available
 	Cgroups *configs.Cgroup
@@ -194,6 +255,55 @@ type Sandbox struct {
 	ctx context.Context
 
 	cw *consoleWatcher
+
+	audit *auditLog
+
+	// ephemeralDisk is the storage of the ephemeral disk, mounted by
+	// the agent when the sandbox is started.
+	ephemeralDisk     *grpc.Storage
+	ephemeralDiskPath string
+
+	// guestSwap is the storage of the swap device, enabled by the agent
+	// when the sandbox is started.
+	guestSwap *grpc.Storage
+
+	// networkPolicy is the nftables ruleset enforced by the agent,
+	// see SetNetworkPolicy.
+	networkPolicy     string
+	networkPolicyLock sync.Mutex
+
+	// agentLogLevel and debugConsole are the log level of the agent and
+	// the state of the debug console once changed at runtime, see
+	// SetAgentLogLevel and SetDebugConsole.
+	agentLogLevel  string
+	debugConsole   *bool
+	agentDebugLock sync.Mutex
+
+	// sharedMemory are the open files backing the shared memory
+	// regions, by region name, see setupSharedMemory.
+	sharedMemory map[string]*os.File
+
+	// plan is the plan of a sandbox created in dry run mode
+	plan *SandboxPlan
+
+	// bootPhases and events are reported in the exit report of the
+	// sandbox, written when it stops
+	bootPhases map[string]float64
+	events     []SandboxEvent
+	exitReport *ExitReport
+	reportLock sync.Mutex
+
+	// agentCallSlots bounds the requests made in parallel to the agent, nil
+	// when the containers are handled one by one, see agentCall.
+	agentCallSlots chan struct{}
+
+	// storageMetricsUpdating is set while UpdateStorageMetrics walks the
+	// storage of the sandbox
+	storageMetricsUpdating int32
+
+	// sandboxMetrics are created on the first use, see metrics.
+	sandboxMetrics *sandboxMetrics
+	metricsOnce    sync.Once
 }
 
 // ID returns the sandbox identifier string.
@@ -459,6 +569,13 @@ func createSandbox(ctx context.Context, sandboxConfig SandboxConfig, factory Fac
 		s.Logger().WithField("features", s.config.Experimental).Infof("Enable experimental features")
 	}
 
+	if s.config.DryRun {
+		if err := s.dryRun(ctx); err != nil {
+			return nil, err
+		}
+		return s, nil
+	}
+
 	// Sandbox state has been loaded from storage.
 	// If the Stae is not empty, this is a re-creation, i.e.
 	// we don't need to talk to the guest's agent, but only
@@ -472,6 +589,14 @@ func createSandbox(ctx context.Context, sandboxConfig SandboxConfig, factory Fac
 		return nil, err
 	}
 
+	if err := s.setupInitData(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := s.setupSharedMemory(ctx); err != nil {
+		return nil, err
+	}
+
 	// Set sandbox state
 	if err := s.setSandboxState(types.StateReady); err != nil {
 		return nil, err
@@ -515,12 +640,48 @@ func newSandbox(ctx context.Context, sandboxConfig SandboxConfig, factory Factor
 		ctx:             ctx,
 	}
 
+	if sandboxConfig.ContainerParallelism > 1 {
+		s.agentCallSlots = make(chan struct{}, sandboxConfig.ContainerParallelism)
+	}
+
 	hypervisor.setSandbox(s)
 
 	if s.store, err = persist.GetDriver(); err != nil || s.store == nil {
 		return nil, fmt.Errorf("failed to get fs persist driver: %v", err)
 	}
 
+	s.audit = newAuditLog(s.id, s.store.RunStoragePath(), sandboxConfig.AuditConfig)
+
+	if err = sandboxConfig.EphemeralDiskConfig.Validate(); err != nil {
+		return nil, err
+	}
+
+	if sandboxConfig.RootfsDedup && sandboxConfig.RootfsDedupDir == "" {
+		return nil, fmt.Errorf("rootfs deduplication requires a directory for the snapshots")
+	}
+
+	if err = sandboxConfig.InitDataConfig.Validate(sandboxConfig.HypervisorType); err != nil {
+		return nil, err
+	}
+
+	if err = sandboxConfig.AdmissionConfig.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err = sandboxConfig.GuestSwapConfig.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err = sandboxConfig.SharedMemoryConfig.Validate(sandboxConfig.HypervisorType); err != nil {
+		return nil, err
+	}
+
+	// the constrained and no constraints cgroups of the VMM are only
+	// supported on cgroup v1 hosts
+	if !sandboxConfig.SandboxCgroupOnly && !rootless.IsRootless() && cgroups.Mode() == cgroups.Unified {
+		return nil, fmt.Errorf("sandbox_cgroup_only must be enabled on cgroup v2 hosts")
+	}
+
 	defer func() {
 		if retErr != nil {
 			s.Logger().WithError(retErr).Error("Create new sandbox failed")
@@ -533,6 +694,15 @@ func newSandbox(ctx context.Context, sandboxConfig SandboxConfig, factory Factor
 		sandboxConfig.HypervisorConfig.SELinuxProcessLabel = spec.Process.SelinuxLabel
 	}
 
+	if err = initSELinuxLabels(&sandboxConfig.HypervisorConfig); err != nil {
+		return nil, err
+	}
+	defer func() {
+		if retErr != nil {
+			releaseSELinuxLabels(&sandboxConfig.HypervisorConfig)
+		}
+	}()
+
 	s.devManager = deviceManager.NewDeviceManager(sandboxConfig.HypervisorConfig.BlockDeviceDriver,
 		sandboxConfig.HypervisorConfig.EnableVhostUserStore,
 		sandboxConfig.HypervisorConfig.VhostUserStorePath, nil)
@@ -542,11 +712,33 @@ func newSandbox(ctx context.Context, sandboxConfig SandboxConfig, factory Factor
 		s.Logger().WithError(err).Debug("restore sandbox failed")
 	}
 
+	// The hugepages of the pod are backed by host hugepages, the VM
+	// being created with them. A VM from the factory has none.
+	if s.state.State == "" && factory == nil && sandboxConfig.HypervisorType == QemuHypervisor &&
+		spec != nil && spec.Linux != nil && spec.Linux.CgroupsPath != "" {
+		if sandboxConfig.HypervisorConfig.GuestHugepages, err = podHugepages(spec.Linux.CgroupsPath); err != nil {
+			return nil, fmt.Errorf("Could not get the pod hugepages: %v", err)
+		}
+	}
+
+	// The agent only reads the initdata the kernel parameters name.
+	if s.state.State == "" && s.initDataPassed() {
+		if err = sandboxConfig.HypervisorConfig.AddKernelParam(sandboxConfig.InitDataConfig.kernelParam()); err != nil {
+			return nil, err
+		}
+	}
+
 	// store doesn't require hypervisor to be stored immediately
 	if err = s.hypervisor.createSandbox(ctx, s.id, s.networkNS, &sandboxConfig.HypervisorConfig); err != nil {
 		return nil, err
 	}
 
+	if nodes := len(sandboxConfig.HypervisorConfig.NUMANodes); nodes > 1 {
+		if caps := s.hypervisor.capabilities(ctx); !caps.IsGuestNUMASupported() {
+			return nil, fmt.Errorf("guest NUMA topology of %d nodes not supported by the hypervisor", nodes)
+		}
+	}
+
 	if s.disableVMShutdown, err = s.agent.init(ctx, s, sandboxConfig.AgentConfig); err != nil {
 		return nil, err
 	}
@@ -607,6 +799,7 @@ func (s *Sandbox) createCgroupManager() error {
 			CgroupPaths: s.state.CgroupPaths,
 			Resources:   resources,
 			CgroupPath:  cgroupPath,
+			Systemd:     s.config.SystemdCgroup,
 		},
 	); err != nil {
 		return err
@@ -671,11 +864,24 @@ func (s *Sandbox) removeContainer(containerID string) error {
 			containerID, s.id)
 	}
 
+	s.Lock()
 	delete(s.containers, containerID)
+	s.Unlock()
 
 	return nil
 }
 
+// removeContainerConfig removes the config of a container from the sandbox
+// config, based on a container ID.
+func (s *Sandbox) removeContainerConfig(containerID string) {
+	for idx, contConfig := range s.config.Containers {
+		if contConfig.ID == containerID {
+			s.config.Containers = append(s.config.Containers[:idx], s.config.Containers[idx+1:]...)
+			return
+		}
+	}
+}
+
 // Delete deletes an already created sandbox.
 // The VM in which the sandbox is running will be shut down.
 func (s *Sandbox) Delete(ctx context.Context) error {
@@ -707,6 +913,27 @@ func (s *Sandbox) Delete(ctx context.Context) error {
 
 	s.agent.cleanup(ctx, s)
 
+	if err := removeEphemeralDisk(s.config.EphemeralDiskConfig, s.id); err != nil {
+		s.Logger().WithError(err).Error("failed to remove ephemeral disk")
+	}
+
+	if err := removeGuestSwap(s.config.GuestSwapConfig, s.id); err != nil {
+		s.Logger().WithError(err).Error("failed to remove guest swap")
+	}
+
+	// the snapshots are removed along with the containers
+	if s.config.RootfsDedup {
+		if err := os.Remove(filepath.Join(s.config.RootfsDedupDir, s.id)); err != nil && !os.IsNotExist(err) {
+			s.Logger().WithError(err).Error("failed to remove rootfs snapshots directory")
+		}
+	}
+
+	if err := s.releaseSharedMemory(); err != nil {
+		s.Logger().WithError(err).Error("failed to release shared memory")
+	}
+
+	releaseSELinuxLabels(&s.config.HypervisorConfig)
+
 	return s.store.Destroy(s.id)
 }
 
@@ -750,6 +977,11 @@ func (s *Sandbox) createNetwork(ctx context.Context) error {
 		return nil
 	}
 
+	// netmon would scan the network namespace for the interface changes
+	if s.config.NetworkConfig.StaticConfig != nil && s.config.NetworkConfig.NetmonConfig.Enable {
+		return fmt.Errorf("the static network configuration conflicts with netmon")
+	}
+
 	span, ctx := katatrace.Trace(ctx, s.Logger(), "createNetwork", s.tracingTags())
 	defer span.End()
 
@@ -830,7 +1062,15 @@ func (s *Sandbox) generateNetInfo(inf *pbTypes.Interface) (NetworkInfo, error) {
 }
 
 // AddInterface adds new nic to the sandbox.
-func (s *Sandbox) AddInterface(ctx context.Context, inf *pbTypes.Interface) (*pbTypes.Interface, error) {
+func (s *Sandbox) AddInterface(ctx context.Context, inf *pbTypes.Interface) (_ *pbTypes.Interface, err error) {
+	defer func() {
+		s.audit.record(ctx, AuditNetworkUpdate, map[string]interface{}{
+			"action":    "add_interface",
+			"interface": inf.Name,
+			"hwaddr":    inf.HwAddr,
+		}, err)
+	}()
+
 	netInfo, err := s.generateNetInfo(inf)
 	if err != nil {
 		return nil, err
@@ -861,7 +1101,15 @@ func (s *Sandbox) AddInterface(ctx context.Context, inf *pbTypes.Interface) (*pb
 }
 
 // RemoveInterface removes a nic of the sandbox.
-func (s *Sandbox) RemoveInterface(ctx context.Context, inf *pbTypes.Interface) (*pbTypes.Interface, error) {
+func (s *Sandbox) RemoveInterface(ctx context.Context, inf *pbTypes.Interface) (_ *pbTypes.Interface, err error) {
+	defer func() {
+		s.audit.record(ctx, AuditNetworkUpdate, map[string]interface{}{
+			"action":    "remove_interface",
+			"interface": inf.Name,
+			"hwaddr":    inf.HwAddr,
+		}, err)
+	}()
+
 	for i, endpoint := range s.networkNS.Endpoints {
 		if endpoint.HardwareAddr() == inf.HwAddr {
 			s.Logger().WithField("endpoint-type", endpoint.Type()).Info("Hot detaching endpoint")
@@ -887,7 +1135,18 @@ func (s *Sandbox) ListInterfaces(ctx context.Context) ([]*pbTypes.Interface, err
 
 // UpdateRoutes updates the sandbox route table (e.g. for portmapping support).
 func (s *Sandbox) UpdateRoutes(ctx context.Context, routes []*pbTypes.Route) ([]*pbTypes.Route, error) {
-	return s.agent.updateRoutes(ctx, routes)
+	updated, err := s.agent.updateRoutes(ctx, routes)
+
+	var dests []string
+	for _, r := range routes {
+		dests = append(dests, r.Dest)
+	}
+	s.audit.record(ctx, AuditNetworkUpdate, map[string]interface{}{
+		"action": "update_routes",
+		"routes": dests,
+	}, err)
+
+	return updated, err
 }
 
 // ListRoutes lists all routes and their configurations in the sandbox.
@@ -998,6 +1257,14 @@ func (s *Sandbox) startVM(ctx context.Context) (err error) {
 	defer span.End()
 
 	s.Logger().Info("Starting VM")
+	start := time.Now()
+
+	// the VMs of the factory are already running
+	if s.factory == nil {
+		if err := s.checkAdmission(); err != nil {
+			return err
+		}
+	}
 
 	if s.config.HypervisorConfig.Debug {
 		// create console watcher
@@ -1051,6 +1318,11 @@ func (s *Sandbox) startVM(ctx context.Context) (err error) {
 	}
 
 	s.Logger().Info("VM started")
+	s.recordBootPhase(bootPhaseVMStart, start)
+
+	if err := s.setVMMPriority(ctx); err != nil {
+		return err
+	}
 
 	if s.cw != nil {
 		s.Logger().Debug("console watcher starts")
@@ -1060,15 +1332,25 @@ func (s *Sandbox) startVM(ctx context.Context) (err error) {
 		}
 	}
 
+	if err := s.setupEphemeralDisk(ctx); err != nil {
+		return err
+	}
+
+	if err := s.setupGuestSwap(ctx); err != nil {
+		return err
+	}
+
 	// Once the hypervisor is done starting the sandbox,
 	// we want to guarantee that it is manageable.
 	// For that we need to ask the agent to start the
 	// sandbox inside the VM.
+	start = time.Now()
 	if err := s.agent.startSandbox(ctx, s); err != nil {
 		return err
 	}
 
 	s.Logger().Info("Agent started in the sandbox")
+	s.recordBootPhase(bootPhaseAgentStart, start)
 
 	return nil
 }
@@ -1092,7 +1374,12 @@ func (s *Sandbox) addContainer(c *Container) error {
 	if _, ok := s.containers[c.id]; ok {
 		return fmt.Errorf("Duplicated container: %s", c.id)
 	}
+
+	// the lock only guards the readers not serialized with the
+	// changes of the containers, e.g. the storage metrics
+	s.Lock()
 	s.containers[c.id] = c
+	s.Unlock()
 
 	return nil
 }
@@ -1101,32 +1388,43 @@ func (s *Sandbox) addContainer(c *Container) error {
 // This should be called only when the sandbox is already created.
 // It will add new container config to sandbox.config.Containers
 func (s *Sandbox) CreateContainer(ctx context.Context, contConfig ContainerConfig) (VCContainer, error) {
-	// Update sandbox config to include the new container's config
-	s.config.Containers = append(s.config.Containers, contConfig)
-
 	var err error
 
+	// Create the container object, add devices to the sandbox's device-manager.
+	// The sandbox config is updated once the container is created, the
+	// caller lock being released meanwhile when the creation runs in
+	// parallel with other ones, see agentCall.
+	c, err := newContainer(ctx, s, &contConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	// Release the devices of the device plugins on failure, once the
+	// rollback below has detached them.
 	defer func() {
 		if err != nil {
-			if len(s.config.Containers) > 0 {
-				// delete container config
-				s.config.Containers = s.config.Containers[:len(s.config.Containers)-1]
+			if err := c.releasePluginDevices(ctx); err != nil {
+				c.Logger().WithError(err).Warn("failed to release the plugin devices")
 			}
 		}
 	}()
 
-	// Create the container object, add devices to the sandbox's device-manager:
-	c, err := newContainer(ctx, s, &s.config.Containers[len(s.config.Containers)-1])
-	if err != nil {
-		return nil, err
-	}
-
 	// create and start the container
 	err = c.create(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	// Update sandbox config to include the new container's config
+	s.config.Containers = append(s.config.Containers, *c.config)
+	c.config = &s.config.Containers[len(s.config.Containers)-1]
+
+	defer func() {
+		if err != nil {
+			s.removeContainerConfig(c.id)
+		}
+	}()
+
 	// Add the container to the containers list in the sandbox.
 	if err = s.addContainer(c); err != nil {
 		return nil, err
@@ -1254,12 +1552,7 @@ func (s *Sandbox) DeleteContainer(ctx context.Context, containerID string) (VCCo
 	}
 
 	// Update sandbox config
-	for idx, contConfig := range s.config.Containers {
-		if contConfig.ID == containerID {
-			s.config.Containers = append(s.config.Containers[:idx], s.config.Containers[idx+1:]...)
-			break
-		}
-	}
+	s.removeContainerConfig(containerID)
 
 	// update the sandbox cgroup
 	if err = s.cgroupsUpdate(ctx); err != nil {
@@ -1308,6 +1601,15 @@ func (s *Sandbox) EnterContainer(ctx context.Context, containerID string, cmd ty
 
 	// Enter it.
 	process, err := c.enter(ctx, cmd)
+	params := map[string]interface{}{
+		"container": containerID,
+		"args":      cmd.Args,
+		"user":      cmd.User,
+	}
+	if process != nil {
+		params["exec_id"] = process.Token
+	}
+	s.audit.record(ctx, AuditExec, params, err)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -1370,7 +1672,7 @@ func (s *Sandbox) Stats(ctx context.Context) (SandboxStats, error) {
 		path = cgroupNoConstraintsPath(s.state.CgroupPath)
 	}
 
-	cgroup, err := cgroupsLoadFunc(cgroupSubsystems, cgroups.StaticPath(path))
+	cgroup, err := cgroupsLoadFunc(cgroupSubsystems, staticCgroupPath(path))
 	if err != nil {
 		return SandboxStats{}, fmt.Errorf("Could not load sandbox cgroup in %v: %v", s.state.CgroupPath, err)
 	}
@@ -1382,8 +1684,41 @@ func (s *Sandbox) Stats(ctx context.Context) (SandboxStats, error) {
 
 	stats := SandboxStats{}
 
-	stats.CgroupStats.CPUStats.CPUUsage.TotalUsage = metrics.CPU.Usage.Total
-	stats.CgroupStats.MemoryStats.Usage.Usage = metrics.Memory.Usage.Usage
+	if metrics.CPU != nil && metrics.CPU.Usage != nil {
+		stats.CgroupStats.CPUStats.CPUUsage = CPUUsage{
+			TotalUsage:        metrics.CPU.Usage.Total,
+			PercpuUsage:       metrics.CPU.Usage.PerCPU,
+			UsageInKernelmode: metrics.CPU.Usage.Kernel,
+			UsageInUsermode:   metrics.CPU.Usage.User,
+		}
+	}
+	if metrics.Memory != nil {
+		stats.CgroupStats.MemoryStats.Cache = metrics.Memory.Cache
+		if metrics.Memory.Usage != nil {
+			stats.CgroupStats.MemoryStats.Usage = MemoryData{
+				Usage:    metrics.Memory.Usage.Usage,
+				MaxUsage: metrics.Memory.Usage.Max,
+				Failcnt:  metrics.Memory.Usage.Failcnt,
+				Limit:    metrics.Memory.Usage.Limit,
+			}
+		}
+	}
+	if metrics.Pids != nil {
+		stats.CgroupStats.PidsStats = PidsStats{
+			Current: metrics.Pids.Current,
+			Limit:   metrics.Pids.Limit,
+		}
+	}
+
+	if stats.CgroupStats.CPUStats.ThrottlingData, err = s.hostCPUThrottling(); err != nil {
+		s.Logger().WithError(err).Warn("Could not get the CPU throttling of the vCPUs")
+	}
+
+	stats.NetworkStats, err = s.networkStats()
+	if err != nil {
+		return stats, err
+	}
+
 	tids, err := s.hypervisor.getThreadIDs(ctx)
 	if err != nil {
 		return stats, err
@@ -1393,6 +1728,45 @@ func (s *Sandbox) Stats(ctx context.Context) (SandboxStats, error) {
 	return stats, nil
 }
 
+// networkStats returns the stats of the sandbox network interfaces, which
+// carry all the traffic of the VM.
+func (s *Sandbox) networkStats() ([]*NetworkStats, error) {
+	var stats []*NetworkStats
+
+	err := doNetNS(s.networkNS.NetNsPath, func(_ ns.NetNS) error {
+		for _, endpoint := range s.networkNS.Endpoints {
+			link, err := netlink.LinkByName(endpoint.Name())
+			if _, ok := err.(netlink.LinkNotFoundError); ok {
+				// passed through interfaces are not in the netns anymore
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("could not get link %s: %v", endpoint.Name(), err)
+			}
+
+			attrs := link.Attrs()
+			if attrs.Statistics == nil {
+				continue
+			}
+
+			stats = append(stats, &NetworkStats{
+				Name:      attrs.Name,
+				RxBytes:   attrs.Statistics.RxBytes,
+				RxPackets: attrs.Statistics.RxPackets,
+				RxErrors:  attrs.Statistics.RxErrors,
+				RxDropped: attrs.Statistics.RxDropped,
+				TxBytes:   attrs.Statistics.TxBytes,
+				TxPackets: attrs.Statistics.TxPackets,
+				TxErrors:  attrs.Statistics.TxErrors,
+				TxDropped: attrs.Statistics.TxDropped,
+			})
+		}
+		return nil
+	})
+
+	return stats, err
+}
+
 // PauseContainer pauses a running container.
 func (s *Sandbox) PauseContainer(ctx context.Context, containerID string) error {
 	// Fetch the container.
@@ -1437,8 +1811,11 @@ func (s *Sandbox) createContainers(ctx context.Context) error {
 	span, ctx := katatrace.Trace(ctx, s.Logger(), "createContainers", s.tracingTags())
 	defer span.End()
 
-	for i := range s.config.Containers {
+	isSandbox := func(i int) bool {
+		return s.config.Containers[i].isSandbox()
+	}
 
+	if err := s.runContainers(ctx, len(s.config.Containers), isSandbox, func(ctx context.Context, i int) error {
 		c, err := newContainer(ctx, s, &s.config.Containers[i])
 		if err != nil {
 			return err
@@ -1447,9 +1824,9 @@ func (s *Sandbox) createContainers(ctx context.Context) error {
 			return err
 		}
 
-		if err := s.addContainer(c); err != nil {
-			return err
-		}
+		return s.addContainer(c)
+	}); err != nil {
+		return err
 	}
 
 	// Update resources after having added containers to the sandbox, since
@@ -1487,10 +1864,20 @@ func (s *Sandbox) Start(ctx context.Context) error {
 			s.setSandboxState(prevState)
 		}
 	}()
+
+	containers := make([]*Container, 0, len(s.containers))
 	for _, c := range s.containers {
-		if startErr = c.start(ctx); startErr != nil {
-			return startErr
-		}
+		containers = append(containers, c)
+	}
+
+	isSandbox := func(i int) bool {
+		return containers[i].config.isSandbox()
+	}
+
+	if startErr = s.runContainers(ctx, len(containers), isSandbox, func(ctx context.Context, i int) error {
+		return containers[i].start(ctx)
+	}); startErr != nil {
+		return startErr
 	}
 
 	if err := s.storeSandbox(ctx); err != nil {
@@ -1502,6 +1889,92 @@ func (s *Sandbox) Start(ctx context.Context) error {
 	return nil
 }
 
+// runContainers calls fn for the n containers of the sandbox, the sandbox
+// container, which the others depend on, being handled before them. The
+// host side of the operations stays serialized, only the requests made to
+// the agent through agentCall run in parallel, up to ContainerParallelism
+// of them at a time.
+func (s *Sandbox) runContainers(ctx context.Context, n int, isSandbox func(i int) bool, fn func(ctx context.Context, i int) error) error {
+	if s.agentCallSlots == nil || n < 2 {
+		for i := 0; i < n; i++ {
+			if err := fn(ctx, i); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var others []int
+	for i := 0; i < n; i++ {
+		if !isSandbox(i) {
+			others = append(others, i)
+			continue
+		}
+		if err := fn(ctx, i); err != nil {
+			return err
+		}
+	}
+
+	var (
+		wg       sync.WaitGroup
+		lock     sync.Mutex
+		firstErr error
+	)
+	lockCtx := WithAgentCallLock(ctx, &lock)
+
+	for _, i := range others {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			lock.Lock()
+			defer lock.Unlock()
+
+			// do not go on once a container failed
+			if firstErr != nil {
+				return
+			}
+
+			if err := fn(lockCtx, i); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	return firstErr
+}
+
+type agentCallLockKey struct{}
+
+// WithAgentCallLock returns a context whose requests to the agent, made
+// through agentCall, release the lock l held by the caller while they are
+// processed, for the containers created in parallel.
+func WithAgentCallLock(ctx context.Context, l sync.Locker) context.Context {
+	return context.WithValue(ctx, agentCallLockKey{}, l)
+}
+
+// agentCall runs fn, which sends requests to the agent. When the context
+// carries the lock of the caller, see WithAgentCallLock, it is released
+// meanwhile so that the requests of several containers are processed at
+// the same time, up to ContainerParallelism of them.
+func (s *Sandbox) agentCall(ctx context.Context, fn func() error) error {
+	l, ok := ctx.Value(agentCallLockKey{}).(sync.Locker)
+	if !ok || s.agentCallSlots == nil || !s.agent.longLiveConn() {
+		return fn()
+	}
+
+	l.Unlock()
+	s.agentCallSlots <- struct{}{}
+	defer func() {
+		<-s.agentCallSlots
+		l.Lock()
+	}()
+
+	return fn()
+}
+
 // Stop stops a sandbox. The containers that are making the sandbox
 // will be destroyed.
 // When force is true, ignore guest related stop failures.
@@ -1524,6 +1997,24 @@ func (s *Sandbox) Stop(ctx context.Context, force bool) error {
 		}
 	}
 
+	// The devices kept attached for the restarting containers are not
+	// reused anymore.
+	if s.devManager != nil {
+		if err := s.devManager.DetachReleasedDevices(ctx, s, 0); err != nil && !force {
+			return err
+		}
+	}
+
+	// the processes and cgroups of the VM are still there
+	report := s.newExitReport()
+	s.reportLock.Lock()
+	s.exitReport = report
+	s.reportLock.Unlock()
+	if err := s.writeExitReport(report); err != nil {
+		s.Logger().WithError(err).Warn("Could not write the exit report")
+	}
+	s.Logger().WithField("report", report).Info("Sandbox exit report")
+
 	if err := s.stopVM(ctx); err != nil && !force {
 		return err
 	}
@@ -1616,10 +2107,14 @@ func (s *Sandbox) unsetSandboxBlockIndex(index int) error {
 
 // HotplugAddDevice is used for add a device to sandbox
 // Sandbox implement DeviceReceiver interface from device/api/interface.go
-func (s *Sandbox) HotplugAddDevice(ctx context.Context, device api.Device, devType config.DeviceType) error {
+func (s *Sandbox) HotplugAddDevice(ctx context.Context, device api.Device, devType config.DeviceType) (err error) {
 	span, ctx := katatrace.Trace(ctx, s.Logger(), "HotplugAddDevice", s.tracingTags())
 	defer span.End()
 
+	defer func() {
+		s.audit.record(ctx, AuditDeviceHotplug, deviceAuditParams(device, devType), err)
+	}()
+
 	if s.config.SandboxCgroupOnly {
 		// We are about to add a device to the hypervisor,
 		// the device cgroup MUST be updated since the hypervisor
@@ -1674,7 +2169,11 @@ func (s *Sandbox) HotplugAddDevice(ctx context.Context, device api.Device, devTy
 
 // HotplugRemoveDevice is used for removing a device from sandbox
 // Sandbox implement DeviceReceiver interface from device/api/interface.go
-func (s *Sandbox) HotplugRemoveDevice(ctx context.Context, device api.Device, devType config.DeviceType) error {
+func (s *Sandbox) HotplugRemoveDevice(ctx context.Context, device api.Device, devType config.DeviceType) (err error) {
+	defer func() {
+		s.audit.record(ctx, AuditDeviceHotunplug, deviceAuditParams(device, devType), err)
+	}()
+
 	defer func() {
 		if s.config.SandboxCgroupOnly {
 			// Remove device from cgroup, the hypervisor
@@ -1791,6 +2290,18 @@ func (s *Sandbox) AddDevice(ctx context.Context, info config.DeviceInfo) (api.De
 		}
 	}()
 
+	// The device is not used by any container, it is recorded for the
+	// device leaks check. A running sandbox is saved right away for the
+	// attachment to survive a crash, a sandbox being created is saved once
+	// created.
+	s.state.SandboxDevices = append(s.state.SandboxDevices, b.DeviceID())
+	if s.state.State == types.StateRunning {
+		if err = s.Save(); err != nil {
+			s.state.SandboxDevices = s.state.SandboxDevices[:len(s.state.SandboxDevices)-1]
+			return nil, err
+		}
+	}
+
 	return b, nil
 }
 
@@ -1835,6 +2346,11 @@ func (s *Sandbox) updateResources(ctx context.Context) error {
 		if err := s.agent.onlineCPUMem(ctx, vcpusAdded, true); err != nil {
 			return err
 		}
+
+		// the hotplugged vCPU threads are not realtime yet
+		if err := s.setVCPUsPriority(ctx); err != nil {
+			return err
+		}
 	}
 	s.Logger().Debugf("Sandbox CPUs: %d", newCPUs)
 
@@ -1874,6 +2390,15 @@ func (s *Sandbox) calculateSandboxMemory() int64 {
 		if m := c.Resources.Memory; m != nil && m.Limit != nil {
 			memorySandbox += *m.Limit
 		}
+
+		// hugepages are allocated from the VM memory, the pod ones
+		// being added with the VM
+		for _, l := range c.Resources.HugepageLimits {
+			if size, err := parseHugepageSize(l.Pagesize); err == nil && s.config.HypervisorConfig.GuestHugepages[size] != 0 {
+				continue
+			}
+			memorySandbox += int64(l.Limit)
+		}
 	}
 	return memorySandbox
 }
@@ -1945,7 +2470,7 @@ func (s *Sandbox) cgroupsUpdate(ctx context.Context) error {
 		return nil
 	}
 
-	cgroup, err := cgroupsLoadFunc(V1Constraints, cgroups.StaticPath(s.state.CgroupPath))
+	cgroup, err := cgroupsLoadFunc(V1Constraints, staticCgroupPath(s.state.CgroupPath))
 	if err != nil {
 		return fmt.Errorf("Could not load cgroup %v: %v", s.state.CgroupPath, err)
 	}
@@ -2157,6 +2682,7 @@ func (s *Sandbox) cpuResources() *specs.LinuxCPU {
 	}
 
 	cpu.Cpus = strings.Trim(cpu.Cpus, " \n\t,")
+	quota = s.config.CPUQuotaPolicy.hostQuota(quota, period)
 
 	return validCPUResources(cpu)
 }
@@ -2231,10 +2757,21 @@ func (s *Sandbox) GetOOMEvent(ctx context.Context) (string, error) {
 	return s.agent.getOOMEvent(ctx)
 }
 
+// GetMemoryEvent waits for a memory event of the containers of the sandbox.
+func (s *Sandbox) GetMemoryEvent(ctx context.Context) (MemoryEvent, error) {
+	return s.agent.getMemoryEvent(ctx)
+}
+
 func (s *Sandbox) GetAgentURL() (string, error) {
 	return s.agent.getAgentURL()
 }
 
+// GetAgentAllowedAPIs returns the requests served by the agent,
+// nil when all of them are served.
+func (s *Sandbox) GetAgentAllowedAPIs() []string {
+	return agentAllowedAPIs(s.config.AgentConfig)
+}
+
 // getSandboxCPUSet returns the union of each of the sandbox's containers' CPU sets'
 // cpus and mems as a string in canonical linux CPU/mems list format
 func (s *Sandbox) getSandboxCPUSet() (string, string, error) {
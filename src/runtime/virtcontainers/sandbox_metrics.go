@@ -7,127 +7,407 @@ package virtcontainers
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"syscall"
 
 	mutils "github.com/kata-containers/kata-containers/src/runtime/pkg/utils"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/device/config"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/pkg/agent/protocols/grpc"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/procfs"
+	"github.com/sirupsen/logrus"
 )
 
 const namespaceHypervisor = "kata_hypervisor"
 const namespaceKatashim = "kata_shim"
 const namespaceVirtiofsd = "kata_virtiofsd"
 
-var (
+// sandboxMetrics are the metrics of a sandbox. Each sandbox has its own
+// ones, so the sandboxes served by a shim group are not mixed up.
+type sandboxMetrics struct {
 	// hypervisor
-	hypervisorThreads = prometheus.NewGauge(prometheus.GaugeOpts{
-		Namespace: namespaceHypervisor,
-		Name:      "threads",
-		Help:      "Hypervisor process threads.",
-	})
-
-	hypervisorProcStatus = prometheus.NewGaugeVec(prometheus.GaugeOpts{
-		Namespace: namespaceHypervisor,
-		Name:      "proc_status",
-		Help:      "Hypervisor process status.",
-	},
-		[]string{"item"},
-	)
-
-	hypervisorProcStat = prometheus.NewGaugeVec(prometheus.GaugeOpts{
-		Namespace: namespaceHypervisor,
-		Name:      "proc_stat",
-		Help:      "Hypervisor process statistics.",
-	},
-		[]string{"item"},
-	)
-
-	hypervisorNetdev = prometheus.NewGaugeVec(prometheus.GaugeOpts{
-		Namespace: namespaceHypervisor,
-		Name:      "netdev",
-		Help:      "Net devices statistics.",
-	},
-		[]string{"interface", "item"},
-	)
-
-	hypervisorIOStat = prometheus.NewGaugeVec(prometheus.GaugeOpts{
-		Namespace: namespaceHypervisor,
-		Name:      "io_stat",
-		Help:      "Process IO statistics.",
-	},
-		[]string{"item"},
-	)
-
-	hypervisorOpenFDs = prometheus.NewGauge(prometheus.GaugeOpts{
-		Namespace: namespaceHypervisor,
-		Name:      "fds",
-		Help:      "Open FDs for hypervisor.",
-	})
+	hypervisorThreads    prometheus.Gauge
+	hypervisorProcStatus *prometheus.GaugeVec
+	hypervisorProcStat   *prometheus.GaugeVec
+	hypervisorNetdev     *prometheus.GaugeVec
+	hypervisorIOStat     *prometheus.GaugeVec
+	hypervisorOpenFDs    prometheus.Gauge
 
 	// agent
-	agentRPCDurationsHistogram = prometheus.NewHistogramVec(prometheus.HistogramOpts{
-		Namespace: namespaceKatashim,
-		Name:      "agent_rpc_durations_histogram_milliseconds",
-		Help:      "RPC latency distributions.",
-		Buckets:   prometheus.ExponentialBuckets(1, 2, 10),
-	},
-		[]string{"action"},
-	)
+	agentRPCDurationsHistogram *prometheus.HistogramVec
+	agentPolicyDenials         *prometheus.CounterVec
+	agentRPCs                  *prometheus.CounterVec
+	agentRPCBytes              *prometheus.CounterVec
+	agentRPCsInFlight          prometheus.Gauge
+	agentDialAttempts          prometheus.Gauge
+
+	// boot progress
+	bootPhaseDuration *prometheus.GaugeVec
 
 	// virtiofsd
-	virtiofsdThreads = prometheus.NewGauge(prometheus.GaugeOpts{
-		Namespace: namespaceVirtiofsd,
-		Name:      "threads",
-		Help:      "Virtiofsd process threads.",
-	})
+	virtiofsdThreads    prometheus.Gauge
+	virtiofsdProcStatus *prometheus.GaugeVec
+	virtiofsdProcStat   *prometheus.GaugeVec
+	virtiofsdIOStat     *prometheus.GaugeVec
+	virtiofsdOpenFDs    prometheus.Gauge
+
+	// sandbox components liveness
+	componentUp       *prometheus.GaugeVec
+	componentRestarts *prometheus.CounterVec
+
+	// components processes
+	componentThreads    *prometheus.GaugeVec
+	componentOpenFDs    *prometheus.GaugeVec
+	componentProcStatus *prometheus.GaugeVec
+	componentProcStat   *prometheus.GaugeVec
+	componentIOStat     *prometheus.GaugeVec
+
+	// sandbox bind mounts
+	sandboxBindMountFailures *prometheus.CounterVec
+
+	// sandbox storage
+	storageUsage *prometheus.GaugeVec
+}
 
-	virtiofsdProcStatus = prometheus.NewGaugeVec(prometheus.GaugeOpts{
-		Namespace: namespaceVirtiofsd,
-		Name:      "proc_status",
-		Help:      "Virtiofsd process status.",
-	},
-		[]string{"item"},
-	)
-
-	virtiofsdProcStat = prometheus.NewGaugeVec(prometheus.GaugeOpts{
-		Namespace: namespaceVirtiofsd,
-		Name:      "proc_stat",
-		Help:      "Virtiofsd process statistics.",
-	},
-		[]string{"item"},
-	)
-
-	virtiofsdIOStat = prometheus.NewGaugeVec(prometheus.GaugeOpts{
-		Namespace: namespaceVirtiofsd,
-		Name:      "io_stat",
-		Help:      "Process IO statistics.",
-	},
-		[]string{"item"},
-	)
-
-	virtiofsdOpenFDs = prometheus.NewGauge(prometheus.GaugeOpts{
-		Namespace: namespaceVirtiofsd,
-		Name:      "fds",
-		Help:      "Open FDs for virtiofsd.",
-	})
+// newSandboxMetrics creates the metrics of a sandbox.
+func newSandboxMetrics() *sandboxMetrics {
+	return &sandboxMetrics{
+		// hypervisor
+		hypervisorThreads: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespaceHypervisor,
+			Name:      "threads",
+			Help:      "Hypervisor process threads.",
+		}),
+		hypervisorProcStatus: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespaceHypervisor,
+			Name:      "proc_status",
+			Help:      "Hypervisor process status.",
+		},
+			[]string{"item"},
+		),
+		hypervisorProcStat: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespaceHypervisor,
+			Name:      "proc_stat",
+			Help:      "Hypervisor process statistics.",
+		},
+			[]string{"item"},
+		),
+		hypervisorNetdev: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespaceHypervisor,
+			Name:      "netdev",
+			Help:      "Net devices statistics.",
+		},
+			[]string{"interface", "item"},
+		),
+		hypervisorIOStat: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespaceHypervisor,
+			Name:      "io_stat",
+			Help:      "Process IO statistics.",
+		},
+			[]string{"item"},
+		),
+		hypervisorOpenFDs: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespaceHypervisor,
+			Name:      "fds",
+			Help:      "Open FDs for hypervisor.",
+		}),
+
+		// agent
+		agentRPCDurationsHistogram: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespaceKatashim,
+			Name:      "agent_rpc_durations_histogram_milliseconds",
+			Help:      "RPC latency distributions.",
+			Buckets:   prometheus.ExponentialBuckets(1, 2, 10),
+		},
+			[]string{"action"},
+		),
+		agentPolicyDenials: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespaceKatashim,
+			Name:      "agent_policy_denials_total",
+			Help:      "Agent requests denied by the agent policy.",
+		},
+			[]string{"request"},
+		),
+		agentRPCs: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespaceKatashim,
+			Name:      "agent_rpcs_total",
+			Help:      "RPCs sent on the agent connection.",
+		},
+			[]string{"method", "result"},
+		),
+		agentRPCBytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespaceKatashim,
+			Name:      "agent_rpc_bytes_total",
+			Help:      "Payload bytes of the RPCs on the agent connection.",
+		},
+			[]string{"method", "direction"},
+		),
+		agentRPCsInFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespaceKatashim,
+			Name:      "agent_rpcs_in_flight",
+			Help:      "RPCs multiplexed on the agent connection waiting for their response.",
+		}),
+		agentDialAttempts: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespaceKatashim,
+			Name:      "agent_dial_attempts",
+			Help:      "Failed attempts to dial the agent of the last connection.",
+		}),
+
+		// boot progress
+		bootPhaseDuration: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespaceKatashim,
+			Name:      "boot_phase_duration_seconds",
+			Help:      "Duration of the phases of the boot of the sandbox(seconds).",
+		},
+			[]string{"phase"},
+		),
+
+		// virtiofsd
+		virtiofsdThreads: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespaceVirtiofsd,
+			Name:      "threads",
+			Help:      "Virtiofsd process threads.",
+		}),
+		virtiofsdProcStatus: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespaceVirtiofsd,
+			Name:      "proc_status",
+			Help:      "Virtiofsd process status.",
+		},
+			[]string{"item"},
+		),
+		virtiofsdProcStat: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespaceVirtiofsd,
+			Name:      "proc_stat",
+			Help:      "Virtiofsd process statistics.",
+		},
+			[]string{"item"},
+		),
+		virtiofsdIOStat: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespaceVirtiofsd,
+			Name:      "io_stat",
+			Help:      "Process IO statistics.",
+		},
+			[]string{"item"},
+		),
+		virtiofsdOpenFDs: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespaceVirtiofsd,
+			Name:      "fds",
+			Help:      "Open FDs for virtiofsd.",
+		}),
+
+		// sandbox components liveness
+		componentUp: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespaceKatashim,
+			Name:      "component_up",
+			Help:      "Whether the sandbox component process is up(1) or down(0).",
+		},
+			[]string{"component", "name"},
+		),
+		componentRestarts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespaceKatashim,
+			Name:      "component_restarts_total",
+			Help:      "Restarts of the sandbox component process.",
+		},
+			[]string{"component", "name"},
+		),
+
+		// components processes
+		componentThreads: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespaceKatashim,
+			Name:      "component_threads",
+			Help:      "Sandbox component process threads.",
+		},
+			[]string{"component", "name"},
+		),
+		componentOpenFDs: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespaceKatashim,
+			Name:      "component_fds",
+			Help:      "Open FDs for the sandbox component process.",
+		},
+			[]string{"component", "name"},
+		),
+		componentProcStatus: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespaceKatashim,
+			Name:      "component_proc_status",
+			Help:      "Sandbox component process status.",
+		},
+			[]string{"component", "name", "item"},
+		),
+		componentProcStat: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespaceKatashim,
+			Name:      "component_proc_stat",
+			Help:      "Sandbox component process statistics.",
+		},
+			[]string{"component", "name", "item"},
+		),
+		componentIOStat: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespaceKatashim,
+			Name:      "component_io_stat",
+			Help:      "Sandbox component process IO statistics.",
+		},
+			[]string{"component", "name", "item"},
+		),
+
+		// sandbox bind mounts
+		sandboxBindMountFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespaceKatashim,
+			Name:      "sandbox_bind_mount_failures_total",
+			Help:      "Failures to setup or cleanup the sandbox bind mounts.",
+		},
+			[]string{"operation"},
+		),
+
+		// sandbox storage
+		storageUsage: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespaceKatashim,
+			Name:      "storage_usage_bytes",
+			Help:      "Host disk usage of the sandbox storage(bytes).",
+		},
+			[]string{"storage"},
+		),
+	}
+}
+
+const (
+	componentHypervisor = "hypervisor"
+	componentVirtiofsd  = "virtiofsd"
+	componentVhostUser  = "vhost_user"
+	// componentAuxiliary is a process of the hypervisor driver other than
+	// the VMM and virtiofsd
+	componentAuxiliary = "auxiliary"
 )
 
-func RegisterMetrics() {
-	// hypervisor
-	prometheus.MustRegister(hypervisorThreads)
-	prometheus.MustRegister(hypervisorProcStatus)
-	prometheus.MustRegister(hypervisorProcStat)
-	prometheus.MustRegister(hypervisorNetdev)
-	prometheus.MustRegister(hypervisorIOStat)
-	prometheus.MustRegister(hypervisorOpenFDs)
+const (
+	bootPhaseVMStart    = "vm_start"
+	bootPhaseAgentDial  = "agent_dial"
+	bootPhaseAgentStart = "agent_start"
+)
+
+const (
+	storageRootfsOverlay = "rootfs_overlay"
+	storageEphemeral     = "ephemeral"
+	storageSharedDir     = "shared_dir"
+)
+
+// sandboxComponent is a host process the sandbox relies on.
+type sandboxComponent struct {
+	component string
+	name      string
+	pid       int
+	// socketPath is checked instead of the pid for the processes
+	// which are not started by Kata, like vhost-user backends.
+	socketPath string
+}
+
+func (c sandboxComponent) alive() bool {
+	if c.socketPath != "" {
+		fi, err := os.Stat(c.socketPath)
+		return err == nil && fi.Mode()&os.ModeSocket != 0
+	}
+
+	if c.pid <= 0 {
+		return false
+	}
+
+	return syscall.Kill(c.pid, syscall.Signal(0)) == nil
+}
+
+// metricsRegisterer is implemented by the hypervisors exposing their own metrics.
+type metricsRegisterer interface {
+	registerMetrics(r prometheus.Registerer)
+}
+
+// metrics returns the metrics of the sandbox.
+func (s *Sandbox) metrics() *sandboxMetrics {
+	s.metricsOnce.Do(func() {
+		s.sandboxMetrics = newSandboxMetrics()
+	})
+	return s.sandboxMetrics
+}
+
+// RegisterMetrics registers the sandbox metrics always exposed by the shim:
+// the agent RPCs, the components liveness and the hypervisor specific metrics.
+func (s *Sandbox) RegisterMetrics(r prometheus.Registerer) {
+	m := s.metrics()
 	// agent
-	prometheus.MustRegister(agentRPCDurationsHistogram)
+	r.MustRegister(m.agentRPCDurationsHistogram)
+	r.MustRegister(m.agentPolicyDenials)
+	r.MustRegister(m.agentRPCs)
+	r.MustRegister(m.agentRPCBytes)
+	r.MustRegister(m.agentRPCsInFlight)
+	r.MustRegister(m.agentDialAttempts)
+	// boot progress
+	r.MustRegister(m.bootPhaseDuration)
+	// components liveness
+	r.MustRegister(m.componentUp)
+	r.MustRegister(m.componentRestarts)
+	// sandbox bind mounts
+	r.MustRegister(m.sandboxBindMountFailures)
+	// hypervisor specific
+	if h, ok := s.hypervisor.(metricsRegisterer); ok {
+		h.registerMetrics(r)
+	}
+}
+
+// agentRPCMetrics accounts the RPCs and their payload bytes on the agent
+// connection, to find the methods saturating the vsock, e.g. the exec
+// or log streams.
+type agentRPCMetrics struct {
+	agent *kataAgent
+}
+
+func (o agentRPCMetrics) Started(method string, sent int) {
+	m := o.agent.metrics()
+	m.agentRPCsInFlight.Inc()
+	m.agentRPCBytes.WithLabelValues(method, "sent").Add(float64(sent))
+}
+
+func (o agentRPCMetrics) Finished(method string, received int, err error) {
+	m := o.agent.metrics()
+	m.agentRPCsInFlight.Dec()
+	m.agentRPCBytes.WithLabelValues(method, "received").Add(float64(received))
+
+	result := "ok"
+	if err != nil {
+		result = "error"
+	}
+	m.agentRPCs.WithLabelValues(method, result).Inc()
+}
+
+// RegisterProcessMetrics registers the metrics of the hypervisor,
+// virtiofsd and the other component processes, updated by
+// UpdateRuntimeMetrics.
+func (s *Sandbox) RegisterProcessMetrics(r prometheus.Registerer) {
+	m := s.metrics()
+	// hypervisor
+	r.MustRegister(m.hypervisorThreads)
+	r.MustRegister(m.hypervisorProcStatus)
+	r.MustRegister(m.hypervisorProcStat)
+	r.MustRegister(m.hypervisorNetdev)
+	r.MustRegister(m.hypervisorIOStat)
+	r.MustRegister(m.hypervisorOpenFDs)
 	// virtiofsd
-	prometheus.MustRegister(virtiofsdThreads)
-	prometheus.MustRegister(virtiofsdProcStatus)
-	prometheus.MustRegister(virtiofsdProcStat)
-	prometheus.MustRegister(virtiofsdIOStat)
-	prometheus.MustRegister(virtiofsdOpenFDs)
+	r.MustRegister(m.virtiofsdThreads)
+	r.MustRegister(m.virtiofsdProcStatus)
+	r.MustRegister(m.virtiofsdProcStat)
+	r.MustRegister(m.virtiofsdIOStat)
+	r.MustRegister(m.virtiofsdOpenFDs)
+	// all the components
+	r.MustRegister(m.componentThreads)
+	r.MustRegister(m.componentOpenFDs)
+	r.MustRegister(m.componentProcStatus)
+	r.MustRegister(m.componentProcStat)
+	r.MustRegister(m.componentIOStat)
+}
+
+// RegisterStorageMetrics registers the sandbox storage metrics,
+// updated by UpdateStorageMetrics.
+func (s *Sandbox) RegisterStorageMetrics(r prometheus.Registerer) {
+	r.MustRegister(s.metrics().storageUsage)
 }
 
 // UpdateRuntimeMetrics update shim/hypervisor's metrics
@@ -139,38 +419,22 @@ func (s *Sandbox) UpdateRuntimeMetrics() error {
 
 	hypervisorPid := pids[0]
 
-	proc, err := procfs.NewProc(hypervisorPid)
+	collector, err := mutils.HostProcCollector()
 	if err != nil {
 		return err
 	}
 
-	// process FDs
-	if fds, err := proc.FileDescriptorsLen(); err == nil {
-		hypervisorOpenFDs.Set(float64(fds))
-	}
-
-	// process net device statistics
-	if netdev, err := proc.NetDev(); err == nil {
-		// netdev: map[string]NetDevLine
-		for _, v := range netdev {
-			mutils.SetGaugeVecNetDev(hypervisorNetdev, v)
-		}
-	}
-
-	// process statistics
-	if procStat, err := proc.Stat(); err == nil {
-		hypervisorThreads.Set(float64(procStat.NumThreads))
-		mutils.SetGaugeVecProcStat(hypervisorProcStat, procStat)
-	}
-
-	// process status
-	if procStatus, err := proc.NewStatus(); err == nil {
-		mutils.SetGaugeVecProcStatus(hypervisorProcStatus, procStatus)
-	}
-
-	// process IO statistics
-	if ioStat, err := proc.IO(); err == nil {
-		mutils.SetGaugeVecProcIO(hypervisorIOStat, ioStat)
+	m := s.metrics()
+	err = collector.Collect(hypervisorPid, mutils.ProcMetrics{
+		OpenFDs:    m.hypervisorOpenFDs,
+		Threads:    m.hypervisorThreads,
+		NetDev:     m.hypervisorNetdev,
+		ProcStat:   m.hypervisorProcStat,
+		ProcStatus: m.hypervisorProcStatus,
+		ProcIO:     m.hypervisorIOStat,
+	})
+	if err != nil {
+		return err
 	}
 
 	// virtiofs metrics
@@ -179,9 +443,40 @@ func (s *Sandbox) UpdateRuntimeMetrics() error {
 		return err
 	}
 
+	s.updateComponentsMetrics(collector)
+
 	return nil
 }
 
+// updateComponentsMetrics collects the proc metrics of all the component
+// processes, labeled with their component, so the CPU and memory of the
+// shared-fs daemons and the auxiliary processes of the hypervisor are
+// visible next to the VMM ones.
+func (s *Sandbox) updateComponentsMetrics(collector *mutils.ProcCollector) {
+	m := s.metrics()
+	for _, c := range s.components() {
+		if c.pid <= 0 {
+			continue
+		}
+
+		labels := prometheus.Labels{"component": c.component, "name": c.name}
+		err := collector.Collect(c.pid, mutils.ProcMetrics{
+			OpenFDs:    m.componentOpenFDs.With(labels),
+			Threads:    m.componentThreads.With(labels),
+			ProcStat:   m.componentProcStat.MustCurryWith(labels),
+			ProcStatus: m.componentProcStatus.MustCurryWith(labels),
+			ProcIO:     m.componentIOStat.MustCurryWith(labels),
+		})
+		if err != nil {
+			// the component is reported down by component_up
+			s.Logger().WithError(err).WithFields(logrus.Fields{
+				"component": c.component,
+				"name":      c.name,
+			}).Debug("failed to collect the component process metrics")
+		}
+	}
+}
+
 func (s *Sandbox) UpdateVirtiofsdMetrics() error {
 	vfsPid := s.hypervisor.getVirtioFsPid()
 	if vfsPid == nil {
@@ -189,37 +484,219 @@ func (s *Sandbox) UpdateVirtiofsdMetrics() error {
 		return nil
 	}
 
-	proc, err := procfs.NewProc(*vfsPid)
+	collector, err := mutils.HostProcCollector()
 	if err != nil {
 		return err
 	}
 
-	// process FDs
-	if fds, err := proc.FileDescriptorsLen(); err == nil {
-		virtiofsdOpenFDs.Set(float64(fds))
+	m := s.metrics()
+	return collector.Collect(*vfsPid, mutils.ProcMetrics{
+		OpenFDs:    m.virtiofsdOpenFDs,
+		Threads:    m.virtiofsdThreads,
+		ProcStat:   m.virtiofsdProcStat,
+		ProcStatus: m.virtiofsdProcStatus,
+		ProcIO:     m.virtiofsdIOStat,
+	})
+}
+
+// components returns the host processes the sandbox relies on:
+// the hypervisor, virtiofsd, the auxiliary processes of the hypervisor
+// driver and the vhost-user backends.
+func (s *Sandbox) components() []sandboxComponent {
+	hypervisorPid := 0
+	pids := s.hypervisor.getPids()
+	if len(pids) > 0 {
+		hypervisorPid = pids[0]
+	}
+
+	components := []sandboxComponent{
+		{
+			component: componentHypervisor,
+			name:      string(s.config.HypervisorType),
+			pid:       hypervisorPid,
+		},
+	}
+
+	vfsPid := 0
+	if s.config.HypervisorConfig.SharedFS == config.VirtioFS {
+		if pid := s.hypervisor.getVirtioFsPid(); pid != nil {
+			vfsPid = *pid
+			components = append(components, sandboxComponent{
+				component: componentVirtiofsd,
+				name:      componentVirtiofsd,
+				pid:       vfsPid,
+			})
+		}
+	}
+
+	// the other processes of the hypervisor driver, named after their
+	// position so they keep their name when restarted
+	for i := 1; i < len(pids); i++ {
+		if pids[i] <= 0 || pids[i] == vfsPid {
+			continue
+		}
+		components = append(components, sandboxComponent{
+			component: componentAuxiliary,
+			name:      fmt.Sprintf("%s-%d", s.config.HypervisorType, i),
+			pid:       pids[i],
+		})
+	}
+
+	if s.devManager == nil {
+		return components
+	}
+
+	for _, d := range s.devManager.GetAllDevices() {
+		vAttr, ok := d.GetDeviceInfo().(*config.VhostUserDeviceAttrs)
+		if !ok || vAttr == nil {
+			continue
+		}
+		components = append(components, sandboxComponent{
+			component:  componentVhostUser,
+			name:       vAttr.DevID,
+			socketPath: vAttr.SocketPath,
+		})
+	}
+
+	return components
+}
+
+// UpdateStorageMetrics updates the host disk usage of the sandbox storage:
+// the containers rootfs overlay upper dirs, the host backed emptyDir volumes
+// and the shared dir exposed to the guest.
+// Walking the directories may take a while, so callers should not block on
+// it: the metrics keep the values of the last update, and an update is a
+// no-op while another one is running.
+func (s *Sandbox) UpdateStorageMetrics() error {
+	if !atomic.CompareAndSwapInt32(&s.storageMetricsUpdating, 0, 1) {
+		return nil
 	}
+	defer atomic.StoreInt32(&s.storageMetricsUpdating, 0)
+
+	rootfsDirs, ephemeralDirs := s.storageDirs()
 
-	// process statistics
-	if procStat, err := proc.Stat(); err == nil {
-		virtiofsdThreads.Set(float64(procStat.NumThreads))
-		mutils.SetGaugeVecProcStat(virtiofsdProcStat, procStat)
+	mounts, err := procfs.GetMounts()
+	if err != nil {
+		return err
 	}
 
-	// process status
-	if procStatus, err := proc.NewStatus(); err == nil {
-		mutils.SetGaugeVecProcStatus(virtiofsdProcStatus, procStatus)
+	// the shared dir is mostly made of bind mounts of the rootfs and
+	// volumes, do not account them twice.
+	mountPoints := make(map[string]bool, len(mounts))
+	for _, m := range mounts {
+		mountPoints[m.MountPoint] = true
 	}
 
-	// process IO statistics
-	if ioStat, err := proc.IO(); err == nil {
-		mutils.SetGaugeVecProcIO(virtiofsdIOStat, ioStat)
+	var rootfs, ephemeral int64
+	for _, dir := range rootfsDirs {
+		rootfs += diskUsage(dir, mountPoints)
 	}
+	for _, dir := range ephemeralDirs {
+		ephemeral += diskUsage(dir, mountPoints)
+	}
+
+	m := s.metrics()
+	m.storageUsage.WithLabelValues(storageRootfsOverlay).Set(float64(rootfs))
+	m.storageUsage.WithLabelValues(storageEphemeral).Set(float64(ephemeral))
+	m.storageUsage.WithLabelValues(storageSharedDir).Set(float64(diskUsage(getMountPath(s.id), mountPoints)))
 
 	return nil
 }
 
+// storageDirs returns the rootfs overlay upper dirs and the host backed
+// emptyDir volumes of the containers, walked without the sandbox lock.
+func (s *Sandbox) storageDirs() (rootfs []string, ephemeral []string) {
+	s.Lock()
+	defer s.Unlock()
+
+	for _, c := range s.containers {
+		if upperDir := overlayUpperDir(c.rootFs); upperDir != "" {
+			rootfs = append(rootfs, upperDir)
+		}
+
+		for _, m := range c.mounts {
+			if Isk8sHostEmptyDir(m.Source) {
+				ephemeral = append(ephemeral, m.Source)
+			}
+		}
+	}
+
+	return rootfs, ephemeral
+}
+
+// overlayUpperDir returns the upper dir of an overlay rootfs,
+// or an empty string if the rootfs is not an overlay.
+func overlayUpperDir(rootFs RootFs) string {
+	if rootFs.Type != "overlay" {
+		return ""
+	}
+
+	for _, opt := range rootFs.Options {
+		if strings.HasPrefix(opt, "upperdir=") {
+			return strings.TrimPrefix(opt, "upperdir=")
+		}
+	}
+
+	return ""
+}
+
+// diskUsage returns the disk space allocated to the files under root,
+// skipping the mount points found under it.
+func diskUsage(root string, mountPoints map[string]bool) int64 {
+	var usage int64
+
+	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			// files may vanish while walking, just skip them
+			return nil
+		}
+
+		if path != root && info.IsDir() && mountPoints[path] {
+			return filepath.SkipDir
+		}
+
+		if st, ok := info.Sys().(*syscall.Stat_t); ok {
+			// st_blocks is always expressed in 512-byte units
+			usage += st.Blocks * 512
+		}
+
+		return nil
+	})
+
+	return usage
+}
+
+// ParseMetricsGroups returns the space-trimmed names of groups of metrics,
+// the empty names are ignored and the others must be known groups.
+func ParseMetricsGroups(groups, known []string) ([]string, error) {
+	var parsed []string
+	for _, group := range groups {
+		group = strings.TrimSpace(group)
+		if group == "" {
+			continue
+		}
+
+		found := false
+		for _, k := range known {
+			if group == k {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown metrics group %q, expected one of %s", group, strings.Join(known, ", "))
+		}
+
+		parsed = append(parsed, group)
+	}
+
+	return parsed, nil
+}
+
 func (s *Sandbox) GetAgentMetrics(ctx context.Context) (string, error) {
-	r, err := s.agent.getAgentMetrics(ctx, &grpc.GetMetricsRequest{})
+	r, err := s.agent.getAgentMetrics(ctx, &grpc.GetMetricsRequest{
+		Groups: s.config.AgentConfig.MetricsGroups,
+	})
 	if err != nil {
 		return "", err
 	}
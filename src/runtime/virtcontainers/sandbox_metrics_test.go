@@ -0,0 +1,186 @@
+// Copyright (c) 2021 Kata Contributors
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	mutils "github.com/kata-containers/kata-containers/src/runtime/pkg/utils"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOverlayUpperDir(t *testing.T) {
+	assert := assert.New(t)
+
+	rootFs := RootFs{
+		Type:    "overlay",
+		Options: []string{"lowerdir=/lower", "upperdir=/upper", "workdir=/work"},
+	}
+	assert.Equal("/upper", overlayUpperDir(rootFs))
+
+	rootFs.Options = []string{"lowerdir=/lower"}
+	assert.Empty(overlayUpperDir(rootFs))
+
+	rootFs = RootFs{
+		Type:    "ext4",
+		Options: []string{"upperdir=/upper"},
+	}
+	assert.Empty(overlayUpperDir(rootFs))
+}
+
+func TestDiskUsage(t *testing.T) {
+	assert := assert.New(t)
+
+	root, err := ioutil.TempDir("", "disk-usage")
+	assert.NoError(err)
+	defer os.RemoveAll(root)
+
+	data := make([]byte, 64*1024)
+	assert.NoError(ioutil.WriteFile(filepath.Join(root, "file"), data, 0644))
+
+	mnt := filepath.Join(root, "mnt")
+	assert.NoError(os.Mkdir(mnt, 0755))
+	assert.NoError(ioutil.WriteFile(filepath.Join(mnt, "file"), data, 0644))
+
+	all := diskUsage(root, map[string]bool{})
+	skipped := diskUsage(root, map[string]bool{mnt: true})
+
+	assert.True(skipped > 0)
+	assert.True(all > skipped)
+
+	assert.Zero(diskUsage(filepath.Join(root, "missing"), map[string]bool{}))
+}
+
+func TestParseMetricsGroups(t *testing.T) {
+	assert := assert.New(t)
+
+	groups, err := ParseMetricsGroups([]string{" proc", "meminfo ", ""}, AgentMetricsGroups)
+	assert.NoError(err)
+	assert.Equal([]string{"proc", "meminfo"}, groups)
+
+	groups, err = ParseMetricsGroups(nil, AgentMetricsGroups)
+	assert.NoError(err)
+	assert.Empty(groups)
+
+	_, err = ParseMetricsGroups([]string{"proc", "memory"}, AgentMetricsGroups)
+	assert.Error(err)
+}
+
+func TestAgentRPCMetrics(t *testing.T) {
+	assert := assert.New(t)
+
+	s := &Sandbox{}
+	k := &kataAgent{sandboxMetrics: s.metrics()}
+	metrics := s.metrics()
+
+	m := agentRPCMetrics{agent: k}
+	m.Started("ReadStdout", 10)
+	assert.Equal(1.0, gaugeValue(metrics.agentRPCsInFlight))
+
+	m.Finished("ReadStdout", 4096, errors.New("connection closed"))
+	assert.Equal(0.0, gaugeValue(metrics.agentRPCsInFlight))
+	assert.Equal(10.0, counterValue(metrics.agentRPCBytes.WithLabelValues("ReadStdout", "sent")))
+	assert.Equal(4096.0, counterValue(metrics.agentRPCBytes.WithLabelValues("ReadStdout", "received")))
+	assert.Equal(1.0, counterValue(metrics.agentRPCs.WithLabelValues("ReadStdout", "error")))
+
+	// the RPCs of a reused agent are accounted to the new sandbox
+	other := &Sandbox{}
+	(&kataAgent{sandboxMetrics: other.metrics()}).reuseAgent(k)
+	m.Started("ReadStdout", 10)
+	assert.Equal(0.0, gaugeValue(metrics.agentRPCsInFlight))
+	assert.Equal(1.0, gaugeValue(other.metrics().agentRPCsInFlight))
+}
+
+func TestSandboxMetricsRegistry(t *testing.T) {
+	assert := assert.New(t)
+
+	// each sandbox has its own metrics, they can be registered in a
+	// registry per sandbox and are not mixed up
+	s1 := &Sandbox{}
+	s2 := &Sandbox{}
+	for _, s := range []*Sandbox{s1, s2} {
+		r := prometheus.NewRegistry()
+		assert.NotPanics(func() {
+			s.RegisterMetrics(r)
+			s.RegisterProcessMetrics(r)
+			s.RegisterStorageMetrics(r)
+		})
+	}
+
+	s1.metrics().agentDialAttempts.Set(3)
+	assert.Equal(3.0, gaugeValue(s1.metrics().agentDialAttempts))
+	assert.Zero(gaugeValue(s2.metrics().agentDialAttempts))
+}
+
+func TestUpdateComponentsMetrics(t *testing.T) {
+	contID := "505"
+	contConfig := newTestContainerConfigNoop(contID)
+	hConfig := newHypervisorConfig(nil, nil)
+	assert := assert.New(t)
+
+	s, err := testCreateSandbox(t, testSandboxID, MockHypervisor, hConfig, NetworkConfig{}, []ContainerConfig{contConfig}, nil)
+	assert.NoError(err)
+	defer cleanUp()
+
+	h := s.hypervisor.(*mockHypervisor)
+	h.mockPid = os.Getpid()
+	h.mockAuxPids = []int{os.Getpid(), 0}
+
+	components := s.components()
+	assert.Len(components, 2)
+	assert.Equal(componentAuxiliary, components[1].component)
+	auxName := string(MockHypervisor) + "-1"
+	assert.Equal(auxName, components[1].name)
+
+	collector, err := mutils.HostProcCollector()
+	assert.NoError(err)
+	s.updateComponentsMetrics(collector)
+
+	for _, labels := range [][]string{
+		{componentHypervisor, string(MockHypervisor)},
+		{componentAuxiliary, auxName},
+	} {
+		assert.NotZero(gaugeValue(s.metrics().componentThreads.WithLabelValues(labels...)), labels)
+		assert.NotZero(gaugeValue(s.metrics().componentOpenFDs.WithLabelValues(labels...)), labels)
+		assert.NotZero(gaugeValue(s.metrics().componentProcStatus.WithLabelValues(append(labels, "vmrss")...)), labels)
+	}
+}
+
+func TestUpdateStorageMetricsSingleFlight(t *testing.T) {
+	assert := assert.New(t)
+
+	upper, err := ioutil.TempDir("", "storage-metrics")
+	assert.NoError(err)
+	defer os.RemoveAll(upper)
+	assert.NoError(ioutil.WriteFile(filepath.Join(upper, "file"), make([]byte, 64*1024), 0644))
+
+	s := &Sandbox{
+		id: "sid",
+		containers: map[string]*Container{
+			"c": {rootFs: RootFs{Type: "overlay", Options: []string{"upperdir=" + upper}}},
+		},
+	}
+
+	rootfs, ephemeral := s.storageDirs()
+	assert.Equal([]string{upper}, rootfs)
+	assert.Empty(ephemeral)
+
+	// an update is running, the metrics are not updated again
+	s.metrics().storageUsage.WithLabelValues(storageRootfsOverlay).Set(0)
+	s.storageMetricsUpdating = 1
+	assert.NoError(s.UpdateStorageMetrics())
+	assert.Zero(gaugeValue(s.metrics().storageUsage.WithLabelValues(storageRootfsOverlay)))
+
+	s.storageMetricsUpdating = 0
+	assert.NoError(s.UpdateStorageMetrics())
+	assert.True(gaugeValue(s.metrics().storageUsage.WithLabelValues(storageRootfsOverlay)) > 0)
+	assert.Zero(s.storageMetricsUpdating)
+}
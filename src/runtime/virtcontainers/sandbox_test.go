@@ -17,6 +17,7 @@ import (
 	"sync"
 	"syscall"
 	"testing"
+	"time"
 
 	ktu "github.com/kata-containers/kata-containers/src/runtime/pkg/katatestutils"
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/device/config"
@@ -106,6 +107,27 @@ func TestCreateMockSandbox(t *testing.T) {
 	defer cleanUp()
 }
 
+func TestCreateMockSandboxNUMANodes(t *testing.T) {
+	assert := assert.New(t)
+	defer cleanUp()
+
+	hConfig := newHypervisorConfig(nil, nil)
+	hConfig.NumVCPUs = 2
+	hConfig.MemorySize = 2048
+	hConfig.NUMANodes = []NUMANode{
+		{VCPUs: []uint32{0}, MemorySize: 1024},
+		{VCPUs: []uint32{1}, MemorySize: 1024},
+	}
+
+	// the mock hypervisor has a single NUMA node
+	_, err := testCreateSandbox(t, testSandboxID, MockHypervisor, hConfig, NetworkConfig{}, nil, nil)
+	assert.Error(err)
+
+	hConfig.NUMANodes = []NUMANode{{VCPUs: []uint32{0, 1}, MemorySize: 2048}}
+	_, err = testCreateSandbox(t, testSandboxID, MockHypervisor, hConfig, NetworkConfig{}, nil, nil)
+	assert.NoError(err)
+}
+
 func TestCalculateSandboxCPUs(t *testing.T) {
 	sandbox := &Sandbox{}
 	sandbox.config = &SandboxConfig{}
@@ -152,6 +174,9 @@ func TestCalculateSandboxMem(t *testing.T) {
 	constrained := newTestContainerConfigNoop("cont-00001")
 	limit := int64(4000)
 	constrained.Resources.Memory = &specs.LinuxMemory{Limit: &limit}
+	hugepages := newTestContainerConfigNoop("cont-00001")
+	hugepages.Resources.Memory = &specs.LinuxMemory{Limit: &limit}
+	hugepages.Resources.HugepageLimits = []specs.LinuxHugepageLimit{{Pagesize: "2MB", Limit: 2 << 20}}
 
 	tests := []struct {
 		name       string
@@ -164,6 +189,7 @@ func TestCalculateSandboxMem(t *testing.T) {
 		{"2-constrained", []ContainerConfig{constrained, constrained}, limit * 2},
 		{"3-mix-constraints", []ContainerConfig{unconstrained, constrained, constrained}, limit * 2},
 		{"3-constrained", []ContainerConfig{constrained, constrained, constrained}, limit * 3},
+		{"1-hugepages", []ContainerConfig{hugepages}, limit + 2<<20},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -172,6 +198,11 @@ func TestCalculateSandboxMem(t *testing.T) {
 			assert.Equal(t, got, tt.want)
 		})
 	}
+
+	// the pod hugepages are added with the VM
+	sandbox.config.HypervisorConfig.GuestHugepages = map[uint64]uint64{2 << 20: 2 << 20}
+	sandbox.config.Containers = []ContainerConfig{hugepages}
+	assert.Equal(t, limit, sandbox.calculateSandboxMemory())
 }
 
 func TestCreateSandboxEmptyID(t *testing.T) {
@@ -923,6 +954,140 @@ func TestStartContainer(t *testing.T) {
 	assert.Nil(t, err, "Start container failed: %v", err)
 }
 
+func TestStartContainerDeferredCreation(t *testing.T) {
+	assert := assert.New(t)
+
+	hConfig := newHypervisorConfig(nil, nil)
+	hConfig.LazyBlockDeviceAttach = true
+	s, err := testCreateSandbox(t, testSandboxID, MockHypervisor, hConfig, NetworkConfig{}, nil, nil)
+	assert.NoError(err)
+	defer cleanUp()
+
+	assert.NoError(s.Start(context.Background()))
+
+	contID := "999"
+	_, err = s.CreateContainer(context.Background(), newTestContainerConfigNoop(contID))
+	assert.NoError(err)
+
+	c, err := s.findContainer(contID)
+	assert.NoError(err)
+
+	// the mock agent doesn't support block devices
+	assert.False(c.deferCreation(context.Background()))
+	assert.False(c.state.CreationDeferred)
+
+	c.state.CreationDeferred = true
+	limit := int64(1024)
+	c.pendingResources = &specs.LinuxResources{Memory: &specs.LinuxMemory{Limit: &limit}}
+
+	_, err = s.StartContainer(context.Background(), contID)
+	assert.NoError(err)
+	assert.False(c.state.CreationDeferred)
+	assert.Nil(c.pendingResources)
+	assert.Equal(types.StateRunning, c.state.State)
+
+	_, err = s.StopContainer(context.Background(), contID, false)
+	assert.NoError(err)
+	assert.False(c.state.CreationDeferred)
+
+	// a container whose creation is deferred is stopped without the agent
+	c.state.CreationDeferred = true
+	c.state.State = types.StateReady
+	_, err = s.StopContainer(context.Background(), contID, false)
+	assert.NoError(err)
+	assert.Equal(types.StateStopped, c.state.State)
+}
+
+func TestRunContainers(t *testing.T) {
+	assert := assert.New(t)
+
+	s := &Sandbox{
+		config: &SandboxConfig{},
+		agent:  &kataAgent{keepConn: true},
+	}
+	ctx := context.Background()
+
+	const n = 6
+	isSandbox := func(i int) bool {
+		return i == n-1
+	}
+
+	var (
+		mu                   sync.Mutex
+		order                []int
+		running, maxParallel int
+	)
+	call := func(ctx context.Context) error {
+		return s.agentCall(ctx, func() error {
+			mu.Lock()
+			running++
+			if running > maxParallel {
+				maxParallel = running
+			}
+			mu.Unlock()
+
+			time.Sleep(10 * time.Millisecond)
+
+			mu.Lock()
+			running--
+			mu.Unlock()
+			return nil
+		})
+	}
+	run := func(ctx context.Context, i int) error {
+		mu.Lock()
+		order = append(order, i)
+		mu.Unlock()
+
+		return call(ctx)
+	}
+
+	// one by one, in order
+	assert.NoError(s.runContainers(ctx, n, isSandbox, run))
+	assert.Equal([]int{0, 1, 2, 3, 4, 5}, order)
+	assert.Equal(1, maxParallel)
+
+	// the sandbox container first, then the others in parallel
+	order = nil
+	s.agentCallSlots = make(chan struct{}, 3)
+	assert.NoError(s.runContainers(ctx, n, isSandbox, run))
+	assert.Len(order, n)
+	assert.Equal(n-1, order[0])
+	assert.True(maxParallel > 1)
+	assert.True(maxParallel <= 3)
+
+	// the containers created one at a time by the caller, which holds its
+	// lock but for the requests to the agent
+	maxParallel = 0
+	var (
+		callerLock sync.Mutex
+		wg         sync.WaitGroup
+	)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			callerLock.Lock()
+			defer callerLock.Unlock()
+
+			assert.NoError(call(WithAgentCallLock(ctx, &callerLock)))
+		}()
+	}
+	wg.Wait()
+	assert.True(maxParallel > 1)
+	assert.True(maxParallel <= 3)
+
+	// the first error is returned
+	err := s.runContainers(ctx, n, isSandbox, func(ctx context.Context, i int) error {
+		if i == 2 {
+			return fmt.Errorf("container %d failed", i)
+		}
+		return nil
+	})
+	assert.EqualError(err, "container 2 failed")
+}
+
 func TestStatusContainer(t *testing.T) {
 	s, err := testCreateSandbox(t, testSandboxID, MockHypervisor, newHypervisorConfig(nil, nil), NetworkConfig{}, nil, nil)
 	assert.Nil(t, err, "VirtContainers should not allow empty sandboxes")
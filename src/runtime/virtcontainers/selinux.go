@@ -0,0 +1,102 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"fmt"
+
+	"github.com/opencontainers/selinux/go-selinux"
+	"github.com/opencontainers/selinux/go-selinux/label"
+)
+
+// initSELinuxLabels computes the SELinux labels of the hypervisor process
+// and of the sandbox files, svirt style: both share an MCS category pair
+// unique to the VM, so that the hypervisors cannot access each other's
+// files.
+func initSELinuxLabels(conf *HypervisorConfig) error {
+	if !conf.SELinuxCategories || !selinux.GetEnabled() {
+		return nil
+	}
+
+	// the labels of the kvm containers of the policy, with a new
+	// category pair reserved for this process
+	kvmProcessLabel, kvmFileLabel := selinux.KVMContainerLabels()
+
+	processLabel, fileLabel, err := svirtLabels(conf.SELinuxProcessLabel, kvmProcessLabel, kvmFileLabel)
+	if err != nil {
+		return err
+	}
+
+	if processLabel != kvmProcessLabel {
+		selinux.ReleaseLabel(kvmProcessLabel)
+		selinux.ReserveLabel(processLabel)
+	}
+
+	conf.SELinuxProcessLabel = processLabel
+	conf.SELinuxFileLabel = fileLabel
+
+	return nil
+}
+
+// releaseSELinuxLabels releases the category pair reserved for the VM by
+// initSELinuxLabels.
+func releaseSELinuxLabels(conf *HypervisorConfig) {
+	if !conf.SELinuxCategories || conf.SELinuxProcessLabel == "" {
+		return
+	}
+
+	selinux.ReleaseLabel(conf.SELinuxProcessLabel)
+}
+
+// svirtLabels returns the hypervisor process label, given the level of
+// the kvm process label unless it has one, and the file label of the
+// sandbox, the kvm file label with the level of the process label.
+func svirtLabels(processLabel, kvmProcessLabel, kvmFileLabel string) (string, string, error) {
+	if processLabel == "" {
+		processLabel = kvmProcessLabel
+	}
+	if processLabel == "" {
+		return "", "", fmt.Errorf("no SELinux label for the hypervisor process")
+	}
+
+	process, err := selinux.NewContext(processLabel)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid SELinux label %q: %v", processLabel, err)
+	}
+
+	kvmProcess, err := selinux.NewContext(kvmProcessLabel)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid SELinux label %q: %v", kvmProcessLabel, err)
+	}
+
+	if process["level"] == "" && kvmProcess["level"] != "" {
+		process["level"] = kvmProcess["level"]
+	}
+
+	if kvmFileLabel == "" {
+		return process.Get(), "", nil
+	}
+
+	file, err := selinux.NewContext(kvmFileLabel)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid SELinux label %q: %v", kvmFileLabel, err)
+	}
+	if process["level"] != "" {
+		file["level"] = process["level"]
+	}
+
+	return process.Get(), file.Get(), nil
+}
+
+// labelSandboxPath gives the sandbox file label to path,
+// for the hypervisor to be able to access it.
+func labelSandboxPath(conf *HypervisorConfig, path string) error {
+	if conf.SELinuxFileLabel == "" {
+		return nil
+	}
+
+	return label.SetFileLabel(path, conf.SELinuxFileLabel)
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSvirtLabels(t *testing.T) {
+	assert := assert.New(t)
+
+	const (
+		kvmProcess = "system_u:system_r:container_kvm_t:s0:c1,c2"
+		kvmFile    = "system_u:object_r:container_file_t:s0"
+	)
+
+	// the kvm labels of the policy
+	process, file, err := svirtLabels("", kvmProcess, kvmFile)
+	assert.NoError(err)
+	assert.Equal(kvmProcess, process)
+	assert.Equal("system_u:object_r:container_file_t:s0:c1,c2", file)
+
+	// a label without level is given the reserved categories
+	process, file, err = svirtLabels("system_u:system_r:svirt_t", kvmProcess, kvmFile)
+	assert.NoError(err)
+	assert.Equal("system_u:system_r:svirt_t:s0:c1,c2", process)
+	assert.Equal("system_u:object_r:container_file_t:s0:c1,c2", file)
+
+	// the level of the label is kept, e.g. the sandbox label of the container manager
+	process, file, err = svirtLabels("system_u:system_r:container_t:s0:c5,c6", kvmProcess, kvmFile)
+	assert.NoError(err)
+	assert.Equal("system_u:system_r:container_t:s0:c5,c6", process)
+	assert.Equal("system_u:object_r:container_file_t:s0:c5,c6", file)
+
+	// no file label in the policy
+	process, file, err = svirtLabels("", kvmProcess, "")
+	assert.NoError(err)
+	assert.Equal(kvmProcess, process)
+	assert.Empty(file)
+
+	_, _, err = svirtLabels("", "", kvmFile)
+	assert.Error(err)
+
+	_, _, err = svirtLabels("invalid", kvmProcess, kvmFile)
+	assert.Error(err)
+}
+
+func TestInitSELinuxLabelsDisabled(t *testing.T) {
+	assert := assert.New(t)
+
+	conf := &HypervisorConfig{SELinuxProcessLabel: "system_u:system_r:svirt_t"}
+	assert.NoError(initSELinuxLabels(conf))
+	assert.Equal("system_u:system_r:svirt_t", conf.SELinuxProcessLabel)
+	assert.Empty(conf.SELinuxFileLabel)
+
+	// no file label, nothing to do
+	assert.NoError(labelSandboxPath(conf, "/nonexistent"))
+}
@@ -0,0 +1,296 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/katautils/featuregate"
+	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/types"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// SharedMemoryFeatureGate enables the memory regions shared between
+	// the VMs of the sandboxes of a host.
+	SharedMemoryFeatureGate = "SharedMemoryChannel"
+
+	// maximum number of shared memory regions of a sandbox
+	maxSharedMemoryRegions = 8
+
+	// the lock file serializing the setup and release of the regions
+	sharedMemoryLockFile = ".lock"
+)
+
+var (
+	sharedMemoryGateErr error
+
+	// sharedMemoryDir is the host directory of the files backing the
+	// shared memory regions, a tmpfs, a variable to be changed in the
+	// tests.
+	sharedMemoryDir = "/run/kata-containers/shared-memory"
+
+	sharedMemoryNameRegexp = regexp.MustCompile(`^[a-z0-9]([a-z0-9_.-]{0,62}[a-z0-9])?$`)
+)
+
+func init() {
+	sharedMemoryGateErr = featuregate.Register(featuregate.Gate{
+		Name:        SharedMemoryFeatureGate,
+		Description: "Share memory regions between the VMs of the sandboxes asking for them by name, as ivshmem devices (experimental, QEMU only).",
+	})
+}
+
+// SharedMemoryRegion is a memory region shared with the other sandboxes of
+// the host using the same region name, for the low latency IPC between
+// co-scheduled pods.
+type SharedMemoryRegion struct {
+	// Name identifies the region on the host.
+	Name string
+
+	// Size is the size of the region in bytes, a power of two, which
+	// must be the same for all the sandboxes sharing the region.
+	Size uint64
+}
+
+// SharedMemoryConfig is the configuration of the shared memory regions of a
+// sandbox.
+type SharedMemoryConfig struct {
+	Regions []SharedMemoryRegion
+
+	// ValidRegions are the patterns of the names of the regions the
+	// sandboxes can use, as the names are global on the host.
+	ValidRegions []string
+
+	// MaxRegionSizeMB and MaxSizeMB are the maximum sizes of a region
+	// and of all the regions of a sandbox, backed by the host memory.
+	MaxRegionSizeMB uint32
+	MaxSizeMB       uint32
+}
+
+func (c SharedMemoryConfig) enabled() bool {
+	return len(c.Regions) != 0
+}
+
+// Validate checks the shared memory configuration.
+func (c SharedMemoryConfig) Validate(hypervisorType HypervisorType) error {
+	if !c.enabled() {
+		return nil
+	}
+
+	if sharedMemoryGateErr != nil {
+		return sharedMemoryGateErr
+	}
+	if !featuregate.Enabled(SharedMemoryFeatureGate) {
+		return fmt.Errorf("shared memory regions are disabled, see the %s feature gate", SharedMemoryFeatureGate)
+	}
+
+	// the regions are ivshmem devices
+	if hypervisorType != QemuHypervisor {
+		return fmt.Errorf("shared memory regions are not supported by %s", hypervisorType)
+	}
+
+	if len(c.Regions) > maxSharedMemoryRegions {
+		return fmt.Errorf("too many shared memory regions: %d, the maximum is %d", len(c.Regions), maxSharedMemoryRegions)
+	}
+
+	var total uint64
+	names := make(map[string]bool)
+	for _, r := range c.Regions {
+		if !sharedMemoryNameRegexp.MatchString(r.Name) {
+			return fmt.Errorf("invalid shared memory region name %q", r.Name)
+		}
+		if !c.validRegion(r.Name) {
+			return fmt.Errorf("shared memory region %q is not a valid region", r.Name)
+		}
+		if names[r.Name] {
+			return fmt.Errorf("duplicate shared memory region %q", r.Name)
+		}
+		names[r.Name] = true
+
+		// the region is the BAR of a PCI device
+		if r.Size < uint64(os.Getpagesize()) || r.Size&(r.Size-1) != 0 {
+			return fmt.Errorf("size of shared memory region %q must be a power of two of at least %d bytes, got %d", r.Name, os.Getpagesize(), r.Size)
+		}
+		if r.Size > uint64(c.MaxRegionSizeMB)<<20 {
+			return fmt.Errorf("size of shared memory region %q exceeds the maximum size %d MiB", r.Name, c.MaxRegionSizeMB)
+		}
+		total += r.Size
+	}
+
+	if total > uint64(c.MaxSizeMB)<<20 {
+		return fmt.Errorf("size of the shared memory regions exceeds the maximum size %d MiB", c.MaxSizeMB)
+	}
+
+	return nil
+}
+
+// validRegion returns whether a region name matches the valid regions.
+func (c SharedMemoryConfig) validRegion(name string) bool {
+	for _, pattern := range c.ValidRegions {
+		if matched, _ := filepath.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// lockSharedMemoryDir takes the lock of the shared memory directory, to be
+// released by closing the returned file.
+func lockSharedMemoryDir() (*os.File, error) {
+	if err := os.MkdirAll(sharedMemoryDir, 0700); err != nil {
+		return nil, err
+	}
+
+	lock, err := os.OpenFile(filepath.Join(sharedMemoryDir, sharedMemoryLockFile), os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := unix.Flock(int(lock.Fd()), unix.LOCK_EX); err != nil {
+		lock.Close()
+		return nil, err
+	}
+
+	return lock, nil
+}
+
+// openSharedMemoryRegion opens the file backing a region, creating it for
+// the first user. The file is kept open, with a shared lock, while the
+// region is used, so that its last user can tell it is unused.
+func openSharedMemoryRegion(r SharedMemoryRegion) (*os.File, error) {
+	f, err := os.OpenFile(filepath.Join(sharedMemoryDir, r.Name), os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := unix.Flock(int(f.Fd()), unix.LOCK_SH); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	switch uint64(info.Size()) {
+	case r.Size:
+	case 0:
+		if err := f.Truncate(int64(r.Size)); err != nil {
+			f.Close()
+			return nil, err
+		}
+	default:
+		f.Close()
+		return nil, fmt.Errorf("shared memory region %q is used with a size of %d bytes, not %d", r.Name, info.Size(), r.Size)
+	}
+
+	return f, nil
+}
+
+// setupSharedMemory opens the shared memory regions of the sandbox and adds
+// them to the VM, before it is started.
+func (s *Sandbox) setupSharedMemory(ctx context.Context) (err error) {
+	c := s.config.SharedMemoryConfig
+	if !c.enabled() {
+		return nil
+	}
+
+	// the devices can't be added to the VMs booted in advance
+	if s.factory != nil {
+		return fmt.Errorf("shared memory regions are not supported with the VM factory")
+	}
+
+	lock, err := lockSharedMemoryDir()
+	if err != nil {
+		return err
+	}
+	defer lock.Close()
+
+	s.sharedMemory = make(map[string]*os.File)
+	defer func() {
+		if err != nil {
+			for _, f := range s.sharedMemory {
+				f.Close()
+			}
+			s.sharedMemory = nil
+		}
+	}()
+
+	for _, r := range c.Regions {
+		f, err := openSharedMemoryRegion(r)
+		if err != nil {
+			return err
+		}
+		s.sharedMemory[r.Name] = f
+
+		s.Logger().WithFields(logrus.Fields{
+			"region": r.Name,
+			"size":   r.Size,
+		}).Info("Adding shared memory region")
+
+		region := types.SharedMemory{
+			ID:   r.Name,
+			Path: f.Name(),
+			Size: r.Size,
+		}
+		if err := s.hypervisor.addDevice(ctx, region, sharedMemoryDev); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// releaseSharedMemory releases the shared memory regions of the sandbox,
+// removing the files of the regions no other sandbox uses. The VM must be
+// stopped.
+func (s *Sandbox) releaseSharedMemory() error {
+	c := s.config.SharedMemoryConfig
+	if !c.enabled() {
+		return nil
+	}
+
+	lock, err := lockSharedMemoryDir()
+	if err != nil {
+		return err
+	}
+	defer lock.Close()
+
+	for _, f := range s.sharedMemory {
+		f.Close()
+	}
+	s.sharedMemory = nil
+
+	for _, r := range c.Regions {
+		path := filepath.Join(sharedMemoryDir, r.Name)
+		f, err := os.Open(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		// the other users hold a shared lock
+		if err := unix.Flock(int(f.Fd()), unix.LOCK_EX|unix.LOCK_NB); err == nil {
+			s.Logger().WithField("region", r.Name).Info("Removing unused shared memory region")
+			if err := os.Remove(path); err != nil {
+				f.Close()
+				return err
+			}
+		}
+		f.Close()
+	}
+
+	return nil
+}
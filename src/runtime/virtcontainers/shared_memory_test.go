@@ -0,0 +1,125 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kata-containers/kata-containers/src/runtime/pkg/katautils/featuregate"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSharedMemoryConfigValidate(t *testing.T) {
+	assert := assert.New(t)
+
+	c := SharedMemoryConfig{
+		Regions:         []SharedMemoryRegion{{Name: "accel", Size: 64 << 20}},
+		ValidRegions:    []string{"accel", "ring-*"},
+		MaxRegionSizeMB: 64,
+		MaxSizeMB:       96,
+	}
+
+	assert.NoError(SharedMemoryConfig{}.Validate(FirecrackerHypervisor))
+
+	// disabled by default
+	assert.Error(c.Validate(QemuHypervisor))
+
+	featuregate.Set(map[string]bool{SharedMemoryFeatureGate: true})
+	defer featuregate.Set(nil)
+
+	assert.NoError(c.Validate(QemuHypervisor))
+	assert.Error(c.Validate(ClhHypervisor))
+
+	for _, r := range []SharedMemoryRegion{
+		{Name: "Accel", Size: 64 << 20},
+		{Name: "../accel", Size: 64 << 20},
+		{Name: "accel", Size: 3 << 20},
+		{Name: "accel", Size: 1024},
+		{Name: "accel", Size: 128 << 20},
+		{Name: "other", Size: 1 << 20},
+	} {
+		c := c
+		c.Regions = []SharedMemoryRegion{r}
+		assert.Error(c.Validate(QemuHypervisor), r.Name)
+	}
+
+	c.Regions = append(c.Regions, SharedMemoryRegion{Name: "ring-0", Size: 32 << 20})
+	assert.NoError(c.Validate(QemuHypervisor))
+
+	// the regions of the sandbox are bounded by the maximum size
+	c.Regions = append(c.Regions, SharedMemoryRegion{Name: "ring-1", Size: 1 << 20})
+	assert.Error(c.Validate(QemuHypervisor))
+
+	c.Regions = []SharedMemoryRegion{{Name: "accel", Size: 64 << 20}, {Name: "accel", Size: 1 << 20}}
+	assert.Error(c.Validate(QemuHypervisor))
+
+	// no region is valid unless the host sets them
+	assert.Error(SharedMemoryConfig{Regions: c.Regions[:1]}.Validate(QemuHypervisor))
+}
+
+func TestSandboxSharedMemory(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "shared-memory")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	savedDir := sharedMemoryDir
+	sharedMemoryDir = dir
+	defer func() {
+		sharedMemoryDir = savedDir
+	}()
+
+	newSandbox := func(size uint64) *Sandbox {
+		return &Sandbox{
+			hypervisor: &mockHypervisor{},
+			config: &SandboxConfig{
+				SharedMemoryConfig: SharedMemoryConfig{
+					Regions: []SharedMemoryRegion{{Name: "accel", Size: size}},
+				},
+			},
+		}
+	}
+	path := filepath.Join(dir, "accel")
+
+	s1 := newSandbox(1 << 20)
+	assert.NoError(s1.setupSharedMemory(context.Background()))
+	info, err := os.Stat(path)
+	assert.NoError(err)
+	assert.Equal(int64(1<<20), info.Size())
+
+	// the size must match the one of the first user
+	assert.Error(newSandbox(2 << 20).setupSharedMemory(context.Background()))
+
+	s2 := newSandbox(1 << 20)
+	assert.NoError(s2.setupSharedMemory(context.Background()))
+
+	// still used by s2
+	assert.NoError(s1.releaseSharedMemory())
+	_, err = os.Stat(path)
+	assert.NoError(err)
+
+	assert.NoError(s2.releaseSharedMemory())
+	_, err = os.Stat(path)
+	assert.True(os.IsNotExist(err))
+}
+
+func TestIvshmemDeviceQemuParams(t *testing.T) {
+	assert := assert.New(t)
+
+	dev := ivshmemDevice{ID: "accel", MemPath: "/run/kata-containers/shared-memory/accel", Size: 1 << 20}
+	assert.True(dev.Valid())
+	assert.Equal([]string{
+		"-object", "memory-backend-file,id=shmmem-accel,mem-path=/run/kata-containers/shared-memory/accel,size=1048576,share=on",
+		"-device", "ivshmem-plain,id=shm-accel,memdev=shmmem-accel",
+	}, dev.QemuParams(nil))
+
+	assert.False(ivshmemDevice{ID: "accel"}.Valid())
+}
@@ -10,6 +10,8 @@ const (
 	blockDeviceHotplugSupport
 	multiQueueSupport
 	fsSharingSupported
+	memoryHotUnplugSupport
+	guestNUMASupport
 )
 
 // Capabilities describe a virtcontainers hypervisor capabilities
@@ -57,3 +59,24 @@ func (caps *Capabilities) IsFsSharingSupported() bool {
 func (caps *Capabilities) SetFsSharingSupport() {
 	caps.flags |= fsSharingSupported
 }
+
+// IsMemoryHotUnplugSupported tells if an hypervisor can shrink the VM memory.
+func (caps *Capabilities) IsMemoryHotUnplugSupported() bool {
+	return caps.flags&memoryHotUnplugSupport != 0
+}
+
+// SetMemoryHotUnplugSupport sets the memory hot unplug capability to true.
+func (caps *Capabilities) SetMemoryHotUnplugSupport() {
+	caps.flags |= memoryHotUnplugSupport
+}
+
+// IsGuestNUMASupported tells if an hypervisor supports a guest NUMA
+// topology of several nodes.
+func (caps *Capabilities) IsGuestNUMASupported() bool {
+	return caps.flags&guestNUMASupport != 0
+}
+
+// SetGuestNUMASupport sets the guest NUMA topology capability to true.
+func (caps *Capabilities) SetGuestNUMASupport() {
+	caps.flags |= guestNUMASupport
+}
@@ -43,3 +43,19 @@ func TestMultiQueueCapability(t *testing.T) {
 	caps.SetMultiQueueSupport()
 	assert.True(caps.IsMultiQueueSupported())
 }
+
+func TestMemoryHotUnplugCapability(t *testing.T) {
+	var caps Capabilities
+
+	assert.False(t, caps.IsMemoryHotUnplugSupported())
+	caps.SetMemoryHotUnplugSupport()
+	assert.True(t, caps.IsMemoryHotUnplugSupported())
+}
+
+func TestGuestNUMACapability(t *testing.T) {
+	var caps Capabilities
+
+	assert.False(t, caps.IsGuestNUMASupported())
+	caps.SetGuestNUMASupport()
+	assert.True(t, caps.IsGuestNUMASupported())
+}
@@ -17,6 +17,10 @@ type ContainerState struct {
 	// CgroupPath is the cgroup hierarchy where sandbox's processes
 	// including the hypervisor are placed.
 	CgroupPath string `json:"cgroupPath,omitempty"`
+
+	// CreationDeferred is true while the container is not yet created
+	// in the guest, because its rootfs is attached when it starts.
+	CreationDeferred bool `json:"creationDeferred,omitempty"`
 }
 
 // Valid checks that the container state is valid.
@@ -60,6 +60,10 @@ type SandboxState struct {
 	// with the value as the path.
 	CgroupPaths map[string]string `json:"cgroupPaths"`
 
+	// SandboxDevices are the IDs of the devices attached to the sandbox
+	// itself rather than to one of its containers.
+	SandboxDevices []string `json:"sandboxDevices,omitempty"`
+
 	// PersistVersion indicates current storage api version.
 	// It's also known as ABI version of kata-runtime.
 	// Note: it won't be written to disk
@@ -213,6 +217,21 @@ func (s *MockHybridVSock) String() string {
 	return fmt.Sprintf("%s://%s", MockHybridVSockScheme, s.UdsPath)
 }
 
+// FwCfg defines a firmware configuration blob passed to the guest,
+// read from the /sys/firmware/qemu_fw_cfg directory of the guest.
+type FwCfg struct {
+	Name string
+	File string
+}
+
+// SharedMemory defines a memory region shared with other VMs, backed by
+// a host file.
+type SharedMemory struct {
+	ID   string
+	Path string
+	Size uint64
+}
+
 // Socket defines a socket to communicate between
 // the host and any process inside the VM.
 type Socket struct {
@@ -100,11 +100,19 @@ const (
 	procDeviceIndex = iota
 	procPathIndex
 	procTypeIndex
+	procOptionIndex
 )
 
 // GetDevicePathAndFsType gets the device for the mount point and the file system type
 // of the mount.
 func GetDevicePathAndFsType(mountPoint string) (devicePath, fsType string, err error) {
+	devicePath, fsType, _, err = GetDevicePathAndFsTypeOptions(mountPoint)
+	return
+}
+
+// GetDevicePathAndFsTypeOptions gets the device for the mount point, the file system type
+// and the options of the mount.
+func GetDevicePathAndFsTypeOptions(mountPoint string) (devicePath, fsType string, fsOptions []string, err error) {
 	if mountPoint == "" {
 		err = fmt.Errorf("Mount point cannot be empty")
 		return
@@ -138,6 +146,7 @@ func GetDevicePathAndFsType(mountPoint string) (devicePath, fsType string, err e
 		if mountPoint == fields[procPathIndex] {
 			devicePath = fields[procDeviceIndex]
 			fsType = fields[procTypeIndex]
+			fsOptions = strings.Split(fields[procOptionIndex], ",")
 			return
 		}
 	}
@@ -50,6 +50,17 @@ func TestGetDevicePathAndFsTypeSuccessful(t *testing.T) {
 	assert.Equal(fstype, "proc")
 }
 
+func TestGetDevicePathAndFsTypeOptionsSuccessful(t *testing.T) {
+	assert := assert.New(t)
+
+	path, fstype, options, err := GetDevicePathAndFsTypeOptions("/proc")
+	assert.NoError(err)
+
+	assert.Equal(path, "proc")
+	assert.Equal(fstype, "proc")
+	assert.Contains(options, "rw")
+}
+
 func TestIsAPVFIOMediatedDeviceFalse(t *testing.T) {
 	assert := assert.New(t)
 
@@ -6,10 +6,13 @@
 package virtcontainers
 
 import (
+	"bufio"
 	"context"
 	"encoding/hex"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/device/config"
 	persistapi "github.com/kata-containers/kata-containers/src/runtime/virtcontainers/persist/api"
@@ -25,6 +28,10 @@ const hostSocketSearchPath = "/tmp/vhostuser_%s/vhu.sock"
 
 var vhostuserTrace = getNetworkTrace(VhostUserEndpointType)
 
+// the host memory information with the hugepages available to the
+// vhost-user network interfaces, replaced by the tests
+var vhostUserMemInfo = procMemInfo
+
 // VhostUserEndpoint represents a vhost-user socket based network interface
 type VhostUserEndpoint struct {
 	// Path to the vhost-user socket on the host system
@@ -144,6 +151,70 @@ func findVhostUserNetSocketPath(netInfo NetworkInfo) (string, error) {
 	return "", nil
 }
 
+// getHostFreeHugePagesKb returns the size of the free hugepages of the host.
+func getHostFreeHugePagesKb(memInfoPath string) (uint64, error) {
+	f, err := os.Open(memInfoPath)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	var free, sizeKb uint64
+	var foundFree, foundSize bool
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		// Expected formats: ["HugePages_Free:", "512"] and ["Hugepagesize:", "2048", "kB"]
+		parts := strings.Fields(scanner.Text())
+		if len(parts) < 2 {
+			continue
+		}
+
+		switch {
+		case parts[0] == "HugePages_Free:":
+			if free, err = strconv.ParseUint(parts[1], 0, 64); err == nil {
+				foundFree = true
+			}
+		case parts[0] == "Hugepagesize:" && len(parts) == 3 && parts[2] == "kB":
+			if sizeKb, err = strconv.ParseUint(parts[1], 0, 64); err == nil {
+				foundSize = true
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+
+	if !foundFree || !foundSize {
+		return 0, fmt.Errorf("unable to get the free hugepages from %s", memInfoPath)
+	}
+
+	return free * sizeKb, nil
+}
+
+// checkVhostUserNetHugePages checks the prerequisites of the vhost-user
+// network interfaces: their backends, e.g. OVS-DPDK or VPP, map the guest
+// memory, which must be backed by hugepages and shared, and the host must
+// have enough free hugepages for the VM memory.
+func checkVhostUserNetHugePages(conf HypervisorConfig, memInfoPath string) error {
+	if !conf.HugePages {
+		return fmt.Errorf("vhost-user network interfaces require the VM memory to be backed by hugepages, set enable_hugepages")
+	}
+
+	freeKb, err := getHostFreeHugePagesKb(memInfoPath)
+	if err != nil {
+		return err
+	}
+
+	if requiredKb := uint64(conf.MemorySize) * 1024; freeKb < requiredKb {
+		return fmt.Errorf("not enough free hugepages for the VM memory of the vhost-user network interfaces: %d kB free, %d kB required",
+			freeKb, requiredKb)
+	}
+
+	return nil
+}
+
 // vhostUserSocketPath returns the path of the socket discovered.  This discovery
 // will vary depending on the type of vhost-user socket.
 //  Today only VhostUserNetDevice is supported.
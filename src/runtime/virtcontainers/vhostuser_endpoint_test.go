@@ -8,8 +8,10 @@ package virtcontainers
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
 	"net"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -141,3 +143,51 @@ func TestCreateVhostUserEndpoint(t *testing.T) {
 	assert.NoError(err)
 	assert.Exactly(result, expected)
 }
+
+// mockHugePagesMemInfo writes a meminfo file with the free hugepages,
+// used by the vhost-user network interfaces
+func mockHugePagesMemInfo(dir string, free int) (func(), error) {
+	memInfo := filepath.Join(dir, "meminfo")
+	content := fmt.Sprintf("MemTotal:       16307836 kB\nHugePages_Total:    1024\nHugePages_Free:     %d\nHugepagesize:       2048 kB\n", free)
+	if err := ioutil.WriteFile(memInfo, []byte(content), 0644); err != nil {
+		return nil, err
+	}
+
+	saved := vhostUserMemInfo
+	vhostUserMemInfo = memInfo
+
+	return func() {
+		vhostUserMemInfo = saved
+	}, nil
+}
+
+func TestCheckVhostUserNetHugePages(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "vhost-user")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	// 512 free hugepages of 2MiB
+	restore, err := mockHugePagesMemInfo(dir, 512)
+	assert.NoError(err)
+	defer restore()
+
+	freeKb, err := getHostFreeHugePagesKb(vhostUserMemInfo)
+	assert.NoError(err)
+	assert.Equal(uint64(512*2048), freeKb)
+
+	conf := HypervisorConfig{MemorySize: 1024}
+	assert.Error(checkVhostUserNetHugePages(conf, vhostUserMemInfo))
+
+	conf.HugePages = true
+	assert.NoError(checkVhostUserNetHugePages(conf, vhostUserMemInfo))
+
+	conf.MemorySize = 2048
+	assert.Error(checkVhostUserNetHugePages(conf, vhostUserMemInfo))
+
+	// no hugepages configured on the host
+	assert.NoError(ioutil.WriteFile(vhostUserMemInfo, []byte("MemTotal:       16307836 kB\n"), 0644))
+	_, err = getHostFreeHugePagesKb(vhostUserMemInfo)
+	assert.Error(err)
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/kata-containers/kata-containers/src/runtime/virtcontainers/utils"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
 )
 
 // virtiofsdTracingTags defines tags for the trace span
@@ -63,6 +64,28 @@ type virtiofsd struct {
 	PID int
 	// Neded by tracing
 	ctx context.Context
+	// sandboxID is the ID of the sandbox served by the daemon
+	sandboxID string
+	// ids is the range of the user and group IDs allocated to the daemons,
+	// run as root if empty
+	ids string
+}
+
+// virtiofsdCaps are the capabilities kept by virtiofsd when it is run with
+// its own user and group IDs, to serve the files of any owner and to setup
+// its sandbox.
+var virtiofsdCaps = []uintptr{
+	unix.CAP_CHOWN,
+	unix.CAP_DAC_OVERRIDE,
+	unix.CAP_DAC_READ_SEARCH,
+	unix.CAP_FOWNER,
+	unix.CAP_FSETID,
+	unix.CAP_MKNOD,
+	unix.CAP_SETFCAP,
+	unix.CAP_SETGID,
+	unix.CAP_SETUID,
+	unix.CAP_SYS_ADMIN,
+	unix.CAP_SYS_CHROOT,
 }
 
 // Open socket on behalf of virtiofsd
@@ -107,6 +130,17 @@ func (v *virtiofsd) Start(ctx context.Context, onQuit onQuitFunc) (int, error) {
 
 	cmd.ExtraFiles = append(cmd.ExtraFiles, socketFD)
 
+	if v.ids != "" {
+		if err := v.setCredential(cmd); err != nil {
+			return 0, err
+		}
+		defer func() {
+			if pid == 0 {
+				v.releaseID()
+			}
+		}()
+	}
+
 	// Extra files start from 2 (0: stdin, 1: stdout, 2: stderr)
 	// Extra FDs for virtiofsd start from 3
 	// Get the FD number for previous added socketFD
@@ -127,6 +161,7 @@ func (v *virtiofsd) Start(ctx context.Context, onQuit onQuitFunc) (int, error) {
 	if err = utils.StartCmd(cmd); err != nil {
 		return pid, err
 	}
+	pid = cmd.Process.Pid
 
 	// Monitor virtiofsd's stderr and stop sandbox if virtiofsd quits
 	go func() {
@@ -142,10 +177,48 @@ func (v *virtiofsd) Start(ctx context.Context, onQuit onQuitFunc) (int, error) {
 		}
 	}()
 
-	return cmd.Process.Pid, nil
+	return pid, nil
+}
+
+// setCredential sets the user and group IDs allocated to the daemon of the
+// sandbox, isolating it from the other daemons and the host processes, and
+// makes it the owner of its socket.
+func (v *virtiofsd) setCredential(cmd *exec.Cmd) error {
+	r, err := parseIDRange(v.ids)
+	if err != nil {
+		return err
+	}
+
+	id, err := allocateHostID(virtiofsdIDsDir, r, v.sandboxID)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Chown(v.socketPath, int(id), int(id)); err != nil {
+		v.releaseID()
+		return err
+	}
+
+	v.Logger().WithField("id", id).Info("Running virtiofsd with its own user and group")
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Credential:  &syscall.Credential{Uid: id, Gid: id},
+		AmbientCaps: virtiofsdCaps,
+	}
+
+	return nil
+}
+
+func (v *virtiofsd) releaseID() {
+	if err := releaseHostID(virtiofsdIDsDir, v.sandboxID); err != nil {
+		v.Logger().WithError(err).Warn("releasing virtiofsd ID failed")
+	}
 }
 
 func (v *virtiofsd) Stop(ctx context.Context) error {
+	if v.ids != "" {
+		defer v.releaseID()
+	}
+
 	if err := v.kill(ctx); err != nil {
 		return nil
 	}
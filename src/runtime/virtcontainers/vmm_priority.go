@@ -0,0 +1,251 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unsafe"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
+)
+
+// I/O scheduling classes of the hypervisor processes, see ionice(1)
+const (
+	ioClassRealtime   = "realtime"
+	ioClassBestEffort = "best-effort"
+	ioClassIdle       = "idle"
+)
+
+var ioClasses = map[string]int{
+	ioClassRealtime:   1,
+	ioClassBestEffort: 2,
+	ioClassIdle:       3,
+}
+
+const (
+	ioprioWhoProcess = 1
+	ioprioClassShift = 13
+	maxIOPriority    = 7
+
+	schedFIFO           = 1
+	maxRealtimePriority = 99
+
+	minOOMScoreAdj = -1000
+	maxOOMScoreAdj = 1000
+	minNice        = -20
+	maxNice        = 19
+
+	procDir = "/proc"
+)
+
+// checkVMMPriority checks the priorities of the hypervisor processes.
+func (conf *HypervisorConfig) checkVMMPriority() error {
+	if conf.VMMOOMScoreAdj < minOOMScoreAdj || conf.VMMOOMScoreAdj > maxOOMScoreAdj {
+		return fmt.Errorf("VMM oom_score_adj %d out of [%d, %d]", conf.VMMOOMScoreAdj, minOOMScoreAdj, maxOOMScoreAdj)
+	}
+
+	if conf.VMMNice < minNice || conf.VMMNice > maxNice {
+		return fmt.Errorf("VMM nice value %d out of [%d, %d]", conf.VMMNice, minNice, maxNice)
+	}
+
+	if _, ok := ioClasses[conf.VMMIOClass]; !ok && conf.VMMIOClass != "" {
+		return fmt.Errorf("unknown VMM I/O class %q", conf.VMMIOClass)
+	}
+
+	if conf.VMMIOPriority > maxIOPriority {
+		return fmt.Errorf("VMM I/O priority %d out of [0, %d]", conf.VMMIOPriority, maxIOPriority)
+	}
+
+	if conf.VCPURealtimePriority > maxRealtimePriority {
+		return fmt.Errorf("vCPU realtime priority %d out of [0, %d]", conf.VCPURealtimePriority, maxRealtimePriority)
+	}
+
+	return nil
+}
+
+// ioPriority returns the ioprio_set(2) value of the I/O class and priority.
+func ioPriority(class string, priority uint32) int {
+	return ioClasses[class]<<ioprioClassShift | int(priority)
+}
+
+// processThreads returns the threads of a process.
+func processThreads(pid int) ([]int, error) {
+	entries, err := ioutil.ReadDir(filepath.Join(procDir, strconv.Itoa(pid), "task"))
+	if err != nil {
+		return nil, err
+	}
+
+	var tids []int
+	for _, e := range entries {
+		tid, err := strconv.Atoi(e.Name())
+		if err != nil {
+			continue
+		}
+		tids = append(tids, tid)
+	}
+
+	return tids, nil
+}
+
+// setOOMScoreAdj sets and verifies the oom_score_adj of a process.
+func setOOMScoreAdj(pid, score int) error {
+	path := filepath.Join(procDir, strconv.Itoa(pid), "oom_score_adj")
+	if err := ioutil.WriteFile(path, []byte(strconv.Itoa(score)), 0644); err != nil {
+		return err
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if current := strings.TrimSpace(string(data)); current != strconv.Itoa(score) {
+		return fmt.Errorf("oom_score_adj is %s, not %d", current, score)
+	}
+
+	return nil
+}
+
+// setNice sets and verifies the nice value of a thread.
+func setNice(tid, nice int) error {
+	if err := unix.Setpriority(unix.PRIO_PROCESS, tid, nice); err != nil {
+		return err
+	}
+
+	// the getpriority system call returns 20 - nice
+	prio, err := unix.Getpriority(unix.PRIO_PROCESS, tid)
+	if err != nil {
+		return err
+	}
+	if 20-prio != nice {
+		return fmt.Errorf("nice value is %d, not %d", 20-prio, nice)
+	}
+
+	return nil
+}
+
+// setIOPriority sets and verifies the I/O priority of a thread.
+func setIOPriority(tid, ioprio int) error {
+	if _, _, errno := unix.Syscall(unix.SYS_IOPRIO_SET, ioprioWhoProcess, uintptr(tid), uintptr(ioprio)); errno != 0 {
+		return errno
+	}
+
+	current, _, errno := unix.Syscall(unix.SYS_IOPRIO_GET, ioprioWhoProcess, uintptr(tid), 0)
+	if errno != 0 {
+		return errno
+	}
+	if int(current) != ioprio {
+		return fmt.Errorf("I/O priority is %#x, not %#x", current, ioprio)
+	}
+
+	return nil
+}
+
+// setRealtimePriority sets and verifies the SCHED_FIFO priority of a thread.
+func setRealtimePriority(tid int, priority uint32) error {
+	param := struct{ priority int32 }{int32(priority)}
+	if _, _, errno := unix.Syscall(unix.SYS_SCHED_SETSCHEDULER, uintptr(tid), schedFIFO, uintptr(unsafe.Pointer(&param))); errno != 0 {
+		return errno
+	}
+
+	policy, _, errno := unix.Syscall(unix.SYS_SCHED_GETSCHEDULER, uintptr(tid), 0, 0)
+	if errno != 0 {
+		return errno
+	}
+	if policy != schedFIFO {
+		return fmt.Errorf("scheduling policy is %d, not SCHED_FIFO", policy)
+	}
+
+	return nil
+}
+
+// setProcessPriority applies the priorities of the configuration to a
+// hypervisor process and all its threads, the threads it creates later
+// inherit them.
+func setProcessPriority(pid int, conf *HypervisorConfig) error {
+	if conf.VMMOOMScoreAdj != 0 {
+		if err := setOOMScoreAdj(pid, conf.VMMOOMScoreAdj); err != nil {
+			return fmt.Errorf("Could not set oom_score_adj: %v", err)
+		}
+	}
+
+	if conf.VMMNice == 0 && conf.VMMIOClass == "" {
+		return nil
+	}
+
+	tids, err := processThreads(pid)
+	if err != nil {
+		return err
+	}
+
+	for _, tid := range tids {
+		if conf.VMMNice != 0 {
+			if err := setNice(tid, conf.VMMNice); err != nil {
+				return fmt.Errorf("Could not set the nice value of thread %d: %v", tid, err)
+			}
+		}
+
+		if conf.VMMIOClass != "" {
+			if err := setIOPriority(tid, ioPriority(conf.VMMIOClass, conf.VMMIOPriority)); err != nil {
+				return fmt.Errorf("Could not set the I/O priority of thread %d: %v", tid, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// setVMMPriority applies the configured priorities to the hypervisor
+// processes, e.g. qemu and virtiofsd, and to the vCPU threads.
+func (s *Sandbox) setVMMPriority(ctx context.Context) error {
+	conf := &s.config.HypervisorConfig
+	if conf.VMMOOMScoreAdj != 0 || conf.VMMNice != 0 || conf.VMMIOClass != "" {
+		for _, pid := range s.hypervisor.getPids() {
+			if pid <= 0 {
+				continue
+			}
+
+			if err := setProcessPriority(pid, conf); err != nil {
+				return fmt.Errorf("Could not set the priority of hypervisor process %d: %v", pid, err)
+			}
+		}
+
+		s.Logger().WithFields(logrus.Fields{
+			"oom-score-adj": conf.VMMOOMScoreAdj,
+			"nice":          conf.VMMNice,
+			"io-class":      conf.VMMIOClass,
+			"io-priority":   conf.VMMIOPriority,
+		}).Info("Hypervisor priority set")
+	}
+
+	return s.setVCPUsPriority(ctx)
+}
+
+// setVCPUsPriority makes the vCPU threads realtime, if configured.
+func (s *Sandbox) setVCPUsPriority(ctx context.Context) error {
+	priority := s.config.HypervisorConfig.VCPURealtimePriority
+	if priority == 0 {
+		return nil
+	}
+
+	tids, err := s.hypervisor.getThreadIDs(ctx)
+	if err != nil {
+		return fmt.Errorf("Could not get the vCPU threads: %v", err)
+	}
+
+	for vcpu, tid := range tids.vcpus {
+		if err := setRealtimePriority(tid, priority); err != nil {
+			return fmt.Errorf("Could not set the realtime priority of vCPU %d: %v", vcpu, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2021 Ant Group
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+
+package virtcontainers
+
+import (
+	"io/ioutil"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/sys/unix"
+)
+
+func TestCheckVMMPriority(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, c := range []struct {
+		conf  HypervisorConfig
+		error bool
+	}{
+		{HypervisorConfig{}, false},
+		{HypervisorConfig{VMMOOMScoreAdj: -1000, VMMNice: -20, VMMIOClass: ioClassRealtime, VMMIOPriority: 7, VCPURealtimePriority: 99}, false},
+		{HypervisorConfig{VMMOOMScoreAdj: -1001}, true},
+		{HypervisorConfig{VMMOOMScoreAdj: 1001}, true},
+		{HypervisorConfig{VMMNice: 20}, true},
+		{HypervisorConfig{VMMIOClass: "low"}, true},
+		{HypervisorConfig{VMMIOClass: ioClassIdle, VMMIOPriority: 8}, true},
+		{HypervisorConfig{VCPURealtimePriority: 100}, true},
+	} {
+		err := c.conf.checkVMMPriority()
+		if c.error {
+			assert.Error(err, "%+v", c.conf)
+		} else {
+			assert.NoError(err, "%+v", c.conf)
+		}
+	}
+}
+
+func TestSetProcessPriority(t *testing.T) {
+	assert := assert.New(t)
+
+	cmd := exec.Command("sleep", "60")
+	cmd.Stdin = strings.NewReader("")
+	cmd.Stdout = ioutil.Discard
+	cmd.Stderr = ioutil.Discard
+	if !assert.NoError(cmd.Start()) {
+		return
+	}
+	defer cmd.Process.Kill()
+	pid := cmd.Process.Pid
+
+	// lowering the priorities doesn't require any privilege
+	conf := &HypervisorConfig{
+		VMMOOMScoreAdj: 500,
+		VMMNice:        5,
+		VMMIOClass:     ioClassBestEffort,
+		VMMIOPriority:  7,
+	}
+	assert.NoError(setProcessPriority(pid, conf))
+
+	data, err := ioutil.ReadFile(filepath.Join(procDir, strconv.Itoa(pid), "oom_score_adj"))
+	assert.NoError(err)
+	assert.Equal("500", strings.TrimSpace(string(data)))
+
+	prio, err := unix.Getpriority(unix.PRIO_PROCESS, pid)
+	assert.NoError(err)
+	assert.Equal(5, 20-prio)
+
+	ioprio, _, errno := unix.Syscall(unix.SYS_IOPRIO_GET, ioprioWhoProcess, uintptr(pid), 0)
+	assert.Zero(errno)
+	assert.Equal(ioPriority(ioClassBestEffort, 7), int(ioprio))
+
+	assert.Error(setProcessPriority(-1, conf))
+}
@@ -15,6 +15,7 @@ use protocols::agent_ttrpc::*;
 use protocols::health::*;
 use protocols::health_ttrpc::*;
 use slog::{debug, info};
+use std::fs;
 use std::io;
 use std::io::Write; // XXX: for flush()
 use std::os::unix::io::{IntoRawFd, RawFd};
@@ -132,6 +133,16 @@ static AGENT_CMDS: &'static [AgentCmd] = &[
         st: ServiceType::Agent,
         fp: agent_cmd_sandbox_get_guest_details,
     },
+    AgentCmd {
+        name: "GetGuestStatus",
+        st: ServiceType::Agent,
+        fp: agent_cmd_sandbox_get_guest_status,
+    },
+    AgentCmd {
+        name: "GetMemoryEvent",
+        st: ServiceType::Agent,
+        fp: agent_cmd_sandbox_get_memory_event,
+    },
     AgentCmd {
         name: "GetMetrics",
         st: ServiceType::Agent,
@@ -192,11 +203,26 @@ static AGENT_CMDS: &'static [AgentCmd] = &[
         st: ServiceType::Agent,
         fp: agent_cmd_container_resume,
     },
+    AgentCmd {
+        name: "SetDebugConsole",
+        st: ServiceType::Agent,
+        fp: agent_cmd_sandbox_set_debug_console,
+    },
     AgentCmd {
         name: "SetGuestDateTime",
         st: ServiceType::Agent,
         fp: agent_cmd_sandbox_set_guest_date_time,
     },
+    AgentCmd {
+        name: "SetLogLevel",
+        st: ServiceType::Agent,
+        fp: agent_cmd_sandbox_set_log_level,
+    },
+    AgentCmd {
+        name: "SetNetworkPolicy",
+        st: ServiceType::Agent,
+        fp: agent_cmd_sandbox_set_network_policy,
+    },
     AgentCmd {
         name: "SignalProcess",
         st: ServiceType::Agent,
@@ -1536,6 +1562,52 @@ fn agent_cmd_sandbox_get_oom_event(
     Ok(())
 }
 
+fn agent_cmd_sandbox_get_guest_status(
+    ctx: &Context,
+    client: &AgentServiceClient,
+    _health: &HealthClient,
+    _options: &mut Options,
+    _args: &str,
+) -> Result<()> {
+    let req = GetGuestStatusRequest::default();
+
+    let ctx = clone_context(ctx);
+
+    debug!(sl!(), "sending request"; "request" => format!("{:?}", req));
+
+    let reply = client
+        .get_guest_status(ctx, &req)
+        .map_err(|e| anyhow!("{:?}", e).context(ERR_API_FAILED))?;
+
+    info!(sl!(), "response received";
+        "response" => format!("{:?}", reply));
+
+    Ok(())
+}
+
+fn agent_cmd_sandbox_get_memory_event(
+    ctx: &Context,
+    client: &AgentServiceClient,
+    _health: &HealthClient,
+    _options: &mut Options,
+    _args: &str,
+) -> Result<()> {
+    let req = GetMemoryEventRequest::default();
+
+    let ctx = clone_context(ctx);
+
+    debug!(sl!(), "sending request"; "request" => format!("{:?}", req));
+
+    let reply = client
+        .get_memory_event(ctx, &req)
+        .map_err(|e| anyhow!("{:?}", e).context(ERR_API_FAILED))?;
+
+    info!(sl!(), "response received";
+        "response" => format!("{:?}", reply));
+
+    Ok(())
+}
+
 fn agent_cmd_sandbox_copy_file(
     ctx: &Context,
     client: &AgentServiceClient,
@@ -1752,6 +1824,110 @@ fn agent_cmd_sandbox_set_guest_date_time(
     Ok(())
 }
 
+fn agent_cmd_sandbox_set_network_policy(
+    ctx: &Context,
+    client: &AgentServiceClient,
+    _health: &HealthClient,
+    options: &mut Options,
+    args: &str,
+) -> Result<()> {
+    let mut req = SetNetworkPolicyRequest::default();
+
+    let ctx = clone_context(ctx);
+
+    // the policy is removed when no ruleset file is given
+    let file = utils::get_option("file", options, args);
+    if file != "" {
+        let ruleset = fs::read_to_string(&file)
+            .map_err(|e| anyhow!(e).context("failed to read the ruleset"))?;
+        req.set_ruleset(ruleset);
+    }
+
+    debug!(sl!(), "sending request"; "request" => format!("{:?}", req));
+
+    let reply = client
+        .set_network_policy(ctx, &req)
+        .map_err(|e| anyhow!("{:?}", e).context(ERR_API_FAILED))?;
+
+    info!(sl!(), "response received";
+        "response" => format!("{:?}", reply));
+
+    Ok(())
+}
+
+fn agent_cmd_sandbox_set_log_level(
+    ctx: &Context,
+    client: &AgentServiceClient,
+    _health: &HealthClient,
+    options: &mut Options,
+    args: &str,
+) -> Result<()> {
+    let mut req = SetLogLevelRequest::default();
+
+    let ctx = clone_context(ctx);
+
+    let level = utils::get_option("level", options, args);
+    if level == "" {
+        return Err(anyhow!("need log level"));
+    }
+
+    req.set_level(level);
+
+    debug!(sl!(), "sending request"; "request" => format!("{:?}", req));
+
+    let reply = client
+        .set_log_level(ctx, &req)
+        .map_err(|e| anyhow!("{:?}", e).context(ERR_API_FAILED))?;
+
+    info!(sl!(), "response received";
+        "response" => format!("{:?}", reply));
+
+    Ok(())
+}
+
+fn agent_cmd_sandbox_set_debug_console(
+    ctx: &Context,
+    client: &AgentServiceClient,
+    _health: &HealthClient,
+    options: &mut Options,
+    args: &str,
+) -> Result<()> {
+    let mut req = SetDebugConsoleRequest::default();
+
+    let ctx = clone_context(ctx);
+
+    let enable_str = utils::get_option("enable", options, args);
+
+    if enable_str != "" {
+        let enable = enable_str
+            .parse::<bool>()
+            .map_err(|e| anyhow!(e).context("invalid enable bool"))?;
+
+        req.set_enable(enable);
+    }
+
+    let vport_str = utils::get_option("vport", options, args);
+
+    if vport_str != "" {
+        let vport = vport_str
+            .parse::<u32>()
+            .map_err(|e| anyhow!(e).context("invalid vport value"))?;
+
+        req.set_vport(vport);
+    }
+
+    debug!(sl!(), "sending request"; "request" => format!("{:?}", req));
+
+    let reply = client
+        .set_debug_console(ctx, &req)
+        .map_err(|e| anyhow!("{:?}", e).context(ERR_API_FAILED))?;
+
+    info!(sl!(), "response received";
+        "response" => format!("{:?}", reply));
+
+    Ok(())
+}
+
 fn agent_cmd_sandbox_add_arp_neighbors(
     ctx: &Context,
     client: &AgentServiceClient,